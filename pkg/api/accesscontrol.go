@@ -30,6 +30,7 @@ var (
 	ScopeProvisionersPlugins       = ac.Scope("provisioners", "plugins")
 	ScopeProvisionersDatasources   = ac.Scope("provisioners", "datasources")
 	ScopeProvisionersNotifications = ac.Scope("provisioners", "notifications")
+	ScopeProvisionersPreferences   = ac.Scope("provisioners", "preferences")
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
@@ -215,6 +216,19 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Grants: []string{string(ac.RoleGrafanaAdmin)},
 	}
 
+	orgSwitchTokenCreatorRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:org.users:switch-token-creator",
+			DisplayName: "Organization switch token creator",
+			Description: "Issue tokens that switch the session of a user to an organization without changing their default organization. Needs to be assigned globally.",
+			Group:       "User administration (organizational)",
+			Permissions: []ac.Permission{
+				{Action: ac.ActionOrgUsersSwitchTokensCreate, Scope: ac.ScopeUsersAll},
+			},
+		},
+		Grants: []string{string(ac.RoleGrafanaAdmin)},
+	}
+
 	teamCreatorGrants := []string{string(models.ROLE_ADMIN)}
 	if hs.Cfg.EditorsCanAdmin {
 		teamCreatorGrants = append(teamCreatorGrants, string(models.ROLE_EDITOR))
@@ -391,7 +405,7 @@ func (hs *HTTPServer) declareFixedRoles() error {
 	return hs.AccessControl.DeclareFixedRoles(
 		provisioningWriterRole, datasourcesReaderRole, datasourcesWriterRole,
 		datasourcesIdReaderRole, orgReaderRole, orgWriterRole,
-		orgMaintainerRole, teamsCreatorRole, teamsWriterRole, datasourcesExplorerRole,
+		orgMaintainerRole, orgSwitchTokenCreatorRole, teamsCreatorRole, teamsWriterRole, datasourcesExplorerRole,
 		annotationsReaderRole, dashboardAnnotationsWriterRole, annotationsWriterRole,
 		dashboardsCreatorRole, dashboardsReaderRole, dashboardsWriterRole,
 		foldersCreatorRole, foldersReaderRole, foldersWriterRole, apikeyReaderRole, apikeyWriterRole,
@@ -4,10 +4,12 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/models"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func (hs *HTTPServer) AdminGetSettings(c *models.ReqContext) response.Response {
@@ -28,6 +30,40 @@ func (hs *HTTPServer) AdminGetStats(c *models.ReqContext) response.Response {
 	return response.JSON(http.StatusOK, statsQuery.Result)
 }
 
+// GET /api/admin/auth/events
+func (hs *HTTPServer) AdminGetAuthEvents(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, hs.authEventsService.Events())
+}
+
+// GET /api/admin/read-only
+func (hs *HTTPServer) AdminGetReadOnlyMode(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, hs.readOnlyService.Status())
+}
+
+// PUT /api/admin/read-only
+func (hs *HTTPServer) AdminSetReadOnlyMode(c *models.ReqContext) response.Response {
+	cmd := dtos.SetReadOnlyModeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if err := hs.readOnlyService.SetEnabled(c.Req.Context(), cmd.Enabled, cmd.Reason); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to change read-only mode", err)
+	}
+	hs.log.Info("Read-only mode changed by admin", "enabled", cmd.Enabled, "userId", c.UserId, "reason", cmd.Reason)
+	return response.JSON(http.StatusOK, hs.readOnlyService.Status())
+}
+
+// GET /api/admin/migrations/status
+func (hs *HTTPServer) AdminGetMigrationStatus(c *models.ReqContext) response.Response {
+	statuses, err := hs.SQLStore.GetMigrationStatus(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get migration status from database", err)
+	}
+
+	return response.JSON(http.StatusOK, statuses)
+}
+
 func (hs *HTTPServer) getAuthorizedSettings(ctx context.Context, user *models.SignedInUser, bag setting.SettingsBag) (setting.SettingsBag, error) {
 	if hs.AccessControl.IsDisabled() {
 		return bag, nil
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth/authzsnapshot"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// GET /api/admin/users/:id/authz-snapshot
+func (hs *HTTPServer) AdminGetUserAuthzSnapshot(c *models.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	snapshot, err := hs.authzSnapshotService.GetSnapshot(c.Req.Context(), userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return response.Error(http.StatusNotFound, models.ErrUserNotFound.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get authorization snapshot", err)
+	}
+
+	etag := `"` + snapshot.Hash + `"`
+	if c.Req.Header.Get("If-None-Match") == etag {
+		return response.Respond(http.StatusNotModified, []byte{}).SetHeader("ETag", etag)
+	}
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to encode authorization snapshot", err)
+	}
+	return response.Respond(http.StatusOK, body).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Cache-Control", "private, no-cache").
+		SetHeader("ETag", etag).
+		SetHeader(authzsnapshot.SignatureHeader, hs.authzSnapshotService.Sign(body))
+}
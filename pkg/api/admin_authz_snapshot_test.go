@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth/authzsnapshot"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestAdminGetUserAuthzSnapshot(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.AuthzSnapshot.Enabled = true
+	cfg.AuthzSnapshot.SigningKey = "secret"
+	sc := setupHTTPServerWithCfg(t, true, false, cfg)
+	db := sc.db.(*sqlstore.SQLStore)
+	sc.hs.authzSnapshotService = authzsnapshot.ProvideService(cfg, db, sc.acmock, kvstore.ProvideService(db))
+
+	alice, err := db.CreateUser(context.Background(), user.CreateUserCommand{Login: "alice"})
+	require.NoError(t, err)
+	url := fmt.Sprintf("/api/admin/users/%d/authz-snapshot", alice.ID)
+
+	t.Run("should be forbidden for users that are not server admins", func(t *testing.T) {
+		setInitCtxSignedInUser(sc.initCtx, models.SignedInUser{UserId: 1, OrgId: 1, OrgRole: models.ROLE_ADMIN})
+		response := callAPI(sc.server, http.MethodGet, url, nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInUser(sc.initCtx, models.SignedInUser{UserId: 1, OrgId: 1, IsGrafanaAdmin: true})
+
+	t.Run("should return a signed snapshot", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodGet, url, nil, t)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		require.Equal(t, sc.hs.authzSnapshotService.Sign(response.Body.Bytes()), response.Header().Get(authzsnapshot.SignatureHeader))
+
+		var snapshot authzsnapshot.Snapshot
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &snapshot))
+		require.Equal(t, alice.ID, snapshot.UserID)
+		require.Equal(t, int64(1), snapshot.Version)
+		require.Len(t, snapshot.Orgs, 1)
+		require.Equal(t, `"`+snapshot.Hash+`"`, response.Header().Get("ETag"))
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("If-None-Match", response.Header().Get("ETag"))
+		recorder := httptest.NewRecorder()
+		sc.server.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusNotModified, recorder.Code)
+		require.Empty(t, recorder.Body.String())
+	})
+
+	t.Run("should return 404 for unknown users", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodGet, "/api/admin/users/1000/authz-snapshot", nil, t)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	defaultLogLevelTTL = 10 * time.Minute
+	maxLogLevelTTL     = 24 * time.Hour
+)
+
+// GET /api/admin/logging/levels
+func (hs *HTTPServer) AdminGetLogLevels(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, log.LevelOverrides())
+}
+
+// PUT /api/admin/logging/levels/:logger
+func (hs *HTTPServer) AdminSetLogLevel(c *models.ReqContext) response.Response {
+	cmd := dtos.SetLogLevelCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	ttl := defaultLogLevelTTL
+	if cmd.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(cmd.TTL); err != nil {
+			return response.Error(http.StatusBadRequest, fmt.Sprintf("invalid ttl '%s'", cmd.TTL), err)
+		}
+	}
+	if ttl > maxLogLevelTTL {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("the ttl must not be longer than %s", maxLogLevelTTL), nil)
+	}
+
+	logger := web.Params(c.Req)[":logger"]
+	override, err := log.SetLevel(logger, cmd.Level, ttl)
+	if err != nil {
+		if errors.Is(err, log.ErrUnknownLogLevel) || errors.Is(err, log.ErrInvalidLevelTTL) {
+			return response.Error(http.StatusBadRequest, err.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to set log level", err)
+	}
+
+	c.Logger.Info("Log level changed by admin", "logger", logger, "level", override.Level, "expires", override.Expires, "userId", c.UserId)
+	return response.JSON(http.StatusOK, override)
+}
+
+// DELETE /api/admin/logging/levels/:logger
+func (hs *HTTPServer) AdminResetLogLevel(c *models.ReqContext) response.Response {
+	logger := web.Params(c.Req)[":logger"]
+	if err := log.ResetLevel(logger); err != nil {
+		if errors.Is(err, log.ErrLevelOverrideMissing) {
+			return response.Error(http.StatusNotFound, err.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to reset log level", err)
+	}
+
+	c.Logger.Info("Log level reset by admin", "logger", logger, "userId", c.UserId)
+	return response.Success("Log level reset")
+}
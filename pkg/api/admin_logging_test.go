@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestAdminLogLevels(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+	t.Cleanup(func() {
+		for _, override := range log.LevelOverrides() {
+			_ = log.ResetLevel(override.Logger)
+		}
+	})
+
+	t.Run("should be forbidden for users that are not server admins", func(t *testing.T) {
+		setInitCtxSignedInUser(sc.initCtx, models.SignedInUser{UserId: 1, OrgId: 1, OrgRole: models.ROLE_ADMIN})
+		response := callAPI(sc.server, http.MethodPut, "/api/admin/logging/levels/ngalert", strings.NewReader(`{"level": "debug"}`), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInUser(sc.initCtx, models.SignedInUser{UserId: 1, OrgId: 1, IsGrafanaAdmin: true})
+
+	t.Run("should set, list and reset log levels", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPut, "/api/admin/logging/levels/ngalert.scheduler", strings.NewReader(`{"level": "debug", "ttl": "5m"}`), t)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var override log.LevelOverride
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&override))
+		require.Equal(t, "ngalert.scheduler", override.Logger)
+		require.Equal(t, "debug", override.Level)
+
+		response = callAPI(sc.server, http.MethodGet, "/api/admin/logging/levels", nil, t)
+		require.Equal(t, http.StatusOK, response.Code)
+		var overrides []log.LevelOverride
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&overrides))
+		require.Len(t, overrides, 1)
+		require.Equal(t, "ngalert.scheduler", overrides[0].Logger)
+
+		response = callAPI(sc.server, http.MethodDelete, "/api/admin/logging/levels/ngalert.scheduler", nil, t)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Empty(t, log.LevelOverrides())
+
+		response = callAPI(sc.server, http.MethodDelete, "/api/admin/logging/levels/ngalert.scheduler", nil, t)
+		require.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("should reject invalid levels and ttls", func(t *testing.T) {
+		for _, body := range []string{`{"level": "verbose"}`, `{"level": "debug", "ttl": "soon"}`, `{"level": "debug", "ttl": "-1m"}`, `{"level": "debug", "ttl": "48h"}`} {
+			response := callAPI(sc.server, http.MethodPut, "/api/admin/logging/levels/ngalert", strings.NewReader(body), t)
+			assert.Equal(t, http.StatusBadRequest, response.Code, body)
+		}
+		require.Empty(t, log.LevelOverrides())
+	})
+}
@@ -39,3 +39,11 @@ func (hs *HTTPServer) AdminProvisioningReloadNotifications(c *models.ReqContext)
 	}
 	return response.Success("Notifications config reloaded")
 }
+
+func (hs *HTTPServer) AdminProvisioningReloadPreferences(c *models.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionPreferences(c.Req.Context())
+	if err != nil {
+		return response.Error(500, "Failed to reload preferences config", err)
+	}
+	return response.Success("Preferences config reloaded")
+}
@@ -93,6 +93,27 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 			url:          "/api/admin/provisioning/notifications/reload",
 			exit:         true,
 		},
+		{
+			desc:         "should work for preferences with specific scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"message":"Preferences config reloaded"}`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersPreferences,
+				},
+			},
+			url: "/api/admin/provisioning/preferences/reload",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ProvisionPreferences, 1)
+			},
+		},
+		{
+			desc:         "should fail for preferences with no permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/preferences/reload",
+			exit:         true,
+		},
 		{
 			desc:         "should work for datasources with specific scope",
 			expectedCode: http.StatusOK,
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth/revocation"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// POST /api/admin/auth/revoke-tokens
+func (hs *HTTPServer) AdminRevokeTokens(c *models.ReqContext) response.Response {
+	form := dtos.AdminRevokeTokensForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	result, err := hs.tokenRevocationService.Revoke(c.Req.Context(), form.Criteria, form.DryRun)
+	if err != nil {
+		if errors.Is(err, revocation.ErrNoCriteria) || errors.Is(err, revocation.ErrInvalidIPRange) {
+			return response.Error(http.StatusBadRequest, err.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to revoke tokens", err)
+	}
+
+	if !form.DryRun {
+		c.Logger.Info("Tokens revoked by admin", "userId", c.UserId, "criteria", fmt.Sprintf("%+v", form.Criteria),
+			"serviceAccountTokens", result.ServiceAccountTokens, "userSessions", result.UserSessions)
+	}
+	return response.JSON(http.StatusOK, result)
+}
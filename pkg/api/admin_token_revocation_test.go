@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/revocation"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const adminRevokeTokensURL = "/api/admin/auth/revoke-tokens"
+
+func TestAdminRevokeTokens(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	db := sc.db.(*sqlstore.SQLStore)
+	sc.hs.tokenRevocationService = revocation.ProvideService(db)
+
+	sa, err := db.CreateUser(context.Background(), user.CreateUserCommand{Login: "sa-ci", Name: "ci", IsServiceAccount: true})
+	require.NoError(t, err)
+	err = db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		now := time.Now()
+		_, err := sess.Insert(&models.ApiKey{OrgId: 1, Name: "ci", Key: "secret", Role: models.ROLE_VIEWER, Created: now, Updated: now, ServiceAccountId: &sa.ID})
+		return err
+	})
+	require.NoError(t, err)
+
+	setInitCtxSignedInUser(sc.initCtx, models.SignedInUser{UserId: 1, OrgId: 1, Login: testUserLogin})
+	permissions := []ac.Permission{
+		{Action: ac.ActionUsersAuthTokenUpdate, Scope: ac.ScopeGlobalUsersAll},
+		{Action: serviceaccounts.ActionWrite, Scope: serviceaccounts.ScopeAll},
+	}
+
+	callRevokeTokens := func(t *testing.T, body string) revocation.Result {
+		t.Helper()
+		response := callAPI(sc.server, http.MethodPost, adminRevokeTokensURL, strings.NewReader(body), t)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var result revocation.Result
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&result))
+		return result
+	}
+
+	t.Run("should be forbidden without the required permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, permissions[:1], 1)
+		response := callAPI(sc.server, http.MethodPost, adminRevokeTokensURL, strings.NewReader(`{"neverUsed": true}`), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setAccessControlPermissions(sc.acmock, permissions, 1)
+
+	t.Run("should refuse to revoke without criteria", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, adminRevokeTokensURL, strings.NewReader(`{"dryRun": true}`), t)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("should reject invalid IP ranges", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, adminRevokeTokensURL, strings.NewReader(`{"ipRange": "10.0.0"}`), t)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("should only count the tokens in a dry run", func(t *testing.T) {
+		result := callRevokeTokens(t, `{"serviceAccountNamePrefix": "ci", "dryRun": true}`)
+		require.Equal(t, revocation.Result{DryRun: true, ServiceAccountTokens: 1}, result)
+
+		result = callRevokeTokens(t, `{"serviceAccountNamePrefix": "ci", "dryRun": true}`)
+		require.Equal(t, int64(1), result.ServiceAccountTokens)
+	})
+
+	t.Run("should revoke the tokens", func(t *testing.T) {
+		result := callRevokeTokens(t, `{"serviceAccountNamePrefix": "ci", "neverUsed": true}`)
+		require.Equal(t, revocation.Result{ServiceAccountTokens: 1}, result)
+
+		result = callRevokeTokens(t, `{"serviceAccountNamePrefix": "ci", "dryRun": true}`)
+		require.Equal(t, int64(0), result.ServiceAccountTokens)
+	})
+}
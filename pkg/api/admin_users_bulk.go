@@ -0,0 +1,323 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// POST /api/admin/users/bulk
+func (hs *HTTPServer) AdminBulkUpsertUsers(c *models.ReqContext) response.Response {
+	form := dtos.AdminBulkUsersForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if form.DeactivateMissing && len(form.Users) == 0 {
+		return response.Error(400, "Refusing to deactivate all users, the list of users is empty", nil)
+	}
+
+	inFeed := make(map[string]struct{}, 2*len(form.Users))
+	for i := range form.Users {
+		u := &form.Users[i]
+		if u.Login == "" {
+			u.Login = u.Email
+		}
+		if err := validateBulkUser(*u); err != nil {
+			return response.Error(400, fmt.Sprintf("Validation error for user #%d: %s", i, err.Error()), nil)
+		}
+		inFeed[u.Login] = struct{}{}
+		if u.Email != "" {
+			inFeed[u.Email] = struct{}{}
+		}
+	}
+
+	result := dtos.AdminBulkUsersResult{Users: make([]dtos.AdminBulkUserResult, 0, len(form.Users))}
+	for _, u := range form.Users {
+		var status string
+		var userID int64
+		// every user is synchronized in its own transaction, a failing user does not affect the others
+		err := hs.SQLStore.InTransaction(c.Req.Context(), func(ctx context.Context) error {
+			var err error
+			status, userID, err = hs.bulkUpsertUser(ctx, c.SignedInUser, u, form.Upsert)
+			return err
+		})
+		if err != nil {
+			c.Logger.Warn("failed to synchronize user", "login", u.Login, "error", err)
+			addBulkUserResult(&result, dtos.AdminBulkUserResult{Login: u.Login, Id: userID, Status: dtos.BulkUserFailed, Error: err.Error()})
+			continue
+		}
+		if status == dtos.BulkUserCreated {
+			metrics.MApiAdminUserCreate.Inc()
+		}
+		addBulkUserResult(&result, dtos.AdminBulkUserResult{Login: u.Login, Id: userID, Status: status})
+	}
+
+	if form.DeactivateMissing {
+		if err := hs.deactivateMissingUsers(c, inFeed, &result); err != nil {
+			return response.Error(500, "Failed to deactivate missing users", err)
+		}
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+func validateBulkUser(u dtos.AdminBulkUser) error {
+	if u.Login == "" {
+		return errors.New("need to specify either login or email")
+	}
+	if u.Password != "" && len(u.Password) < 4 {
+		return errors.New("password is too short")
+	}
+	for _, r := range u.OrgRoles {
+		if !r.Role.IsValid() {
+			return fmt.Errorf("invalid role '%s' for organization %d", r.Role, r.OrgId)
+		}
+	}
+	for _, t := range u.Teams {
+		if t.Name == "" {
+			return fmt.Errorf("team name is missing for organization %d", t.OrgId)
+		}
+	}
+	return nil
+}
+
+// bulkUpsertUser creates or updates a single user of a bulk request together with its organization roles
+// and team memberships. ctx is expected to carry the transaction of the user.
+func (hs *HTTPServer) bulkUpsertUser(ctx context.Context, signedInUser *models.SignedInUser, u dtos.AdminBulkUser, upsert bool) (string, int64, error) {
+	query := models.GetUserByLoginQuery{LoginOrEmail: u.Login}
+	err := hs.SQLStore.GetUserByLogin(ctx, &query)
+	if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+		return "", 0, err
+	}
+
+	status := dtos.BulkUserUnchanged
+	var usr *user.User
+	if errors.Is(err, models.ErrUserNotFound) {
+		password := u.Password
+		if password == "" {
+			if password, err = util.GetRandomString(32); err != nil {
+				return "", 0, err
+			}
+		}
+		usr, err = hs.SQLStore.CreateUser(ctx, user.CreateUserCommand{
+			Login:    u.Login,
+			Email:    u.Email,
+			Name:     u.Name,
+			Password: password,
+		})
+		if err != nil {
+			if errors.Is(err, models.ErrUserAlreadyExists) {
+				return "", 0, fmt.Errorf("user with email '%s' or username '%s' already exists", u.Email, u.Login)
+			}
+			return "", 0, err
+		}
+		status = dtos.BulkUserCreated
+	} else {
+		usr = query.Result
+		if !upsert {
+			return dtos.BulkUserSkipped, usr.ID, nil
+		}
+
+		cmd := models.UpdateUserCommand{UserId: usr.ID, Login: usr.Login, Email: usr.Email, Name: usr.Name}
+		if u.Email != "" {
+			cmd.Email = u.Email
+		}
+		if u.Name != "" {
+			cmd.Name = u.Name
+		}
+		if cmd.Email != usr.Email || cmd.Name != usr.Name {
+			if err := hs.SQLStore.UpdateUser(ctx, &cmd); err != nil {
+				return "", usr.ID, err
+			}
+			status = dtos.BulkUserUpdated
+		}
+
+		// users that are part of the feed again are enabled
+		if usr.IsDisabled {
+			if err := hs.SQLStore.DisableUser(ctx, &models.DisableUserCommand{UserId: usr.ID, IsDisabled: false}); err != nil {
+				return "", usr.ID, err
+			}
+			status = dtos.BulkUserUpdated
+		}
+	}
+
+	changed, err := hs.syncBulkUserOrgRoles(ctx, usr.ID, u.OrgRoles)
+	if err != nil {
+		return "", usr.ID, err
+	}
+	if changed && status == dtos.BulkUserUnchanged {
+		status = dtos.BulkUserUpdated
+	}
+
+	changed, err = hs.syncBulkUserTeams(ctx, signedInUser, usr.ID, u.Teams)
+	if err != nil {
+		return "", usr.ID, err
+	}
+	if changed && status == dtos.BulkUserUnchanged {
+		status = dtos.BulkUserUpdated
+	}
+
+	return status, usr.ID, nil
+}
+
+// syncBulkUserOrgRoles adds the user to the given organizations, or updates its role in them.
+// Memberships in organizations that are not listed are kept.
+func (hs *HTTPServer) syncBulkUserOrgRoles(ctx context.Context, userID int64, roles []dtos.AdminBulkUserOrgRole) (bool, error) {
+	if len(roles) == 0 {
+		return false, nil
+	}
+
+	query := models.GetUserOrgListQuery{UserId: userID}
+	if err := hs.SQLStore.GetUserOrgList(ctx, &query); err != nil {
+		return false, err
+	}
+	current := make(map[int64]models.RoleType, len(query.Result))
+	for _, org := range query.Result {
+		current[org.OrgId] = org.Role
+	}
+
+	changed := false
+	for _, r := range roles {
+		role, ok := current[r.OrgId]
+		if !ok {
+			cmd := models.AddOrgUserCommand{OrgId: r.OrgId, UserId: userID, Role: r.Role}
+			if err := hs.SQLStore.AddOrgUser(ctx, &cmd); err != nil {
+				return false, fmt.Errorf("failed to add user to organization %d: %w", r.OrgId, err)
+			}
+			changed = true
+			continue
+		}
+		if role == r.Role {
+			continue
+		}
+		cmd := models.UpdateOrgUserCommand{OrgId: r.OrgId, UserId: userID, Role: r.Role}
+		if err := hs.SQLStore.UpdateOrgUser(ctx, &cmd); err != nil {
+			return false, fmt.Errorf("failed to update role in organization %d: %w", r.OrgId, err)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// syncBulkUserTeams adds the user as member to the given teams. Existing memberships, including the ones
+// in teams that are not listed, are kept as they are.
+func (hs *HTTPServer) syncBulkUserTeams(ctx context.Context, signedInUser *models.SignedInUser, userID int64, teams []dtos.AdminBulkUserTeam) (bool, error) {
+	changed := false
+	memberships := map[int64][]*models.TeamMemberDTO{}
+	for _, t := range teams {
+		query := models.SearchTeamsQuery{
+			OrgId:        t.OrgId,
+			Name:         t.Name,
+			UserIdFilter: models.FilterIgnoreUser,
+			SignedInUser: signedInUser,
+			HiddenUsers:  map[string]struct{}{},
+		}
+		if err := hs.SQLStore.SearchTeams(ctx, &query); err != nil {
+			return false, err
+		}
+		if len(query.Result.Teams) == 0 {
+			return false, fmt.Errorf("team '%s' not found in organization %d", t.Name, t.OrgId)
+		}
+		teamID := query.Result.Teams[0].Id
+
+		if _, ok := memberships[t.OrgId]; !ok {
+			m, err := hs.SQLStore.GetUserTeamMemberships(ctx, t.OrgId, userID, false)
+			if err != nil {
+				return false, err
+			}
+			memberships[t.OrgId] = m
+		}
+		isMember := false
+		for _, m := range memberships[t.OrgId] {
+			if m.TeamId == teamID {
+				isMember = true
+				break
+			}
+		}
+		if isMember {
+			continue
+		}
+
+		// users are added as regular members, which is permission 0
+		if err := addOrUpdateTeamMember(ctx, hs.teamPermissionsService, userID, t.OrgId, teamID, getPermissionName(0)); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// bulkUsersSearchPageSize is the number of users searched at once for the users to deactivate.
+var bulkUsersSearchPageSize = 1000
+
+// deactivateMissingUsers disables every enabled user whose login and email are not part of inFeed.
+// Users managed by an external authentication provider, server admins and the signed in user are left untouched.
+func (hs *HTTPServer) deactivateMissingUsers(c *models.ReqContext, inFeed map[string]struct{}, result *dtos.AdminBulkUsersResult) error {
+	// the missing users are collected before any is disabled, which would shift the pages of enabled users
+	var missing []*models.UserSearchHitDTO
+	enabled := false
+	for page := 1; ; page++ {
+		query := models.SearchUsersQuery{SignedInUser: c.SignedInUser, IsDisabled: &enabled, Page: page, Limit: bulkUsersSearchPageSize}
+		if err := hs.SQLStore.SearchUsers(c.Req.Context(), &query); err != nil {
+			return err
+		}
+
+		for _, u := range query.Result.Users {
+			if u.Id == c.UserId || u.IsAdmin || (len(u.AuthModule) > 0 && u.AuthModule[0] != "") {
+				continue
+			}
+			if _, ok := inFeed[u.Login]; ok {
+				continue
+			}
+			if _, ok := inFeed[u.Email]; ok && u.Email != "" {
+				continue
+			}
+			missing = append(missing, u)
+		}
+
+		if len(query.Result.Users) < bulkUsersSearchPageSize {
+			break
+		}
+	}
+
+	for _, u := range missing {
+		if err := hs.SQLStore.DisableUser(c.Req.Context(), &models.DisableUserCommand{UserId: u.Id, IsDisabled: true}); err != nil {
+			addBulkUserResult(result, dtos.AdminBulkUserResult{Login: u.Login, Id: u.Id, Status: dtos.BulkUserFailed, Error: err.Error()})
+			continue
+		}
+		if err := hs.AuthTokenService.RevokeAllUserTokens(c.Req.Context(), u.Id); err != nil {
+			addBulkUserResult(result, dtos.AdminBulkUserResult{Login: u.Login, Id: u.Id, Status: dtos.BulkUserFailed, Error: err.Error()})
+			continue
+		}
+		addBulkUserResult(result, dtos.AdminBulkUserResult{Login: u.Login, Id: u.Id, Status: dtos.BulkUserDeactivated})
+	}
+	return nil
+}
+
+func addBulkUserResult(result *dtos.AdminBulkUsersResult, r dtos.AdminBulkUserResult) {
+	switch r.Status {
+	case dtos.BulkUserCreated:
+		result.Created++
+	case dtos.BulkUserUpdated:
+		result.Updated++
+	case dtos.BulkUserUnchanged:
+		result.Unchanged++
+	case dtos.BulkUserSkipped:
+		result.Skipped++
+	case dtos.BulkUserFailed:
+		result.Failed++
+	case dtos.BulkUserDeactivated:
+		result.Deactivated++
+	}
+	result.Users = append(result.Users, r)
+}
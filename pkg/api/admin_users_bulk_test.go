@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const adminBulkUsersURL = "/api/admin/users/bulk"
+
+func TestAdminBulkUpsertUsers(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	sc.hs.AuthTokenService = auth.NewFakeUserAuthTokenService()
+
+	caller, err := sc.db.CreateUser(context.Background(), user.CreateUserCommand{SkipOrgSetup: true, Login: testUserLogin})
+	require.NoError(t, err)
+	org, err := sc.db.CreateOrgWithMember("TestOrg", caller.ID)
+	require.NoError(t, err)
+	team, err := sc.db.CreateTeam("backend", "backend@example.com", org.Id)
+	require.NoError(t, err)
+
+	alice, err := sc.db.CreateUser(context.Background(), user.CreateUserCommand{Login: "alice", Email: "alice@example.com", Name: "Alice", OrgID: org.Id})
+	require.NoError(t, err)
+	bob, err := sc.db.CreateUser(context.Background(), user.CreateUserCommand{Login: "bob", Email: "bob@example.com", OrgID: org.Id})
+	require.NoError(t, err)
+	admin, err := sc.db.CreateUser(context.Background(), user.CreateUserCommand{Login: "carol", Email: "carol@example.com", OrgID: org.Id, IsAdmin: true})
+	require.NoError(t, err)
+
+	setInitCtxSignedInUser(sc.initCtx, models.SignedInUser{UserId: caller.ID, OrgId: org.Id, Login: testUserLogin})
+	permissions := []ac.Permission{
+		{Action: ac.ActionUsersCreate},
+		{Action: ac.ActionUsersRead, Scope: ac.ScopeGlobalUsersAll},
+		{Action: ac.ActionUsersWrite, Scope: ac.ScopeGlobalUsersAll},
+		{Action: ac.ActionUsersDisable, Scope: ac.ScopeGlobalUsersAll},
+		{Action: ac.ActionUsersEnable, Scope: ac.ScopeGlobalUsersAll},
+		{Action: ac.ActionTeamsRead, Scope: ac.ScopeTeamsAll},
+	}
+
+	t.Run("should be forbidden without the required permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []ac.Permission{{Action: ac.ActionUsersCreate}}, org.Id)
+		response := callAPI(sc.server, http.MethodPost, adminBulkUsersURL, strings.NewReader(`{"users": []}`), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setAccessControlPermissions(sc.acmock, permissions, org.Id)
+
+	t.Run("should refuse to deactivate all users", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, adminBulkUsersURL, strings.NewReader(`{"users": [], "deactivateMissing": true}`), t)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("should reject invalid roles", func(t *testing.T) {
+		body := `{"users": [{"login": "dave", "orgRoles": [{"orgId": 1, "role": "Owner"}]}]}`
+		response := callAPI(sc.server, http.MethodPost, adminBulkUsersURL, strings.NewReader(body), t)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("should skip existing users without upsert", func(t *testing.T) {
+		form := dtos.AdminBulkUsersForm{Users: []dtos.AdminBulkUser{{Login: "alice", Name: "Alice Doe"}}}
+		result := callBulkUsers(t, sc, form)
+
+		require.Equal(t, 1, result.Skipped)
+		require.Equal(t, dtos.AdminBulkUserResult{Login: "alice", Id: alice.ID, Status: dtos.BulkUserSkipped}, result.Users[0])
+		query := models.GetUserByIdQuery{Id: alice.ID}
+		require.NoError(t, sc.db.GetUserById(context.Background(), &query))
+		require.Equal(t, "Alice", query.Result.Name)
+	})
+
+	t.Run("should upsert users and deactivate missing ones", func(t *testing.T) {
+		// the users to deactivate are searched one page at a time
+		pageSize := bulkUsersSearchPageSize
+		bulkUsersSearchPageSize = 1
+		t.Cleanup(func() { bulkUsersSearchPageSize = pageSize })
+
+		form := dtos.AdminBulkUsersForm{
+			Upsert:            true,
+			DeactivateMissing: true,
+			Users: []dtos.AdminBulkUser{
+				{
+					Login:    "alice",
+					Name:     "Alice Doe",
+					OrgRoles: []dtos.AdminBulkUserOrgRole{{OrgId: org.Id, Role: models.ROLE_EDITOR}},
+				},
+				{
+					Email:    "dave@example.com",
+					OrgRoles: []dtos.AdminBulkUserOrgRole{{OrgId: org.Id, Role: models.ROLE_VIEWER}},
+					Teams:    []dtos.AdminBulkUserTeam{{OrgId: org.Id, Name: "backend"}},
+				},
+				{
+					Login: "erin",
+					Teams: []dtos.AdminBulkUserTeam{{OrgId: org.Id, Name: "unknown"}},
+				},
+			},
+		}
+		result := callBulkUsers(t, sc, form)
+
+		require.Equal(t, 1, result.Created)
+		require.Equal(t, 1, result.Updated)
+		require.Equal(t, 1, result.Failed)
+		require.Equal(t, 1, result.Deactivated)
+		require.Len(t, result.Users, 4)
+		require.Equal(t, dtos.BulkUserUpdated, result.Users[0].Status)
+		require.Equal(t, "dave@example.com", result.Users[1].Login)
+		require.Equal(t, dtos.BulkUserCreated, result.Users[1].Status)
+		require.Equal(t, dtos.BulkUserFailed, result.Users[2].Status)
+		require.Contains(t, result.Users[2].Error, "team 'unknown' not found")
+		require.Equal(t, dtos.AdminBulkUserResult{Login: "bob", Id: bob.ID, Status: dtos.BulkUserDeactivated}, result.Users[3])
+
+		orgs := models.GetUserOrgListQuery{UserId: alice.ID}
+		require.NoError(t, sc.db.GetUserOrgList(context.Background(), &orgs))
+		roles := map[int64]models.RoleType{}
+		for _, o := range orgs.Result {
+			roles[o.OrgId] = o.Role
+		}
+		require.Equal(t, models.ROLE_EDITOR, roles[org.Id])
+
+		memberships, err := sc.db.GetUserTeamMemberships(context.Background(), org.Id, result.Users[1].Id, false)
+		require.NoError(t, err)
+		require.Len(t, memberships, 1)
+		require.Equal(t, team.Id, memberships[0].TeamId)
+
+		// the failed user must have been rolled back
+		err = sc.db.GetUserByLogin(context.Background(), &models.GetUserByLoginQuery{LoginOrEmail: "erin"})
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+
+		query := models.GetUserByIdQuery{Id: bob.ID}
+		require.NoError(t, sc.db.GetUserById(context.Background(), &query))
+		require.True(t, query.Result.IsDisabled)
+		query = models.GetUserByIdQuery{Id: caller.ID}
+		require.NoError(t, sc.db.GetUserById(context.Background(), &query))
+		require.False(t, query.Result.IsDisabled)
+		query = models.GetUserByIdQuery{Id: admin.ID}
+		require.NoError(t, sc.db.GetUserById(context.Background(), &query))
+		require.False(t, query.Result.IsDisabled)
+	})
+
+	t.Run("should enable deactivated users that are part of the list again", func(t *testing.T) {
+		form := dtos.AdminBulkUsersForm{Upsert: true, Users: []dtos.AdminBulkUser{{Login: "bob"}}}
+		result := callBulkUsers(t, sc, form)
+
+		require.Equal(t, dtos.AdminBulkUserResult{Login: "bob", Id: bob.ID, Status: dtos.BulkUserUpdated}, result.Users[0])
+		query := models.GetUserByIdQuery{Id: bob.ID}
+		require.NoError(t, sc.db.GetUserById(context.Background(), &query))
+		require.False(t, query.Result.IsDisabled)
+	})
+}
+
+func callBulkUsers(t *testing.T, sc accessControlScenarioContext, form dtos.AdminBulkUsersForm) dtos.AdminBulkUsersResult {
+	t.Helper()
+	body, err := json.Marshal(form)
+	require.NoError(t, err)
+	response := callAPI(sc.server, http.MethodPost, adminBulkUsersURL, strings.NewReader(string(body)), t)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	var result dtos.AdminBulkUsersResult
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	return result
+}
@@ -445,21 +445,21 @@ func (hs *HTTPServer) postDashboard(c *models.ReqContext, cmd models.SaveDashboa
 
 // GetHomeDashboard returns the home dashboard.
 func (hs *HTTPServer) GetHomeDashboard(c *models.ReqContext) response.Response {
-	prefsQuery := pref.GetPreferenceWithDefaultsQuery{OrgID: c.OrgId, UserID: c.SignedInUser.UserId}
+	homeQuery := pref.ResolveHomeDashboardQuery{OrgID: c.OrgId, UserID: c.SignedInUser.UserId, Teams: c.SignedInUser.Teams}
 	homePage := hs.Cfg.HomePage
 
-	preference, err := hs.preferenceService.GetWithDefaults(c.Req.Context(), &prefsQuery)
+	home, err := hs.preferenceService.ResolveHomeDashboard(c.Req.Context(), &homeQuery)
 	if err != nil {
 		return response.Error(500, "Failed to get preferences", err)
 	}
 
-	if preference.HomeDashboardID == 0 && len(homePage) > 0 {
+	if home.DashboardID == 0 && len(homePage) > 0 {
 		homePageRedirect := dtos.DashboardRedirect{RedirectUri: homePage}
 		return response.JSON(http.StatusOK, &homePageRedirect)
 	}
 
-	if preference.HomeDashboardID != 0 {
-		slugQuery := models.GetDashboardRefByIdQuery{Id: preference.HomeDashboardID}
+	if home.DashboardID != 0 {
+		slugQuery := models.GetDashboardRefByIdQuery{Id: home.DashboardID}
 		err := hs.DashboardService.GetDashboardUIDById(c.Req.Context(), &slugQuery)
 		if err == nil {
 			url := models.GetDashboardUrl(slugQuery.Result.Uid, slugQuery.Result.Slug)
@@ -791,6 +791,16 @@ func (hs *HTTPServer) GetDashboardTags(c *models.ReqContext) {
 	c.JSON(http.StatusOK, query.Result)
 }
 
+// GetDashboardContentViolations lists the dangerous content, such as scripts in text panels, found in the
+// dashboards the user can edit.
+func (hs *HTTPServer) GetDashboardContentViolations(c *models.ReqContext) response.Response {
+	result, err := hs.DashboardService.GetContentViolations(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to look for dangerous content in dashboards", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
 // GetDashboardUIDs converts internal ids to UIDs
 func (hs *HTTPServer) GetDashboardUIDs(c *models.ReqContext) {
 	ids := strings.Split(web.Params(c.Req)[":ids"], ",")
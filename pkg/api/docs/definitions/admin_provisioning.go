@@ -48,6 +48,22 @@ package definitions
 // 403: forbiddenError
 // 500: internalServerError
 
+// swagger:route POST /admin/provisioning/preferences/reload admin_provisioning reloadProvisionedPreferences
+//
+// Reload organization preferences provisioning configurations.
+//
+// Reloads the provisioning config files for organization preferences again. It won’t return until the preferences are stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:preferences`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+
 // swagger:route POST /admin/provisioning/notifications/reload admin_provisioning reloadProvisionedAlertNotifiers
 //
 // Reload legacy alert notifier provisioning configurations.
@@ -24,6 +24,27 @@ import (
 // 412: preconditionFailedError
 // 500: internalServerError
 
+// swagger:route POST /admin/users/bulk admin_users bulkUpsertUsers
+//
+// Create, update and deactivate users in bulk.
+//
+// Synchronizes a list of users, including their organization roles and team memberships, with Grafana.
+// Every user is processed in its own transaction, the response reports the outcome for every user.
+// Existing users are only updated if `upsert` is set. If `deactivateMissing` is set, all users that are not part of the list are disabled,
+// except for users authenticated by an external provider, server admins and the calling user.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have the permissions with actions `users:create`,
+// and `users:write`, `users:disable` and `users:enable` with scope `global.users:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: bulkUpsertUsersResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+
 // swagger:route PUT /admin/users/{user_id}/password admin_users setPassword
 //
 // Set password for user.
@@ -261,6 +282,13 @@ type CreateUserParam struct {
 	Body dtos.AdminCreateUserForm `json:"body"`
 }
 
+// swagger:parameters bulkUpsertUsers
+type BulkUpsertUsersParams struct {
+	// in:body
+	// required:true
+	Body dtos.AdminBulkUsersForm `json:"body"`
+}
+
 // swagger:parameters updateUserQuota
 type UpdateUserQuotaParam struct {
 	// in:body
@@ -284,6 +312,12 @@ type CreateUserResponse struct {
 	Body models.UserIdDTO `json:"body"`
 }
 
+// swagger:response bulkUpsertUsersResponse
+type BulkUpsertUsersResponse struct {
+	// in:body
+	Body dtos.AdminBulkUsersResult `json:"body"`
+}
+
 // swagger:response getSettingsResponse
 type GetSettingsResponse struct {
 	// in:body
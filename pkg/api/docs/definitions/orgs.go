@@ -157,6 +157,24 @@ import (
 // 403: forbiddenError
 // 500: internalServerError
 
+// swagger:route POST /orgs/{org_id}/users/{user_id}/switch-tokens orgs adminCreateOrgSwitchToken
+//
+// Create an org switch token.
+//
+// Creates a short-lived token which switches the session of the user to the organization when it is exchanged at `/org/switch`.
+// The default organization of the user is not changed. The token expires after five minutes and can be used once.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled
+// you need to have a permission with action: `org.users.switchtokens:create` with scope `users:*`.
+//
+// Responses:
+// 200: createOrgSwitchTokenResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+
 // swagger:route GET /orgs/{org_id}/quotas orgs getOrgQuota
 //
 // Fetch Organization quota.
@@ -216,6 +234,16 @@ type AdminUpdateOrgUserParams struct {
 	UserID int64 `json:"user_id"`
 }
 
+// swagger:parameters adminCreateOrgSwitchToken
+type AdminCreateOrgSwitchTokenParams struct {
+	// in:path
+	// required:true
+	OrgID int64 `json:"org_id"`
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+}
+
 // swagger:parameters getOrgByID
 type GetOrgByIDParams struct {
 	// in:path
@@ -335,3 +363,9 @@ type SearchOrgResponse struct {
 	// in: body
 	Body []*models.OrgDTO `json:"body"`
 }
+
+// swagger:response createOrgSwitchTokenResponse
+type CreateOrgSwitchTokenResponse struct {
+	// in: body
+	Body dtos.OrgSwitchToken `json:"body"`
+}
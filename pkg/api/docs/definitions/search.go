@@ -70,6 +70,10 @@ type SearchParams struct {
 	// default: alpha-asc
 	// Enum: alpha-asc,alpha-desc
 	Sort string `json:"sort"`
+	// Comma-separated list of fields to include in each returned hit, e.g. uid,title. All fields are returned if empty.
+	// in:query
+	// required: false
+	Fields []string `json:"fields"`
 }
 
 // swagger:response searchResponse
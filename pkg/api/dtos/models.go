@@ -50,6 +50,17 @@ type CurrentUser struct {
 
 type UserPermissionsMap map[string]bool
 
+type SetReadOnlyModeCommand struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason"`
+}
+
+type SetLogLevelCommand struct {
+	Level string `json:"level"`
+	// TTL is how long the level applies, for example 30m.
+	TTL string `json:"ttl"`
+}
+
 // swagger:model
 type MetricRequest struct {
 	// From Start time in epoch timestamps in milliseconds or relative using Grafana time units.
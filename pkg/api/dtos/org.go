@@ -1,5 +1,7 @@
 package dtos
 
+import "time"
+
 type UpdateOrgForm struct {
 	Name string `json:"name" binding:"Required"`
 }
@@ -12,3 +14,9 @@ type UpdateOrgAddressForm struct {
 	State    string `json:"state"`
 	Country  string `json:"country"`
 }
+
+type OrgSwitchToken struct {
+	Token     string    `json:"token"`
+	Url       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
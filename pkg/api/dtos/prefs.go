@@ -46,3 +46,9 @@ type PatchPrefsCmd struct {
 	QueryHistory     *pref.QueryHistoryPreference `json:"queryHistory,omitempty"`
 	HomeDashboardUID *string                      `json:"homeDashboardUID,omitempty"`
 }
+
+// swagger:model
+type HomeDashboardTeamPriority struct {
+	// The teams whose home dashboards are used first for their members, in order
+	TeamIDs []int64 `json:"teamIds"`
+}
@@ -1,5 +1,10 @@
 package dtos
 
+import (
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth/revocation"
+)
+
 type SignUpForm struct {
 	Email string `json:"email" binding:"Required"`
 }
@@ -21,6 +26,72 @@ type AdminCreateUserForm struct {
 	OrgId    int64  `json:"orgId"`
 }
 
+// AdminBulkUsersForm is the request body of POST /api/admin/users/bulk.
+type AdminBulkUsersForm struct {
+	Users []AdminBulkUser `json:"users"`
+	// Upsert updates users that already exist, otherwise they are skipped.
+	Upsert bool `json:"upsert"`
+	// DeactivateMissing disables all users that are not part of Users.
+	// Users authenticated by an external provider, server admins and the calling user are never disabled.
+	DeactivateMissing bool `json:"deactivateMissing"`
+}
+
+type AdminBulkUser struct {
+	// Login identifies the user. If empty, the email is used as login.
+	Login string `json:"login"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	// Password is only used when the user is created. A random password is generated if empty.
+	Password string                 `json:"password"`
+	OrgRoles []AdminBulkUserOrgRole `json:"orgRoles"`
+	Teams    []AdminBulkUserTeam    `json:"teams"`
+}
+
+type AdminBulkUserOrgRole struct {
+	OrgId int64           `json:"orgId"`
+	Role  models.RoleType `json:"role"`
+}
+
+type AdminBulkUserTeam struct {
+	OrgId int64  `json:"orgId"`
+	Name  string `json:"name"`
+}
+
+const (
+	BulkUserCreated     = "created"
+	BulkUserUpdated     = "updated"
+	BulkUserUnchanged   = "unchanged"
+	BulkUserSkipped     = "skipped"
+	BulkUserFailed      = "failed"
+	BulkUserDeactivated = "deactivated"
+)
+
+// AdminBulkUsersResult reports what happened to every user of an AdminBulkUsersForm
+// as well as to every user disabled because of DeactivateMissing.
+type AdminBulkUsersResult struct {
+	Created     int                   `json:"created"`
+	Updated     int                   `json:"updated"`
+	Unchanged   int                   `json:"unchanged"`
+	Skipped     int                   `json:"skipped"`
+	Failed      int                   `json:"failed"`
+	Deactivated int                   `json:"deactivated"`
+	Users       []AdminBulkUserResult `json:"users"`
+}
+
+type AdminBulkUserResult struct {
+	Login  string `json:"login"`
+	Id     int64  `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AdminRevokeTokensForm is the request body of POST /api/admin/auth/revoke-tokens.
+type AdminRevokeTokensForm struct {
+	revocation.Criteria
+	// DryRun only counts the tokens and sessions that match the criteria.
+	DryRun bool `json:"dryRun"`
+}
+
 type AdminUpdateUserPasswordForm struct {
 	Password string `json:"password" binding:"Required"`
 }
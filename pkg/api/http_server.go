@@ -37,6 +37,9 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/plugincontext"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/auth/authevents"
+	"github.com/grafana/grafana/pkg/services/auth/authzsnapshot"
+	"github.com/grafana/grafana/pkg/services/auth/revocation"
 	"github.com/grafana/grafana/pkg/services/cleanup"
 	"github.com/grafana/grafana/pkg/services/comments"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
@@ -67,6 +70,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/readonly"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/searchusers"
@@ -171,6 +175,10 @@ type HTTPServer struct {
 	CoremodelStaticRegistry      *registry.Static
 	kvStore                      kvstore.KVStore
 	secretsMigrator              secrets.Migrator
+	authEventsService            *authevents.Service
+	readOnlyService              *readonly.Service
+	tokenRevocationService       *revocation.Service
+	authzSnapshotService         *authzsnapshot.Service
 }
 
 type ServerOptions struct {
@@ -206,6 +214,8 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 	dashboardPermissionsService accesscontrol.DashboardPermissionsService, dashboardVersionService dashver.Service,
 	starService star.Service, csrfService csrf.Service, coremodelRegistry *registry.Generic, coremodelStaticRegistry *registry.Static,
 	kvStore kvstore.KVStore, secretsMigrator secrets.Migrator, remoteSecretsCheck secretsKV.UseRemoteSecretsPluginCheck, publicDashboardsApi *publicdashboardsApi.Api,
+	authEventsService *authevents.Service, readOnlyService *readonly.Service, tokenRevocationService *revocation.Service,
+	authzSnapshotService *authzsnapshot.Service,
 ) (*HTTPServer, error) {
 	web.Env = cfg.Env
 	m := web.New()
@@ -292,6 +302,10 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 		kvStore:                      kvStore,
 		PublicDashboardsApi:          publicDashboardsApi,
 		secretsMigrator:              secretsMigrator,
+		authEventsService:            authEventsService,
+		readOnlyService:              readOnlyService,
+		tokenRevocationService:       tokenRevocationService,
+		authzSnapshotService:         authzSnapshotService,
 	}
 	if hs.Listener != nil {
 		hs.log.Debug("Using provided listener")
@@ -555,6 +569,7 @@ func (hs *HTTPServer) addMiddlewaresAndStaticRoutes() {
 
 	m.Use(middleware.HandleNoCacheHeader)
 	m.UseMiddleware(middleware.AddCSPHeader(hs.Cfg, hs.log))
+	m.Use(middleware.ReadOnly(hs.Cfg, hs.readOnlyService))
 
 	for _, mw := range hs.middlewares {
 		m.Use(mw)
@@ -298,6 +298,7 @@ func (hs *HTTPServer) Logout(c *models.ReqContext) {
 	}
 
 	cookies.WriteSessionCookie(c, hs.Cfg, "", -1)
+	hs.clearOrgContext(c)
 
 	if setting.SignoutRedirectUrl != "" {
 		c.Redirect(setting.SignoutRedirectUrl)
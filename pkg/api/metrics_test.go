@@ -75,6 +75,7 @@ func TestAPIEndpoint_Metrics_QueryMetricsV2(t *testing.T) {
 				return &backend.QueryDataResponse{Responses: resp}, nil
 			},
 		},
+		nil,
 		&fakeOAuthTokenService{},
 	)
 	serverFeatureEnabled := SetupAPITestServer(t, func(hs *HTTPServer) {
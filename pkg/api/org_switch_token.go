@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	orgSwitchTokenPrefix = "org-switch-token-%s"
+	orgSwitchTokenTTL    = 5 * time.Minute
+)
+
+// OrgSwitchTokenClaims is the content of an org switch token as it is kept in the remote cache.
+type OrgSwitchTokenClaims struct {
+	OrgID    int64
+	UserID   int64
+	IssuerID int64
+}
+
+func init() {
+	remotecache.Register(&OrgSwitchTokenClaims{})
+}
+
+// POST /api/orgs/:orgId/users/:userId/switch-tokens
+func (hs *HTTPServer) CreateOrgSwitchToken(c *models.ReqContext) response.Response {
+	orgID, err := strconv.ParseInt(web.Params(c.Req)[":orgId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "orgId is invalid", err)
+	}
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+
+	if !hs.validateUsingOrg(c.Req.Context(), userID, orgID) {
+		return response.Error(http.StatusNotFound, "User is not a member of the organization", nil)
+	}
+
+	token, err := util.GetRandomString(32)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to generate token", err)
+	}
+	claims := &OrgSwitchTokenClaims{OrgID: orgID, UserID: userID, IssuerID: c.UserId}
+	if err := hs.RemoteCacheService.Set(c.Req.Context(), orgSwitchTokenKey(token), claims, orgSwitchTokenTTL); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to store token", err)
+	}
+
+	c.Logger.Info("Org switch token issued", "issuerId", c.UserId, "issuerLogin", c.Login, "userId", userID, "orgId", orgID)
+
+	return response.JSON(http.StatusOK, dtos.OrgSwitchToken{
+		Token:     token,
+		Url:       fmt.Sprintf("%sorg/switch?token=%s", hs.Cfg.AppURL, url.QueryEscape(token)),
+		ExpiresAt: time.Now().Add(orgSwitchTokenTTL),
+	})
+}
+
+// GET /org/switch
+// ExchangeOrgSwitchToken switches the session of the signed in user to the organization of an org switch token
+// and redirects to `redirectTo`, or to the home dashboard of that organization.
+func (hs *HTTPServer) ExchangeOrgSwitchToken(c *models.ReqContext) {
+	claims, err := hs.consumeOrgSwitchToken(c.Req.Context(), c.Query("token"))
+	if err != nil {
+		c.Logger.Warn("Org switch token rejected", "userId", c.UserId, "error", err)
+		c.Handle(hs.Cfg, http.StatusUnauthorized, "Invalid or expired org switch token", nil)
+		return
+	}
+
+	if claims.UserID != c.UserId {
+		c.Logger.Warn("Org switch token used by another user", "userId", c.UserId, "tokenUserId", claims.UserID, "orgId", claims.OrgID)
+		c.Handle(hs.Cfg, http.StatusForbidden, "The org switch token was issued for another user", nil)
+		return
+	}
+
+	if !hs.validateUsingOrg(c.Req.Context(), c.UserId, claims.OrgID) {
+		c.Handle(hs.Cfg, http.StatusForbidden, "Not a member of the organization", nil)
+		return
+	}
+
+	// the organization context lasts as long as the login session can, a negative max age would delete the cookie
+	maxAge := int(hs.Cfg.LoginMaxLifetime.Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	cookies.WriteCookie(c.Resp, cookies.OrgContextCookieName, strconv.FormatInt(claims.OrgID, 10), maxAge, hs.CookieOptionsFromCfg)
+	c.Logger.Info("Org switch token exchanged", "userId", c.UserId, "orgId", claims.OrgID, "issuerId", claims.IssuerID)
+
+	redirectTo := c.Query("redirectTo")
+	if redirectTo == "" || hs.ValidateRedirectTo(redirectTo) != nil {
+		redirectTo = fmt.Sprintf("%s/?orgId=%d", hs.Cfg.AppSubURL, claims.OrgID)
+	}
+	c.Redirect(redirectTo)
+}
+
+// consumeOrgSwitchToken returns the claims of an org switch token and removes it, so it cannot be used again.
+func (hs *HTTPServer) consumeOrgSwitchToken(ctx context.Context, token string) (*OrgSwitchTokenClaims, error) {
+	if token == "" {
+		return nil, errors.New("token is missing")
+	}
+
+	// of concurrent exchanges of the same token, only one gets the claims
+	val, err := hs.RemoteCacheService.GetAndDelete(ctx, orgSwitchTokenKey(token))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := val.(*OrgSwitchTokenClaims)
+	if !ok {
+		return nil, errors.New("unexpected value in cache")
+	}
+	return claims, nil
+}
+
+// clearOrgContext removes the organization a session was switched to with an org switch token,
+// so the default organization of the user applies again.
+func (hs *HTTPServer) clearOrgContext(c *models.ReqContext) {
+	if c.GetCookie(cookies.OrgContextCookieName) != "" {
+		cookies.DeleteCookie(c.Resp, cookies.OrgContextCookieName, hs.CookieOptionsFromCfg)
+	}
+}
+
+// the tokens are only kept hashed, so they cannot be read from the cache
+func orgSwitchTokenKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf(orgSwitchTokenPrefix, hex.EncodeToString(hash[:]))
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestOrgSwitchTokens(t *testing.T) {
+	cache := remotecache.NewFakeStore(t)
+	sc := setupHTTPServer(t, true, true)
+	sc.hs.RemoteCacheService = cache
+	sc.hs.Cfg.LoginMaxLifetime = 30 * 24 * time.Hour
+
+	issuer, err := sc.db.CreateUser(context.Background(), user.CreateUserCommand{Login: testUserLogin, IsAdmin: true})
+	require.NoError(t, err)
+	member, err := sc.db.CreateUser(context.Background(), user.CreateUserCommand{Login: "member"})
+	require.NoError(t, err)
+	org, err := sc.db.CreateOrgWithMember("Embedded", issuer.ID)
+	require.NoError(t, err)
+	err = sc.db.AddOrgUser(context.Background(), &models.AddOrgUserCommand{OrgId: org.Id, UserId: member.ID, Role: models.ROLE_VIEWER})
+	require.NoError(t, err)
+	outsider, err := sc.db.CreateUser(context.Background(), user.CreateUserCommand{Login: "outsider"})
+	require.NoError(t, err)
+
+	tokenURL := func(userID int64) string {
+		return fmt.Sprintf("/api/orgs/%d/users/%d/switch-tokens", org.Id, userID)
+	}
+
+	setInitCtxSignedInUser(sc.initCtx, models.SignedInUser{UserId: issuer.ID, OrgId: issuer.OrgID, Login: testUserLogin})
+
+	t.Run("should be forbidden without the required permission", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []ac.Permission{{Action: ac.ActionOrgUsersRead, Scope: ac.ScopeUsersAll}}, org.Id)
+		response := callAPI(sc.server, http.MethodPost, tokenURL(member.ID), nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setAccessControlPermissions(sc.acmock, []ac.Permission{{Action: ac.ActionOrgUsersSwitchTokensCreate, Scope: ac.ScopeUsersAll}}, org.Id)
+
+	t.Run("should not issue tokens for users outside of the organization", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, tokenURL(outsider.ID), nil, t)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("should switch the session of the user once", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, tokenURL(member.ID), nil, t)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var token dtos.OrgSwitchToken
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &token))
+		require.NotEmpty(t, token.Token)
+		require.Contains(t, token.Url, "org/switch?token=")
+
+		setInitCtxSignedInUser(sc.initCtx, models.SignedInUser{UserId: member.ID, OrgId: member.OrgID, Login: "member"})
+		response = callAPI(sc.server, http.MethodGet, "/org/switch?redirectTo=%2Fd%2Fabc&token="+token.Token, nil, t)
+		require.Equal(t, http.StatusFound, response.Code)
+		assert.Equal(t, "/d/abc", response.Header().Get("Location"))
+		cookie := findCookie(response, cookies.OrgContextCookieName)
+		require.NotNil(t, cookie)
+		assert.Equal(t, fmt.Sprint(org.Id), cookie.Value)
+		// the cookie is kept by the browser for the lifetime of the login session
+		assert.Equal(t, int((30 * 24 * time.Hour).Seconds()), cookie.MaxAge)
+
+		// the default organization is kept
+		query := models.GetUserByIdQuery{Id: member.ID}
+		require.NoError(t, sc.db.GetUserById(context.Background(), &query))
+		assert.Equal(t, member.OrgID, query.Result.OrgID)
+
+		_, err := sc.hs.consumeOrgSwitchToken(context.Background(), token.Token)
+		require.Error(t, err)
+	})
+
+	t.Run("should let only one of concurrent exchanges use a token", func(t *testing.T) {
+		setInitCtxSignedInUser(sc.initCtx, models.SignedInUser{UserId: issuer.ID, OrgId: issuer.OrgID, Login: testUserLogin})
+		response := callAPI(sc.server, http.MethodPost, tokenURL(member.ID), nil, t)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var token dtos.OrgSwitchToken
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &token))
+
+		const exchanges = 50
+		var wg sync.WaitGroup
+		var consumed int32
+		wg.Add(exchanges)
+		for i := 0; i < exchanges; i++ {
+			go func() {
+				defer wg.Done()
+				if claims, err := sc.hs.consumeOrgSwitchToken(context.Background(), token.Token); err == nil {
+					assert.Equal(t, org.Id, claims.OrgID)
+					atomic.AddInt32(&consumed, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), consumed)
+	})
+}
+
+func findCookie(response *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range response.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
@@ -325,10 +325,11 @@ func TestGetOrgUsersAPIEndpoint_AccessControlMetadata(t *testing.T) {
 			enableAccessControl: true,
 			expectedCode:        http.StatusOK,
 			expectedMetadata: map[string]bool{
-				"org.users:write":  true,
-				"org.users:add":    true,
-				"org.users:read":   true,
-				"org.users:remove": true},
+				"org.users:write":               true,
+				"org.users:add":                 true,
+				"org.users:read":                true,
+				"org.users:remove":              true,
+				"org.users.switchtokens:create": true},
 			user:      testServerAdminViewer,
 			targetOrg: testServerAdminViewer.OrgId,
 		},
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -202,3 +203,29 @@ func (hs *HTTPServer) PatchOrgPreferences(c *models.ReqContext) response.Respons
 	}
 	return hs.patchPreferencesFor(c.Req.Context(), c.OrgId, 0, 0, &dtoCmd)
 }
+
+// GET /api/org/preferences/home-dashboard/teams
+func (hs *HTTPServer) GetOrgHomeDashboardTeamPriority(c *models.ReqContext) response.Response {
+	teamIDs, err := hs.preferenceService.GetHomeDashboardTeamPriority(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get home dashboard team priority", err)
+	}
+	return response.JSON(http.StatusOK, dtos.HomeDashboardTeamPriority{TeamIDs: teamIDs})
+}
+
+// PUT /api/org/preferences/home-dashboard/teams
+func (hs *HTTPServer) UpdateOrgHomeDashboardTeamPriority(c *models.ReqContext) response.Response {
+	dto := dtos.HomeDashboardTeamPriority{}
+	if err := web.Bind(c.Req, &dto); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	cmd := pref.SetHomeDashboardTeamPriorityCommand{OrgID: c.OrgId, TeamIDs: dto.TeamIDs}
+	if err := hs.preferenceService.SetHomeDashboardTeamPriority(c.Req.Context(), &cmd); err != nil {
+		if errors.Is(err, pref.ErrInvalidHomeDashboardTeamPriority) {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to update home dashboard team priority", err)
+	}
+	return response.Success("Home dashboard team priority updated")
+}
@@ -23,6 +23,7 @@ var (
 	putOrgPreferencesURL    = "/api/org/preferences/"
 	patchOrgPreferencesUrl  = "/api/org/preferences/"
 	patchUserPreferencesUrl = "/api/user/preferences/"
+	homeDashboardTeamsURL   = "/api/org/preferences/home-dashboard/teams"
 
 	testUpdateOrgPreferencesCmd                     = `{ "theme": "light", "homeDashboardId": 1 }`
 	testPatchOrgPreferencesCmd                      = `{"navbar":{"savedItems":[{"id":"snapshots","text":"Snapshots","icon":"camera","url":"/dashboard/snapshots"}]}}`
@@ -195,3 +196,37 @@ func TestAPIEndpoint_PatchOrgPreferences(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, response.Code)
 	})
 }
+
+func TestAPIEndpoint_HomeDashboardTeamPriority(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+	prefService := preftest.NewPreferenceServiceFake()
+	prefService.ExpectedTeamPriority = []int64{2, 1}
+	sc.hs.preferenceService = prefService
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("Viewer cannot update the team priority", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPut, homeDashboardTeamsURL, strings.NewReader(`{"teamIds":[1]}`), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInOrgAdmin(sc.initCtx)
+	t.Run("Org Admin can get the team priority", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodGet, homeDashboardTeamsURL, nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"teamIds":[2,1]}`, response.Body.String())
+	})
+
+	t.Run("Org Admin can update the team priority", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPut, homeDashboardTeamsURL, strings.NewReader(`{"teamIds":[1,2]}`), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Invalid team priority returns 400", func(t *testing.T) {
+		prefService.ExpectedError = pref.ErrInvalidHomeDashboardTeamPriority
+		response := callAPI(sc.server, http.MethodPut, homeDashboardTeamsURL, strings.NewReader(`{"teamIds":[1,1]}`), t)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
@@ -0,0 +1,84 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// FieldsQueryParam is the name of the query parameter used to request a sparse fieldset.
+const FieldsQueryParam = "fields"
+
+// Fields returns the sparse fieldset requested through the `fields` query parameter.
+// Both comma separated values (?fields=uid,name) and repeated parameters
+// (?fields=uid&fields=name) are supported. An empty result means no filtering was requested.
+func Fields(c *models.ReqContext) []string {
+	var fields []string
+	for _, value := range c.QueryStrings(FieldsQueryParam) {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// JSONFields creates a JSON response where the returned objects are trimmed to the given fields.
+// If body serializes to a list, every element of that list is trimmed. If listKey is not empty and
+// body serializes to an object, the list stored under listKey is trimmed instead and the remaining
+// properties of the object (e.g. paging information) are kept as they are.
+// When fields is empty, this behaves like JSON.
+func JSONFields(status int, body interface{}, fields []string, listKey string) *NormalResponse {
+	if len(fields) == 0 {
+		return JSON(status, body)
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return Error(500, "body json marshal", err)
+	}
+
+	var generic interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	// keep numbers as they are, IDs must not lose precision by becoming float64
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return Error(500, "body json unmarshal", err)
+	}
+
+	allowed := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		allowed[f] = struct{}{}
+	}
+
+	if obj, ok := generic.(map[string]interface{}); ok && listKey != "" {
+		obj[listKey] = trimFields(obj[listKey], allowed)
+		return JSON(status, obj)
+	}
+	return JSON(status, trimFields(generic, allowed))
+}
+
+// trimFields removes all properties not in allowed from an object, or from every object of a list.
+// Values of any other type are returned unmodified.
+func trimFields(v interface{}, allowed map[string]struct{}) interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		for i := range t {
+			t[i] = trimFields(t[i], allowed)
+		}
+		return t
+	case map[string]interface{}:
+		for k := range t {
+			if _, ok := allowed[k]; !ok {
+				delete(t, k)
+			}
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,51 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestFields(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/search?fields=uid,title&fields=%20url%20&fields=", nil)
+	c := &models.ReqContext{Context: &web.Context{Req: req}}
+
+	require.Equal(t, []string{"uid", "title", "url"}, Fields(c))
+}
+
+func TestJSONFields(t *testing.T) {
+	type item struct {
+		ID    int64  `json:"id"`
+		UID   string `json:"uid"`
+		Title string `json:"title"`
+	}
+	items := []item{
+		{ID: 9007199254740993, UID: "a", Title: "A"},
+		{ID: 2, UID: "b", Title: "B"},
+	}
+
+	t.Run("returns the body unmodified when no fields are requested", func(t *testing.T) {
+		resp := JSONFields(http.StatusOK, items, nil, "")
+		require.JSONEq(t, `[{"id":9007199254740993,"uid":"a","title":"A"},{"id":2,"uid":"b","title":"B"}]`, string(resp.Body()))
+	})
+
+	t.Run("trims every element of a list", func(t *testing.T) {
+		resp := JSONFields(http.StatusOK, items, []string{"id", "uid", "unknown"}, "")
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, `[{"id":9007199254740993,"uid":"a"},{"id":2,"uid":"b"}]`, string(resp.Body()))
+	})
+
+	t.Run("trims the list under listKey and keeps other properties", func(t *testing.T) {
+		body := map[string]interface{}{
+			"totalCount": 2,
+			"items":      items,
+		}
+		resp := JSONFields(http.StatusOK, body, []string{"title"}, "items")
+		require.JSONEq(t, `{"totalCount":2,"items":[{"title":"A"},{"title":"B"}]}`, string(resp.Body()))
+	})
+}
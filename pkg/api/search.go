@@ -77,7 +77,7 @@ func (hs *HTTPServer) Search(c *models.ReqContext) response.Response {
 	defer c.TimeRequest(metrics.MApiDashboardSearch)
 
 	if !c.QueryBool("accesscontrol") {
-		return response.JSON(http.StatusOK, searchQuery.Result)
+		return response.JSONFields(http.StatusOK, searchQuery.Result, response.Fields(c), "")
 	}
 
 	return hs.searchHitsWithMetadata(c, searchQuery.Result)
@@ -115,7 +115,7 @@ func (hs *HTTPServer) searchHitsWithMetadata(c *models.ReqContext, hits models.H
 		hitsWithMeta = append(hitsWithMeta, hitWithMeta{hit, meta})
 	}
 
-	return response.JSON(http.StatusOK, hitsWithMeta)
+	return response.JSONFields(http.StatusOK, hitsWithMeta, response.Fields(c), "")
 }
 
 func (hs *HTTPServer) ListSortOptions(c *models.ReqContext) response.Response {
@@ -128,6 +128,7 @@ func (hs *HTTPServer) UpdateUserActiveOrg(c *models.ReqContext) response.Respons
 	if err := hs.SQLStore.SetUsingOrg(c.Req.Context(), &cmd); err != nil {
 		return response.Error(500, "Failed to change active organization", err)
 	}
+	hs.clearOrgContext(c)
 
 	return response.Success("Active organization changed")
 }
@@ -235,6 +236,7 @@ func (hs *HTTPServer) UserSetUsingOrg(c *models.ReqContext) response.Response {
 	if err := hs.SQLStore.SetUsingOrg(c.Req.Context(), &cmd); err != nil {
 		return response.Error(500, "Failed to change active organization", err)
 	}
+	hs.clearOrgContext(c)
 
 	return response.Success("Active organization changed")
 }
@@ -256,6 +258,7 @@ func (hs *HTTPServer) ChangeActiveOrgAndRedirectToHome(c *models.ReqContext) {
 	if err := hs.SQLStore.SetUsingOrg(c.Req.Context(), &cmd); err != nil {
 		hs.NotFoundHandler(c)
 	}
+	hs.clearOrgContext(c)
 
 	c.Redirect(hs.Cfg.AppSubURL + "/")
 }
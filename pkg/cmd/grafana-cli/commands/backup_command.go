@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/runner"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/services/backup"
+)
+
+func backupCommand(c utils.CommandLine, runner runner.Runner) error {
+	path := c.Args().First()
+	if path == "" {
+		return fmt.Errorf("missing path of the backup archive")
+	}
+	resources, err := backup.ParseResources(c.String("resources"))
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create backup archive: %w", err)
+	}
+
+	manifest, err := runner.BackupService.Backup(context.Background(), f, resources)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// do not leave an incomplete archive behind
+		_ = os.Remove(path)
+		return fmt.Errorf("failed to back up: %w", err)
+	}
+
+	logger.Infof("\n")
+	logger.Infof("Backed up %v to %s\n", manifest.Resources, color.GreenString(path))
+	logger.Infof("Secrets are replaced by ${GF_BACKUP_...} placeholders, set these environment variables before restoring.\n")
+	return nil
+}
+
+func restoreCommand(c utils.CommandLine, runner runner.Runner) error {
+	path := c.Args().First()
+	if path == "" {
+		return fmt.Errorf("missing path of the backup archive")
+	}
+	resources, err := backup.ParseResources(c.String("resources"))
+	if err != nil {
+		return err
+	}
+
+	// We can ignore the gosec G304 warning on this one because `path` is provided
+	// by the administrator running the command.
+	// nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open backup archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	manifest, summary, err := runner.BackupService.Restore(context.Background(), f, resources)
+	if err != nil {
+		return fmt.Errorf("failed to restore: %w", err)
+	}
+
+	logger.Infof("\n")
+	logger.Infof("Restored backup of Grafana %s created at %s\n", manifest.GrafanaVersion, manifest.Created.Format("2006-01-02 15:04:05"))
+	for _, resource := range backup.Resources {
+		s, ok := summary[resource]
+		if !ok {
+			continue
+		}
+		logger.Infof("%-12s %s restored, %d skipped\n", resource, color.GreenString("%d", s.Restored), s.Skipped)
+	}
+	return nil
+}
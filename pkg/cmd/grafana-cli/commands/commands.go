@@ -160,6 +160,28 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:   "backup",
+		Usage:  "backup <archive path>",
+		Action: runRunnerCommand(backupCommand),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "resources",
+				Usage: "Comma separated list of the resources to back up: orgs, users, datasources, dashboards and alerting. Defaults to all",
+			},
+		},
+	},
+	{
+		Name:   "restore",
+		Usage:  "restore <archive path>",
+		Action: runRunnerCommand(restoreCommand),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "resources",
+				Usage: "Comma separated list of the resources to restore: orgs, users, datasources, dashboards and alerting. Defaults to all resources of the archive",
+			},
+		},
+	},
 	{
 		Name:  "data-migration",
 		Usage: "Runs a script that migrates or cleanups data in your database",
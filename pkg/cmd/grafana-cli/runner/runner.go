@@ -1,8 +1,10 @@
 package runner
 
 import (
+	"github.com/grafana/grafana/pkg/services/backup"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/manager"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -17,11 +19,14 @@ type Runner struct {
 	EncryptionService encryption.Internal
 	SecretsService    *manager.SecretsService
 	SecretsMigrator   secrets.Migrator
+	BackupService     *backup.Service
 }
 
 func New(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, settingsProvider setting.Provider,
 	encryptionService encryption.Internal, features featuremgmt.FeatureToggles,
-	secretsService *manager.SecretsService, secretsMigrator secrets.Migrator,
+	secretsService *manager.SecretsService, secretsMigrator secrets.Migrator, backupService *backup.Service,
+	// the guardian provider sets up the permission checks of the dashboard service
+	_ *guardian.Provider,
 ) Runner {
 	return Runner{
 		Cfg:               cfg,
@@ -31,5 +36,6 @@ func New(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, settingsProvider setting
 		SecretsService:    secretsService,
 		SecretsMigrator:   secretsMigrator,
 		Features:          features,
+		BackupService:     backupService,
 	}
 }
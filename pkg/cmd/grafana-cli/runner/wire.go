@@ -9,13 +9,35 @@ import (
 	"github.com/google/wire"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/ossaccesscontrol"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/auth/authevents"
+	"github.com/grafana/grafana/pkg/services/backup"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashboardstore "github.com/grafana/grafana/pkg/services/dashboards/database"
+	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards/service"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datasourceservice "github.com/grafana/grafana/pkg/services/datasources/service"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	secretsDatabase "github.com/grafana/grafana/pkg/services/secrets/database"
+	secretsStore "github.com/grafana/grafana/pkg/services/secrets/kvstore"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
 	secretsMigrator "github.com/grafana/grafana/pkg/services/secrets/migrator"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -41,6 +63,30 @@ var wireSet = wire.NewSet(
 	secretsMigrator.ProvideSecretsMigrator,
 	wire.Bind(new(secrets.Migrator), new(*secretsMigrator.SecretsMigrator)),
 	hooks.ProvideService,
+	wire.Bind(new(sqlstore.Store), new(*sqlstore.SQLStore)),
+	kvstore.ProvideService,
+	serverlock.ProvideService,
+	authevents.ProvideService,
+	auth.ProvideUserAuthTokenService,
+	wire.Bind(new(models.UserTokenService), new(*auth.UserAuthTokenService)),
+	quota.ProvideService,
+	ossaccesscontrol.ProvideFolderPermissions,
+	wire.Bind(new(accesscontrol.FolderPermissionsService), new(*ossaccesscontrol.FolderPermissionsService)),
+	ossaccesscontrol.ProvideDashboardPermissions,
+	wire.Bind(new(accesscontrol.DashboardPermissionsService), new(*ossaccesscontrol.DashboardPermissionsService)),
+	dashboardstore.ProvideDashboardStore,
+	wire.Bind(new(dashboards.Store), new(*dashboardstore.DashboardStore)),
+	dashboardservice.ProvideDashboardService,
+	wire.Bind(new(dashboards.DashboardService), new(*dashboardservice.DashboardServiceImpl)),
+	alerting.ProvideDashAlertExtractorService,
+	wire.Bind(new(alerting.DashAlertExtractor), new(*alerting.DashAlertExtractorService)),
+	wire.Bind(new(alerting.AlertStore), new(*sqlstore.SQLStore)),
+	guardian.ProvideService,
+	secretsStore.ProvideService,
+	datasourceservice.ProvideService,
+	wire.Bind(new(datasources.DataSourceService), new(*datasourceservice.Service)),
+	provideAlertRuleService,
+	backup.ProvideService,
 )
 
 func Initialize(cfg *setting.Cfg) (Runner, error) {
@@ -48,9 +94,32 @@ func Initialize(cfg *setting.Cfg) (Runner, error) {
 	return Runner{}, nil
 }
 
+// provideAlertRuleService provides the alert rule service of the provisioning API, which the unified alerting
+// service creates in a running instance.
+func provideAlertRuleService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, kv kvstore.KVStore, quotas *quota.QuotaService,
+	dashboardService dashboards.DashboardService) *provisioning.AlertRuleService {
+	logger := log.New("provisioning.alertrules")
+	store := &ngstore.DBstore{
+		SQLStore:        sqlStore,
+		BaseInterval:    cfg.UnifiedAlerting.BaseInterval,
+		DefaultInterval: cfg.UnifiedAlerting.DefaultRuleEvaluationInterval,
+		Logger:          logger,
+	}
+	return provisioning.NewAlertRuleService(store, store, store, quotas, lint.NewService(kv, log.New("ngalert.lint")), store,
+		nil, sqlStore, noOpPluginReader{}, dashboardService, cfg.UnifiedAlerting, nil, logger)
+}
+
 // NoOp implementations of those dependencies that makes no sense to
 // inject on CLI command executions (like the route registerer, for instance).
 
+// noOpPluginReader reports every plugin as a backend plugin that supports alerting, because the CLI does not load
+// plugins. The data sources queried by alert rules are still required to exist.
+type noOpPluginReader struct{}
+
+func (noOpPluginReader) Plugin(_ context.Context, pluginID string) (plugins.PluginDTO, bool) {
+	return plugins.PluginDTO{JSONData: plugins.JSONData{ID: pluginID, Backend: true, Alerting: true}}, true
+}
+
 type noOpUsageStats struct{}
 
 func (noOpUsageStats) GetUsageReport(context.Context) (usagestats.Report, error) {
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-server/commands"
+	"github.com/grafana/grafana/pkg/services/dashboardimport/jsonnet"
 )
 
 // The following variables cannot be constants, since they can be overridden through the -X link flag
@@ -13,6 +14,11 @@ var buildBranch = "main"
 var buildstamp string
 
 func main() {
+	// the jsonnet bundles imported as dashboards are evaluated in child processes of the server
+	if jsonnet.IsEvaluatorProcess() {
+		os.Exit(jsonnet.RunEvaluatorProcess())
+	}
+
 	os.Exit(commands.RunServer(commands.ServerOptions{
 		Version:     version,
 		Commit:      commit,
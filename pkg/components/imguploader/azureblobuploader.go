@@ -29,14 +29,19 @@ type AzureBlobUploader struct {
 	account_key    string
 	container_name string
 	log            log.Logger
+	// URLs with a shared access signature of private images are returned instead of the URLs of public images
+	enableSignedURLs    bool
+	signedURLExpiration time.Duration
 }
 
-func NewAzureBlobUploader(account_name string, account_key string, container_name string) *AzureBlobUploader {
+func NewAzureBlobUploader(account_name string, account_key string, container_name string, enableSignedURLs bool, signedURLExpiration time.Duration) *AzureBlobUploader {
 	return &AzureBlobUploader{
-		account_name:   account_name,
-		account_key:    account_key,
-		container_name: container_name,
-		log:            log.New("azureBlobUploader"),
+		account_name:        account_name,
+		account_key:         account_key,
+		container_name:      container_name,
+		log:                 log.New("azureBlobUploader"),
+		enableSignedURLs:    enableSignedURLs,
+		signedURLExpiration: signedURLExpiration,
 	}
 }
 
@@ -92,7 +97,55 @@ func (az *AzureBlobUploader) Upload(ctx context.Context, imageDiskPath string) (
 	}
 
 	url := fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", az.account_name, az.container_name, randomFileName)
-	return url, nil
+	if !az.enableSignedURLs {
+		return url, nil
+	}
+
+	sas, err := blob.Auth.BlobSAS(az.container_name, randomFileName, time.Now().Add(az.signedURLExpiration))
+	if err != nil {
+		return "", err
+	}
+	return url + "?" + sas, nil
+}
+
+// DeleteOlderThan deletes the images in the container that were uploaded before the given time.
+func (az *AzureBlobUploader) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	blob := NewStorageClient(az.account_name, az.account_key)
+
+	var names []string
+	marker := ""
+	for {
+		list, err := blob.ListBlobs(ctx, az.container_name, marker)
+		if err != nil {
+			return 0, err
+		}
+		for _, b := range list.Blobs {
+			if !isUploadedImage("", b.Name) {
+				continue
+			}
+			lastModified, err := time.Parse(ms_date_layout, b.Properties.LastModified)
+			if err != nil {
+				az.log.Warn("Failed to parse the last modified time of image", "blob_name", b.Name, "err", err)
+				continue
+			}
+			if lastModified.Before(before) {
+				names = append(names, b.Name)
+			}
+		}
+		if list.NextMarker == "" {
+			break
+		}
+		marker = list.NextMarker
+	}
+
+	deleted := 0
+	for _, name := range names {
+		if err := blob.DeleteBlob(ctx, az.container_name, name); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
 }
 
 // --- AZURE LIBRARY
@@ -193,6 +246,92 @@ func (c *StorageClient) FileUpload(ctx context.Context, container, blobName stri
 	return c.transport().RoundTrip(req)
 }
 
+// BlobList is a page of the blobs of a container.
+type BlobList struct {
+	Blobs []struct {
+		Name       string
+		Properties struct {
+			LastModified string `xml:"Last-Modified"`
+		}
+	} `xml:"Blobs>Blob"`
+	NextMarker string
+}
+
+func (c *StorageClient) ListBlobs(ctx context.Context, container, marker string) (*BlobList, error) {
+	u := c.absUrl("%s?restype=container&comp=list", container)
+	if marker != "" {
+		u += "&marker=" + url.QueryEscape(marker)
+	}
+	resp, err := c.do(ctx, "GET", u)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	var list BlobList
+	if err := xml.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (c *StorageClient) DeleteBlob(ctx context.Context, container, blobName string) error {
+	resp, err := c.do(ctx, "DELETE", c.absUrl("%s/%s", container, escape(blobName)))
+	if err != nil {
+		return err
+	}
+	if err := resp.Body.Close(); err != nil {
+		logger.Warn("Failed to close response body", "err", err)
+	}
+	return nil
+}
+
+// do sends a signed request without a body, and returns an error if the response is an error.
+func (c *StorageClient) do(ctx context.Context, method, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	copyHeadersToRequest(req, map[string]string{
+		"x-ms-date":    time.Now().UTC().Format(ms_date_layout),
+		"x-ms-version": version,
+	})
+
+	if err := c.Auth.SignRequest(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				logger.Warn("Failed to close response body", "err", err)
+			}
+		}()
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return nil, err
+		}
+		aerr := &Error{
+			Code:   resp.StatusCode,
+			Status: resp.Status,
+			Body:   body,
+			Header: resp.Header,
+		}
+		aerr.parseXML()
+		return nil, aerr
+	}
+	return resp, nil
+}
+
 func escape(content string) string {
 	content = url.QueryEscape(content)
 	// the Azure's behavior uses %20 to represent whitespace instead of + (plus)
@@ -241,6 +380,50 @@ func (a *Auth) SignRequest(req *http.Request) error {
 	return nil
 }
 
+// sasVersion is the version of the shared access signatures, the string to sign depends on it.
+const sasVersion = "2019-12-12"
+
+// BlobSAS returns the query of a URL with a service shared access signature that allows to read
+// the blob until the expiry time.
+func (a *Auth) BlobSAS(container, blobName string, expiry time.Time) (string, error) {
+	signedExpiry := expiry.UTC().Format(time.RFC3339)
+	strToSign := strings.Join([]string{
+		"r", // signed permissions
+		"",  // signed start
+		signedExpiry,
+		fmt.Sprintf("/blob/%s/%s/%s", a.Account, container, blobName),
+		"", // signed identifier
+		"", // signed IP
+		"https",
+		sasVersion,
+		"b", // signed resource
+		"",  // signed snapshot time
+		"",  // cache control
+		"",  // content disposition
+		"",  // content encoding
+		"",  // content language
+		"",  // content type
+	}, "\n")
+
+	decodedKey, err := base64.StdEncoding.DecodeString(a.Key)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, decodedKey)
+	if _, err := mac.Write([]byte(strToSign)); err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("sv", sasVersion)
+	query.Set("sr", "b")
+	query.Set("sp", "r")
+	query.Set("se", signedExpiry)
+	query.Set("spr", "https")
+	query.Set("sig", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return query.Encode(), nil
+}
+
 func tryget(headers map[string][]string, key string) string {
 	// We default to empty string for "0" values to match server side behavior when generating signatures.
 	if len(headers[key]) > 0 { // && headers[key][0] != "0" { //&& key != "Content-Length" {
@@ -2,7 +2,12 @@ package imguploader
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/require"
@@ -26,3 +31,25 @@ func TestUploadToAzureBlob(t *testing.T) {
 		require.NotEqual(t, "", path)
 	})
 }
+
+func TestAzureBlobSAS(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("secret"))
+	auth := &Auth{Account: "account", Key: key}
+	expiry := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	sas, err := auth.BlobSAS("container", "image.png", expiry)
+	require.NoError(t, err)
+
+	query, err := url.ParseQuery(sas)
+	require.NoError(t, err)
+	require.Equal(t, "2019-12-12", query.Get("sv"))
+	require.Equal(t, "b", query.Get("sr"))
+	require.Equal(t, "r", query.Get("sp"))
+	require.Equal(t, "2022-06-01T12:00:00Z", query.Get("se"))
+	require.Equal(t, "https", query.Get("spr"))
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, err = mac.Write([]byte("r\n\n2022-06-01T12:00:00Z\n/blob/account/container/image.png\n\n\nhttps\n2019-12-12\nb\n\n\n\n\n\n"))
+	require.NoError(t, err)
+	require.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), query.Get("sig"))
+}
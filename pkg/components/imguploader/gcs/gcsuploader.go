@@ -3,12 +3,14 @@ package gcs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -17,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/util"
 	"golang.org/x/oauth2/google"
 	"golang.org/x/oauth2/jwt"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -72,36 +75,9 @@ func (u *Uploader) Upload(ctx context.Context, imageDiskPath string) (string, er
 
 	key := path.Join(u.path, fileName)
 
-	var keyData []byte
-	if u.KeyFile != "" {
-		u.log.Debug("Opening key file ", u.KeyFile)
-		keyData, err = ioutil.ReadFile(u.KeyFile)
-		if err != nil {
-			return "", err
-		}
-	}
-
-	const scope = storage.ScopeReadWrite
-
-	var client gcsifaces.StorageClient
-	if u.KeyFile != "" {
-		u.log.Debug("Creating Google credentials from JSON")
-		creds, err := google.CredentialsFromJSON(ctx, keyData, scope)
-		if err != nil {
-			return "", err
-		}
-
-		u.log.Debug("Creating GCS client")
-		client, err = newClient(ctx, option.WithCredentials(creds))
-		if err != nil {
-			return "", err
-		}
-	} else {
-		u.log.Debug("Creating GCS client with default application credentials")
-		client, err = newClient(ctx, option.WithScopes(scope))
-		if err != nil {
-			return "", err
-		}
+	client, keyData, err := u.newClient(ctx)
+	if err != nil {
+		return "", err
 	}
 
 	if err := u.uploadFile(ctx, client, imageDiskPath, key); err != nil {
@@ -117,7 +93,7 @@ func (u *Uploader) Upload(ctx context.Context, imageDiskPath string) (string, er
 	if u.KeyFile != "" {
 		jwtData = keyData
 	} else {
-		creds, err := client.FindDefaultCredentials(ctx, scope)
+		creds, err := client.FindDefaultCredentials(ctx, storage.ScopeReadWrite)
 		if err != nil {
 			return "", fmt.Errorf("failed to find default Google credentials: %s", err)
 		}
@@ -142,6 +118,75 @@ func (u *Uploader) Upload(ctx context.Context, imageDiskPath string) (string, er
 	return signedURL, nil
 }
 
+// DeleteOlderThan deletes the images in the path of the bucket that were uploaded before the given time.
+func (u *Uploader) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	client, _, err := u.newClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	prefix := u.path
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	bucket := client.Bucket(u.Bucket)
+	it := bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	var keys []string
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		name := strings.TrimPrefix(attrs.Name, prefix)
+		if strings.Contains(name, "/") || !strings.HasSuffix(name, ".png") || !attrs.Created.Before(before) {
+			continue
+		}
+		keys = append(keys, attrs.Name)
+	}
+
+	deleted := 0
+	for _, key := range keys {
+		u.log.Debug("Deleting image from GCS bucket", "bucket", u.Bucket, "key", key)
+		if err := bucket.Object(key).Delete(ctx); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// newClient returns a GCS client for the credentials of the key file, or the default application
+// credentials if there is no key file, together with the content of the key file.
+func (u *Uploader) newClient(ctx context.Context) (gcsifaces.StorageClient, []byte, error) {
+	const scope = storage.ScopeReadWrite
+
+	if u.KeyFile == "" {
+		u.log.Debug("Creating GCS client with default application credentials")
+		client, err := newClient(ctx, option.WithScopes(scope))
+		return client, nil, err
+	}
+
+	u.log.Debug("Opening key file ", u.KeyFile)
+	keyData, err := ioutil.ReadFile(u.KeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u.log.Debug("Creating Google credentials from JSON")
+	creds, err := google.CredentialsFromJSON(ctx, keyData, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u.log.Debug("Creating GCS client")
+	client, err := newClient(ctx, option.WithCredentials(creds))
+	return client, keyData, err
+}
+
 func (u *Uploader) uploadFile(
 	ctx context.Context,
 	client gcsifaces.StorageClient,
@@ -213,6 +258,10 @@ func (b bucketWrapper) Object(key string) gcsifaces.StorageObject {
 	return objectWrapper{b.bucket.Object(key)}
 }
 
+func (b bucketWrapper) Objects(ctx context.Context, q *storage.Query) gcsifaces.StorageObjectIterator {
+	return b.bucket.Objects(ctx, q)
+}
+
 type objectWrapper struct {
 	object *storage.ObjectHandle
 }
@@ -221,6 +270,10 @@ func (o objectWrapper) NewWriter(ctx context.Context) gcsifaces.StorageWriter {
 	return writerWrapper{o.object.NewWriter(ctx)}
 }
 
+func (o objectWrapper) Delete(ctx context.Context) error {
+	return o.object.Delete(ctx)
+}
+
 type writerWrapper struct {
 	*storage.Writer
 }
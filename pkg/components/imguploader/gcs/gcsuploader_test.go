@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2/google"
 	"golang.org/x/oauth2/jwt"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -145,6 +146,49 @@ func TestUploadToGCS_DefaultCredentials(t *testing.T) {
 	})
 }
 
+func TestDeleteOlderThanFromGCS(t *testing.T) {
+	const bucket = "test"
+	ctx := context.Background()
+	now := time.Now()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	it := mock_gcsifaces.NewMockStorageObjectIterator(ctrl)
+	gomock.InOrder(
+		it.EXPECT().Next().Return(&storage.ObjectAttrs{Name: "images/old.png", Created: now.Add(-2 * time.Hour)}, nil),
+		it.EXPECT().Next().Return(&storage.ObjectAttrs{Name: "images/new.png", Created: now}, nil),
+		it.EXPECT().Next().Return(&storage.ObjectAttrs{Name: "images/other/old.png", Created: now.Add(-2 * time.Hour)}, nil),
+		it.EXPECT().Next().Return(&storage.ObjectAttrs{Name: "images/old.txt", Created: now.Add(-2 * time.Hour)}, nil),
+		it.EXPECT().Next().Return(nil, iterator.Done),
+	)
+
+	om := mock_gcsifaces.NewMockStorageObject(ctrl)
+	om.EXPECT().Delete(gomock.Eq(ctx)).Return(nil)
+
+	bm := mock_gcsifaces.NewMockStorageBucket(ctrl)
+	bm.EXPECT().Objects(gomock.Eq(ctx), gomock.Eq(&storage.Query{Prefix: "images/"})).Return(it)
+	bm.EXPECT().Object(gomock.Eq("images/old.png")).Return(om)
+
+	cm := mock_gcsifaces.NewMockStorageClient(ctrl)
+	cm.EXPECT().Bucket(gomock.Eq(bucket)).Return(bm)
+
+	origNewClient := newClient
+	t.Cleanup(func() {
+		newClient = origNewClient
+	})
+	newClient = func(ctx context.Context, options ...option.ClientOption) (gcsifaces.StorageClient, error) {
+		return cm, nil
+	}
+
+	uploader, err := NewUploader("", bucket, "images", false, dfltExpiration)
+	require.NoError(t, err)
+
+	deleted, err := uploader.DeleteOlderThan(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+}
+
 type signedURLOptsMatcher struct {
 	opts *storage.SignedURLOptions
 }
@@ -4,16 +4,18 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/components/imguploader/gcs"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
+	"gopkg.in/ini.v1"
 )
 
 const (
-	pngExt                        = ".png"
-	defaultGCSSignedURLExpiration = 7 * 24 * time.Hour // 7 days
+	pngExt                     = ".png"
+	defaultSignedURLExpiration = 7 * 24 * time.Hour // 7 days
 )
 
 //go:generate mockgen -destination=mock.go -package=imguploader github.com/grafana/grafana/pkg/components/imguploader ImageUploader
@@ -28,6 +30,13 @@ func (NopImageUploader) Upload(ctx context.Context, path string) (string, error)
 	return "", nil
 }
 
+// ImageCleaner is implemented by the image uploaders that can delete the images they uploaded.
+type ImageCleaner interface {
+	// DeleteOlderThan deletes the images that were uploaded before the given time, and returns
+	// the number of deleted images.
+	DeleteOlderThan(ctx context.Context, before time.Time) (int, error)
+}
+
 var (
 	logger = log.New("imguploader")
 )
@@ -62,7 +71,17 @@ func NewImageUploader() (ImageUploader, error) {
 			region = info.region
 		}
 
-		return NewS3Uploader(endpoint, region, bucket, path, "public-read", accessKey, secretKey, pathStyleAccess), nil
+		enableSignedURLs, suExp, err := signedURLSettings(s3sec)
+		if err != nil {
+			return nil, err
+		}
+
+		acl := "public-read"
+		if enableSignedURLs {
+			acl = "private"
+		}
+
+		return NewS3Uploader(endpoint, region, bucket, path, acl, accessKey, secretKey, pathStyleAccess, enableSignedURLs, suExp), nil
 	case "webdav":
 		webdavSec, err := setting.Raw.GetSection("external_image_storage.webdav")
 		if err != nil {
@@ -88,16 +107,9 @@ func NewImageUploader() (ImageUploader, error) {
 		keyFile := gcssec.Key("key_file").MustString("")
 		bucketName := gcssec.Key("bucket").MustString("")
 		path := gcssec.Key("path").MustString("")
-		enableSignedURLs := gcssec.Key("enable_signed_urls").MustBool(false)
-		exp := gcssec.Key("signed_url_expiration").MustString("")
-		var suExp time.Duration
-		if exp != "" {
-			suExp, err = time.ParseDuration(exp)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			suExp = defaultGCSSignedURLExpiration
+		enableSignedURLs, suExp, err := signedURLSettings(gcssec)
+		if err != nil {
+			return nil, err
 		}
 
 		return gcs.NewUploader(keyFile, bucketName, path, enableSignedURLs, suExp)
@@ -110,8 +122,12 @@ func NewImageUploader() (ImageUploader, error) {
 		account_name := azureBlobSec.Key("account_name").MustString("")
 		account_key := azureBlobSec.Key("account_key").MustString("")
 		container_name := azureBlobSec.Key("container_name").MustString("")
+		enableSignedURLs, suExp, err := signedURLSettings(azureBlobSec)
+		if err != nil {
+			return nil, err
+		}
 
-		return NewAzureBlobUploader(account_name, account_key, container_name), nil
+		return NewAzureBlobUploader(account_name, account_key, container_name, enableSignedURLs, suExp), nil
 	case "local":
 		return NewLocalImageUploader()
 	}
@@ -123,6 +139,32 @@ func NewImageUploader() (ImageUploader, error) {
 	return NopImageUploader{}, nil
 }
 
+// isUploadedImage returns true if the key of an object is the key of an image uploaded to the path, the other
+// objects must not be deleted.
+func isUploadedImage(path, key string) bool {
+	if !strings.HasPrefix(key, path) {
+		return false
+	}
+	name := strings.TrimPrefix(key, path)
+	return !strings.Contains(name, "/") && strings.HasSuffix(name, pngExt)
+}
+
+// signedURLSettings returns whether signed URLs are enabled in the section of a provider, and for how
+// long they are valid.
+func signedURLSettings(sec *ini.Section) (bool, time.Duration, error) {
+	enableSignedURLs := sec.Key("enable_signed_urls").MustBool(false)
+	exp := sec.Key("signed_url_expiration").MustString("")
+	if exp == "" {
+		return enableSignedURLs, defaultSignedURLExpiration, nil
+	}
+
+	suExp, err := time.ParseDuration(exp)
+	if err != nil {
+		return false, 0, err
+	}
+	return enableSignedURLs, suExp, nil
+}
+
 type s3Info struct {
 	region string
 	bucket string
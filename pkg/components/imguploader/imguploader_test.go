@@ -2,6 +2,7 @@ package imguploader
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/imguploader/gcs"
 	"github.com/grafana/grafana/pkg/setting"
@@ -83,6 +84,34 @@ func TestImageUploaderFactory(t *testing.T) {
 			})
 		})
 
+		t.Run("S3ImageUploader config with signed URLs", func(t *testing.T) {
+			cfg := setting.NewCfg()
+			err := cfg.Load(setting.CommandLineArgs{
+				HomePath: "../../../",
+			})
+			require.NoError(t, err)
+
+			setting.ImageUploadProvider = "s3"
+
+			s3sec, err := setting.Raw.GetSection("external_image_storage.s3")
+			require.NoError(t, err)
+			_, err = s3sec.NewKey("bucket_url", "https://foo.bar.baz.s3-us-east-2.amazonaws.com")
+			require.NoError(t, err)
+			_, err = s3sec.NewKey("enable_signed_urls", "true")
+			require.NoError(t, err)
+			_, err = s3sec.NewKey("signed_url_expiration", "24h")
+			require.NoError(t, err)
+
+			uploader, err := NewImageUploader()
+			require.NoError(t, err)
+
+			original, ok := uploader.(*S3Uploader)
+			require.True(t, ok)
+			require.Equal(t, "private", original.acl)
+			require.True(t, original.enableSignedURLs)
+			require.Equal(t, 24*time.Hour, original.signedURLExpiration)
+		})
+
 		t.Run("Webdav uploader", func(t *testing.T) {
 			cfg := setting.NewCfg()
 			err := cfg.Load(setting.CommandLineArgs{
@@ -163,6 +192,35 @@ func TestImageUploaderFactory(t *testing.T) {
 				require.Equal(t, "account_name", original.account_name)
 				require.Equal(t, "account_key", original.account_key)
 				require.Equal(t, "container_name", original.container_name)
+				require.False(t, original.enableSignedURLs)
+				require.Equal(t, defaultSignedURLExpiration, original.signedURLExpiration)
+			})
+
+			t.Run("with signed URLs", func(t *testing.T) {
+				azureBlobSec, err := cfg.Raw.GetSection("external_image_storage.azure_blob")
+				require.NoError(t, err)
+				_, err = azureBlobSec.NewKey("enable_signed_urls", "true")
+				require.NoError(t, err)
+				_, err = azureBlobSec.NewKey("signed_url_expiration", "1h")
+				require.NoError(t, err)
+
+				uploader, err := NewImageUploader()
+				require.NoError(t, err)
+
+				original, ok := uploader.(*AzureBlobUploader)
+				require.True(t, ok)
+				require.True(t, original.enableSignedURLs)
+				require.Equal(t, time.Hour, original.signedURLExpiration)
+			})
+
+			t.Run("with invalid signed URL expiration", func(t *testing.T) {
+				azureBlobSec, err := cfg.Raw.GetSection("external_image_storage.azure_blob")
+				require.NoError(t, err)
+				_, err = azureBlobSec.NewKey("signed_url_expiration", "one hour")
+				require.NoError(t, err)
+
+				_, err = NewImageUploader()
+				require.Error(t, err)
 			})
 		})
 
@@ -184,3 +242,11 @@ func TestImageUploaderFactory(t *testing.T) {
 		})
 	})
 }
+
+func TestIsUploadedImage(t *testing.T) {
+	require.True(t, isUploadedImage("", "abc.png"))
+	require.True(t, isUploadedImage("images/", "images/abc.png"))
+	require.False(t, isUploadedImage("images/", "abc.png"))
+	require.False(t, isUploadedImage("images/", "images/other/abc.png"))
+	require.False(t, isUploadedImage("images/", "images/abc.txt"))
+}
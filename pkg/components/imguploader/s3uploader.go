@@ -15,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/defaults"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -31,26 +32,32 @@ type S3Uploader struct {
 	accessKey       string
 	pathStyleAccess bool
 	log             log.Logger
+	// signed URLs of private images are returned instead of the URLs of public images
+	enableSignedURLs    bool
+	signedURLExpiration time.Duration
 }
 
-func NewS3Uploader(endpoint, region, bucket, path, acl, accessKey, secretKey string, pathStyleAccess bool) *S3Uploader {
+func NewS3Uploader(endpoint, region, bucket, path, acl, accessKey, secretKey string, pathStyleAccess bool,
+	enableSignedURLs bool, signedURLExpiration time.Duration) *S3Uploader {
 	return &S3Uploader{
-		endpoint:        endpoint,
-		region:          region,
-		bucket:          bucket,
-		path:            path,
-		acl:             acl,
-		accessKey:       accessKey,
-		secretKey:       secretKey,
-		pathStyleAccess: pathStyleAccess,
-		log:             log.New("s3uploader"),
+		endpoint:            endpoint,
+		region:              region,
+		bucket:              bucket,
+		path:                path,
+		acl:                 acl,
+		accessKey:           accessKey,
+		secretKey:           secretKey,
+		pathStyleAccess:     pathStyleAccess,
+		log:                 log.New("s3uploader"),
+		enableSignedURLs:    enableSignedURLs,
+		signedURLExpiration: signedURLExpiration,
 	}
 }
 
-func (u *S3Uploader) Upload(ctx context.Context, imageDiskPath string) (string, error) {
+func (u *S3Uploader) newSession() (*session.Session, error) {
 	sess, err := session.NewSession()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	creds := credentials.NewChainCredentials(
 		[]credentials.Provider{
@@ -68,7 +75,10 @@ func (u *S3Uploader) Upload(ctx context.Context, imageDiskPath string) (string,
 		S3ForcePathStyle: aws.Bool(u.pathStyleAccess),
 		Credentials:      creds,
 	}
+	return session.NewSession(cfg)
+}
 
+func (u *S3Uploader) Upload(ctx context.Context, imageDiskPath string) (string, error) {
 	rand, err := util.GetRandomString(20)
 	if err != nil {
 		return "", err
@@ -89,7 +99,7 @@ func (u *S3Uploader) Upload(ctx context.Context, imageDiskPath string) (string,
 		}
 	}()
 
-	sess, err = session.NewSession(cfg)
+	sess, err := u.newSession()
 	if err != nil {
 		return "", err
 	}
@@ -105,7 +115,63 @@ func (u *S3Uploader) Upload(ctx context.Context, imageDiskPath string) (string,
 	if err != nil {
 		return "", err
 	}
-	return result.Location, nil
+
+	if !u.enableSignedURLs {
+		return result.Location, nil
+	}
+
+	req, _ := s3.New(sess).GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(u.bucket),
+		Key:    aws.String(key),
+	})
+	return req.Presign(u.signedURLExpiration)
+}
+
+// DeleteOlderThan deletes the images in the path of the bucket that were uploaded before the given time.
+func (u *S3Uploader) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	sess, err := u.newSession()
+	if err != nil {
+		return 0, err
+	}
+	svc := s3.New(sess)
+
+	var keys []*s3.ObjectIdentifier
+	err = svc.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(u.bucket),
+		Prefix: aws.String(u.path),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			if isUploadedImage(u.path, aws.StringValue(obj.Key)) && obj.LastModified != nil && obj.LastModified.Before(before) {
+				keys = append(keys, &s3.ObjectIdentifier{Key: obj.Key})
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	// a request can delete up to 1000 objects
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > 1000 {
+			n = 1000
+		}
+		out, err := svc.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(u.bucket),
+			Delete: &s3.Delete{Objects: keys[:n], Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n - len(out.Errors)
+		for _, e := range out.Errors {
+			u.log.Warn("Failed to delete image", "bucket", u.bucket, "key", aws.StringValue(e.Key), "err", aws.StringValue(e.Message))
+		}
+		keys = keys[n:]
+	}
+	return deleted, nil
 }
 
 func webIdentityProvider(sess client.ConfigProvider) credentials.Provider {
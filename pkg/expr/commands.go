@@ -22,7 +22,10 @@ type Command interface {
 type MathCommand struct {
 	RawExpression string
 	Expression    *mathexp.Expr
-	refID         string
+	// TimeRange is the time range of the query. Its end is the current time of
+	// the time functions of the expression.
+	TimeRange TimeRange
+	refID     string
 }
 
 // NewMathCommand creates a new MathCommand. It will return an error
@@ -54,6 +57,7 @@ func UnmarshalMathCommand(rn *rawNode) (*MathCommand, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid math command type in '%v': %v", rn.RefID, err)
 	}
+	gm.TimeRange = rn.TimeRange
 	return gm, nil
 }
 
@@ -66,7 +70,11 @@ func (gm *MathCommand) NeedsVars() []string {
 // Execute runs the command and returns the results or an error if the command
 // failed to execute.
 func (gm *MathCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
-	return gm.Expression.Execute(gm.refID, vars)
+	now := gm.TimeRange.To
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return gm.Expression.ExecuteAt(gm.refID, vars, now)
 }
 
 // ReduceCommand is an expression command for reduction of a timeseries such as a min, mean, or max.
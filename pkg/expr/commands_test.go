@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
@@ -142,3 +143,19 @@ func randomReduceFunc() string {
 	res := mathexp.GetSupportedReduceFuncs()
 	return res[rand.Intn(len(res)-1)]
 }
+
+func TestMathExecuteAtEndOfTimeRange(t *testing.T) {
+	// Saturday 2022-09-17 10:00 UTC
+	to := time.Date(2022, time.September, 17, 10, 0, 0, 0, time.UTC)
+	cmd, err := UnmarshalMathCommand(&rawNode{
+		RefID:     "B",
+		Query:     map[string]interface{}{"expression": "day_of_week()"},
+		TimeRange: TimeRange{From: to.Add(-time.Hour), To: to},
+	})
+	require.NoError(t, err)
+
+	results, err := cmd.Execute(context.Background(), mathexp.Vars{})
+	require.NoError(t, err)
+	require.Len(t, results.Values, 1)
+	require.Equal(t, ptr.Float64(6), results.Values[0].(mathexp.Scalar).GetFloat64Value())
+}
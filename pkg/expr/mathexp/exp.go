@@ -5,6 +5,7 @@ import (
 	"math"
 	"reflect"
 	"runtime"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
@@ -24,6 +25,9 @@ type State struct {
 	//  - Unions (How many result A and many Result B in case A + B are joined)
 	//  - NaN/Null behavior
 	RefID string
+	// Now is the time the expression is executed at. It is the current time of
+	// functions such as day_of_week and time_since.
+	Now time.Time
 }
 
 // Vars holds the results of datasource queries or other expression commands.
@@ -44,10 +48,16 @@ func New(expr string, funcs ...map[string]parse.Func) (*Expr, error) {
 
 // Execute applies a parse expression to the context and executes it
 func (e *Expr) Execute(refID string, vars Vars) (r Results, err error) {
+	return e.ExecuteAt(refID, vars, time.Now())
+}
+
+// ExecuteAt is like Execute, but the expression is executed as if the current time was now.
+func (e *Expr) ExecuteAt(refID string, vars Vars, now time.Time) (r Results, err error) {
 	s := &State{
 		Expr:  e,
 		Vars:  vars,
 		RefID: refID,
+		Now:   now,
 	}
 	return e.executeState(s)
 }
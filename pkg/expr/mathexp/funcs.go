@@ -68,6 +68,32 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"day_of_week": {
+		Args:         []parse.ReturnType{parse.TypeString},
+		OptionalArgs: 1,
+		Return:       parse.TypeScalar,
+		F:            dayOfWeek,
+		Check:        checkTimezone,
+	},
+	"hour_of_day": {
+		Args:         []parse.ReturnType{parse.TypeString},
+		OptionalArgs: 1,
+		Return:       parse.TypeScalar,
+		F:            hourOfDay,
+		Check:        checkTimezone,
+	},
+	"is_business_hours": {
+		Args:         []parse.ReturnType{parse.TypeString},
+		OptionalArgs: 1,
+		Return:       parse.TypeScalar,
+		F:            isBusinessHours,
+		Check:        checkTimezone,
+	},
+	"time_since": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeNumberSet,
+		F:      timeSince,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
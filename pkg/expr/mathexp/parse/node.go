@@ -161,7 +161,7 @@ func (f *FuncNode) StringAST() string {
 
 // Check performs parse time checking on the FuncNode so it fulfills the Node interface.
 func (f *FuncNode) Check(t *Tree) error {
-	if len(f.Args) < len(f.F.Args) {
+	if len(f.Args) < len(f.F.Args)-f.F.OptionalArgs {
 		return fmt.Errorf("parse: not enough arguments for %s", f.Name)
 	} else if len(f.Args) > len(f.F.Args) {
 		return fmt.Errorf("parse: too many arguments for %s", f.Name)
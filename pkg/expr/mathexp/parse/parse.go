@@ -35,6 +35,9 @@ type Func struct {
 	F             interface{}
 	VariantReturn bool
 	Check         func(*Tree, *FuncNode) error
+	// OptionalArgs is the number of trailing Args that can be omitted.
+	// F must be variadic to accept them.
+	OptionalArgs int
 }
 
 // Parse returns a Tree, created by parsing the expression described in the
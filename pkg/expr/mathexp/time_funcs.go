@@ -0,0 +1,103 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+const (
+	businessHoursStart = 9
+	businessHoursEnd   = 17
+)
+
+// checkTimezone checks that the optional timezone argument of a time function
+// is a known timezone.
+func checkTimezone(_ *parse.Tree, f *parse.FuncNode) error {
+	if len(f.Args) == 0 {
+		return nil
+	}
+	tz, ok := f.Args[0].(*parse.StringNode)
+	if !ok {
+		return fmt.Errorf("parse: expected a timezone for %s", f.Name)
+	}
+	if _, err := time.LoadLocation(tz.Text); err != nil {
+		return fmt.Errorf("parse: invalid timezone %q for %s: %w", tz.Text, f.Name, err)
+	}
+	return nil
+}
+
+// nowIn returns the time the expression is executed at in the timezone, or in UTC if there is none.
+func (e *State) nowIn(tz []string) (time.Time, error) {
+	if len(tz) == 0 {
+		return e.Now.UTC(), nil
+	}
+	loc, err := time.LoadLocation(tz[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return e.Now.In(loc), nil
+}
+
+// dayOfWeek returns a scalar of the day of the week in the timezone, from 0 for Sunday to 6 for Saturday.
+func dayOfWeek(e *State, tz ...string) (Results, error) {
+	now, err := e.nowIn(tz)
+	if err != nil {
+		return Results{}, err
+	}
+	f := float64(now.Weekday())
+	return NewScalarResults(e.RefID, &f), nil
+}
+
+// hourOfDay returns a scalar of the hour of the day in the timezone, from 0 to 23.
+func hourOfDay(e *State, tz ...string) (Results, error) {
+	now, err := e.nowIn(tz)
+	if err != nil {
+		return Results{}, err
+	}
+	f := float64(now.Hour())
+	return NewScalarResults(e.RefID, &f), nil
+}
+
+// isBusinessHours returns a scalar of 1 from 9:00 to 17:00 Monday to Friday in the timezone, else 0.
+func isBusinessHours(e *State, tz ...string) (Results, error) {
+	now, err := e.nowIn(tz)
+	if err != nil {
+		return Results{}, err
+	}
+	f := float64(0)
+	if now.Weekday() != time.Saturday && now.Weekday() != time.Sunday &&
+		now.Hour() >= businessHoursStart && now.Hour() < businessHoursEnd {
+		f = 1
+	}
+	return NewScalarResults(e.RefID, &f), nil
+}
+
+// timeSince returns a number for each series of the seconds since the latest point with a value.
+// NaN is returned for series without values.
+func timeSince(e *State, varSet Results) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		series, ok := res.(Series)
+		if !ok {
+			return newRes, fmt.Errorf("time_since expects a series, got %s", res.Type())
+		}
+		var latest time.Time
+		for i := 0; i < series.Len(); i++ {
+			t, f := series.GetPoint(i)
+			if f != nil && t.After(latest) {
+				latest = t
+			}
+		}
+		nF := math.NaN()
+		if !latest.IsZero() {
+			nF = e.Now.Sub(latest).Seconds()
+		}
+		n := NewNumber(e.RefID, series.GetLabels())
+		n.SetValue(&nF)
+		newRes.Values = append(newRes.Values, n)
+	}
+	return newRes, nil
+}
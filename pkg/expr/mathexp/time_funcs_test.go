@@ -0,0 +1,138 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeFuncs(t *testing.T) {
+	// Friday 2022-09-16 16:30 UTC, which is Saturday 01:30 in Tokyo
+	now := time.Date(2022, time.September, 16, 16, 30, 0, 0, time.UTC)
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "day_of_week in UTC",
+			expr:      "day_of_week()",
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   NewScalarResults("", float64Pointer(5)),
+		},
+		{
+			name:      "day_of_week in a timezone",
+			expr:      `day_of_week("Asia/Tokyo")`,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   NewScalarResults("", float64Pointer(6)),
+		},
+		{
+			name:      "hour_of_day in a timezone",
+			expr:      `hour_of_day("America/New_York")`,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   NewScalarResults("", float64Pointer(12)),
+		},
+		{
+			name:      "is_business_hours during business hours",
+			expr:      `is_business_hours("UTC")`,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   NewScalarResults("", float64Pointer(1)),
+		},
+		{
+			name:      "is_business_hours after business hours",
+			expr:      `is_business_hours("Europe/Berlin")`,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   NewScalarResults("", float64Pointer(0)),
+		},
+		{
+			name:      "is_business_hours on weekends",
+			expr:      `is_business_hours("Asia/Tokyo")`,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   NewScalarResults("", float64Pointer(0)),
+		},
+		{
+			name:      "time functions in conditions",
+			expr:      `day_of_week() >= 1 && day_of_week() <= 5`,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   NewScalarResults("", float64Pointer(1)),
+		},
+		{
+			name:     "unknown timezone - should error",
+			expr:     `day_of_week("Mars/Olympus_Mons")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "number as timezone - should error",
+			expr:     `is_business_hours(1)`,
+			newErrIs: require.Error,
+		},
+		{
+			name: "time_since on series",
+			expr: "time_since($A)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeSeries("", data.Labels{"host": "a"}, tp{
+							now.Add(-5 * time.Minute), float64Pointer(1),
+						}, tp{
+							now.Add(-time.Minute), float64Pointer(2),
+						}, tp{
+							now.Add(-30 * time.Second), nil,
+						}),
+						makeSeries("", data.Labels{"host": "b"}, tp{
+							now.Add(-time.Hour), nil,
+						}),
+					},
+				},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeNumber("", data.Labels{"host": "a"}, float64Pointer(60)),
+					makeNumber("", data.Labels{"host": "b"}, float64Pointer(math.NaN())),
+				},
+			},
+		},
+		{
+			name:     "time_since on scalar - should error",
+			expr:     "time_since(1)",
+			newErrIs: require.Error,
+		},
+	}
+
+	// NaN values are equal to each other
+	opt := cmp.Comparer(func(x, y float64) bool {
+		return (math.IsNaN(x) && math.IsNaN(y)) || x == y
+	})
+	options := append([]cmp.Option{opt}, data.FrameTestCompareOptions()...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.ExecuteAt("", tt.vars, now)
+				tt.execErrIs(t, err)
+				if diff := cmp.Diff(tt.results, res, options...); diff != "" {
+					t.Errorf("Result mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -196,7 +196,7 @@ func (s *Service) buildDSNode(dp *simple.DirectedGraph, rn *rawNode, req *Reques
 // Execute runs the node and adds the results to vars. If the node requires
 // other nodes they must have already been executed and their results must
 // already by in vars.
-func (dn *DSNode) Execute(ctx context.Context, vars mathexp.Vars, s *Service) (mathexp.Results, error) {
+func (dn *DSNode) Execute(ctx context.Context, vars mathexp.Vars, s *Service) (r mathexp.Results, _ error) {
 	dsInstanceSettings, err := adapters.ModelToInstanceSettings(dn.datasource, s.decryptSecureJsonDataFn(ctx))
 	if err != nil {
 		return mathexp.Results{}, fmt.Errorf("%v: %w", "failed to convert datasource instance settings", err)
@@ -221,14 +221,23 @@ func (dn *DSNode) Execute(ctx context.Context, vars mathexp.Vars, s *Service) (m
 		},
 	}
 
+	start := time.Now()
 	resp, err := s.dataService.QueryData(ctx, &backend.QueryDataRequest{
 		PluginContext: pc,
 		Queries:       q,
 		Headers:       dn.request.Headers,
 	})
+	latency := time.Since(start)
 	if err != nil {
 		return mathexp.Results{}, err
 	}
+	defer func() {
+		var frames data.Frames
+		for _, qr := range resp.Responses {
+			frames = append(frames, qr.Frames...)
+		}
+		recordQueryStats(ctx, dn.refID, latency, frames, r)
+	}()
 
 	vals := make([]mathexp.Value, 0)
 	for refID, qr := range resp.Responses {
@@ -89,6 +89,50 @@ func TestService(t *testing.T) {
 	}
 }
 
+func TestServiceQueryStats(t *testing.T) {
+	dsDF := data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+		data.NewField("value", data.Labels{"host": "a"}, []*float64{fp(2), fp(3)}),
+		data.NewField("value", data.Labels{"host": "b"}, []*float64{fp(4), fp(5)}))
+
+	s := Service{
+		cfg:               setting.NewCfg(),
+		dataService:       &mockEndpoint{Frames: []*data.Frame{dsDF}},
+		dataSourceService: &datafakes.FakeDataSourceService{},
+	}
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgId: 1,
+				Uid:   "test",
+				Type:  "test",
+			},
+			JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+		},
+		{
+			RefID:      "B",
+			DataSource: DataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries})
+	require.NoError(t, err)
+
+	collector := NewQueryStatsCollector()
+	_, err = s.ExecutePipeline(WithQueryStatsCollector(context.Background(), collector), pl)
+	require.NoError(t, err)
+
+	stats := collector.Stats()
+	require.Len(t, stats, 1)
+	require.Contains(t, stats, "A")
+	require.Equal(t, 2, stats["A"].SeriesCount)
+	require.Equal(t, 4, stats["A"].SampleCount)
+	require.Greater(t, stats["A"].Bytes, 0)
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
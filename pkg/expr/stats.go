@@ -0,0 +1,86 @@
+package expr
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// QueryStats are the statistics of the data returned by a data source query.
+type QueryStats struct {
+	// SeriesCount is the number of series or numbers the query returned to expressions.
+	SeriesCount int `json:"seriesCount"`
+	// SampleCount is the number of values in the numeric fields of the returned frames.
+	SampleCount int `json:"sampleCount"`
+	// Bytes is the size of the returned frames encoded as Arrow.
+	Bytes int `json:"bytes"`
+	// Latency is the time the data source took to answer the query.
+	Latency time.Duration `json:"latency"`
+}
+
+// QueryStatsCollector collects the statistics of the data source queries executed by
+// pipelines that are given a context from WithQueryStatsCollector.
+type QueryStatsCollector struct {
+	mtx   sync.Mutex
+	stats map[string]QueryStats
+}
+
+func NewQueryStatsCollector() *QueryStatsCollector {
+	return &QueryStatsCollector{stats: make(map[string]QueryStats)}
+}
+
+// Stats returns the statistics of the queries collected so far by RefID.
+func (c *QueryStatsCollector) Stats() map[string]QueryStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	result := make(map[string]QueryStats, len(c.stats))
+	for refID, s := range c.stats {
+		result[refID] = s
+	}
+	return result
+}
+
+func (c *QueryStatsCollector) record(refID string, s QueryStats) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.stats[refID] = s
+}
+
+type queryStatsCollectorKey struct{}
+
+// WithQueryStatsCollector returns a context that makes data source queries executed with it
+// record their statistics in the collector.
+func WithQueryStatsCollector(ctx context.Context, c *QueryStatsCollector) context.Context {
+	return context.WithValue(ctx, queryStatsCollectorKey{}, c)
+}
+
+// recordQueryStats records the statistics of the response of a data source query in the
+// collector of the context, if any.
+func recordQueryStats(ctx context.Context, refID string, latency time.Duration, frames data.Frames, res mathexp.Results) {
+	c, ok := ctx.Value(queryStatsCollectorKey{}).(*QueryStatsCollector)
+	if !ok || c == nil {
+		return
+	}
+	s := QueryStats{
+		SeriesCount: len(res.Values),
+		Latency:     latency,
+	}
+	for _, frame := range frames {
+		if frame == nil {
+			continue
+		}
+		for _, field := range frame.Fields {
+			if field.Type().Numeric() {
+				s.SampleCount += field.Len()
+			}
+		}
+		if b, err := frame.MarshalArrow(); err == nil {
+			s.Bytes += len(b)
+		}
+	}
+	c.record(refID, s)
+}
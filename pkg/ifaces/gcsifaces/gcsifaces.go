@@ -27,12 +27,22 @@ type StorageClient interface {
 type StorageBucket interface {
 	// Object returns a StorageObject for a key.
 	Object(key string) StorageObject
+	// Objects returns a StorageObjectIterator over the objects that match the query.
+	Objects(ctx context.Context, q *storage.Query) StorageObjectIterator
+}
+
+// StorageObjectIterator represents an iterator over GCS objects.
+type StorageObjectIterator interface {
+	// Next returns the attributes of the next object, or iterator.Done if there are no more objects.
+	Next() (*storage.ObjectAttrs, error)
 }
 
 // StorageObject represents a GCS object.
 type StorageObject interface {
 	// NewWriter returns a new StorageWriter.
 	NewWriter(ctx context.Context) StorageWriter
+	// Delete deletes the object.
+	Delete(ctx context.Context) error
 }
 
 // StorageWriter represents a GCS writer.
@@ -0,0 +1,230 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// ErrEgressDenied is returned by the outbound clients for the destinations that the egress policy does not allow.
+var ErrEgressDenied = errors.New("destination is not allowed by the egress policy")
+
+// EgressPolicy restricts the destinations of the outbound clients.
+type EgressPolicy struct {
+	allowed egressRules
+	denied  egressRules
+	// minTLSVersion is the minimum TLS version of the connections, 0 to use the default of the client.
+	minTLSVersion uint16
+}
+
+// NewEgressPolicy creates an egress policy. The rules are host names, wildcards such as *.example.com that
+// match the subdomains of a domain, and CIDR ranges. Host names and wildcards are matched against the host of the
+// requested URL, CIDR ranges against the addresses that the clients connect to. If allowed is not empty, only
+// the destinations that match one of its rules can be reached. Denied rules have precedence over allowed ones.
+func NewEgressPolicy(allowed, denied []string, minTLSVersion uint16) (*EgressPolicy, error) {
+	allowedRules, err := parseEgressRules(allowed)
+	if err != nil {
+		return nil, err
+	}
+	deniedRules, err := parseEgressRules(denied)
+	if err != nil {
+		return nil, err
+	}
+	return &EgressPolicy{allowed: allowedRules, denied: deniedRules, minTLSVersion: minTLSVersion}, nil
+}
+
+var (
+	egressPolicyMtx sync.RWMutex
+	egressPolicy    = &EgressPolicy{}
+)
+
+// SetEgressPolicy sets the policy of all the outbound clients, including those that were already created.
+func SetEgressPolicy(p *EgressPolicy) {
+	if p == nil {
+		p = &EgressPolicy{}
+	}
+	egressPolicyMtx.Lock()
+	defer egressPolicyMtx.Unlock()
+	egressPolicy = p
+}
+
+func getEgressPolicy() *EgressPolicy {
+	egressPolicyMtx.RLock()
+	defer egressPolicyMtx.RUnlock()
+	return egressPolicy
+}
+
+// checkHost checks the host of a requested URL. It returns true if the destination is allowed by name, in which
+// case its addresses are only checked against the denied CIDR ranges.
+func (p *EgressPolicy) checkHost(host string) (bool, error) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+	if p.denied.matchHost(host) || (ip != nil && p.denied.matchIP(ip)) {
+		return false, fmt.Errorf("%w: %s", ErrEgressDenied, host)
+	}
+	if p.allowed.empty() || p.allowed.matchHost(host) || (ip != nil && p.allowed.matchIP(ip)) {
+		return true, nil
+	}
+	if len(p.allowed.networks) == 0 {
+		return false, fmt.Errorf("%w: %s", ErrEgressDenied, host)
+	}
+	// the host might still resolve to an allowed address
+	return false, nil
+}
+
+// checksAddresses returns whether the policy has rules for the addresses of the destinations.
+func (p *EgressPolicy) checksAddresses() bool {
+	return !p.allowed.empty() || len(p.denied.networks) > 0
+}
+
+// checkHostAddresses resolves the host of a requested URL and checks all its addresses. It is used for the requests
+// sent through a proxy, which connects to the destination itself, so the addresses cannot be checked when connecting.
+func (p *EgressPolicy) checkHostAddresses(ctx context.Context, host string, allowedByHost bool) error {
+	ips, err := lookupIP(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, ip := range ips {
+		if err := p.checkIP(ip, allowedByHost); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkIP checks an address that a client connects to.
+func (p *EgressPolicy) checkIP(ip net.IP, allowedByHost bool) error {
+	if p.denied.matchIP(ip) {
+		return fmt.Errorf("%w: %s", ErrEgressDenied, ip)
+	}
+	if !allowedByHost && !p.allowed.matchIP(ip) {
+		return fmt.Errorf("%w: %s", ErrEgressDenied, ip)
+	}
+	return nil
+}
+
+func (p *EgressPolicy) checkTLSVersion(cs tls.ConnectionState) error {
+	if cs.Version < p.minTLSVersion {
+		return fmt.Errorf("%w: %s uses %s, the minimum is %s", ErrEgressDenied, cs.ServerName,
+			tls.VersionName(cs.Version), tls.VersionName(p.minTLSVersion))
+	}
+	return nil
+}
+
+type egressRules struct {
+	hosts    []string
+	networks []*net.IPNet
+}
+
+func parseEgressRules(rules []string) (egressRules, error) {
+	var result egressRules
+	for _, rule := range rules {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		if rule == "" {
+			continue
+		}
+		if strings.Contains(rule, "/") {
+			_, network, err := net.ParseCIDR(rule)
+			if err != nil {
+				return egressRules{}, fmt.Errorf("invalid CIDR range %q: %w", rule, err)
+			}
+			result.networks = append(result.networks, network)
+			continue
+		}
+		if ip := net.ParseIP(rule); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			result.networks = append(result.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		result.hosts = append(result.hosts, rule)
+	}
+	return result, nil
+}
+
+func (r egressRules) empty() bool {
+	return len(r.hosts) == 0 && len(r.networks) == 0
+}
+
+func (r egressRules) matchHost(host string) bool {
+	for _, rule := range r.hosts {
+		if rule == host {
+			return true
+		}
+		if strings.HasPrefix(rule, "*.") && strings.HasSuffix(host, rule[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r egressRules) matchIP(ip net.IP) bool {
+	for _, network := range r.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type egressAllowedByHostKey struct{}
+
+// egressDialContext resolves the address and only connects to the addresses allowed by the policy, so a host name
+// cannot be used to reach a denied address.
+func egressDialContext(dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		policy := getEgressPolicy()
+		if !policy.checksAddresses() {
+			return dial(ctx, network, address)
+		}
+
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		allowedByHost, _ := ctx.Value(egressAllowedByHostKey{}).(bool)
+		ips, err := lookupIP(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for _, ip := range ips {
+			if err := policy.checkIP(ip, allowedByHost); err != nil {
+				lastErr = err
+				continue
+			}
+			conn, err := dial(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no address found for %s", host)
+		}
+		return nil, lastErr
+	}
+}
+
+// lookupIP returns the addresses of a host, or the host itself if it is an address.
+func lookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+	return ips, nil
+}
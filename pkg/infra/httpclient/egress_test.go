@@ -0,0 +1,145 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEgressPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { SetEgressPolicy(nil) })
+	byIP := server.URL
+	byName := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	client := NewOutboundClient(OutboundOptions{Name: "test"})
+
+	testCases := []struct {
+		name    string
+		allowed []string
+		denied  []string
+		url     string
+		err     bool
+	}{
+		{name: "all destinations are allowed without rules", url: byName},
+		{name: "denied range", denied: []string{"127.0.0.0/8"}, url: byIP, err: true},
+		{name: "denied range is checked after name resolution", denied: []string{"127.0.0.0/8"}, url: byName, err: true},
+		{name: "denied host", denied: []string{"localhost"}, url: byName, err: true},
+		{name: "denied host does not match other hosts", denied: []string{"example.com"}, url: byName},
+		{name: "host not in the allowed list", allowed: []string{"example.com"}, url: byName, err: true},
+		{name: "allowed host", allowed: []string{"localhost"}, url: byName},
+		{name: "allowed host does not allow its address", allowed: []string{"localhost"}, url: byIP, err: true},
+		{name: "allowed range", allowed: []string{"127.0.0.1/32"}, url: byIP},
+		{name: "allowed range is checked after name resolution", allowed: []string{"127.0.0.1/32"}, url: byName},
+		{name: "allowed address", allowed: []string{"127.0.0.1"}, url: byIP},
+		{name: "denied rules have precedence", allowed: []string{"localhost"}, denied: []string{"127.0.0.1"}, url: byName, err: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := NewEgressPolicy(tc.allowed, tc.denied, 0)
+			require.NoError(t, err)
+			SetEgressPolicy(policy)
+			// the policy is checked when connecting
+			client.CloseIdleConnections()
+
+			resp, err := client.Get(tc.url)
+			if tc.err {
+				require.ErrorIs(t, err, ErrEgressDenied)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+
+	t.Run("wildcards match subdomains", func(t *testing.T) {
+		policy, err := NewEgressPolicy([]string{"*.example.com"}, nil, 0)
+		require.NoError(t, err)
+		allowed, err := policy.checkHost("hooks.example.com")
+		require.NoError(t, err)
+		require.True(t, allowed)
+		_, err = policy.checkHost("example.com")
+		require.ErrorIs(t, err, ErrEgressDenied)
+		_, err = policy.checkHost("badexample.com")
+		require.ErrorIs(t, err, ErrEgressDenied)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := NewEgressPolicy(nil, []string{"10.0.0.0/33"}, 0)
+		require.Error(t, err)
+	})
+}
+
+func TestEgressPolicyMinTLSVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { SetEgressPolicy(nil) })
+	client := NewOutboundClient(OutboundOptions{Name: "test", InsecureSkipVerify: true})
+
+	policy, err := NewEgressPolicy(nil, nil, tls.VersionTLS12)
+	require.NoError(t, err)
+	SetEgressPolicy(policy)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	policy, err = NewEgressPolicy(nil, nil, tls.VersionTLS13)
+	require.NoError(t, err)
+	SetEgressPolicy(policy)
+	client.CloseIdleConnections()
+	_, err = client.Get(server.URL)
+	require.ErrorIs(t, err, ErrEgressDenied)
+}
+
+func TestEgressPolicyWithProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.Host)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(proxy.Close)
+	t.Cleanup(func() { SetEgressPolicy(nil) })
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("NO_PROXY", "")
+	client := NewOutboundClient(OutboundOptions{Name: "test"})
+
+	testCases := []struct {
+		name    string
+		allowed []string
+		err     bool
+	}{
+		{name: "all destinations are allowed without rules"},
+		{name: "the addresses of the destination are checked instead of the proxy", allowed: []string{"127.0.0.1"}, err: true},
+		{name: "allowed address", allowed: []string{"127.0.0.1", "10.1.2.3"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := NewEgressPolicy(tc.allowed, nil, 0)
+			require.NoError(t, err)
+			SetEgressPolicy(policy)
+			client.CloseIdleConnections()
+			proxied = nil
+
+			resp, err := client.Get("http://10.1.2.3/")
+			if tc.err {
+				require.ErrorIs(t, err, ErrEgressDenied)
+				require.Empty(t, proxied)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, []string{"10.1.2.3"}, proxied)
+		})
+	}
+}
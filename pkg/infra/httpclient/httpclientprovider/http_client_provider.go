@@ -6,6 +6,7 @@ import (
 	"time"
 
 	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics/metricutil"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -37,6 +38,7 @@ func New(cfg *setting.Cfg, validator models.PluginRequestValidator, tracer traci
 	}
 
 	setDefaultTimeoutOptions(cfg)
+	setEgressPolicy(cfg, logger)
 
 	return newProviderFunc(sdkhttpclient.ProviderOptions{
 		Middlewares: middlewares,
@@ -65,6 +67,19 @@ func newConntrackRoundTripper(name string, transport *http.Transport) *http.Tran
 	return transport
 }
 
+// setEgressPolicy sets the policy of the outbound clients, see httpclient.NewOutboundClient.
+//
+// Note: Like the timeout options, the policy is global state as the outbound clients are often package variables.
+func setEgressPolicy(cfg *setting.Cfg, logger log.Logger) {
+	policy, err := httpclient.NewEgressPolicy(cfg.Egress.AllowedHosts, cfg.Egress.DeniedHosts, cfg.Egress.MinTLSVersion)
+	if err != nil {
+		// the settings are validated when they are read
+		logger.Error("Invalid egress policy, outbound requests are not restricted", "error", err)
+		return
+	}
+	httpclient.SetEgressPolicy(policy)
+}
+
 // setDefaultTimeoutOptions overrides the default timeout options for the SDK.
 //
 // Note: Not optimal changing global state, but hard to not do in this case.
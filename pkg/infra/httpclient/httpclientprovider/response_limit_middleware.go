@@ -1,6 +1,7 @@
 package httpclientprovider
 
 import (
+	"encoding/json"
 	"net/http"
 
 	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
@@ -10,8 +11,13 @@ import (
 // ResponseLimitMiddlewareName is the middleware name used by ResponseLimitMiddleware.
 const ResponseLimitMiddlewareName = "response-limit"
 
+// ResponseLimitOptionName is the name of the data source option (jsonData)
+// that lowers the response limit for a single data source.
+const ResponseLimitOptionName = "responseLimit"
+
 func ResponseLimitMiddleware(limit int64) sdkhttpclient.Middleware {
 	return sdkhttpclient.NamedMiddlewareFunc(ResponseLimitMiddlewareName, func(opts sdkhttpclient.Options, next http.RoundTripper) http.RoundTripper {
+		limit := responseLimit(opts, limit)
 		if limit <= 0 {
 			return next
 		}
@@ -22,6 +28,10 @@ func ResponseLimitMiddleware(limit int64) sdkhttpclient.Middleware {
 			}
 
 			if res != nil && res.StatusCode != http.StatusSwitchingProtocols {
+				if res.ContentLength > limit {
+					_ = res.Body.Close()
+					return nil, &httpclient.ResponseLimitError{Limit: limit}
+				}
 				res.Body = httpclient.MaxBytesReader(res.Body, limit)
 			}
 
@@ -29,3 +39,42 @@ func ResponseLimitMiddleware(limit int64) sdkhttpclient.Middleware {
 		})
 	})
 }
+
+// responseLimit returns the response limit of the data source if set in
+// the custom options and lower than the server limit, otherwise the server
+// limit. A server limit of 0 means there is no limit.
+func responseLimit(opts sdkhttpclient.Options, serverLimit int64) int64 {
+	if limit := dataSourceResponseLimit(opts); limit > 0 && (serverLimit <= 0 || limit < serverLimit) {
+		return limit
+	}
+	return serverLimit
+}
+
+// dataSourceResponseLimit returns the response limit set in the custom
+// options of the data source, or 0 if there is none.
+func dataSourceResponseLimit(opts sdkhttpclient.Options) int64 {
+	if opts.CustomOptions == nil {
+		return 0
+	}
+
+	switch v := opts.CustomOptions[ResponseLimitOptionName].(type) {
+	case json.Number:
+		if limit, err := v.Int64(); err == nil && limit > 0 {
+			return limit
+		}
+	case float64:
+		if v > 0 {
+			return int64(v)
+		}
+	case int64:
+		if v > 0 {
+			return v
+		}
+	case int:
+		if v > 0 {
+			return int64(v)
+		}
+	}
+
+	return 0
+}
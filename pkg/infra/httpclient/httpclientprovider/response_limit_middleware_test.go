@@ -2,6 +2,7 @@ package httpclientprovider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 	"github.com/stretchr/testify/require"
+
+	infrahttpclient "github.com/grafana/grafana/pkg/infra/httpclient"
 )
 
 func TestResponseLimitMiddleware(t *testing.T) {
@@ -58,3 +61,54 @@ func TestResponseLimitMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseLimitMiddlewareDataSourceLimit(t *testing.T) {
+	finalRoundTripper := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Request: req, Body: ioutil.NopCloser(strings.NewReader("dummy"))}, nil
+	})
+
+	tcs := []struct {
+		desc          string
+		limit         int64
+		customOptions map[string]interface{}
+		bodyLength    int
+	}{
+		{desc: "data source limit as json.Number", limit: 4, customOptions: map[string]interface{}{ResponseLimitOptionName: json.Number("2")}, bodyLength: 2},
+		{desc: "data source limit as float64", limit: 4, customOptions: map[string]interface{}{ResponseLimitOptionName: float64(3)}, bodyLength: 3},
+		{desc: "data source limit higher than the server limit", limit: 1, customOptions: map[string]interface{}{ResponseLimitOptionName: json.Number("3")}, bodyLength: 1},
+		{desc: "data source limit without a server limit", limit: 0, customOptions: map[string]interface{}{ResponseLimitOptionName: json.Number("2")}, bodyLength: 2},
+		{desc: "invalid data source limit", limit: 1, customOptions: map[string]interface{}{ResponseLimitOptionName: "abc"}, bodyLength: 1},
+		{desc: "no data source limit", limit: 1, customOptions: map[string]interface{}{}, bodyLength: 1},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			rt := ResponseLimitMiddleware(tc.limit).CreateMiddleware(httpclient.Options{CustomOptions: tc.customOptions}, finalRoundTripper)
+			req, err := http.NewRequest(http.MethodGet, "http://test.com/query", nil)
+			require.NoError(t, err)
+			res, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+
+			bodyBytes, err := ioutil.ReadAll(res.Body)
+			require.ErrorIs(t, err, infrahttpclient.ErrResponseBodyTooLarge)
+			require.Len(t, bodyBytes, tc.bodyLength)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestResponseLimitMiddlewareContentLength(t *testing.T) {
+	finalRoundTripper := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Request: req, ContentLength: 5, Body: ioutil.NopCloser(strings.NewReader("dummy"))}, nil
+	})
+
+	rt := ResponseLimitMiddleware(1).CreateMiddleware(httpclient.Options{}, finalRoundTripper)
+	req, err := http.NewRequest(http.MethodGet, "http://test.com/query", nil)
+	require.NoError(t, err)
+
+	// nolint:bodyclose
+	res, err := rt.RoundTrip(req)
+	require.Nil(t, res)
+	var limitErr *infrahttpclient.ResponseLimitError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, int64(1), limitErr.Limit)
+}
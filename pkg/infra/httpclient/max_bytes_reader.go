@@ -15,6 +15,21 @@ import (
 // ErrResponseBodyTooLarge indicates response body is too large
 var ErrResponseBodyTooLarge = errors.New("http: response body too large")
 
+// ResponseLimitError is returned when reading a response body beyond its
+// configured limit. It wraps ErrResponseBodyTooLarge so callers can check
+// for it using errors.Is.
+type ResponseLimitError struct {
+	Limit int64
+}
+
+func (e *ResponseLimitError) Error() string {
+	return fmt.Sprintf("error: %s, response limit is set to: %d", ErrResponseBodyTooLarge, e.Limit)
+}
+
+func (e *ResponseLimitError) Unwrap() error {
+	return ErrResponseBodyTooLarge
+}
+
 // MaxBytesReader is similar to io.LimitReader but is intended for
 // limiting the size of incoming request bodies. In contrast to
 // io.LimitReader, MaxBytesReader's result is a ReadCloser, returns a
@@ -24,13 +39,14 @@ var ErrResponseBodyTooLarge = errors.New("http: response body too large")
 // MaxBytesReader prevents clients from accidentally or maliciously
 // sending a large request and wasting server resources.
 func MaxBytesReader(r io.ReadCloser, n int64) io.ReadCloser {
-	return &maxBytesReader{r: r, n: n}
+	return &maxBytesReader{r: r, n: n, limit: n}
 }
 
 type maxBytesReader struct {
-	r   io.ReadCloser // underlying reader
-	n   int64         // max bytes remaining
-	err error         // sticky error
+	r     io.ReadCloser // underlying reader
+	n     int64         // max bytes remaining
+	limit int64         // max bytes
+	err   error         // sticky error
 }
 
 func (l *maxBytesReader) Read(p []byte) (n int, err error) {
@@ -57,7 +73,7 @@ func (l *maxBytesReader) Read(p []byte) (n int, err error) {
 	n = int(l.n)
 	l.n = 0
 
-	l.err = fmt.Errorf("error: %w, response limit is set to: %d", ErrResponseBodyTooLarge, n)
+	l.err = &ResponseLimitError{Limit: l.limit}
 	return n, l.err
 }
 
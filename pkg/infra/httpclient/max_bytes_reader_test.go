@@ -38,3 +38,13 @@ func TestMaxBytesReader(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxBytesReaderError(t *testing.T) {
+	body := ioutil.NopCloser(strings.NewReader("dummy"))
+	_, err := ioutil.ReadAll(MaxBytesReader(body, 3))
+
+	var limitErr *ResponseLimitError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, int64(3), limitErr.Limit)
+	require.ErrorIs(t, err, ErrResponseBodyTooLarge)
+}
@@ -0,0 +1,132 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mwitkow/go-conntrack"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/net/http/httpproxy"
+)
+
+var outboundRequestCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "outbound_request_total",
+		Help:      "A counter for outgoing requests sent by Grafana, by client. The code is denied for the requests blocked by the egress policy.",
+	},
+	[]string{"client", "code", "method"},
+)
+
+// OutboundOptions are the options of the clients created by NewOutboundClient.
+type OutboundOptions struct {
+	// Name identifies the client in the metrics, for example notifications or rendering.
+	Name string
+	// Timeout of the requests, no timeout if it is 0.
+	Timeout time.Duration
+	// InsecureSkipVerify disables the verification of the certificates of the destinations.
+	InsecureSkipVerify bool
+}
+
+// NewOutboundClient creates a client for the requests that Grafana makes on its own, such as notifications,
+// webhooks, plugin installs and image rendering. The requests are subject to the egress policy set with
+// SetEgressPolicy, and the connections of the client are instrumented.
+func NewOutboundClient(opts OutboundOptions) *http.Client {
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: NewOutboundTransport(opts),
+	}
+}
+
+// NewOutboundTransport creates the transport of the clients created by NewOutboundClient. The requests are sent
+// through the proxy of the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, read when the transport is
+// created.
+func NewOutboundTransport(opts OutboundOptions) http.RoundTripper {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	proxy := httpproxy.FromEnvironment().ProxyFunc()
+	transport := &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		},
+		DialContext: egressDialContext(conntrack.NewDialContextFunc(
+			conntrack.DialWithName("outbound_"+opts.Name),
+			conntrack.DialWithDialContextFunc(dialer.DialContext),
+		)),
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: opts.InsecureSkipVerify, //nolint:gosec
+			Renegotiation:      tls.RenegotiateFreelyAsClient,
+			// the policy is read on each connection, so it applies to the clients created before it was set
+			VerifyConnection: func(cs tls.ConnectionState) error {
+				return getEgressPolicy().checkTLSVersion(cs)
+			},
+		},
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	return &outboundTransport{name: opts.Name, next: transport}
+}
+
+type outboundTransport struct {
+	name string
+	next *http.Transport
+}
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections close the connections of the transport.
+func (t *outboundTransport) CloseIdleConnections() {
+	t.next.CloseIdleConnections()
+}
+
+func (t *outboundTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := getEgressPolicy()
+	allowedByHost, err := policy.checkHost(req.URL.Hostname())
+	if err != nil {
+		outboundRequestCounter.WithLabelValues(t.name, "denied", req.Method).Inc()
+		return nil, err
+	}
+	if policy.checksAddresses() {
+		if err := t.checkProxiedAddresses(req, policy, allowedByHost); err != nil {
+			outboundRequestCounter.WithLabelValues(t.name, errorCode(err), req.Method).Inc()
+			return nil, err
+		}
+	}
+	req = req.WithContext(context.WithValue(req.Context(), egressAllowedByHostKey{}, allowedByHost))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		outboundRequestCounter.WithLabelValues(t.name, errorCode(err), req.Method).Inc()
+		return nil, err
+	}
+	outboundRequestCounter.WithLabelValues(t.name, strconv.Itoa(resp.StatusCode), req.Method).Inc()
+	return resp, nil
+}
+
+// checkProxiedAddresses checks the addresses of the destination of a request sent through a proxy. The dialer only
+// sees the address of the proxy, so the addresses of the destination are resolved and checked before the request is
+// sent. The proxy resolves the host again, so a host whose addresses change between the two lookups is not caught.
+func (t *outboundTransport) checkProxiedAddresses(req *http.Request, policy *EgressPolicy, allowedByHost bool) error {
+	proxyURL, err := t.next.Proxy(req)
+	if err != nil || proxyURL == nil {
+		return err
+	}
+	return policy.checkHostAddresses(req.Context(), req.URL.Hostname(), allowedByHost)
+}
+
+// errorCode is the code of the metrics of a failed request.
+func errorCode(err error) string {
+	if errors.Is(err, ErrEgressDenied) {
+		return "denied"
+	}
+	return "error"
+}
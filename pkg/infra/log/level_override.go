@@ -0,0 +1,105 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log/level"
+)
+
+var (
+	ErrUnknownLogLevel      = errors.New("unknown log level")
+	ErrInvalidLevelTTL      = errors.New("the level override must expire after a positive duration")
+	ErrLevelOverrideMissing = errors.New("no level override for the logger")
+)
+
+// LevelOverride is a log level set at runtime for a named logger and the loggers whose name starts with its name
+// followed by a dot. It takes precedence over the filters of the configuration until it expires.
+type LevelOverride struct {
+	Logger  string    `json:"logger"`
+	Level   string    `json:"level"`
+	Expires time.Time `json:"expires"`
+}
+
+type levelOverride struct {
+	LevelOverride
+	option level.Option
+	timer  *time.Timer
+}
+
+// SetLevel overrides the level of a named logger, for example `ngalert.scheduler`, and of the loggers below it until
+// the TTL has elapsed. Setting the level of a logger again replaces its override and its expiration.
+func SetLevel(logger, levelName string, ttl time.Duration) (LevelOverride, error) {
+	return root.setLevel(logger, levelName, ttl)
+}
+
+// ResetLevel removes the level override of a named logger before it expires.
+func ResetLevel(logger string) error {
+	return root.resetLevel(logger, nil)
+}
+
+// LevelOverrides returns the level overrides that have not expired, sorted by logger.
+func LevelOverrides() []LevelOverride {
+	return root.getLevelOverrides()
+}
+
+func (lm *logManager) setLevel(logger, levelName string, ttl time.Duration) (LevelOverride, error) {
+	levelName = strings.ToLower(levelName)
+	option, ok := logLevels[levelName]
+	if !ok {
+		return LevelOverride{}, fmt.Errorf("%w '%s'", ErrUnknownLogLevel, levelName)
+	}
+	if ttl <= 0 {
+		return LevelOverride{}, ErrInvalidLevelTTL
+	}
+
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+
+	if existing, ok := lm.levelOverrides[logger]; ok {
+		existing.timer.Stop()
+	}
+	override := &levelOverride{
+		LevelOverride: LevelOverride{Logger: logger, Level: levelName, Expires: now().Add(ttl)},
+		option:        option,
+	}
+	override.timer = time.AfterFunc(ttl, func() {
+		_ = lm.resetLevel(logger, override)
+	})
+	lm.levelOverrides[logger] = override
+	lm.swapNamedLoggers()
+	return override.LevelOverride, nil
+}
+
+// resetLevel removes the level override of a logger. If expected is set, the override is removed only if it has not
+// been replaced since.
+func (lm *logManager) resetLevel(logger string, expected *levelOverride) error {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+
+	override, ok := lm.levelOverrides[logger]
+	if !ok || expected != nil && override != expected {
+		return ErrLevelOverrideMissing
+	}
+	override.timer.Stop()
+	delete(lm.levelOverrides, logger)
+	lm.swapNamedLoggers()
+	return nil
+}
+
+func (lm *logManager) getLevelOverrides() []LevelOverride {
+	lm.mutex.RLock()
+	defer lm.mutex.RUnlock()
+
+	result := make([]LevelOverride, 0, len(lm.levelOverrides))
+	for _, override := range lm.levelOverrides {
+		result = append(result, override.LevelOverride)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Logger < result[j].Logger
+	})
+	return result
+}
@@ -0,0 +1,101 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log/level"
+)
+
+func TestLevelOverrides(t *testing.T) {
+	newLoggerScenario(t, "Level overrides should change the level of named loggers until they expire", func(t *testing.T, ctx *scenarioContext) {
+		var messages []string
+		handler := gokitlog.LoggerFunc(func(keyvals ...interface{}) error {
+			for i := 0; i < len(keyvals)-1; i += 2 {
+				if keyvals[i] == "msg" {
+					messages = append(messages, keyvals[i+1].(string))
+				}
+			}
+			return nil
+		})
+		root.initialize([]logWithFilters{{
+			val:      handler,
+			maxLevel: level.AllowInfo(),
+			filters:  map[string]level.Option{"ngalert.scheduler": level.AllowWarn()},
+		}})
+
+		scheduler := New("ngalert.scheduler")
+		ruleLogger := scheduler.New("ruleUID", "abc")
+		other := New("provisioning.contactpoints")
+		logAll := func() {
+			messages = nil
+			scheduler.Info("scheduler info")
+			scheduler.Debug("scheduler debug")
+			ruleLogger.Debug("rule debug")
+			other.Debug("other debug")
+		}
+
+		logAll()
+		require.Empty(t, messages)
+		// the filters of the configuration apply to the loggers below the filtered one
+		New("ngalert.scheduler.ticker").Info("ticker info")
+		require.Empty(t, messages)
+
+		override, err := SetLevel("ngalert", "DEBUG", time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, LevelOverride{Logger: "ngalert", Level: "debug", Expires: ctx.mockedTime.Add(time.Hour)}, override)
+		require.Equal(t, []LevelOverride{override}, LevelOverrides())
+		logAll()
+		require.Equal(t, []string{"scheduler info", "scheduler debug", "rule debug"}, messages)
+
+		t.Run("loggers created after the override should get its level", func(t *testing.T) {
+			messages = nil
+			New("ngalert.state.manager").Debug("state debug")
+			require.Equal(t, []string{"state debug"}, messages)
+		})
+
+		t.Run("the most specific override should win", func(t *testing.T) {
+			_, err := SetLevel("ngalert.scheduler", "error", time.Hour)
+			require.NoError(t, err)
+			logAll()
+			require.Empty(t, messages)
+			require.Len(t, LevelOverrides(), 2)
+
+			require.NoError(t, ResetLevel("ngalert.scheduler"))
+			logAll()
+			require.Equal(t, []string{"scheduler info", "scheduler debug", "rule debug"}, messages)
+		})
+
+		t.Run("resetting should restore the filters of the configuration", func(t *testing.T) {
+			require.NoError(t, ResetLevel("ngalert"))
+			require.ErrorIs(t, ResetLevel("ngalert"), ErrLevelOverrideMissing)
+			require.Empty(t, LevelOverrides())
+			logAll()
+			require.Empty(t, messages)
+		})
+
+		t.Run("overrides should expire", func(t *testing.T) {
+			_, err := SetLevel("provisioning", "debug", 10*time.Millisecond)
+			require.NoError(t, err)
+			logAll()
+			require.Equal(t, []string{"other debug"}, messages)
+
+			require.Eventually(t, func() bool {
+				return len(LevelOverrides()) == 0
+			}, time.Second, 10*time.Millisecond)
+			logAll()
+			require.Empty(t, messages)
+		})
+
+		t.Run("invalid overrides should be rejected", func(t *testing.T) {
+			_, err := SetLevel("ngalert", "verbose", time.Hour)
+			require.ErrorIs(t, err, ErrUnknownLogLevel)
+			_, err = SetLevel("ngalert", "debug", 0)
+			require.ErrorIs(t, err, ErrInvalidLevelTTL)
+			require.Empty(t, LevelOverrides())
+		})
+	})
+}
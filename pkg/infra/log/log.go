@@ -58,6 +58,7 @@ type logManager struct {
 	*ConcreteLogger
 	loggersByName     map[string]*ConcreteLogger
 	logFilters        []logWithFilters
+	levelOverrides    map[string]*levelOverride
 	mutex             sync.RWMutex
 	gokitLogActivated bool
 }
@@ -66,6 +67,7 @@ func newManager(logger gokitlog.Logger) *logManager {
 	return &logManager{
 		ConcreteLogger: newConcreteLogger(logger),
 		loggersByName:  map[string]*ConcreteLogger{},
+		levelOverrides: map[string]*levelOverride{},
 	}
 }
 
@@ -86,6 +88,14 @@ func (lm *logManager) initialize(loggers []logWithFilters) {
 
 	lm.ConcreteLogger.Swap(&compositeLogger{loggers: defaultLoggers})
 	lm.logFilters = loggers
+	lm.swapNamedLoggers()
+}
+
+// swapNamedLoggers applies the filters and level overrides to the named loggers. The caller must hold the lock.
+func (lm *logManager) swapNamedLoggers() {
+	if len(lm.logFilters) == 0 {
+		return
+	}
 
 	loggersByName := []string{}
 	for k := range lm.loggersByName {
@@ -94,21 +104,48 @@ func (lm *logManager) initialize(loggers []logWithFilters) {
 	sort.Strings(loggersByName)
 
 	for _, name := range loggersByName {
-		ctxLoggers := make([]gokitlog.Logger, len(loggers))
+		ctxLoggers := make([]gokitlog.Logger, len(lm.logFilters))
 
-		for index, logger := range loggers {
+		for index, logger := range lm.logFilters {
 			ctxLogger := gokitlog.With(logger.val, lm.loggersByName[name].ctx...)
-			if filterLevel, exists := logger.filters[name]; !exists {
-				ctxLoggers[index] = level.NewFilter(ctxLogger, logger.maxLevel)
-			} else {
-				ctxLoggers[index] = level.NewFilter(ctxLogger, filterLevel)
-			}
+			ctxLoggers[index] = level.NewFilter(ctxLogger, lm.filterLevel(name, logger))
 		}
 
 		lm.loggersByName[name].Swap(&compositeLogger{loggers: ctxLoggers})
 	}
 }
 
+// filterLevel returns the level of a named logger for a handler. A level override or a filter of a logger also applies
+// to the loggers whose name starts with its name followed by a dot, the most specific one wins. Level overrides take
+// precedence over the filters of the configuration. The caller must hold the lock.
+func (lm *logManager) filterLevel(name string, logger logWithFilters) level.Option {
+	if override := lm.findLevelOverride(name); override != nil {
+		return override.option
+	}
+	for n := name; n != ""; n = parentLoggerName(n) {
+		if filterLevel, ok := logger.filters[n]; ok {
+			return filterLevel
+		}
+	}
+	return logger.maxLevel
+}
+
+func (lm *logManager) findLevelOverride(name string) *levelOverride {
+	for n := name; n != ""; n = parentLoggerName(n) {
+		if override, ok := lm.levelOverrides[n]; ok {
+			return override
+		}
+	}
+	return nil
+}
+
+func parentLoggerName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
 func (lm *logManager) New(ctx ...interface{}) *ConcreteLogger {
 	if len(ctx) == 0 {
 		return lm.ConcreteLogger
@@ -134,13 +171,7 @@ func (lm *logManager) New(ctx ...interface{}) *ConcreteLogger {
 
 	compositeLogger := newCompositeLogger()
 	for _, logWithFilter := range lm.logFilters {
-		filterLevel, ok := logWithFilter.filters[loggerName]
-		if ok {
-			logWithFilter.val = level.NewFilter(logWithFilter.val, filterLevel)
-		} else {
-			logWithFilter.val = level.NewFilter(logWithFilter.val, logWithFilter.maxLevel)
-		}
-
+		logWithFilter.val = level.NewFilter(logWithFilter.val, lm.filterLevel(loggerName, logWithFilter))
 		compositeLogger.loggers = append(compositeLogger.loggers, logWithFilter.val)
 	}
 
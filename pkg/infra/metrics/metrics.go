@@ -54,6 +54,12 @@ var (
 	// MApiLoginSAML is a metric api login SAML counter
 	MApiLoginSAML prometheus.Counter
 
+	// MAuthLoginAttempts is a metric counter for login attempts by provider, outcome and org
+	MAuthLoginAttempts *prometheus.CounterVec
+
+	// MAuthEvents is a metric counter for auth token events by event and outcome
+	MAuthEvents *prometheus.CounterVec
+
 	// MApiOrgCreate is a metric api org created counter
 	MApiOrgCreate prometheus.Counter
 
@@ -286,6 +292,18 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MAuthLoginAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "auth_login_attempts_total",
+		Help:      "counter for login attempts by provider, outcome and org",
+		Namespace: ExporterName,
+	}, []string{"provider", "outcome", "org_id"})
+
+	MAuthEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "auth_events_total",
+		Help:      "counter for auth token events by event and outcome",
+		Namespace: ExporterName,
+	}, []string{"event", "outcome"})
+
 	MApiOrgCreate = metricutil.NewCounterStartingAtZero(prometheus.CounterOpts{
 		Name:      "api_org_create_total",
 		Help:      "api org created counter",
@@ -613,6 +631,8 @@ func initMetricVars() {
 		MApiLoginPost,
 		MApiLoginOAuth,
 		MApiLoginSAML,
+		MAuthLoginAttempts,
+		MAuthEvents,
 		MApiOrgCreate,
 		MApiDashboardSnapshotCreate,
 		MApiDashboardSnapshotExternal,
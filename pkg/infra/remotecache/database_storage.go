@@ -132,6 +132,32 @@ func (dc *databaseCache) Delete(ctx context.Context, key string) error {
 	})
 }
 
+// GetAndDelete reads the item and deletes it. The caller whose delete removes the row gets the item.
+func (dc *databaseCache) GetAndDelete(ctx context.Context, key string) (interface{}, error) {
+	val, err := dc.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted int64
+	err = dc.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		res, err := session.Exec("DELETE FROM cache_data WHERE cache_key=?", key)
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		// another caller deleted the item in the meantime
+		return nil, ErrCacheItemNotFound
+	}
+	return val, nil
+}
+
 // CacheData is the struct representing the table in the database
 type CacheData struct {
 	CacheKey  string
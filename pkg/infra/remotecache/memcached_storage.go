@@ -2,6 +2,7 @@ package remotecache
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
@@ -70,3 +71,19 @@ func (s *memcachedStorage) Get(ctx context.Context, key string) (interface{}, er
 func (s *memcachedStorage) Delete(ctx context.Context, key string) error {
 	return s.c.Delete(key)
 }
+
+// GetAndDelete gets value by given key in the cache and deletes it. The caller whose delete removes the key gets
+// the value.
+func (s *memcachedStorage) GetAndDelete(ctx context.Context, key string) (interface{}, error) {
+	val, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.c.Delete(key); err != nil {
+		if errors.Is(err, memcache.ErrCacheMiss) {
+			return nil, ErrCacheItemNotFound
+		}
+		return nil, err
+	}
+	return val, nil
+}
@@ -3,6 +3,7 @@ package remotecache
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -116,3 +117,25 @@ func (s *redisStorage) Delete(ctx context.Context, key string) error {
 	cmd := s.c.Del(ctx, key)
 	return cmd.Err()
 }
+
+// GetAndDelete gets value by given key in session and deletes it in the same transaction.
+func (s *redisStorage) GetAndDelete(ctx context.Context, key string) (interface{}, error) {
+	var get *redis.StringCmd
+	_, err := s.c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	item := &cachedItem{}
+	if err := decodeGob([]byte(get.Val()), item); err != nil {
+		return nil, err
+	}
+	return item.Val, nil
+}
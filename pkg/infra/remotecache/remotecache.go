@@ -55,6 +55,9 @@ type CacheStorage interface {
 
 	// Delete object from cache
 	Delete(ctx context.Context, key string) error
+	// GetAndDelete reads object from Cache and deletes it. Of concurrent callers, only one gets the object and the
+	// others get ErrCacheItemNotFound.
+	GetAndDelete(ctx context.Context, key string) (interface{}, error)
 }
 
 // RemoteCache allows Grafana to cache data outside its own process
@@ -84,6 +87,11 @@ func (ds *RemoteCache) Delete(ctx context.Context, key string) error {
 	return ds.client.Delete(ctx, key)
 }
 
+// GetAndDelete reads object from Cache and deletes it, so that only one caller gets it
+func (ds *RemoteCache) GetAndDelete(ctx context.Context, key string) (interface{}, error) {
+	return ds.client.GetAndDelete(ctx, key)
+}
+
 // Run starts the backend processes for cache clients.
 func (ds *RemoteCache) Run(ctx context.Context) error {
 	// create new interface if more clients need GC jobs
@@ -2,6 +2,8 @@ package remotecache
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -51,6 +53,7 @@ func TestInvalidCacheTypeReturnsError(t *testing.T) {
 func runTestsForClient(t *testing.T, client CacheStorage) {
 	canPutGetAndDeleteCachedObjects(t, client)
 	canNotFetchExpiredItems(t, client)
+	canGetAndDeleteCachedObjectsOnce(t, client)
 }
 
 func canPutGetAndDeleteCachedObjects(t *testing.T, client CacheStorage) {
@@ -87,3 +90,32 @@ func canNotFetchExpiredItems(t *testing.T, client CacheStorage) {
 	_, err = client.Get(context.Background(), "key1")
 	assert.Equal(t, err, ErrCacheItemNotFound)
 }
+
+func canGetAndDeleteCachedObjectsOnce(t *testing.T, client CacheStorage) {
+	cacheableStruct := CacheableStruct{String: "hej", Int64: 2000}
+
+	err := client.Set(context.Background(), "key1", cacheableStruct, 0)
+	require.NoError(t, err)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	var found int32
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			data, err := client.GetAndDelete(context.Background(), "key1")
+			if err == nil {
+				assert.Equal(t, cacheableStruct, data)
+				atomic.AddInt32(&found, 1)
+				return
+			}
+			assert.Equal(t, ErrCacheItemNotFound, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), found)
+
+	_, err = client.Get(context.Background(), "key1")
+	assert.Equal(t, err, ErrCacheItemNotFound)
+}
@@ -9,6 +9,10 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+// OrgContextCookieName is the name of the cookie that holds the organization a session was switched to
+// with an org switch token. It takes precedence over the default organization of the user.
+const OrgContextCookieName = "grafana_org_context"
+
 type CookieOptions struct {
 	Path             string
 	Secure           bool
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/grafana/grafana/pkg/middleware/cookies"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -44,6 +45,11 @@ func OrgRedirect(cfg *setting.Cfg, store sqlstore.Store) web.Handler {
 			return
 		}
 
+		// switching the organization explicitly ends an organization context set with an org switch token
+		if ctx.GetCookie(cookies.OrgContextCookieName) != "" {
+			cookies.DeleteCookie(ctx.Resp, cookies.OrgContextCookieName, nil)
+		}
+
 		urlParams := c.Req.URL.Query()
 		qs := urlParams.Encode()
 
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/readonly"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// readOnlyAllowedPaths are the paths that accept mutating methods in read-only mode, because they do not change
+// data or are needed to leave read-only mode.
+var readOnlyAllowedPaths = []string{
+	"/login",
+	"/log",
+	"/log-grafana-javascript-agent",
+	"/api/ds/query",
+	"/api/frontend-metrics",
+	"/api/admin/read-only",
+}
+
+// readOnlyAllowedPrefixes are the path prefixes that accept mutating methods in read-only mode.
+var readOnlyAllowedPrefixes = []string{
+	"/api/datasources/proxy/",
+}
+
+// ReadOnly rejects the requests that change data with 503 and a Retry-After header while the instance is in
+// read-only mode. Reads, queries and alert evaluation are not affected.
+func ReadOnly(cfg *setting.Cfg, readOnlyService *readonly.Service) web.Handler {
+	return func(c *models.ReqContext) {
+		if !readOnlyService.IsEnabled() || !isMutatingRequest(c.Req) {
+			return
+		}
+
+		path := c.Req.URL.Path
+		if cfg.ServeFromSubPath && cfg.AppSubURL != "" {
+			path = strings.TrimPrefix(path, cfg.AppSubURL)
+		}
+		if isReadOnlyAllowedPath(path) {
+			return
+		}
+
+		retryAfter := int(readOnlyService.RetryAfter().Seconds())
+		c.Resp.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		c.JsonApiErr(http.StatusServiceUnavailable, "Grafana is in read-only mode", nil)
+	}
+}
+
+func isMutatingRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+func isReadOnlyAllowedPath(path string) bool {
+	path = strings.TrimSuffix(path, "/")
+	for _, p := range readOnlyAllowedPaths {
+		if path == p {
+			return true
+		}
+	}
+	for _, p := range readOnlyAllowedPrefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/readonly"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.ReadOnlyModeRetryAfter = 2 * time.Minute
+	readOnlyService, err := readonly.ProvideService(cfg, kvstore.ProvideService(sqlstore.InitTestDB(t)))
+	require.NoError(t, err)
+
+	m := web.New()
+	m.UseMiddleware(web.Renderer("../../public/views", "[[", "]]"))
+	m.Use(getContextHandler(t, cfg, nil, nil).Middleware)
+	m.Use(ReadOnly(cfg, readOnlyService))
+	handler := func(c *models.ReqContext) {
+		c.JSON(http.StatusOK, map[string]interface{}{"message": "OK"})
+	}
+	m.Get("/api/dashboards/uid/abc", handler)
+	m.Post("/api/dashboards/db", handler)
+	m.Post("/api/ds/query", handler)
+	m.Post("/api/datasources/proxy/1/api/v1/query", handler)
+	m.Put("/api/admin/read-only", handler)
+
+	doReq := func(method, path string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req, err := http.NewRequest(method, path, nil)
+		require.NoError(t, err)
+		m.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("all requests pass when read-only mode is disabled", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doReq(http.MethodPost, "/api/dashboards/db").Code)
+	})
+
+	require.NoError(t, readOnlyService.SetEnabled(context.Background(), true, "maintenance"))
+
+	t.Run("mutating requests are rejected in read-only mode", func(t *testing.T) {
+		resp := doReq(http.MethodPost, "/api/dashboards/db")
+		assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+		assert.Equal(t, "120", resp.Header().Get("Retry-After"))
+	})
+
+	t.Run("reads, queries and the read-only API pass in read-only mode", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doReq(http.MethodGet, "/api/dashboards/uid/abc").Code)
+		assert.Equal(t, http.StatusOK, doReq(http.MethodPost, "/api/ds/query").Code)
+		assert.Equal(t, http.StatusOK, doReq(http.MethodPost, "/api/datasources/proxy/1/api/v1/query").Code)
+		assert.Equal(t, http.StatusOK, doReq(http.MethodPut, "/api/admin/read-only").Code)
+	})
+}
@@ -134,6 +134,58 @@ func (mr *MockStorageBucketMockRecorder) Object(key interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Object", reflect.TypeOf((*MockStorageBucket)(nil).Object), key)
 }
 
+// Objects mocks base method
+func (m *MockStorageBucket) Objects(ctx context.Context, q *storage.Query) gcsifaces.StorageObjectIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Objects", ctx, q)
+	ret0, _ := ret[0].(gcsifaces.StorageObjectIterator)
+	return ret0
+}
+
+// Objects indicates an expected call of Objects
+func (mr *MockStorageBucketMockRecorder) Objects(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Objects", reflect.TypeOf((*MockStorageBucket)(nil).Objects), ctx, q)
+}
+
+// MockStorageObjectIterator is a mock of StorageObjectIterator interface
+type MockStorageObjectIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageObjectIteratorMockRecorder
+}
+
+// MockStorageObjectIteratorMockRecorder is the mock recorder for MockStorageObjectIterator
+type MockStorageObjectIteratorMockRecorder struct {
+	mock *MockStorageObjectIterator
+}
+
+// NewMockStorageObjectIterator creates a new mock instance
+func NewMockStorageObjectIterator(ctrl *gomock.Controller) *MockStorageObjectIterator {
+	mock := &MockStorageObjectIterator{ctrl: ctrl}
+	mock.recorder = &MockStorageObjectIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStorageObjectIterator) EXPECT() *MockStorageObjectIteratorMockRecorder {
+	return m.recorder
+}
+
+// Next mocks base method
+func (m *MockStorageObjectIterator) Next() (*storage.ObjectAttrs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(*storage.ObjectAttrs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next
+func (mr *MockStorageObjectIteratorMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockStorageObjectIterator)(nil).Next))
+}
+
 // MockStorageObject is a mock of StorageObject interface
 type MockStorageObject struct {
 	ctrl     *gomock.Controller
@@ -171,6 +223,20 @@ func (mr *MockStorageObjectMockRecorder) NewWriter(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewWriter", reflect.TypeOf((*MockStorageObject)(nil).NewWriter), ctx)
 }
 
+// Delete mocks base method
+func (m *MockStorageObject) Delete(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockStorageObjectMockRecorder) Delete(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorageObject)(nil).Delete), ctx)
+}
+
 // MockStorageWriter is a mock of StorageWriter interface
 type MockStorageWriter struct {
 	ctrl     *gomock.Controller
@@ -23,6 +23,9 @@ type Cfg struct {
 	// Azure Cloud settings
 	Azure *azsettings.AzureSettings
 
+	// DataProxyQueryRowLimit is the maximum number of rows of a data frame returned by a data source query, 0 means no limit
+	DataProxyQueryRowLimit int64
+
 	BuildVersion string // TODO Remove
 }
 
@@ -47,6 +50,8 @@ func FromGrafanaCfg(grafanaCfg *setting.Cfg) *Cfg {
 	// Azure
 	cfg.Azure = grafanaCfg.Azure
 
+	cfg.DataProxyQueryRowLimit = grafanaCfg.DataProxyQueryRowLimit
+
 	cfg.BuildVersion = grafanaCfg.BuildVersion
 
 	return cfg
@@ -2,10 +2,12 @@ package manager
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin/instrumentation"
@@ -35,18 +37,113 @@ func (m *PluginManager) QueryData(ctx context.Context, req *backend.QueryDataReq
 		return nil, fmt.Errorf("%v: %w", "failed to query data", err)
 	}
 
+	rowLimit := m.rowLimit(req.PluginContext)
 	for refID, res := range resp.Responses {
 		// set frame ref ID based on response ref ID
-		for _, f := range res.Frames {
+		for i, f := range res.Frames {
 			if f.RefID == "" {
 				f.RefID = refID
 			}
+			if rowLimit > 0 && f.Rows() > int(rowLimit) {
+				res.Frames[i] = truncateFrame(f, int(rowLimit))
+			}
 		}
 	}
 
 	return resp, err
 }
 
+// rowLimit returns the row limit of the data source if set in its JSON data
+// and lower than the query row limit of the data proxy, otherwise the query
+// row limit of the data proxy. A row limit of 0 means there is no limit.
+func (m *PluginManager) rowLimit(pCtx backend.PluginContext) int64 {
+	serverLimit := m.cfg.DataProxyQueryRowLimit
+	if ds := pCtx.DataSourceInstanceSettings; ds != nil && len(ds.JSONData) > 0 {
+		var jsonData struct {
+			RowLimit int64 `json:"rowLimit"`
+		}
+		if err := json.Unmarshal(ds.JSONData, &jsonData); err == nil && jsonData.RowLimit > 0 &&
+			(serverLimit <= 0 || jsonData.RowLimit < serverLimit) {
+			return jsonData.RowLimit
+		}
+	}
+
+	return serverLimit
+}
+
+// truncateFrame returns a copy of the frame with only the first limit rows, a
+// warning notice that the data is partial and, for clients, the truncation in
+// the custom metadata of the frame, see truncatedMeta.
+func truncateFrame(f *data.Frame, limit int) *data.Frame {
+	rows := f.Rows()
+	truncated := &data.Frame{
+		Name:   f.Name,
+		RefID:  f.RefID,
+		Fields: make([]*data.Field, 0, len(f.Fields)),
+	}
+	for _, field := range f.Fields {
+		n := limit
+		if field.Len() < n {
+			n = field.Len()
+		}
+		tf := data.NewFieldFromFieldType(field.Type(), n)
+		tf.Name = field.Name
+		tf.Labels = field.Labels
+		tf.Config = field.Config
+		for i := 0; i < n; i++ {
+			tf.Set(i, field.At(i))
+		}
+		truncated.Fields = append(truncated.Fields, tf)
+	}
+
+	meta := data.FrameMeta{}
+	if f.Meta != nil {
+		meta = *f.Meta
+		meta.Notices = append([]data.Notice(nil), f.Meta.Notices...)
+	}
+	meta.Custom = truncatedMeta(meta.Custom, rows, limit)
+	truncated.Meta = &meta
+	truncated.AppendNotices(data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     fmt.Sprintf("Results are partial: the data source returned %d rows, which exceeds the row limit of %d", rows, limit),
+	})
+
+	return truncated
+}
+
+// The keys of the custom metadata of a frame truncated to the row limit.
+const (
+	// TruncatedMetaKey is true when the frame was truncated.
+	TruncatedMetaKey = "truncated"
+	// TruncatedRowsMetaKey is the number of rows of the frame before it was truncated.
+	TruncatedRowsMetaKey = "truncatedRows"
+	// RowLimitMetaKey is the row limit the frame was truncated to.
+	RowLimitMetaKey = "rowLimit"
+)
+
+// truncatedMeta adds the truncation of a frame to its custom metadata. The
+// custom metadata of the data source is kept if it is a JSON object.
+func truncatedMeta(custom interface{}, rows, limit int) interface{} {
+	meta := map[string]interface{}{}
+	switch c := custom.(type) {
+	case nil:
+	case map[string]interface{}:
+		for k, v := range c {
+			meta[k] = v
+		}
+	default:
+		b, err := json.Marshal(c)
+		if err != nil || json.Unmarshal(b, &meta) != nil {
+			// the custom metadata is not an object that can hold the truncation
+			return custom
+		}
+	}
+	meta[TruncatedMetaKey] = true
+	meta[TruncatedRowsMetaKey] = rows
+	meta[RowLimitMetaKey] = limit
+	return meta
+}
+
 func (m *PluginManager) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
 	p, exists := m.plugin(ctx, req.PluginContext.PluginID)
 	if !exists {
@@ -6,13 +6,11 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -23,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/plugins"
 )
 
@@ -370,25 +369,11 @@ func (i *Installer) handleResponse(res *http.Response) (io.ReadCloser, error) {
 }
 
 func makeHttpClient(skipTLSVerify bool, timeout time.Duration) http.Client {
-	tr := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: skipTLSVerify,
-		},
-	}
-
-	return http.Client{
-		Timeout:   timeout,
-		Transport: tr,
-	}
+	return *httpclient.NewOutboundClient(httpclient.OutboundOptions{
+		Name:               "plugins",
+		Timeout:            timeout,
+		InsecureSkipVerify: skipTLSVerify,
+	})
 }
 
 func normalizeVersion(version string) string {
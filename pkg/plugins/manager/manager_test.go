@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/grafana-azure-sdk-go/azsettings"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -442,6 +443,73 @@ func TestPluginManager_lifecycle_managed(t *testing.T) {
 						require.Equal(t, json, res.JSONDetails)
 					})
 
+					t.Run("Query data should truncate frames over the row limit", func(t *testing.T) {
+						ctx.manager.cfg.DataProxyQueryRowLimit = 3
+						t.Cleanup(func() { ctx.manager.cfg.DataProxyQueryRowLimit = 0 })
+						ctx.pluginClient.QueryDataHandlerFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+							return &backend.QueryDataResponse{
+								Responses: backend.Responses{
+									"A": backend.DataResponse{Frames: data.Frames{
+										data.NewFrame("large", data.NewField("value", nil, []int64{1, 2, 3, 4, 5})),
+										data.NewFrame("small", data.NewField("value", nil, []int64{1, 2})),
+									}},
+								},
+							}, nil
+						}
+
+						res, err := ctx.manager.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: backend.PluginContext{PluginID: testPluginID}})
+						require.NoError(t, err)
+						frames := res.Responses["A"].Frames
+						require.Len(t, frames, 2)
+						require.Equal(t, 3, frames[0].Rows())
+						require.Equal(t, "A", frames[0].RefID)
+						require.Equal(t, int64(3), frames[0].Fields[0].At(2))
+						require.Len(t, frames[0].Meta.Notices, 1)
+						require.Equal(t, data.NoticeSeverityWarning, frames[0].Meta.Notices[0].Severity)
+						require.Equal(t, map[string]interface{}{TruncatedMetaKey: true, TruncatedRowsMetaKey: 5, RowLimitMetaKey: 3}, frames[0].Meta.Custom)
+						require.Equal(t, 2, frames[1].Rows())
+						require.Nil(t, frames[1].Meta)
+
+						t.Run("a lower row limit of the data source takes precedence", func(t *testing.T) {
+							res, err := ctx.manager.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: backend.PluginContext{
+								PluginID:                   testPluginID,
+								DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{JSONData: []byte(`{"rowLimit": 1}`)},
+							}})
+							require.NoError(t, err)
+							frames := res.Responses["A"].Frames
+							require.Equal(t, 1, frames[0].Rows())
+							require.Equal(t, 1, frames[1].Rows())
+						})
+
+						t.Run("a higher row limit of the data source is ignored", func(t *testing.T) {
+							res, err := ctx.manager.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: backend.PluginContext{
+								PluginID:                   testPluginID,
+								DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{JSONData: []byte(`{"rowLimit": 10}`)},
+							}})
+							require.NoError(t, err)
+							frames := res.Responses["A"].Frames
+							require.Equal(t, 3, frames[0].Rows())
+							require.Equal(t, 2, frames[1].Rows())
+						})
+					})
+
+					t.Run("Query data should keep the custom metadata of truncated frames", func(t *testing.T) {
+						ctx.manager.cfg.DataProxyQueryRowLimit = 1
+						t.Cleanup(func() { ctx.manager.cfg.DataProxyQueryRowLimit = 0 })
+						ctx.pluginClient.QueryDataHandlerFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+							frame := data.NewFrame("large", data.NewField("value", nil, []int64{1, 2}))
+							frame.Meta = &data.FrameMeta{Custom: struct {
+								Cursor string `json:"cursor"`
+							}{Cursor: "abc"}}
+							return &backend.QueryDataResponse{Responses: backend.Responses{"A": backend.DataResponse{Frames: data.Frames{frame}}}}, nil
+						}
+
+						res, err := ctx.manager.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: backend.PluginContext{PluginID: testPluginID}})
+						require.NoError(t, err)
+						custom := res.Responses["A"].Frames[0].Meta.Custom
+						require.Equal(t, map[string]interface{}{"cursor": "abc", TruncatedMetaKey: true, TruncatedRowsMetaKey: 2, RowLimitMetaKey: 1}, custom)
+					})
+
 					t.Run("Call resource should return expected response", func(t *testing.T) {
 						ctx.pluginClient.CallResourceHandlerFunc = func(ctx context.Context,
 							req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
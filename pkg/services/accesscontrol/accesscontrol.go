@@ -40,6 +40,18 @@ type RoleRegistry interface {
 	RegisterFixedRoles(ctx context.Context) error
 }
 
+// RoleGetter returns the roles known to access control by name.
+type RoleGetter interface {
+	// GetRoleByName returns the basic or fixed role with the given name.
+	GetRoleByName(ctx context.Context, name string) (*RoleDTO, error)
+}
+
+// ManagedRoleStore provides the managed roles that hold the permissions granted directly to users.
+type ManagedRoleStore interface {
+	// GetOrCreateManagedUserRole returns the managed role of a user, creating it if the user has none yet.
+	GetOrCreateManagedUserRole(ctx context.Context, orgID, userID int64) (*Role, error)
+}
+
 type PermissionsStore interface {
 	// GetUserPermissions returns user permissions with only action and scope fields set.
 	GetUserPermissions(ctx context.Context, query GetUserPermissionsQuery) ([]Permission, error)
@@ -156,6 +156,24 @@ func TestAccessControlStore_DeleteUserPermissions(t *testing.T) {
 	assert.Len(t, permissions, 0)
 }
 
+func TestAccessControlStore_GetOrCreateManagedUserRole(t *testing.T) {
+	store, sql := setupTestEnv(t)
+
+	user, _ := createUserAndTeam(t, sql, 1)
+
+	role, err := store.GetOrCreateManagedUserRole(context.Background(), 1, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accesscontrol.ManagedUserRoleName(user.ID), role.Name)
+	assert.NotEmpty(t, role.UID)
+
+	existing, err := store.GetOrCreateManagedUserRole(context.Background(), 1, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, role.UID, existing.UID)
+
+	_, err = store.GetOrCreateManagedUserRole(context.Background(), 1, 0)
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+}
+
 func createUserAndTeam(t *testing.T, sql *sqlstore.SQLStore, orgID int64) (*user.User, models.Team) {
 	t.Helper()
 
@@ -75,6 +75,22 @@ func (s *AccessControlStore) setUserResourcePermission(
 	return permission, nil
 }
 
+// GetOrCreateManagedUserRole returns the managed role of a user, creating it if the user has none yet.
+func (s *AccessControlStore) GetOrCreateManagedUserRole(ctx context.Context, orgID, userID int64) (*accesscontrol.Role, error) {
+	if userID == 0 {
+		return nil, models.ErrUserNotFound
+	}
+
+	var role *accesscontrol.Role
+	err := s.sql.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		role, err = s.getOrCreateManagedRole(sess, orgID, accesscontrol.ManagedUserRoleName(userID), s.userAdder(sess, orgID, userID))
+		return err
+	})
+
+	return role, err
+}
+
 func (s *AccessControlStore) SetTeamResourcePermission(
 	ctx context.Context, orgID, teamID int64,
 	cmd types.SetResourcePermissionCommand,
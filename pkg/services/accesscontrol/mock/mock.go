@@ -21,6 +21,7 @@ type Calls struct {
 	GetUserBuiltInRoles            []interface{}
 	RegisterFixedRoles             []interface{}
 	RegisterAttributeScopeResolver []interface{}
+	GetRoleByName                  []interface{}
 }
 
 type Mock struct {
@@ -42,12 +43,14 @@ type Mock struct {
 	GetUserBuiltInRolesFunc            func(user *models.SignedInUser) []string
 	RegisterFixedRolesFunc             func() error
 	RegisterScopeAttributeResolverFunc func(string, accesscontrol.ScopeAttributeResolver)
+	GetRoleByNameFunc                  func(context.Context, string) (*accesscontrol.RoleDTO, error)
 
 	scopeResolvers accesscontrol.ScopeResolvers
 }
 
 // Ensure the mock stays in line with the interface
 var _ fullAccessControl = New()
+var _ accesscontrol.RoleGetter = New()
 
 func New() *Mock {
 	mock := &Mock{
@@ -180,3 +183,14 @@ func (m *Mock) RegisterScopeAttributeResolver(scopePrefix string, resolver acces
 		m.RegisterScopeAttributeResolverFunc(scopePrefix, resolver)
 	}
 }
+
+// GetRoleByName returns the basic or fixed role with the given name.
+// This mock returns accesscontrol.ErrRoleNotFound unless an override is provided.
+func (m *Mock) GetRoleByName(ctx context.Context, name string) (*accesscontrol.RoleDTO, error) {
+	m.Calls.GetRoleByName = append(m.Calls.GetRoleByName, []interface{}{ctx, name})
+	// Use override if provided
+	if m.GetRoleByNameFunc != nil {
+		return m.GetRoleByNameFunc(ctx, name)
+	}
+	return nil, accesscontrol.ErrRoleNotFound
+}
@@ -311,6 +311,9 @@ const (
 	ActionOrgUsersAdd    = "org.users:add"
 	ActionOrgUsersRemove = "org.users:remove"
 	ActionOrgUsersWrite  = "org.users:write"
+	// We can ignore gosec G101 since this does not contain any credentials.
+	// nolint:gosec
+	ActionOrgUsersSwitchTokensCreate = "org.users.switchtokens:create"
 
 	// LDAP actions
 	ActionLDAPUsersRead    = "ldap.user:read"
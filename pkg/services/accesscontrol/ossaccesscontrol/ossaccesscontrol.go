@@ -193,6 +193,30 @@ func (ac *OSSAccessControlService) DeclareFixedRoles(registrations ...accesscont
 	return nil
 }
 
+// GetRoleByName returns the basic role ("basic:viewer") or declared fixed role with the given name
+func (ac *OSSAccessControlService) GetRoleByName(_ context.Context, name string) (*accesscontrol.RoleDTO, error) {
+	for _, basicRole := range ac.roles {
+		if basicRole.Name == name {
+			role := *basicRole
+			return &role, nil
+		}
+	}
+
+	var found *accesscontrol.RoleDTO
+	ac.registrations.Range(func(registration accesscontrol.RoleRegistration) bool {
+		if registration.Role.Name == name {
+			role := registration.Role
+			found = &role
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		return nil, accesscontrol.ErrRoleNotFound
+	}
+	return found, nil
+}
+
 // RegisterScopeAttributeResolver allows the caller to register scope resolvers for a
 // specific scope prefix (ex: datasources:name:)
 func (ac *OSSAccessControlService) RegisterScopeAttributeResolver(scopePrefix string, resolver accesscontrol.ScopeAttributeResolver) {
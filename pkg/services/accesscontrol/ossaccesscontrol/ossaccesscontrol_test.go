@@ -356,6 +356,36 @@ func TestOSSAccessControlService_RegisterFixedRoles(t *testing.T) {
 	}
 }
 
+func TestOSSAccessControlService_GetRoleByName(t *testing.T) {
+	ac := setupTestEnv(t)
+	ac.registrations.Append(accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name:        "fixed:test:test",
+			Permissions: []accesscontrol.Permission{{Action: "test:test"}},
+		},
+		Grants: []string{"Viewer"},
+	})
+	require.NoError(t, ac.RegisterFixedRoles(context.Background()))
+
+	t.Run("should return basic role with its fixed permissions", func(t *testing.T) {
+		role, err := ac.GetRoleByName(context.Background(), "basic:viewer")
+		require.NoError(t, err)
+		assert.Equal(t, "basic:viewer", role.Name)
+		assert.Contains(t, extractRawPermissionsHelper(role.Permissions), accesscontrol.Permission{Action: "test:test"})
+	})
+
+	t.Run("should return fixed role", func(t *testing.T) {
+		role, err := ac.GetRoleByName(context.Background(), "fixed:test:test")
+		require.NoError(t, err)
+		assert.Equal(t, []accesscontrol.Permission{{Action: "test:test"}}, role.Permissions)
+	})
+
+	t.Run("should return error for unknown role", func(t *testing.T) {
+		_, err := ac.GetRoleByName(context.Background(), "fixed:unknown")
+		assert.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+	})
+}
+
 func TestOSSAccessControlService_GetUserPermissions(t *testing.T) {
 	testUser := models.SignedInUser{
 		UserId:  2,
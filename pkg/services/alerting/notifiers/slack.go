@@ -3,12 +3,10 @@ package notifiers
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -16,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/alerting"
@@ -346,20 +345,10 @@ func (sn *SlackNotifier) sendRequest(ctx context.Context, data []byte) error {
 		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", sn.token))
 	}
 
-	netTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			Renegotiation: tls.RenegotiateFreelyAsClient,
-		},
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout: 30 * time.Second,
-		}).DialContext,
-		TLSHandshakeTimeout: 5 * time.Second,
-	}
-	netClient := &http.Client{
-		Timeout:   time.Second * 30,
-		Transport: netTransport,
-	}
+	netClient := httpclient.NewOutboundClient(httpclient.OutboundOptions{
+		Name:    "notifications",
+		Timeout: 30 * time.Second,
+	})
 	resp, err := netClient.Do(request)
 	if err != nil {
 		return err
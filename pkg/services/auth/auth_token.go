@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"net"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth/authevents"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -25,11 +27,12 @@ var getTime = time.Now
 const urgentRotateTime = 1 * time.Minute
 
 func ProvideUserAuthTokenService(sqlStore *sqlstore.SQLStore, serverLockService *serverlock.ServerLockService,
-	cfg *setting.Cfg) *UserAuthTokenService {
+	cfg *setting.Cfg, authEvents *authevents.Service) *UserAuthTokenService {
 	s := &UserAuthTokenService{
 		SQLStore:          sqlStore,
 		ServerLockService: serverLockService,
 		Cfg:               cfg,
+		authEvents:        authEvents,
 		log:               log.New("auth"),
 	}
 	return s
@@ -39,6 +42,7 @@ type UserAuthTokenService struct {
 	SQLStore          *sqlstore.SQLStore
 	ServerLockService *serverlock.ServerLockService
 	Cfg               *setting.Cfg
+	authEvents        *authevents.Service
 	log               log.Logger
 }
 
@@ -91,6 +95,7 @@ func (s *UserAuthTokenService) CreateToken(ctx context.Context, user *user.User,
 		return err
 	})
 
+	s.authEvents.TokenEvent(authevents.EventTokenCreated, user.ID, err)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +111,25 @@ func (s *UserAuthTokenService) CreateToken(ctx context.Context, user *user.User,
 }
 
 func (s *UserAuthTokenService) LookupToken(ctx context.Context, unhashedToken string) (*models.UserToken, error) {
+	userToken, err := s.lookupToken(ctx, unhashedToken)
+
+	var userID int64
+	var revokedErr *models.TokenRevokedError
+	var expiredErr *models.TokenExpiredError
+	switch {
+	case userToken != nil:
+		userID = userToken.UserId
+	case errors.As(err, &revokedErr):
+		userID = revokedErr.UserID
+	case errors.As(err, &expiredErr):
+		userID = expiredErr.UserID
+	}
+	s.authEvents.TokenEvent(authevents.EventTokenLookup, userID, err)
+
+	return userToken, err
+}
+
+func (s *UserAuthTokenService) lookupToken(ctx context.Context, unhashedToken string) (*models.UserToken, error) {
 	hashedToken := hashToken(unhashedToken)
 	var model userAuthToken
 	var exists bool
@@ -274,6 +298,7 @@ func (s *UserAuthTokenService) TryRotateToken(ctx context.Context, token *models
 
 	s.log.Debug("auth token rotated", "affected", affected, "auth_token_id", model.Id, "userId", model.UserId)
 	if affected > 0 {
+		s.authEvents.TokenEvent(authevents.EventTokenRotated, model.UserId, nil)
 		model.UnhashedToken = newToken
 		if err := model.toUserToken(token); err != nil {
 			return false, err
@@ -319,6 +344,7 @@ func (s *UserAuthTokenService) RevokeToken(ctx context.Context, token *models.Us
 	}
 
 	s.log.Debug("user auth token revoked", "tokenId", model.Id, "userId", model.UserId, "clientIP", model.ClientIp, "userAgent", model.UserAgent, "soft", soft)
+	s.authEvents.TokenEvent(authevents.EventTokenRevoked, model.UserId, nil)
 
 	return nil
 }
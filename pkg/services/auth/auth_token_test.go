@@ -13,6 +13,8 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth/authevents"
+	"github.com/grafana/grafana/pkg/services/hooks"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/user"
 
@@ -46,6 +48,14 @@ func TestUserAuthToken(t *testing.T) {
 			require.Equal(t, int64(1), count)
 		})
 
+		t.Run("Records an auth event", func(t *testing.T) {
+			events := ctx.tokenService.authEvents.Events()
+			require.NotEmpty(t, events)
+			require.Equal(t, authevents.EventTokenCreated, events[0].Event)
+			require.Equal(t, authevents.OutcomeSuccess, events[0].Outcome)
+			require.Equal(t, user.ID, events[0].UserID)
+		})
+
 		t.Run("When lookup unhashed token should return user auth token", func(t *testing.T) {
 			userToken, err := ctx.tokenService.LookupToken(context.Background(), userToken.UnhashedToken)
 			require.Nil(t, err)
@@ -541,7 +551,8 @@ func createTestContext(t *testing.T) *testContext {
 			LoginMaxLifetime:             maxLifetimeDurationVal,
 			TokenRotationIntervalMinutes: 10,
 		},
-		log: log.New("test-logger"),
+		authEvents: authevents.ProvideService(&setting.Cfg{AuthEventsEndpointEnabled: true}, hooks.ProvideService()),
+		log:        log.New("test-logger"),
 	}
 
 	return &testContext{
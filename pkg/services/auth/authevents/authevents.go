@@ -0,0 +1,182 @@
+package authevents
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/login"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	EventLogin        = "login"
+	EventTokenCreated = "token_created"
+	EventTokenLookup  = "token_lookup"
+	EventTokenRotated = "token_rotated"
+	EventTokenRevoked = "token_revoked"
+)
+
+const (
+	OutcomeSuccess            = "success"
+	OutcomeInvalidCredentials = "invalid_credentials"
+	OutcomeTooManyAttempts    = "too_many_attempts"
+	OutcomeUserDisabled       = "user_disabled"
+	OutcomeDenied             = "denied"
+	OutcomeNotFound           = "not_found"
+	OutcomeRevoked            = "revoked"
+	OutcomeExpired            = "expired"
+	OutcomeError              = "error"
+)
+
+// unknownProvider is the provider of login attempts that failed before the provider was known.
+const unknownProvider = "unknown"
+
+// Event is a login attempt or an auth token event.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	Provider string    `json:"provider,omitempty"`
+	Outcome  string    `json:"outcome"`
+	OrgID    int64     `json:"orgId,omitempty"`
+	UserID   int64     `json:"userId,omitempty"`
+	Login    string    `json:"login,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Service counts login attempts and auth token events, and keeps the latest of them for the
+// events endpoint when it is enabled.
+type Service struct {
+	enabled   bool
+	maxEvents int
+
+	mu     sync.Mutex
+	events []Event
+	next   int
+}
+
+func ProvideService(cfg *setting.Cfg, hooksService *hooks.HooksService) *Service {
+	s := &Service{
+		enabled:   cfg.AuthEventsEndpointEnabled,
+		maxEvents: cfg.AuthEventsEndpointMaxEvents,
+	}
+	if s.maxEvents <= 0 {
+		s.maxEvents = 100
+	}
+	hooksService.AddLoginHook(s.loginHook)
+	return s
+}
+
+// loginHook records the login attempts of all the login providers, which run the login hooks with
+// the outcome of the attempt.
+func (s *Service) loginHook(info *models.LoginInfo, _ *models.ReqContext) {
+	e := Event{
+		Event:    EventLogin,
+		Provider: info.AuthModule,
+		Outcome:  loginOutcome(info.Error),
+	}
+	if e.Provider == "" {
+		e.Provider = unknownProvider
+	}
+	if info.Error != nil {
+		e.Error = info.Error.Error()
+	}
+	if info.User != nil {
+		e.OrgID = info.User.OrgID
+		e.UserID = info.User.ID
+		e.Login = info.User.Login
+	}
+
+	orgID := ""
+	if e.OrgID != 0 {
+		orgID = strconv.FormatInt(e.OrgID, 10)
+	}
+	metrics.MAuthLoginAttempts.WithLabelValues(e.Provider, e.Outcome, orgID).Inc()
+	s.record(e)
+}
+
+// TokenEvent records an event of the auth token of a user, err is the error of the operation.
+// Successful token lookups happen on every request, so they are counted but not kept.
+func (s *Service) TokenEvent(event string, userID int64, err error) {
+	e := Event{
+		Event:   event,
+		Outcome: tokenOutcome(err),
+		UserID:  userID,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	metrics.MAuthEvents.WithLabelValues(e.Event, e.Outcome).Inc()
+	if event == EventTokenLookup && err == nil {
+		return
+	}
+	s.record(e)
+}
+
+// Events returns the kept events, latest first. It is empty unless the events endpoint is enabled.
+func (s *Service) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]Event, 0, len(s.events))
+	for i := 1; i <= len(s.events); i++ {
+		events = append(events, s.events[(s.next-i+len(s.events))%len(s.events)])
+	}
+	return events
+}
+
+func (s *Service) record(e Event) {
+	if !s.enabled {
+		return
+	}
+	e.Time = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) < s.maxEvents {
+		s.events = append(s.events, e)
+	} else {
+		s.events[s.next] = e
+	}
+	s.next = (s.next + 1) % s.maxEvents
+}
+
+func loginOutcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, login.ErrInvalidCredentials), errors.Is(err, models.ErrUserNotFound):
+		return OutcomeInvalidCredentials
+	case errors.Is(err, login.ErrTooManyLoginAttempts):
+		return OutcomeTooManyAttempts
+	case errors.Is(err, login.ErrUserDisabled):
+		return OutcomeUserDisabled
+	case errors.Is(err, login.ErrProviderDeniedRequest), errors.Is(err, login.ErrEmailNotAllowed),
+		errors.Is(err, login.ErrNoEmail):
+		return OutcomeDenied
+	default:
+		return OutcomeError
+	}
+}
+
+func tokenOutcome(err error) string {
+	var revokedErr *models.TokenRevokedError
+	var expiredErr *models.TokenExpiredError
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, models.ErrUserTokenNotFound):
+		return OutcomeNotFound
+	case errors.As(err, &revokedErr):
+		return OutcomeRevoked
+	case errors.As(err, &expiredErr):
+		return OutcomeExpired
+	default:
+		return OutcomeError
+	}
+}
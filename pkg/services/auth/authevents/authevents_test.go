@@ -0,0 +1,115 @@
+package authevents
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/login"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLoginHook(t *testing.T) {
+	hooksService := hooks.ProvideService()
+	s := ProvideService(&setting.Cfg{AuthEventsEndpointEnabled: true}, hooksService)
+
+	attempts := func(provider, outcome, orgID string) float64 {
+		return testutil.ToFloat64(metrics.MAuthLoginAttempts.WithLabelValues(provider, outcome, orgID))
+	}
+	before := attempts("github", OutcomeSuccess, "2")
+	beforeDenied := attempts("github", OutcomeDenied, "")
+
+	hooksService.RunLoginHook(&models.LoginInfo{
+		AuthModule: "github",
+		User:       &user.User{ID: 3, OrgID: 2, Login: "jdoe"},
+	}, nil)
+	hooksService.RunLoginHook(&models.LoginInfo{
+		AuthModule: "github",
+		Error:      login.ErrEmailNotAllowed,
+	}, nil)
+
+	require.Equal(t, before+1, attempts("github", OutcomeSuccess, "2"))
+	require.Equal(t, beforeDenied+1, attempts("github", OutcomeDenied, ""))
+
+	events := s.Events()
+	require.Len(t, events, 2)
+	require.Equal(t, Event{
+		Time:     events[0].Time,
+		Event:    EventLogin,
+		Provider: "github",
+		Outcome:  OutcomeDenied,
+		Error:    login.ErrEmailNotAllowed.Error(),
+	}, events[0])
+	require.Equal(t, Event{
+		Time:     events[1].Time,
+		Event:    EventLogin,
+		Provider: "github",
+		Outcome:  OutcomeSuccess,
+		OrgID:    2,
+		UserID:   3,
+		Login:    "jdoe",
+	}, events[1])
+}
+
+func TestLoginOutcome(t *testing.T) {
+	tests := map[error]string{
+		nil:                            OutcomeSuccess,
+		login.ErrInvalidCredentials:    OutcomeInvalidCredentials,
+		models.ErrUserNotFound:         OutcomeInvalidCredentials,
+		login.ErrTooManyLoginAttempts:  OutcomeTooManyAttempts,
+		login.ErrUserDisabled:          OutcomeUserDisabled,
+		login.ErrProviderDeniedRequest: OutcomeDenied,
+		login.ErrNoEmail:               OutcomeDenied,
+		errors.New("boom"):             OutcomeError,
+	}
+	for err, outcome := range tests {
+		require.Equal(t, outcome, loginOutcome(err), "error: %v", err)
+	}
+}
+
+func TestTokenEvent(t *testing.T) {
+	s := ProvideService(&setting.Cfg{AuthEventsEndpointEnabled: true}, hooks.ProvideService())
+
+	lookups := func(outcome string) float64 {
+		return testutil.ToFloat64(metrics.MAuthEvents.WithLabelValues(EventTokenLookup, outcome))
+	}
+	beforeSuccess, beforeRevoked := lookups(OutcomeSuccess), lookups(OutcomeRevoked)
+
+	s.TokenEvent(EventTokenLookup, 1, nil)
+	s.TokenEvent(EventTokenLookup, 1, &models.TokenRevokedError{UserID: 1, TokenID: 2})
+
+	require.Equal(t, beforeSuccess+1, lookups(OutcomeSuccess))
+	require.Equal(t, beforeRevoked+1, lookups(OutcomeRevoked))
+
+	events := s.Events()
+	require.Len(t, events, 1, "successful lookups are not kept")
+	require.Equal(t, OutcomeRevoked, events[0].Outcome)
+	require.Equal(t, int64(1), events[0].UserID)
+}
+
+func TestEvents(t *testing.T) {
+	t.Run("keeps the latest events", func(t *testing.T) {
+		s := ProvideService(&setting.Cfg{AuthEventsEndpointEnabled: true, AuthEventsEndpointMaxEvents: 2}, hooks.ProvideService())
+		for userID := int64(1); userID <= 3; userID++ {
+			s.TokenEvent(EventTokenCreated, userID, nil)
+		}
+
+		events := s.Events()
+		require.Len(t, events, 2)
+		require.Equal(t, int64(3), events[0].UserID)
+		require.Equal(t, int64(2), events[1].UserID)
+	})
+
+	t.Run("keeps nothing when the events endpoint is disabled", func(t *testing.T) {
+		s := ProvideService(setting.NewCfg(), hooks.ProvideService())
+		s.TokenEvent(EventTokenCreated, 1, nil)
+
+		require.Empty(t, s.Events())
+	})
+}
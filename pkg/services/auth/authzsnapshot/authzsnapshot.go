@@ -0,0 +1,306 @@
+package authzsnapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// SignatureHeader is the header of the responses and webhook requests that holds the HMAC-SHA256 of their body.
+const SignatureHeader = "X-Grafana-Signature"
+
+const (
+	kvNamespace    = "authz-snapshot"
+	webhookTimeout = 10 * time.Second
+)
+
+// Snapshot is the org roles, teams and permissions of a user. The version increases every time the content of the
+// snapshot changes, and the hash identifies the content.
+type Snapshot struct {
+	UserID         int64         `json:"userId"`
+	Login          string        `json:"login"`
+	IsGrafanaAdmin bool          `json:"isGrafanaAdmin"`
+	Orgs           []OrgSnapshot `json:"orgs"`
+	Version        int64         `json:"version"`
+	Hash           string        `json:"hash"`
+}
+
+// OrgSnapshot is the role, the teams and a hash of the RBAC permissions of a user in an organization. The hash
+// changes whenever a permission of the user is granted or revoked in the organization.
+type OrgSnapshot struct {
+	OrgID           int64           `json:"orgId"`
+	Role            models.RoleType `json:"role"`
+	Teams           []int64         `json:"teams"`
+	PermissionsHash string          `json:"permissionsHash"`
+}
+
+// Change is the body of the webhook requests sent when the snapshot of a user changes.
+type Change struct {
+	UserID          int64  `json:"userId"`
+	Version         int64  `json:"version"`
+	PreviousVersion int64  `json:"previousVersion"`
+	Hash            string `json:"hash"`
+}
+
+// version is the latest version of the snapshot of a user and the hash of its content.
+type version struct {
+	Version int64  `json:"version"`
+	Hash    string `json:"hash"`
+}
+
+// Service builds the authorization snapshots of users and keeps track of their versions. The users whose snapshot
+// was fetched are checked periodically, and the webhook is notified when their snapshot changes.
+type Service struct {
+	cfg      setting.AuthzSnapshotSettings
+	key      []byte
+	sqlStore *sqlstore.SQLStore
+	ac       accesscontrol.AccessControl
+	versions *kvstore.NamespacedKVStore
+	client   *http.Client
+	log      log.Logger
+
+	// mu serializes the updates of the versions.
+	mu sync.Mutex
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, ac accesscontrol.AccessControl, kv kvstore.KVStore) *Service {
+	return &Service{
+		cfg:      cfg.AuthzSnapshot,
+		key:      []byte(cfg.AuthzSnapshot.SigningKey),
+		sqlStore: sqlStore,
+		ac:       ac,
+		versions: kvstore.WithNamespace(kv, 0, kvNamespace),
+		client:   httpclient.NewOutboundClient(httpclient.OutboundOptions{Name: "authz_snapshot_webhook", Timeout: webhookTimeout}),
+		log:      log.New("auth.authzsnapshot"),
+	}
+}
+
+// IsDisabled returns true if there is no webhook to notify of the changes.
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.Enabled || s.cfg.WebhookURL == ""
+}
+
+// Run checks the snapshots that were fetched for changes until the context is done.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.poll(ctx)
+		}
+	}
+}
+
+func (s *Service) poll(ctx context.Context) {
+	keys, err := s.versions.Keys(ctx, "")
+	if err != nil {
+		s.log.Error("failed to list the authorization snapshots", "err", err)
+		return
+	}
+	for _, key := range keys {
+		userID, err := strconv.ParseInt(key.Key, 10, 64)
+		if err != nil {
+			continue
+		}
+		if _, err := s.GetSnapshot(ctx, userID); err != nil {
+			if errors.Is(err, models.ErrUserNotFound) {
+				// the user was deleted, there is nothing left to mirror
+				if err := s.versions.Del(ctx, key.Key); err != nil {
+					s.log.Warn("failed to delete the version of the authorization snapshot", "userId", userID, "err", err)
+				}
+				continue
+			}
+			s.log.Error("failed to check the authorization snapshot", "userId", userID, "err", err)
+		}
+	}
+}
+
+// GetSnapshot returns the current snapshot of a user. If its content changed since the last snapshot, its version is
+// increased and the webhook is notified.
+func (s *Service) GetSnapshot(ctx context.Context, userID int64) (*Snapshot, error) {
+	userQuery := models.GetUserByIdQuery{Id: userID}
+	if err := s.sqlStore.GetUserById(ctx, &userQuery); err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{
+		UserID:         userQuery.Result.ID,
+		Login:          userQuery.Result.Login,
+		IsGrafanaAdmin: userQuery.Result.IsAdmin,
+		Orgs:           []OrgSnapshot{},
+	}
+
+	orgsQuery := models.GetUserOrgListQuery{UserId: userID}
+	if err := s.sqlStore.GetUserOrgList(ctx, &orgsQuery); err != nil {
+		return nil, err
+	}
+	for _, org := range orgsQuery.Result {
+		orgSnapshot, err := s.orgSnapshot(ctx, userID, org.OrgId)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Orgs = append(snapshot.Orgs, orgSnapshot)
+	}
+	sort.Slice(snapshot.Orgs, func(i, j int) bool {
+		return snapshot.Orgs[i].OrgID < snapshot.Orgs[j].OrgID
+	})
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	snapshot.Hash = hex.EncodeToString(sum[:])
+
+	if snapshot.Version, err = s.updateVersion(ctx, userID, snapshot.Hash); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func (s *Service) orgSnapshot(ctx context.Context, userID, orgID int64) (OrgSnapshot, error) {
+	query := models.GetSignedInUserQuery{UserId: userID, OrgId: orgID}
+	if err := s.sqlStore.GetSignedInUser(ctx, &query); err != nil {
+		return OrgSnapshot{}, err
+	}
+	permissions, err := s.ac.GetUserPermissions(ctx, query.Result, accesscontrol.Options{ReloadCache: true})
+	if err != nil {
+		return OrgSnapshot{}, fmt.Errorf("failed to get the permissions of the user in org %d: %w", orgID, err)
+	}
+
+	teams := append([]int64{}, query.Result.Teams...)
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i] < teams[j]
+	})
+	return OrgSnapshot{
+		OrgID:           orgID,
+		Role:            query.Result.OrgRole,
+		Teams:           teams,
+		PermissionsHash: hashPermissions(permissions),
+	}, nil
+}
+
+// hashPermissions returns a hash of the permissions that does not depend on their order or duplicates.
+func hashPermissions(permissions []accesscontrol.Permission) string {
+	pairs := make(map[string]struct{}, len(permissions))
+	for _, p := range permissions {
+		pairs[p.Action+"\x00"+p.Scope] = struct{}{}
+	}
+	sorted := make([]string, 0, len(pairs))
+	for pair := range pairs {
+		sorted = append(sorted, pair)
+	}
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	for _, pair := range sorted {
+		h.Write([]byte(pair))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// updateVersion returns the version of the snapshot of a user with the given hash, and stores a new version if the
+// hash changed.
+func (s *Service) updateVersion(ctx context.Context, userID int64, hash string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strconv.FormatInt(userID, 10)
+	var current version
+	value, ok, err := s.versions.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		if err := json.Unmarshal([]byte(value), &current); err != nil {
+			return 0, err
+		}
+		if current.Hash == hash {
+			return current.Version, nil
+		}
+	}
+
+	next := version{Version: current.Version + 1, Hash: hash}
+	b, err := json.Marshal(next)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.versions.Set(ctx, key, string(b)); err != nil {
+		return 0, err
+	}
+	// there is nothing to invalidate if the snapshot of the user was never fetched
+	if ok {
+		s.notify(Change{UserID: userID, Version: next.Version, PreviousVersion: current.Version, Hash: hash})
+	}
+	return next.Version, nil
+}
+
+// Sign returns the HMAC-SHA256 of the body, in hex.
+func (s *Service) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// notify sends the change to the webhook in the background.
+func (s *Service) notify(change Change) {
+	if s.IsDisabled() {
+		return
+	}
+	body, err := json.Marshal(change)
+	if err != nil {
+		s.log.Error("failed to encode authorization snapshot change", "userId", change.UserID, "err", err)
+		return
+	}
+	go func() {
+		if err := s.post(body); err != nil {
+			s.log.Error("failed to call authorization snapshot webhook", "userId", change.UserID, "version", change.Version, "err", err)
+		}
+	}()
+}
+
+func (s *Service) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Grafana")
+	req.Header.Set(SignatureHeader, s.Sign(body))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.log.Warn("failed to close response body", "err", err)
+		}
+	}()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package authzsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestGetSnapshot(t *testing.T) {
+	ctx := context.Background()
+	db := sqlstore.InitTestDB(t)
+	alice, err := db.CreateUser(ctx, user.CreateUserCommand{Login: "alice"})
+	require.NoError(t, err)
+	team, err := db.CreateTeam("operators", "", alice.OrgID)
+	require.NoError(t, err)
+
+	type request struct {
+		change    Change
+		signature string
+	}
+	requests := make(chan request, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var change Change
+		require.NoError(t, json.Unmarshal(body, &change))
+		requests <- request{change: change, signature: r.Header.Get(SignatureHeader)}
+	}))
+	t.Cleanup(webhook.Close)
+
+	permissions := []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:*"}}
+	ac := mock.New()
+	ac.GetUserPermissionsFunc = func(context.Context, *models.SignedInUser, accesscontrol.Options) ([]accesscontrol.Permission, error) {
+		return permissions, nil
+	}
+	cfg := setting.NewCfg()
+	cfg.AuthzSnapshot = setting.AuthzSnapshotSettings{Enabled: true, SigningKey: "secret", WebhookURL: webhook.URL, PollInterval: time.Minute}
+	s := ProvideService(cfg, db, ac, kvstore.ProvideService(db))
+
+	snapshot, err := s.GetSnapshot(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), snapshot.Version)
+	require.Equal(t, "alice", snapshot.Login)
+	require.Len(t, snapshot.Orgs, 1)
+	require.Equal(t, alice.OrgID, snapshot.Orgs[0].OrgID)
+	require.Equal(t, models.ROLE_ADMIN, snapshot.Orgs[0].Role)
+	require.Empty(t, snapshot.Orgs[0].Teams)
+	firstHash := snapshot.Hash
+
+	t.Run("the version should not change with the content", func(t *testing.T) {
+		permissions = []accesscontrol.Permission{permissions[0], permissions[0]}
+		snapshot, err := s.GetSnapshot(ctx, alice.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), snapshot.Version)
+		require.Equal(t, firstHash, snapshot.Hash)
+		require.Empty(t, requests)
+	})
+
+	t.Run("the version should increase and the webhook be notified when the teams change", func(t *testing.T) {
+		require.NoError(t, db.AddTeamMember(alice.ID, alice.OrgID, team.Id, false, 0))
+		snapshot, err := s.GetSnapshot(ctx, alice.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), snapshot.Version)
+		require.Equal(t, []int64{team.Id}, snapshot.Orgs[0].Teams)
+		require.NotEqual(t, firstHash, snapshot.Hash)
+
+		r := <-requests
+		require.Equal(t, Change{UserID: alice.ID, Version: 2, PreviousVersion: 1, Hash: snapshot.Hash}, r.change)
+		body, err := json.Marshal(r.change)
+		require.NoError(t, err)
+		require.Equal(t, s.Sign(body), r.signature)
+	})
+
+	t.Run("polling should detect the changes of the permissions", func(t *testing.T) {
+		permissions = append(permissions, accesscontrol.Permission{Action: "folders:read", Scope: "folders:*"})
+		s.poll(ctx)
+		r := <-requests
+		require.Equal(t, int64(3), r.change.Version)
+	})
+
+	t.Run("deleted users should be forgotten", func(t *testing.T) {
+		require.NoError(t, db.DeleteUser(ctx, &models.DeleteUserCommand{UserId: alice.ID}))
+		_, err := s.GetSnapshot(ctx, alice.ID)
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+		s.poll(ctx)
+		keys, err := s.versions.Keys(ctx, "")
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+}
+
+func TestHashPermissions(t *testing.T) {
+	read := accesscontrol.Permission{Action: "dashboards:read", Scope: "dashboards:*"}
+	write := accesscontrol.Permission{Action: "dashboards:write", Scope: "dashboards:*"}
+	require.Equal(t, hashPermissions([]accesscontrol.Permission{read, write}), hashPermissions([]accesscontrol.Permission{write, read, write}))
+	require.NotEqual(t, hashPermissions([]accesscontrol.Permission{read}), hashPermissions([]accesscontrol.Permission{read, write}))
+}
@@ -0,0 +1,181 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// batchSize is the maximum number of rows deleted by one statement, to stay below the limit of
+// bound parameters of the databases.
+const batchSize = 500
+
+var (
+	ErrNoCriteria     = errors.New("at least one criterion is required to revoke tokens")
+	ErrInvalidIPRange = errors.New("invalid IP range")
+)
+
+// Criteria select the service account tokens and user sessions to revoke. A token or session is
+// revoked only if it matches all the criteria that are set.
+type Criteria struct {
+	// CreatedBefore matches the tokens and sessions created before the time.
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+	// NeverUsed matches the tokens and sessions that have never been used to authenticate a request.
+	NeverUsed bool `json:"neverUsed,omitempty"`
+	// IPRange matches the sessions whose last client IP is in the range, in CIDR notation. Service
+	// account tokens do not record the IP of their clients, so none of them match.
+	IPRange string `json:"ipRange,omitempty"`
+	// ServiceAccountNamePrefix matches the tokens of the service accounts whose name starts with the
+	// prefix. User sessions do not belong to service accounts, so none of them match.
+	ServiceAccountNamePrefix string `json:"serviceAccountNamePrefix,omitempty"`
+}
+
+// Result is the number of service account tokens and user sessions that match the criteria, which
+// were revoked unless it was a dry run.
+type Result struct {
+	DryRun               bool  `json:"dryRun"`
+	ServiceAccountTokens int64 `json:"serviceAccountTokens"`
+	UserSessions         int64 `json:"userSessions"`
+}
+
+// Service revokes service account tokens and user sessions in bulk, for example after a suspected
+// leak of credentials.
+type Service struct {
+	sqlStore *sqlstore.SQLStore
+}
+
+func ProvideService(sqlStore *sqlstore.SQLStore) *Service {
+	return &Service{sqlStore: sqlStore}
+}
+
+// Revoke deletes the service account tokens and user sessions that match the criteria in one
+// transaction, so that either all or none of them are revoked. If dryRun is true, they are only
+// counted.
+func (s *Service) Revoke(ctx context.Context, criteria Criteria, dryRun bool) (Result, error) {
+	result := Result{DryRun: dryRun}
+	if criteria.CreatedBefore == nil && !criteria.NeverUsed && criteria.IPRange == "" && criteria.ServiceAccountNamePrefix == "" {
+		return result, ErrNoCriteria
+	}
+	var ipRange *net.IPNet
+	if criteria.IPRange != "" {
+		var err error
+		if _, ipRange, err = net.ParseCIDR(criteria.IPRange); err != nil {
+			return result, fmt.Errorf("%w %q: %s", ErrInvalidIPRange, criteria.IPRange, err)
+		}
+	}
+
+	err := s.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		tokenIDs, err := s.findServiceAccountTokens(sess, criteria)
+		if err != nil {
+			return err
+		}
+		sessionIDs, err := s.findUserSessions(sess, criteria, ipRange)
+		if err != nil {
+			return err
+		}
+		result.ServiceAccountTokens = int64(len(tokenIDs))
+		result.UserSessions = int64(len(sessionIDs))
+		if dryRun {
+			return nil
+		}
+
+		if err := deleteByID(sess, "api_key", tokenIDs); err != nil {
+			return err
+		}
+		return deleteByID(sess, "user_auth_token", sessionIDs)
+	})
+	if err != nil {
+		return Result{DryRun: dryRun}, err
+	}
+	return result, nil
+}
+
+func (s *Service) findServiceAccountTokens(sess *sqlstore.DBSession, criteria Criteria) ([]int64, error) {
+	if criteria.IPRange != "" {
+		return nil, nil
+	}
+
+	quotedUser := s.sqlStore.Dialect.Quote("user")
+	q := sess.Table("api_key").
+		Join("INNER", quotedUser, quotedUser+".id = api_key.service_account_id").
+		Cols("api_key.id", quotedUser+".name")
+	if criteria.CreatedBefore != nil {
+		q = q.Where("api_key.created < ?", *criteria.CreatedBefore)
+	}
+	if criteria.NeverUsed {
+		q = q.Where("api_key.last_used_at IS NULL")
+	}
+
+	var tokens []struct {
+		ID   int64  `xorm:"id"`
+		Name string `xorm:"name"`
+	}
+	if err := q.Find(&tokens); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(tokens))
+	for _, t := range tokens {
+		if strings.HasPrefix(t.Name, criteria.ServiceAccountNamePrefix) {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *Service) findUserSessions(sess *sqlstore.DBSession, criteria Criteria, ipRange *net.IPNet) ([]int64, error) {
+	if criteria.ServiceAccountNamePrefix != "" {
+		return nil, nil
+	}
+
+	q := sess.Table("user_auth_token").Cols("id", "client_ip")
+	if criteria.CreatedBefore != nil {
+		q = q.Where("created_at < ?", criteria.CreatedBefore.Unix())
+	}
+	if criteria.NeverUsed {
+		q = q.Where("seen_at = 0")
+	}
+
+	var sessions []struct {
+		ID       int64  `xorm:"id"`
+		ClientIP string `xorm:"client_ip"`
+	}
+	if err := q.Find(&sessions); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(sessions))
+	for _, session := range sessions {
+		if ipRange != nil {
+			ip := net.ParseIP(session.ClientIP)
+			if ip == nil || !ipRange.Contains(ip) {
+				continue
+			}
+		}
+		ids = append(ids, session.ID)
+	}
+	return ids, nil
+}
+
+func deleteByID(sess *sqlstore.DBSession, table string, ids []int64) error {
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > batchSize {
+			n = batchSize
+		}
+		params := []interface{}{"DELETE FROM " + table + " WHERE id IN (?" + strings.Repeat(",?", n-1) + ")"}
+		for _, id := range ids[:n] {
+			params = append(params, id)
+		}
+		if _, err := sess.Exec(params...); err != nil {
+			return err
+		}
+		ids = ids[n:]
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type session struct {
+	Id            int64
+	UserId        int64
+	AuthToken     string
+	PrevAuthToken string
+	UserAgent     string
+	ClientIp      string
+	AuthTokenSeen bool
+	SeenAt        int64
+	RotatedAt     int64
+	CreatedAt     int64
+	UpdatedAt     int64
+}
+
+func (session) TableName() string { return "user_auth_token" }
+
+func TestRevoke(t *testing.T) {
+	now := time.Now()
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+	yesterday := now.Add(-24 * time.Hour)
+
+	setup := func(t *testing.T) (*Service, *sqlstore.SQLStore) {
+		db := sqlstore.InitTestDB(t)
+		ctx := context.Background()
+		ci, err := db.CreateUser(ctx, user.CreateUserCommand{Login: "sa-ci-deploy", Name: "ci-deploy", IsServiceAccount: true})
+		require.NoError(t, err)
+		grafana, err := db.CreateUser(ctx, user.CreateUserCommand{Login: "sa-grafana", Name: "grafana", IsServiceAccount: true})
+		require.NoError(t, err)
+		alice, err := db.CreateUser(ctx, user.CreateUserCommand{Login: "alice"})
+		require.NoError(t, err)
+
+		err = db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+			_, err := sess.Insert(
+				&models.ApiKey{OrgId: 1, Name: "ci-old", Key: "1", Role: models.ROLE_VIEWER, Created: lastWeek, Updated: lastWeek, ServiceAccountId: &ci.ID},
+				&models.ApiKey{OrgId: 1, Name: "ci-used", Key: "2", Role: models.ROLE_VIEWER, Created: lastWeek, Updated: lastWeek, ServiceAccountId: &ci.ID, LastUsedAt: &yesterday},
+				&models.ApiKey{OrgId: 1, Name: "grafana-new", Key: "3", Role: models.ROLE_VIEWER, Created: now, Updated: now, ServiceAccountId: &grafana.ID},
+				&models.ApiKey{OrgId: 1, Name: "api-key", Key: "4", Role: models.ROLE_VIEWER, Created: lastWeek, Updated: lastWeek},
+				&session{UserId: alice.ID, AuthToken: "a", PrevAuthToken: "a", ClientIp: "10.0.0.1", CreatedAt: lastWeek.Unix(), SeenAt: yesterday.Unix()},
+				&session{UserId: alice.ID, AuthToken: "b", PrevAuthToken: "b", ClientIp: "192.168.1.10", CreatedAt: lastWeek.Unix()},
+				&session{UserId: alice.ID, AuthToken: "c", PrevAuthToken: "c", ClientIp: "10.0.0.2", CreatedAt: now.Unix()},
+			)
+			return err
+		})
+		require.NoError(t, err)
+		return ProvideService(db), db
+	}
+	count := func(t *testing.T, db *sqlstore.SQLStore, table string) int64 {
+		var n int64
+		err := db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+			var err error
+			n, err = sess.Table(table).Count()
+			return err
+		})
+		require.NoError(t, err)
+		return n
+	}
+
+	testCases := []struct {
+		desc     string
+		criteria Criteria
+		expected Result
+	}{
+		{
+			desc:     "created before",
+			criteria: Criteria{CreatedBefore: &yesterday},
+			expected: Result{ServiceAccountTokens: 2, UserSessions: 2},
+		},
+		{
+			desc:     "never used",
+			criteria: Criteria{NeverUsed: true},
+			expected: Result{ServiceAccountTokens: 2, UserSessions: 2},
+		},
+		{
+			desc:     "IP range only matches sessions",
+			criteria: Criteria{IPRange: "10.0.0.0/8"},
+			expected: Result{UserSessions: 2},
+		},
+		{
+			desc:     "service account name prefix only matches tokens",
+			criteria: Criteria{ServiceAccountNamePrefix: "ci-"},
+			expected: Result{ServiceAccountTokens: 2},
+		},
+		{
+			desc:     "all criteria must match",
+			criteria: Criteria{CreatedBefore: &yesterday, NeverUsed: true, IPRange: "192.168.0.0/16"},
+			expected: Result{UserSessions: 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, db := setup(t)
+
+			result, err := s.Revoke(context.Background(), tc.criteria, true)
+			require.NoError(t, err)
+			require.Equal(t, Result{DryRun: true, ServiceAccountTokens: tc.expected.ServiceAccountTokens, UserSessions: tc.expected.UserSessions}, result)
+			require.Equal(t, int64(4), count(t, db, "api_key"))
+			require.Equal(t, int64(3), count(t, db, "user_auth_token"))
+
+			result, err = s.Revoke(context.Background(), tc.criteria, false)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+			require.Equal(t, 4-tc.expected.ServiceAccountTokens, count(t, db, "api_key"))
+			require.Equal(t, 3-tc.expected.UserSessions, count(t, db, "user_auth_token"))
+		})
+	}
+
+	t.Run("API keys that do not belong to a service account are kept", func(t *testing.T) {
+		s, db := setup(t)
+
+		_, err := s.Revoke(context.Background(), Criteria{CreatedBefore: &now}, false)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count(t, db, "api_key"))
+	})
+
+	t.Run("invalid criteria are rejected", func(t *testing.T) {
+		s, _ := setup(t)
+
+		_, err := s.Revoke(context.Background(), Criteria{}, true)
+		require.ErrorIs(t, err, ErrNoCriteria)
+		_, err = s.Revoke(context.Background(), Criteria{IPRange: "10.0.0.1"}, true)
+		require.ErrorIs(t, err, ErrInvalidIPRange)
+	})
+}
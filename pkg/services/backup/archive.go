@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const manifestFile = "manifest.json"
+
+// maxFileSize is the largest file of an archive that is read, to not read arbitrary large files into memory.
+const maxFileSize = 1 << 30
+
+func resourceFile(resource string) string {
+	return resource + ".json"
+}
+
+// archiveWriter writes the files of a backup archive, a gzipped tarball of JSON files.
+type archiveWriter struct {
+	gz  *gzip.Writer
+	tar *tar.Writer
+}
+
+func newArchiveWriter(w io.Writer) *archiveWriter {
+	gz := gzip.NewWriter(w)
+	return &archiveWriter{gz: gz, tar: tar.NewWriter(gz)}
+}
+
+func (a *archiveWriter) writeJSON(name string, v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	if err := a.tar.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err = a.tar.Write(content)
+	return err
+}
+
+func (a *archiveWriter) Close() error {
+	if err := a.tar.Close(); err != nil {
+		return err
+	}
+	return a.gz.Close()
+}
+
+// readArchive returns the files of a backup archive by name.
+func readArchive(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrIncompatibleArchive, err.Error())
+	}
+	defer func() { _ = gz.Close() }()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrIncompatibleArchive, err.Error())
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > maxFileSize {
+			return nil, fmt.Errorf("%w: %s is too large", ErrIncompatibleArchive, header.Name)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[header.Name] = content
+	}
+}
@@ -0,0 +1,457 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashboardsDB "github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// FormatVersion is the version of the archive format. Archives of other versions cannot be restored.
+const FormatVersion = 1
+
+const (
+	ResourceOrgs        = "orgs"
+	ResourceUsers       = "users"
+	ResourceDashboards  = "dashboards"
+	ResourceDatasources = "datasources"
+	ResourceAlerting    = "alerting"
+)
+
+// Resources are the resource families that can be backed up, in the order they are restored.
+var Resources = []string{ResourceOrgs, ResourceUsers, ResourceDatasources, ResourceDashboards, ResourceAlerting}
+
+// pageSize is the number of users and dashboards that are read at once.
+const pageSize = 1000
+
+var ErrUnknownResource = errors.New("unknown resource")
+var ErrIncompatibleArchive = errors.New("incompatible backup archive")
+
+// Manifest describes the content of a backup archive.
+type Manifest struct {
+	FormatVersion  int       `json:"formatVersion"`
+	GrafanaVersion string    `json:"grafanaVersion"`
+	Created        time.Time `json:"created"`
+	Resources      []string  `json:"resources"`
+}
+
+// Org is an organization in a backup. The organizations are always part of a backup, because the other resources
+// refer to them. They are matched by name on restore.
+type Org struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// User is a user in a backup, without the password.
+type User struct {
+	Login      string    `json:"login"`
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+	IsAdmin    bool      `json:"isGrafanaAdmin"`
+	IsDisabled bool      `json:"isDisabled"`
+	Orgs       []OrgRole `json:"orgs"`
+}
+
+// OrgRole is the role of a user in an organization.
+type OrgRole struct {
+	OrgID int64           `json:"orgId"`
+	Role  models.RoleType `json:"role"`
+}
+
+// Dashboard is a dashboard or a folder in a backup.
+type Dashboard struct {
+	OrgID     int64            `json:"orgId"`
+	UID       string           `json:"uid"`
+	FolderUID string           `json:"folderUid,omitempty"`
+	IsFolder  bool             `json:"isFolder"`
+	Data      *simplejson.Json `json:"dashboard"`
+}
+
+// DataSource is a data source in a backup. The values of its secure JSON data are replaced with placeholders,
+// which are read from the environment on restore.
+type DataSource struct {
+	OrgID           int64                `json:"orgId"`
+	UID             string               `json:"uid"`
+	Name            string               `json:"name"`
+	Type            string               `json:"type"`
+	Access          datasources.DsAccess `json:"access"`
+	URL             string               `json:"url"`
+	User            string               `json:"user"`
+	Database        string               `json:"database"`
+	BasicAuth       bool                 `json:"basicAuth"`
+	BasicAuthUser   string               `json:"basicAuthUser"`
+	WithCredentials bool                 `json:"withCredentials"`
+	IsDefault       bool                 `json:"isDefault"`
+	JSONData        *simplejson.Json     `json:"jsonData"`
+	SecureJSONData  map[string]string    `json:"secureJsonData,omitempty"`
+}
+
+// Alerting is the alerting configuration of an organization in a backup. The secure settings of the contact points
+// are replaced with placeholders, which are read from the environment on restore.
+type Alerting struct {
+	OrgID                     int64                           `json:"orgId"`
+	AlertmanagerConfiguration *definitions.PostableUserConfig `json:"alertmanagerConfiguration,omitempty"`
+	Rules                     []ngmodels.AlertRule            `json:"rules"`
+}
+
+// Service backs up resources to an archive and restores them. It reads the resources with the same stores the
+// services of a running instance use, so it can work across database types. Dashboards, data sources and alert
+// rules are restored through their services, which validate them and set their default permissions.
+type Service struct {
+	log               log.Logger
+	cfg               *setting.Cfg
+	sqlStore          *sqlstore.SQLStore
+	dashboardStore    *dashboardsDB.DashboardStore
+	alertingStore     *ngstore.DBstore
+	secrets           secrets.Service
+	dashboardService  dashboards.DashboardService
+	dataSourceService datasources.DataSourceService
+	alertRuleService  *provisioning.AlertRuleService
+
+	lookupEnv func(string) (string, bool)
+	now       func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, secretsService secrets.Service,
+	dashboardService dashboards.DashboardService, dataSourceService datasources.DataSourceService,
+	alertRuleService *provisioning.AlertRuleService) *Service {
+	logger := log.New("backup")
+	return &Service{
+		log:            logger,
+		cfg:            cfg,
+		sqlStore:       sqlStore,
+		dashboardStore: dashboardsDB.ProvideDashboardStore(sqlStore),
+		alertingStore: &ngstore.DBstore{
+			SQLStore:        sqlStore,
+			BaseInterval:    cfg.UnifiedAlerting.BaseInterval,
+			DefaultInterval: cfg.UnifiedAlerting.DefaultRuleEvaluationInterval,
+			Logger:          logger,
+		},
+		secrets:           secretsService,
+		dashboardService:  dashboardService,
+		dataSourceService: dataSourceService,
+		alertRuleService:  alertRuleService,
+		lookupEnv:         os.LookupEnv,
+		now:               time.Now,
+	}
+}
+
+// ParseResources returns the resource families in a comma separated list, or all of them if the list is empty.
+func ParseResources(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return Resources, nil
+	}
+	selected := map[string]bool{}
+	for _, r := range strings.Split(list, ",") {
+		r = strings.TrimSpace(r)
+		if !isResource(r) {
+			return nil, fmt.Errorf("%w: %s, the resources are %s", ErrUnknownResource, r, strings.Join(Resources, ", "))
+		}
+		selected[r] = true
+	}
+	result := make([]string, 0, len(selected))
+	for _, r := range Resources {
+		if selected[r] {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func isResource(name string) bool {
+	for _, r := range Resources {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Backup writes the selected resource families of all organizations to w as a backup archive.
+func (s *Service) Backup(ctx context.Context, w io.Writer, resources []string) (Manifest, error) {
+	manifest := Manifest{
+		FormatVersion:  FormatVersion,
+		GrafanaVersion: s.cfg.BuildVersion,
+		Created:        s.now().UTC(),
+		Resources:      resources,
+	}
+
+	orgs, err := s.backupOrgs(ctx)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to back up organizations: %w", err)
+	}
+
+	archive := newArchiveWriter(w)
+	if err := archive.writeJSON(manifestFile, manifest); err != nil {
+		return Manifest{}, err
+	}
+	if err := archive.writeJSON(resourceFile(ResourceOrgs), orgs); err != nil {
+		return Manifest{}, err
+	}
+
+	for _, r := range resources {
+		var content interface{}
+		var err error
+		switch r {
+		case ResourceOrgs:
+			continue
+		case ResourceUsers:
+			content, err = s.backupUsers(ctx)
+		case ResourceDashboards:
+			content, err = s.backupDashboards(ctx, orgs)
+		case ResourceDatasources:
+			content, err = s.backupDataSources(ctx, orgs)
+		case ResourceAlerting:
+			content, err = s.backupAlerting(ctx, orgs)
+		default:
+			err = fmt.Errorf("%w: %s", ErrUnknownResource, r)
+		}
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to back up %s: %w", r, err)
+		}
+		if err := archive.writeJSON(resourceFile(r), content); err != nil {
+			return Manifest{}, err
+		}
+		s.log.Info("Backed up resources", "resource", r)
+	}
+
+	if err := archive.Close(); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+func (s *Service) backupOrgs(ctx context.Context) ([]Org, error) {
+	query := models.SearchOrgsQuery{}
+	if err := s.sqlStore.SearchOrgs(ctx, &query); err != nil {
+		return nil, err
+	}
+	orgs := make([]Org, 0, len(query.Result))
+	for _, o := range query.Result {
+		orgs = append(orgs, Org{ID: o.Id, Name: o.Name})
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].ID < orgs[j].ID })
+	return orgs, nil
+}
+
+func (s *Service) backupUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	for page := 1; ; page++ {
+		query := models.SearchUsersQuery{SignedInUser: backupUser(ac.GlobalOrgID), Page: page, Limit: pageSize}
+		if err := s.sqlStore.SearchUsers(ctx, &query); err != nil {
+			return nil, err
+		}
+		for _, hit := range query.Result.Users {
+			orgsQuery := models.GetUserOrgListQuery{UserId: hit.Id}
+			if err := s.sqlStore.GetUserOrgList(ctx, &orgsQuery); err != nil {
+				return nil, err
+			}
+			u := User{
+				Login:      hit.Login,
+				Email:      hit.Email,
+				Name:       hit.Name,
+				IsAdmin:    hit.IsAdmin,
+				IsDisabled: hit.IsDisabled,
+				Orgs:       make([]OrgRole, 0, len(orgsQuery.Result)),
+			}
+			for _, o := range orgsQuery.Result {
+				u.Orgs = append(u.Orgs, OrgRole{OrgID: o.OrgId, Role: o.Role})
+			}
+			users = append(users, u)
+		}
+		if len(query.Result.Users) < pageSize {
+			return users, nil
+		}
+	}
+}
+
+func (s *Service) backupDashboards(ctx context.Context, orgs []Org) ([]Dashboard, error) {
+	var result []Dashboard
+	for _, org := range orgs {
+		// folders go first, so they exist when the dashboards in them are restored
+		for _, hitType := range []models.HitType{models.DashHitFolder, models.DashHitDB} {
+			for page := int64(1); ; page++ {
+				hits, err := s.dashboardStore.FindDashboards(ctx, &models.FindPersistedDashboardsQuery{
+					OrgId:        org.ID,
+					SignedInUser: backupUser(org.ID),
+					Type:         string(hitType),
+					Permission:   models.PERMISSION_VIEW,
+					Limit:        pageSize,
+					Page:         page,
+				})
+				if err != nil {
+					return nil, err
+				}
+				dashboards, err := s.dashboardsOfHits(ctx, org.ID, hits)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, dashboards...)
+				if len(hits) < pageSize {
+					break
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) dashboardsOfHits(ctx context.Context, orgID int64, hits []dashboards.DashboardSearchProjection) ([]Dashboard, error) {
+	if len(hits) == 0 {
+		return nil, nil
+	}
+	folderUIDs := make(map[string]string, len(hits))
+	uids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		folderUIDs[hit.UID] = hit.FolderUID
+		uids = append(uids, hit.UID)
+	}
+
+	query := models.GetDashboardsQuery{DashboardUIds: uids}
+	if err := s.dashboardStore.GetDashboards(ctx, &query); err != nil {
+		return nil, err
+	}
+	result := make([]Dashboard, 0, len(query.Result))
+	for _, d := range query.Result {
+		// the UIDs are only unique in an organization
+		if d.OrgId != orgID {
+			continue
+		}
+		data := d.Data
+		data.Del("id")
+		result = append(result, Dashboard{
+			OrgID:     orgID,
+			UID:       d.Uid,
+			FolderUID: folderUIDs[d.Uid],
+			IsFolder:  d.IsFolder,
+			Data:      data,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UID < result[j].UID })
+	return result, nil
+}
+
+func (s *Service) backupDataSources(ctx context.Context, orgs []Org) ([]DataSource, error) {
+	var result []DataSource
+	for _, org := range orgs {
+		query := datasources.GetDataSourcesQuery{OrgId: org.ID}
+		if err := s.sqlStore.GetDataSources(ctx, &query); err != nil {
+			return nil, err
+		}
+		for _, ds := range query.Result {
+			b := DataSource{
+				OrgID:           org.ID,
+				UID:             ds.Uid,
+				Name:            ds.Name,
+				Type:            ds.Type,
+				Access:          ds.Access,
+				URL:             ds.Url,
+				User:            ds.User,
+				Database:        ds.Database,
+				BasicAuth:       ds.BasicAuth,
+				BasicAuthUser:   ds.BasicAuthUser,
+				WithCredentials: ds.WithCredentials,
+				IsDefault:       ds.IsDefault,
+				JSONData:        ds.JsonData,
+			}
+			if len(ds.SecureJsonData) > 0 {
+				b.SecureJSONData = make(map[string]string, len(ds.SecureJsonData))
+				for key := range ds.SecureJsonData {
+					b.SecureJSONData[key] = secretPlaceholder("DS", ds.Uid, key)
+				}
+			}
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) backupAlerting(ctx context.Context, orgs []Org) ([]Alerting, error) {
+	var result []Alerting
+	for _, org := range orgs {
+		a := Alerting{OrgID: org.ID}
+
+		amQuery := ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: org.ID}
+		err := s.alertingStore.GetLatestAlertmanagerConfiguration(ctx, &amQuery)
+		if err != nil && !errors.Is(err, ngstore.ErrNoAlertmanagerConfiguration) {
+			return nil, err
+		}
+		// the default configuration is created again by the instance the backup is restored to
+		if err == nil && !amQuery.Result.Default {
+			cfg := definitions.PostableUserConfig{}
+			if err := json.Unmarshal([]byte(amQuery.Result.AlertmanagerConfiguration), &cfg); err != nil {
+				return nil, fmt.Errorf("failed to parse the Alertmanager configuration of org %d: %w", org.ID, err)
+			}
+			for _, r := range cfg.AlertmanagerConfig.Receivers {
+				for _, gr := range r.GrafanaManagedReceivers {
+					for key := range gr.SecureSettings {
+						gr.SecureSettings[key] = secretPlaceholder("ALERTING", gr.UID, key)
+					}
+				}
+			}
+			a.AlertmanagerConfiguration = &cfg
+		}
+
+		rulesQuery := ngmodels.ListAlertRulesQuery{OrgID: org.ID}
+		if err := s.alertingStore.ListAlertRules(ctx, &rulesQuery); err != nil {
+			return nil, err
+		}
+		a.Rules = make([]ngmodels.AlertRule, 0, len(rulesQuery.Result))
+		for _, r := range rulesQuery.Result {
+			rule := *r
+			rule.ID = 0
+			a.Rules = append(a.Rules, rule)
+		}
+
+		if a.AlertmanagerConfiguration != nil || len(a.Rules) > 0 {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+// backupUser is the user the resources are read as, it can read all of them.
+func backupUser(orgID int64) *models.SignedInUser {
+	return &models.SignedInUser{
+		OrgId:          orgID,
+		OrgRole:        models.ROLE_ADMIN,
+		IsGrafanaAdmin: true,
+		Permissions: map[int64]map[string][]string{
+			orgID: {
+				ac.ActionUsersRead:              {ac.ScopeGlobalUsersAll},
+				dashboards.ActionFoldersRead:    {dashboards.ScopeFoldersAll},
+				dashboards.ActionDashboardsRead: {dashboards.ScopeDashboardsAll},
+			},
+		},
+	}
+}
+
+var placeholderRegex = regexp.MustCompile(`^\$\{([A-Z0-9_]+)\}$`)
+var nonPlaceholderChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// secretPlaceholder returns the placeholder of a secret, which references an environment variable.
+func secretPlaceholder(parts ...string) string {
+	name := nonPlaceholderChars.ReplaceAllString(strings.ToUpper(strings.Join(parts, "_")), "_")
+	return "${GF_BACKUP_" + strings.Trim(name, "_") + "}"
+}
@@ -0,0 +1,232 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/ossaccesscontrol"
+	dashboardsDB "github.com/grafana/grafana/pkg/services/dashboards/database"
+	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards/service"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datasourceservice "github.com/grafana/grafana/pkg/services/datasources/service"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	secretsDatabase "github.com/grafana/grafana/pkg/services/secrets/database"
+	secretskvstore "github.com/grafana/grafana/pkg/services/secrets/kvstore"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testAlertmanagerConfig = `{
+	"alertmanager_config": {
+		"route": {"receiver": "pager"},
+		"receivers": [{
+			"name": "pager",
+			"grafana_managed_receiver_configs": [{
+				"uid": "pager-uid",
+				"name": "pager",
+				"type": "pagerduty",
+				"settings": {},
+				"secureSettings": {"integrationKey": "secret-key"}
+			}]
+		}]
+	}
+}`
+
+func TestParseResources(t *testing.T) {
+	resources, err := ParseResources("")
+	require.NoError(t, err)
+	require.Equal(t, Resources, resources)
+
+	resources, err = ParseResources("alerting, orgs,dashboards")
+	require.NoError(t, err)
+	require.Equal(t, []string{ResourceOrgs, ResourceDashboards, ResourceAlerting}, resources)
+
+	_, err = ParseResources("orgs,teams")
+	require.ErrorIs(t, err, ErrUnknownResource)
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestService(t)
+
+	admin, err := s.sqlStore.CreateUser(ctx, user.CreateUserCommand{Login: "admin", IsAdmin: true})
+	require.NoError(t, err)
+	org, err := s.sqlStore.CreateOrgWithMember("team", admin.ID)
+	require.NoError(t, err)
+	jdoe, err := s.sqlStore.CreateUser(ctx, user.CreateUserCommand{Login: "jdoe", Email: "jdoe@example.com", Password: "secret", SkipOrgSetup: true})
+	require.NoError(t, err)
+	require.NoError(t, s.sqlStore.AddOrgUser(ctx, &models.AddOrgUserCommand{OrgId: org.Id, UserId: jdoe.ID, Role: models.ROLE_EDITOR}))
+
+	encrypted, err := s.secrets.EncryptJsonData(ctx, map[string]string{"password": "ds-secret"}, secrets.WithoutScope())
+	require.NoError(t, err)
+	require.NoError(t, s.sqlStore.AddDataSource(ctx, &datasources.AddDataSourceCommand{
+		OrgId: org.Id, Uid: "prom-uid", Name: "Prometheus", Type: "prometheus", Access: datasources.DS_ACCESS_PROXY,
+		EncryptedSecureJsonData: encrypted,
+	}))
+
+	folder, err := s.dashboardStore.SaveDashboard(models.SaveDashboardCommand{
+		OrgId: org.Id, IsFolder: true, Dashboard: simplejson.NewFromAny(map[string]interface{}{"uid": "folder-uid", "title": "Team"}),
+	})
+	require.NoError(t, err)
+	_, err = s.dashboardStore.SaveDashboard(models.SaveDashboardCommand{
+		OrgId: org.Id, FolderId: folder.Id, Dashboard: simplejson.NewFromAny(map[string]interface{}{"uid": "dash-uid", "title": "Overview"}),
+	})
+	require.NoError(t, err)
+
+	amConfig := definitions.PostableUserConfig{}
+	require.NoError(t, json.Unmarshal([]byte(testAlertmanagerConfig), &amConfig))
+	require.NoError(t, amConfig.ProcessConfig(s.secrets.Encrypt))
+	raw, err := json.Marshal(amConfig)
+	require.NoError(t, err)
+	require.NoError(t, s.alertingStore.SaveAlertmanagerConfiguration(ctx, &ngmodels.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(raw), ConfigurationVersion: "v1", OrgID: org.Id,
+	}))
+	rule := ngmodels.AlertRuleGen(func(r *ngmodels.AlertRule) {
+		r.OrgID = org.Id
+		r.NamespaceUID = "folder-uid"
+		r.IntervalSeconds = 60
+		r.DashboardUID = nil
+		r.PanelID = nil
+		r.Data[0].DatasourceUID = "prom-uid"
+	})()
+	_, err = s.alertingStore.InsertAlertRules(ctx, []ngmodels.AlertRule{*rule})
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	manifest, err := s.Backup(ctx, &archive, Resources)
+	require.NoError(t, err)
+	require.Equal(t, FormatVersion, manifest.FormatVersion)
+
+	files, err := readArchive(bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.NotContains(t, string(files["users.json"]), "password")
+	require.Contains(t, string(files["datasources.json"]), "${GF_BACKUP_DS_PROM_UID_PASSWORD}")
+	require.NotContains(t, string(files["datasources.json"]), "ds-secret")
+	require.Contains(t, string(files["alerting.json"]), "${GF_BACKUP_ALERTING_PAGER_UID_INTEGRATIONKEY}")
+
+	// restore into an empty instance
+	target := setupTestService(t)
+	_, err = target.sqlStore.CreateUser(ctx, user.CreateUserCommand{Login: "admin", IsAdmin: true})
+	require.NoError(t, err)
+	target.lookupEnv = func(name string) (string, bool) {
+		if name == "GF_BACKUP_DS_PROM_UID_PASSWORD" {
+			return "restored-secret", true
+		}
+		return "", false
+	}
+
+	_, summary, err := target.Restore(ctx, bytes.NewReader(archive.Bytes()), Resources)
+	require.NoError(t, err)
+	require.Equal(t, ResourceSummary{Restored: 1, Skipped: 1}, *summary[ResourceOrgs])
+	require.Equal(t, ResourceSummary{Restored: 1, Skipped: 1}, *summary[ResourceUsers])
+	require.Equal(t, ResourceSummary{Restored: 1}, *summary[ResourceDatasources])
+	require.Equal(t, ResourceSummary{Restored: 2}, *summary[ResourceDashboards])
+	require.Equal(t, ResourceSummary{Restored: 2}, *summary[ResourceAlerting])
+
+	restoredOrg, err := target.sqlStore.GetOrgByName("team")
+	require.NoError(t, err)
+
+	userQuery := models.GetUserByLoginQuery{LoginOrEmail: "jdoe"}
+	require.NoError(t, target.sqlStore.GetUserByLogin(ctx, &userQuery))
+	require.Empty(t, userQuery.Result.Password)
+	orgsQuery := models.GetUserOrgListQuery{UserId: userQuery.Result.ID}
+	require.NoError(t, target.sqlStore.GetUserOrgList(ctx, &orgsQuery))
+	require.Len(t, orgsQuery.Result, 1)
+	require.Equal(t, restoredOrg.Id, orgsQuery.Result[0].OrgId)
+
+	dsQuery := datasources.GetDataSourceQuery{OrgId: restoredOrg.Id, Uid: "prom-uid"}
+	require.NoError(t, target.sqlStore.GetDataSource(ctx, &dsQuery))
+	decrypted, err := target.secrets.DecryptJsonData(ctx, dsQuery.Result.SecureJsonData)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"password": "restored-secret"}, decrypted)
+
+	dash, err := target.dashboardStore.GetDashboard(ctx, &models.GetDashboardQuery{OrgId: restoredOrg.Id, Uid: "dash-uid"})
+	require.NoError(t, err)
+	restoredFolder, err := target.dashboardStore.GetFolderByUID(ctx, restoredOrg.Id, "folder-uid")
+	require.NoError(t, err)
+	require.Equal(t, restoredFolder.Id, dash.FolderId)
+
+	rulesQuery := ngmodels.ListAlertRulesQuery{OrgID: restoredOrg.Id}
+	require.NoError(t, target.alertingStore.ListAlertRules(ctx, &rulesQuery))
+	require.Len(t, rulesQuery.Result, 1)
+	require.Equal(t, rule.UID, rulesQuery.Result[0].UID)
+	require.Equal(t, rule.IntervalSeconds, rulesQuery.Result[0].IntervalSeconds)
+
+	t.Run("restoring again skips the existing resources", func(t *testing.T) {
+		_, summary, err := target.Restore(ctx, bytes.NewReader(archive.Bytes()), Resources)
+		require.NoError(t, err)
+		for _, r := range Resources {
+			require.Zero(t, summary[r].Restored, r)
+		}
+	})
+
+	t.Run("archives of other format versions are rejected", func(t *testing.T) {
+		var other bytes.Buffer
+		w := newArchiveWriter(&other)
+		require.NoError(t, w.writeJSON(manifestFile, Manifest{FormatVersion: FormatVersion + 1}))
+		require.NoError(t, w.Close())
+
+		_, _, err := target.Restore(ctx, &other, Resources)
+		require.ErrorIs(t, err, ErrIncompatibleArchive)
+	})
+}
+
+func setupTestService(t *testing.T) *Service {
+	t.Helper()
+	sqlStore := sqlstore.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.AdminUser = "admin"
+	cfg.UnifiedAlerting.BaseInterval = 10 * time.Second
+	cfg.UnifiedAlerting.DefaultRuleEvaluationInterval = time.Minute
+	secretsService := secretsManager.SetupTestService(t, secretsDatabase.ProvideSecretsStore(sqlStore))
+
+	features := featuremgmt.WithFeatures()
+	ac := acmock.New()
+	dashboardService := dashboardservice.ProvideDashboardService(cfg, dashboardsDB.ProvideDashboardStore(sqlStore), nil, features,
+		acmock.NewMockedPermissionsService(), acmock.NewMockedPermissionsService(), ac)
+	guardian.InitLegacyGuardian(sqlStore, dashboardService)
+	secretsStore := secretskvstore.ProvideService(sqlStore, secretsService, secretskvstore.ProvideRemotePluginCheck())
+	dataSourceService := datasourceservice.ProvideService(sqlStore, secretsService, secretsStore, cfg, features, ac, ossaccesscontrol.ProvideDatasourcePermissionsService())
+	logger := log.NewNopLogger()
+	ruleStore := &ngstore.DBstore{
+		SQLStore:        sqlStore,
+		BaseInterval:    cfg.UnifiedAlerting.BaseInterval,
+		DefaultInterval: cfg.UnifiedAlerting.DefaultRuleEvaluationInterval,
+		Logger:          logger,
+	}
+	alertRuleService := provisioning.NewAlertRuleService(ruleStore, ruleStore, ruleStore, quota.ProvideService(cfg, nil, sqlStore),
+		lint.NewService(kvstore.ProvideService(sqlStore), logger), ruleStore, nil, dataSourceService, fakePluginReader{},
+		dashboardService, cfg.UnifiedAlerting, nil, logger)
+	return ProvideService(cfg, sqlStore, secretsService, dashboardService, dataSourceService, alertRuleService)
+}
+
+// fakePluginReader has the Prometheus plugin installed.
+type fakePluginReader struct{}
+
+func (fakePluginReader) Plugin(ctx context.Context, pluginID string) (plugins.PluginDTO, bool) {
+	if pluginID != "prometheus" {
+		return plugins.PluginDTO{}, false
+	}
+	return plugins.PluginDTO{JSONData: plugins.JSONData{ID: pluginID, Backend: true, Alerting: true}}, true
+}
@@ -0,0 +1,476 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Summary is the number of restored and skipped resources of each resource family.
+type Summary map[string]*ResourceSummary
+
+type ResourceSummary struct {
+	Restored int `json:"restored"`
+	Skipped  int `json:"skipped"`
+}
+
+func (s Summary) restored(resource string) {
+	s.get(resource).Restored++
+}
+
+func (s Summary) skipped(resource string) {
+	s.get(resource).Skipped++
+}
+
+func (s Summary) get(resource string) *ResourceSummary {
+	if s[resource] == nil {
+		s[resource] = &ResourceSummary{}
+	}
+	return s[resource]
+}
+
+// Restore restores the selected resource families of a backup archive. Restoring never changes or deletes
+// existing resources: organizations are matched by name, users by login and the other resources by UID, and the
+// resources that already exist are skipped. The Alertmanager configuration of an organization is only restored
+// if the organization still has the default configuration.
+func (s *Service) Restore(ctx context.Context, r io.Reader, resources []string) (Manifest, Summary, error) {
+	files, err := readArchive(r)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+
+	manifest := Manifest{}
+	if err := decodeFile(files, manifestFile, &manifest); err != nil {
+		return Manifest{}, nil, err
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return Manifest{}, nil, fmt.Errorf("%w: the archive has format version %d, supported is version %d", ErrIncompatibleArchive, manifest.FormatVersion, FormatVersion)
+	}
+	inArchive := map[string]bool{ResourceOrgs: true}
+	for _, r := range manifest.Resources {
+		inArchive[r] = true
+	}
+
+	var orgs []Org
+	if err := decodeFile(files, resourceFile(ResourceOrgs), &orgs); err != nil {
+		return Manifest{}, nil, err
+	}
+
+	summary := Summary{}
+	orgIDs, err := s.restoreOrgs(ctx, orgs, contains(resources, ResourceOrgs), summary)
+	if err != nil {
+		return Manifest{}, nil, fmt.Errorf("failed to restore organizations: %w", err)
+	}
+
+	for _, r := range resources {
+		if r == ResourceOrgs {
+			continue
+		}
+		if !inArchive[r] {
+			s.log.Warn("Resource is not part of the backup", "resource", r)
+			continue
+		}
+
+		var err error
+		switch r {
+		case ResourceUsers:
+			var users []User
+			if err = decodeFile(files, resourceFile(r), &users); err == nil {
+				err = s.restoreUsers(ctx, users, orgIDs, summary)
+			}
+		case ResourceDatasources:
+			var dataSources []DataSource
+			if err = decodeFile(files, resourceFile(r), &dataSources); err == nil {
+				err = s.restoreDataSources(ctx, dataSources, orgIDs, summary)
+			}
+		case ResourceDashboards:
+			var dashboards []Dashboard
+			if err = decodeFile(files, resourceFile(r), &dashboards); err == nil {
+				err = s.restoreDashboards(ctx, dashboards, orgIDs, summary)
+			}
+		case ResourceAlerting:
+			var alerting []Alerting
+			if err = decodeFile(files, resourceFile(r), &alerting); err == nil {
+				err = s.restoreAlerting(ctx, alerting, orgIDs, summary)
+			}
+		default:
+			err = fmt.Errorf("%w: %s", ErrUnknownResource, r)
+		}
+		if err != nil {
+			return Manifest{}, nil, fmt.Errorf("failed to restore %s: %w", r, err)
+		}
+		s.log.Info("Restored resources", "resource", r, "restored", summary.get(r).Restored, "skipped", summary.get(r).Skipped)
+	}
+	return manifest, summary, nil
+}
+
+// restoreOrgs returns the IDs of the organizations of the archive in this instance by their IDs in the archive.
+// Missing organizations are created if create is set, otherwise the resources of them are not restored.
+func (s *Service) restoreOrgs(ctx context.Context, orgs []Org, create bool, summary Summary) (map[int64]int64, error) {
+	orgIDs := make(map[int64]int64, len(orgs))
+	for _, o := range orgs {
+		existing, err := s.sqlStore.GetOrgByName(o.Name)
+		if err == nil {
+			orgIDs[o.ID] = existing.Id
+			if create {
+				summary.skipped(ResourceOrgs)
+			}
+			continue
+		}
+		if !errors.Is(err, models.ErrOrgNotFound) {
+			return nil, err
+		}
+		if !create {
+			s.log.Warn("Organization does not exist, its resources are not restored", "org", o.Name)
+			continue
+		}
+
+		adminID, err := s.adminUserID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		org, err := s.sqlStore.CreateOrgWithMember(o.Name, adminID)
+		if err != nil {
+			return nil, err
+		}
+		orgIDs[o.ID] = org.Id
+		summary.restored(ResourceOrgs)
+	}
+	return orgIDs, nil
+}
+
+// adminUserID returns the ID of the admin user of the configuration, which becomes an admin of the
+// organizations created on restore, like the user creating an organization in the UI.
+func (s *Service) adminUserID(ctx context.Context) (int64, error) {
+	query := models.GetUserByLoginQuery{LoginOrEmail: s.cfg.AdminUser}
+	if err := s.sqlStore.GetUserByLogin(ctx, &query); err != nil {
+		return 0, fmt.Errorf("failed to get the admin user %s: %w", s.cfg.AdminUser, err)
+	}
+	return query.Result.ID, nil
+}
+
+func (s *Service) restoreUsers(ctx context.Context, users []User, orgIDs map[int64]int64, summary Summary) error {
+	for _, u := range users {
+		query := models.GetUserByLoginQuery{LoginOrEmail: u.Login}
+		err := s.sqlStore.GetUserByLogin(ctx, &query)
+		if err == nil {
+			summary.skipped(ResourceUsers)
+			continue
+		}
+		if !errors.Is(err, models.ErrUserNotFound) {
+			return err
+		}
+
+		created, err := s.sqlStore.CreateUser(ctx, user.CreateUserCommand{
+			Login:        u.Login,
+			Email:        u.Email,
+			Name:         u.Name,
+			IsAdmin:      u.IsAdmin,
+			IsDisabled:   u.IsDisabled,
+			SkipOrgSetup: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create user %s: %w", u.Login, err)
+		}
+		for _, o := range u.Orgs {
+			orgID, ok := orgIDs[o.OrgID]
+			if !ok {
+				continue
+			}
+			if err := s.sqlStore.AddOrgUser(ctx, &models.AddOrgUserCommand{
+				LoginOrEmail: u.Login,
+				Role:         o.Role,
+				OrgId:        orgID,
+				UserId:       created.ID,
+			}); err != nil {
+				return fmt.Errorf("failed to add user %s to org %d: %w", u.Login, orgID, err)
+			}
+		}
+		summary.restored(ResourceUsers)
+	}
+	return nil
+}
+
+func (s *Service) restoreDataSources(ctx context.Context, dataSources []DataSource, orgIDs map[int64]int64, summary Summary) error {
+	for _, ds := range dataSources {
+		orgID, ok := orgIDs[ds.OrgID]
+		if !ok {
+			summary.skipped(ResourceDatasources)
+			continue
+		}
+		err := s.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{OrgId: orgID, Uid: ds.UID})
+		if err == nil {
+			summary.skipped(ResourceDatasources)
+			continue
+		}
+		if !errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return err
+		}
+
+		cmd := datasources.AddDataSourceCommand{
+			OrgId:           orgID,
+			Uid:             ds.UID,
+			Name:            ds.Name,
+			Type:            ds.Type,
+			Access:          ds.Access,
+			Url:             ds.URL,
+			User:            ds.User,
+			Database:        ds.Database,
+			BasicAuth:       ds.BasicAuth,
+			BasicAuthUser:   ds.BasicAuthUser,
+			WithCredentials: ds.WithCredentials,
+			IsDefault:       ds.IsDefault,
+			JsonData:        ds.JSONData,
+			SecureJsonData:  s.expandSecrets(ds.SecureJSONData, "datasource", ds.Name),
+		}
+		if err := s.dataSourceService.AddDataSource(ctx, &cmd); err != nil {
+			if errors.Is(err, datasources.ErrDataSourceNameExists) {
+				s.log.Warn("Another data source has the name of the data source", "datasource", ds.Name, "uid", ds.UID, "orgId", orgID)
+				summary.skipped(ResourceDatasources)
+				continue
+			}
+			return fmt.Errorf("failed to add data source %s: %w", ds.Name, err)
+		}
+		summary.restored(ResourceDatasources)
+	}
+	return nil
+}
+
+func (s *Service) restoreDashboards(ctx context.Context, dashboardList []Dashboard, orgIDs map[int64]int64, summary Summary) error {
+	adminID, err := s.adminUserID(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range dashboardList {
+		orgID, ok := orgIDs[d.OrgID]
+		if !ok {
+			summary.skipped(ResourceDashboards)
+			continue
+		}
+		err := s.dashboardService.GetDashboard(ctx, &models.GetDashboardQuery{OrgId: orgID, Uid: d.UID})
+		if err == nil {
+			summary.skipped(ResourceDashboards)
+			continue
+		}
+		if !errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return err
+		}
+
+		var folderID int64
+		if d.FolderUID != "" {
+			folder, err := s.getFolder(ctx, orgID, d.FolderUID)
+			if err != nil {
+				s.log.Warn("Folder of the dashboard does not exist", "dashboard", d.UID, "folder", d.FolderUID, "orgId", orgID, "error", err)
+				summary.skipped(ResourceDashboards)
+				continue
+			}
+			folderID = folder.Id
+		}
+
+		d.Data.Del("id")
+		d.Data.Set("uid", d.UID)
+		dash := models.NewDashboardFromJson(d.Data)
+		dash.FolderId = folderID
+		dash.IsFolder = d.IsFolder
+		// the dashboards are restored on behalf of the admin user, like the organizations
+		_, err = s.dashboardService.ImportDashboard(ctx, &dashboards.SaveDashboardDTO{
+			OrgId:     orgID,
+			Dashboard: dash,
+			Message:   "Restored from backup",
+			User:      &models.SignedInUser{UserId: adminID, OrgId: orgID, OrgRole: models.ROLE_ADMIN, IsGrafanaAdmin: true},
+		})
+		var dashErr dashboards.DashboardErr
+		if errors.As(err, &dashErr) {
+			s.log.Warn("Dashboard cannot be restored", "dashboard", d.UID, "orgId", orgID, "error", err)
+			summary.skipped(ResourceDashboards)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save dashboard %s: %w", d.UID, err)
+		}
+		summary.restored(ResourceDashboards)
+	}
+	return nil
+}
+
+// getFolder returns the folder of an organization with the given UID.
+func (s *Service) getFolder(ctx context.Context, orgID int64, uid string) (*models.Dashboard, error) {
+	query := models.GetDashboardQuery{OrgId: orgID, Uid: uid}
+	if err := s.dashboardService.GetDashboard(ctx, &query); err != nil {
+		return nil, err
+	}
+	if !query.Result.IsFolder {
+		return nil, dashboards.ErrFolderNotFound
+	}
+	return query.Result, nil
+}
+
+func (s *Service) restoreAlerting(ctx context.Context, alerting []Alerting, orgIDs map[int64]int64, summary Summary) error {
+	for _, a := range alerting {
+		orgID, ok := orgIDs[a.OrgID]
+		if !ok {
+			summary.skipped(ResourceAlerting)
+			continue
+		}
+		if a.AlertmanagerConfiguration != nil {
+			if err := s.restoreAlertmanagerConfiguration(ctx, orgID, a, summary); err != nil {
+				return err
+			}
+		}
+		if err := s.restoreAlertRules(ctx, orgID, a.Rules, summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) restoreAlertmanagerConfiguration(ctx context.Context, orgID int64, a Alerting, summary Summary) error {
+	query := ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID}
+	err := s.alertingStore.GetLatestAlertmanagerConfiguration(ctx, &query)
+	if err != nil && !errors.Is(err, ngstore.ErrNoAlertmanagerConfiguration) {
+		return err
+	}
+	if err == nil && !query.Result.Default {
+		s.log.Warn("Organization has its own Alertmanager configuration, it is not replaced", "orgId", orgID)
+		summary.skipped(ResourceAlerting)
+		return nil
+	}
+
+	cfg := a.AlertmanagerConfiguration
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		for _, gr := range r.GrafanaManagedReceivers {
+			gr.SecureSettings = s.expandSecrets(gr.SecureSettings, "contact point", gr.Name)
+		}
+	}
+	if err := cfg.ProcessConfig(s.secrets.Encrypt); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := s.alertingStore.SaveAlertmanagerConfiguration(ctx, &ngmodels.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(raw),
+		ConfigurationVersion:      fmt.Sprintf("v%d", ngmodels.AlertConfigurationVersion),
+		OrgID:                     orgID,
+		Origin:                    ngmodels.AlertConfigurationOriginRestore,
+	}); err != nil {
+		return err
+	}
+	summary.restored(ResourceAlerting)
+	return nil
+}
+
+// restoreAlertRules imports the missing rules of every rule group of the backup. A group that already exists keeps
+// its interval and labels, so that its existing rules are not changed.
+func (s *Service) restoreAlertRules(ctx context.Context, orgID int64, rules []ngmodels.AlertRule, summary Summary) error {
+	folders := map[string]bool{}
+	var groups []*definitions.AlertRuleGroup
+	byKey := map[ngmodels.AlertRuleGroupKey]*definitions.AlertRuleGroup{}
+	for _, r := range rules {
+		_, _, err := s.alertRuleService.GetAlertRule(ctx, orgID, r.UID)
+		if err == nil {
+			summary.skipped(ResourceAlerting)
+			continue
+		}
+		if !errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return err
+		}
+		if _, ok := folders[r.NamespaceUID]; !ok {
+			_, err := s.getFolder(ctx, orgID, r.NamespaceUID)
+			folders[r.NamespaceUID] = err == nil
+		}
+		if !folders[r.NamespaceUID] {
+			s.log.Warn("Folder of the alert rule does not exist", "rule", r.UID, "folder", r.NamespaceUID, "orgId", orgID)
+			summary.skipped(ResourceAlerting)
+			continue
+		}
+
+		key := ngmodels.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: r.NamespaceUID, RuleGroup: r.RuleGroup}
+		group, ok := byKey[key]
+		if !ok {
+			group = &definitions.AlertRuleGroup{Title: r.RuleGroup, FolderUID: r.NamespaceUID, Interval: r.IntervalSeconds, Labels: r.GroupLabels}
+			existing, err := s.alertRuleService.GetRuleGroup(ctx, orgID, r.NamespaceUID, r.RuleGroup)
+			if err == nil {
+				group.Interval = existing.Interval
+				group.Labels = existing.Labels
+			} else if !errors.Is(err, ngstore.ErrAlertRuleGroupNotFound) {
+				return err
+			}
+			byKey[key] = group
+			groups = append(groups, group)
+		}
+		r.ID = 0
+		r.OrgID = orgID
+		group.Rules = append(group.Rules, r)
+	}
+
+	for _, group := range groups {
+		err := s.alertRuleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{*group}, ngmodels.ProvenanceNone, false)
+		if errors.Is(err, provisioning.ErrValidation) {
+			s.log.Warn("Alert rules cannot be restored", "folder", group.FolderUID, "group", group.Title, "orgId", orgID, "error", err)
+			for range group.Rules {
+				summary.skipped(ResourceAlerting)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import rule group %s: %w", group.Title, err)
+		}
+		for range group.Rules {
+			summary.restored(ResourceAlerting)
+		}
+	}
+	return nil
+}
+
+// expandSecrets returns the secrets with their placeholders replaced by the values of the environment variables
+// they reference. Secrets whose environment variable is not set are left out.
+func (s *Service) expandSecrets(values map[string]string, kind, name string) map[string]string {
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		m := placeholderRegex.FindStringSubmatch(value)
+		if m == nil {
+			result[key] = value
+			continue
+		}
+		if v, ok := s.lookupEnv(m[1]); ok {
+			result[key] = v
+			continue
+		}
+		s.log.Warn("Secret is not restored, its environment variable is not set", "kind", kind, "name", name, "key", key, "variable", m[1])
+	}
+	return result
+}
+
+func decodeFile(files map[string][]byte, name string, v interface{}) error {
+	content, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%w: %s is missing", ErrIncompatibleArchive, name)
+	}
+	if err := json.NewDecoder(bytes.NewReader(content)).Decode(v); err != nil {
+		return fmt.Errorf("%w: failed to decode %s: %s", ErrIncompatibleArchive, name, err.Error())
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
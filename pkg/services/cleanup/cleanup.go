@@ -8,6 +8,7 @@ import (
 	"path"
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/imguploader"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
@@ -72,6 +73,13 @@ func (srv *CleanUpService) Run(ctx context.Context) error {
 			if err != nil {
 				srv.log.Error("failed to lock and execute cleanup of old login attempts", "error", err)
 			}
+			err = srv.ServerLockService.LockAndExecute(ctx, "delete expired uploaded images",
+				time.Minute*10, func(context.Context) {
+					srv.deleteExpiredUploadedImages(ctxWithTimeout)
+				})
+			if err != nil {
+				srv.log.Error("failed to lock and execute cleanup of expired uploaded images", "error", err)
+			}
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -138,6 +146,34 @@ func (srv *CleanUpService) shouldCleanupTempFile(filemtime time.Time, now time.T
 	return filemtime.Add(srv.Cfg.TempDataLifetime).Before(now)
 }
 
+// newImageUploader is stubbable by tests.
+var newImageUploader = imguploader.NewImageUploader
+
+// deleteExpiredUploadedImages deletes the images uploaded to the external image storage before the
+// retention period, if the storage supports it.
+func (srv *CleanUpService) deleteExpiredUploadedImages(ctx context.Context) {
+	if srv.Cfg.ImageUploadRetention <= 0 {
+		return
+	}
+
+	uploader, err := newImageUploader()
+	if err != nil {
+		srv.log.Error("Failed to create image uploader", "error", err)
+		return
+	}
+	cleaner, ok := uploader.(imguploader.ImageCleaner)
+	if !ok {
+		return
+	}
+
+	deleted, err := cleaner.DeleteOlderThan(ctx, time.Now().Add(-srv.Cfg.ImageUploadRetention))
+	if err != nil {
+		srv.log.Error("Problem deleting expired uploaded images", "error", err, "deleted", deleted)
+	} else {
+		srv.log.Debug("Deleted expired uploaded images", "deleted", deleted)
+	}
+}
+
 func (srv *CleanUpService) deleteExpiredSnapshots(ctx context.Context) {
 	cmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
 	if err := srv.dashboardSnapshotService.DeleteExpiredSnapshots(ctx, &cmd); err != nil {
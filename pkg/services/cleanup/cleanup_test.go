@@ -1,9 +1,12 @@
 package cleanup
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/require"
 )
@@ -35,3 +38,40 @@ func TestCleanUpTmpFiles(t *testing.T) {
 		require.False(t, service.shouldCleanupTempFile(weekAgo, now))
 	})
 }
+
+type fakeImageCleaner struct {
+	imguploader.NopImageUploader
+	before []time.Time
+}
+
+func (f *fakeImageCleaner) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	f.before = append(f.before, before)
+	return 1, nil
+}
+
+func TestDeleteExpiredUploadedImages(t *testing.T) {
+	cleaner := &fakeImageCleaner{}
+	origNewImageUploader := newImageUploader
+	newImageUploader = func() (imguploader.ImageUploader, error) {
+		return cleaner, nil
+	}
+	t.Cleanup(func() { newImageUploader = origNewImageUploader })
+
+	cfg := setting.Cfg{}
+	service := CleanUpService{
+		Cfg: &cfg,
+		log: log.New("cleanup"),
+	}
+
+	t.Run("If retention is 0, uploaded images should never be deleted", func(t *testing.T) {
+		service.deleteExpiredUploadedImages(context.Background())
+		require.Empty(t, cleaner.before)
+	})
+
+	t.Run("Should delete images uploaded before the retention", func(t *testing.T) {
+		cfg.ImageUploadRetention = 24 * time.Hour
+		service.deleteExpiredUploadedImages(context.Background())
+		require.Len(t, cleaner.before, 1)
+		require.WithinDuration(t, time.Now().Add(-24*time.Hour), cleaner.before[0], time.Minute)
+	})
+}
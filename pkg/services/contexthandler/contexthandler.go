@@ -391,12 +391,33 @@ func (h *ContextHandler) initContextWithToken(reqContext *models.ReqContext, org
 		return false
 	}
 
+	// a session switched to an organization with an org switch token stays in that organization,
+	// unless another one is requested explicitly
+	orgContextID := int64(0)
+	if orgID == 0 {
+		if id, err := strconv.ParseInt(reqContext.GetCookie(cookies.OrgContextCookieName), 10, 64); err == nil && id > 0 {
+			orgContextID = id
+			orgID = id
+		}
+	}
+
 	query := models.GetSignedInUserQuery{UserId: token.UserId, OrgId: orgID}
 	if err := h.SQLStore.GetSignedInUserWithCacheCtx(ctx, &query); err != nil {
 		reqContext.Logger.Error("Failed to get user with id", "userId", token.UserId, "error", err)
 		return false
 	}
 
+	if orgContextID != 0 && query.Result.OrgId != orgContextID {
+		// the user is no longer a member of the organization, fall back to the default one
+		reqContext.Logger.Debug("Removing organization context of session", "userId", token.UserId, "orgId", orgContextID)
+		cookies.DeleteCookie(reqContext.Resp, cookies.OrgContextCookieName, nil)
+		query = models.GetSignedInUserQuery{UserId: token.UserId}
+		if err := h.SQLStore.GetSignedInUserWithCacheCtx(ctx, &query); err != nil {
+			reqContext.Logger.Error("Failed to get user with id", "userId", token.UserId, "error", err)
+			return false
+		}
+	}
+
 	reqContext.SignedInUser = query.Result
 	reqContext.IsSignedIn = true
 	reqContext.UserToken = token
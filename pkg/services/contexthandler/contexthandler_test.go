@@ -5,12 +5,16 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/assert"
@@ -79,6 +83,54 @@ func TestTokenRotationAtEndOfRequest(t *testing.T) {
 	assert.True(t, foundLoginCookie, "Could not find cookie")
 }
 
+func TestOrgContextCookie(t *testing.T) {
+	ctxHdlr := getContextHandler(t)
+	ctxHdlr.Cfg.LoginCookieName = "login_token"
+	store := ctxHdlr.SQLStore.(*sqlstore.SQLStore)
+
+	usr, err := store.CreateUser(context.Background(), user.CreateUserCommand{Login: "embedded"})
+	require.NoError(t, err)
+	org, err := store.CreateOrgWithMember("Embedded", usr.ID)
+	require.NoError(t, err)
+	other, err := store.CreateOrgWithMember("Other", 0)
+	require.NoError(t, err)
+
+	ctxHdlr.AuthTokenService = &auth.FakeUserAuthTokenService{
+		LookupTokenProvider: func(ctx context.Context, unhashedToken string) (*models.UserToken, error) {
+			return &models.UserToken{UserId: usr.ID}, nil
+		},
+	}
+
+	initWithOrgContext := func(orgID string) (*models.ReqContext, *httptest.ResponseRecorder) {
+		reqContext, rr, err := initTokenRotationScenario(context.Background(), t, ctxHdlr)
+		require.NoError(t, err)
+		reqContext.Req.AddCookie(&http.Cookie{Name: "login_token", Value: "token"})
+		reqContext.Req.AddCookie(&http.Cookie{Name: cookies.OrgContextCookieName, Value: orgID})
+		require.True(t, ctxHdlr.initContextWithToken(reqContext, 0))
+		return reqContext, rr
+	}
+
+	t.Run("the organization of the session takes precedence over the default one", func(t *testing.T) {
+		require.NotEqual(t, usr.OrgID, org.Id)
+		reqContext, _ := initWithOrgContext(strconv.FormatInt(org.Id, 10))
+		assert.Equal(t, org.Id, reqContext.OrgId)
+	})
+
+	t.Run("the default organization is used if the user is not a member", func(t *testing.T) {
+		reqContext, rr := initWithOrgContext(strconv.FormatInt(other.Id, 10))
+		assert.Equal(t, usr.OrgID, reqContext.OrgId)
+
+		// nolint:bodyclose
+		resp := rr.Result()
+		t.Cleanup(func() {
+			assert.NoError(t, resp.Body.Close())
+		})
+		require.Len(t, resp.Cookies(), 1)
+		assert.Equal(t, cookies.OrgContextCookieName, resp.Cookies()[0].Name)
+		assert.Equal(t, -1, resp.Cookies()[0].MaxAge)
+	})
+}
+
 func initTokenRotationScenario(ctx context.Context, t *testing.T, ctxHdlr *ContextHandler) (
 	*models.ReqContext, *httptest.ResponseRecorder, error) {
 	t.Helper()
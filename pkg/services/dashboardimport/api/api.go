@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/apierrors"
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboardimport"
+	"github.com/grafana/grafana/pkg/services/dashboardimport/jsonnet"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -40,6 +42,11 @@ func (api *ImportDashboardAPI) RegisterAPIEndpoints(routeRegister routing.RouteR
 			authorize(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionDashboardsCreate)),
 			routing.Wrap(api.ImportDashboard),
 		)
+		route.Post(
+			"/import/jsonnet",
+			authorize(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionDashboardsCreate)),
+			routing.Wrap(api.ImportJsonnet),
+		)
 	}, middleware.ReqSignedIn)
 }
 
@@ -71,6 +78,42 @@ func (api *ImportDashboardAPI) ImportDashboard(c *models.ReqContext) response.Re
 	return response.JSON(http.StatusOK, resp)
 }
 
+func (api *ImportDashboardAPI) ImportJsonnet(c *models.ReqContext) response.Response {
+	req := dashboardimport.ImportJsonnetRequest{}
+	if err := web.Bind(c.Req, &req); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if req.Entrypoint == "" || len(req.Files) == 0 {
+		return response.Error(http.StatusUnprocessableEntity, "Files and entrypoint must be set", nil)
+	}
+
+	limitReached, err := api.quotaService.QuotaReached(c, "dashboard")
+	if err != nil {
+		return response.Error(500, "failed to get quota", err)
+	}
+
+	if limitReached {
+		return response.Error(403, "Quota reached", nil)
+	}
+
+	req.User = c.SignedInUser
+	resp, err := api.dashboardImportService.ImportJsonnet(c.Req.Context(), &req)
+	if err != nil {
+		if errors.Is(err, jsonnet.ErrInvalidBundle) || errors.Is(err, jsonnet.ErrLibraryNotAllowed) ||
+			errors.Is(err, jsonnet.ErrEvaluation) || errors.Is(err, jsonnet.ErrEvaluationTimeout) ||
+			errors.Is(err, jsonnet.ErrNoDashboards) || errors.Is(err, jsonnet.ErrInvalidDashboard) {
+			return response.Error(http.StatusBadRequest, err.Error(), nil)
+		}
+		if errors.Is(err, jsonnet.ErrEvaluatorBusy) {
+			return response.Error(http.StatusServiceUnavailable, err.Error(), nil)
+		}
+		return apierrors.ToDashboardErrorResponse(c.Req.Context(), api.pluginStore, err)
+	}
+
+	return response.JSON(http.StatusOK, resp)
+}
+
 type QuotaService interface {
 	QuotaReached(c *models.ReqContext, target string) (bool, error)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/dashboardimport"
+	"github.com/grafana/grafana/pkg/services/dashboardimport/jsonnet"
 	"github.com/grafana/grafana/pkg/web/webtest"
 	"github.com/stretchr/testify/require"
 )
@@ -152,8 +154,60 @@ func TestImportDashboardAPI(t *testing.T) {
 	})
 }
 
+func TestImportJsonnetAPI(t *testing.T) {
+	service := &serviceMock{
+		importJsonnetFunc: func(ctx context.Context, req *dashboardimport.ImportJsonnetRequest) (*dashboardimport.ImportJsonnetResponse, error) {
+			if req.Entrypoint == "broken.jsonnet" {
+				return nil, fmt.Errorf("%w: syntax error", jsonnet.ErrEvaluation)
+			}
+			if req.Entrypoint == "busy.jsonnet" {
+				return nil, jsonnet.ErrEvaluatorBusy
+			}
+			return &dashboardimport.ImportJsonnetResponse{}, nil
+		},
+	}
+
+	importDashboardAPI := New(service, quotaServiceFunc(quotaNotReached), nil, acmock.New().WithDisabled())
+	routeRegister := routing.NewRouteRegister()
+	importDashboardAPI.RegisterAPIEndpoints(routeRegister)
+	s := webtest.NewServer(t, routeRegister)
+
+	send := func(t *testing.T, cmd *dashboardimport.ImportJsonnetRequest) int {
+		jsonBytes, err := json.Marshal(cmd)
+		require.NoError(t, err)
+		req := s.NewPostRequest("/api/dashboards/import/jsonnet", bytes.NewReader(jsonBytes))
+		webtest.RequestWithSignedInUser(req, &models.SignedInUser{
+			UserId: 1,
+		})
+		resp, err := s.SendJSON(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	t.Run("Signed in, missing files should return error", func(t *testing.T) {
+		require.Equal(t, http.StatusUnprocessableEntity, send(t, &dashboardimport.ImportJsonnetRequest{Entrypoint: "main.jsonnet"}))
+	})
+
+	t.Run("Signed in, bundle failing evaluation should return bad request", func(t *testing.T) {
+		files := map[string]string{"broken.jsonnet": "{"}
+		require.Equal(t, http.StatusBadRequest, send(t, &dashboardimport.ImportJsonnetRequest{Files: files, Entrypoint: "broken.jsonnet"}))
+	})
+
+	t.Run("Signed in, bundle should be rejected when too many are evaluated", func(t *testing.T) {
+		files := map[string]string{"busy.jsonnet": "{}"}
+		require.Equal(t, http.StatusServiceUnavailable, send(t, &dashboardimport.ImportJsonnetRequest{Files: files, Entrypoint: "busy.jsonnet"}))
+	})
+
+	t.Run("Signed in, bundle should be imported", func(t *testing.T) {
+		files := map[string]string{"main.jsonnet": "{}"}
+		require.Equal(t, http.StatusOK, send(t, &dashboardimport.ImportJsonnetRequest{Files: files, Entrypoint: "main.jsonnet"}))
+	})
+}
+
 type serviceMock struct {
 	importDashboardFunc func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error)
+	importJsonnetFunc   func(ctx context.Context, req *dashboardimport.ImportJsonnetRequest) (*dashboardimport.ImportJsonnetResponse, error)
 }
 
 func (s *serviceMock) ImportDashboard(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error) {
@@ -164,6 +218,14 @@ func (s *serviceMock) ImportDashboard(ctx context.Context, req *dashboardimport.
 	return nil, nil
 }
 
+func (s *serviceMock) ImportJsonnet(ctx context.Context, req *dashboardimport.ImportJsonnetRequest) (*dashboardimport.ImportJsonnetResponse, error) {
+	if s.importJsonnetFunc != nil {
+		return s.importJsonnetFunc(ctx, req)
+	}
+
+	return nil, nil
+}
+
 func quotaReached(c *models.ReqContext, target string) (bool, error) {
 	return true, nil
 }
@@ -46,7 +46,27 @@ type ImportDashboardResponse struct {
 	Removed          bool   `json:"removed"`
 }
 
+// ImportJsonnetRequest request object for importing the dashboards produced by a jsonnet bundle.
+type ImportJsonnetRequest struct {
+	// Files are the jsonnet files of the bundle, keyed by their path relative to the root of the bundle. Vendored
+	// libraries are in the vendor directory.
+	Files map[string]string `json:"files"`
+	// Entrypoint is the path of the file to evaluate.
+	Entrypoint string `json:"entrypoint"`
+	Overwrite  bool   `json:"overwrite"`
+	FolderId   int64  `json:"folderId"`
+
+	User *models.SignedInUser `json:"-"`
+}
+
+// ImportJsonnetResponse response object returned when importing the dashboards produced by a jsonnet bundle.
+type ImportJsonnetResponse struct {
+	Dashboards []ImportDashboardResponse `json:"dashboards"`
+}
+
 // Service service interface for importing dashboards.
 type Service interface {
 	ImportDashboard(ctx context.Context, req *ImportDashboardRequest) (*ImportDashboardResponse, error)
+	// ImportJsonnet evaluates a jsonnet bundle and saves the dashboards it produces as provisioned dashboards.
+	ImportJsonnet(ctx context.Context, req *ImportJsonnetRequest) (*ImportJsonnetResponse, error)
 }
@@ -0,0 +1,287 @@
+package jsonnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-jsonnet"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// VendorDir is the directory of the vendored libraries in a bundle, as laid out by jsonnet-bundler.
+const VendorDir = "vendor"
+
+// LibDir is the directory of the libraries of the project in a bundle, as laid out by Tanka.
+const LibDir = "lib"
+
+// maxStack is the maximum depth of the evaluation stack, it bounds runaway recursion.
+const maxStack = 500
+
+var (
+	ErrInvalidBundle     = errors.New("invalid jsonnet bundle")
+	ErrLibraryNotAllowed = errors.New("library is not allowed")
+	ErrEvaluation        = errors.New("jsonnet evaluation failed")
+	ErrEvaluationTimeout = errors.New("jsonnet evaluation timed out")
+	ErrEvaluatorBusy     = errors.New("too many jsonnet evaluations in progress")
+	ErrNoDashboards      = errors.New("jsonnet evaluation produced no dashboards")
+	ErrInvalidDashboard  = errors.New("jsonnet evaluation produced an invalid dashboard")
+)
+
+// Bundle is a set of jsonnet files, keyed by their path relative to the root of the bundle, and the file to evaluate.
+type Bundle struct {
+	Files      map[string]string
+	Entrypoint string
+}
+
+// Dashboard is a dashboard produced by the evaluation of a bundle.
+type Dashboard struct {
+	// Key is the path of the dashboard in the output of the evaluation, it is empty if the output is the dashboard.
+	Key  string
+	Data *simplejson.Json
+}
+
+// Evaluator evaluates jsonnet bundles in a sandbox: imports are only resolved within the bundle, there are no
+// native functions or external variables, and the evaluation is bounded in depth, time and memory. Every evaluation
+// runs in a child process, which is killed when it exceeds its time or memory, and the number of evaluations in
+// progress is limited.
+type Evaluator struct {
+	// AllowedLibraries are the libraries that bundles may contain, such as github.com/grafana/grafonnet.
+	AllowedLibraries []string
+	Timeout          time.Duration
+	// MaxConcurrent is the maximum number of evaluations in progress, further evaluations fail with
+	// ErrEvaluatorBusy. There is no limit if it is 0 or less.
+	MaxConcurrent int
+	// MaxMemory is the maximum size in bytes of the heap of the process of an evaluation. There is no limit if it is
+	// 0 or less.
+	MaxMemory int64
+	// Executable is the program run to evaluate bundles, it defaults to the running program. It must call
+	// RunEvaluatorProcess when IsEvaluatorProcess returns true, before doing anything else.
+	Executable string
+
+	slotsOnce sync.Once
+	slots     chan struct{}
+}
+
+// Evaluate evaluates the entrypoint of the bundle and returns the dashboards of the output. The output is either a
+// dashboard, or an array or object of dashboards, which may be nested, as with Tanka environments.
+func (e *Evaluator) Evaluate(ctx context.Context, bundle Bundle) ([]Dashboard, error) {
+	files, err := e.validate(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if !e.acquire() {
+		return nil, ErrEvaluatorBusy
+	}
+	defer e.release()
+
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+	raw, err := e.run(ctx, evaluation{Files: files, Entrypoint: path.Clean(bundle.Entrypoint), MaxMemory: e.MaxMemory})
+	if err != nil {
+		return nil, err
+	}
+
+	var output interface{}
+	if err := json.Unmarshal(raw, &output); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrEvaluation, err.Error())
+	}
+	var dashboards []Dashboard
+	if err := collectDashboards("", output, &dashboards); err != nil {
+		return nil, err
+	}
+	if len(dashboards) == 0 {
+		return nil, ErrNoDashboards
+	}
+	return dashboards, nil
+}
+
+// validate checks the paths of the files of the bundle and that it only contains allowed libraries, wherever they
+// are in the bundle. It returns the files keyed by their clean path.
+func (e *Evaluator) validate(bundle Bundle) (map[string]string, error) {
+	if bundle.Entrypoint == "" {
+		return nil, fmt.Errorf("%w: missing entrypoint", ErrInvalidBundle)
+	}
+
+	files := make(map[string]string, len(bundle.Files))
+	var notAllowed []string
+	for p, content := range bundle.Files {
+		clean := path.Clean(p)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return nil, fmt.Errorf("%w: path %q is outside of the bundle", ErrInvalidBundle, p)
+		}
+		if _, ok := files[clean]; ok {
+			return nil, fmt.Errorf("%w: path %q is in the bundle more than once", ErrInvalidBundle, clean)
+		}
+		if lib, ok := libraryFile(clean); ok && !e.libraryAllowed(lib) {
+			notAllowed = append(notAllowed, clean)
+		}
+		files[clean] = content
+	}
+	if len(notAllowed) > 0 {
+		sort.Strings(notAllowed)
+		return nil, fmt.Errorf("%w: %s", ErrLibraryNotAllowed, strings.Join(notAllowed, ", "))
+	}
+
+	if _, ok := files[path.Clean(bundle.Entrypoint)]; !ok {
+		return nil, fmt.Errorf("%w: entrypoint %q is not in the bundle", ErrInvalidBundle, bundle.Entrypoint)
+	}
+	return files, nil
+}
+
+// acquire takes a slot for an evaluation, it returns false if all of them are taken.
+func (e *Evaluator) acquire() bool {
+	if e.MaxConcurrent <= 0 {
+		return true
+	}
+	e.slotsOnce.Do(func() {
+		e.slots = make(chan struct{}, e.MaxConcurrent)
+	})
+	select {
+	case e.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release frees the slot taken by acquire.
+func (e *Evaluator) release() {
+	if e.MaxConcurrent > 0 {
+		<-e.slots
+	}
+}
+
+// libraryFile returns the path of the file relative to the directory of its library, such as
+// github.com/grafana/grafonnet/main.libsonnet, and true if it belongs to a library. The files of the vendor directory
+// belong to libraries, and so do the files in a directory named after a host, like github.com, wherever it is: they
+// can be imported by relative paths or from the lib directory as well.
+func libraryFile(file string) (string, bool) {
+	if lib := strings.TrimPrefix(file, VendorDir+"/"); lib != file {
+		return lib, true
+	}
+	dirs := strings.Split(file, "/")
+	for i, dir := range dirs[:len(dirs)-1] {
+		if isHost(dir) {
+			return strings.Join(dirs[i:], "/"), true
+		}
+	}
+	return "", false
+}
+
+// isHost returns true if the directory is named like a host, with a dot followed by a top-level domain.
+func isHost(dir string) bool {
+	dot := strings.LastIndexByte(dir, '.')
+	if dot <= 0 || dot == len(dir)-1 {
+		return false
+	}
+	for _, r := range dir[dot+1:] {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// libraryAllowed returns true if the file, given by its path relative to the directory of its library, belongs to an
+// allowed library.
+func (e *Evaluator) libraryAllowed(file string) bool {
+	for _, lib := range e.AllowedLibraries {
+		lib = strings.Trim(lib, "/")
+		if lib != "" && strings.HasPrefix(file, lib+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// collectDashboards adds the dashboards of the value to dashboards. Objects with a title are dashboards, the values
+// of other objects and arrays are searched for dashboards.
+func collectDashboards(key string, value interface{}, dashboards *[]Dashboard) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v["title"]; ok {
+			*dashboards = append(*dashboards, Dashboard{Key: key, Data: simplejson.NewFromAny(v)})
+			return nil
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			nestedKey := k
+			if key != "" {
+				nestedKey = key + "." + k
+			}
+			if err := collectDashboards(nestedKey, v[k], dashboards); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, nested := range v {
+			if err := collectDashboards(fmt.Sprintf("%s[%d]", key, i), nested, dashboards); err != nil {
+				return err
+			}
+		}
+	default:
+		if key == "" {
+			return fmt.Errorf("%w: the output is not an object or array", ErrInvalidDashboard)
+		}
+		return fmt.Errorf("%w: %q is not an object or array", ErrInvalidDashboard, key)
+	}
+	return nil
+}
+
+// bundleImporter resolves imports within the files of a bundle. Imports are relative to the importing file, or to
+// the lib and vendor directories of the bundle.
+type bundleImporter struct {
+	files    map[string]string
+	contents map[string]jsonnet.Contents
+}
+
+func newBundleImporter(files map[string]string) *bundleImporter {
+	return &bundleImporter{
+		files:    files,
+		contents: make(map[string]jsonnet.Contents, len(files)),
+	}
+}
+
+func (i *bundleImporter) Import(importedFrom, importedPath string) (jsonnet.Contents, string, error) {
+	if path.IsAbs(importedPath) {
+		return jsonnet.Contents{}, "", fmt.Errorf("import %q is outside of the bundle", importedPath)
+	}
+	candidates := []string{
+		path.Join(path.Dir(importedFrom), importedPath),
+		path.Join(LibDir, importedPath),
+		path.Join(VendorDir, importedPath),
+	}
+	for _, candidate := range candidates {
+		if candidate == ".." || strings.HasPrefix(candidate, "../") {
+			continue
+		}
+		content, ok := i.files[candidate]
+		if !ok {
+			continue
+		}
+		// the same contents must be returned for a path, so that the VM can cache them
+		c, ok := i.contents[candidate]
+		if !ok {
+			c = jsonnet.MakeContents(content)
+			i.contents[candidate] = c
+		}
+		return c, candidate, nil
+	}
+	return jsonnet.Contents{}, "", fmt.Errorf("couldn't find import %q in the bundle", importedPath)
+}
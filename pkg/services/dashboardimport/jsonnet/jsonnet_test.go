@@ -0,0 +1,242 @@
+package jsonnet
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// the evaluations run the test binary
+	if IsEvaluatorProcess() {
+		os.Exit(RunEvaluatorProcess())
+	}
+	os.Exit(m.Run())
+}
+
+func TestEvaluator(t *testing.T) {
+	evaluator := &Evaluator{
+		AllowedLibraries: []string{"github.com/grafana/grafonnet"},
+		Timeout:          time.Minute,
+	}
+
+	t.Run("evaluates a dashboard", func(t *testing.T) {
+		dashboards, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files:      map[string]string{"main.jsonnet": `{ uid: "a", title: "A " + std.toString(1 + 1) }`},
+			Entrypoint: "main.jsonnet",
+		})
+
+		require.NoError(t, err)
+		require.Len(t, dashboards, 1)
+		require.Equal(t, "", dashboards[0].Key)
+		require.Equal(t, "A 2", dashboards[0].Data.Get("title").MustString())
+	})
+
+	t.Run("evaluates nested dashboards", func(t *testing.T) {
+		dashboards, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files: map[string]string{"environments/default/main.jsonnet": `{
+				b: { title: "B" },
+				a: { nested: [{ title: "A1" }, { title: "A2" }] },
+			}`},
+			Entrypoint: "environments/default/main.jsonnet",
+		})
+
+		require.NoError(t, err)
+		keys := make([]string, 0, len(dashboards))
+		for _, d := range dashboards {
+			keys = append(keys, d.Key)
+		}
+		require.Equal(t, []string{"a.nested[0]", "a.nested[1]", "b"}, keys)
+	})
+
+	t.Run("resolves imports within the bundle", func(t *testing.T) {
+		dashboards, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files: map[string]string{
+				"environments/default/main.jsonnet": `
+					local g = import "github.com/grafana/grafonnet/main.libsonnet";
+					local panels = import "panels.libsonnet";
+					local common = import "../../common.libsonnet";
+					g.dashboard(common.prefix + "Overview") + { panels: panels }`,
+				"environments/default/panels.libsonnet":              `[{ type: "text", options: { content: importstr "README.md" } }]`,
+				"environments/default/README.md":                     `readme`,
+				"common.libsonnet":                                   `{ prefix: "Team: " }`,
+				"vendor/github.com/grafana/grafonnet/main.libsonnet": `{ dashboard(title):: { title: title } }`,
+			},
+			Entrypoint: "environments/default/main.jsonnet",
+		})
+
+		require.NoError(t, err)
+		require.Len(t, dashboards, 1)
+		require.Equal(t, "Team: Overview", dashboards[0].Data.Get("title").MustString())
+		require.Equal(t, "readme", dashboards[0].Data.GetPath("panels").GetIndex(0).GetPath("options", "content").MustString())
+	})
+
+	t.Run("resolves imports from the lib directory", func(t *testing.T) {
+		dashboards, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files: map[string]string{
+				"main.jsonnet":       `import "util.libsonnet"`,
+				"lib/util.libsonnet": `{ title: "From lib" }`,
+			},
+			Entrypoint: "main.jsonnet",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "From lib", dashboards[0].Data.Get("title").MustString())
+	})
+
+	t.Run("rejects vendored libraries that are not allowed", func(t *testing.T) {
+		_, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files: map[string]string{
+				"main.jsonnet": `{ title: "A" }`,
+				"vendor/github.com/grafana/grafonnet/main.libsonnet":   `{}`,
+				"vendor/github.com/grafana/grafonnet-x/main.libsonnet": `{}`,
+				"vendor/github.com/other/lib/main.libsonnet":           `{}`,
+			},
+			Entrypoint: "main.jsonnet",
+		})
+
+		require.ErrorIs(t, err, ErrLibraryNotAllowed)
+		require.ErrorContains(t, err, "vendor/github.com/grafana/grafonnet-x/main.libsonnet, vendor/github.com/other/lib/main.libsonnet")
+	})
+
+	t.Run("rejects libraries that are not allowed outside of the vendor directory", func(t *testing.T) {
+		_, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files: map[string]string{
+				"main.jsonnet":                             `(import "github.com/other/lib/main.libsonnet") + (import "github.com/other/util/main.libsonnet")`,
+				"github.com/other/lib/main.libsonnet":      `{ title: "A" }`,
+				"lib/github.com/other/util/main.libsonnet": `{}`,
+			},
+			Entrypoint: "main.jsonnet",
+		})
+
+		require.ErrorIs(t, err, ErrLibraryNotAllowed)
+		require.ErrorContains(t, err, "github.com/other/lib/main.libsonnet, lib/github.com/other/util/main.libsonnet")
+	})
+
+	t.Run("accepts allowed libraries outside of the vendor directory", func(t *testing.T) {
+		dashboards, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files: map[string]string{
+				"main.jsonnet": `(import "github.com/grafana/grafonnet/main.libsonnet") + (import "v1.2/common.libsonnet")`,
+				"lib/github.com/grafana/grafonnet/main.libsonnet": `{ title: "A" }`,
+				"v1.2/common.libsonnet":                           `{ uid: "a" }`,
+			},
+			Entrypoint: "main.jsonnet",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "a", dashboards[0].Data.Get("uid").MustString())
+	})
+
+	t.Run("rejects paths outside of the bundle", func(t *testing.T) {
+		for _, p := range []string{"../main.jsonnet", "/etc/main.jsonnet", "lib/../../main.jsonnet"} {
+			_, err := evaluator.Evaluate(context.Background(), Bundle{
+				Files:      map[string]string{p: `{ title: "A" }`},
+				Entrypoint: p,
+			})
+
+			require.ErrorIs(t, err, ErrInvalidBundle, p)
+		}
+	})
+
+	t.Run("rejects imports outside of the bundle", func(t *testing.T) {
+		for _, imported := range []string{"/etc/passwd", "../../etc/passwd"} {
+			_, err := evaluator.Evaluate(context.Background(), Bundle{
+				Files:      map[string]string{"main.jsonnet": `{ title: importstr "` + imported + `" }`},
+				Entrypoint: "main.jsonnet",
+			})
+
+			require.ErrorIs(t, err, ErrEvaluation, imported)
+		}
+	})
+
+	t.Run("rejects a missing entrypoint", func(t *testing.T) {
+		_, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files:      map[string]string{"main.jsonnet": `{ title: "A" }`},
+			Entrypoint: "other.jsonnet",
+		})
+
+		require.ErrorIs(t, err, ErrInvalidBundle)
+	})
+
+	t.Run("returns evaluation errors", func(t *testing.T) {
+		_, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files:      map[string]string{"main.jsonnet": `{ title: std.extVar("title") }`},
+			Entrypoint: "main.jsonnet",
+		})
+
+		require.ErrorIs(t, err, ErrEvaluation)
+	})
+
+	t.Run("stops runaway recursion", func(t *testing.T) {
+		_, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files:      map[string]string{"main.jsonnet": `local f(x) = f(x + 1) + 1; { title: f(0) }`},
+			Entrypoint: "main.jsonnet",
+		})
+
+		require.ErrorIs(t, err, ErrEvaluation)
+		require.ErrorContains(t, err, "max stack frames exceeded")
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		evaluator := &Evaluator{Timeout: time.Millisecond}
+
+		_, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files:      map[string]string{"main.jsonnet": `{ title: std.length(std.range(1, 10000000)) }`},
+			Entrypoint: "main.jsonnet",
+		})
+
+		require.ErrorIs(t, err, ErrEvaluationTimeout)
+	})
+
+	t.Run("stops evaluations that use too much memory", func(t *testing.T) {
+		evaluator := &Evaluator{Timeout: time.Minute, MaxMemory: 32 << 20}
+
+		_, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files:      map[string]string{"main.jsonnet": `{ title: std.join(",", std.makeArray(5000000, function(i) std.toString(i))) }`},
+			Entrypoint: "main.jsonnet",
+		})
+
+		require.ErrorIs(t, err, ErrEvaluation)
+		require.ErrorContains(t, err, "more than 33554432 bytes of memory")
+	})
+
+	t.Run("rejects evaluations when too many are in progress", func(t *testing.T) {
+		evaluator := &Evaluator{Timeout: 100 * time.Millisecond, MaxConcurrent: 1}
+		runaway := Bundle{
+			Files:      map[string]string{"main.jsonnet": `local f(n) = if n == 0 then 0 else f(n - 1) + f(n - 1); { title: f(50) }`},
+			Entrypoint: "main.jsonnet",
+		}
+		require.True(t, evaluator.acquire())
+
+		_, err := evaluator.Evaluate(context.Background(), runaway)
+		require.ErrorIs(t, err, ErrEvaluatorBusy)
+
+		// the slot of a timed out evaluation is free once its process is killed
+		evaluator.release()
+		_, err = evaluator.Evaluate(context.Background(), runaway)
+		require.ErrorIs(t, err, ErrEvaluationTimeout)
+		_, err = evaluator.Evaluate(context.Background(), Bundle{
+			Files:      map[string]string{"main.jsonnet": `{ title: "A" }`},
+			Entrypoint: "main.jsonnet",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects output without dashboards", func(t *testing.T) {
+		_, err := evaluator.Evaluate(context.Background(), Bundle{
+			Files:      map[string]string{"main.jsonnet": `{ a: {}, b: [] }`},
+			Entrypoint: "main.jsonnet",
+		})
+		require.ErrorIs(t, err, ErrNoDashboards)
+
+		_, err = evaluator.Evaluate(context.Background(), Bundle{
+			Files:      map[string]string{"main.jsonnet": `{ a: { title: "A" }, b: "text" }`},
+			Entrypoint: "main.jsonnet",
+		})
+		require.ErrorIs(t, err, ErrInvalidDashboard)
+		require.ErrorContains(t, err, `"b"`)
+	})
+}
@@ -0,0 +1,123 @@
+package jsonnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/google/go-jsonnet"
+)
+
+// evaluatorProcessEnv is set in the environment of the processes of the evaluations.
+const evaluatorProcessEnv = "GF_JSONNET_EVALUATOR_PROCESS"
+
+// The exit codes of the process of an evaluation, other than 0 when the evaluation succeeds.
+const (
+	exitCodeInvalidInput = 3
+	exitCodeEvaluation   = 4
+	exitCodeMemory       = 5
+)
+
+// memoryCheckInterval is the interval at which the process of an evaluation checks the size of its heap.
+const memoryCheckInterval = 10 * time.Millisecond
+
+// evaluation is what the process of an evaluation reads from its standard input.
+type evaluation struct {
+	Files      map[string]string `json:"files"`
+	Entrypoint string            `json:"entrypoint"`
+	MaxMemory  int64             `json:"maxMemory"`
+}
+
+// IsEvaluatorProcess returns true if the process was started by an Evaluator to evaluate a bundle.
+func IsEvaluatorProcess() bool {
+	return os.Getenv(evaluatorProcessEnv) != ""
+}
+
+// RunEvaluatorProcess evaluates the bundle read from the standard input and writes the output of the evaluation to
+// the standard output, or the error to the standard error. It returns the exit code of the process.
+func RunEvaluatorProcess() int {
+	var in evaluation
+	if err := json.NewDecoder(os.Stdin).Decode(&in); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the bundle: %s", err)
+		return exitCodeInvalidInput
+	}
+	if in.MaxMemory > 0 {
+		go limitMemory(in.MaxMemory)
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.MaxStack = maxStack
+	vm.Importer(newBundleImporter(in.Files))
+	output, err := vm.EvaluateSnippet(in.Entrypoint, in.Files[in.Entrypoint])
+	if err != nil {
+		fmt.Fprint(os.Stderr, err.Error())
+		return exitCodeEvaluation
+	}
+	if _, err := os.Stdout.WriteString(output); err != nil {
+		return exitCodeInvalidInput
+	}
+	return 0
+}
+
+// limitMemory exits the process once its heap is larger than max bytes.
+func limitMemory(max int64) {
+	var stats runtime.MemStats
+	for range time.Tick(memoryCheckInterval) {
+		runtime.ReadMemStats(&stats)
+		if stats.HeapAlloc > uint64(max) {
+			os.Exit(exitCodeMemory)
+		}
+	}
+}
+
+// run runs the evaluation in a child process, which is killed when the context is done, and returns its output.
+func (e *Evaluator) run(ctx context.Context, in evaluation) ([]byte, error) {
+	executable := e.Executable
+	if executable == "" {
+		var err error
+		if executable, err = os.Executable(); err != nil {
+			return nil, fmt.Errorf("failed to find the executable of the jsonnet evaluation: %w", err)
+		}
+	}
+	input, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, executable)
+	// the evaluation gets nothing from the environment of Grafana, and a single CPU
+	cmd.Env = []string{evaluatorProcessEnv + "=true", "GOMAXPROCS=1"}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrEvaluationTimeout, e.Timeout)
+		}
+		return nil, ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.ExitCode() {
+		case exitCodeEvaluation:
+			return nil, fmt.Errorf("%w: %s", ErrEvaluation, stderr.String())
+		case exitCodeMemory:
+			return nil, fmt.Errorf("%w: the evaluation used more than %d bytes of memory", ErrEvaluation, in.MaxMemory)
+		}
+	}
+	if err != nil {
+		// only the first line of a crash of the process is of interest
+		msg := strings.SplitN(strings.TrimSpace(stderr.String()), "\n", 2)[0]
+		return nil, fmt.Errorf("failed to run the jsonnet evaluation: %w: %s", err, msg)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboardimport"
+	"github.com/grafana/grafana/pkg/services/dashboardimport/jsonnet"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ImportJsonnet evaluates the jsonnet bundle of the request and saves the dashboards it produces as provisioned
+// dashboards, so that they can only be changed by importing the bundle again. The dashboards must have a uid, by
+// which they are updated. Dashboards that were not imported from jsonnet are only replaced if the request overwrites
+// them. All the dashboards are checked against the permissions of the user before any is saved.
+func (s *ImportDashboardService) ImportJsonnet(ctx context.Context, req *dashboardimport.ImportJsonnetRequest) (*dashboardimport.ImportJsonnetResponse, error) {
+	evaluated, err := s.jsonnetEvaluator.Evaluate(ctx, jsonnet.Bundle{Files: req.Files, Entrypoint: req.Entrypoint})
+	if err != nil {
+		return nil, err
+	}
+
+	type provisionedDashboard struct {
+		dto          *dashboards.SaveDashboardDTO
+		provisioning *models.DashboardProvisioning
+	}
+	toSave := make([]provisionedDashboard, 0, len(evaluated))
+	uids := make(map[string]string, len(evaluated))
+	for _, d := range evaluated {
+		externalID := req.Entrypoint
+		if d.Key != "" {
+			externalID = req.Entrypoint + "#" + d.Key
+		}
+		dash := models.NewDashboardFromJson(d.Data)
+		if dash.Uid == "" {
+			return nil, fmt.Errorf("%w: %s has no uid, which is required to update it by later imports", jsonnet.ErrInvalidDashboard, externalID)
+		}
+		if other, ok := uids[dash.Uid]; ok {
+			return nil, fmt.Errorf("%w: %s and %s have the same uid %q", jsonnet.ErrInvalidDashboard, other, externalID, dash.Uid)
+		}
+		uids[dash.Uid] = externalID
+
+		provisioned, err := s.dashboardProvisioningService.GetProvisionedDashboardDataByDashboardUID(req.User.OrgId, dash.Uid)
+		if err != nil {
+			return nil, err
+		}
+		if provisioned != nil && provisioned.Name != dashboards.JsonnetProvisionerName {
+			return nil, dashboards.ErrDashboardCannotSaveProvisionedDashboard
+		}
+
+		content, err := d.Data.Encode()
+		if err != nil {
+			return nil, err
+		}
+		checkSum, err := util.Md5SumString(string(content))
+		if err != nil {
+			return nil, err
+		}
+
+		dash.FolderId = req.FolderId
+		dto := &dashboards.SaveDashboardDTO{
+			OrgId:     req.User.OrgId,
+			Dashboard: dash,
+			// the bundle is the source of truth of the dashboards imported from it before
+			Overwrite: req.Overwrite || provisioned != nil,
+			User:      req.User,
+		}
+		// provisioned dashboards are saved with the permissions of the provisioner, so the permissions of the user
+		// are checked beforehand
+		if _, err := s.dashboardService.BuildSaveDashboardCommand(ctx, dto, true, false); err != nil {
+			return nil, err
+		}
+
+		toSave = append(toSave, provisionedDashboard{
+			dto: dto,
+			provisioning: &models.DashboardProvisioning{
+				Name:       dashboards.JsonnetProvisionerName,
+				ExternalId: externalID,
+				CheckSum:   checkSum,
+				Updated:    time.Now().Unix(),
+			},
+		})
+	}
+
+	resp := &dashboardimport.ImportJsonnetResponse{
+		Dashboards: make([]dashboardimport.ImportDashboardResponse, 0, len(toSave)),
+	}
+	for _, d := range toSave {
+		savedDashboard, err := s.dashboardProvisioningService.SaveProvisionedDashboard(ctx, d.dto, d.provisioning)
+		if err != nil {
+			return nil, err
+		}
+		resp.Dashboards = append(resp.Dashboards, dashboardimport.ImportDashboardResponse{
+			UID:              savedDashboard.Uid,
+			Title:            savedDashboard.Title,
+			Path:             d.provisioning.ExternalId,
+			Revision:         savedDashboard.Data.Get("revision").MustInt64(1),
+			FolderId:         savedDashboard.FolderId,
+			ImportedUri:      "db/" + savedDashboard.Slug,
+			ImportedUrl:      savedDashboard.GetUrl(),
+			ImportedRevision: savedDashboard.Data.Get("revision").MustInt64(1),
+			Imported:         true,
+			DashboardId:      savedDashboard.Id,
+			Slug:             savedDashboard.Slug,
+		})
+	}
+	return resp, nil
+}
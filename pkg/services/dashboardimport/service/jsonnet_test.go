@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboardimport"
+	"github.com/grafana/grafana/pkg/services/dashboardimport/jsonnet"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestMain(m *testing.M) {
+	// the evaluations of the bundles run the test binary
+	if jsonnet.IsEvaluatorProcess() {
+		os.Exit(jsonnet.RunEvaluatorProcess())
+	}
+	os.Exit(m.Run())
+}
+
+func TestImportJsonnet(t *testing.T) {
+	user := &models.SignedInUser{UserId: 2, OrgRole: models.ROLE_EDITOR, OrgId: 3}
+	bundle := map[string]string{
+		"main.jsonnet": `{
+			overview: { uid: "overview", title: "Overview", panels: [] },
+			nodes: [{ uid: "node", title: "Node", panels: [] }],
+		}`,
+	}
+
+	createSut := func(provisioned map[string]*models.DashboardProvisioning) (*ImportDashboardService, *dashboardProvisioningServiceMock) {
+		provisioning := &dashboardProvisioningServiceMock{provisioned: provisioned}
+		return &ImportDashboardService{
+			dashboardService:             &dashboardServiceMock{},
+			dashboardProvisioningService: provisioning,
+			jsonnetEvaluator:             &jsonnet.Evaluator{},
+		}, provisioning
+	}
+
+	t.Run("saves the dashboards as provisioned dashboards", func(t *testing.T) {
+		s, provisioning := createSut(nil)
+
+		resp, err := s.ImportJsonnet(context.Background(), &dashboardimport.ImportJsonnetRequest{
+			Files:      bundle,
+			Entrypoint: "main.jsonnet",
+			FolderId:   5,
+			User:       user,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, resp.Dashboards, 2)
+		require.Equal(t, "node", resp.Dashboards[0].UID)
+		require.Equal(t, "main.jsonnet#nodes[0]", resp.Dashboards[0].Path)
+		require.Equal(t, "overview", resp.Dashboards[1].UID)
+		require.Equal(t, "main.jsonnet#overview", resp.Dashboards[1].Path)
+
+		require.Len(t, provisioning.saved, 2)
+		for _, saved := range provisioning.saved {
+			require.Equal(t, dashboards.JsonnetProvisionerName, saved.provisioning.Name)
+			require.NotEmpty(t, saved.provisioning.CheckSum)
+			require.Equal(t, int64(3), saved.dto.OrgId)
+			require.Equal(t, int64(5), saved.dto.Dashboard.FolderId)
+		}
+	})
+
+	t.Run("checks the permissions of the user before saving", func(t *testing.T) {
+		s, provisioning := createSut(nil)
+		var checkedUser *models.SignedInUser
+		s.dashboardService = &dashboardServiceMock{
+			buildSaveDashboardCommandFunc: func(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*models.SaveDashboardCommand, error) {
+				checkedUser = dto.User
+				return nil, dashboards.ErrDashboardUpdateAccessDenied
+			},
+		}
+
+		_, err := s.ImportJsonnet(context.Background(), &dashboardimport.ImportJsonnetRequest{Files: bundle, Entrypoint: "main.jsonnet", User: user})
+
+		require.ErrorIs(t, err, dashboards.ErrDashboardUpdateAccessDenied)
+		require.Equal(t, user, checkedUser)
+		require.Empty(t, provisioning.saved)
+	})
+
+	t.Run("rejects dashboards provisioned from files", func(t *testing.T) {
+		s, provisioning := createSut(map[string]*models.DashboardProvisioning{
+			"overview": {Name: "files"},
+		})
+
+		_, err := s.ImportJsonnet(context.Background(), &dashboardimport.ImportJsonnetRequest{Files: bundle, Entrypoint: "main.jsonnet", User: user})
+
+		require.ErrorIs(t, err, dashboards.ErrDashboardCannotSaveProvisionedDashboard)
+		require.Empty(t, provisioning.saved)
+	})
+
+	t.Run("updates dashboards imported from jsonnet before", func(t *testing.T) {
+		s, provisioning := createSut(map[string]*models.DashboardProvisioning{
+			"overview": {Name: dashboards.JsonnetProvisionerName},
+		})
+
+		_, err := s.ImportJsonnet(context.Background(), &dashboardimport.ImportJsonnetRequest{Files: bundle, Entrypoint: "main.jsonnet", User: user})
+
+		require.NoError(t, err)
+		require.Len(t, provisioning.saved, 2)
+		for _, saved := range provisioning.saved {
+			require.Equal(t, saved.dto.Dashboard.Uid == "overview", saved.dto.Overwrite)
+		}
+	})
+
+	t.Run("rejects dashboards without uid", func(t *testing.T) {
+		s, provisioning := createSut(nil)
+
+		_, err := s.ImportJsonnet(context.Background(), &dashboardimport.ImportJsonnetRequest{
+			Files:      map[string]string{"main.jsonnet": `{ title: "Overview" }`},
+			Entrypoint: "main.jsonnet",
+			User:       user,
+		})
+
+		require.ErrorIs(t, err, jsonnet.ErrInvalidDashboard)
+		require.Empty(t, provisioning.saved)
+	})
+
+	t.Run("rejects dashboards with the same uid", func(t *testing.T) {
+		s, provisioning := createSut(nil)
+
+		_, err := s.ImportJsonnet(context.Background(), &dashboardimport.ImportJsonnetRequest{
+			Files:      map[string]string{"main.jsonnet": `[{ uid: "a", title: "A" }, { uid: "a", title: "B" }]`},
+			Entrypoint: "main.jsonnet",
+			User:       user,
+		})
+
+		require.ErrorIs(t, err, jsonnet.ErrInvalidDashboard)
+		require.Empty(t, provisioning.saved)
+	})
+}
+
+type savedProvisionedDashboard struct {
+	dto          *dashboards.SaveDashboardDTO
+	provisioning *models.DashboardProvisioning
+}
+
+type dashboardProvisioningServiceMock struct {
+	dashboards.DashboardProvisioningService
+	provisioned map[string]*models.DashboardProvisioning
+	saved       []savedProvisionedDashboard
+}
+
+func (s *dashboardProvisioningServiceMock) GetProvisionedDashboardDataByDashboardUID(orgID int64, dashboardUID string) (*models.DashboardProvisioning, error) {
+	return s.provisioned[dashboardUID], nil
+}
+
+func (s *dashboardProvisioningServiceMock) SaveProvisionedDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO, provisioning *models.DashboardProvisioning) (*models.Dashboard, error) {
+	s.saved = append(s.saved, savedProvisionedDashboard{dto: dto, provisioning: provisioning})
+	dash := *dto.Dashboard
+	dash.Id = int64(len(s.saved))
+	return &dash, nil
+}
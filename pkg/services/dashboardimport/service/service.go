@@ -10,23 +10,33 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboardimport"
 	"github.com/grafana/grafana/pkg/services/dashboardimport/api"
+	"github.com/grafana/grafana/pkg/services/dashboardimport/jsonnet"
 	"github.com/grafana/grafana/pkg/services/dashboardimport/utils"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/librarypanels"
 	"github.com/grafana/grafana/pkg/services/plugindashboards"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(routeRegister routing.RouteRegister,
+func ProvideService(cfg *setting.Cfg, routeRegister routing.RouteRegister,
 	quotaService *quota.QuotaService,
 	pluginDashboardService plugindashboards.Service, pluginStore plugins.Store,
 	libraryPanelService librarypanels.Service, dashboardService dashboards.DashboardService,
+	dashboardProvisioningService dashboards.DashboardProvisioningService,
 	ac accesscontrol.AccessControl,
 ) *ImportDashboardService {
 	s := &ImportDashboardService{
-		pluginDashboardService: pluginDashboardService,
-		dashboardService:       dashboardService,
-		libraryPanelService:    libraryPanelService,
+		pluginDashboardService:       pluginDashboardService,
+		dashboardService:             dashboardService,
+		dashboardProvisioningService: dashboardProvisioningService,
+		libraryPanelService:          libraryPanelService,
+		jsonnetEvaluator: &jsonnet.Evaluator{
+			AllowedLibraries: cfg.JsonnetAllowedLibraries,
+			Timeout:          cfg.JsonnetEvaluationTimeout,
+			MaxConcurrent:    cfg.JsonnetMaxConcurrentEvaluations,
+			MaxMemory:        cfg.JsonnetEvaluationMaxMemory,
+		},
 	}
 
 	dashboardImportAPI := api.New(s, quotaService, pluginStore, ac)
@@ -36,9 +46,11 @@ func ProvideService(routeRegister routing.RouteRegister,
 }
 
 type ImportDashboardService struct {
-	pluginDashboardService plugindashboards.Service
-	dashboardService       dashboards.DashboardService
-	libraryPanelService    librarypanels.Service
+	pluginDashboardService       plugindashboards.Service
+	dashboardService             dashboards.DashboardService
+	dashboardProvisioningService dashboards.DashboardProvisioningService
+	libraryPanelService          librarypanels.Service
+	jsonnetEvaluator             *jsonnet.Evaluator
 }
 
 func (s *ImportDashboardService) ImportDashboard(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error) {
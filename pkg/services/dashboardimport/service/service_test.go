@@ -172,7 +172,16 @@ func (m *pluginDashboardServiceMock) LoadPluginDashboard(ctx context.Context, re
 
 type dashboardServiceMock struct {
 	dashboards.DashboardService
-	importDashboardFunc func(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*models.Dashboard, error)
+	importDashboardFunc           func(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*models.Dashboard, error)
+	buildSaveDashboardCommandFunc func(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*models.SaveDashboardCommand, error)
+}
+
+func (s *dashboardServiceMock) BuildSaveDashboardCommand(ctx context.Context, dto *dashboards.SaveDashboardDTO, shouldValidateAlerts bool, validateProvisionedDashboard bool) (*models.SaveDashboardCommand, error) {
+	if s.buildSaveDashboardCommandFunc != nil {
+		return s.buildSaveDashboardCommandFunc(ctx, dto)
+	}
+
+	return &models.SaveDashboardCommand{}, nil
 }
 
 func (s *dashboardServiceMock) ImportDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*models.Dashboard, error) {
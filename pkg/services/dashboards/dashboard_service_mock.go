@@ -76,6 +76,29 @@ func (_m *FakeDashboardService) FindDashboards(ctx context.Context, query *model
 	return r0, r1
 }
 
+// GetContentViolations provides a mock function with given fields: ctx, user
+func (_m *FakeDashboardService) GetContentViolations(ctx context.Context, user *models.SignedInUser) ([]DashboardContentViolations, error) {
+	ret := _m.Called(ctx, user)
+
+	var r0 []DashboardContentViolations
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SignedInUser) []DashboardContentViolations); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]DashboardContentViolations)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SignedInUser) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDashboard provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) GetDashboard(ctx context.Context, query *models.GetDashboardQuery) error {
 	ret := _m.Called(ctx, query)
@@ -6,6 +6,10 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 )
 
+// JsonnetProvisionerName is the name of the provisioner of the dashboards imported from jsonnet bundles. It is
+// reserved, so that the dashboards are not taken over or cleaned up by the file provisioning.
+const JsonnetProvisionerName = "jsonnet"
+
 type SaveDashboardDTO struct {
 	OrgId     int64
 	UpdatedAt time.Time
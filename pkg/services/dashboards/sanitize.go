@@ -0,0 +1,179 @@
+package dashboards
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+type ContentViolationKind string
+
+const (
+	// ContentViolationScript is a script element in the HTML of a text panel.
+	ContentViolationScript ContentViolationKind = "script"
+	// ContentViolationEventHandler is an event handler attribute, such as onclick, in the HTML of a text panel.
+	ContentViolationEventHandler ContentViolationKind = "event-handler"
+	// ContentViolationJavascriptURL is a javascript: URL in a link or in the HTML of a text panel.
+	ContentViolationJavascriptURL ContentViolationKind = "javascript-url"
+)
+
+// ContentViolation is dangerous content found in a dashboard.
+type ContentViolation struct {
+	// Path is the location of the content in the dashboard JSON, e.g. panels[2].options.content.
+	Path    string               `json:"path"`
+	Kind    ContentViolationKind `json:"kind"`
+	PanelID int64                `json:"panelId,omitempty"`
+}
+
+// DashboardContentViolations are the content violations found in a dashboard.
+type DashboardContentViolations struct {
+	UID        string             `json:"uid"`
+	Title      string             `json:"title"`
+	FolderUID  string             `json:"folderUid,omitempty"`
+	Violations []ContentViolation `json:"violations"`
+}
+
+// SanitizeDashboardContent looks for script-bearing HTML in the text panels of a dashboard and for javascript:
+// URLs in its links and the links of its panels. If strip is true, the dangerous content is removed from data.
+func SanitizeDashboardContent(data *simplejson.Json, strip bool) []ContentViolation {
+	s := &contentSanitizer{strip: strip}
+	s.links(data.Get("links"), "links", 0)
+	s.panels(data.Get("panels"), "panels")
+	// dashboards with the schema before 5.0 keep their panels in rows
+	for i := range data.Get("rows").MustArray() {
+		s.panels(data.Get("rows").GetIndex(i).Get("panels"), fmt.Sprintf("rows[%d].panels", i))
+	}
+	return s.violations
+}
+
+type contentSanitizer struct {
+	strip      bool
+	violations []ContentViolation
+}
+
+func (s *contentSanitizer) add(path string, kind ContentViolationKind, panelID int64) {
+	s.violations = append(s.violations, ContentViolation{Path: path, Kind: kind, PanelID: panelID})
+}
+
+func (s *contentSanitizer) panels(panels *simplejson.Json, path string) {
+	for i := range panels.MustArray() {
+		panel := panels.GetIndex(i)
+		panelPath := fmt.Sprintf("%s[%d]", path, i)
+		id := panel.Get("id").MustInt64()
+
+		if panel.Get("type").MustString() == "text" {
+			s.html(panel.Get("options"), "content", panelPath+".options.content", id)
+			// text panels before 7.1 keep their content at the top level
+			s.html(panel, "content", panelPath+".content", id)
+		}
+		s.links(panel.Get("links"), panelPath+".links", id)
+		s.links(panel.GetPath("fieldConfig", "defaults", "links"), panelPath+".fieldConfig.defaults.links", id)
+		// collapsed rows keep their panels
+		s.panels(panel.Get("panels"), panelPath+".panels")
+	}
+}
+
+func (s *contentSanitizer) links(links *simplejson.Json, path string, panelID int64) {
+	for i := range links.MustArray() {
+		link := links.GetIndex(i)
+		if !isJavascriptURL(link.Get("url").MustString()) {
+			continue
+		}
+		s.add(fmt.Sprintf("%s[%d].url", path, i), ContentViolationJavascriptURL, panelID)
+		if s.strip {
+			link.Set("url", "")
+		}
+	}
+}
+
+func (s *contentSanitizer) html(obj *simplejson.Json, key string, path string, panelID int64) {
+	content := obj.Get(key).MustString()
+	if content == "" {
+		return
+	}
+	kinds, sanitized := sanitizeHTML(content)
+	for _, kind := range kinds {
+		s.add(path, kind, panelID)
+	}
+	if s.strip && len(kinds) > 0 {
+		obj.Set(key, sanitized)
+	}
+}
+
+// sanitizeHTML removes script elements, event handler attributes and javascript: URLs from an HTML fragment.
+// It returns the kinds of dangerous content that were found and the fragment without them. As the fragment
+// is rendered again, it is only meant to be used when something was found.
+func sanitizeHTML(content string) ([]ContentViolationKind, string) {
+	root := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(content), root)
+	if err != nil {
+		return nil, content
+	}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+
+	found := make(map[ContentViolationKind]bool)
+	sanitizeNode(root, found)
+	if len(found) == 0 {
+		return nil, content
+	}
+
+	var kinds []ContentViolationKind
+	for _, kind := range []ContentViolationKind{ContentViolationScript, ContentViolationEventHandler, ContentViolationJavascriptURL} {
+		if found[kind] {
+			kinds = append(kinds, kind)
+		}
+	}
+	var sb strings.Builder
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&sb, c); err != nil {
+			return kinds, ""
+		}
+	}
+	return kinds, sb.String()
+}
+
+func sanitizeNode(n *html.Node, found map[ContentViolationKind]bool) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode && c.DataAtom == atom.Script {
+			found[ContentViolationScript] = true
+			n.RemoveChild(c)
+			c = next
+			continue
+		}
+		if c.Type == html.ElementNode {
+			attrs := c.Attr[:0]
+			for _, a := range c.Attr {
+				switch {
+				case strings.HasPrefix(strings.ToLower(a.Key), "on"):
+					found[ContentViolationEventHandler] = true
+				case isJavascriptURL(a.Val):
+					found[ContentViolationJavascriptURL] = true
+				default:
+					attrs = append(attrs, a)
+				}
+			}
+			c.Attr = attrs
+		}
+		sanitizeNode(c, found)
+		c = next
+	}
+}
+
+// isJavascriptURL returns true if u uses the javascript: scheme. Like browsers, it ignores the whitespace
+// and control characters that can be used to hide the scheme.
+func isJavascriptURL(u string) bool {
+	u = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, u)
+	return strings.HasPrefix(strings.ToLower(u), "javascript:")
+}
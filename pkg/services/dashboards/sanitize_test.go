@@ -0,0 +1,65 @@
+package dashboards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestSanitizeDashboardContent(t *testing.T) {
+	newDashboard := func(t *testing.T) *simplejson.Json {
+		t.Helper()
+		data, err := simplejson.NewJson([]byte(`{
+			"links": [{"title": "docs", "url": "https://grafana.com"}, {"title": "bad", "url": " JavaScript:alert(1)"}],
+			"panels": [
+				{"id": 1, "type": "text", "options": {"mode": "html", "content": "<p onclick=\"alert(1)\">hi</p><script>alert(2)</script>"}},
+				{"id": 2, "type": "text", "options": {"mode": "markdown", "content": "# Safe"}},
+				{"id": 3, "type": "row", "panels": [
+					{"id": 4, "type": "timeseries", "fieldConfig": {"defaults": {"links": [{"url": "javascript:alert(3)"}]}}}
+				]}
+			],
+			"rows": [{"panels": [{"id": 5, "type": "text", "content": "<a href=\"javascript:alert(4)\">link</a>"}]}]
+		}`))
+		require.NoError(t, err)
+		return data
+	}
+
+	expected := []ContentViolation{
+		{Path: "links[1].url", Kind: ContentViolationJavascriptURL},
+		{Path: "panels[0].options.content", Kind: ContentViolationScript, PanelID: 1},
+		{Path: "panels[0].options.content", Kind: ContentViolationEventHandler, PanelID: 1},
+		{Path: "panels[2].panels[0].fieldConfig.defaults.links[0].url", Kind: ContentViolationJavascriptURL, PanelID: 4},
+		{Path: "rows[0].panels[0].content", Kind: ContentViolationJavascriptURL, PanelID: 5},
+	}
+
+	t.Run("flags dangerous content without changing the dashboard", func(t *testing.T) {
+		data := newDashboard(t)
+		before, err := data.Encode()
+		require.NoError(t, err)
+
+		violations := SanitizeDashboardContent(data, false)
+
+		require.Equal(t, expected, violations)
+		after, err := data.Encode()
+		require.NoError(t, err)
+		require.JSONEq(t, string(before), string(after))
+	})
+
+	t.Run("strips dangerous content", func(t *testing.T) {
+		data := newDashboard(t)
+
+		violations := SanitizeDashboardContent(data, true)
+
+		require.Equal(t, expected, violations)
+		require.Equal(t, "https://grafana.com", data.Get("links").GetIndex(0).Get("url").MustString())
+		require.Equal(t, "", data.Get("links").GetIndex(1).Get("url").MustString())
+		require.Equal(t, "<p>hi</p>", data.Get("panels").GetIndex(0).GetPath("options", "content").MustString())
+		require.Equal(t, "# Safe", data.Get("panels").GetIndex(1).GetPath("options", "content").MustString())
+		require.Equal(t, "", data.Get("panels").GetIndex(2).Get("panels").GetIndex(0).GetPath("fieldConfig", "defaults", "links").GetIndex(0).Get("url").MustString())
+		require.Equal(t, "<a>link</a>", data.Get("rows").GetIndex(0).Get("panels").GetIndex(0).Get("content").MustString())
+
+		require.Empty(t, SanitizeDashboardContent(data, false))
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -39,6 +40,7 @@ type DashboardServiceImpl struct {
 	folderPermissions    accesscontrol.FolderPermissionsService
 	dashboardPermissions accesscontrol.DashboardPermissionsService
 	ac                   accesscontrol.AccessControl
+	contentSanitization  setting.DashboardSanitizationPolicy
 }
 
 func ProvideDashboardService(
@@ -58,6 +60,7 @@ func ProvideDashboardService(
 		folderPermissions:    folderPermissionsService,
 		dashboardPermissions: dashboardPermissionsService,
 		ac:                   ac,
+		contentSanitization:  cfg.DashboardContentSanitization,
 	}
 }
 
@@ -104,6 +107,8 @@ func (dr *DashboardServiceImpl) BuildSaveDashboardCommand(ctx context.Context, d
 		return nil, err
 	}
 
+	dr.sanitizeDashboardContent(dash)
+
 	if shouldValidateAlerts {
 		dashAlertInfo := alerting.DashAlertInfo{Dash: dash, User: dto.User, OrgID: dash.OrgId}
 		if err := dr.dashAlertExtractor.ValidateAlerts(ctx, dashAlertInfo); err != nil {
@@ -181,6 +186,19 @@ func (dr *DashboardServiceImpl) DeleteOrphanedProvisionedDashboards(ctx context.
 	return dr.dashboardStore.DeleteOrphanedProvisionedDashboards(ctx, cmd)
 }
 
+// sanitizeDashboardContent applies the content sanitization policy to a dashboard that is about to be saved.
+func (dr *DashboardServiceImpl) sanitizeDashboardContent(dash *models.Dashboard) {
+	if dr.contentSanitization == "" || dr.contentSanitization == setting.DashboardSanitizationOff {
+		return
+	}
+
+	strip := dr.contentSanitization == setting.DashboardSanitizationStrip
+	for _, v := range dashboards.SanitizeDashboardContent(dash.Data, strip) {
+		dr.log.Warn("Dangerous content found in dashboard", "dashboardUid", dash.Uid, "dashboardTitle", dash.Title,
+			"path", v.Path, "kind", v.Kind, "stripped", strip)
+	}
+}
+
 func validateDashboardRefreshInterval(dash *models.Dashboard) error {
 	if setting.MinRefreshInterval == "" {
 		return nil
@@ -509,6 +527,63 @@ func (dr *DashboardServiceImpl) FindDashboards(ctx context.Context, query *model
 	return dr.dashboardStore.FindDashboards(ctx, query)
 }
 
+// contentViolationsBatchSize is the number of dashboards loaded at once to look for dangerous content.
+const contentViolationsBatchSize = 100
+
+func (dr *DashboardServiceImpl) GetContentViolations(ctx context.Context, user *models.SignedInUser) ([]dashboards.DashboardContentViolations, error) {
+	result := make([]dashboards.DashboardContentViolations, 0)
+	seen := make(map[int64]bool)
+	for page := int64(1); ; page++ {
+		hits, err := dr.FindDashboards(ctx, &models.FindPersistedDashboardsQuery{
+			SignedInUser: user,
+			OrgId:        user.OrgId,
+			Type:         searchstore.TypeDashboard,
+			Permission:   models.PERMISSION_EDIT,
+			Limit:        contentViolationsBatchSize,
+			Page:         page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(hits) == 0 {
+			break
+		}
+
+		ids := make([]int64, 0, len(hits))
+		folderUIDs := make(map[int64]string, len(hits))
+		for _, hit := range hits {
+			// a dashboard is returned once per tag
+			if seen[hit.ID] {
+				continue
+			}
+			seen[hit.ID] = true
+			ids = append(ids, hit.ID)
+			folderUIDs[hit.ID] = hit.FolderUID
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		query := &models.GetDashboardsQuery{DashboardIds: ids}
+		if err := dr.dashboardStore.GetDashboards(ctx, query); err != nil {
+			return nil, err
+		}
+		for _, dash := range query.Result {
+			violations := dashboards.SanitizeDashboardContent(dash.Data, false)
+			if len(violations) == 0 {
+				continue
+			}
+			result = append(result, dashboards.DashboardContentViolations{
+				UID:        dash.Uid,
+				Title:      dash.Title,
+				FolderUID:  folderUIDs[dash.Id],
+				Violations: violations,
+			})
+		}
+	}
+	return result, nil
+}
+
 func (dr *DashboardServiceImpl) SearchDashboards(ctx context.Context, query *models.FindPersistedDashboardsQuery) error {
 	res, err := dr.FindDashboards(ctx, query)
 	if err != nil {
@@ -204,3 +204,65 @@ func TestIntegrationDashboardService(t *testing.T) {
 		})
 	})
 }
+
+func TestDashboardContentSanitization(t *testing.T) {
+	origNewDashboardGuardian := guardian.New
+	defer func() { guardian.New = origNewDashboardGuardian }()
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+
+	newDashboard := func() *models.Dashboard {
+		dash := models.NewDashboard("Dash")
+		dash.Data.Set("links", []interface{}{map[string]interface{}{"url": "javascript:alert(1)"}})
+		return dash
+	}
+
+	t.Run("dangerous content is kept when it is flagged", func(t *testing.T) {
+		fakeStore := dashboards.FakeDashboardStore{}
+		fakeStore.On("ValidateDashboardBeforeSave", mock.Anything, mock.Anything).Return(false, nil).Once()
+		service := &DashboardServiceImpl{log: log.New("test.logger"), dashboardStore: &fakeStore, contentSanitization: setting.DashboardSanitizationFlag}
+
+		dto := &dashboards.SaveDashboardDTO{Dashboard: newDashboard(), User: &models.SignedInUser{UserId: 1}}
+		cmd, err := service.BuildSaveDashboardCommand(context.Background(), dto, false, false)
+		require.NoError(t, err)
+		require.Equal(t, "javascript:alert(1)", cmd.Dashboard.Get("links").GetIndex(0).Get("url").MustString())
+	})
+
+	t.Run("dangerous content is stripped before the dashboard is saved", func(t *testing.T) {
+		fakeStore := dashboards.FakeDashboardStore{}
+		fakeStore.On("ValidateDashboardBeforeSave", mock.Anything, mock.Anything).Return(false, nil).Once()
+		service := &DashboardServiceImpl{log: log.New("test.logger"), dashboardStore: &fakeStore, contentSanitization: setting.DashboardSanitizationStrip}
+
+		dto := &dashboards.SaveDashboardDTO{Dashboard: newDashboard(), User: &models.SignedInUser{UserId: 1}}
+		cmd, err := service.BuildSaveDashboardCommand(context.Background(), dto, false, false)
+		require.NoError(t, err)
+		require.Equal(t, "", cmd.Dashboard.Get("links").GetIndex(0).Get("url").MustString())
+	})
+
+	t.Run("violations are reported for the dashboards the user can edit", func(t *testing.T) {
+		fakeStore := dashboards.FakeDashboardStore{}
+		defer fakeStore.AssertExpectations(t)
+		service := &DashboardServiceImpl{log: log.New("test.logger"), dashboardStore: &fakeStore}
+
+		unsafe := newDashboard()
+		unsafe.SetId(1)
+		unsafe.SetUid("unsafe")
+		safe := models.NewDashboard("Safe")
+		safe.SetId(2)
+		fakeStore.On("FindDashboards", mock.Anything, mock.MatchedBy(func(q *models.FindPersistedDashboardsQuery) bool {
+			return q.Page == 1 && q.Permission == models.PERMISSION_EDIT
+		})).Return([]dashboards.DashboardSearchProjection{{ID: 1, FolderUID: "folder"}, {ID: 1, FolderUID: "folder"}, {ID: 2}}, nil).Once()
+		fakeStore.On("FindDashboards", mock.Anything, mock.Anything).Return([]dashboards.DashboardSearchProjection{}, nil).Once()
+		fakeStore.On("GetDashboards", mock.Anything, &models.GetDashboardsQuery{DashboardIds: []int64{1, 2}}).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.GetDashboardsQuery).Result = []*models.Dashboard{unsafe, safe}
+		}).Return(nil).Once()
+
+		result, err := service.GetContentViolations(context.Background(), &models.SignedInUser{UserId: 1, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []dashboards.DashboardContentViolations{{
+			UID:        "unsafe",
+			Title:      "Dash",
+			FolderUID:  "folder",
+			Violations: []dashboards.ContentViolation{{Path: "links[0].url", Kind: dashboards.ContentViolationJavascriptURL}},
+		}}, result)
+	})
+}
@@ -0,0 +1,95 @@
+package live
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	// ActionChannelRead allows subscribing to the channels of the scope.
+	ActionChannelRead = "live.channel:read"
+	// ActionChannelWrite allows publishing and pushing data to the channels of the scope.
+	ActionChannelWrite = "live.channel:write"
+	// ActionChannelRulesRead allows reading the channel rules whose pattern is in the scope.
+	ActionChannelRulesRead = "live.channel.rules:read"
+	// ActionChannelRulesWrite allows creating, updating and deleting the channel rules whose pattern is in the scope.
+	ActionChannelRulesWrite = "live.channel.rules:write"
+
+	ScopeChannelsRoot = "live.channels"
+)
+
+// ScopeChannelsAll matches every channel and channel rule pattern.
+var ScopeChannelsAll = accesscontrol.GetResourceAllScope(ScopeChannelsRoot)
+
+// ScopeChannel returns the scope of a channel or of the pattern of a channel rule, live.channels:path:<channel>.
+// Roles grant access to a group of channels with a wildcard at the end, e.g. live.channels:path:stream/team-a/*.
+func ScopeChannel(channel string) string {
+	return accesscontrol.Scope(ScopeChannelsRoot, "path", channel)
+}
+
+var (
+	channelsReaderRole = accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name:        accesscontrol.FixedRolePrefix + "live.channels:reader",
+			DisplayName: "Live channels reader",
+			Description: "Subscribe to all Live channels.",
+			Group:       "Live",
+			Permissions: []accesscontrol.Permission{
+				{Action: ActionChannelRead, Scope: ScopeChannelsAll},
+			},
+		},
+		Grants: []string{string(models.ROLE_VIEWER)},
+	}
+
+	// channelsWriterRole is granted to viewers because the channels keep checking the role of the publisher
+	// themselves, e.g. publishing to a channel with a rule requires the admin role by default.
+	channelsWriterRole = accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name:        accesscontrol.FixedRolePrefix + "live.channels:writer",
+			DisplayName: "Live channels writer",
+			Description: "Subscribe, publish and push data to all Live channels.",
+			Group:       "Live",
+			Permissions: accesscontrol.ConcatPermissions(channelsReaderRole.Role.Permissions, []accesscontrol.Permission{
+				{Action: ActionChannelWrite, Scope: ScopeChannelsAll},
+			}),
+		},
+		Grants: []string{string(models.ROLE_VIEWER)},
+	}
+
+	channelRulesWriterRole = accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name:        accesscontrol.FixedRolePrefix + "live.channel.rules:writer",
+			DisplayName: "Live channel rules writer",
+			Description: "Read, create, update and delete all Live channel rules.",
+			Group:       "Live",
+			Permissions: []accesscontrol.Permission{
+				{Action: ActionChannelRulesRead, Scope: ScopeChannelsAll},
+				{Action: ActionChannelRulesWrite, Scope: ScopeChannelsAll},
+			},
+		},
+		Grants: []string{string(models.ROLE_ADMIN)},
+	}
+)
+
+func declareFixedRoles(ac accesscontrol.AccessControl) error {
+	return ac.DeclareFixedRoles(channelsReaderRole, channelsWriterRole, channelRulesWriterRole)
+}
+
+// CanAccessChannel returns true if the user can perform the action on the channel, or on the channel rule with the
+// pattern. It always returns true when access control is disabled, leaving the checks to the channels.
+func (g *GrafanaLive) CanAccessChannel(ctx context.Context, user *models.SignedInUser, action string, channel string) (bool, error) {
+	if g.accessControl == nil || g.accessControl.IsDisabled() {
+		return true, nil
+	}
+	return g.accessControl.Evaluate(ctx, user, accesscontrol.EvalPermission(action, ScopeChannel(channel)))
+}
+
+// CanPushToStream returns true if the user can push data to the managed stream, which requires write access to all
+// of its channels.
+func (g *GrafanaLive) CanPushToStream(ctx context.Context, user *models.SignedInUser, streamID string) (bool, error) {
+	return g.CanAccessChannel(ctx, user, ActionChannelWrite, live.ScopeStream+"/"+streamID+"/*")
+}
@@ -0,0 +1,110 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	accesscontrolmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestCanAccessChannel(t *testing.T) {
+	user := &models.SignedInUser{OrgId: 1, UserId: 1}
+	g := &GrafanaLive{
+		accessControl: accesscontrolmock.New().WithPermissions([]accesscontrol.Permission{
+			{Action: ActionChannelRead, Scope: ScopeChannel("stream/team-a/*")},
+			{Action: ActionChannelWrite, Scope: ScopeChannel("stream/team-a/cpu")},
+		}),
+	}
+
+	testCases := []struct {
+		action   string
+		channel  string
+		expected bool
+	}{
+		{action: ActionChannelRead, channel: "stream/team-a/cpu", expected: true},
+		{action: ActionChannelRead, channel: "stream/team-a/memory", expected: true},
+		{action: ActionChannelRead, channel: "stream/team-b/cpu", expected: false},
+		{action: ActionChannelWrite, channel: "stream/team-a/cpu", expected: true},
+		{action: ActionChannelWrite, channel: "stream/team-a/memory", expected: false},
+	}
+	for _, tc := range testCases {
+		allowed, err := g.CanAccessChannel(context.Background(), user, tc.action, tc.channel)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, allowed, "%s on %s", tc.action, tc.channel)
+	}
+
+	t.Run("pushing to a stream requires write access to all of its channels", func(t *testing.T) {
+		allowed, err := g.CanPushToStream(context.Background(), user, "team-a")
+		require.NoError(t, err)
+		require.False(t, allowed)
+
+		g := &GrafanaLive{
+			accessControl: accesscontrolmock.New().WithPermissions([]accesscontrol.Permission{
+				{Action: ActionChannelWrite, Scope: ScopeChannel("stream/team-a/*")},
+			}),
+		}
+		allowed, err = g.CanPushToStream(context.Background(), user, "team-a")
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+
+	t.Run("every channel can be accessed when access control is disabled", func(t *testing.T) {
+		g := &GrafanaLive{accessControl: accesscontrolmock.New().WithDisabled()}
+		allowed, err := g.CanAccessChannel(context.Background(), user, ActionChannelWrite, "stream/team-b/cpu")
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+}
+
+func TestChannelRulesHTTP(t *testing.T) {
+	g := &GrafanaLive{
+		accessControl: accesscontrolmock.New().WithPermissions([]accesscontrol.Permission{
+			{Action: ActionChannelRulesRead, Scope: ScopeChannel("stream/team-a/*")},
+			{Action: ActionChannelRulesWrite, Scope: ScopeChannel("stream/team-a/*")},
+		}),
+		pipelineStorage: &DryRunRuleStorage{
+			ChannelRules: []pipeline.ChannelRule{
+				{OrgId: 1, Pattern: "stream/team-a/cpu"},
+				{OrgId: 1, Pattern: "stream/team-b/cpu"},
+			},
+		},
+	}
+	reqContext := func(body string) *models.ReqContext {
+		return &models.ReqContext{
+			Context:      &web.Context{Req: httptest.NewRequest("POST", "/api/live/channel-rules", strings.NewReader(body))},
+			SignedInUser: &models.SignedInUser{OrgId: 1, UserId: 1},
+		}
+	}
+
+	t.Run("only the rules with a pattern in the scope of the user are listed", func(t *testing.T) {
+		resp := g.HandleChannelRulesListHTTP(reqContext(""))
+
+		require.Equal(t, 200, resp.Status())
+		var result struct {
+			Rules []pipeline.ChannelRule `json:"rules"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Len(t, result.Rules, 1)
+		require.Equal(t, "stream/team-a/cpu", result.Rules[0].Pattern)
+	})
+
+	t.Run("rules with a pattern out of the scope of the user cannot be changed", func(t *testing.T) {
+		resp := g.HandleChannelRulesPostHTTP(reqContext(`{"pattern": "stream/team-b/memory"}`))
+		require.Equal(t, 403, resp.Status())
+
+		resp = g.HandleChannelRulesPutHTTP(reqContext(`{"pattern": "stream/team-b/cpu"}`))
+		require.Equal(t, 403, resp.Status())
+
+		resp = g.HandleChannelRulesDeleteHTTP(reqContext(`{"pattern": "stream/team-b/cpu"}`))
+		require.Equal(t, 403, resp.Status())
+	})
+}
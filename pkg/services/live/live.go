@@ -89,10 +89,15 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 			Features: make(map[string]models.ChannelHandlerFactory),
 		},
 		usageStatsService: usageStatsService,
+		accessControl:     accessControl,
 	}
 
 	logger.Debug("GrafanaLive initialization", "ha", g.IsHA())
 
+	if err := declareFixedRoles(accessControl); err != nil {
+		return nil, err
+	}
+
 	// We use default config here as starting point. Default config contains
 	// reasonable values for available options.
 	scfg := centrifuge.DefaultConfig
@@ -365,16 +370,38 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	g.pushWebsocketHandler = func(ctx *models.ReqContext) {
 		user := ctx.SignedInUser
+		streamID := web.Params(ctx.Req)[":streamId"]
+		allowed, err := g.CanPushToStream(ctx.Req.Context(), user, streamID)
+		if err != nil {
+			logger.Error("Error checking channel permissions", "error", err, "streamId", streamID)
+			ctx.Resp.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			ctx.Resp.WriteHeader(http.StatusForbidden)
+			return
+		}
 		newCtx := livecontext.SetContextSignedUser(ctx.Req.Context(), user)
-		newCtx = livecontext.SetContextStreamID(newCtx, web.Params(ctx.Req)[":streamId"])
+		newCtx = livecontext.SetContextStreamID(newCtx, streamID)
 		r := ctx.Req.WithContext(newCtx)
 		pushWSHandler.ServeHTTP(ctx.Resp, r)
 	}
 
 	g.pushPipelineWebsocketHandler = func(ctx *models.ReqContext) {
 		user := ctx.SignedInUser
+		channelID := web.Params(ctx.Req)["*"]
+		allowed, err := g.CanAccessChannel(ctx.Req.Context(), user, ActionChannelWrite, channelID)
+		if err != nil {
+			logger.Error("Error checking channel permissions", "error", err, "channel", channelID)
+			ctx.Resp.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			ctx.Resp.WriteHeader(http.StatusForbidden)
+			return
+		}
 		newCtx := livecontext.SetContextSignedUser(ctx.Req.Context(), user)
-		newCtx = livecontext.SetContextChannelID(newCtx, web.Params(ctx.Req)["*"])
+		newCtx = livecontext.SetContextChannelID(newCtx, channelID)
 		r := ctx.Req.WithContext(newCtx)
 		pushPipelineWSHandler.ServeHTTP(ctx.Resp, r)
 	}
@@ -435,6 +462,8 @@ type GrafanaLive struct {
 
 	usageStatsService usagestats.Service
 	usageStats        usageStats
+
+	accessControl accesscontrol.AccessControl
 }
 
 func (g *GrafanaLive) getStreamPlugin(ctx context.Context, pluginID string) (backend.StreamHandler, error) {
@@ -633,6 +662,17 @@ func (g *GrafanaLive) handleOnSubscribe(ctx context.Context, client *centrifuge.
 		return centrifuge.SubscribeReply{}, centrifuge.ErrorPermissionDenied
 	}
 
+	allowed, err := g.CanAccessChannel(client.Context(), user, ActionChannelRead, channel)
+	if err != nil {
+		logger.Error("Error checking channel permissions", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
+		return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
+	}
+	if !allowed {
+		// using HTTP error codes for WS errors too.
+		code, text := subscribeStatusToHTTPError(backend.SubscribeStreamStatusPermissionDenied)
+		return centrifuge.SubscribeReply{}, &centrifuge.Error{Code: uint32(code), Message: text}
+	}
+
 	var reply models.SubscribeReply
 	var status backend.SubscribeStreamStatus
 	var ruleFound bool
@@ -733,6 +773,17 @@ func (g *GrafanaLive) handleOnPublish(ctx context.Context, client *centrifuge.Cl
 		return centrifuge.PublishReply{}, centrifuge.ErrorPermissionDenied
 	}
 
+	allowed, err := g.CanAccessChannel(client.Context(), user, ActionChannelWrite, channel)
+	if err != nil {
+		logger.Error("Error checking channel permissions", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
+		return centrifuge.PublishReply{}, centrifuge.ErrorInternal
+	}
+	if !allowed {
+		// using HTTP error codes for WS errors too.
+		code, text := publishStatusToHTTPError(backend.PublishStreamStatusPermissionDenied)
+		return centrifuge.PublishReply{}, &centrifuge.Error{Code: uint32(code), Message: text}
+	}
+
 	if g.Pipeline != nil {
 		rule, ok, err := g.Pipeline.Get(user.OrgId, channel)
 		if err != nil {
@@ -967,6 +1018,15 @@ func (g *GrafanaLive) HandleHTTPPublish(ctx *models.ReqContext) response.Respons
 	user := ctx.SignedInUser
 	channel := cmd.Channel
 
+	allowed, err := g.CanAccessChannel(ctx.Req.Context(), user, ActionChannelWrite, channel)
+	if err != nil {
+		logger.Error("Error checking channel permissions", "user", user, "channel", channel, "error", err)
+		return response.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil)
+	}
+	if !allowed {
+		return response.Error(http.StatusForbidden, http.StatusText(http.StatusForbidden), nil)
+	}
+
 	if g.Pipeline != nil {
 		rule, ok, err := g.Pipeline.Get(user.OrgId, channel)
 		if err != nil {
@@ -1059,17 +1119,39 @@ func (g *GrafanaLive) HandleInfoHTTP(ctx *models.ReqContext) response.Response {
 	})
 }
 
-// HandleChannelRulesListHTTP ...
+// HandleChannelRulesListHTTP returns the channel rules whose pattern the user can read.
 func (g *GrafanaLive) HandleChannelRulesListHTTP(c *models.ReqContext) response.Response {
-	result, err := g.pipelineStorage.ListChannelRules(c.Req.Context(), c.OrgId)
+	rules, err := g.pipelineStorage.ListChannelRules(c.Req.Context(), c.OrgId)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to get channel rules", err)
 	}
+	result := make([]pipeline.ChannelRule, 0, len(rules))
+	for _, rule := range rules {
+		allowed, err := g.CanAccessChannel(c.Req.Context(), c.SignedInUser, ActionChannelRulesRead, rule.Pattern)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to check channel rule permissions", err)
+		}
+		if allowed {
+			result = append(result, rule)
+		}
+	}
 	return response.JSON(http.StatusOK, util.DynMap{
 		"rules": result,
 	})
 }
 
+// canWriteChannelRule returns a response if the user cannot write the channel rule with the pattern.
+func (g *GrafanaLive) canWriteChannelRule(c *models.ReqContext, pattern string) response.Response {
+	allowed, err := g.CanAccessChannel(c.Req.Context(), c.SignedInUser, ActionChannelRulesWrite, pattern)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to check channel rule permissions", err)
+	}
+	if !allowed {
+		return response.Error(http.StatusForbidden, fmt.Sprintf("Not allowed to manage the channel rule %q", pattern), nil)
+	}
+	return nil
+}
+
 type ConvertDryRunRequest struct {
 	ChannelRules []pipeline.ChannelRule `json:"channelRules"`
 	Channel      string                 `json:"channel"`
@@ -1176,6 +1258,9 @@ func (g *GrafanaLive) HandleChannelRulesPostHTTP(c *models.ReqContext) response.
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "Error decoding channel rule", err)
 	}
+	if resp := g.canWriteChannelRule(c, cmd.Pattern); resp != nil {
+		return resp
+	}
 	rule, err := g.pipelineStorage.CreateChannelRule(c.Req.Context(), c.OrgId, cmd)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to create channel rule", err)
@@ -1199,6 +1284,9 @@ func (g *GrafanaLive) HandleChannelRulesPutHTTP(c *models.ReqContext) response.R
 	if cmd.Pattern == "" {
 		return response.Error(http.StatusBadRequest, "Rule pattern required", nil)
 	}
+	if resp := g.canWriteChannelRule(c, cmd.Pattern); resp != nil {
+		return resp
+	}
 	rule, err := g.pipelineStorage.UpdateChannelRule(c.Req.Context(), c.OrgId, cmd)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to update channel rule", err)
@@ -1222,6 +1310,9 @@ func (g *GrafanaLive) HandleChannelRulesDeleteHTTP(c *models.ReqContext) respons
 	if cmd.Pattern == "" {
 		return response.Error(http.StatusBadRequest, "Rule pattern required", nil)
 	}
+	if resp := g.canWriteChannelRule(c, cmd.Pattern); resp != nil {
+		return resp
+	}
 	err = g.pipelineStorage.DeleteChannelRule(c.Req.Context(), c.OrgId, cmd)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to delete channel rule", err)
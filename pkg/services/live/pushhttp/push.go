@@ -48,6 +48,17 @@ func (g *Gateway) Run(ctx context.Context) error {
 func (g *Gateway) Handle(ctx *models.ReqContext) {
 	streamID := web.Params(ctx.Req)[":streamId"]
 
+	allowed, err := g.GrafanaLive.CanPushToStream(ctx.Req.Context(), ctx.SignedInUser, streamID)
+	if err != nil {
+		logger.Error("Error checking channel permissions", "error", err, "streamId", streamID)
+		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		ctx.Resp.WriteHeader(http.StatusForbidden)
+		return
+	}
+
 	stream, err := g.GrafanaLive.ManagedStreamRunner.GetOrCreateStream(ctx.SignedInUser.OrgId, liveDto.ScopeStream, streamID)
 	if err != nil {
 		logger.Error("Error getting stream", "error", err)
@@ -99,6 +110,17 @@ func (g *Gateway) Handle(ctx *models.ReqContext) {
 func (g *Gateway) HandlePipelinePush(ctx *models.ReqContext) {
 	channelID := web.Params(ctx.Req)["*"]
 
+	allowed, err := g.GrafanaLive.CanAccessChannel(ctx.Req.Context(), ctx.SignedInUser, live.ActionChannelWrite, channelID)
+	if err != nil {
+		logger.Error("Error checking channel permissions", "error", err, "channel", channelID)
+		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		ctx.Resp.WriteHeader(http.StatusForbidden)
+		return
+	}
+
 	body, err := io.ReadAll(ctx.Req.Body)
 	if err != nil {
 		logger.Error("Error reading body", "error", err)
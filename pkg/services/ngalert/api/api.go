@@ -12,7 +12,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/defaultlabels"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
@@ -35,7 +37,7 @@ type Scheduler interface {
 
 type Alertmanager interface {
 	// Configuration
-	SaveAndApplyConfig(ctx context.Context, config *apimodels.PostableUserConfig) error
+	SaveAndApplyConfig(ctx context.Context, config *apimodels.PostableUserConfig, createdBy string) error
 	SaveAndApplyDefaultConfig(ctx context.Context) error
 	GetStatus() apimodels.GettableStatus
 
@@ -51,6 +53,7 @@ type Alertmanager interface {
 
 	// Testing
 	TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error)
+	TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) ([]notifier.TestTemplatesResult, error)
 }
 
 type AlertingStore interface {
@@ -80,7 +83,15 @@ type API struct {
 	ContactPointService  *provisioning.ContactPointService
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
+	AlertmanagerImport   *provisioning.AlertmanagerImportService
+	ProvisioningBatch    *provisioning.BatchService
+	ExternalAMSync       *provisioning.ExternalAlertmanagerSyncService
 	AlertRules           *provisioning.AlertRuleService
+	RuleLint             *lint.Service
+	RuleTemplates        *provisioning.RuleTemplateService
+	ProvisioningWebhooks *provisioning.ProvisioningWebhookService
+	RuleFolders          *provisioning.RuleFolderService
+	DefaultLabels        *defaultlabels.Service
 }
 
 // RegisterAPIEndpoints registers API handlers
@@ -94,7 +105,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterAlertmanagerApiEndpoints(NewForkedAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
-		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager},
+		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager, ruleStore: api.RuleStore},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkedProm(
@@ -116,6 +127,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:             logger,
 			cfg:             &api.Cfg.UnifiedAlerting,
 			ac:              api.AccessControl,
+			ruleLint:        api.RuleLint,
 		},
 	), m)
 	api.RegisterTestingApiEndpoints(NewForkedTestingApi(
@@ -125,12 +137,18 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:             logger,
 			accessControl:   api.AccessControl,
 			evaluator:       eval.NewEvaluator(api.Cfg, log.New("ngalert.eval"), api.DatasourceCache, api.SecretsService, api.ExpressionService),
+			ruleStore:       api.RuleStore,
+			cfg:             &api.Cfg.UnifiedAlerting,
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewForkedConfiguration(
 		&AdminSrv{
-			store:     api.AdminConfigStore,
-			log:       logger,
-			scheduler: api.Schedule,
+			store:         api.AdminConfigStore,
+			ruleStore:     api.RuleStore,
+			ruleLint:      api.RuleLint,
+			defaultLabels: api.DefaultLabels,
+			ac:            api.AccessControl,
+			log:           logger,
+			scheduler:     api.Schedule,
 		},
 	), m)
 
@@ -141,5 +159,13 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		ruleLint:            api.RuleLint,
+		alertmanagerImport:  api.AlertmanagerImport,
+		alertmanagerConfig:  api.MultiOrgAlertmanager,
+		batch:               api.ProvisioningBatch,
+		externalAMSync:      api.ExternalAMSync,
+		ruleTemplates:       api.RuleTemplates,
+		webhooks:            api.ProvisioningWebhooks,
+		ruleFolders:         api.RuleFolders,
 	}), m)
 }
@@ -1,13 +1,17 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/defaultlabels"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
@@ -15,10 +19,29 @@ import (
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
+type RuleLintService interface {
+	GetConfig(ctx context.Context, orgID int64) (apimodels.RuleLintConfig, error)
+	SaveConfig(ctx context.Context, orgID int64, cfg apimodels.RuleLintConfig) error
+	LintRules(ctx context.Context, orgID int64, rules []*ngmodels.AlertRule) (apimodels.RuleLintReport, error)
+	CheckRules(ctx context.Context, orgID int64, rules []*ngmodels.AlertRule) ([]apimodels.RuleLintViolation, error)
+	GetFolderPolicy(ctx context.Context, orgID int64, folderUID string) (apimodels.FolderRulePolicy, error)
+	SaveFolderPolicy(ctx context.Context, orgID int64, folderUID string, policy apimodels.FolderRulePolicy) error
+	DeleteFolderPolicy(ctx context.Context, orgID int64, folderUID string) error
+}
+
+type DefaultLabelsService interface {
+	GetLabels(ctx context.Context, orgID int64) (map[string]string, error)
+	SaveLabels(ctx context.Context, orgID int64, labels map[string]string) error
+}
+
 type AdminSrv struct {
-	scheduler Scheduler
-	store     store.AdminConfigurationStore
-	log       log.Logger
+	scheduler     Scheduler
+	store         store.AdminConfigurationStore
+	ruleStore     store.RuleStore
+	ruleLint      RuleLintService
+	defaultLabels DefaultLabelsService
+	ac            accesscontrol.AccessControl
+	log           log.Logger
 }
 
 func (srv AdminSrv) RouteGetAlertmanagers(c *models.ReqContext) response.Response {
@@ -110,3 +133,149 @@ func (srv AdminSrv) RouteDeleteNGalertConfig(c *models.ReqContext) response.Resp
 
 	return response.JSON(http.StatusOK, util.DynMap{"message": "admin configuration deleted"})
 }
+
+func (srv AdminSrv) RouteGetRuleLintConfig(c *models.ReqContext) response.Response {
+	if c.OrgRole != models.ROLE_ADMIN {
+		return accessForbiddenResp()
+	}
+
+	cfg, err := srv.ruleLint.GetConfig(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule lint configuration")
+	}
+	return response.JSON(http.StatusOK, cfg)
+}
+
+func (srv AdminSrv) RoutePostRuleLintConfig(c *models.ReqContext, body apimodels.RuleLintConfig) response.Response {
+	if c.OrgRole != models.ROLE_ADMIN {
+		return accessForbiddenResp()
+	}
+
+	if err := srv.ruleLint.SaveConfig(c.Req.Context(), c.OrgId, body); err != nil {
+		if errors.Is(err, lint.ErrInvalidConfig) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to save rule lint configuration")
+	}
+	return response.JSON(http.StatusCreated, util.DynMap{"message": "rule lint configuration updated"})
+}
+
+func (srv AdminSrv) RouteGetFolderRulePolicy(c *models.ReqContext, folderUID string) response.Response {
+	if c.OrgRole != models.ROLE_ADMIN {
+		return accessForbiddenResp()
+	}
+
+	policy, err := srv.ruleLint.GetFolderPolicy(c.Req.Context(), c.OrgId, folderUID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get folder rule policy")
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+func (srv AdminSrv) RoutePostFolderRulePolicy(c *models.ReqContext, body apimodels.FolderRulePolicy, folderUID string) response.Response {
+	if c.OrgRole != models.ROLE_ADMIN {
+		return accessForbiddenResp()
+	}
+
+	if _, err := srv.ruleStore.GetNamespaceByUID(c.Req.Context(), folderUID, c.OrgId, c.SignedInUser); err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+	if err := srv.ruleLint.SaveFolderPolicy(c.Req.Context(), c.OrgId, folderUID, body); err != nil {
+		if errors.Is(err, lint.ErrInvalidFolderPolicy) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to save folder rule policy")
+	}
+	return response.JSON(http.StatusCreated, util.DynMap{"message": "folder rule policy updated"})
+}
+
+func (srv AdminSrv) RouteDeleteFolderRulePolicy(c *models.ReqContext, folderUID string) response.Response {
+	if c.OrgRole != models.ROLE_ADMIN {
+		return accessForbiddenResp()
+	}
+
+	if err := srv.ruleLint.DeleteFolderPolicy(c.Req.Context(), c.OrgId, folderUID); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to delete folder rule policy")
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "folder rule policy deleted"})
+}
+
+func (srv AdminSrv) RouteGetDefaultLabels(c *models.ReqContext) response.Response {
+	if c.OrgRole != models.ROLE_ADMIN {
+		return accessForbiddenResp()
+	}
+
+	labels, err := srv.defaultLabels.GetLabels(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get default labels")
+	}
+	return response.JSON(http.StatusOK, apimodels.DefaultLabels{Labels: labels})
+}
+
+func (srv AdminSrv) RoutePostDefaultLabels(c *models.ReqContext, body apimodels.DefaultLabels) response.Response {
+	if c.OrgRole != models.ROLE_ADMIN {
+		return accessForbiddenResp()
+	}
+
+	if err := srv.defaultLabels.SaveLabels(c.Req.Context(), c.OrgId, body.Labels); err != nil {
+		if errors.Is(err, defaultlabels.ErrInvalidLabels) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to save default labels")
+	}
+	return response.JSON(http.StatusCreated, util.DynMap{"message": "default labels updated"})
+}
+
+func (srv AdminSrv) RoutePostRuleLint(c *models.ReqContext, body apimodels.RuleLintRequest) response.Response {
+	namespaceMap, err := srv.ruleStore.GetUserVisibleNamespaces(c.Req.Context(), c.OrgId, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+
+	rules := make([]*ngmodels.AlertRule, 0)
+	if len(namespaceMap) > 0 {
+		namespaceUIDs := make([]string, 0, len(namespaceMap))
+		for uid := range namespaceMap {
+			namespaceUIDs = append(namespaceUIDs, uid)
+		}
+		q := ngmodels.ListAlertRulesQuery{
+			OrgID:         c.OrgId,
+			NamespaceUIDs: namespaceUIDs,
+		}
+		if err := srv.ruleStore.ListAlertRules(c.Req.Context(), &q); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+		}
+
+		wanted := make(map[string]struct{}, len(body.RuleUIDs))
+		for _, uid := range body.RuleUIDs {
+			wanted[uid] = struct{}{}
+		}
+		hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+			return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqViewer, evaluator)
+		}
+		for _, rule := range q.Result {
+			if _, ok := wanted[rule.UID]; len(wanted) > 0 && !ok {
+				continue
+			}
+			if !authorizeDatasourceAccessForRule(rule, hasAccess) {
+				continue
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	report, err := srv.ruleLint.LintRules(c.Req.Context(), c.OrgId, rules)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to lint alert rules")
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// ruleLintErrorResp returns the response to a request saving rules that could not be linted or were rejected by the
+// rule lint policies of the organization.
+func ruleLintErrorResp(err error, violations []apimodels.RuleLintViolation) response.Response {
+	if errors.Is(err, lint.ErrRulesRejected) {
+		return response.JSON(http.StatusBadRequest, util.DynMap{"message": err.Error(), "violations": violations})
+	}
+	return ErrResp(http.StatusInternalServerError, err, "failed to lint alert rules")
+}
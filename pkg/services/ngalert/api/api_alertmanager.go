@@ -15,9 +15,11 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/prometheus/common/model"
 )
 
 const (
@@ -26,10 +28,11 @@ const (
 )
 
 type AlertmanagerSrv struct {
-	log    log.Logger
-	ac     accesscontrol.AccessControl
-	mam    *notifier.MultiOrgAlertmanager
-	crypto notifier.Crypto
+	log       log.Logger
+	ac        accesscontrol.AccessControl
+	mam       *notifier.MultiOrgAlertmanager
+	crypto    notifier.Crypto
+	ruleStore store.RuleStore
 }
 
 type UnknownReceiverError struct {
@@ -128,6 +131,68 @@ func (srv AlertmanagerSrv) RouteGetAlertingConfig(c *models.ReqContext) response
 	return response.JSON(http.StatusOK, config)
 }
 
+func (srv AlertmanagerSrv) RouteGetAlertingConfigHistory(c *models.ReqContext) response.Response {
+	limit, page := c.QueryInt("limit"), c.QueryInt("page")
+	if limit < 0 || page < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("limit and page must not be negative"), "")
+	}
+	offset := 0
+	if limit > 0 && page > 1 {
+		offset = (page - 1) * limit
+	}
+	versions, err := srv.mam.GetAlertmanagerConfigurationVersions(c.Req.Context(), c.OrgId, limit, offset)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, versions)
+}
+
+func (srv AlertmanagerSrv) RouteGetAlertingConfigDiff(c *models.ReqContext) response.Response {
+	from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid version to compare from")
+	}
+	var diff apimodels.AlertingConfigDiff
+	if c.Query("to") == "" {
+		diff, err = srv.mam.DiffAlertmanagerConfigurationWithCurrent(c.Req.Context(), c.OrgId, from)
+	} else {
+		to, parseErr := strconv.ParseInt(c.Query("to"), 10, 64)
+		if parseErr != nil {
+			return ErrResp(http.StatusBadRequest, parseErr, "invalid version to compare to")
+		}
+		diff, err = srv.mam.DiffAlertmanagerConfigurations(c.Req.Context(), c.OrgId, from, to)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, diff)
+}
+
+func (srv AlertmanagerSrv) RoutePostAlertingConfigHistoryRollback(c *models.ReqContext, id string) response.Response {
+	versionID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid version")
+	}
+	err = srv.mam.RollbackAlertmanagerConfiguration(c.Req.Context(), c.OrgId, versionID, c.Login)
+	if err == nil {
+		return response.JSON(http.StatusAccepted, util.DynMap{"message": "configuration rolled back"})
+	}
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	var configRejectedError notifier.AlertmanagerConfigRejectedError
+	if errors.As(err, &configRejectedError) {
+		return ErrResp(http.StatusBadRequest, configRejectedError, "")
+	}
+	if errors.Is(err, notifier.ErrNoAlertmanagerForOrg) {
+		return response.Error(http.StatusNotFound, err.Error(), err)
+	}
+	return ErrResp(http.StatusInternalServerError, err, "")
+}
+
 func (srv AlertmanagerSrv) RouteGetAMAlertGroups(c *models.ReqContext) response.Response {
 	am, errResp := srv.AlertmanagerFor(c.OrgId)
 	if errResp != nil {
@@ -222,7 +287,7 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 			return ErrResp(http.StatusBadRequest, err, "")
 		}
 	}
-	err = srv.mam.ApplyAlertmanagerConfiguration(c.Req.Context(), c.OrgId, body)
+	err = srv.mam.ApplyAlertmanagerConfiguration(c.Req.Context(), c.OrgId, body, c.Login)
 	if err == nil {
 		return response.JSON(http.StatusAccepted, util.DynMap{"message": "configuration created"})
 	}
@@ -287,6 +352,77 @@ func (srv AlertmanagerSrv) RoutePostTestReceivers(c *models.ReqContext, body api
 	return response.JSON(statusForTestReceivers(result.Receivers), newTestReceiversResult(result))
 }
 
+func (srv AlertmanagerSrv) RoutePostTestTemplates(c *models.ReqContext, body apimodels.TestTemplatesConfigBodyParams) response.Response {
+	am, errResp := srv.AlertmanagerFor(c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	if len(body.Alerts) == 0 && body.RuleUID != "" {
+		q := ngmodels.GetAlertRuleByUIDQuery{UID: body.RuleUID, OrgID: c.OrgId}
+		if err := srv.ruleStore.GetAlertRuleByUID(c.Req.Context(), &q); err != nil {
+			if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, "failed to get alert rule")
+		}
+		namespace, err := srv.ruleStore.GetNamespaceByUID(c.Req.Context(), q.Result.NamespaceUID, c.OrgId, c.SignedInUser)
+		if err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+		body.Alerts = newTestTemplateAlertsFromRule(q.Result, namespace.Title)
+	}
+
+	results, err := am.TestTemplate(c.Req.Context(), body)
+	if err != nil {
+		if errors.Is(err, notifier.ErrInvalidTemplate) {
+			return response.JSON(http.StatusBadRequest, apimodels.TestTemplatesError{
+				Message:       err.Error(),
+				ErrorLocation: notifier.TemplateErrorLocation(err),
+			})
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to render template")
+	}
+	return response.JSON(http.StatusOK, newTestTemplatesResults(results))
+}
+
+// newTestTemplateAlertsFromRule returns a firing sample alert of the rule, with the labels the rule adds to its
+// alerts. Templates in the labels and annotations of the rule are not expanded.
+func newTestTemplateAlertsFromRule(rule *ngmodels.AlertRule, folderTitle string) []*apimodels.TestTemplateAlert {
+	alert := &apimodels.TestTemplateAlert{
+		Labels:      model.LabelSet{},
+		Annotations: model.LabelSet{},
+	}
+	for k, v := range rule.Labels {
+		alert.Labels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	alert.Labels[model.AlertNameLabel] = model.LabelValue(rule.Title)
+	alert.Labels[ngmodels.FolderTitleLabel] = model.LabelValue(folderTitle)
+	for k, v := range rule.Annotations {
+		alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return []*apimodels.TestTemplateAlert{alert}
+}
+
+func newTestTemplatesResults(results []notifier.TestTemplatesResult) apimodels.TestTemplatesResults {
+	v := apimodels.TestTemplatesResults{
+		Results: make([]apimodels.TestTemplatesResult, 0, len(results)),
+	}
+	for _, result := range results {
+		next := apimodels.TestTemplatesResult{
+			Format:  result.Format,
+			Subject: result.Subject,
+			Output:  result.Output,
+		}
+		if result.Error != nil {
+			next.Error = result.Error.Error()
+			next.ErrorLocation = notifier.TemplateErrorLocation(result.Error)
+		}
+		v.Results = append(v.Results, next)
+	}
+	return v
+}
+
 // contextWithTimeoutFromRequest returns a context with a deadline set from the
 // Request-Timeout header in the HTTP request. If the header is absent then the
 // context will use the default timeout. The timeout in the Request-Timeout
@@ -7,9 +7,8 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/util/cmputil"
-	amConfig "github.com/prometheus/alertmanager/config"
-	"github.com/prometheus/alertmanager/pkg/labels"
 )
 
 func (srv AlertmanagerSrv) provenanceGuard(currentConfig apimodels.GettableUserConfig, newConfig apimodels.PostableUserConfig) error {
@@ -29,11 +28,12 @@ func (srv AlertmanagerSrv) provenanceGuard(currentConfig apimodels.GettableUserC
 }
 
 func checkRoutes(currentConfig apimodels.GettableUserConfig, newConfig apimodels.PostableUserConfig) error {
-	reporter := cmputil.DiffReporter{}
-	options := []cmp.Option{cmp.Reporter(&reporter), cmpopts.EquateEmpty(), cmpopts.IgnoreUnexported(labels.Matcher{})}
-	routesEqual := cmp.Equal(currentConfig.AlertmanagerConfig.Route, newConfig.AlertmanagerConfig.Route, options...)
-	if !routesEqual && currentConfig.AlertmanagerConfig.Route.Provenance != ngmodels.ProvenanceNone {
-		return fmt.Errorf("policies were provisioned and cannot be changed through the UI")
+	current, updated := currentConfig.AlertmanagerConfig.Route, newConfig.AlertmanagerConfig.Route
+	if current == nil || updated == nil {
+		return nil
+	}
+	if err := provisioning.CheckProvisionedRoutes(current, updated, ngmodels.ProvenanceNone); err != nil {
+		return fmt.Errorf("policies were provisioned and cannot be changed through the UI: %w", err)
 	}
 	return nil
 }
@@ -119,7 +119,7 @@ func checkContactPoints(currReceivers []*apimodels.GettableApiReceiver, newRecei
 }
 
 func checkMuteTimes(currentConfig apimodels.GettableUserConfig, newConfig apimodels.PostableUserConfig) error {
-	newMTs := make(map[string]amConfig.MuteTimeInterval)
+	newMTs := make(map[string]apimodels.MuteTimeIntervalConfig)
 	for _, newMuteTime := range newConfig.AlertmanagerConfig.MuteTimeIntervals {
 		newMTs[newMuteTime.Name] = newMuteTime
 	}
@@ -136,7 +136,9 @@ func checkMuteTimes(currentConfig apimodels.GettableUserConfig, newConfig apimod
 			return fmt.Errorf("cannot delete provisioned mute time '%s'", muteTime.Name)
 		}
 		reporter := cmputil.DiffReporter{}
-		options := []cmp.Option{cmp.Reporter(&reporter), cmpopts.EquateEmpty()}
+		options := []cmp.Option{cmp.Reporter(&reporter), cmpopts.EquateEmpty(), cmp.Comparer(func(a, b apimodels.Location) bool {
+			return a.String() == b.String()
+		})}
 		timesEqual := cmp.Equal(muteTime.TimeIntervals, postedMT.TimeIntervals, options...)
 		if !timesEqual {
 			return fmt.Errorf("cannot save provisioned mute time '%s'", muteTime.Name)
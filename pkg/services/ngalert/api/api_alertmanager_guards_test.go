@@ -2,6 +2,7 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -46,6 +47,47 @@ func TestCheckRoute(t *testing.T) {
 				return cfg
 			}(),
 		},
+		{
+			name:          "adding a nested route to a provisioned route should not fail",
+			shouldErr:     false,
+			currentConfig: gettableRoute(t, models.ProvenanceAPI),
+			newConfig: func() definitions.PostableUserConfig {
+				cfg := postableRoute(t, models.ProvenanceAPI)
+				cfg.AlertmanagerConfig.Route.Routes = append(cfg.AlertmanagerConfig.Route.Routes, &definitions.Route{Receiver: "team-a"})
+				return cfg
+			}(),
+		},
+		{
+			name:      "editing a provisioned nested route should fail",
+			shouldErr: true,
+			currentConfig: func() definitions.GettableUserConfig {
+				cfg := gettableRoute(t, models.ProvenanceNone)
+				cfg.AlertmanagerConfig.Route.Routes[0].ID = "nested"
+				cfg.AlertmanagerConfig.Route.Routes[0].Provenance = models.ProvenanceAPI
+				return cfg
+			}(),
+			newConfig: func() definitions.PostableUserConfig {
+				cfg := postableRoute(t, models.ProvenanceNone)
+				cfg.AlertmanagerConfig.Route.Routes[0].ID = "nested"
+				cfg.AlertmanagerConfig.Route.Routes[0].Matchers[0].Value = "123"
+				return cfg
+			}(),
+		},
+		{
+			name:      "deleting a provisioned nested route should fail",
+			shouldErr: true,
+			currentConfig: func() definitions.GettableUserConfig {
+				cfg := gettableRoute(t, models.ProvenanceNone)
+				cfg.AlertmanagerConfig.Route.Routes[0].ID = "nested"
+				cfg.AlertmanagerConfig.Route.Routes[0].Provenance = models.ProvenanceAPI
+				return cfg
+			}(),
+			newConfig: func() definitions.PostableUserConfig {
+				cfg := postableRoute(t, models.ProvenanceNone)
+				cfg.AlertmanagerConfig.Route.Routes = nil
+				return cfg
+			}(),
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -358,7 +400,7 @@ func TestCheckMuteTimes(t *testing.T) {
 			name:      "equal configs should not error",
 			shouldErr: false,
 			currentConfig: gettableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name:          "test-1",
 						TimeIntervals: defaultInterval(t),
@@ -372,7 +414,7 @@ func TestCheckMuteTimes(t *testing.T) {
 					"test-1": models.ProvenanceNone,
 				}),
 			newConfig: postableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name:          "test-1",
 						TimeIntervals: defaultInterval(t),
@@ -387,7 +429,7 @@ func TestCheckMuteTimes(t *testing.T) {
 			name:      "removing a non provisioned object should not fail",
 			shouldErr: false,
 			currentConfig: gettableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name:          "test-1",
 						TimeIntervals: defaultInterval(t),
@@ -396,13 +438,13 @@ func TestCheckMuteTimes(t *testing.T) {
 				map[string]models.Provenance{
 					"test-1": models.ProvenanceNone,
 				}),
-			newConfig: postableMuteIntervals(t, []amConfig.MuteTimeInterval{}),
+			newConfig: postableMuteIntervals(t, []definitions.MuteTimeIntervalConfig{}),
 		},
 		{
 			name:      "removing a provisioned object should fail",
 			shouldErr: true,
 			currentConfig: gettableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name:          "test-1",
 						TimeIntervals: defaultInterval(t),
@@ -415,7 +457,7 @@ func TestCheckMuteTimes(t *testing.T) {
 				map[string]models.Provenance{
 					"test-1": models.ProvenanceAPI,
 				}),
-			newConfig: postableMuteIntervals(t, []amConfig.MuteTimeInterval{
+			newConfig: postableMuteIntervals(t, []definitions.MuteTimeIntervalConfig{
 				{
 					Name:          "test-2",
 					TimeIntervals: defaultInterval(t),
@@ -426,7 +468,7 @@ func TestCheckMuteTimes(t *testing.T) {
 			name:      "adding a non provisioned object should not fail",
 			shouldErr: false,
 			currentConfig: gettableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name:          "test-1",
 						TimeIntervals: defaultInterval(t),
@@ -436,7 +478,7 @@ func TestCheckMuteTimes(t *testing.T) {
 					"test-1": models.ProvenanceNone,
 				}),
 			newConfig: postableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name:          "test-1",
 						TimeIntervals: defaultInterval(t),
@@ -451,7 +493,7 @@ func TestCheckMuteTimes(t *testing.T) {
 			name:      "editing a non provisioned object should not fail",
 			shouldErr: false,
 			currentConfig: gettableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name:          "test-1",
 						TimeIntervals: defaultInterval(t),
@@ -461,10 +503,10 @@ func TestCheckMuteTimes(t *testing.T) {
 					"test-1": models.ProvenanceNone,
 				}),
 			newConfig: postableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name: "test-1",
-						TimeIntervals: func() []timeinterval.TimeInterval {
+						TimeIntervals: func() []definitions.TimeInterval {
 							intervals := defaultInterval(t)
 							intervals[0].Times = []timeinterval.TimeRange{
 								{
@@ -481,7 +523,7 @@ func TestCheckMuteTimes(t *testing.T) {
 			name:      "editing a provisioned object should fail",
 			shouldErr: true,
 			currentConfig: gettableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name:          "test-1",
 						TimeIntervals: defaultInterval(t),
@@ -491,10 +533,10 @@ func TestCheckMuteTimes(t *testing.T) {
 					"test-1": models.ProvenanceAPI,
 				}),
 			newConfig: postableMuteIntervals(t,
-				[]amConfig.MuteTimeInterval{
+				[]definitions.MuteTimeIntervalConfig{
 					{
 						Name: "test-1",
-						TimeIntervals: func() []timeinterval.TimeInterval {
+						TimeIntervals: func() []definitions.TimeInterval {
 							intervals := defaultInterval(t)
 							intervals[0].Times = []timeinterval.TimeRange{
 								{
@@ -507,6 +549,48 @@ func TestCheckMuteTimes(t *testing.T) {
 					},
 				}),
 		},
+		{
+			name:      "saving a provisioned object with the same location should not fail",
+			shouldErr: false,
+			currentConfig: gettableMuteIntervals(t,
+				[]definitions.MuteTimeIntervalConfig{
+					{
+						Name:          "test-1",
+						TimeIntervals: intervalInLocation(t, "Europe/Berlin"),
+					},
+				},
+				map[string]models.Provenance{
+					"test-1": models.ProvenanceAPI,
+				}),
+			newConfig: postableMuteIntervals(t,
+				[]definitions.MuteTimeIntervalConfig{
+					{
+						Name:          "test-1",
+						TimeIntervals: intervalInLocation(t, "Europe/Berlin"),
+					},
+				}),
+		},
+		{
+			name:      "changing the location of a provisioned object should fail",
+			shouldErr: true,
+			currentConfig: gettableMuteIntervals(t,
+				[]definitions.MuteTimeIntervalConfig{
+					{
+						Name:          "test-1",
+						TimeIntervals: intervalInLocation(t, "Europe/Berlin"),
+					},
+				},
+				map[string]models.Provenance{
+					"test-1": models.ProvenanceAPI,
+				}),
+			newConfig: postableMuteIntervals(t,
+				[]definitions.MuteTimeIntervalConfig{
+					{
+						Name:          "test-1",
+						TimeIntervals: intervalInLocation(t, "America/New_York"),
+					},
+				}),
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -520,7 +604,7 @@ func TestCheckMuteTimes(t *testing.T) {
 	}
 }
 
-func gettableMuteIntervals(t *testing.T, muteTimeIntervals []amConfig.MuteTimeInterval, provenances map[string]models.Provenance) definitions.GettableUserConfig {
+func gettableMuteIntervals(t *testing.T, muteTimeIntervals []definitions.MuteTimeIntervalConfig, provenances map[string]models.Provenance) definitions.GettableUserConfig {
 	return definitions.GettableUserConfig{
 		AlertmanagerConfig: definitions.GettableApiAlertingConfig{
 			MuteTimeProvenances: provenances,
@@ -531,7 +615,7 @@ func gettableMuteIntervals(t *testing.T, muteTimeIntervals []amConfig.MuteTimeIn
 	}
 }
 
-func postableMuteIntervals(t *testing.T, muteTimeIntervals []amConfig.MuteTimeInterval) definitions.PostableUserConfig {
+func postableMuteIntervals(t *testing.T, muteTimeIntervals []definitions.MuteTimeIntervalConfig) definitions.PostableUserConfig {
 	t.Helper()
 	return definitions.PostableUserConfig{
 		AlertmanagerConfig: definitions.PostableApiAlertingConfig{
@@ -542,9 +626,9 @@ func postableMuteIntervals(t *testing.T, muteTimeIntervals []amConfig.MuteTimeIn
 	}
 }
 
-func defaultInterval(t *testing.T) []timeinterval.TimeInterval {
+func defaultInterval(t *testing.T) []definitions.TimeInterval {
 	t.Helper()
-	return []timeinterval.TimeInterval{
+	return []definitions.TimeInterval{
 		{
 			Years: []timeinterval.YearRange{
 				{
@@ -587,3 +671,12 @@ func defaultInterval(t *testing.T) []timeinterval.TimeInterval {
 		},
 	}
 }
+
+func intervalInLocation(t *testing.T, name string) []definitions.TimeInterval {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	intervals := defaultInterval(t)
+	intervals[0].Location = &definitions.Location{Location: loc}
+	return intervals
+}
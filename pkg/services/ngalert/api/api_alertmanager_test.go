@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/go-openapi/strfmt"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -23,6 +26,7 @@ import (
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
 	"github.com/grafana/grafana/pkg/setting"
@@ -195,6 +199,50 @@ func TestAlertmanagerConfig(t *testing.T) {
 		require.Equal(t, 202, response.Status())
 	})
 
+	t.Run("assert 400 Bad Request when the policy tree exceeds the limits of the org", func(t *testing.T) {
+		rc := models.ReqContext{
+			Context: &web.Context{
+				Req: &http.Request{},
+			},
+			SignedInUser: &models.SignedInUser{
+				OrgId: 2, // Org 2 allows only a single route.
+			},
+		}
+		request := createAmConfigRequest(t)
+		request.AlertmanagerConfig.Route.Routes = []*apimodels.Route{{Receiver: "grafana-default-email"}}
+
+		response := sut.RoutePostAlertingConfig(&rc, request)
+
+		require.Equal(t, 400, response.Status())
+		require.Contains(t, string(response.Body()), "the tree has 2 routes, at most 1 are allowed")
+	})
+
+	t.Run("assert 400 Bad Request when a mute timing has overlapping ranges", func(t *testing.T) {
+		rc := models.ReqContext{
+			Context: &web.Context{
+				Req: &http.Request{},
+			},
+			SignedInUser: &models.SignedInUser{
+				OrgId: 1,
+			},
+		}
+		request := createAmConfigRequest(t)
+		request.AlertmanagerConfig.MuteTimeIntervals = []apimodels.MuteTimeIntervalConfig{{
+			Name: "business-hours",
+			TimeIntervals: []apimodels.TimeInterval{{
+				Weekdays: []timeinterval.WeekdayRange{
+					{InclusiveRange: timeinterval.InclusiveRange{Begin: 1, End: 5}},
+					{InclusiveRange: timeinterval.InclusiveRange{Begin: 5, End: 6}},
+				},
+			}},
+		}}
+
+		response := sut.RoutePostAlertingConfig(&rc, request)
+
+		require.Equal(t, 400, response.Status())
+		require.Contains(t, string(response.Body()), `invalid mute timing \"business-hours\": time_intervals[0].weekdays[1]: friday:saturday overlaps weekdays[0] (monday:friday)`)
+	})
+
 	t.Run("assert 202 when alertmanager to configure is not ready", func(t *testing.T) {
 		sut := createSut(t, nil)
 		rc := models.ReqContext{
@@ -480,6 +528,270 @@ func TestRouteCreateSilence(t *testing.T) {
 	}
 }
 
+func TestRoutePostTestTemplates(t *testing.T) {
+	rc := func() *models.ReqContext {
+		return &models.ReqContext{
+			Context:      &web.Context{Req: &http.Request{}},
+			SignedInUser: &models.SignedInUser{OrgId: 1},
+		}
+	}
+	slackOutput := func(t *testing.T, results apimodels.TestTemplatesResults) string {
+		t.Helper()
+		for _, result := range results.Results {
+			if result.Format == apimodels.TestTemplateFormatSlack {
+				require.Empty(t, result.Error)
+				return result.Output
+			}
+		}
+		require.Fail(t, "no result for slack")
+		return ""
+	}
+
+	t.Run("renders the template for every format", func(t *testing.T) {
+		sut := createSut(t, nil)
+		body := apimodels.TestTemplatesConfigBodyParams{
+			Name:     "custom",
+			Template: `{{ define "custom.title" }}{{ .CommonLabels.alertname }} fired{{ end }}`,
+			Title:    `{{ template "custom.title" . }}`,
+			Alerts: []*apimodels.TestTemplateAlert{
+				{Labels: model.LabelSet{"alertname": "HighCPU"}},
+			},
+		}
+
+		response := sut.RoutePostTestTemplates(rc(), body)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var results apimodels.TestTemplatesResults
+		require.NoError(t, json.Unmarshal(response.Body(), &results))
+		require.Len(t, results.Results, 3)
+		require.Contains(t, slackOutput(t, results), `"title": "HighCPU fired"`)
+		for _, result := range results.Results {
+			switch result.Format {
+			case apimodels.TestTemplateFormatWebhook:
+				require.Empty(t, result.Error)
+				require.Contains(t, result.Output, `"alertname": "HighCPU"`)
+			case apimodels.TestTemplateFormatEmail:
+				// the test Alertmanager has no notification service to render emails
+				require.NotEmpty(t, result.Error)
+			}
+		}
+	})
+
+	t.Run("renders sample alerts of a rule", func(t *testing.T) {
+		sut := createSut(t, nil)
+		ruleStore := store.NewFakeRuleStore(t)
+		rule := ngmodels.AlertRuleGen()()
+		rule.OrgID = 1
+		rule.Title = "High latency"
+		rule.Labels = map[string]string{"team": "alerting"}
+		ruleStore.PutRule(context.Background(), rule)
+		sut.ruleStore = ruleStore
+		body := apimodels.TestTemplatesConfigBodyParams{
+			Name:     "custom",
+			Template: `{{ define "custom.message" }}{{ .CommonLabels.team }}/{{ .CommonLabels.alertname }}{{ end }}`,
+			Message:  `{{ template "custom.message" . }}`,
+			RuleUID:  rule.UID,
+			Formats:  []apimodels.TestTemplateFormat{apimodels.TestTemplateFormatSlack},
+		}
+
+		response := sut.RoutePostTestTemplates(rc(), body)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var results apimodels.TestTemplatesResults
+		require.NoError(t, json.Unmarshal(response.Body(), &results))
+		require.Len(t, results.Results, 1)
+		require.Contains(t, slackOutput(t, results), `"text": "alerting/High latency"`)
+	})
+
+	t.Run("invalid template returns 400", func(t *testing.T) {
+		sut := createSut(t, nil)
+		body := apimodels.TestTemplatesConfigBodyParams{
+			Name:     "custom",
+			Template: "{{ define \"custom.title\" }}\n{{ .CommonLabels.alertname }\n{{ end }}",
+		}
+
+		response := sut.RoutePostTestTemplates(rc(), body)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		var result apimodels.TestTemplatesError
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.NotEmpty(t, result.Message)
+		require.Equal(t, &apimodels.TemplateErrorLocation{Template: "custom", Line: 2}, result.ErrorLocation)
+	})
+
+	t.Run("render errors are returned with their location", func(t *testing.T) {
+		sut := createSut(t, nil)
+		body := apimodels.TestTemplatesConfigBodyParams{
+			Name:     "custom",
+			Template: "{{ define \"custom.title\" }}\n  {{ template \"does-not-exist\" . }}{{ end }}",
+			Title:    `{{ template "custom.title" . }}`,
+			Formats:  []apimodels.TestTemplateFormat{apimodels.TestTemplateFormatSlack},
+		}
+
+		response := sut.RoutePostTestTemplates(rc(), body)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var results apimodels.TestTemplatesResults
+		require.NoError(t, json.Unmarshal(response.Body(), &results))
+		require.Len(t, results.Results, 1)
+		require.NotEmpty(t, results.Results[0].Error)
+		require.Equal(t, &apimodels.TemplateErrorLocation{Template: "custom", Line: 2, Column: 14}, results.Results[0].ErrorLocation)
+	})
+
+	t.Run("unknown format returns 400", func(t *testing.T) {
+		sut := createSut(t, nil)
+		body := apimodels.TestTemplatesConfigBodyParams{
+			Name:     "custom",
+			Template: `{{ define "custom.title" }}{{ end }}`,
+			Formats:  []apimodels.TestTemplateFormat{"pager"},
+		}
+
+		response := sut.RoutePostTestTemplates(rc(), body)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
+func TestRouteGetAlertingConfigHistory(t *testing.T) {
+	rc := func(query string) *models.ReqContext {
+		return &models.ReqContext{
+			Context:      &web.Context{Req: &http.Request{URL: &url.URL{RawQuery: query}}},
+			SignedInUser: &models.SignedInUser{OrgId: 1},
+		}
+	}
+
+	t.Run("returns the stored versions", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RouteGetAlertingConfigHistory(rc(""))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var versions apimodels.AlertingConfigVersions
+		require.NoError(t, json.Unmarshal(response.Body(), &versions))
+		require.Len(t, versions, 1)
+	})
+
+	t.Run("returns the pages of the stored versions", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RouteGetAlertingConfigHistory(rc("limit=1&page=2"))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var versions apimodels.AlertingConfigVersions
+		require.NoError(t, json.Unmarshal(response.Body(), &versions))
+		require.Empty(t, versions)
+	})
+
+	t.Run("negative limit returns 400", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RouteGetAlertingConfigHistory(rc("limit=-1"))
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("diff of the same version has no changes", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RouteGetAlertingConfigDiff(rc("from=0&to=0"))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var diff apimodels.AlertingConfigDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &diff))
+		require.Empty(t, diff.Route)
+		require.Empty(t, diff.Receivers)
+		require.Empty(t, diff.Templates)
+		require.Empty(t, diff.MuteTimeIntervals)
+	})
+
+	t.Run("diff of an unknown version returns 404", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RouteGetAlertingConfigDiff(rc("from=0&to=42"))
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("diff without the version to compare to compares the current configuration", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RouteGetAlertingConfigDiff(rc("from=0"))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var diff apimodels.AlertingConfigDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &diff))
+		require.Equal(t, diff.From, diff.To)
+		require.Empty(t, diff.Receivers)
+	})
+
+	t.Run("diff of an unknown version with the current configuration returns 404", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RouteGetAlertingConfigDiff(rc("from=42"))
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("diff without the version to compare from returns 400", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RouteGetAlertingConfigDiff(rc("to=0"))
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
+func TestRoutePostAlertingConfigHistoryRollback(t *testing.T) {
+	rc := func() *models.ReqContext {
+		return &models.ReqContext{
+			Context:      &web.Context{Req: &http.Request{}},
+			SignedInUser: &models.SignedInUser{OrgId: 1, Login: "admin"},
+		}
+	}
+
+	t.Run("saves the version as a new version", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RoutePostAlertingConfigHistoryRollback(rc(), "0")
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		versions, err := sut.mam.GetAlertmanagerConfigurationVersions(context.Background(), 1, 0, 0)
+		require.NoError(t, err)
+		require.Equal(t, "rollback", versions[0].Origin)
+		require.Equal(t, "admin", versions[0].CreatedBy)
+	})
+
+	t.Run("removes the provenance of the resources that are not in the version", func(t *testing.T) {
+		sut := createSut(t, nil)
+		ctx := context.Background()
+		require.NoError(t, sut.mam.ProvStore.SetProvenance(ctx, &apimodels.MessageTemplate{Name: "a"}, 1, ngmodels.ProvenanceAPI))
+		require.NoError(t, sut.mam.ProvStore.SetProvenance(ctx, &apimodels.MessageTemplate{Name: "b"}, 1, ngmodels.ProvenanceAPI))
+
+		response := sut.RoutePostAlertingConfigHistoryRollback(rc(), "0")
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		provenances, err := sut.mam.ProvStore.GetProvenances(ctx, 1, (&apimodels.MessageTemplate{}).ResourceType())
+		require.NoError(t, err)
+		require.Equal(t, map[string]ngmodels.Provenance{"a": ngmodels.ProvenanceAPI}, provenances)
+	})
+
+	t.Run("unknown version returns 404", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RoutePostAlertingConfigHistoryRollback(rc(), "42")
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("invalid version returns 400", func(t *testing.T) {
+		sut := createSut(t, nil)
+
+		response := sut.RoutePostAlertingConfigHistoryRollback(rc(), "latest")
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
 func createSut(t *testing.T, accessControl accesscontrol.AccessControl) AlertmanagerSrv {
 	t.Helper()
 
@@ -529,6 +841,7 @@ func createMultiOrgAlertmanager(t *testing.T) *notifier.MultiOrgAlertmanager {
 			AlertmanagerConfigPollInterval: 3 * time.Minute,
 			DefaultConfiguration:           setting.GetAlertmanagerDefaultConfiguration(),
 			DisabledOrgs:                   map[int64]struct{}{5: {}},
+			PolicyTreeLimitsPerOrg:         map[int64]setting.UnifiedAlertingPolicyTreeLimits{2: {MaxRoutes: 1}},
 		}, // do not poll in tests.
 	}
 
@@ -3,16 +3,23 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"gopkg.in/yaml.v3"
 )
 
 type ProvisioningSrv struct {
@@ -22,44 +29,127 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	ruleLint            RuleLintService
+	alertmanagerImport  AlertmanagerImportService
+	alertmanagerConfig  AlertmanagerConfigValidator
+	batch               ProvisioningBatchService
+	externalAMSync      ExternalAlertmanagerSyncService
+	ruleTemplates       RuleTemplateService
+	webhooks            ProvisioningWebhookService
+	ruleFolders         RuleFolderService
 }
 
 type ContactPointService interface {
 	GetContactPoints(ctx context.Context, orgID int64) ([]definitions.EmbeddedContactPoint, error)
+	GetContactPointDuplicates(ctx context.Context, orgID int64) (definitions.ContactPointDuplicates, error)
+	GetContactPointDuplicatesOf(ctx context.Context, orgID int64, uid string) ([]string, error)
 	CreateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, p alerting_models.Provenance) (definitions.EmbeddedContactPoint, error)
 	UpdateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, p alerting_models.Provenance) error
 	DeleteContactPoint(ctx context.Context, orgID int64, uid string) error
 }
 
 type TemplateService interface {
-	GetTemplates(ctx context.Context, orgID int64) (map[string]string, error)
+	GetTemplates(ctx context.Context, orgID int64) ([]definitions.MessageTemplate, error)
+	GetTemplate(ctx context.Context, orgID int64, name string) (definitions.MessageTemplate, error)
 	SetTemplate(ctx context.Context, orgID int64, tmpl definitions.MessageTemplate) (definitions.MessageTemplate, error)
-	DeleteTemplate(ctx context.Context, orgID int64, name string) error
+	DeleteTemplate(ctx context.Context, orgID int64, name string, provenance alerting_models.Provenance) error
+	ExportTemplates(ctx context.Context, orgID int64) (definitions.TemplatesExport, error)
+	ImportTemplates(ctx context.Context, orgID int64, doc definitions.TemplatesExport, dryRun, overwrite bool) (definitions.TemplatesImportResult, error)
+	GetTemplateUsage(ctx context.Context, orgID int64, name string) (definitions.TemplateUsage, error)
+	RenameTemplate(ctx context.Context, orgID int64, name, newName string, provenance alerting_models.Provenance) (definitions.MessageTemplate, error)
+	GetDefaultTemplates() definitions.DefaultTemplates
 }
 
 type NotificationPolicyService interface {
-	GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
-	UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p alerting_models.Provenance) error
+	GetPolicyTreeWithHash(ctx context.Context, orgID int64) (definitions.Route, string, error)
+	UpdatePolicyTreeWithHash(ctx context.Context, orgID int64, tree definitions.Route, p alerting_models.Provenance, fetchedConfigurationHash string) error
+	GetPolicyTreeHistory(ctx context.Context, orgID int64) ([]definitions.NotificationPolicyVersion, error)
+	GetPolicyTreeVersion(ctx context.Context, orgID int64, version int64) (definitions.NotificationPolicyVersion, error)
+	RollbackPolicyTree(ctx context.Context, orgID int64, version int64, p alerting_models.Provenance) error
+	ResetPolicyTree(ctx context.Context, orgID int64, mode provisioning.PolicyResetMode) error
+	MoveRoute(ctx context.Context, orgID int64, routeID string, index int) error
+	RepointRoutes(ctx context.Context, orgID int64, from, to string) (int, error)
+	ApplyPolicyTreeTemplate(ctx context.Context, orgID int64, name string, values map[string]string, p alerting_models.Provenance) (definitions.Route, error)
+	LintPolicyTree(ctx context.Context, orgID int64, tree definitions.Route) ([]definitions.PolicyTreeLintWarning, error)
+	GetEffectivePolicies(ctx context.Context, orgID int64) ([]definitions.EffectivePolicy, error)
+	GetPoliciesByReceiver(ctx context.Context, orgID int64, receiver string) ([]definitions.ReceiverPolicy, error)
+	GetPolicyTreeGraph(ctx context.Context, orgID int64) (definitions.PolicyTreeGraph, error)
 }
 
 type MuteTimingService interface {
 	GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error)
+	GetMuteTiming(ctx context.Context, name string, orgID int64) (definitions.MuteTimeInterval, error)
+	ExportMuteTimings(ctx context.Context, orgID int64) (definitions.MuteTimingsExport, error)
 	CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error)
 	UpdateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error)
-	DeleteMuteTiming(ctx context.Context, name string, orgID int64) error
+	DeleteMuteTiming(ctx context.Context, name string, orgID int64, force bool) error
+	RenameMuteTiming(ctx context.Context, name, newName string, orgID int64, p alerting_models.Provenance) (*definitions.MuteTimeInterval, error)
+}
+
+type AlertmanagerImportService interface {
+	Import(ctx context.Context, orgID int64, yml string, dryRun bool) (definitions.AlertmanagerImportResult, error)
+}
+
+type ProvisioningBatchService interface {
+	Apply(ctx context.Context, orgID int64, batch definitions.ProvisioningBatch) (definitions.ProvisioningBatchResult, error)
+}
+
+type ExternalAlertmanagerSyncService interface {
+	GetSyncStatus(ctx context.Context, orgID int64) (definitions.ExternalAlertmanagerSyncStatus, error)
+}
+
+type AlertmanagerConfigValidator interface {
+	ValidateAlertmanagerConfiguration(ctx context.Context, orgID int64, config definitions.PostableUserConfig) ([]error, error)
 }
 
 type AlertRuleService interface {
 	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
+	GetAlertRules(ctx context.Context, orgID int64, folderUID, group string, matchers labels.Matchers) ([]definitions.AlertRule, error)
 	CreateAlertRule(ctx context.Context, rule alerting_models.AlertRule, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
+	CloneAlertRule(ctx context.Context, orgID int64, ruleUID string, clone definitions.AlertRuleClone) (alerting_models.AlertRule, error)
 	UpdateAlertRule(ctx context.Context, rule alerting_models.AlertRule, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
+	PatchAlertRule(ctx context.Context, orgID int64, ruleUID string, patch definitions.AlertRulePatch, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
 	DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance alerting_models.Provenance) error
 	GetRuleGroup(ctx context.Context, orgID int64, folder, group string) (definitions.AlertRuleGroup, error)
-	UpdateRuleGroup(ctx context.Context, orgID int64, folderUID, rulegroup string, interval int64) error
+	GetRuleGroupWithVersion(ctx context.Context, orgID int64, folder, group string) (definitions.AlertRuleGroup, string, error)
+	ExportRuleGroups(ctx context.Context, orgID int64, folderUID string) (definitions.AlertRulesExport, error)
+	UpdateRuleGroup(ctx context.Context, orgID int64, folderUID, rulegroup string, interval int64, labels map[string]string, version string) error
+	SetAlertRulePaused(ctx context.Context, orgID int64, ruleUID string, paused bool) (alerting_models.AlertRule, error)
+	SetAlertRuleProvenance(ctx context.Context, orgID int64, ruleUID string, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
+	SetAlertRulesProvenance(ctx context.Context, orgID int64, ruleUIDs []string, provenance alerting_models.Provenance) (definitions.AlertRulesProvenanceResult, error)
+	SetRuleGroupPaused(ctx context.Context, orgID int64, folderUID, rulegroup string, paused bool, version string) error
+	SetRuleGroupOrder(ctx context.Context, orgID int64, folderUID, rulegroup string, ruleUIDs []string, version string) error
+	CopyRuleGroup(ctx context.Context, orgID int64, folderUID, rulegroup string, cp definitions.AlertRuleGroupCopy) (definitions.AlertRuleGroup, error)
+	DetectRuleDrift(ctx context.Context, orgID int64, document definitions.AlertRulesExport) (definitions.AlertRulesDrift, error)
+	ImportPrometheusRules(ctx context.Context, orgID int64, imp definitions.PrometheusRulesImport, dryRun bool) (definitions.PrometheusRulesImportResult, error)
+	DeleteRuleGroup(ctx context.Context, orgID int64, folderUID, rulegroup string, provenance alerting_models.Provenance, force bool, version string) (definitions.RuleGroupDeletion, error)
+}
+
+type RuleTemplateService interface {
+	GetRuleTemplates(ctx context.Context, orgID int64) ([]definitions.RuleTemplate, error)
+	GetRuleTemplate(ctx context.Context, orgID int64, name string) (definitions.RuleTemplate, error)
+	SetRuleTemplate(ctx context.Context, orgID int64, tmpl definitions.RuleTemplate) (definitions.RuleTemplate, error)
+	DeleteRuleTemplate(ctx context.Context, orgID int64, name string) error
+	RenderRuleTemplate(ctx context.Context, orgID int64, name string, instance definitions.RuleTemplateInstance) (alerting_models.AlertRule, error)
+}
+
+type ProvisioningWebhookService interface {
+	GetWebhooks(ctx context.Context, orgID int64) ([]definitions.ProvisioningWebhook, error)
+	GetWebhook(ctx context.Context, orgID int64, uid string) (definitions.ProvisioningWebhook, error)
+	CreateWebhook(ctx context.Context, orgID int64, webhook definitions.ProvisioningWebhook) (definitions.ProvisioningWebhook, error)
+	UpdateWebhook(ctx context.Context, orgID int64, webhook definitions.ProvisioningWebhook) (definitions.ProvisioningWebhook, error)
+	DeleteWebhook(ctx context.Context, orgID int64, uid string) error
+	Subscribed(ctx context.Context, orgID int64, resource string) bool
+	Notify(ctx context.Context, orgID int64, change definitions.ProvisioningChange)
+}
+
+type RuleFolderService interface {
+	EnsureFolder(ctx context.Context, user *models.SignedInUser, orgID int64, uid string, folder *definitions.RuleImportFolder, dryRun bool) (bool, error)
+	DeleteFolder(ctx context.Context, user *models.SignedInUser, orgID int64, uid string)
 }
 
 func (srv *ProvisioningSrv) RouteGetPolicyTree(c *models.ReqContext) response.Response {
-	policies, err := srv.policies.GetPolicyTree(c.Req.Context(), c.OrgId)
+	policies, hash, err := srv.policies.GetPolicyTreeWithHash(c.Req.Context(), c.OrgId)
 	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
 		return ErrResp(http.StatusNotFound, err, "")
 	}
@@ -67,14 +157,22 @@ func (srv *ProvisioningSrv) RouteGetPolicyTree(c *models.ReqContext) response.Re
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
 
-	return response.JSON(http.StatusOK, policies)
+	return response.JSON(http.StatusOK, policies).SetHeader("ETag", strconv.Quote(hash))
 }
 
 func (srv *ProvisioningSrv) RoutePutPolicyTree(c *models.ReqContext, tree definitions.Route) response.Response {
-	err := srv.policies.UpdatePolicyTree(c.Req.Context(), c.OrgId, tree, alerting_models.ProvenanceAPI)
+	before := srv.policyTreeSnapshot(c)
+	err := srv.policies.UpdatePolicyTreeWithHash(c.Req.Context(), c.OrgId, tree, alerting_models.ProvenanceAPI, ifMatch(c))
 	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
 		return ErrResp(http.StatusNotFound, err, "")
 	}
+	if errors.Is(err, provisioning.ErrVersionConflict) || errors.Is(err, store.ErrVersionLockedObjectNotFound) {
+		return ErrResp(http.StatusConflict, provisioning.ErrVersionConflict, "")
+	}
+	var missing *definitions.MissingTimeIntervalsError
+	if errors.As(err, &missing) {
+		return response.JSON(http.StatusBadRequest, util.DynMap{"message": err.Error(), "missingTimeIntervals": missing.Names})
+	}
 	if errors.Is(err, provisioning.ErrValidation) {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
@@ -82,15 +180,194 @@ func (srv *ProvisioningSrv) RoutePutPolicyTree(c *models.ReqContext, tree defini
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
 
+	srv.notifyChange(c, definitions.ProvisioningResourceNotificationPolicy, "", before, srv.policyTreeSnapshot(c))
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "policies updated"})
 }
 
+func (srv *ProvisioningSrv) RouteGetEffectivePolicies(c *models.ReqContext) response.Response {
+	policies, err := srv.policies.GetEffectivePolicies(c.Req.Context(), c.OrgId)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	return response.JSON(http.StatusOK, policies)
+}
+
+func (srv *ProvisioningSrv) RouteGetPoliciesByReceiver(c *models.ReqContext) response.Response {
+	receiver := c.Query("receiver")
+	if receiver == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("the receiver query parameter is required"), "")
+	}
+	policies, err := srv.policies.GetPoliciesByReceiver(c.Req.Context(), c.OrgId, receiver)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	return response.JSON(http.StatusOK, policies)
+}
+
+func (srv *ProvisioningSrv) RouteGetPolicyTreeGraph(c *models.ReqContext) response.Response {
+	format := c.Query("format")
+	if format != "" && format != "json" && format != "dot" {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("unknown format %q, expected either json or dot", format), "")
+	}
+	graph, err := srv.policies.GetPolicyTreeGraph(c.Req.Context(), c.OrgId)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	if format == "dot" {
+		return response.Respond(http.StatusOK, provisioning.PolicyTreeGraphDOT(graph)).
+			SetHeader("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	}
+	return response.JSON(http.StatusOK, graph)
+}
+
+func (srv *ProvisioningSrv) RouteGetPolicyTreeHistory(c *models.ReqContext) response.Response {
+	versions, err := srv.policies.GetPolicyTreeHistory(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, versions)
+}
+
+func (srv *ProvisioningSrv) RouteGetPolicyTreeVersion(c *models.ReqContext, version string) response.Response {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid version")
+	}
+	policies, err := srv.policies.GetPolicyTreeVersion(c.Req.Context(), c.OrgId, v)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, policies)
+}
+
+func (srv *ProvisioningSrv) RoutePostPolicyTreeRollback(c *models.ReqContext, version string) response.Response {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid version")
+	}
+	before := srv.policyTreeSnapshot(c)
+	err = srv.policies.RollbackPolicyTree(c.Req.Context(), c.OrgId, v, alerting_models.ProvenanceAPI)
+	if errors.Is(err, provisioning.ErrNotFound) || errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	srv.notifyChange(c, definitions.ProvisioningResourceNotificationPolicy, "", before, srv.policyTreeSnapshot(c))
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "policies rolled back"})
+}
+
+func (srv *ProvisioningSrv) RoutePostPolicyMove(c *models.ReqContext, move definitions.PolicyMove, id string) response.Response {
+	before := srv.policyTreeSnapshot(c)
+	err := srv.policies.MoveRoute(c.Req.Context(), c.OrgId, id, move.Index)
+	if errors.Is(err, provisioning.ErrNotFound) || errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	srv.notifyChange(c, definitions.ProvisioningResourceNotificationPolicy, "", before, srv.policyTreeSnapshot(c))
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "policy moved"})
+}
+
+func (srv *ProvisioningSrv) RoutePostPolicyRepoint(c *models.ReqContext, repoint definitions.PolicyRepoint) response.Response {
+	before := srv.policyTreeSnapshot(c)
+	updated, err := srv.policies.RepointRoutes(c.Req.Context(), c.OrgId, repoint.From, repoint.To)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if updated > 0 {
+		srv.notifyChange(c, definitions.ProvisioningResourceNotificationPolicy, "", before, srv.policyTreeSnapshot(c))
+	}
+	return response.JSON(http.StatusOK, definitions.PolicyRepointResult{Updated: updated})
+}
+
+func (srv *ProvisioningSrv) RouteGetPolicyTreeTemplates(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, provisioning.PolicyTreeTemplates())
+}
+
+func (srv *ProvisioningSrv) RoutePostPolicyTreeTemplate(c *models.ReqContext, values definitions.PolicyTreeTemplateValues, name string) response.Response {
+	tree, err := srv.policies.ApplyPolicyTreeTemplate(c.Req.Context(), c.OrgId, name, values.Values, alerting_models.ProvenanceAPI)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) || errors.Is(err, provisioning.ErrNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, tree)
+}
+
+func (srv *ProvisioningSrv) RoutePostPolicyTreeLint(c *models.ReqContext, tree definitions.Route) response.Response {
+	warnings, err := srv.policies.LintPolicyTree(c.Req.Context(), c.OrgId, tree)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, warnings)
+}
+
+func (srv *ProvisioningSrv) RouteResetPolicyTree(c *models.ReqContext) response.Response {
+	mode := provisioning.PolicyResetFull
+	if m := c.Query("mode"); m != "" {
+		mode = provisioning.PolicyResetMode(m)
+	}
+	before := srv.policyTreeSnapshot(c)
+	err := srv.policies.ResetPolicyTree(c.Req.Context(), c.OrgId, mode)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	srv.notifyChange(c, definitions.ProvisioningResourceNotificationPolicy, "", before, srv.policyTreeSnapshot(c))
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "policies reset"})
+}
+
 func (srv *ProvisioningSrv) RouteGetContactPoints(c *models.ReqContext) response.Response {
 	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), c.OrgId)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, cps)
+	filtered := make([]definitions.EmbeddedContactPoint, 0, len(cps))
+	for _, cp := range cps {
+		if nameMatches(c, cp.Name) {
+			filtered = append(filtered, cp)
+		}
+	}
+	start, end := listPage(c, len(filtered))
+	return response.JSONFields(http.StatusOK, filtered[start:end], response.Fields(c), "")
 }
 
 func (srv *ProvisioningSrv) RoutePostContactPoint(c *models.ReqContext, cp definitions.EmbeddedContactPoint) response.Response {
@@ -102,11 +379,14 @@ func (srv *ProvisioningSrv) RoutePostContactPoint(c *models.ReqContext, cp defin
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
+	srv.notifyChange(c, definitions.ProvisioningResourceContactPoint, contactPoint.UID, nil, srv.contactPointSnapshot(c, contactPoint.UID))
+	contactPoint.DuplicateOf = srv.contactPointDuplicatesOf(c, contactPoint.UID)
 	return response.JSON(http.StatusAccepted, contactPoint)
 }
 
 func (srv *ProvisioningSrv) RoutePutContactPoint(c *models.ReqContext, cp definitions.EmbeddedContactPoint, UID string) response.Response {
 	cp.UID = UID
+	before := srv.contactPointSnapshot(c, UID)
 	err := srv.contactPointService.UpdateContactPoint(c.Req.Context(), c.OrgId, cp, alerting_models.ProvenanceAPI)
 	if errors.Is(err, provisioning.ErrValidation) {
 		return ErrResp(http.StatusBadRequest, err, "")
@@ -117,14 +397,43 @@ func (srv *ProvisioningSrv) RoutePutContactPoint(c *models.ReqContext, cp defini
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint updated"})
+	srv.notifyChange(c, definitions.ProvisioningResourceContactPoint, UID, before, srv.contactPointSnapshot(c, UID))
+	body := util.DynMap{"message": "contactpoint updated"}
+	if duplicates := srv.contactPointDuplicatesOf(c, UID); len(duplicates) > 0 {
+		body["duplicateOf"] = duplicates
+	}
+	return response.JSON(http.StatusAccepted, body)
+}
+
+func (srv *ProvisioningSrv) RouteGetContactPointDuplicates(c *models.ReqContext) response.Response {
+	duplicates, err := srv.contactPointService.GetContactPointDuplicates(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, duplicates)
+}
+
+// contactPointDuplicatesOf returns the UIDs of the contact points with the same settings as the saved contact point.
+// Failing to find them does not fail the request, the contact point is saved already.
+func (srv *ProvisioningSrv) contactPointDuplicatesOf(c *models.ReqContext, uid string) []string {
+	duplicates, err := srv.contactPointService.GetContactPointDuplicatesOf(c.Req.Context(), c.OrgId, uid)
+	if err != nil {
+		srv.log.Warn("failed to find duplicates of contact point", "uid", uid, "err", err)
+		return nil
+	}
+	if len(duplicates) > 0 {
+		srv.log.Info("contact point has the same settings as other contact points", "uid", uid, "duplicates", duplicates)
+	}
+	return duplicates
 }
 
 func (srv *ProvisioningSrv) RouteDeleteContactPoint(c *models.ReqContext, UID string) response.Response {
+	before := srv.contactPointSnapshot(c, UID)
 	err := srv.contactPointService.DeleteContactPoint(c.Req.Context(), c.OrgId, UID)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
+	srv.notifyChange(c, definitions.ProvisioningResourceContactPoint, UID, before, nil)
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint deleted"})
 }
 
@@ -133,22 +442,29 @@ func (srv *ProvisioningSrv) RouteGetTemplates(c *models.ReqContext) response.Res
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	result := make([]definitions.MessageTemplate, 0, len(templates))
-	for k, v := range templates {
-		result = append(result, definitions.MessageTemplate{Name: k, Template: v})
+	filtered := make([]definitions.MessageTemplate, 0, len(templates))
+	for _, tmpl := range templates {
+		if nameMatches(c, tmpl.Name) {
+			filtered = append(filtered, tmpl)
+		}
 	}
-	return response.JSON(http.StatusOK, result)
+	start, end := listPage(c, len(filtered))
+	return response.JSON(http.StatusOK, filtered[start:end])
+}
+
+func (srv *ProvisioningSrv) RouteGetDefaultTemplates(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, srv.templates.GetDefaultTemplates())
 }
 
 func (srv *ProvisioningSrv) RouteGetTemplate(c *models.ReqContext, name string) response.Response {
-	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.OrgId)
+	tmpl, err := srv.templates.GetTemplate(c.Req.Context(), c.OrgId, name)
 	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	if tmpl, ok := templates[name]; ok {
-		return response.JSON(http.StatusOK, definitions.MessageTemplate{Name: name, Template: tmpl})
-	}
-	return response.Empty(http.StatusNotFound)
+	return response.JSON(http.StatusOK, tmpl)
 }
 
 func (srv *ProvisioningSrv) RoutePutTemplate(c *models.ReqContext, body definitions.MessageTemplateContent, name string) response.Response {
@@ -168,139 +484,923 @@ func (srv *ProvisioningSrv) RoutePutTemplate(c *models.ReqContext, body definiti
 }
 
 func (srv *ProvisioningSrv) RouteDeleteTemplate(c *models.ReqContext, name string) response.Response {
-	err := srv.templates.DeleteTemplate(c.Req.Context(), c.OrgId, name)
+	err := srv.templates.DeleteTemplate(c.Req.Context(), c.OrgId, name, alerting_models.ProvenanceAPI)
 	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusNoContent, nil)
 }
 
-func (srv *ProvisioningSrv) RouteGetMuteTiming(c *models.ReqContext, name string) response.Response {
-	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgId)
+func (srv *ProvisioningSrv) RouteGetTemplateUsage(c *models.ReqContext, name string) response.Response {
+	usage, err := srv.templates.GetTemplateUsage(c.Req.Context(), c.OrgId, name)
 	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	for _, timing := range timings {
-		if name == timing.Name {
-			return response.JSON(http.StatusOK, timing)
+	return response.JSON(http.StatusOK, usage)
+}
+
+func (srv *ProvisioningSrv) RoutePostTemplateRename(c *models.ReqContext, rename definitions.MessageTemplateRename, name string) response.Response {
+	renamed, err := srv.templates.RenameTemplate(c.Req.Context(), c.OrgId, name, rename.Name, alerting_models.ProvenanceAPI)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
 		}
+		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.Empty(http.StatusNotFound)
+	return response.JSON(http.StatusOK, renamed)
 }
 
-func (srv *ProvisioningSrv) RouteGetMuteTimings(c *models.ReqContext) response.Response {
-	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgId)
+func (srv *ProvisioningSrv) RouteGetTemplatesExport(c *models.ReqContext) response.Response {
+	format := c.Query("format")
+	if format == "" {
+		format = "yaml"
+	}
+	if format != "yaml" && format != "json" {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("unknown format %q, expected either yaml or json", format), "")
+	}
+	export, err := srv.templates.ExportTemplates(c.Req.Context(), c.OrgId)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, timings)
+
+	var resp *response.NormalResponse
+	if format == "json" {
+		resp = response.JSON(http.StatusOK, export)
+	} else {
+		body, err := yaml.Marshal(export)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to marshal templates")
+		}
+		resp = response.Respond(http.StatusOK, body).SetHeader("Content-Type", "application/yaml")
+	}
+	if c.QueryBool("download") {
+		resp.SetHeader("Content-Disposition", fmt.Sprintf(`attachment;filename=templates.%s`, format))
+	}
+	return resp
 }
 
-func (srv *ProvisioningSrv) RoutePostMuteTiming(c *models.ReqContext, mt definitions.MuteTimeInterval) response.Response {
-	mt.Provenance = alerting_models.ProvenanceAPI
-	created, err := srv.muteTimings.CreateMuteTiming(c.Req.Context(), mt, c.OrgId)
+func (srv *ProvisioningSrv) RoutePostTemplatesImport(c *models.ReqContext, doc definitions.TemplatesExport) response.Response {
+	result, err := srv.templates.ImportTemplates(c.Req.Context(), c.OrgId, doc, c.QueryBool("dryRun"), c.QueryBool("overwrite"))
 	if err != nil {
+		if errors.Is(err, provisioning.ErrImportConflict) {
+			return response.JSON(http.StatusConflict, result)
+		}
 		if errors.Is(err, provisioning.ErrValidation) {
 			return ErrResp(http.StatusBadRequest, err, "")
 		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusCreated, created)
+	return response.JSON(http.StatusOK, result)
 }
 
-func (srv *ProvisioningSrv) RoutePutMuteTiming(c *models.ReqContext, mt definitions.MuteTimeInterval, name string) response.Response {
-	mt.Name = name
-	mt.Provenance = alerting_models.ProvenanceAPI
-	updated, err := srv.muteTimings.UpdateMuteTiming(c.Req.Context(), mt, c.OrgId)
+func (srv *ProvisioningSrv) RoutePostAlertmanagerImport(c *models.ReqContext, body definitions.AlertmanagerImport) response.Response {
+	result, err := srv.alertmanagerImport.Import(c.Req.Context(), c.OrgId, body.Config, c.QueryBool("dryRun"))
 	if err != nil {
+		if errors.Is(err, provisioning.ErrImportConflict) {
+			return response.JSON(http.StatusConflict, result)
+		}
 		if errors.Is(err, provisioning.ErrValidation) {
 			return ErrResp(http.StatusBadRequest, err, "")
 		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	if updated == nil {
-		return response.Empty(http.StatusNotFound)
-	}
-	return response.JSON(http.StatusAccepted, updated)
+	return response.JSON(http.StatusOK, result)
 }
 
-func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *models.ReqContext, name string) response.Response {
-	err := srv.muteTimings.DeleteMuteTiming(c.Req.Context(), name, c.OrgId)
+func (srv *ProvisioningSrv) RoutePostProvisioningBatch(c *models.ReqContext, body definitions.ProvisioningBatch) response.Response {
+	result, err := srv.batch.Apply(c.Req.Context(), c.OrgId, body)
 	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, provisioning.ErrVersionConflict) || errors.Is(err, store.ErrVersionLockedObjectNotFound) {
+			return ErrResp(http.StatusConflict, provisioning.ErrVersionConflict, "")
+		}
+		var inUse provisioning.MuteTimingInUseError
+		if errors.As(err, &inUse) {
+			return response.JSON(http.StatusConflict, util.DynMap{"message": err.Error(), "policies": inUse.Policies})
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusNoContent, nil)
+	return response.JSON(http.StatusAccepted, result)
 }
 
-func (srv *ProvisioningSrv) RouteRouteGetAlertRule(c *models.ReqContext, UID string) response.Response {
-	rule, provenace, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, UID)
+func (srv *ProvisioningSrv) RouteGetExternalAlertmanagerSyncStatus(c *models.ReqContext) response.Response {
+	status, err := srv.externalAMSync.GetSyncStatus(c.Req.Context(), c.OrgId)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, definitions.NewAlertRule(rule, provenace))
+	return response.JSON(http.StatusOK, status)
 }
 
-func (srv *ProvisioningSrv) RoutePostAlertRule(c *models.ReqContext, ar definitions.AlertRule) response.Response {
-	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), ar.UpstreamModel(), alerting_models.ProvenanceAPI)
-	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
-	}
+func (srv *ProvisioningSrv) RoutePostAlertmanagerConfigValidate(c *models.ReqContext, body definitions.PostableUserConfig) response.Response {
+	errs, err := srv.alertmanagerConfig.ValidateAlertmanagerConfiguration(c.Req.Context(), c.OrgId, body)
 	if err != nil {
-		if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+		if errors.Is(err, notifier.ErrNoAlertmanagerForOrg) {
+			return response.Error(http.StatusNotFound, err.Error(), err)
 		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	ar.ID = createdAlertRule.ID
-	ar.UID = createdAlertRule.UID
-	ar.Updated = createdAlertRule.Updated
-	return response.JSON(http.StatusCreated, ar)
+	result := definitions.AlertmanagerConfigValidation{Valid: len(errs) == 0, Errors: make([]string, 0, len(errs))}
+	for _, err := range errs {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	return response.JSON(http.StatusOK, result)
 }
 
-func (srv *ProvisioningSrv) RoutePutAlertRule(c *models.ReqContext, ar definitions.AlertRule, UID string) response.Response {
-	updated := ar.UpstreamModel()
-	updated.UID = UID
-	updatedAlertRule, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), ar.UpstreamModel(), alerting_models.ProvenanceAPI)
-	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
-		return response.Empty(http.StatusNotFound)
-	}
-	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
-	}
+func (srv *ProvisioningSrv) RouteGetMuteTiming(c *models.ReqContext, name string) response.Response {
+	timing, err := srv.muteTimings.GetMuteTiming(c.Req.Context(), name, c.OrgId)
 	if err != nil {
-		if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
 		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	ar.Updated = updatedAlertRule.Updated
-	return response.JSON(http.StatusOK, ar)
+	return response.JSON(http.StatusOK, timing)
 }
 
-func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *models.ReqContext, UID string) response.Response {
-	err := srv.alertRules.DeleteAlertRule(c.Req.Context(), c.OrgId, UID, alerting_models.ProvenanceAPI)
+func (srv *ProvisioningSrv) RouteGetMuteTimings(c *models.ReqContext) response.Response {
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgId)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusNoContent, "")
+	filtered := make([]definitions.MuteTimeInterval, 0, len(timings))
+	for _, timing := range timings {
+		if nameMatches(c, timing.Name) {
+			filtered = append(filtered, timing)
+		}
+	}
+	start, end := listPage(c, len(filtered))
+	return response.JSON(http.StatusOK, filtered[start:end])
 }
 
-func (srv *ProvisioningSrv) RouteGetAlertRuleGroup(c *models.ReqContext, folder string, group string) response.Response {
-	g, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.OrgId, folder, group)
+func (srv *ProvisioningSrv) RouteGetMuteTimingsExport(c *models.ReqContext) response.Response {
+	format := c.Query("format")
+	if format == "" {
+		format = "yaml"
+	}
+	if format != "yaml" && format != "json" {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("unknown format %q, expected either yaml or json", format), "")
+	}
+	export, err := srv.muteTimings.ExportMuteTimings(c.Req.Context(), c.OrgId)
 	if err != nil {
-		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
-			return ErrResp(http.StatusNotFound, err, "")
-		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, g)
+
+	var resp *response.NormalResponse
+	if format == "json" {
+		resp = response.JSON(http.StatusOK, export)
+	} else {
+		body, err := yaml.Marshal(export)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to marshal mute timings")
+		}
+		resp = response.Respond(http.StatusOK, body).SetHeader("Content-Type", "application/yaml")
+	}
+	if c.QueryBool("download") {
+		resp.SetHeader("Content-Disposition", fmt.Sprintf(`attachment;filename=mute-timings.%s`, format))
+	}
+	return resp
 }
 
-func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *models.ReqContext, ag definitions.AlertRuleGroupMetadata, folderUID string, group string) response.Response {
-	err := srv.alertRules.UpdateRuleGroup(c.Req.Context(), c.OrgId, folderUID, group, ag.Interval)
+func (srv *ProvisioningSrv) RoutePostMuteTiming(c *models.ReqContext, mt definitions.MuteTimeInterval) response.Response {
+	mt.Provenance = alerting_models.ProvenanceAPI
+	created, err := srv.muteTimings.CreateMuteTiming(c.Req.Context(), mt, c.OrgId)
 	if err != nil {
-		if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutMuteTiming(c *models.ReqContext, mt definitions.MuteTimeInterval, name string) response.Response {
+	mt.Name = name
+	mt.Provenance = alerting_models.ProvenanceAPI
+	updated, err := srv.muteTimings.UpdateMuteTiming(c.Req.Context(), mt, c.OrgId)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if updated == nil {
+		return response.Empty(http.StatusNotFound)
+	}
+	return response.JSON(http.StatusAccepted, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *models.ReqContext, name string) response.Response {
+	err := srv.muteTimings.DeleteMuteTiming(c.Req.Context(), name, c.OrgId, c.QueryBool("force"))
+	if err != nil {
+		var inUse provisioning.MuteTimingInUseError
+		if errors.As(err, &inUse) {
+			return response.JSON(http.StatusConflict, util.DynMap{"message": err.Error(), "policies": inUse.Policies})
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RoutePostMuteTimingRename(c *models.ReqContext, rename definitions.MuteTimingRename, name string) response.Response {
+	renamed, err := srv.muteTimings.RenameMuteTiming(c.Req.Context(), name, rename.Name, c.OrgId, alerting_models.ProvenanceAPI)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, renamed)
+}
+
+// provisioningVersion returns the payload schema version that the request pins with the version header.
+func provisioningVersion(c *models.ReqContext) (string, error) {
+	return definitions.ParseProvisioningVersion(c.Req.Header.Get(definitions.ProvisioningVersionHeader))
+}
+
+// alertRuleValidationErrResp returns the response to a request saving an invalid alert rule. The datasources that
+// are referenced by the queries of the rule but do not exist or do not support alerting are listed.
+func alertRuleValidationErrResp(err error) response.Response {
+	var refErr provisioning.DatasourceReferenceError
+	if errors.As(err, &refErr) {
+		return response.JSON(http.StatusBadRequest, util.DynMap{"message": err.Error(), "missingDatasources": refErr.Missing, "incompatibleDatasources": refErr.Incompatible})
+	}
+	return ErrResp(http.StatusBadRequest, err, "")
+}
+
+// ifMatch returns the ETag of the If-Match header of the request, without the weak prefix and the quotes.
+func ifMatch(c *models.ReqContext) string {
+	etag := strings.TrimPrefix(c.Req.Header.Get("If-Match"), "W/")
+	if unquoted, err := strconv.Unquote(etag); err == nil {
+		etag = unquoted
+	}
+	return etag
+}
+
+// webhookSubscribed returns true if a provisioning webhook of the organization is notified of the changes of the
+// resource.
+func (srv *ProvisioningSrv) webhookSubscribed(c *models.ReqContext, resource string) bool {
+	return srv.webhooks != nil && srv.webhooks.Subscribed(c.Req.Context(), c.OrgId, resource)
+}
+
+// notifyChange notifies the provisioning webhooks of the change of a resource. The resource was created if it has no
+// state before the change, and deleted if it has none after.
+func (srv *ProvisioningSrv) notifyChange(c *models.ReqContext, resource, uid string, before, after interface{}) {
+	if !srv.webhookSubscribed(c, resource) || (before == nil && after == nil) {
+		return
+	}
+	action := definitions.ProvisioningChangeUpdated
+	if before == nil {
+		action = definitions.ProvisioningChangeCreated
+	} else if after == nil {
+		action = definitions.ProvisioningChangeDeleted
+	}
+	change := definitions.ProvisioningChange{
+		Action:   action,
+		Resource: resource,
+		UID:      uid,
+		Before:   before,
+		After:    after,
+	}
+	if c.SignedInUser != nil {
+		change.User = c.SignedInUser.Login
+	}
+	srv.webhooks.Notify(c.Req.Context(), c.OrgId, change)
+}
+
+// alertRuleSnapshot returns the stored alert rule for the provisioning webhooks, or nil if they are not notified of
+// the changes of alert rules or if the rule does not exist.
+func (srv *ProvisioningSrv) alertRuleSnapshot(c *models.ReqContext, uid string) interface{} {
+	if !srv.webhookSubscribed(c, definitions.ProvisioningResourceAlertRule) {
+		return nil
+	}
+	rule, provenance, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, uid)
+	if err != nil {
+		return nil
+	}
+	return definitions.NewAlertRule(rule, provenance)
+}
+
+// contactPointSnapshot returns the contact point with redacted secrets for the provisioning webhooks, or nil if they
+// are not notified of the changes of contact points or if the contact point does not exist.
+func (srv *ProvisioningSrv) contactPointSnapshot(c *models.ReqContext, uid string) interface{} {
+	if !srv.webhookSubscribed(c, definitions.ProvisioningResourceContactPoint) {
+		return nil
+	}
+	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return nil
+	}
+	for _, cp := range cps {
+		if cp.UID == uid {
+			return cp
+		}
+	}
+	return nil
+}
+
+// policyTreeSnapshot returns the notification policy tree for the provisioning webhooks, or nil if they are not
+// notified of the changes of the tree.
+func (srv *ProvisioningSrv) policyTreeSnapshot(c *models.ReqContext) interface{} {
+	if !srv.webhookSubscribed(c, definitions.ProvisioningResourceNotificationPolicy) {
+		return nil
+	}
+	tree, _, err := srv.policies.GetPolicyTreeWithHash(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return nil
+	}
+	return tree
+}
+
+// alertRuleResponse returns the alert rule in the schema of the version. Responses in a deprecated version say so
+// with the Deprecation and Warning headers.
+func alertRuleResponse(status int, version string, rule definitions.AlertRule) response.Response {
+	return versionedResponse(response.JSON(status, definitions.VersionedAlertRule(version, rule)), version)
+}
+
+// versionedResponse sets the provisioning version headers of a response whose body is in the schema of the version.
+func versionedResponse(resp *response.NormalResponse, version string) response.Response {
+	resp.SetHeader(definitions.ProvisioningVersionHeader, version)
+	if definitions.ProvisioningVersionDeprecated(version) {
+		resp.SetHeader("Deprecation", "true")
+		resp.SetHeader("Warning", fmt.Sprintf(`299 - "provisioning API version %s is deprecated, use %s"`, version, definitions.ProvisioningVersionV1))
+	}
+	return resp
+}
+
+func (srv *ProvisioningSrv) RouteRouteGetAlertRule(c *models.ReqContext, UID string) response.Response {
+	version, err := provisioningVersion(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	rule, provenace, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, UID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return alertRuleResponse(http.StatusOK, version, definitions.NewAlertRule(rule, provenace))
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRules(c *models.ReqContext) response.Response {
+	version, err := provisioningVersion(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	matchers, err := labelSelectors(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	rules, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.OrgId, c.Query("folderUid"), c.Query("ruleGroup"), matchers)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	filtered := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		if nameMatches(c, rule.Title) {
+			filtered = append(filtered, definitions.VersionedAlertRule(version, rule))
+		}
+	}
+	start, end := listPage(c, len(filtered))
+	return versionedResponse(response.JSONFields(http.StatusOK, filtered[start:end], response.Fields(c), ""), version)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRule(c *models.ReqContext, ar definitions.AlertRule) response.Response {
+	version, err := provisioningVersion(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if version == definitions.ProvisioningVersionV0Alpha1 {
+		ar = definitions.NewAlertRuleV0Alpha1(ar).V1(nil)
+	}
+	rule := ar.UpstreamModel()
+	if violations, err := srv.ruleLint.CheckRules(c.Req.Context(), c.OrgId, []*alerting_models.AlertRule{&rule}); err != nil {
+		return ruleLintErrorResp(err, violations)
+	}
+	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), rule, alerting_models.ProvenanceAPI)
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return alertRuleValidationErrResp(err)
+	}
+	if errors.Is(err, provisioning.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	ar.ID = createdAlertRule.ID
+	ar.UID = createdAlertRule.UID
+	ar.Updated = createdAlertRule.Updated
+	srv.notifyChange(c, definitions.ProvisioningResourceAlertRule, createdAlertRule.UID, nil, definitions.NewAlertRule(createdAlertRule, alerting_models.ProvenanceAPI))
+	return alertRuleResponse(http.StatusCreated, version, ar)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleClone(c *models.ReqContext, clone definitions.AlertRuleClone, UID string) response.Response {
+	version, err := provisioningVersion(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	rule, err := srv.alertRules.CloneAlertRule(c.Req.Context(), c.OrgId, UID, clone)
+	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+		return response.Empty(http.StatusNotFound)
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if violations, err := srv.ruleLint.CheckRules(c.Req.Context(), c.OrgId, []*alerting_models.AlertRule{&rule}); err != nil {
+		return ruleLintErrorResp(err, violations)
+	}
+	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), rule, alerting_models.ProvenanceAPI)
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return alertRuleValidationErrResp(err)
+	}
+	if errors.Is(err, provisioning.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	created := definitions.NewAlertRule(createdAlertRule, alerting_models.ProvenanceAPI)
+	srv.notifyChange(c, definitions.ProvisioningResourceAlertRule, createdAlertRule.UID, nil, created)
+	return alertRuleResponse(http.StatusCreated, version, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRule(c *models.ReqContext, ar definitions.AlertRule, UID string) response.Response {
+	version, err := provisioningVersion(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if version == definitions.ProvisioningVersionV0Alpha1 {
+		stored, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, ar.UID)
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		ar = definitions.NewAlertRuleV0Alpha1(ar).V1(&stored)
+	}
+	updated := ar.UpstreamModel()
+	updated.UID = UID
+	before := srv.alertRuleSnapshot(c, UID)
+	if violations, err := srv.ruleLint.CheckRules(c.Req.Context(), c.OrgId, []*alerting_models.AlertRule{&updated}); err != nil {
+		return ruleLintErrorResp(err, violations)
+	}
+	updatedAlertRule, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), ar.UpstreamModel(), alerting_models.ProvenanceAPI)
+	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+		return response.Empty(http.StatusNotFound)
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return alertRuleValidationErrResp(err)
+	}
+	if errors.Is(err, provisioning.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	ar.Updated = updatedAlertRule.Updated
+	srv.notifyChange(c, definitions.ProvisioningResourceAlertRule, UID, before, definitions.NewAlertRule(updatedAlertRule, alerting_models.ProvenanceAPI))
+	return alertRuleResponse(http.StatusOK, version, ar)
+}
+
+func (srv *ProvisioningSrv) RoutePatchAlertRule(c *models.ReqContext, patch definitions.AlertRulePatch, UID string) response.Response {
+	version, err := provisioningVersion(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	before := srv.alertRuleSnapshot(c, UID)
+	rule, err := srv.alertRules.PatchAlertRule(c.Req.Context(), c.OrgId, UID, patch, alerting_models.ProvenanceAPI)
+	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+		return response.Empty(http.StatusNotFound)
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) || errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if violations, err := srv.ruleLint.CheckRules(c.Req.Context(), c.OrgId, []*alerting_models.AlertRule{&rule}); err != nil {
+		return ruleLintErrorResp(err, violations)
+	}
+	updatedAlertRule, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), rule, alerting_models.ProvenanceAPI)
+	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+		return response.Empty(http.StatusNotFound)
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return alertRuleValidationErrResp(err)
+	}
+	if errors.Is(err, provisioning.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	patched := definitions.NewAlertRule(updatedAlertRule, alerting_models.ProvenanceAPI)
+	srv.notifyChange(c, definitions.ProvisioningResourceAlertRule, UID, before, patched)
+	return alertRuleResponse(http.StatusOK, version, patched)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *models.ReqContext, UID string) response.Response {
+	before := srv.alertRuleSnapshot(c, UID)
+	err := srv.alertRules.DeleteAlertRule(c.Req.Context(), c.OrgId, UID, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	srv.notifyChange(c, definitions.ProvisioningResourceAlertRule, UID, before, nil)
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRulePause(c *models.ReqContext, pause definitions.AlertRulePause, UID string) response.Response {
+	_, err := srv.alertRules.SetAlertRulePaused(c.Req.Context(), c.OrgId, UID, pause.IsPaused)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, pause)
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleProvenance(c *models.ReqContext, provenance definitions.AlertRuleProvenance, UID string) response.Response {
+	_, err := srv.alertRules.SetAlertRuleProvenance(c.Req.Context(), c.OrgId, UID, provenance.Provenance)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, provenance)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRulesProvenance(c *models.ReqContext, provenance definitions.AlertRulesProvenance) response.Response {
+	result, err := srv.alertRules.SetAlertRulesProvenance(c.Req.Context(), c.OrgId, provenance.RuleUIDs, provenance.Provenance)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleGroup(c *models.ReqContext, folder string, group string) response.Response {
+	g, version, err := srv.alertRules.GetRuleGroupWithVersion(c.Req.Context(), c.OrgId, folder, group)
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSONFields(http.StatusOK, g, response.Fields(c), "rules").SetHeader("ETag", strconv.Quote(version))
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleGroupsExport(c *models.ReqContext, folderUID string) response.Response {
+	format := c.Query("format")
+	if format == "" {
+		format = "yaml"
+	}
+	if format != "yaml" && format != "json" && format != "hcl" && format != "prometheus" {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("unknown format %q, expected either yaml, json, hcl or prometheus", format), "")
+	}
+	export, err := srv.alertRules.ExportRuleGroups(c.Req.Context(), c.OrgId, folderUID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	var resp *response.NormalResponse
+	switch format {
+	case "json":
+		resp = response.JSON(http.StatusOK, export)
+	case "hcl":
+		var tree *definitions.Route
+		policies, _, err := srv.policies.GetPolicyTreeWithHash(c.Req.Context(), c.OrgId)
+		if err != nil && !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		if err == nil {
+			tree = &policies
+		}
+		resp = response.Respond(http.StatusOK, provisioning.RuleGroupsHCL(export, tree)).
+			SetHeader("Content-Type", "text/plain; charset=utf-8")
+	case "prometheus":
+		body, err := provisioning.RuleGroupsPrometheus(export)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to marshal alert rules")
+		}
+		resp = response.Respond(http.StatusOK, body).SetHeader("Content-Type", "application/yaml")
+	default:
+		body, err := yaml.Marshal(export)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to marshal alert rules")
+		}
+		resp = response.Respond(http.StatusOK, body).SetHeader("Content-Type", "application/yaml")
+	}
+	if c.QueryBool("download") {
+		extension := format
+		switch format {
+		case "hcl":
+			extension = "tf"
+		case "prometheus":
+			extension = "rules.yaml"
+		}
+		resp.SetHeader("Content-Disposition", fmt.Sprintf(`attachment;filename=alert-rules.%s`, extension))
+	}
+	return resp
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRuleGroup(c *models.ReqContext, folderUID string, group string) response.Response {
+	deletion, err := srv.alertRules.DeleteRuleGroup(c.Req.Context(), c.OrgId, folderUID, group, alerting_models.ProvenanceAPI, c.QueryBool("force"), ifMatch(c))
+	if err != nil {
+		var inUse provisioning.RuleGroupInUseError
+		if errors.As(err, &inUse) {
+			return response.JSON(http.StatusConflict, inUse.Deletion)
+		}
+		if errors.Is(err, provisioning.ErrVersionConflict) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, deletion)
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroupPause(c *models.ReqContext, pause definitions.AlertRulePause, folderUID string, group string) response.Response {
+	err := srv.alertRules.SetRuleGroupPaused(c.Req.Context(), c.OrgId, folderUID, group, pause.IsPaused, ifMatch(c))
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, provisioning.ErrVersionConflict) || errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, pause)
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroupOrder(c *models.ReqContext, order definitions.AlertRuleGroupOrder, folderUID string, group string) response.Response {
+	err := srv.alertRules.SetRuleGroupOrder(c.Req.Context(), c.OrgId, folderUID, group, order.RuleUIDs, ifMatch(c))
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, provisioning.ErrVersionConflict) || errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, order)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupCopy(c *models.ReqContext, cp definitions.AlertRuleGroupCopy, folderUID string, group string) response.Response {
+	if cp.OrgID != 0 && cp.OrgID != c.OrgId && !c.SignedInUser.IsGrafanaAdmin {
+		return ErrResp(http.StatusForbidden, errors.New("only Grafana administrators can copy rule groups to another organization"), "")
+	}
+	copied, err := srv.alertRules.CopyRuleGroup(c.Req.Context(), c.OrgId, folderUID, group, cp)
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, provisioning.ErrQuotaReached) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusCreated, copied)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRulesDrift(c *models.ReqContext, document definitions.AlertRulesExport) response.Response {
+	drift, err := srv.alertRules.DetectRuleDrift(c.Req.Context(), c.OrgId, document)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, drift)
+}
+
+func (srv *ProvisioningSrv) RoutePostPrometheusRulesImport(c *models.ReqContext, imp definitions.PrometheusRulesImport) response.Response {
+	dryRun := c.QueryBool("dryRun")
+	folderCreated := false
+	if imp.FolderUID != "" {
+		created, err := srv.ruleFolders.EnsureFolder(c.Req.Context(), c.SignedInUser, c.OrgId, imp.FolderUID, imp.Folder, dryRun)
+		if err != nil {
+			if errors.Is(err, provisioning.ErrValidation) {
+				return ErrResp(http.StatusBadRequest, err, "")
+			}
+			if errors.Is(err, dashboards.ErrFolderAccessDenied) {
+				return ErrResp(http.StatusForbidden, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		folderCreated = created
+	}
+	result, err := srv.alertRules.ImportPrometheusRules(c.Req.Context(), c.OrgId, imp, dryRun)
+	if err != nil {
+		if folderCreated && !dryRun {
+			srv.ruleFolders.DeleteFolder(c.Req.Context(), c.SignedInUser, c.OrgId, imp.FolderUID)
+		}
+		if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, provisioning.ErrQuotaReached) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result.FolderCreated = folderCreated
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetRuleTemplates(c *models.ReqContext) response.Response {
+	templates, err := srv.ruleTemplates.GetRuleTemplates(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, templates)
+}
+
+func (srv *ProvisioningSrv) RouteGetRuleTemplate(c *models.ReqContext, name string) response.Response {
+	tmpl, err := srv.ruleTemplates.GetRuleTemplate(c.Req.Context(), c.OrgId, name)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, tmpl)
+}
+
+func (srv *ProvisioningSrv) RoutePutRuleTemplate(c *models.ReqContext, tmpl definitions.RuleTemplate, name string) response.Response {
+	tmpl.Name = name
+	modified, err := srv.ruleTemplates.SetRuleTemplate(c.Req.Context(), c.OrgId, tmpl)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, modified)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteRuleTemplate(c *models.ReqContext, name string) response.Response {
+	err := srv.ruleTemplates.DeleteRuleTemplate(c.Req.Context(), c.OrgId, name)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RoutePostRuleTemplateInstantiate(c *models.ReqContext, instance definitions.RuleTemplateInstance, name string) response.Response {
+	version, err := provisioningVersion(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	rule, err := srv.ruleTemplates.RenderRuleTemplate(c.Req.Context(), c.OrgId, name, instance)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if violations, err := srv.ruleLint.CheckRules(c.Req.Context(), c.OrgId, []*alerting_models.AlertRule{&rule}); err != nil {
+		return ruleLintErrorResp(err, violations)
+	}
+	created, err := srv.alertRules.CreateAlertRule(c.Req.Context(), rule, alerting_models.ProvenanceAPI)
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return alertRuleValidationErrResp(err)
+	}
+	if errors.Is(err, provisioning.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := definitions.NewAlertRule(created, alerting_models.ProvenanceAPI)
+	srv.notifyChange(c, definitions.ProvisioningResourceAlertRule, created.UID, nil, result)
+	return alertRuleResponse(http.StatusCreated, version, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisioningWebhooks(c *models.ReqContext) response.Response {
+	webhooks, err := srv.webhooks.GetWebhooks(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, webhooks)
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisioningWebhook(c *models.ReqContext, UID string) response.Response {
+	webhook, err := srv.webhooks.GetWebhook(c.Req.Context(), c.OrgId, UID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, webhook)
+}
+
+func (srv *ProvisioningSrv) RoutePostProvisioningWebhook(c *models.ReqContext, webhook definitions.ProvisioningWebhook) response.Response {
+	created, err := srv.webhooks.CreateWebhook(c.Req.Context(), c.OrgId, webhook)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutProvisioningWebhook(c *models.ReqContext, webhook definitions.ProvisioningWebhook, UID string) response.Response {
+	webhook.UID = UID
+	updated, err := srv.webhooks.UpdateWebhook(c.Req.Context(), c.OrgId, webhook)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteProvisioningWebhook(c *models.ReqContext, UID string) response.Response {
+	err := srv.webhooks.DeleteWebhook(c.Req.Context(), c.OrgId, UID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *models.ReqContext, ag definitions.AlertRuleGroupMetadata, folderUID string, group string) response.Response {
+	err := srv.alertRules.UpdateRuleGroup(c.Req.Context(), c.OrgId, folderUID, group, ag.Interval, ag.Labels, ifMatch(c))
+	if err != nil {
+		if errors.Is(err, provisioning.ErrVersionConflict) || errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
 		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
@@ -3,22 +3,32 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	gfcore "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
 	secrets "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
-	prometheus "github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/stretchr/testify/require"
 )
@@ -44,6 +54,256 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 202, response.Status())
 		})
 
+		t.Run("rollback restores a previous version", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutPolicyTree(&rc, definitions.Route{Receiver: "other-receiver"})
+			require.Equal(t, 202, response.Status())
+
+			response = sut.RouteGetPolicyTreeHistory(&rc)
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `[{"version":1,"created":"0001-01-01T00:00:00Z"}]`, string(response.Body()))
+
+			response = sut.RoutePostPolicyTreeRollback(&rc, "1")
+			require.Equal(t, 202, response.Status())
+
+			response = sut.RouteGetPolicyTree(&rc)
+			require.Contains(t, string(response.Body()), `"receiver":"some-receiver"`)
+		})
+
+		t.Run("GET returns the configuration hash as ETag", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			resp := sut.RouteGetPolicyTree(&rc)
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, `"hash-0"`, resp.(*response.NormalResponse).Header().Get("ETag"))
+		})
+
+		t.Run("PUT with the current hash returns 202", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Header.Set("If-Match", `"hash-0"`)
+
+			response := sut.RoutePutPolicyTree(&rc, definitions.Route{})
+
+			require.Equal(t, 202, response.Status())
+		})
+
+		t.Run("PUT with an outdated hash returns 409", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			response := sut.RoutePutPolicyTree(&rc, definitions.Route{Receiver: "other-receiver"})
+			require.Equal(t, 202, response.Status())
+
+			rc.Req.Header.Set("If-Match", `"hash-0"`)
+			response = sut.RoutePutPolicyTree(&rc, definitions.Route{})
+
+			require.Equal(t, 409, response.Status())
+		})
+
+		t.Run("successful DELETE resets the tree", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "mode=provisioned"}
+
+			response := sut.RouteResetPolicyTree(&rc)
+
+			require.Equal(t, 202, response.Status())
+			fake := sut.policies.(*fakeNotificationPolicyService)
+			require.Equal(t, provisioning.PolicyResetProvisioned, fake.resetMode)
+		})
+
+		t.Run("DELETE without mode resets the whole tree", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteResetPolicyTree(&rc)
+
+			require.Equal(t, 202, response.Status())
+			fake := sut.policies.(*fakeNotificationPolicyService)
+			require.Equal(t, provisioning.PolicyResetFull, fake.resetMode)
+		})
+
+		t.Run("successful move returns 202", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyMove(&rc, definitions.PolicyMove{Index: 2}, "abc")
+
+			require.Equal(t, 202, response.Status())
+			fake := sut.policies.(*fakeNotificationPolicyService)
+			require.Equal(t, map[string]int{"abc": 2}, fake.moved)
+		})
+
+		t.Run("move of an unknown policy returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyMove(&rc, definitions.PolicyMove{}, "unknown")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("repoint returns 200 with the number of updated policies", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyRepoint(&rc, definitions.PolicyRepoint{From: "a", To: "b"})
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"updated":1}`, string(response.Body()))
+		})
+
+		t.Run("GET templates returns 200 with the templates", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetPolicyTreeTemplates(&rc)
+
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), `"name":"per-team"`)
+		})
+
+		t.Run("POST template returns 200 with the new tree", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyTreeTemplate(&rc, definitions.PolicyTreeTemplateValues{
+				Values: map[string]string{"default_receiver": "a receiver"},
+			}, "per-team")
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"receiver":"a receiver"}`, string(response.Body()))
+		})
+
+		t.Run("POST unknown template returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyTreeTemplate(&rc, definitions.PolicyTreeTemplateValues{}, "unknown")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("lint returns 200 with the warnings", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyTreeLint(&rc, definitions.Route{Receiver: "a receiver"})
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `[{"receiver":"a receiver","matcher":"team=\"a\"","label":"team","message":"no alert rule has the label 'team'"}]`, string(response.Body()))
+		})
+
+		t.Run("GET effective policies returns the resolved values", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetEffectivePolicies(&rc)
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `[{"receiver":"some-receiver","group_by":[],"group_wait":"30s","group_interval":"5m","repeat_interval":"4h","continue":false}]`, string(response.Body()))
+		})
+
+		t.Run("GET effective policies without a configuration returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.policies = &fakeRejectingNotificationPolicyService{}
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetEffectivePolicies(&rc)
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("GET policies by receiver returns the policies using it", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "receiver=some-receiver"}
+
+			response := sut.RouteGetPoliciesByReceiver(&rc)
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `[{"path":[],"parent_ids":[],"inherited":false,"route":{"receiver":"some-receiver"}}]`, string(response.Body()))
+		})
+
+		t.Run("GET policies by receiver without a receiver returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+
+			response := sut.RouteGetPoliciesByReceiver(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("GET policies by receiver without a configuration returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.policies = &fakeRejectingNotificationPolicyService{}
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "receiver=some-receiver"}
+
+			response := sut.RouteGetPoliciesByReceiver(&rc)
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("GET policy tree graph returns the graph as JSON", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+
+			response := sut.RouteGetPolicyTreeGraph(&rc)
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{
+				"nodes": [{"id": "policy", "kind": "policy", "label": "Default policy", "metadata": {"receiver": "some-receiver"}}],
+				"edges": [{"from": "policy", "to": "contact_point/some-receiver", "kind": "contact_point"}]
+			}`, string(response.Body()))
+		})
+
+		t.Run("GET policy tree graph in DOT format returns a DOT document", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "format=dot"}
+
+			resp := sut.RouteGetPolicyTreeGraph(&rc)
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "text/vnd.graphviz; charset=utf-8", resp.(*response.NormalResponse).Header().Get("Content-Type"))
+			require.Contains(t, string(resp.Body()), `"policy" -> "contact_point/some-receiver" [style=dashed];`)
+		})
+
+		t.Run("GET policy tree graph in an unknown format returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "format=svg"}
+
+			response := sut.RouteGetPolicyTreeGraph(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("GET of an unknown version returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetPolicyTreeVersion(&rc, "5")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("GET of an invalid version returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetPolicyTreeVersion(&rc, "latest")
+
+			require.Equal(t, 400, response.Status())
+		})
+
 		t.Run("when new policy tree is invalid", func(t *testing.T) {
 			t.Run("PUT returns 400", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
@@ -57,6 +317,70 @@ func TestProvisioningApi(t *testing.T) {
 				expBody := `{"error":"invalid object specification: invalid policy tree","message":"invalid object specification: invalid policy tree"}`
 				require.Equal(t, expBody, string(response.Body()))
 			})
+
+			t.Run("PUT lists the undefined time intervals", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeRejectingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+				tree := definitions.Route{MuteTimeIntervals: []string{"weekends"}}
+
+				response := sut.RoutePutPolicyTree(&rc, tree)
+
+				require.Equal(t, 400, response.Status())
+				expBody := `{"message":"undefined time intervals used in routes: \"weekends\"","missingTimeIntervals":["weekends"]}`
+				require.JSONEq(t, expBody, string(response.Body()))
+			})
+
+			t.Run("DELETE with an unknown mode returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeRejectingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+				rc.Req.URL = &url.URL{RawQuery: "mode=everything"}
+
+				response := sut.RouteResetPolicyTree(&rc)
+
+				require.Equal(t, 400, response.Status())
+			})
+
+			t.Run("move returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeRejectingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostPolicyMove(&rc, definitions.PolicyMove{Index: 5}, "abc")
+
+				require.Equal(t, 400, response.Status())
+			})
+
+			t.Run("repoint returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeRejectingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostPolicyRepoint(&rc, definitions.PolicyRepoint{From: "a", To: "unknown"})
+
+				require.Equal(t, 400, response.Status())
+			})
+
+			t.Run("POST template returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeRejectingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostPolicyTreeTemplate(&rc, definitions.PolicyTreeTemplateValues{}, "per-team")
+
+				require.Equal(t, 400, response.Status())
+			})
+
+			t.Run("rollback returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeRejectingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostPolicyTreeRollback(&rc, "1")
+
+				require.Equal(t, 400, response.Status())
+			})
 		})
 
 		t.Run("when org has no AM config", func(t *testing.T) {
@@ -106,10 +430,30 @@ func TestProvisioningApi(t *testing.T) {
 				require.NotEmpty(t, response.Body())
 				require.Contains(t, string(response.Body()), "something went wrong")
 			})
+
+			t.Run("lint returns 500", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeFailingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostPolicyTreeLint(&rc, definitions.Route{})
+
+				require.Equal(t, 500, response.Status())
+			})
 		})
 	})
 
 	t.Run("contact points", func(t *testing.T) {
+		t.Run("duplicates GET returns 200", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetContactPointDuplicates(&rc)
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, "[]", string(response.Body()))
+		})
+
 		t.Run("are invalid", func(t *testing.T) {
 			t.Run("POST returns 400", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
@@ -161,106 +505,1423 @@ func TestProvisioningApi(t *testing.T) {
 				require.Contains(t, string(response.Body()), "template must have content")
 			})
 		})
-	})
-
-	t.Run("mute timings", func(t *testing.T) {
-		t.Run("are invalid", func(t *testing.T) {
-			t.Run("POST returns 400", func(t *testing.T) {
-				sut := createProvisioningSrvSut(t)
-				rc := createTestRequestCtx()
-				mti := createInvalidMuteTiming()
 
-				response := sut.RoutePostMuteTiming(&rc, mti)
-
-				require.Equal(t, 400, response.Status())
-				require.NotEmpty(t, response.Body())
-				require.Contains(t, string(response.Body()), "invalid")
-			})
-
-			t.Run("PUT returns 400", func(t *testing.T) {
-				sut := createProvisioningSrvSut(t)
-				rc := createTestRequestCtx()
-				mti := createInvalidMuteTiming()
+		t.Run("are provisioned from files, PUT and DELETE return 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			configs := &provisioning.MockAMConfigStore{}
+			configs.EXPECT().GetsConfig(models.AlertConfiguration{AlertmanagerConfiguration: testConfig})
+			prov := &provisioning.MockProvisioningStore{}
+			prov.EXPECT().GetReturns(models.ProvenanceFile)
+			sut.templates = provisioning.NewTemplateService(configs, prov, &provisioning.NopTransactionManager{}, nil, log.NewNopLogger())
+			rc := createTestRequestCtx()
 
-				response := sut.RoutePutMuteTiming(&rc, mti, "interval")
+			response := sut.RoutePutTemplate(&rc, definitions.MessageTemplateContent{Template: "changed"}, "a")
+			require.Equal(t, 400, response.Status())
+			require.Contains(t, string(response.Body()), "cannot changed provenance from 'file' to 'api'")
 
-				require.Equal(t, 400, response.Status())
-				require.NotEmpty(t, response.Body())
-				require.Contains(t, string(response.Body()), "invalid")
-			})
+			response = sut.RouteDeleteTemplate(&rc, "a")
+			require.Equal(t, 400, response.Status())
+			require.Contains(t, string(response.Body()), "cannot delete template 'a' with provenance 'file' as 'api'")
 		})
 
-		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+		t.Run("are missing, GET returns 404", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
-			mti := definitions.MuteTimeInterval{}
 
-			response := sut.RoutePutMuteTiming(&rc, mti, "does not exist")
+			response := sut.RouteGetTemplate(&rc, "does not exist")
 
 			require.Equal(t, 404, response.Status())
 		})
-	})
 
-	t.Run("alert rules", func(t *testing.T) {
+		t.Run("defaults are returned", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetDefaultTemplates(&rc)
+
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), `"name":"default.message"`)
+		})
+
+		t.Run("are listed by name and page", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			list := func(query string) response.Response {
+				rc := createTestRequestCtx()
+				rc.Req.URL = &url.URL{RawQuery: query}
+				return sut.RouteGetTemplates(&rc)
+			}
+
+			resp := list("name=A")
+			require.Equal(t, 200, resp.Status())
+			require.JSONEq(t, `[{"name": "a", "template": "template"}]`, string(resp.Body()))
+			require.JSONEq(t, `[]`, string(list("name=b").Body()))
+			require.JSONEq(t, `[]`, string(list("limit=1&page=2").Body()))
+		})
+
+		t.Run("are missing, GET usage returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetTemplateUsage(&rc, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are missing, rename returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostTemplateRename(&rc, definitions.MessageTemplateRename{Name: "new name"}, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("export returns a YAML provisioning file by default", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+
+			resp := sut.RouteGetTemplatesExport(&rc)
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "application/yaml", resp.(*response.NormalResponse).Header().Get("Content-Type"))
+			require.Equal(t, "apiVersion: 1\ntemplates:\n    - orgId: 1\n      name: a\n      template: template\n", string(resp.Body()))
+		})
+
+		t.Run("export in JSON format is downloadable", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "format=json&download=true"}
+
+			resp := sut.RouteGetTemplatesExport(&rc)
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "attachment;filename=templates.json", resp.(*response.NormalResponse).Header().Get("Content-Disposition"))
+			require.JSONEq(t, `{
+				"apiVersion": 1,
+				"templates": [{"orgId": 1, "name": "a", "template": "template"}]
+			}`, string(resp.Body()))
+		})
+
+		t.Run("import dry run reports the changes", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "dryRun=true"}
+			doc := definitions.TemplatesExport{APIVersion: 1, Templates: []definitions.TemplateExportEntry{
+				{OrgID: 1, Name: "b", Template: `{{ define "b" }}b{{ end }}`},
+			}}
+
+			resp := sut.RoutePostTemplatesImport(&rc, doc)
+
+			require.Equal(t, 200, resp.Status())
+			require.JSONEq(t, `{"dryRun": true, "templates": [{"name": "b", "action": "created"}]}`, string(resp.Body()))
+		})
+
+		t.Run("import returns 409 for templates created in the UI", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+			doc := definitions.TemplatesExport{APIVersion: 1, Templates: []definitions.TemplateExportEntry{
+				{Name: "a", Template: `{{ define "a" }}changed{{ end }}`},
+			}}
+
+			resp := sut.RoutePostTemplatesImport(&rc, doc)
+
+			require.Equal(t, 409, resp.Status())
+			require.JSONEq(t, `{"dryRun": false, "templates": [{"name": "a", "action": "conflict"}]}`, string(resp.Body()))
+		})
+
+		t.Run("import of templates of another org returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+			doc := definitions.TemplatesExport{APIVersion: 1, Templates: []definitions.TemplateExportEntry{
+				{OrgID: 2, Name: "b", Template: `{{ define "b" }}b{{ end }}`},
+			}}
+
+			resp := sut.RoutePostTemplatesImport(&rc, doc)
+
+			require.Equal(t, 400, resp.Status())
+		})
+	})
+
+	t.Run("alertmanager import", func(t *testing.T) {
+		t.Run("dry run reports the contact points", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "dryRun=true"}
+			body := definitions.AlertmanagerImport{Config: `
+route:
+  receiver: team
+receivers:
+  - name: team
+    webhook_configs:
+      - url: https://example.com/hook
+inhibit_rules:
+  - equal: [alertname]
+`}
+
+			resp := sut.RoutePostAlertmanagerImport(&rc, body)
+
+			require.Equal(t, 200, resp.Status())
+			require.JSONEq(t, `{
+				"dryRun": true,
+				"contactPoints": [{"name": "team", "types": ["webhook"]}],
+				"muteTimings": [],
+				"policiesImported": true,
+				"unsupported": ["inhibit_rules: inhibition rules are not supported"]
+			}`, string(resp.Body()))
+		})
+
+		t.Run("returns 409 for existing contact points", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+			body := definitions.AlertmanagerImport{Config: `
+route:
+  receiver: grafana-default-email
+receivers:
+  - name: grafana-default-email
+    webhook_configs:
+      - url: https://example.com/hook
+`}
+
+			resp := sut.RoutePostAlertmanagerImport(&rc, body)
+
+			require.Equal(t, 409, resp.Status())
+			require.Contains(t, string(resp.Body()), "contact point 'grafana-default-email' already exists")
+		})
+
+		t.Run("returns 400 for an invalid configuration", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+
+			resp := sut.RoutePostAlertmanagerImport(&rc, definitions.AlertmanagerImport{Config: "route: {}"})
+
+			require.Equal(t, 400, resp.Status())
+		})
+	})
+
+	t.Run("alertmanager config validation", func(t *testing.T) {
+		t.Run("returns all the errors", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.alertmanagerConfig = &fakeAlertmanagerConfigValidator{errs: []error{errors.New("invalid template"), errors.New("invalid receiver")}}
+			rc := createTestRequestCtx()
+
+			resp := sut.RoutePostAlertmanagerConfigValidate(&rc, definitions.PostableUserConfig{})
+
+			require.Equal(t, 200, resp.Status())
+			require.JSONEq(t, `{"valid": false, "errors": ["invalid template", "invalid receiver"]}`, string(resp.Body()))
+		})
+
+		t.Run("reports a valid configuration", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			resp := sut.RoutePostAlertmanagerConfigValidate(&rc, definitions.PostableUserConfig{})
+
+			require.Equal(t, 200, resp.Status())
+			require.JSONEq(t, `{"valid": true, "errors": []}`, string(resp.Body()))
+		})
+
+		t.Run("returns 404 for an org without Alertmanager", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.alertmanagerConfig = &fakeAlertmanagerConfigValidator{err: notifier.ErrNoAlertmanagerForOrg}
+			rc := createTestRequestCtx()
+
+			resp := sut.RoutePostAlertmanagerConfigValidate(&rc, definitions.PostableUserConfig{})
+
+			require.Equal(t, 404, resp.Status())
+		})
+	})
+
+	t.Run("external alertmanager sync status", func(t *testing.T) {
+		t.Run("returns the status of the org", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.externalAMSync = &fakeExternalAlertmanagerSyncService{status: definitions.ExternalAlertmanagerSyncStatus{
+				Enabled:       true,
+				Alertmanagers: []definitions.ExternalAlertmanagerSync{{URL: "http://mimir/alertmanager", Error: "unexpected status 400"}},
+				Unsupported:   []string{},
+			}}
+			rc := createTestRequestCtx()
+
+			resp := sut.RouteGetExternalAlertmanagerSyncStatus(&rc)
+
+			require.Equal(t, 200, resp.Status())
+			require.Contains(t, string(resp.Body()), `"error":"unexpected status 400"`)
+		})
+	})
+
+	t.Run("provisioning batch", func(t *testing.T) {
+		t.Run("returns 202 with the result", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.batch = &fakeProvisioningBatchService{result: definitions.ProvisioningBatchResult{ContactPoints: []definitions.EmbeddedContactPoint{{UID: "cp"}}}}
+			rc := createTestRequestCtx()
+
+			resp := sut.RoutePostProvisioningBatch(&rc, definitions.ProvisioningBatch{})
+
+			require.Equal(t, 202, resp.Status())
+			require.Contains(t, string(resp.Body()), `"uid":"cp"`)
+		})
+
+		t.Run("returns 400 for an invalid operation", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.batch = &fakeProvisioningBatchService{err: fmt.Errorf("operation 0: %w", provisioning.ErrValidation)}
+			rc := createTestRequestCtx()
+
+			resp := sut.RoutePostProvisioningBatch(&rc, definitions.ProvisioningBatch{})
+
+			require.Equal(t, 400, resp.Status())
+		})
+
+		t.Run("returns 409 if the configuration changed", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.batch = &fakeProvisioningBatchService{err: store.ErrVersionLockedObjectNotFound}
+			rc := createTestRequestCtx()
+
+			resp := sut.RoutePostProvisioningBatch(&rc, definitions.ProvisioningBatch{})
+
+			require.Equal(t, 409, resp.Status())
+		})
+	})
+
+	t.Run("mute timings", func(t *testing.T) {
 		t.Run("are invalid", func(t *testing.T) {
 			t.Run("POST returns 400", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
 				rc := createTestRequestCtx()
+				mti := createInvalidMuteTiming()
+
+				response := sut.RoutePostMuteTiming(&rc, mti)
+
+				require.Equal(t, 400, response.Status())
+				require.NotEmpty(t, response.Body())
+				require.Contains(t, string(response.Body()), "invalid")
+			})
+
+			t.Run("PUT returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				mti := createInvalidMuteTiming()
+
+				response := sut.RoutePutMuteTiming(&rc, mti, "interval")
+
+				require.Equal(t, 400, response.Status())
+				require.NotEmpty(t, response.Body())
+				require.Contains(t, string(response.Body()), "invalid")
+			})
+		})
+
+		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			mti := definitions.MuteTimeInterval{}
+
+			response := sut.RoutePutMuteTiming(&rc, mti, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are missing, GET returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetMuteTiming(&rc, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are listed by name and page", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			list := func(query string) response.Response {
+				rc := createTestRequestCtx()
+				rc.Req.URL = &url.URL{RawQuery: query}
+				return sut.RouteGetMuteTimings(&rc)
+			}
+
+			resp := list("name=TERV&limit=1")
+			require.Equal(t, 200, resp.Status())
+			require.JSONEq(t, `[{"name": "interval", "time_intervals": []}]`, string(resp.Body()))
+			require.JSONEq(t, `[]`, string(list("name=other").Body()))
+		})
+
+		t.Run("are missing, rename returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostMuteTimingRename(&rc, definitions.MuteTimingRename{Name: "new name"}, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("export returns a YAML provisioning file by default", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+
+			resp := sut.RouteGetMuteTimingsExport(&rc)
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "application/yaml", resp.(*response.NormalResponse).Header().Get("Content-Type"))
+			require.Empty(t, resp.(*response.NormalResponse).Header().Get("Content-Disposition"))
+			require.Equal(t, "apiVersion: 1\nmuteTimes:\n    - orgId: 1\n      name: interval\n      time_intervals: []\n", string(resp.Body()))
+		})
+
+		t.Run("export in JSON format is downloadable", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "format=json&download=true"}
+
+			resp := sut.RouteGetMuteTimingsExport(&rc)
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "attachment;filename=mute-timings.json", resp.(*response.NormalResponse).Header().Get("Content-Disposition"))
+			require.JSONEq(t, `{
+				"apiVersion": 1,
+				"muteTimes": [{"orgId": 1, "name": "interval", "time_intervals": []}]
+			}`, string(resp.Body()))
+		})
+
+		t.Run("export in an unknown format returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "format=toml"}
+
+			response := sut.RouteGetMuteTimingsExport(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
+	})
+
+	t.Run("alert rules", func(t *testing.T) {
+		t.Run("are invalid", func(t *testing.T) {
+			t.Run("POST returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				rule := createInvalidAlertRule()
+
+				response := sut.RoutePostAlertRule(&rc, rule)
+
+				require.Equal(t, 400, response.Status())
+				require.NotEmpty(t, response.Body())
+				require.Contains(t, string(response.Body()), "invalid alert rule")
+			})
+
+			t.Run("PUT returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
 				rule := createInvalidAlertRule()
 
-				response := sut.RoutePostAlertRule(&rc, rule)
+				response := sut.RoutePutAlertRule(&rc, rule, "rule")
+
+				require.Equal(t, 400, response.Status())
+				require.NotEmpty(t, response.Body())
+				require.Contains(t, string(response.Body()), "invalid alert rule")
+			})
+		})
+
+		t.Run("reference unknown datasources, POST returns 400 listing them", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].DatasourceUID = "unknown"
+
+			response := sut.RoutePostAlertRule(&rc, rule)
+
+			require.Equal(t, 400, response.Status())
+			body := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(response.Body(), &body))
+			require.Equal(t, []interface{}{"unknown"}, body["missingDatasources"])
+		})
+
+		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+
+			response := sut.RoutePutAlertRule(&rc, rule, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are missing, pause PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutAlertRulePause(&rc, definitions.AlertRulePause{IsPaused: true}, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are paused by PUT", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "paused-rule"
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePutAlertRulePause(&rc, definitions.AlertRulePause{IsPaused: true}, "paused-rule")
+
+			require.Equal(t, 200, response.Status())
+			stored, _, err := sut.alertRules.GetAlertRule(context.Background(), 1, "paused-rule")
+			require.NoError(t, err)
+			require.True(t, stored.IsPaused)
+		})
+
+		t.Run("have their provenance changed by PUT", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "file-rule"
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePutAlertRuleProvenance(&rc, definitions.AlertRuleProvenance{Provenance: models.ProvenanceFile}, "file-rule")
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"provenance":"file"}`, string(response.Body()))
+
+			response = sut.RoutePutAlertRuleProvenance(&rc, definitions.AlertRuleProvenance{Provenance: "terraform"}, "file-rule")
+			require.Equal(t, 400, response.Status())
+			response = sut.RoutePutAlertRuleProvenance(&rc, definitions.AlertRuleProvenance{Provenance: models.ProvenanceAPI}, "does not exist")
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("have their provenance changed in bulk by POST", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			for _, uid := range []string{"rule-a", "rule-b"} {
+				rule := createTestAlertRule(uid, 1)
+				rule.UID = uid
+				insertRule(t, sut, rule)
+			}
+
+			resp := sut.RoutePostAlertRulesProvenance(&rc, definitions.AlertRulesProvenance{RuleUIDs: []string{"rule-a", "rule-b"}, Provenance: models.ProvenanceAPI})
+
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+			require.JSONEq(t, `{"provenance":"api","changed":["rule-a","rule-b"],"unchanged":[]}`, string(resp.Body()))
+
+			resp = sut.RoutePostAlertRulesProvenance(&rc, definitions.AlertRulesProvenance{RuleUIDs: []string{"rule-a", "does not exist"}, Provenance: models.ProvenanceAPI})
+			require.Equal(t, 400, resp.Status())
+			resp = sut.RoutePostAlertRulesProvenance(&rc, definitions.AlertRulesProvenance{RuleUIDs: []string{"rule-a"}, Provenance: "terraform"})
+			require.Equal(t, 400, resp.Status())
+		})
+
+		t.Run("are served in v1 if no version is pinned", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "rule"
+			rule.IsPaused = true
+			insertRule(t, sut, rule)
+
+			resp := sut.RouteRouteGetAlertRule(&rc, "rule")
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "v1", resp.(*response.NormalResponse).Header().Get(definitions.ProvisioningVersionHeader))
+			require.Empty(t, resp.(*response.NormalResponse).Header().Get("Deprecation"))
+			require.Contains(t, string(resp.Body()), `"isPaused":true`)
+		})
+
+		t.Run("are served in v0alpha1 with deprecation headers if it is pinned", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Header.Set(definitions.ProvisioningVersionHeader, "v0alpha1")
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "rule"
+			rule.IsPaused = true
+			insertRule(t, sut, rule)
+
+			resp := sut.RouteRouteGetAlertRule(&rc, "rule")
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "v0alpha1", resp.(*response.NormalResponse).Header().Get(definitions.ProvisioningVersionHeader))
+			require.Equal(t, "true", resp.(*response.NormalResponse).Header().Get("Deprecation"))
+			require.Contains(t, resp.(*response.NormalResponse).Header().Get("Warning"), "v0alpha1 is deprecated")
+			require.NotContains(t, string(resp.Body()), "isPaused")
+		})
+
+		t.Run("are updated in v0alpha1 without resetting the fields of v1", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Header.Set(definitions.ProvisioningVersionHeader, "v0alpha1")
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "rule"
+			rule.IsPaused = true
+			rule.Webhooks = []models.AlertRuleWebhook{{URL: "https://example.com/hook"}}
+			insertRule(t, sut, rule)
+
+			update := createTestAlertRule("updated rule", 1)
+			update.UID = "rule"
+			resp := sut.RoutePutAlertRule(&rc, update, "rule")
+			require.Equal(t, 200, resp.Status())
+
+			stored, _, err := sut.alertRules.GetAlertRule(context.Background(), 1, "rule")
+			require.NoError(t, err)
+			require.Equal(t, "updated rule", stored.Title)
+			require.True(t, stored.IsPaused)
+			require.Equal(t, rule.Webhooks, stored.Webhooks)
+		})
+
+		t.Run("pin an unknown version, GET returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Header.Set(definitions.ProvisioningVersionHeader, "v2")
+
+			resp := sut.RouteRouteGetAlertRule(&rc, "rule")
+
+			require.Equal(t, 400, resp.Status())
+		})
+
+		t.Run("are patched, PATCH returns 200 with the updated rule", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "rule"
+			rule.Labels = map[string]string{"team": "checkout"}
+			rule.Data[0].RelativeTimeRange.From = models.Duration(time.Minute)
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePatchAlertRule(&rc, definitions.AlertRulePatch{
+				Labels: map[string]string{"severity": "critical"},
+			}, "rule")
+
+			require.Equal(t, 200, response.Status(), string(response.Body()))
+			var patched definitions.AlertRule
+			require.NoError(t, json.Unmarshal(response.Body(), &patched))
+			require.Equal(t, "rule", patched.UID)
+			require.Equal(t, rule.Title, patched.Title)
+			require.Equal(t, map[string]string{"team": "checkout", "severity": "critical"}, patched.Labels)
+		})
+
+		t.Run("are patched with invalid changes, PATCH returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "rule"
+			rule.Data[0].RelativeTimeRange.From = models.Duration(time.Minute)
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePatchAlertRule(&rc, definitions.AlertRulePatch{Condition: "unknown"}, "rule")
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are missing, PATCH returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePatchAlertRule(&rc, definitions.AlertRulePatch{Title: "rule"}, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are cloned, POST returns 201 with the clone", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "rule"
+			rule.Data[0].RelativeTimeRange.From = models.Duration(time.Minute)
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePostAlertRuleClone(&rc, definitions.AlertRuleClone{
+				Title:    "rule copy",
+				Labels:   map[string]string{"service": "cart"},
+				IsPaused: true,
+			}, "rule")
+
+			require.Equal(t, 201, response.Status())
+			var clone definitions.AlertRule
+			require.NoError(t, json.Unmarshal(response.Body(), &clone))
+			require.NotEqual(t, "rule", clone.UID)
+			require.Equal(t, "rule copy", clone.Title)
+			require.Equal(t, "cart", clone.Labels["service"])
+			require.True(t, clone.IsPaused)
+		})
+
+		t.Run("are cloned with the same title, POST returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "rule"
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePostAlertRuleClone(&rc, definitions.AlertRuleClone{}, "rule")
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are missing, clone POST returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRuleClone(&rc, definitions.AlertRuleClone{Title: "copy"}, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("violate enforced lint policies", func(t *testing.T) {
+			enforceRunbooks := func(t *testing.T, sut ProvisioningSrv) {
+				t.Helper()
+				cfg := lint.DefaultConfig()
+				cfg.Enforce = true
+				cfg.RequireRunbook.Severity = definitions.RuleLintSeverityError
+				require.NoError(t, sut.ruleLint.SaveConfig(context.Background(), 1, cfg))
+			}
+
+			t.Run("POST returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				enforceRunbooks(t, sut)
+
+				response := sut.RoutePostAlertRule(&rc, createTestAlertRule("rule", 1))
+
+				require.Equal(t, 400, response.Status())
+				require.Contains(t, string(response.Body()), definitions.RuleLintPolicyRequireRunbook)
+			})
+
+			t.Run("PUT returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+				enforceRunbooks(t, sut)
+
+				response := sut.RoutePutAlertRule(&rc, createTestAlertRule("rule", 1), "rule")
+
+				require.Equal(t, 400, response.Status())
+				require.Contains(t, string(response.Body()), definitions.RuleLintPolicyRequireRunbook)
+			})
+
+			t.Run("POST succeeds with runbook", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				enforceRunbooks(t, sut)
+				rule := createTestAlertRule("rule", 1)
+				rule.Annotations = map[string]string{lint.RunbookURLAnnotation: "https://runbooks.example.com/rule"}
+
+				response := sut.RoutePostAlertRule(&rc, rule)
+
+				require.Equal(t, 201, response.Status())
+			})
+		})
+	})
+
+	t.Run("alert rule lists", func(t *testing.T) {
+		listRules := func(t *testing.T, sut ProvisioningSrv, query string) []definitions.AlertRule {
+			t.Helper()
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: query}
+			resp := sut.RouteGetAlertRules(&rc)
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+			rules := []definitions.AlertRule{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &rules))
+			return rules
+		}
+		createRules := func(t *testing.T, sut ProvisioningSrv) {
+			for title, lbls := range map[string]map[string]string{
+				"payments critical": {"team": "payments", "severity": "critical"},
+				"payments info":     {"team": "payments", "severity": "info"},
+				"billing critical":  {"team": "billing", "severity": "critical"},
+			} {
+				rule := createTestAlertRule(title, 1)
+				rule.Labels = lbls
+				insertRule(t, sut, rule)
+			}
+		}
+
+		t.Run("GET returns the rules matching all the label selectors", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			createRules(t, sut)
+
+			require.Len(t, listRules(t, sut, ""), 3)
+
+			rules := listRules(t, sut, url.Values{"label": {"team=payments", `severity!="info"`}}.Encode())
+			require.Len(t, rules, 1)
+			require.Equal(t, "payments critical", rules[0].Title)
+
+			rules = listRules(t, sut, url.Values{"label": {`team=~"payments|billing"`, "severity=critical"}}.Encode())
+			require.Len(t, rules, 2)
+
+			require.Len(t, listRules(t, sut, url.Values{"label": {"env="}}.Encode()), 3)
+			require.Empty(t, listRules(t, sut, url.Values{"label": {"team=payments"}, "folderUid": {"other-folder"}}.Encode()))
+			require.Len(t, listRules(t, sut, url.Values{"label": {"team=payments"}, "limit": {"1"}}.Encode()), 1)
+		})
+
+		t.Run("GET returns only the requested fields", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			createRules(t, sut)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: url.Values{"fields": {"title,labels"}, "name": {"payments critical"}}.Encode()}
+
+			resp := sut.RouteGetAlertRules(&rc)
+
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+			require.JSONEq(t, `[{"title": "payments critical", "labels": {"team": "payments", "severity": "critical"}}]`, string(resp.Body()))
+		})
+
+		t.Run("GET returns 400 for invalid label selectors", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+
+			for _, selector := range []string{"team", "1team=payments", `team=~"("`, `team="payments`} {
+				rc := createTestRequestCtx()
+				rc.Req.URL = &url.URL{RawQuery: url.Values{"label": {selector}}.Encode()}
+
+				require.Equal(t, 400, sut.RouteGetAlertRules(&rc).Status(), selector)
+			}
+		})
+	})
+
+	t.Run("alert rule groups", func(t *testing.T) {
+		t.Run("are present, GET returns 200", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status())
+		})
+
+		t.Run("are missing, GET returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are changed only if their ETag matches If-Match", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+			rc := createTestRequestCtx()
+			resp := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, resp.Status())
+			etag := resp.(*response.NormalResponse).Header().Get("ETag")
+			require.NotEmpty(t, etag)
+
+			rc = createTestRequestCtx()
+			rc.Req.Header.Set("If-Match", etag)
+			resp = sut.RoutePutAlertRuleGroupPause(&rc, definitions.AlertRulePause{IsPaused: true}, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+
+			rc = createTestRequestCtx()
+			rc.Req.Header.Set("If-Match", etag)
+			resp = sut.RoutePutAlertRuleGroup(&rc, definitions.AlertRuleGroupMetadata{Interval: 120}, "folder-uid", "my-cool-group")
+			require.Equal(t, 409, resp.Status())
+
+			rc = createTestRequestCtx()
+			resp = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.NotEqual(t, etag, resp.(*response.NormalResponse).Header().Get("ETag"))
+		})
+
+		t.Run("get the labels of PUT merged into their rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+			rc := createTestRequestCtx()
+
+			resp := sut.RoutePutAlertRuleGroup(&rc, definitions.AlertRuleGroupMetadata{Interval: 60, Labels: map[string]string{"team": "sre"}}, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+
+			resp = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, resp.Status())
+			group := definitions.AlertRuleGroup{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &group))
+			require.Equal(t, map[string]string{"team": "sre"}, group.Labels)
+			require.Equal(t, "sre", group.Rules[0].Labels["team"])
+		})
+
+		t.Run("are paused by PUT", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePutAlertRuleGroupPause(&rc, definitions.AlertRulePause{IsPaused: true}, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status())
+			group, err := sut.alertRules.GetRuleGroup(context.Background(), 1, "folder-uid", "my-cool-group")
+			require.NoError(t, err)
+			require.True(t, group.Rules[0].IsPaused)
+		})
+
+		t.Run("are missing, pause PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutAlertRuleGroupPause(&rc, definitions.AlertRulePause{IsPaused: true}, "folder-uid", "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are deleted by DELETE", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			rule.UID = "rule-uid"
+			insertRule(t, sut, rule)
+
+			response := sut.RouteDeleteAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status(), string(response.Body()))
+			require.JSONEq(t, `{"deleted": true, "ruleUids": ["rule-uid"], "alerts": [], "silences": []}`, string(response.Body()))
+			_, err := sut.alertRules.GetRuleGroup(context.Background(), 1, "folder-uid", "my-cool-group")
+			require.Error(t, err)
+		})
+
+		t.Run("are deleted only with force while rules are silenced", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+			sut.alertRules = silencedAlertRuleService{AlertRuleService: sut.alertRules}
+
+			response := sut.RouteDeleteAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 409, response.Status(), string(response.Body()))
+
+			rc = createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "force=true"}
+			response = sut.RouteDeleteAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status(), string(response.Body()))
+		})
+
+		t.Run("are missing, DELETE returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+
+			response := sut.RouteDeleteAlertRuleGroup(&rc, "folder-uid", "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are copied by POST", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePostAlertRuleGroupCopy(&rc, definitions.AlertRuleGroupCopy{FolderUID: "other-folder-uid"}, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 201, response.Status(), string(response.Body()))
+			group, err := sut.alertRules.GetRuleGroup(context.Background(), 1, "other-folder-uid", "my-cool-group")
+			require.NoError(t, err)
+			require.Len(t, group.Rules, 1)
+
+			response = sut.RoutePostAlertRuleGroupCopy(&rc, definitions.AlertRuleGroupCopy{FolderUID: "other-folder-uid"}, "folder-uid", "my-cool-group")
+			require.Equal(t, 400, response.Status())
+			response = sut.RoutePostAlertRuleGroupCopy(&rc, definitions.AlertRuleGroupCopy{FolderUID: "other-folder-uid"}, "folder-uid", "does not exist")
+			require.Equal(t, 404, response.Status())
+			response = sut.RoutePostAlertRuleGroupCopy(&rc, definitions.AlertRuleGroupCopy{FolderUID: "unknown-folder-uid"}, "folder-uid", "my-cool-group")
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are copied to other organizations only by Grafana administrators", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+			cp := definitions.AlertRuleGroupCopy{OrgID: 2, FolderUID: "folder-uid"}
+
+			response := sut.RoutePostAlertRuleGroupCopy(&rc, cp, "folder-uid", "my-cool-group")
+			require.Equal(t, 403, response.Status())
+
+			rc.SignedInUser.IsGrafanaAdmin = true
+			response = sut.RoutePostAlertRuleGroupCopy(&rc, cp, "folder-uid", "my-cool-group")
+			require.Equal(t, 201, response.Status(), string(response.Body()))
+			group, err := sut.alertRules.GetRuleGroup(context.Background(), 2, "folder-uid", "my-cool-group")
+			require.NoError(t, err)
+			require.Len(t, group.Rules, 1)
+			require.Equal(t, int64(2), group.Rules[0].OrgID)
+		})
+
+		t.Run("get the same rule UIDs in every organization when copied with deterministic UIDs", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.SignedInUser.IsGrafanaAdmin = true
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+			copyTo := func(t *testing.T, body string) string {
+				t.Helper()
+				var cp definitions.AlertRuleGroupCopy
+				require.NoError(t, json.Unmarshal([]byte(body), &cp))
+				response := sut.RoutePostAlertRuleGroupCopy(&rc, cp, "folder-uid", "my-cool-group")
+				require.Equal(t, 201, response.Status(), string(response.Body()))
+				var group definitions.AlertRuleGroup
+				require.NoError(t, json.Unmarshal(response.Body(), &group))
+				require.Len(t, group.Rules, 1)
+				return group.Rules[0].UID
+			}
+
+			uid := copyTo(t, `{"orgId": 2, "folderUid": "folder-uid", "deterministicUids": true}`)
+			require.Equal(t, uid, copyTo(t, `{"orgId": 3, "folderUid": "folder-uid", "deterministicUids": true}`))
+			require.NotEqual(t, uid, copyTo(t, `{"orgId": 4, "folderUid": "folder-uid"}`))
+		})
+
+		t.Run("are compared with a file provisioning document by POST", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, rule)
+			document, err := sut.alertRules.ExportRuleGroups(context.Background(), 1, "folder-uid")
+			require.NoError(t, err)
+
+			response := sut.RoutePostAlertRulesDrift(&rc, document)
+
+			require.Equal(t, 200, response.Status(), string(response.Body()))
+			drift := definitions.AlertRulesDrift{}
+			require.NoError(t, json.Unmarshal(response.Body(), &drift))
+			require.True(t, drift.Drifted)
+			require.Len(t, drift.Rules, 1)
+			require.Equal(t, definitions.AlertRuleDriftProvenance, drift.Rules[0].Status)
+
+			document.Groups[0].Rules[0].UID = ""
+			response = sut.RoutePostAlertRulesDrift(&rc, document)
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are reordered by PUT", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			first := createTestAlertRule("first", 1)
+			first.UID = "first"
+			first.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, first)
+			second := createTestAlertRule("second", 1)
+			second.UID = "second"
+			second.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(time.Minute)}
+			insertRule(t, sut, second)
+
+			response := sut.RoutePutAlertRuleGroupOrder(&rc, definitions.AlertRuleGroupOrder{RuleUIDs: []string{"second", "first"}}, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status())
+			group, err := sut.alertRules.GetRuleGroup(context.Background(), 1, "folder-uid", "my-cool-group")
+			require.NoError(t, err)
+			require.Equal(t, "second", group.Rules[0].UID)
+			require.Equal(t, "first", group.Rules[1].UID)
+		})
+
+		t.Run("are not reordered without all UIDs, order PUT returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = "rule"
+			insertRule(t, sut, rule)
+			other := createTestAlertRule("other", 1)
+			other.UID = "other"
+			insertRule(t, sut, other)
+
+			response := sut.RoutePutAlertRuleGroupOrder(&rc, definitions.AlertRuleGroupOrder{RuleUIDs: []string{"rule"}}, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are missing, order PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutAlertRuleGroupOrder(&rc, definitions.AlertRuleGroupOrder{}, "folder-uid", "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("export returns a YAML provisioning file by default", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			resp := sut.RouteGetAlertRuleGroupsExport(&rc, "folder-uid")
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "application/yaml", resp.(*response.NormalResponse).Header().Get("Content-Type"))
+			require.Empty(t, resp.(*response.NormalResponse).Header().Get("Content-Disposition"))
+			require.Contains(t, string(resp.Body()), "apiVersion: 1\ngroups:\n    - orgId: 1\n      name: my-cool-group\n      folderUid: folder-uid\n      interval: 1m\n")
+		})
+
+		t.Run("export in JSON format is downloadable", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "format=json&download=true"}
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			resp := sut.RouteGetAlertRuleGroupsExport(&rc, "folder-uid")
+
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "attachment;filename=alert-rules.json", resp.(*response.NormalResponse).Header().Get("Content-Disposition"))
+			export := definitions.AlertRulesExport{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &export))
+			require.Len(t, export.Groups, 1)
+			require.Equal(t, "rule", export.Groups[0].Rules[0].Title)
+		})
+
+		t.Run("export in HCL format contains Terraform resources routed to contact points", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "format=hcl&download=true"}
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			resp := sut.RouteGetAlertRuleGroupsExport(&rc, "folder-uid")
 
-				require.Equal(t, 400, response.Status())
-				require.NotEmpty(t, response.Body())
-				require.Contains(t, string(response.Body()), "invalid alert rule")
-			})
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "attachment;filename=alert-rules.tf", resp.(*response.NormalResponse).Header().Get("Content-Disposition"))
+			require.Contains(t, string(resp.Body()), "resource \"grafana_rule_group\" \"rule_group_my_cool_group\" {\n")
+			require.Contains(t, string(resp.Body()), "  # contact points: some-receiver\n  rule {\n    name           = \"rule\"\n")
+		})
 
-			t.Run("PUT returns 400", func(t *testing.T) {
-				sut := createProvisioningSrvSut(t)
-				rc := createTestRequestCtx()
-				insertRule(t, sut, createTestAlertRule("rule", 1))
-				rule := createInvalidAlertRule()
+		t.Run("export in Prometheus format contains the rules that can be converted", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "format=prometheus&download=true"}
+			insertRule(t, sut, createTestAlertRule("rule", 1))
 
-				response := sut.RoutePutAlertRule(&rc, rule, "rule")
+			resp := sut.RouteGetAlertRuleGroupsExport(&rc, "folder-uid")
 
-				require.Equal(t, 400, response.Status())
-				require.NotEmpty(t, response.Body())
-				require.Contains(t, string(response.Body()), "invalid alert rule")
-			})
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, "attachment;filename=alert-rules.rules.yaml", resp.(*response.NormalResponse).Header().Get("Content-Disposition"))
+			require.Contains(t, string(resp.Body()), "# The following rules cannot be converted and are left out:\n# - group 'my-cool-group', rule 'rule': ")
+			require.Contains(t, string(resp.Body()), "groups: []\n")
 		})
 
-		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+		t.Run("export in an unknown format returns 400", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
-			rule := createTestAlertRule("rule", 1)
+			rc.Req.URL = &url.URL{RawQuery: "format=xml"}
 
-			response := sut.RoutePutAlertRule(&rc, rule, "does not exist")
+			resp := sut.RouteGetAlertRuleGroupsExport(&rc, "folder-uid")
 
-			require.Equal(t, 404, response.Status())
+			require.Equal(t, 400, resp.Status())
 		})
 	})
 
-	t.Run("alert rule groups", func(t *testing.T) {
-		t.Run("are present, GET returns 200", func(t *testing.T) {
+	t.Run("prometheus rules import", func(t *testing.T) {
+		imp := definitions.PrometheusRulesImport{
+			FolderUID:     "folder-uid",
+			DatasourceUID: "prometheus",
+			Config: `
+groups:
+  - name: nodes
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+        for: 5m
+      - record: instance:up:count
+        expr: count by (instance) (up)
+`,
+		}
+
+		t.Run("creates the rule groups", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
-			insertRule(t, sut, createTestAlertRule("rule", 1))
+			rc.Req.URL = &url.URL{}
 
-			response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			resp := sut.RoutePostPrometheusRulesImport(&rc, imp)
+
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+			result := definitions.PrometheusRulesImportResult{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.False(t, result.DryRun)
+			require.Len(t, result.Groups, 1)
+			require.Equal(t, []definitions.PrometheusRulesImportSkippedRule{{Group: "nodes", Name: "instance:up:count", Reason: "recording rules are not supported"}}, result.Skipped)
+
+			resp = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "nodes")
+			require.Equal(t, 200, resp.Status())
+		})
+
+		t.Run("updates the rules when the file is imported again", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+
+			resp := sut.RoutePostPrometheusRulesImport(&rc, imp)
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+			first := definitions.PrometheusRulesImportResult{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &first))
+			resp = sut.RoutePostPrometheusRulesImport(&rc, imp)
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+
+			resp = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "nodes")
+			require.Equal(t, 200, resp.Status())
+			group := definitions.AlertRuleGroup{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &group))
+			require.Len(t, group.Rules, 1)
+			require.Equal(t, first.Groups[0].Rules[0].UID, group.Rules[0].UID)
+		})
+
+		t.Run("returns 400 for an invalid rule file", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+			invalid := imp
+			invalid.Config = "groups: ["
+
+			resp := sut.RoutePostPrometheusRulesImport(&rc, invalid)
+
+			require.Equal(t, 400, resp.Status())
+		})
+
+		t.Run("creates the missing folder if it is described", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			folders := &fakeRuleFolderService{missing: map[string]bool{"folder-uid": true}}
+			sut.ruleFolders = folders
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+
+			resp := sut.RoutePostPrometheusRulesImport(&rc, imp)
+			require.Equal(t, 400, resp.Status())
+			require.Empty(t, folders.created)
+
+			withFolder := imp
+			withFolder.Folder = &definitions.RuleImportFolder{Title: "Nodes"}
+			resp = sut.RoutePostPrometheusRulesImport(&rc, withFolder)
+
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+			result := definitions.PrometheusRulesImportResult{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.True(t, result.FolderCreated)
+			require.Equal(t, []string{"folder-uid"}, folders.created)
+			require.Empty(t, folders.deleted)
+		})
+
+		t.Run("deletes the created folder if the rules are not imported", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			folders := &fakeRuleFolderService{missing: map[string]bool{"folder-uid": true}}
+			sut.ruleFolders = folders
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{}
+			invalid := imp
+			invalid.Config = "groups: ["
+			invalid.Folder = &definitions.RuleImportFolder{Title: "Nodes"}
+
+			resp := sut.RoutePostPrometheusRulesImport(&rc, invalid)
+
+			require.Equal(t, 400, resp.Status())
+			require.Equal(t, []string{"folder-uid"}, folders.created)
+			require.Equal(t, []string{"folder-uid"}, folders.deleted)
+		})
+
+		t.Run("does not create the folder in a dry run", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			folders := &fakeRuleFolderService{missing: map[string]bool{"folder-uid": true}}
+			sut.ruleFolders = folders
+			rc := createTestRequestCtx()
+			rc.Req.URL = &url.URL{RawQuery: "dryRun=true"}
+			withFolder := imp
+			withFolder.Folder = &definitions.RuleImportFolder{Title: "Nodes"}
+
+			resp := sut.RoutePostPrometheusRulesImport(&rc, withFolder)
+
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+			result := definitions.PrometheusRulesImportResult{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.True(t, result.DryRun)
+			require.True(t, result.FolderCreated)
+			require.Empty(t, folders.created)
+		})
+	})
+
+	t.Run("rule templates", func(t *testing.T) {
+		tmpl := definitions.RuleTemplate{
+			Variables: []definitions.RuleTemplateVariable{
+				{Name: "threshold", Type: definitions.RuleTemplateVariableNumber},
+				{Name: "team", Type: definitions.RuleTemplateVariableLabel, Default: "platform"},
+			},
+			Rule: definitions.AlertRuleExport{
+				Title:     "High CPU of ${team}",
+				Condition: "A",
+				Data: []definitions.AlertQueryExport{
+					{
+						RefID:             "A",
+						RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(time.Minute)},
+						DatasourceUID:     "prometheus",
+						Model:             map[string]interface{}{"expr": "cpu > ${threshold}"},
+					},
+				},
+				NoDataState:  models.NoData,
+				ExecErrState: models.ErrorErrState,
+				Labels:       map[string]string{"team": "${team}"},
+			},
+		}
+
+		t.Run("are saved by PUT and listed by GET", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutRuleTemplate(&rc, tmpl, "high-cpu")
+			require.Equal(t, 202, response.Status(), string(response.Body()))
 
+			response = sut.RouteGetRuleTemplates(&rc)
 			require.Equal(t, 200, response.Status())
+			templates := definitions.RuleTemplates{}
+			require.NoError(t, json.Unmarshal(response.Body(), &templates))
+			require.Len(t, templates, 1)
+			require.Equal(t, "high-cpu", templates[0].Name)
 		})
 
-		t.Run("are missing, GET returns 404", func(t *testing.T) {
+		t.Run("are rejected by PUT if invalid", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
-			insertRule(t, sut, createTestAlertRule("rule", 1))
+			invalid := tmpl
+			invalid.Variables = []definitions.RuleTemplateVariable{{Name: "threshold", Type: "duration"}}
 
-			response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "does not exist")
+			response := sut.RoutePutRuleTemplate(&rc, invalid, "high-cpu")
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are instantiated into rules by POST", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			require.Equal(t, 202, sut.RoutePutRuleTemplate(&rc, tmpl, "high-cpu").Status())
+
+			response := sut.RoutePostRuleTemplateInstantiate(&rc, definitions.RuleTemplateInstance{
+				FolderUID: "folder-uid",
+				RuleGroup: "my-cool-group",
+				Values:    map[string]interface{}{"threshold": float64(90)},
+			}, "high-cpu")
+
+			require.Equal(t, 201, response.Status(), string(response.Body()))
+			created := definitions.AlertRule{}
+			require.NoError(t, json.Unmarshal(response.Body(), &created))
+			require.Equal(t, "High CPU of platform", created.Title)
+			require.Equal(t, models.ProvenanceAPI, created.Provenance)
+			rule, _, err := sut.alertRules.GetAlertRule(context.Background(), 1, created.UID)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"expr": "cpu > 90", "intervalMs": 1000, "maxDataPoints": 43200}`, string(rule.Data[0].Model))
+		})
+
+		t.Run("are not instantiated without the values of their variables", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			require.Equal(t, 202, sut.RoutePutRuleTemplate(&rc, tmpl, "high-cpu").Status())
+
+			response := sut.RoutePostRuleTemplateInstantiate(&rc, definitions.RuleTemplateInstance{FolderUID: "folder-uid", RuleGroup: "my-cool-group"}, "high-cpu")
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are deleted by DELETE", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			require.Equal(t, 202, sut.RoutePutRuleTemplate(&rc, tmpl, "high-cpu").Status())
 
+			require.Equal(t, 204, sut.RouteDeleteRuleTemplate(&rc, "high-cpu").Status())
+			require.Equal(t, 404, sut.RouteGetRuleTemplate(&rc, "high-cpu").Status())
+			require.Equal(t, 404, sut.RouteDeleteRuleTemplate(&rc, "high-cpu").Status())
+			response := sut.RoutePostRuleTemplateInstantiate(&rc, definitions.RuleTemplateInstance{FolderUID: "folder-uid", RuleGroup: "my-cool-group"}, "high-cpu")
 			require.Equal(t, 404, response.Status())
 		})
 	})
+
+	t.Run("provisioning webhooks", func(t *testing.T) {
+		t.Run("are registered by POST, updated by PUT and deleted by DELETE", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			resp := sut.RoutePostProvisioningWebhook(&rc, definitions.ProvisioningWebhook{URL: "https://example.com", Secret: "s3cr3t"})
+			require.Equal(t, 201, resp.Status(), string(resp.Body()))
+			created := definitions.ProvisioningWebhook{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &created))
+			require.NotEmpty(t, created.UID)
+			require.Empty(t, created.Secret)
+
+			created.Resources = []string{definitions.ProvisioningResourceAlertRule}
+			resp = sut.RoutePutProvisioningWebhook(&rc, created, created.UID)
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+
+			resp = sut.RouteGetProvisioningWebhooks(&rc)
+			require.Equal(t, 200, resp.Status())
+			webhooks := definitions.ProvisioningWebhooks{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &webhooks))
+			require.Equal(t, definitions.ProvisioningWebhooks{created}, webhooks)
+
+			require.Equal(t, 204, sut.RouteDeleteProvisioningWebhook(&rc, created.UID).Status())
+			require.Equal(t, 404, sut.RouteGetProvisioningWebhook(&rc, created.UID).Status())
+			require.Equal(t, 404, sut.RoutePutProvisioningWebhook(&rc, created, created.UID).Status())
+		})
+
+		t.Run("are rejected by POST if invalid", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			resp := sut.RoutePostProvisioningWebhook(&rc, definitions.ProvisioningWebhook{URL: "not a url"})
+
+			require.Equal(t, 400, resp.Status())
+		})
+
+		t.Run("are notified of the changes of alert rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			webhooks := &recordingWebhookService{}
+			sut.webhooks = webhooks
+			rc := createTestRequestCtx()
+			rc.SignedInUser.Login = "admin"
+
+			resp := sut.RoutePostAlertRule(&rc, createTestAlertRule("rule", 1))
+			require.Equal(t, 201, resp.Status())
+			created := definitions.AlertRule{}
+			require.NoError(t, json.Unmarshal(resp.Body(), &created))
+			updated := createTestAlertRule("renamed", 1)
+			updated.UID = created.UID
+			resp = sut.RoutePutAlertRule(&rc, updated, created.UID)
+			require.Equal(t, 200, resp.Status(), string(resp.Body()))
+			require.Equal(t, 204, sut.RouteDeleteAlertRule(&rc, created.UID).Status())
+
+			require.Len(t, webhooks.changes, 3)
+			for i, action := range []string{definitions.ProvisioningChangeCreated, definitions.ProvisioningChangeUpdated, definitions.ProvisioningChangeDeleted} {
+				require.Equal(t, action, webhooks.changes[i].Action)
+				require.Equal(t, definitions.ProvisioningResourceAlertRule, webhooks.changes[i].Resource)
+				require.Equal(t, created.UID, webhooks.changes[i].UID)
+				require.Equal(t, "admin", webhooks.changes[i].User)
+			}
+			require.Equal(t, "rule", webhooks.changes[1].Before.(definitions.AlertRule).Title)
+			require.Equal(t, "renamed", webhooks.changes[1].After.(definitions.AlertRule).Title)
+			require.Nil(t, webhooks.changes[2].After)
+		})
+
+		t.Run("are notified of the changes of the policy tree", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			webhooks := &recordingWebhookService{}
+			sut.webhooks = webhooks
+			rc := createTestRequestCtx()
+			tree := definitions.Route{Receiver: "a", GroupByStr: []string{"alertname"}}
+
+			require.Equal(t, 202, sut.RoutePutPolicyTree(&rc, tree).Status())
+
+			require.Len(t, webhooks.changes, 1)
+			require.Equal(t, definitions.ProvisioningChangeUpdated, webhooks.changes[0].Action)
+			require.Equal(t, definitions.ProvisioningResourceNotificationPolicy, webhooks.changes[0].Resource)
+			require.Equal(t, "a", webhooks.changes[0].After.(definitions.Route).Receiver)
+		})
+
+		t.Run("are not notified of failed changes", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			webhooks := &recordingWebhookService{}
+			sut.webhooks = webhooks
+			rc := createTestRequestCtx()
+
+			require.Equal(t, 404, sut.RoutePutAlertRule(&rc, createTestAlertRule("rule", 1), "does not exist").Status())
+			require.Equal(t, 400, sut.RoutePostAlertRule(&rc, createInvalidAlertRule()).Status())
+
+			require.Empty(t, webhooks.changes)
+		})
+	})
 }
 
 func createProvisioningSrvSut(t *testing.T) ProvisioningSrv {
@@ -276,26 +1937,37 @@ func createProvisioningSrvSut(t *testing.T) ProvisioningSrv {
 	store := store.DBstore{
 		SQLStore:     sqlStore,
 		BaseInterval: time.Second * 10,
+		Logger:       log,
 	}
 	xact := &provisioning.NopTransactionManager{}
 	prov := &provisioning.MockProvisioningStore{}
 	prov.EXPECT().SaveSucceeds()
 	prov.EXPECT().GetReturns(models.ProvenanceNone)
+	prov.EXPECT().GetAllReturns(map[string]models.Provenance{})
 
+	ruleLint := lint.NewService(kvstore.ProvideService(sqlStore), log)
+	contactPoints := provisioning.NewContactPointService(configs, secrets, prov, xact, log)
+	muteTimings := provisioning.NewMuteTimingService(configs, prov, xact, log)
 	return ProvisioningSrv{
 		log:                 log,
 		policies:            newFakeNotificationPolicyService(),
-		contactPointService: provisioning.NewContactPointService(configs, secrets, prov, xact, log),
-		templates:           provisioning.NewTemplateService(configs, prov, xact, log),
-		muteTimings:         provisioning.NewMuteTimingService(configs, prov, xact, log),
-		alertRules:          provisioning.NewAlertRuleService(store, prov, xact, 60, 10, log),
+		contactPointService: contactPoints,
+		templates:           provisioning.NewTemplateService(configs, prov, xact, nil, log),
+		muteTimings:         muteTimings,
+		alertRules:          provisioning.NewAlertRuleService(store, prov, xact, fakeQuotaChecker{}, ruleLint, store, fakeSilenceReader{}, fakeDatasourceReader{}, fakePluginReader{}, fakeDashboardReader{}, setting.UnifiedAlertingSettings{DefaultRuleEvaluationInterval: time.Minute, BaseInterval: 10 * time.Second}, nil, log),
+		ruleLint:            ruleLint,
+		alertmanagerImport:  provisioning.NewAlertmanagerImportService(configs, contactPoints, muteTimings, nil, xact, log),
+		alertmanagerConfig:  &fakeAlertmanagerConfigValidator{},
+		ruleTemplates:       provisioning.NewRuleTemplateService(kvstore.ProvideService(sqlStore), log),
+		webhooks:            provisioning.NewProvisioningWebhookService(kvstore.ProvideService(sqlStore), log),
+		ruleFolders:         &fakeRuleFolderService{},
 	}
 }
 
 func createTestRequestCtx() gfcore.ReqContext {
 	return gfcore.ReqContext{
 		Context: &web.Context{
-			Req: &http.Request{},
+			Req: &http.Request{Header: http.Header{}},
 		},
 		SignedInUser: &gfcore.SignedInUser{
 			OrgId: 1,
@@ -303,9 +1975,135 @@ func createTestRequestCtx() gfcore.ReqContext {
 	}
 }
 
+type fakeQuotaChecker struct{}
+
+func (fakeQuotaChecker) CheckQuotaReached(context.Context, string, *quota.ScopeParameters) (bool, error) {
+	return false, nil
+}
+
+// silencedAlertRuleService is an AlertRuleService whose rule groups all have silenced rules.
+type silencedAlertRuleService struct {
+	AlertRuleService
+}
+
+func (s silencedAlertRuleService) DeleteRuleGroup(ctx context.Context, orgID int64, folderUID, ruleGroup string, provenance models.Provenance, force bool, version string) (definitions.RuleGroupDeletion, error) {
+	if !force {
+		deletion := definitions.RuleGroupDeletion{Silences: []definitions.RuleGroupDeletionSilence{{ID: "silence"}}}
+		return deletion, provisioning.RuleGroupInUseError{Deletion: deletion}
+	}
+	return s.AlertRuleService.DeleteRuleGroup(ctx, orgID, folderUID, ruleGroup, provenance, force, version)
+}
+
+// recordingWebhookService is a ProvisioningWebhookService that is subscribed to every resource and records the
+// changes it is notified of instead of posting them.
+type recordingWebhookService struct {
+	ProvisioningWebhookService
+	changes []definitions.ProvisioningChange
+}
+
+func (s *recordingWebhookService) Subscribed(context.Context, int64, string) bool {
+	return true
+}
+
+func (s *recordingWebhookService) Notify(_ context.Context, _ int64, change definitions.ProvisioningChange) {
+	s.changes = append(s.changes, change)
+}
+
+type fakeExternalAlertmanagerSyncService struct {
+	status definitions.ExternalAlertmanagerSyncStatus
+}
+
+func (f *fakeExternalAlertmanagerSyncService) GetSyncStatus(context.Context, int64) (definitions.ExternalAlertmanagerSyncStatus, error) {
+	return f.status, nil
+}
+
+type fakeProvisioningBatchService struct {
+	result definitions.ProvisioningBatchResult
+	err    error
+}
+
+func (f *fakeProvisioningBatchService) Apply(context.Context, int64, definitions.ProvisioningBatch) (definitions.ProvisioningBatchResult, error) {
+	return f.result, f.err
+}
+
+type fakeAlertmanagerConfigValidator struct {
+	errs []error
+	err  error
+}
+
+func (f *fakeAlertmanagerConfigValidator) ValidateAlertmanagerConfiguration(context.Context, int64, definitions.PostableUserConfig) ([]error, error) {
+	return f.errs, f.err
+}
+
+// fakeRuleFolderService is a RuleFolderService for which all folders exist but the missing ones, which it records
+// the creation and deletion of.
+type fakeRuleFolderService struct {
+	missing map[string]bool
+	created []string
+	deleted []string
+}
+
+func (f *fakeRuleFolderService) EnsureFolder(_ context.Context, _ *gfcore.SignedInUser, _ int64, uid string, folder *definitions.RuleImportFolder, dryRun bool) (bool, error) {
+	if !f.missing[uid] {
+		return false, nil
+	}
+	if folder == nil {
+		return false, fmt.Errorf("%w: the folder '%s' does not exist", provisioning.ErrValidation, uid)
+	}
+	if !dryRun {
+		f.created = append(f.created, uid)
+	}
+	return true, nil
+}
+
+func (f *fakeRuleFolderService) DeleteFolder(_ context.Context, _ *gfcore.SignedInUser, _ int64, uid string) {
+	f.deleted = append(f.deleted, uid)
+}
+
+type fakeSilenceReader definitions.GettableSilences
+
+func (f fakeSilenceReader) ListSilences(context.Context, int64) (definitions.GettableSilences, error) {
+	return definitions.GettableSilences(f), nil
+}
+
+// fakeDatasourceReader has a Prometheus datasource with the UID prometheus in every organization.
+type fakeDatasourceReader struct{}
+
+func (fakeDatasourceReader) GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) error {
+	if query.Uid != "prometheus" {
+		return datasources.ErrDataSourceNotFound
+	}
+	query.Result = &datasources.DataSource{OrgId: query.OrgId, Uid: query.Uid, Type: "prometheus"}
+	return nil
+}
+
+// fakeDashboardReader has the folders with the UIDs folder-uid and other-folder-uid in every organization.
+type fakeDashboardReader struct{}
+
+func (fakeDashboardReader) GetDashboard(ctx context.Context, query *gfcore.GetDashboardQuery) error {
+	if query.Uid != "folder-uid" && query.Uid != "other-folder-uid" {
+		return dashboards.ErrDashboardNotFound
+	}
+	query.Result = &gfcore.Dashboard{OrgId: query.OrgId, Uid: query.Uid, IsFolder: true}
+	return nil
+}
+
+// fakePluginReader has the Prometheus plugin installed.
+type fakePluginReader struct{}
+
+func (fakePluginReader) Plugin(ctx context.Context, pluginID string) (plugins.PluginDTO, bool) {
+	if pluginID != "prometheus" {
+		return plugins.PluginDTO{}, false
+	}
+	return plugins.PluginDTO{JSONData: plugins.JSONData{ID: pluginID, Backend: true, Alerting: true}}, true
+}
+
 type fakeNotificationPolicyService struct {
-	tree definitions.Route
-	prov models.Provenance
+	tree      definitions.Route
+	prov      models.Provenance
+	history   []definitions.Route
+	resetMode provisioning.PolicyResetMode
+	moved     map[string]int
 }
 
 func newFakeNotificationPolicyService() *fakeNotificationPolicyService {
@@ -317,44 +2115,229 @@ func newFakeNotificationPolicyService() *fakeNotificationPolicyService {
 	}
 }
 
-func (f *fakeNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
+func (f *fakeNotificationPolicyService) GetPolicyTreeWithHash(ctx context.Context, orgID int64) (definitions.Route, string, error) {
 	if orgID != 1 {
-		return definitions.Route{}, store.ErrNoAlertmanagerConfiguration
+		return definitions.Route{}, "", store.ErrNoAlertmanagerConfiguration
 	}
 	result := f.tree
 	result.Provenance = f.prov
-	return result, nil
+	return result, f.hash(), nil
+}
+
+func (f *fakeNotificationPolicyService) UpdatePolicyTreeWithHash(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance, fetchedConfigurationHash string) error {
+	if fetchedConfigurationHash != "" && fetchedConfigurationHash != f.hash() {
+		return provisioning.ErrVersionConflict
+	}
+	return f.UpdatePolicyTree(ctx, orgID, tree, p)
+}
+
+func (f *fakeNotificationPolicyService) hash() string {
+	return fmt.Sprintf("hash-%d", len(f.history))
 }
 
 func (f *fakeNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
 	if orgID != 1 {
 		return store.ErrNoAlertmanagerConfiguration
 	}
+	f.history = append(f.history, f.tree)
 	f.tree = tree
 	f.prov = p
 	return nil
 }
 
+func (f *fakeNotificationPolicyService) GetPolicyTreeHistory(ctx context.Context, orgID int64) ([]definitions.NotificationPolicyVersion, error) {
+	result := []definitions.NotificationPolicyVersion{}
+	for i := len(f.history); i > 0; i-- {
+		result = append(result, definitions.NotificationPolicyVersion{Version: int64(i)})
+	}
+	return result, nil
+}
+
+func (f *fakeNotificationPolicyService) GetPolicyTreeVersion(ctx context.Context, orgID int64, version int64) (definitions.NotificationPolicyVersion, error) {
+	if version < 1 || version > int64(len(f.history)) {
+		return definitions.NotificationPolicyVersion{}, provisioning.ErrNotFound
+	}
+	tree := f.history[version-1]
+	return definitions.NotificationPolicyVersion{Version: version, Policy: &tree}, nil
+}
+
+func (f *fakeNotificationPolicyService) RollbackPolicyTree(ctx context.Context, orgID int64, version int64, p models.Provenance) error {
+	v, err := f.GetPolicyTreeVersion(ctx, orgID, version)
+	if err != nil {
+		return err
+	}
+	return f.UpdatePolicyTree(ctx, orgID, *v.Policy, p)
+}
+
+func (f *fakeNotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64, mode provisioning.PolicyResetMode) error {
+	if orgID != 1 {
+		return store.ErrNoAlertmanagerConfiguration
+	}
+	f.resetMode = mode
+	return nil
+}
+
+func (f *fakeNotificationPolicyService) MoveRoute(ctx context.Context, orgID int64, routeID string, index int) error {
+	if routeID == "unknown" {
+		return provisioning.ErrNotFound
+	}
+	if f.moved == nil {
+		f.moved = map[string]int{}
+	}
+	f.moved[routeID] = index
+	return nil
+}
+
+func (f *fakeNotificationPolicyService) GetEffectivePolicies(ctx context.Context, orgID int64) ([]definitions.EffectivePolicy, error) {
+	return provisioning.EffectivePolicies(&f.tree), nil
+}
+
+func (f *fakeNotificationPolicyService) GetPoliciesByReceiver(ctx context.Context, orgID int64, receiver string) ([]definitions.ReceiverPolicy, error) {
+	return provisioning.PoliciesByReceiver(&f.tree, receiver), nil
+}
+
+func (f *fakeNotificationPolicyService) GetPolicyTreeGraph(ctx context.Context, orgID int64) (definitions.PolicyTreeGraph, error) {
+	return provisioning.PolicyTreeGraph(&f.tree, nil, nil), nil
+}
+
+func (f *fakeNotificationPolicyService) RepointRoutes(ctx context.Context, orgID int64, from, to string) (int, error) {
+	if orgID != 1 {
+		return 0, store.ErrNoAlertmanagerConfiguration
+	}
+	return 1, nil
+}
+
+func (f *fakeNotificationPolicyService) ApplyPolicyTreeTemplate(ctx context.Context, orgID int64, name string, values map[string]string, p models.Provenance) (definitions.Route, error) {
+	if name != "per-team" {
+		return definitions.Route{}, provisioning.ErrNotFound
+	}
+	tree := definitions.Route{Receiver: values["default_receiver"]}
+	if err := f.UpdatePolicyTree(ctx, orgID, tree, p); err != nil {
+		return definitions.Route{}, err
+	}
+	return tree, nil
+}
+
+func (f *fakeNotificationPolicyService) LintPolicyTree(ctx context.Context, orgID int64, tree definitions.Route) ([]definitions.PolicyTreeLintWarning, error) {
+	return []definitions.PolicyTreeLintWarning{{
+		Receiver: tree.Receiver,
+		Matcher:  `team="a"`,
+		Label:    "team",
+		Message:  "no alert rule has the label 'team'",
+	}}, nil
+}
+
 type fakeFailingNotificationPolicyService struct{}
 
-func (f *fakeFailingNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
-	return definitions.Route{}, fmt.Errorf("something went wrong")
+func (f *fakeFailingNotificationPolicyService) GetPolicyTreeWithHash(ctx context.Context, orgID int64) (definitions.Route, string, error) {
+	return definitions.Route{}, "", fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) UpdatePolicyTreeWithHash(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance, fetchedConfigurationHash string) error {
+	return fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) GetPolicyTreeHistory(ctx context.Context, orgID int64) ([]definitions.NotificationPolicyVersion, error) {
+	return nil, fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) GetPolicyTreeVersion(ctx context.Context, orgID int64, version int64) (definitions.NotificationPolicyVersion, error) {
+	return definitions.NotificationPolicyVersion{}, fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) RollbackPolicyTree(ctx context.Context, orgID int64, version int64, p models.Provenance) error {
+	return fmt.Errorf("something went wrong")
 }
 
-func (f *fakeFailingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
+func (f *fakeFailingNotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64, mode provisioning.PolicyResetMode) error {
 	return fmt.Errorf("something went wrong")
 }
 
+func (f *fakeFailingNotificationPolicyService) MoveRoute(ctx context.Context, orgID int64, routeID string, index int) error {
+	return fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) GetEffectivePolicies(ctx context.Context, orgID int64) ([]definitions.EffectivePolicy, error) {
+	return nil, fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) GetPoliciesByReceiver(ctx context.Context, orgID int64, receiver string) ([]definitions.ReceiverPolicy, error) {
+	return nil, fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) GetPolicyTreeGraph(ctx context.Context, orgID int64) (definitions.PolicyTreeGraph, error) {
+	return definitions.PolicyTreeGraph{}, fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) RepointRoutes(ctx context.Context, orgID int64, from, to string) (int, error) {
+	return 0, fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) ApplyPolicyTreeTemplate(ctx context.Context, orgID int64, name string, values map[string]string, p models.Provenance) (definitions.Route, error) {
+	return definitions.Route{}, fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) LintPolicyTree(ctx context.Context, orgID int64, tree definitions.Route) ([]definitions.PolicyTreeLintWarning, error) {
+	return nil, fmt.Errorf("something went wrong")
+}
+
 type fakeRejectingNotificationPolicyService struct{}
 
-func (f *fakeRejectingNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
-	return definitions.Route{}, nil
+func (f *fakeRejectingNotificationPolicyService) GetPolicyTreeWithHash(ctx context.Context, orgID int64) (definitions.Route, string, error) {
+	return definitions.Route{}, "", nil
+}
+
+func (f *fakeRejectingNotificationPolicyService) UpdatePolicyTreeWithHash(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance, fetchedConfigurationHash string) error {
+	if err := tree.ValidateMuteTimes(map[string]struct{}{}); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: invalid policy tree", provisioning.ErrValidation)
+}
+
+func (f *fakeRejectingNotificationPolicyService) GetPolicyTreeHistory(ctx context.Context, orgID int64) ([]definitions.NotificationPolicyVersion, error) {
+	return []definitions.NotificationPolicyVersion{}, nil
+}
+
+func (f *fakeRejectingNotificationPolicyService) GetPolicyTreeVersion(ctx context.Context, orgID int64, version int64) (definitions.NotificationPolicyVersion, error) {
+	return definitions.NotificationPolicyVersion{Version: version, Policy: &definitions.Route{}}, nil
 }
 
-func (f *fakeRejectingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
+func (f *fakeRejectingNotificationPolicyService) RollbackPolicyTree(ctx context.Context, orgID int64, version int64, p models.Provenance) error {
 	return fmt.Errorf("%w: invalid policy tree", provisioning.ErrValidation)
 }
 
+func (f *fakeRejectingNotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64, mode provisioning.PolicyResetMode) error {
+	return fmt.Errorf("%w: unknown reset mode", provisioning.ErrValidation)
+}
+
+func (f *fakeRejectingNotificationPolicyService) MoveRoute(ctx context.Context, orgID int64, routeID string, index int) error {
+	return fmt.Errorf("%w: index is out of range", provisioning.ErrValidation)
+}
+
+func (f *fakeRejectingNotificationPolicyService) GetEffectivePolicies(ctx context.Context, orgID int64) ([]definitions.EffectivePolicy, error) {
+	return nil, store.ErrNoAlertmanagerConfiguration
+}
+
+func (f *fakeRejectingNotificationPolicyService) GetPoliciesByReceiver(ctx context.Context, orgID int64, receiver string) ([]definitions.ReceiverPolicy, error) {
+	return nil, store.ErrNoAlertmanagerConfiguration
+}
+
+func (f *fakeRejectingNotificationPolicyService) GetPolicyTreeGraph(ctx context.Context, orgID int64) (definitions.PolicyTreeGraph, error) {
+	return definitions.PolicyTreeGraph{}, store.ErrNoAlertmanagerConfiguration
+}
+
+func (f *fakeRejectingNotificationPolicyService) RepointRoutes(ctx context.Context, orgID int64, from, to string) (int, error) {
+	return 0, fmt.Errorf("%w: unknown receiver", provisioning.ErrValidation)
+}
+
+func (f *fakeRejectingNotificationPolicyService) ApplyPolicyTreeTemplate(ctx context.Context, orgID int64, name string, values map[string]string, p models.Provenance) (definitions.Route, error) {
+	return definitions.Route{}, fmt.Errorf("%w: missing values for the placeholders teams", provisioning.ErrValidation)
+}
+
+func (f *fakeRejectingNotificationPolicyService) LintPolicyTree(ctx context.Context, orgID int64, tree definitions.Route) ([]definitions.PolicyTreeLintWarning, error) {
+	return []definitions.PolicyTreeLintWarning{}, nil
+}
+
 func createInvalidContactPoint() definitions.EmbeddedContactPoint {
 	settings, _ := simplejson.NewJson([]byte(`{}`))
 	return definitions.EmbeddedContactPoint{
@@ -366,9 +2349,9 @@ func createInvalidContactPoint() definitions.EmbeddedContactPoint {
 
 func createInvalidMuteTiming() definitions.MuteTimeInterval {
 	return definitions.MuteTimeInterval{
-		MuteTimeInterval: prometheus.MuteTimeInterval{
+		MuteTimeIntervalConfig: definitions.MuteTimeIntervalConfig{
 			Name: "interval",
-			TimeIntervals: []timeinterval.TimeInterval{
+			TimeIntervals: []definitions.TimeInterval{
 				{
 					Weekdays: []timeinterval.WeekdayRange{
 						{
@@ -395,8 +2378,9 @@ func createTestAlertRule(title string, orgID int64) definitions.AlertRule {
 		Condition: "A",
 		Data: []models.AlertQuery{
 			{
-				RefID: "A",
-				Model: json.RawMessage("{}"),
+				RefID:         "A",
+				Model:         json.RawMessage("{}"),
+				DatasourceUID: "prometheus",
 				RelativeTimeRange: models.RelativeTimeRange{
 					From: models.Duration(60),
 					To:   models.Duration(0),
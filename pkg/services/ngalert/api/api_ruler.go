@@ -39,6 +39,7 @@ type RulerSrv struct {
 	log             log.Logger
 	cfg             *setting.UnifiedAlertingSettings
 	ac              accesscontrol.AccessControl
+	ruleLint        RuleLintService
 }
 
 var (
@@ -320,16 +321,21 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConf
 		RuleGroup:    ruleGroupConfig.Name,
 	}
 
-	return srv.updateAlertRulesInGroup(c, groupKey, rules)
+	violations, err := srv.ruleLint.CheckRules(c.Req.Context(), c.SignedInUser.OrgId, rules)
+	if err != nil {
+		return ruleLintErrorResp(err, violations)
+	}
+
+	return srv.updateAlertRulesInGroup(c, groupKey, rules, violations)
 }
 
 // updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
-// All operations are performed in a single transaction
-func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRule) response.Response {
+// All operations are performed in a single transaction. The lint violations of the rules are returned to the user if the changes are saved.
+func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRule, violations []apimodels.RuleLintViolation) response.Response {
 	var finalChanges *changes
 	hasAccess := accesscontrol.HasAccess(srv.ac, c)
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
-		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group", groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", c.UserId)
+		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group", groupKey.RuleGroup, "orgID", groupKey.OrgID, "user_id", c.UserId)
 		groupChanges, err := calculateChanges(tranCtx, srv.store, groupKey, rules)
 		if err != nil {
 			return err
@@ -362,7 +368,7 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, groupKey ngmod
 			updates := make([]store.UpdateRule, 0, len(finalChanges.Update))
 			inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
 			for _, update := range finalChanges.Update {
-				logger.Debug("updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
+				logger.Debug("updating rule", "ruleUID", update.New.UID, "diff", update.Diff.String())
 				updates = append(updates, store.UpdateRule{
 					Existing: update.Existing,
 					New:      *update.New,
@@ -436,11 +442,14 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, groupKey ngmod
 		})
 	}
 
+	body := util.DynMap{"message": "rule group updated successfully"}
 	if finalChanges.isEmpty() {
-		return response.JSON(http.StatusAccepted, util.DynMap{"message": "no changes detected in the rule group"})
+		body["message"] = "no changes detected in the rule group"
 	}
-
-	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
+	if len(violations) > 0 {
+		body["lintViolations"] = violations
+	}
+	return response.JSON(http.StatusAccepted, body)
 }
 
 func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, namespaceID int64, provenanceRecords map[string]ngmodels.Provenance) apimodels.GettableRuleGroupConfig {
@@ -481,6 +490,9 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 			RuleGroup:       r.RuleGroup,
 			NoDataState:     apimodels.NoDataState(r.NoDataState),
 			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
+			Webhooks:        r.Webhooks,
+			IsPaused:        r.IsPaused,
+			MaxInstances:    r.MaxInstances,
 			Provenance:      provenance,
 		},
 	}
@@ -108,6 +108,19 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		Webhooks:        ruleNode.GrafanaManagedAlert.Webhooks,
+		IsPaused:        ruleNode.GrafanaManagedAlert.IsPaused,
+		MaxInstances:    ruleNode.GrafanaManagedAlert.MaxInstances,
+	}
+
+	if newAlertRule.MaxInstances < 0 {
+		return nil, fmt.Errorf("%w: max_instances cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	for _, w := range newAlertRule.Webhooks {
+		if err := w.Validate(); err != nil {
+			return nil, err
+		}
 	}
 
 	var err error
@@ -201,3 +214,57 @@ func validateRuleGroup(
 	}
 	return result, nil
 }
+
+// ruleGroupProblems returns the problems of the name and the interval of a rule group, and the interval to check its
+// rules with. It is the default interval if the interval of the group is not valid, so that the rules can still be
+// checked.
+func ruleGroupProblems(ruleGroupConfig *apimodels.PostableRuleGroupConfig, cfg *setting.UnifiedAlertingSettings) ([]string, time.Duration) {
+	var problems []string
+	if ruleGroupConfig.Name == "" {
+		problems = append(problems, "rule group name cannot be empty")
+	}
+	if len(ruleGroupConfig.Name) > store.AlertRuleMaxRuleGroupNameLength {
+		problems = append(problems, fmt.Sprintf("rule group name is too long. Max length is %d", store.AlertRuleMaxRuleGroupNameLength))
+	}
+
+	interval := time.Duration(ruleGroupConfig.Interval)
+	if interval == 0 {
+		interval = cfg.DefaultRuleEvaluationInterval
+	}
+	if interval < 0 || int64(interval.Seconds())%int64(cfg.BaseInterval.Seconds()) != 0 {
+		problems = append(problems, fmt.Sprintf("rule evaluation interval (%d second) should be positive number that is multiple of the base interval of %d seconds", int64(interval.Seconds()), int64(cfg.BaseInterval.Seconds())))
+		interval = cfg.DefaultRuleEvaluationInterval
+	}
+	return problems, interval
+}
+
+// ruleNodeProblems returns the problems of the settings of a rule, apart from its queries. The no data and error
+// handling states and the pending period are checked on their own so that all of their problems are reported, the
+// other checks of validateRuleNode are only reported if they pass.
+func ruleNodeProblems(ruleNode *apimodels.PostableExtendedRuleNode, validate func() error) []string {
+	if ruleNode.GrafanaManagedAlert == nil {
+		return []string{"not Grafana managed alert rule"}
+	}
+
+	var problems []string
+	if s := ruleNode.GrafanaManagedAlert.NoDataState; s != "" {
+		if _, err := ngmodels.NoDataStateFromString(string(s)); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if s := ruleNode.GrafanaManagedAlert.ExecErrState; s != "" {
+		if _, err := ngmodels.ErrStateFromString(string(s)); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if _, err := validateForInterval(ruleNode); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	if err := validate(); err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
@@ -244,6 +244,7 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Equal(t, time.Duration(*api.ApiRuleNode.For), alert.For)
 				require.Equal(t, api.ApiRuleNode.Annotations, alert.Annotations)
 				require.Equal(t, api.ApiRuleNode.Labels, alert.Labels)
+				require.Equal(t, api.GrafanaManagedAlert.Webhooks, alert.Webhooks)
 			},
 		},
 		{
@@ -381,6 +382,22 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if max instances is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.MaxInstances = -1
+				return &r
+			},
+		},
+		{
+			name: "fail if webhook URL is not absolute",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Webhooks = []models.AlertRuleWebhook{{URL: "/hook"}}
+				return &r
+			},
+		},
 		{
 			name: "fail if there are not data (nil)",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -18,6 +19,8 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -27,6 +30,8 @@ type TestingApiSrv struct {
 	log             log.Logger
 	accessControl   accesscontrol.AccessControl
 	evaluator       eval.Evaluator
+	ruleStore       store.RuleStore
+	cfg             *setting.UnifiedAlertingSettings
 }
 
 func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *models.ReqContext, body apimodels.TestRulePayload) response.Response {
@@ -61,9 +66,14 @@ func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *models.ReqContext, body a
 	}
 
 	frame := evalResults.AsDataFrame()
-	return response.JSONStreaming(http.StatusOK, util.DynMap{
+	res := util.DynMap{
 		"instances": []*data.Frame{&frame},
-	})
+	}
+	// all results of an evaluation share the statistics of its queries
+	if len(evalResults) > 0 && len(evalResults[0].QueryStats) > 0 {
+		res["queryStats"] = evalResults[0].QueryStats
+	}
+	return response.JSONStreaming(http.StatusOK, res)
 }
 
 func (srv TestingApiSrv) RouteTestRuleConfig(c *models.ReqContext, body apimodels.TestRulePayload, datasourceUID string) response.Response {
@@ -128,3 +138,229 @@ func (srv TestingApiSrv) RouteEvalQueries(c *models.ReqContext, cmd apimodels.Ev
 
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
+
+// maxBacktestEvaluations is the maximum number of evaluations of a backtest, so that a backtest does not overload the
+// data sources.
+const maxBacktestEvaluations = 1000
+
+func (srv TestingApiSrv) RouteBacktestRule(c *models.ReqContext, body apimodels.BacktestPayload) response.Response {
+	if !authorizeDatasourceAccessForRule(&ngmodels.AlertRule{Data: body.Data}, func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.accessControl, c)(accesscontrol.ReqSignedIn, evaluator)
+	}) {
+		return ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization), "")
+	}
+
+	interval := time.Duration(body.Interval)
+	if interval == 0 {
+		interval = srv.cfg.DefaultRuleEvaluationInterval
+	}
+	if err := validateBacktest(body, interval, srv.cfg); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid backtest")
+	}
+
+	evalCond := ngmodels.Condition{
+		Condition: body.Condition,
+		OrgID:     c.SignedInUser.OrgId,
+		Data:      body.Data,
+	}
+	if err := validateCondition(c.Req.Context(), evalCond, c.SignedInUser, c.SkipCache, srv.DatasourceCache); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid condition")
+	}
+
+	result, err := backtest(srv.evaluator, &evalCond, body.From, body.To, interval, time.Duration(body.For))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "Failed to evaluate conditions")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func validateBacktest(body apimodels.BacktestPayload, interval time.Duration, cfg *setting.UnifiedAlertingSettings) error {
+	if body.From.IsZero() || body.To.IsZero() {
+		return errors.New("the time range is required")
+	}
+	if !body.From.Before(body.To) {
+		return errors.New("the start of the time range must be before its end")
+	}
+	if body.For < 0 {
+		return errors.New("the duration of 'for' cannot be negative")
+	}
+	if err := ngmodels.ValidateRuleGroupInterval(int64(interval.Seconds()), int64(cfg.BaseInterval.Seconds())); err != nil {
+		return err
+	}
+	if interval < cfg.MinInterval {
+		return fmt.Errorf("interval (%v) is lower than the minimum interval (%v)", interval, cfg.MinInterval)
+	}
+	if evaluations := int64(body.To.Sub(body.From)/interval) + 1; evaluations > maxBacktestEvaluations {
+		return fmt.Errorf("the time range requires %d evaluations, which is more than the maximum of %d: use a shorter time range or a longer interval", evaluations, maxBacktestEvaluations)
+	}
+	return nil
+}
+
+// backtest evaluates the condition at every interval from the start to the end of the time range, and returns the
+// periods during which each alert instance would have been firing. Like the scheduler does, an instance fires once
+// its condition has been met for the duration of 'for', and it stops firing as soon as it is not met, which includes
+// the evaluations with no data or errors.
+func backtest(evaluator eval.Evaluator, condition *ngmodels.Condition, from, to time.Time, interval, forDuration time.Duration) (apimodels.BacktestResult, error) {
+	result := apimodels.BacktestResult{Instances: []apimodels.BacktestInstance{}}
+	// the index of the instances in the result, by their labels
+	instances := map[string]int{}
+	// the time since which the condition of the instances is met
+	alertingSince := map[string]time.Time{}
+	for now := from; !now.After(to); now = now.Add(interval) {
+		results, err := evaluator.ConditionEval(condition, now)
+		if err != nil {
+			return apimodels.BacktestResult{}, fmt.Errorf("failed to evaluate the condition at %s: %w", now.Format(time.RFC3339), err)
+		}
+		result.Evaluations++
+
+		alerting := map[string]struct{}{}
+		for _, r := range results {
+			if r.State != eval.Alerting {
+				continue
+			}
+			key := r.Instance.String()
+			alerting[key] = struct{}{}
+			since, ok := alertingSince[key]
+			if !ok {
+				since = now
+				alertingSince[key] = now
+			}
+			if now.Sub(since) < forDuration {
+				continue
+			}
+			idx, ok := instances[key]
+			if !ok {
+				idx = len(result.Instances)
+				instances[key] = idx
+				result.Instances = append(result.Instances, apimodels.BacktestInstance{Labels: r.Instance.Copy(), Firing: []apimodels.BacktestPeriod{}})
+			}
+			instance := &result.Instances[idx]
+			if n := len(instance.Firing); n == 0 || instance.Firing[n-1].End != nil {
+				instance.Firing = append(instance.Firing, apimodels.BacktestPeriod{Start: now})
+			}
+		}
+
+		for key := range alertingSince {
+			if _, ok := alerting[key]; ok {
+				continue
+			}
+			delete(alertingSince, key)
+			if idx, ok := instances[key]; ok {
+				instance := &result.Instances[idx]
+				if n := len(instance.Firing); n > 0 && instance.Firing[n-1].End == nil {
+					end := now
+					instance.Firing[n-1].End = &end
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func (srv TestingApiSrv) RouteValidateGrafanaRuleGroup(c *models.ReqContext, ruleGroupConfig apimodels.PostableRuleGroupConfig, namespaceTitle string) response.Response {
+	namespace, err := srv.ruleStore.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.OrgId, c.SignedInUser, false)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	result := apimodels.RuleGroupValidationResult{Problems: []apimodels.RuleValidationProblem{}}
+	groupProblems, interval := ruleGroupProblems(&ruleGroupConfig, srv.cfg)
+	for _, p := range groupProblems {
+		result.Problems = append(result.Problems, apimodels.RuleValidationProblem{Message: p})
+	}
+
+	uids := make(map[string]int, len(ruleGroupConfig.Rules))
+	for idx := range ruleGroupConfig.Rules {
+		ruleNode := &ruleGroupConfig.Rules[idx]
+		var problems []apimodels.RuleValidationProblem
+		if ruleNode.GrafanaManagedAlert != nil {
+			problems = srv.queryProblems(c, ruleNode.GrafanaManagedAlert)
+			if uid := ruleNode.GrafanaManagedAlert.UID; uid != "" {
+				if existingIdx, ok := uids[uid]; ok {
+					problems = append(problems, apimodels.RuleValidationProblem{Message: fmt.Sprintf("rule has UID %s that is already assigned to another rule at index %d", uid, existingIdx)})
+				} else {
+					uids[uid] = idx
+				}
+			}
+		}
+		queriesValid := len(problems) == 0
+		for _, p := range ruleNodeProblems(ruleNode, func() error {
+			if !queriesValid {
+				return nil
+			}
+			noop := func(ngmodels.Condition) error { return nil }
+			_, err := validateRuleNode(ruleNode, ruleGroupConfig.Name, interval, c.SignedInUser.OrgId, namespace, noop, srv.cfg)
+			return err
+		}) {
+			problems = append(problems, apimodels.RuleValidationProblem{Message: p})
+		}
+
+		index := idx
+		for _, p := range problems {
+			p.RuleIndex = &index
+			if ruleNode.GrafanaManagedAlert != nil {
+				p.RuleTitle = ruleNode.GrafanaManagedAlert.Title
+			}
+			result.Problems = append(result.Problems, p)
+		}
+	}
+	result.Valid = len(result.Problems) == 0
+	return response.JSON(http.StatusOK, result)
+}
+
+// queryProblems returns the problems of the queries and expressions of a rule. If their data sources exist and the
+// condition references one of them, they are executed to report the errors of the data sources, such as syntax errors.
+func (srv TestingApiSrv) queryProblems(c *models.ReqContext, rule *apimodels.PostableGrafanaRule) []apimodels.RuleValidationProblem {
+	if len(rule.Data) == 0 {
+		if rule.UID != "" {
+			// the queries of the existing rule are kept
+			return nil
+		}
+		return []apimodels.RuleValidationProblem{{Message: "no queries or expressions are found"}}
+	}
+
+	if !authorizeDatasourceAccessForRule(&ngmodels.AlertRule{Data: rule.Data}, func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.accessControl, c)(accesscontrol.ReqSignedIn, evaluator)
+	}) {
+		return []apimodels.RuleValidationProblem{{Message: "not authorized to query one or many data sources used by the rule"}}
+	}
+
+	var problems []apimodels.RuleValidationProblem
+	refIDs := make(map[string]struct{}, len(rule.Data))
+	for _, query := range rule.Data {
+		refIDs[query.RefID] = struct{}{}
+		datasourceUID, err := query.GetDatasource()
+		if err != nil {
+			problems = append(problems, apimodels.RuleValidationProblem{RefID: query.RefID, Message: err.Error()})
+			continue
+		}
+		isExpression, err := query.IsExpression()
+		if err != nil {
+			problems = append(problems, apimodels.RuleValidationProblem{RefID: query.RefID, Message: err.Error()})
+			continue
+		}
+		if isExpression {
+			continue
+		}
+		if _, err := srv.DatasourceCache.GetDatasourceByUID(c.Req.Context(), datasourceUID, c.SignedInUser, c.SkipCache); err != nil {
+			problems = append(problems, apimodels.RuleValidationProblem{RefID: query.RefID, Message: fmt.Sprintf("data source %s: %s", datasourceUID, err)})
+		}
+	}
+	if _, ok := refIDs[rule.Condition]; !ok {
+		problems = append(problems, apimodels.RuleValidationProblem{Message: fmt.Sprintf("condition %s not found in any query or expression", rule.Condition)})
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	resp, err := srv.evaluator.QueriesAndExpressionsEval(c.SignedInUser.OrgId, rule.Data, timeNow())
+	if err != nil {
+		return []apimodels.RuleValidationProblem{{Message: fmt.Sprintf("failed to execute queries and expressions: %s", err)}}
+	}
+	for _, query := range rule.Data {
+		if r, ok := resp.Responses[query.RefID]; ok && r.Error != nil {
+			problems = append(problems, apimodels.RuleValidationProblem{RefID: query.RefID, Message: r.Error.Error()})
+		}
+	}
+	return problems
+}
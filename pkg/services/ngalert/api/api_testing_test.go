@@ -1,14 +1,21 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/expr"
 	models2 "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
@@ -17,6 +24,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -86,6 +95,46 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 
 			evaluator.AssertCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything)
 		})
+
+		t.Run("should return the statistics of the queries", func(t *testing.T) {
+			data1 := models.GenerateAlertQuery()
+
+			ac := acMock.New().WithPermissions([]accesscontrol.Permission{
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+			})
+
+			ds := &fakes.FakeCacheService{DataSources: []*datasources.DataSource{
+				{Uid: data1.DatasourceUID},
+			}}
+
+			evaluator := &eval.FakeEvaluator{}
+			result := eval.Results{{
+				State: eval.Normal,
+				QueryStats: map[string]expr.QueryStats{
+					data1.RefID: {SeriesCount: 1, SampleCount: 60, Bytes: 1024, Latency: time.Second},
+				},
+			}}
+			evaluator.EXPECT().ConditionEval(mock.Anything, mock.Anything).Return(result, nil)
+
+			srv := createTestingApiSrv(ds, ac, evaluator)
+
+			response := srv.RouteTestGrafanaRuleConfig(rc, definitions.TestRulePayload{
+				GrafanaManagedCondition: &definitions.EvalAlertConditionCommand{
+					Condition: data1.RefID,
+					Data:      []models.AlertQuery{data1},
+				},
+			})
+
+			require.Equal(t, http.StatusOK, response.Status())
+			recorder := httptest.NewRecorder()
+			response.WriteTo(&models2.ReqContext{Context: &web.Context{Resp: web.NewResponseWriter("GET", recorder)}})
+
+			var body struct {
+				QueryStats map[string]expr.QueryStats `json:"queryStats"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+			require.Equal(t, result[0].QueryStats, body.QueryStats)
+		})
 	})
 
 	t.Run("when fine-grained access is disabled", func(t *testing.T) {
@@ -266,6 +315,98 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestRouteBacktestRule(t *testing.T) {
+	rc := &models2.ReqContext{
+		Context:      &web.Context{Req: &http.Request{}},
+		IsSignedIn:   true,
+		SignedInUser: &models2.SignedInUser{OrgId: 1},
+	}
+	cfg := &setting.UnifiedAlertingSettings{
+		BaseInterval:                  10 * time.Second,
+		MinInterval:                   10 * time.Second,
+		DefaultRuleEvaluationInterval: time.Minute,
+	}
+	from := time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)
+	query := models.GenerateAlertQuery()
+	ds := &fakes.FakeCacheService{DataSources: []*datasources.DataSource{{Uid: query.DatasourceUID}}}
+
+	t.Run("should return 401 if user cannot query a data source", func(t *testing.T) {
+		srv := createTestingApiSrv(ds, acMock.New(), &eval.FakeEvaluator{})
+		srv.cfg = cfg
+
+		response := srv.RouteBacktestRule(rc, definitions.BacktestPayload{
+			Condition: query.RefID,
+			Data:      []models.AlertQuery{query},
+			From:      from,
+			To:        from.Add(time.Hour),
+		})
+
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+	})
+
+	t.Run("should return 400 if the backtest is invalid", func(t *testing.T) {
+		srv := createTestingApiSrv(ds, nil, &eval.FakeEvaluator{})
+		srv.cfg = cfg
+		testCases := map[string]definitions.BacktestPayload{
+			"no time range":       {To: from},
+			"reversed time range": {From: from, To: from.Add(-time.Hour)},
+			"negative for":        {From: from, To: from.Add(time.Hour), For: model.Duration(-time.Minute)},
+			"invalid interval":    {From: from, To: from.Add(time.Hour), Interval: model.Duration(15 * time.Second)},
+			"too many evaluations": {
+				From:     from,
+				To:       from.Add(24 * time.Hour),
+				Interval: model.Duration(10 * time.Second),
+			},
+		}
+		for name, body := range testCases {
+			t.Run(name, func(t *testing.T) {
+				body.Condition = query.RefID
+				body.Data = []models.AlertQuery{query}
+				response := srv.RouteBacktestRule(rc, body)
+				require.Equal(t, http.StatusBadRequest, response.Status())
+			})
+		}
+	})
+
+	t.Run("should return the periods during which the instances would have fired", func(t *testing.T) {
+		// instance a meets the condition from minute 1 to 5 and from minute 8 to 9, instance b only at minute 3
+		a := data.Labels{"instance": "a"}
+		b := data.Labels{"instance": "b"}
+		evaluator := &eval.FakeEvaluator{}
+		evaluator.On("ConditionEval", mock.Anything, mock.Anything).Return(func(_ *models.Condition, now time.Time) eval.Results {
+			minute := int(now.Sub(from) / time.Minute)
+			results := eval.Results{{Instance: a, State: eval.Normal}, {Instance: b, State: eval.Normal}}
+			if (minute >= 1 && minute <= 5) || minute >= 8 {
+				results[0].State = eval.Alerting
+			}
+			if minute == 3 {
+				results[1].State = eval.Alerting
+			}
+			return results
+		}, nil)
+		srv := createTestingApiSrv(ds, nil, evaluator)
+		srv.cfg = cfg
+
+		response := srv.RouteBacktestRule(rc, definitions.BacktestPayload{
+			Condition: query.RefID,
+			Data:      []models.AlertQuery{query},
+			For:       model.Duration(2 * time.Minute),
+			From:      from,
+			To:        from.Add(9 * time.Minute),
+		})
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var result definitions.BacktestResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, 10, result.Evaluations)
+		end := from.Add(6 * time.Minute)
+		require.Equal(t, []definitions.BacktestInstance{{
+			Labels: map[string]string{"instance": "a"},
+			Firing: []definitions.BacktestPeriod{{Start: from.Add(3 * time.Minute), End: &end}},
+		}}, result.Instances)
+	})
+}
+
 func createTestingApiSrv(ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator *eval.FakeEvaluator) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New().WithDisabled()
@@ -277,3 +418,84 @@ func createTestingApiSrv(ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator
 		evaluator:       evaluator,
 	}
 }
+
+func TestRouteValidateGrafanaRuleGroup(t *testing.T) {
+	rc := &models2.ReqContext{
+		Context:      &web.Context{Req: &http.Request{}},
+		IsSignedIn:   true,
+		SignedInUser: &models2.SignedInUser{OrgId: 1},
+	}
+	cfg := config(t)
+	folder := randFolder()
+	ruleStore := store.NewFakeRuleStore(t)
+	ruleStore.Folders[1] = append(ruleStore.Folders[1], folder)
+	ds := &fakes.FakeCacheService{DataSources: []*datasources.DataSource{{Uid: "DATASOURCE_TEST"}}}
+
+	newSrv := func(evaluator eval.Evaluator) *TestingApiSrv {
+		return &TestingApiSrv{
+			DatasourceCache: ds,
+			accessControl:   acMock.New().WithDisabled(),
+			evaluator:       evaluator,
+			ruleStore:       ruleStore,
+			cfg:             cfg,
+		}
+	}
+	validate := func(t *testing.T, srv *TestingApiSrv, group definitions.PostableRuleGroupConfig) definitions.RuleGroupValidationResult {
+		t.Helper()
+		response := srv.RouteValidateGrafanaRuleGroup(rc, group, folder.Title)
+		require.Equal(t, http.StatusOK, response.Status())
+		var result definitions.RuleGroupValidationResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		return result
+	}
+
+	t.Run("should report no problems for a valid group", func(t *testing.T) {
+		evaluator := &eval.FakeEvaluator{}
+		evaluator.EXPECT().QueriesAndExpressionsEval(mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+			Responses: map[string]backend.DataResponse{"A": {}},
+		}, nil)
+
+		result := validate(t, newSrv(evaluator), validGroup(cfg, validRule()))
+
+		require.True(t, result.Valid)
+		require.Empty(t, result.Problems)
+		evaluator.AssertNumberOfCalls(t, "QueriesAndExpressionsEval", 1)
+	})
+
+	t.Run("should report all problems of the group and its rules", func(t *testing.T) {
+		evaluator := &eval.FakeEvaluator{}
+		evaluator.EXPECT().QueriesAndExpressionsEval(mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+			Responses: map[string]backend.DataResponse{"A": {Error: errors.New("parse error at char 5")}},
+		}, nil)
+
+		invalidStates := validRule()
+		invalidStates.GrafanaManagedAlert.NoDataState = "Unknown"
+		invalidStates.GrafanaManagedAlert.ExecErrState = "Unknown"
+		invalidStates.GrafanaManagedAlert.Data[0].DatasourceUID = "missing"
+		invalidStates.GrafanaManagedAlert.Condition = "B"
+		invalidQuery := validRule()
+		group := validGroup(cfg, invalidStates, invalidQuery)
+		group.Interval = model.Duration(-cfg.BaseInterval)
+
+		result := validate(t, newSrv(evaluator), group)
+
+		require.False(t, result.Valid)
+		var messages []string
+		for _, p := range result.Problems {
+			idx := -1
+			if p.RuleIndex != nil {
+				idx = *p.RuleIndex
+			}
+			messages = append(messages, fmt.Sprintf("%d/%s: %s", idx, p.RefID, p.Message))
+		}
+		require.Len(t, messages, 6, messages)
+		require.Contains(t, messages[0], "-1/: rule evaluation interval")
+		require.Contains(t, messages[1], "0/A: data source missing")
+		require.Equal(t, "0/: condition B not found in any query or expression", messages[2])
+		require.Contains(t, messages[3], "0/: unknown NoData state option Unknown")
+		require.Contains(t, messages[4], "0/: unknown Error state option Unknown")
+		require.Equal(t, "1/A: parse error at char 5", messages[5])
+		require.Equal(t, invalidQuery.GrafanaManagedAlert.Title, result.Problems[5].RuleTitle)
+		evaluator.AssertNumberOfCalls(t, "QueriesAndExpressionsEval", 1)
+	})
+}
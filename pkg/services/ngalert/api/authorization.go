@@ -71,6 +71,14 @@ func (api *API) authorize(method, path string) web.Handler {
 		fallback = middleware.ReqSignedIn
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/backtest":
+		fallback = middleware.ReqSignedIn
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/validate/grafana/{Namespace}":
+		fallback = middleware.ReqSignedIn
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
 	// Lotex Paths
 	case http.MethodDelete + "/api/ruler/{DatasourceUID}/api/v1/rules/{Namespace}":
@@ -145,9 +153,12 @@ func (api *API) authorize(method, path string) web.Handler {
 	// Notification Policies, Contact Points and Templates
 
 	// Grafana Paths
-	case http.MethodDelete + "/api/alertmanager/grafana/config/api/v1/alerts": // reset alertmanager config to the default
+	case http.MethodDelete + "/api/alertmanager/grafana/config/api/v1/alerts", // reset alertmanager config to the default
+		http.MethodPost + "/api/alertmanager/grafana/config/history/{ID}/rollback":
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsWrite)
-	case http.MethodGet + "/api/alertmanager/grafana/config/api/v1/alerts":
+	case http.MethodGet + "/api/alertmanager/grafana/config/api/v1/alerts",
+		http.MethodGet + "/api/alertmanager/grafana/config/history",
+		http.MethodGet + "/api/alertmanager/grafana/config/history/diff":
 		fallback = middleware.ReqEditorRole
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 	case http.MethodGet + "/api/alertmanager/grafana/api/v2/status":
@@ -155,7 +166,8 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/alerts":
 		// additional authorization is done in the request handler
 		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingNotificationsWrite))
-	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/receivers/test":
+	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/receivers/test",
+		http.MethodPost + "/api/alertmanager/grafana/config/api/v1/templates/test":
 		fallback = middleware.ReqEditorRole
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 
@@ -175,36 +187,95 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodDelete + "/api/v1/ngalert/admin_config",
 		http.MethodGet + "/api/v1/ngalert/admin_config",
 		http.MethodPost + "/api/v1/ngalert/admin_config",
-		http.MethodGet + "/api/v1/ngalert/alertmanagers":
+		http.MethodGet + "/api/v1/ngalert/alertmanagers",
+		http.MethodGet + "/api/v1/ngalert/default_labels",
+		http.MethodPost + "/api/v1/ngalert/default_labels",
+		http.MethodGet + "/api/v1/ngalert/rule_lint/config",
+		http.MethodPost + "/api/v1/ngalert/rule_lint/config",
+		http.MethodGet + "/api/v1/ngalert/rule_lint/folders/{FolderUID}",
+		http.MethodPost + "/api/v1/ngalert/rule_lint/folders/{FolderUID}",
+		http.MethodDelete + "/api/v1/ngalert/rule_lint/folders/{FolderUID}":
 		return middleware.ReqOrgAdmin
+	case http.MethodPost + "/api/v1/ngalert/rule_lint":
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
 	// Grafana-only Provisioning Read Paths
 	case http.MethodGet + "/api/v1/provisioning/policies",
+		http.MethodGet + "/api/v1/provisioning/policies/effective",
+		http.MethodGet + "/api/v1/provisioning/policies/graph",
+		http.MethodGet + "/api/v1/provisioning/policies/history",
+		http.MethodGet + "/api/v1/provisioning/policies/history/{Version}",
+		http.MethodPost + "/api/v1/provisioning/policies/lint",
+		http.MethodPost + "/api/v1/provisioning/alertmanager/config/validate",
+		http.MethodGet + "/api/v1/provisioning/alertmanager/external/status",
+		http.MethodGet + "/api/v1/provisioning/policies/search",
+		http.MethodGet + "/api/v1/provisioning/policies/templates",
 		http.MethodGet + "/api/v1/provisioning/contact-points",
+		http.MethodGet + "/api/v1/provisioning/contact-points/duplicates",
 		http.MethodGet + "/api/v1/provisioning/templates",
+		http.MethodGet + "/api/v1/provisioning/templates/defaults",
 		http.MethodGet + "/api/v1/provisioning/templates/{name}",
+		http.MethodGet + "/api/v1/provisioning/templates/{name}/usage",
+		http.MethodGet + "/api/v1/provisioning/templates/export",
 		http.MethodGet + "/api/v1/provisioning/mute-timings",
 		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodGet + "/api/v1/provisioning/mute-timings/export",
+		http.MethodGet + "/api/v1/provisioning/alert-rules",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}",
-		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}":
+		http.MethodPost + "/api/v1/provisioning/alert-rules/drift",
+		http.MethodGet + "/api/v1/provisioning/rule-templates",
+		http.MethodGet + "/api/v1/provisioning/rule-templates/{name}",
+		http.MethodGet + "/api/v1/provisioning/webhooks",
+		http.MethodGet + "/api/v1/provisioning/webhooks/{UID}",
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/export":
 		fallback = middleware.ReqOrgAdmin
 		eval = ac.EvalPermission(ac.ActionAlertingProvisioningRead) // organization scope
 
 	case http.MethodPut + "/api/v1/provisioning/policies",
+		http.MethodDelete + "/api/v1/provisioning/policies",
+		http.MethodPost + "/api/v1/provisioning/policies/history/{Version}/rollback",
+		http.MethodPost + "/api/v1/provisioning/policies/{ID}/move",
+		http.MethodPost + "/api/v1/provisioning/policies/repoint",
+		http.MethodPost + "/api/v1/provisioning/policies/templates/{Name}",
 		http.MethodPost + "/api/v1/provisioning/contact-points",
 		http.MethodPut + "/api/v1/provisioning/contact-points/{UID}",
 		http.MethodDelete + "/api/v1/provisioning/contact-points/{UID}",
 		http.MethodPut + "/api/v1/provisioning/templates/{name}",
 		http.MethodDelete + "/api/v1/provisioning/templates/{name}",
+		http.MethodPost + "/api/v1/provisioning/templates/import",
+		http.MethodPost + "/api/v1/provisioning/alertmanager/import",
+		http.MethodPost + "/api/v1/provisioning/prometheus/import",
+		http.MethodPost + "/api/v1/provisioning/batch",
+		http.MethodPost + "/api/v1/provisioning/templates/{name}/rename",
 		http.MethodPost + "/api/v1/provisioning/mute-timings",
 		http.MethodPut + "/api/v1/provisioning/mute-timings/{name}",
 		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodPost + "/api/v1/provisioning/mute-timings/{name}/rename",
 		http.MethodPost + "/api/v1/provisioning/alert-rules",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/{UID}/clone",
 		http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}",
+		http.MethodPatch + "/api/v1/provisioning/alert-rules/{UID}",
 		http.MethodDelete + "/api/v1/provisioning/alert-rules/{UID}",
-		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}":
+		http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}/pause",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+		http.MethodDelete + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/order",
+		http.MethodPost + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/copy",
+		http.MethodPut + "/api/v1/provisioning/rule-templates/{name}",
+		http.MethodDelete + "/api/v1/provisioning/rule-templates/{name}",
+		http.MethodPost + "/api/v1/provisioning/rule-templates/{name}/instantiate",
+		http.MethodPost + "/api/v1/provisioning/webhooks",
+		http.MethodPut + "/api/v1/provisioning/webhooks/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/webhooks/{UID}":
 		fallback = middleware.ReqOrgAdmin
 		eval = ac.EvalPermission(ac.ActionAlertingProvisioningWrite) // organization scope
+
+	// Overriding the provenance unlocks resources provisioned otherwise, only administrators can do it.
+	case http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}/provenance",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/provenance":
+		return middleware.ReqOrgAdmin
 	}
 
 	if eval != nil {
@@ -33,3 +33,35 @@ func (f *ForkedConfigurationApi) forkRoutePostNGalertConfig(c *models.ReqContext
 func (f *ForkedConfigurationApi) forkRouteDeleteNGalertConfig(c *models.ReqContext) response.Response {
 	return f.grafana.RouteDeleteNGalertConfig(c)
 }
+
+func (f *ForkedConfigurationApi) forkRouteGetDefaultLabels(c *models.ReqContext) response.Response {
+	return f.grafana.RouteGetDefaultLabels(c)
+}
+
+func (f *ForkedConfigurationApi) forkRoutePostDefaultLabels(c *models.ReqContext, body apimodels.DefaultLabels) response.Response {
+	return f.grafana.RoutePostDefaultLabels(c, body)
+}
+
+func (f *ForkedConfigurationApi) forkRouteGetRuleLintConfig(c *models.ReqContext) response.Response {
+	return f.grafana.RouteGetRuleLintConfig(c)
+}
+
+func (f *ForkedConfigurationApi) forkRoutePostRuleLintConfig(c *models.ReqContext, body apimodels.RuleLintConfig) response.Response {
+	return f.grafana.RoutePostRuleLintConfig(c, body)
+}
+
+func (f *ForkedConfigurationApi) forkRouteGetFolderRulePolicy(c *models.ReqContext, folderUID string) response.Response {
+	return f.grafana.RouteGetFolderRulePolicy(c, folderUID)
+}
+
+func (f *ForkedConfigurationApi) forkRoutePostFolderRulePolicy(c *models.ReqContext, body apimodels.FolderRulePolicy, folderUID string) response.Response {
+	return f.grafana.RoutePostFolderRulePolicy(c, body, folderUID)
+}
+
+func (f *ForkedConfigurationApi) forkRouteDeleteFolderRulePolicy(c *models.ReqContext, folderUID string) response.Response {
+	return f.grafana.RouteDeleteFolderRulePolicy(c, folderUID)
+}
+
+func (f *ForkedConfigurationApi) forkRoutePostRuleLint(c *models.ReqContext, body apimodels.RuleLintRequest) response.Response {
+	return f.grafana.RoutePostRuleLint(c, body)
+}
@@ -183,6 +183,14 @@ func (f *ForkedAlertmanagerApi) forkRouteGetGrafanaAlertingConfig(ctx *models.Re
 	return f.GrafanaSvc.RouteGetAlertingConfig(ctx)
 }
 
+func (f *ForkedAlertmanagerApi) forkRouteGetGrafanaAlertingConfigDiff(ctx *models.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetAlertingConfigDiff(ctx)
+}
+
+func (f *ForkedAlertmanagerApi) forkRouteGetGrafanaAlertingConfigHistory(ctx *models.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetAlertingConfigHistory(ctx)
+}
+
 func (f *ForkedAlertmanagerApi) forkRouteGetGrafanaSilence(ctx *models.ReqContext, id string) response.Response {
 	return f.GrafanaSvc.RouteGetSilence(ctx, id)
 }
@@ -199,6 +207,14 @@ func (f *ForkedAlertmanagerApi) forkRoutePostGrafanaAlertingConfig(ctx *models.R
 	return f.GrafanaSvc.RoutePostAlertingConfig(ctx, conf)
 }
 
+func (f *ForkedAlertmanagerApi) forkRoutePostGrafanaAlertingConfigHistoryRollback(ctx *models.ReqContext, id string) response.Response {
+	return f.GrafanaSvc.RoutePostAlertingConfigHistoryRollback(ctx, id)
+}
+
 func (f *ForkedAlertmanagerApi) forkRoutePostTestGrafanaReceivers(ctx *models.ReqContext, conf apimodels.TestReceiversConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
+
+func (f *ForkedAlertmanagerApi) forkRoutePostTestGrafanaTemplates(ctx *models.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
+	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
+}
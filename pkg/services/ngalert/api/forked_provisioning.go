@@ -27,6 +27,58 @@ func (f *ForkedProvisioningApi) forkRoutePutPolicyTree(ctx *models.ReqContext, r
 	return f.svc.RoutePutPolicyTree(ctx, route)
 }
 
+func (f *ForkedProvisioningApi) forkRouteGetEffectivePolicies(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetEffectivePolicies(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetPoliciesByReceiver(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetPoliciesByReceiver(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetPolicyTreeGraph(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetPolicyTreeGraph(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetPolicyTreeHistory(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetPolicyTreeHistory(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetPolicyTreeVersion(ctx *models.ReqContext, version string) response.Response {
+	return f.svc.RouteGetPolicyTreeVersion(ctx, version)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostPolicyTreeRollback(ctx *models.ReqContext, version string) response.Response {
+	return f.svc.RoutePostPolicyTreeRollback(ctx, version)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostPolicyMove(ctx *models.ReqContext, move apimodels.PolicyMove, id string) response.Response {
+	return f.svc.RoutePostPolicyMove(ctx, move, id)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetPolicyTreeTemplates(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetPolicyTreeTemplates(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostPolicyTreeTemplate(ctx *models.ReqContext, values apimodels.PolicyTreeTemplateValues, name string) response.Response {
+	return f.svc.RoutePostPolicyTreeTemplate(ctx, values, name)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostPolicyRepoint(ctx *models.ReqContext, repoint apimodels.PolicyRepoint) response.Response {
+	return f.svc.RoutePostPolicyRepoint(ctx, repoint)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostPolicyTreeLint(ctx *models.ReqContext, route apimodels.Route) response.Response {
+	return f.svc.RoutePostPolicyTreeLint(ctx, route)
+}
+
+func (f *ForkedProvisioningApi) forkRouteResetPolicyTree(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteResetPolicyTree(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetContactPointDuplicates(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetContactPointDuplicates(ctx)
+}
+
 func (f *ForkedProvisioningApi) forkRouteGetContactpoints(ctx *models.ReqContext) response.Response {
 	return f.svc.RouteGetContactPoints(ctx)
 }
@@ -47,6 +99,42 @@ func (f *ForkedProvisioningApi) forkRouteGetTemplates(ctx *models.ReqContext) re
 	return f.svc.RouteGetTemplates(ctx)
 }
 
+func (f *ForkedProvisioningApi) forkRouteGetDefaultTemplates(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetDefaultTemplates(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetTemplateUsage(ctx *models.ReqContext, name string) response.Response {
+	return f.svc.RouteGetTemplateUsage(ctx, name)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostTemplateRename(ctx *models.ReqContext, rename apimodels.MessageTemplateRename, name string) response.Response {
+	return f.svc.RoutePostTemplateRename(ctx, rename, name)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetTemplatesExport(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetTemplatesExport(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostTemplatesImport(ctx *models.ReqContext, doc apimodels.TemplatesExport) response.Response {
+	return f.svc.RoutePostTemplatesImport(ctx, doc)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostAlertmanagerImport(ctx *models.ReqContext, body apimodels.AlertmanagerImport) response.Response {
+	return f.svc.RoutePostAlertmanagerImport(ctx, body)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostProvisioningBatch(ctx *models.ReqContext, body apimodels.ProvisioningBatch) response.Response {
+	return f.svc.RoutePostProvisioningBatch(ctx, body)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetExternalAlertmanagerSyncStatus(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetExternalAlertmanagerSyncStatus(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostAlertmanagerConfigValidate(ctx *models.ReqContext, body apimodels.PostableUserConfig) response.Response {
+	return f.svc.RoutePostAlertmanagerConfigValidate(ctx, body)
+}
+
 func (f *ForkedProvisioningApi) forkRouteGetTemplate(ctx *models.ReqContext, name string) response.Response {
 	return f.svc.RouteGetTemplate(ctx, name)
 }
@@ -67,6 +155,10 @@ func (f *ForkedProvisioningApi) forkRouteGetMuteTimings(ctx *models.ReqContext)
 	return f.svc.RouteGetMuteTimings(ctx)
 }
 
+func (f *ForkedProvisioningApi) forkRouteGetMuteTimingsExport(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetMuteTimingsExport(ctx)
+}
+
 func (f *ForkedProvisioningApi) forkRoutePostMuteTiming(ctx *models.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
 	return f.svc.RoutePostMuteTiming(ctx, mt)
 }
@@ -79,18 +171,42 @@ func (f *ForkedProvisioningApi) forkRouteDeleteMuteTiming(ctx *models.ReqContext
 	return f.svc.RouteDeleteMuteTiming(ctx, name)
 }
 
+func (f *ForkedProvisioningApi) forkRoutePostMuteTimingRename(ctx *models.ReqContext, rename apimodels.MuteTimingRename, name string) response.Response {
+	return f.svc.RoutePostMuteTimingRename(ctx, rename, name)
+}
+
 func (f *ForkedProvisioningApi) forkRouteGetAlertRule(ctx *models.ReqContext, UID string) response.Response {
 	return f.svc.RouteRouteGetAlertRule(ctx, UID)
 }
 
+func (f *ForkedProvisioningApi) forkRouteGetAlertRules(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRules(ctx)
+}
+
 func (f *ForkedProvisioningApi) forkRoutePostAlertRule(ctx *models.ReqContext, ar apimodels.AlertRule) response.Response {
 	return f.svc.RoutePostAlertRule(ctx, ar)
 }
 
+func (f *ForkedProvisioningApi) forkRoutePatchAlertRule(ctx *models.ReqContext, patch apimodels.AlertRulePatch, UID string) response.Response {
+	return f.svc.RoutePatchAlertRule(ctx, patch, UID)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostAlertRuleClone(ctx *models.ReqContext, clone apimodels.AlertRuleClone, UID string) response.Response {
+	return f.svc.RoutePostAlertRuleClone(ctx, clone, UID)
+}
+
 func (f *ForkedProvisioningApi) forkRoutePutAlertRule(ctx *models.ReqContext, ar apimodels.AlertRule, UID string) response.Response {
 	return f.svc.RoutePutAlertRule(ctx, ar, UID)
 }
 
+func (f *ForkedProvisioningApi) forkRoutePutAlertRulePause(ctx *models.ReqContext, pause apimodels.AlertRulePause, UID string) response.Response {
+	return f.svc.RoutePutAlertRulePause(ctx, pause, UID)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePutAlertRuleProvenance(ctx *models.ReqContext, provenance apimodels.AlertRuleProvenance, UID string) response.Response {
+	return f.svc.RoutePutAlertRuleProvenance(ctx, provenance, UID)
+}
+
 func (f *ForkedProvisioningApi) forkRouteDeleteAlertRule(ctx *models.ReqContext, UID string) response.Response {
 	return f.svc.RouteDeleteAlertRule(ctx, UID)
 }
@@ -99,6 +215,78 @@ func (f *ForkedProvisioningApi) forkRouteGetAlertRuleGroup(ctx *models.ReqContex
 	return f.svc.RouteGetAlertRuleGroup(ctx, folder, group)
 }
 
+func (f *ForkedProvisioningApi) forkRouteGetAlertRuleGroupsExport(ctx *models.ReqContext, folder string) response.Response {
+	return f.svc.RouteGetAlertRuleGroupsExport(ctx, folder)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostAlertRuleGroupCopy(ctx *models.ReqContext, cp apimodels.AlertRuleGroupCopy, folderUID string, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupCopy(ctx, cp, folderUID, group)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostAlertRulesDrift(ctx *models.ReqContext, document apimodels.AlertRulesExport) response.Response {
+	return f.svc.RoutePostAlertRulesDrift(ctx, document)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostAlertRulesProvenance(ctx *models.ReqContext, provenance apimodels.AlertRulesProvenance) response.Response {
+	return f.svc.RoutePostAlertRulesProvenance(ctx, provenance)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetRuleTemplates(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetRuleTemplates(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetRuleTemplate(ctx *models.ReqContext, name string) response.Response {
+	return f.svc.RouteGetRuleTemplate(ctx, name)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePutRuleTemplate(ctx *models.ReqContext, tmpl apimodels.RuleTemplate, name string) response.Response {
+	return f.svc.RoutePutRuleTemplate(ctx, tmpl, name)
+}
+
+func (f *ForkedProvisioningApi) forkRouteDeleteRuleTemplate(ctx *models.ReqContext, name string) response.Response {
+	return f.svc.RouteDeleteRuleTemplate(ctx, name)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostPrometheusRulesImport(ctx *models.ReqContext, body apimodels.PrometheusRulesImport) response.Response {
+	return f.svc.RoutePostPrometheusRulesImport(ctx, body)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostRuleTemplateInstantiate(ctx *models.ReqContext, instance apimodels.RuleTemplateInstance, name string) response.Response {
+	return f.svc.RoutePostRuleTemplateInstantiate(ctx, instance, name)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetProvisioningWebhooks(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetProvisioningWebhooks(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetProvisioningWebhook(ctx *models.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetProvisioningWebhook(ctx, uid)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostProvisioningWebhook(ctx *models.ReqContext, webhook apimodels.ProvisioningWebhook) response.Response {
+	return f.svc.RoutePostProvisioningWebhook(ctx, webhook)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePutProvisioningWebhook(ctx *models.ReqContext, webhook apimodels.ProvisioningWebhook, uid string) response.Response {
+	return f.svc.RoutePutProvisioningWebhook(ctx, webhook, uid)
+}
+
+func (f *ForkedProvisioningApi) forkRouteDeleteProvisioningWebhook(ctx *models.ReqContext, uid string) response.Response {
+	return f.svc.RouteDeleteProvisioningWebhook(ctx, uid)
+}
+
 func (f *ForkedProvisioningApi) forkRoutePutAlertRuleGroup(ctx *models.ReqContext, ag apimodels.AlertRuleGroupMetadata, folder, group string) response.Response {
 	return f.svc.RoutePutAlertRuleGroup(ctx, ag, folder, group)
 }
+
+func (f *ForkedProvisioningApi) forkRouteDeleteAlertRuleGroup(ctx *models.ReqContext, folderUID string, group string) response.Response {
+	return f.svc.RouteDeleteAlertRuleGroup(ctx, folderUID, group)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePutAlertRuleGroupPause(ctx *models.ReqContext, pause apimodels.AlertRulePause, folder, group string) response.Response {
+	return f.svc.RoutePutAlertRuleGroupPause(ctx, pause, folder, group)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePutAlertRuleGroupOrder(ctx *models.ReqContext, order apimodels.AlertRuleGroupOrder, folder, group string) response.Response {
+	return f.svc.RoutePutAlertRuleGroupOrder(ctx, order, folder, group)
+}
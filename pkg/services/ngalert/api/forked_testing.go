@@ -26,6 +26,14 @@ func (f *ForkedTestingApi) forkRouteTestRuleGrafanaConfig(c *models.ReqContext,
 	return f.svc.RouteTestGrafanaRuleConfig(c, body)
 }
 
+func (f *ForkedTestingApi) forkRouteBacktestRule(c *models.ReqContext, body apimodels.BacktestPayload) response.Response {
+	return f.svc.RouteBacktestRule(c, body)
+}
+
 func (f *ForkedTestingApi) forkRouteEvalQueries(c *models.ReqContext, body apimodels.EvalQueriesPayload) response.Response {
 	return f.svc.RouteEvalQueries(c, body)
 }
+
+func (f *ForkedTestingApi) forkRouteValidateGrafanaRuleGroup(c *models.ReqContext, body apimodels.PostableRuleGroupConfig, namespace string) response.Response {
+	return f.svc.RouteValidateGrafanaRuleGroup(c, body, namespace)
+}
@@ -33,6 +33,8 @@ type AlertmanagerApiForkingService interface {
 	RouteGetGrafanaAMAlerts(*models.ReqContext) response.Response
 	RouteGetGrafanaAMStatus(*models.ReqContext) response.Response
 	RouteGetGrafanaAlertingConfig(*models.ReqContext) response.Response
+	RouteGetGrafanaAlertingConfigDiff(*models.ReqContext) response.Response
+	RouteGetGrafanaAlertingConfigHistory(*models.ReqContext) response.Response
 	RouteGetGrafanaSilence(*models.ReqContext) response.Response
 	RouteGetGrafanaSilences(*models.ReqContext) response.Response
 	RouteGetSilence(*models.ReqContext) response.Response
@@ -41,7 +43,9 @@ type AlertmanagerApiForkingService interface {
 	RoutePostAlertingConfig(*models.ReqContext) response.Response
 	RoutePostGrafanaAMAlerts(*models.ReqContext) response.Response
 	RoutePostGrafanaAlertingConfig(*models.ReqContext) response.Response
+	RoutePostGrafanaAlertingConfigHistoryRollback(*models.ReqContext) response.Response
 	RoutePostTestGrafanaReceivers(*models.ReqContext) response.Response
+	RoutePostTestGrafanaTemplates(*models.ReqContext) response.Response
 	RoutePostTestReceivers(*models.ReqContext) response.Response
 }
 
@@ -104,6 +108,12 @@ func (f *ForkedAlertmanagerApi) RouteGetGrafanaAMStatus(ctx *models.ReqContext)
 func (f *ForkedAlertmanagerApi) RouteGetGrafanaAlertingConfig(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetGrafanaAlertingConfig(ctx)
 }
+func (f *ForkedAlertmanagerApi) RouteGetGrafanaAlertingConfigDiff(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetGrafanaAlertingConfigDiff(ctx)
+}
+func (f *ForkedAlertmanagerApi) RouteGetGrafanaAlertingConfigHistory(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetGrafanaAlertingConfigHistory(ctx)
+}
 func (f *ForkedAlertmanagerApi) RouteGetGrafanaSilence(ctx *models.ReqContext) response.Response {
 	silenceIdParam := web.Params(ctx.Req)[":SilenceId"]
 	return f.forkRouteGetGrafanaSilence(ctx, silenceIdParam)
@@ -150,6 +160,10 @@ func (f *ForkedAlertmanagerApi) RoutePostGrafanaAlertingConfig(ctx *models.ReqCo
 	}
 	return f.forkRoutePostGrafanaAlertingConfig(ctx, conf)
 }
+func (f *ForkedAlertmanagerApi) RoutePostGrafanaAlertingConfigHistoryRollback(ctx *models.ReqContext) response.Response {
+	iDParam := web.Params(ctx.Req)[":ID"]
+	return f.forkRoutePostGrafanaAlertingConfigHistoryRollback(ctx, iDParam)
+}
 func (f *ForkedAlertmanagerApi) RoutePostTestGrafanaReceivers(ctx *models.ReqContext) response.Response {
 	conf := apimodels.TestReceiversConfigBodyParams{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -157,6 +171,13 @@ func (f *ForkedAlertmanagerApi) RoutePostTestGrafanaReceivers(ctx *models.ReqCon
 	}
 	return f.forkRoutePostTestGrafanaReceivers(ctx, conf)
 }
+func (f *ForkedAlertmanagerApi) RoutePostTestGrafanaTemplates(ctx *models.ReqContext) response.Response {
+	conf := apimodels.TestTemplatesConfigBodyParams{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostTestGrafanaTemplates(ctx, conf)
+}
 func (f *ForkedAlertmanagerApi) RoutePostTestReceivers(ctx *models.ReqContext) response.Response {
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
 	conf := apimodels.TestReceiversConfigBodyParams{}
@@ -308,6 +329,26 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/config/history/diff"),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/config/history/diff"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/config/history/diff",
+				srv.RouteGetGrafanaAlertingConfigDiff,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/config/history"),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/config/history"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/config/history",
+				srv.RouteGetGrafanaAlertingConfigHistory,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/alertmanager/grafana/api/v2/silence/{SilenceId}"),
 			api.authorize(http.MethodGet, "/api/alertmanager/grafana/api/v2/silence/{SilenceId}"),
@@ -388,6 +429,16 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApiForkingServi
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/alertmanager/grafana/config/history/{ID}/rollback"),
+			api.authorize(http.MethodPost, "/api/alertmanager/grafana/config/history/{ID}/rollback"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/alertmanager/grafana/config/history/{ID}/rollback",
+				srv.RoutePostGrafanaAlertingConfigHistoryRollback,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/alertmanager/grafana/config/api/v1/receivers/test"),
 			api.authorize(http.MethodPost, "/api/alertmanager/grafana/config/api/v1/receivers/test"),
@@ -398,6 +449,16 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApiForkingServi
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/alertmanager/grafana/config/api/v1/templates/test"),
+			api.authorize(http.MethodPost, "/api/alertmanager/grafana/config/api/v1/templates/test"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/alertmanager/grafana/config/api/v1/templates/test",
+				srv.RoutePostTestGrafanaTemplates,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/alertmanager/{DatasourceUID}/config/api/v1/receivers/test"),
 			api.authorize(http.MethodPost, "/api/alertmanager/{DatasourceUID}/config/api/v1/receivers/test"),
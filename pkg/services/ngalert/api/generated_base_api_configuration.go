@@ -19,21 +19,58 @@ import (
 )
 
 type ConfigurationApiForkingService interface {
+	RouteDeleteFolderRulePolicy(*models.ReqContext) response.Response
 	RouteDeleteNGalertConfig(*models.ReqContext) response.Response
 	RouteGetAlertmanagers(*models.ReqContext) response.Response
+	RouteGetDefaultLabels(*models.ReqContext) response.Response
+	RouteGetFolderRulePolicy(*models.ReqContext) response.Response
 	RouteGetNGalertConfig(*models.ReqContext) response.Response
+	RouteGetRuleLintConfig(*models.ReqContext) response.Response
+	RoutePostDefaultLabels(*models.ReqContext) response.Response
+	RoutePostFolderRulePolicy(*models.ReqContext) response.Response
 	RoutePostNGalertConfig(*models.ReqContext) response.Response
+	RoutePostRuleLint(*models.ReqContext) response.Response
+	RoutePostRuleLintConfig(*models.ReqContext) response.Response
 }
 
+func (f *ForkedConfigurationApi) RouteDeleteFolderRulePolicy(ctx *models.ReqContext) response.Response {
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	return f.forkRouteDeleteFolderRulePolicy(ctx, folderUIDParam)
+}
 func (f *ForkedConfigurationApi) RouteDeleteNGalertConfig(ctx *models.ReqContext) response.Response {
 	return f.forkRouteDeleteNGalertConfig(ctx)
 }
 func (f *ForkedConfigurationApi) RouteGetAlertmanagers(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetAlertmanagers(ctx)
 }
+func (f *ForkedConfigurationApi) RouteGetDefaultLabels(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetDefaultLabels(ctx)
+}
+func (f *ForkedConfigurationApi) RouteGetFolderRulePolicy(ctx *models.ReqContext) response.Response {
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	return f.forkRouteGetFolderRulePolicy(ctx, folderUIDParam)
+}
 func (f *ForkedConfigurationApi) RouteGetNGalertConfig(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetNGalertConfig(ctx)
 }
+func (f *ForkedConfigurationApi) RouteGetRuleLintConfig(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetRuleLintConfig(ctx)
+}
+func (f *ForkedConfigurationApi) RoutePostDefaultLabels(ctx *models.ReqContext) response.Response {
+	conf := apimodels.DefaultLabels{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostDefaultLabels(ctx, conf)
+}
+func (f *ForkedConfigurationApi) RoutePostFolderRulePolicy(ctx *models.ReqContext) response.Response {
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	conf := apimodels.FolderRulePolicy{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostFolderRulePolicy(ctx, conf, folderUIDParam)
+}
 func (f *ForkedConfigurationApi) RoutePostNGalertConfig(ctx *models.ReqContext) response.Response {
 	conf := apimodels.PostableNGalertConfig{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -41,9 +78,33 @@ func (f *ForkedConfigurationApi) RoutePostNGalertConfig(ctx *models.ReqContext)
 	}
 	return f.forkRoutePostNGalertConfig(ctx, conf)
 }
+func (f *ForkedConfigurationApi) RoutePostRuleLint(ctx *models.ReqContext) response.Response {
+	conf := apimodels.RuleLintRequest{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostRuleLint(ctx, conf)
+}
+func (f *ForkedConfigurationApi) RoutePostRuleLintConfig(ctx *models.ReqContext) response.Response {
+	conf := apimodels.RuleLintConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostRuleLintConfig(ctx, conf)
+}
 
 func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApiForkingService, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Delete(
+			toMacaronPath("/api/v1/ngalert/rule_lint/folders/{FolderUID}"),
+			api.authorize(http.MethodDelete, "/api/v1/ngalert/rule_lint/folders/{FolderUID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/ngalert/rule_lint/folders/{FolderUID}",
+				srv.RouteDeleteFolderRulePolicy,
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			api.authorize(http.MethodDelete, "/api/v1/ngalert/admin_config"),
@@ -64,6 +125,26 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApiForkingSer
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/default_labels"),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/default_labels"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/default_labels",
+				srv.RouteGetDefaultLabels,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/rule_lint/folders/{FolderUID}"),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/rule_lint/folders/{FolderUID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/rule_lint/folders/{FolderUID}",
+				srv.RouteGetFolderRulePolicy,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			api.authorize(http.MethodGet, "/api/v1/ngalert/admin_config"),
@@ -74,6 +155,36 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApiForkingSer
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/rule_lint/config"),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/rule_lint/config"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/rule_lint/config",
+				srv.RouteGetRuleLintConfig,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/default_labels"),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/default_labels"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/default_labels",
+				srv.RoutePostDefaultLabels,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rule_lint/folders/{FolderUID}"),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/rule_lint/folders/{FolderUID}"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rule_lint/folders/{FolderUID}",
+				srv.RoutePostFolderRulePolicy,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			api.authorize(http.MethodPost, "/api/v1/ngalert/admin_config"),
@@ -84,5 +195,25 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApiForkingSer
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rule_lint"),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/rule_lint"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rule_lint",
+				srv.RoutePostRuleLint,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rule_lint/config"),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/rule_lint/config"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rule_lint/config",
+				srv.RoutePostRuleLintConfig,
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
@@ -20,32 +20,84 @@ import (
 
 type ProvisioningApiForkingService interface {
 	RouteDeleteAlertRule(*models.ReqContext) response.Response
+	RouteDeleteAlertRuleGroup(*models.ReqContext) response.Response
 	RouteDeleteContactpoints(*models.ReqContext) response.Response
 	RouteDeleteMuteTiming(*models.ReqContext) response.Response
+	RouteDeleteProvisioningWebhook(*models.ReqContext) response.Response
+	RouteDeleteRuleTemplate(*models.ReqContext) response.Response
 	RouteDeleteTemplate(*models.ReqContext) response.Response
 	RouteGetAlertRule(*models.ReqContext) response.Response
 	RouteGetAlertRuleGroup(*models.ReqContext) response.Response
+	RouteGetAlertRuleGroupsExport(*models.ReqContext) response.Response
+	RouteGetAlertRules(*models.ReqContext) response.Response
+	RouteGetContactPointDuplicates(*models.ReqContext) response.Response
 	RouteGetContactpoints(*models.ReqContext) response.Response
+	RouteGetDefaultTemplates(*models.ReqContext) response.Response
+	RouteGetEffectivePolicies(*models.ReqContext) response.Response
+	RouteGetExternalAlertmanagerSyncStatus(*models.ReqContext) response.Response
 	RouteGetMuteTiming(*models.ReqContext) response.Response
 	RouteGetMuteTimings(*models.ReqContext) response.Response
+	RouteGetMuteTimingsExport(*models.ReqContext) response.Response
+	RouteGetPoliciesByReceiver(*models.ReqContext) response.Response
 	RouteGetPolicyTree(*models.ReqContext) response.Response
+	RouteGetPolicyTreeGraph(*models.ReqContext) response.Response
+	RouteGetPolicyTreeHistory(*models.ReqContext) response.Response
+	RouteGetPolicyTreeTemplates(*models.ReqContext) response.Response
+	RouteGetPolicyTreeVersion(*models.ReqContext) response.Response
+	RouteGetProvisioningWebhook(*models.ReqContext) response.Response
+	RouteGetProvisioningWebhooks(*models.ReqContext) response.Response
+	RouteGetRuleTemplate(*models.ReqContext) response.Response
+	RouteGetRuleTemplates(*models.ReqContext) response.Response
 	RouteGetTemplate(*models.ReqContext) response.Response
+	RouteGetTemplateUsage(*models.ReqContext) response.Response
 	RouteGetTemplates(*models.ReqContext) response.Response
+	RouteGetTemplatesExport(*models.ReqContext) response.Response
+	RoutePatchAlertRule(*models.ReqContext) response.Response
 	RoutePostAlertRule(*models.ReqContext) response.Response
+	RoutePostAlertRuleClone(*models.ReqContext) response.Response
+	RoutePostAlertmanagerConfigValidate(*models.ReqContext) response.Response
+	RoutePostAlertRuleGroupCopy(*models.ReqContext) response.Response
+	RoutePostAlertRulesDrift(*models.ReqContext) response.Response
+	RoutePostAlertRulesProvenance(*models.ReqContext) response.Response
+	RoutePostAlertmanagerImport(*models.ReqContext) response.Response
 	RoutePostContactpoints(*models.ReqContext) response.Response
+	RoutePostProvisioningBatch(*models.ReqContext) response.Response
 	RoutePostMuteTiming(*models.ReqContext) response.Response
+	RoutePostMuteTimingRename(*models.ReqContext) response.Response
+	RoutePostPolicyMove(*models.ReqContext) response.Response
+	RoutePostPolicyRepoint(*models.ReqContext) response.Response
+	RoutePostPolicyTreeLint(*models.ReqContext) response.Response
+	RoutePostPolicyTreeRollback(*models.ReqContext) response.Response
+	RoutePostPolicyTreeTemplate(*models.ReqContext) response.Response
+	RoutePostPrometheusRulesImport(*models.ReqContext) response.Response
+	RoutePostProvisioningWebhook(*models.ReqContext) response.Response
+	RoutePostRuleTemplateInstantiate(*models.ReqContext) response.Response
+	RoutePostTemplateRename(*models.ReqContext) response.Response
+	RoutePostTemplatesImport(*models.ReqContext) response.Response
 	RoutePutAlertRule(*models.ReqContext) response.Response
 	RoutePutAlertRuleGroup(*models.ReqContext) response.Response
+	RoutePutAlertRuleGroupOrder(*models.ReqContext) response.Response
+	RoutePutAlertRuleGroupPause(*models.ReqContext) response.Response
+	RoutePutAlertRulePause(*models.ReqContext) response.Response
+	RoutePutAlertRuleProvenance(*models.ReqContext) response.Response
 	RoutePutContactpoint(*models.ReqContext) response.Response
 	RoutePutMuteTiming(*models.ReqContext) response.Response
 	RoutePutPolicyTree(*models.ReqContext) response.Response
+	RoutePutProvisioningWebhook(*models.ReqContext) response.Response
+	RoutePutRuleTemplate(*models.ReqContext) response.Response
 	RoutePutTemplate(*models.ReqContext) response.Response
+	RouteResetPolicyTree(*models.ReqContext) response.Response
 }
 
 func (f *ForkedProvisioningApi) RouteDeleteAlertRule(ctx *models.ReqContext) response.Response {
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	return f.forkRouteDeleteAlertRule(ctx, uIDParam)
 }
+func (f *ForkedProvisioningApi) RouteDeleteAlertRuleGroup(ctx *models.ReqContext) response.Response {
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	return f.forkRouteDeleteAlertRuleGroup(ctx, folderUIDParam, groupParam)
+}
 func (f *ForkedProvisioningApi) RouteDeleteContactpoints(ctx *models.ReqContext) response.Response {
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	return f.forkRouteDeleteContactpoints(ctx, uIDParam)
@@ -54,6 +106,14 @@ func (f *ForkedProvisioningApi) RouteDeleteMuteTiming(ctx *models.ReqContext) re
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.forkRouteDeleteMuteTiming(ctx, nameParam)
 }
+func (f *ForkedProvisioningApi) RouteDeleteProvisioningWebhook(ctx *models.ReqContext) response.Response {
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.forkRouteDeleteProvisioningWebhook(ctx, uIDParam)
+}
+func (f *ForkedProvisioningApi) RouteDeleteRuleTemplate(ctx *models.ReqContext) response.Response {
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.forkRouteDeleteRuleTemplate(ctx, nameParam)
+}
 func (f *ForkedProvisioningApi) RouteDeleteTemplate(ctx *models.ReqContext) response.Response {
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.forkRouteDeleteTemplate(ctx, nameParam)
@@ -67,9 +127,28 @@ func (f *ForkedProvisioningApi) RouteGetAlertRuleGroup(ctx *models.ReqContext) r
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.forkRouteGetAlertRuleGroup(ctx, folderUIDParam, groupParam)
 }
+func (f *ForkedProvisioningApi) RouteGetAlertRuleGroupsExport(ctx *models.ReqContext) response.Response {
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	return f.forkRouteGetAlertRuleGroupsExport(ctx, folderUIDParam)
+}
+func (f *ForkedProvisioningApi) RouteGetAlertRules(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetAlertRules(ctx)
+}
+func (f *ForkedProvisioningApi) RouteGetContactPointDuplicates(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetContactPointDuplicates(ctx)
+}
 func (f *ForkedProvisioningApi) RouteGetContactpoints(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetContactpoints(ctx)
 }
+func (f *ForkedProvisioningApi) RouteGetDefaultTemplates(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetDefaultTemplates(ctx)
+}
+func (f *ForkedProvisioningApi) RouteGetEffectivePolicies(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetEffectivePolicies(ctx)
+}
+func (f *ForkedProvisioningApi) RouteGetExternalAlertmanagerSyncStatus(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetExternalAlertmanagerSyncStatus(ctx)
+}
 func (f *ForkedProvisioningApi) RouteGetMuteTiming(ctx *models.ReqContext) response.Response {
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.forkRouteGetMuteTiming(ctx, nameParam)
@@ -77,16 +156,64 @@ func (f *ForkedProvisioningApi) RouteGetMuteTiming(ctx *models.ReqContext) respo
 func (f *ForkedProvisioningApi) RouteGetMuteTimings(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetMuteTimings(ctx)
 }
+func (f *ForkedProvisioningApi) RouteGetMuteTimingsExport(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetMuteTimingsExport(ctx)
+}
+func (f *ForkedProvisioningApi) RouteGetPoliciesByReceiver(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetPoliciesByReceiver(ctx)
+}
 func (f *ForkedProvisioningApi) RouteGetPolicyTree(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetPolicyTree(ctx)
 }
+func (f *ForkedProvisioningApi) RouteGetPolicyTreeGraph(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetPolicyTreeGraph(ctx)
+}
+func (f *ForkedProvisioningApi) RouteGetPolicyTreeHistory(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetPolicyTreeHistory(ctx)
+}
+func (f *ForkedProvisioningApi) RouteGetPolicyTreeTemplates(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetPolicyTreeTemplates(ctx)
+}
+func (f *ForkedProvisioningApi) RouteGetPolicyTreeVersion(ctx *models.ReqContext) response.Response {
+	versionParam := web.Params(ctx.Req)[":Version"]
+	return f.forkRouteGetPolicyTreeVersion(ctx, versionParam)
+}
+func (f *ForkedProvisioningApi) RouteGetProvisioningWebhook(ctx *models.ReqContext) response.Response {
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.forkRouteGetProvisioningWebhook(ctx, uIDParam)
+}
+func (f *ForkedProvisioningApi) RouteGetProvisioningWebhooks(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetProvisioningWebhooks(ctx)
+}
+func (f *ForkedProvisioningApi) RouteGetRuleTemplate(ctx *models.ReqContext) response.Response {
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.forkRouteGetRuleTemplate(ctx, nameParam)
+}
+func (f *ForkedProvisioningApi) RouteGetRuleTemplates(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetRuleTemplates(ctx)
+}
 func (f *ForkedProvisioningApi) RouteGetTemplate(ctx *models.ReqContext) response.Response {
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.forkRouteGetTemplate(ctx, nameParam)
 }
+func (f *ForkedProvisioningApi) RouteGetTemplateUsage(ctx *models.ReqContext) response.Response {
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.forkRouteGetTemplateUsage(ctx, nameParam)
+}
 func (f *ForkedProvisioningApi) RouteGetTemplates(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetTemplates(ctx)
 }
+func (f *ForkedProvisioningApi) RouteGetTemplatesExport(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetTemplatesExport(ctx)
+}
+func (f *ForkedProvisioningApi) RoutePatchAlertRule(ctx *models.ReqContext) response.Response {
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	conf := apimodels.AlertRulePatch{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePatchAlertRule(ctx, conf, uIDParam)
+}
 func (f *ForkedProvisioningApi) RoutePostAlertRule(ctx *models.ReqContext) response.Response {
 	conf := apimodels.AlertRule{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -94,6 +221,58 @@ func (f *ForkedProvisioningApi) RoutePostAlertRule(ctx *models.ReqContext) respo
 	}
 	return f.forkRoutePostAlertRule(ctx, conf)
 }
+func (f *ForkedProvisioningApi) RoutePostAlertRuleClone(ctx *models.ReqContext) response.Response {
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	conf := apimodels.AlertRuleClone{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostAlertRuleClone(ctx, conf, uIDParam)
+}
+func (f *ForkedProvisioningApi) RoutePostAlertRuleGroupCopy(ctx *models.ReqContext) response.Response {
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	conf := apimodels.AlertRuleGroupCopy{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostAlertRuleGroupCopy(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ForkedProvisioningApi) RoutePostAlertRulesDrift(ctx *models.ReqContext) response.Response {
+	conf := apimodels.AlertRulesExport{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostAlertRulesDrift(ctx, conf)
+}
+func (f *ForkedProvisioningApi) RoutePostAlertRulesProvenance(ctx *models.ReqContext) response.Response {
+	conf := apimodels.AlertRulesProvenance{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostAlertRulesProvenance(ctx, conf)
+}
+func (f *ForkedProvisioningApi) RoutePostAlertmanagerConfigValidate(ctx *models.ReqContext) response.Response {
+	conf := apimodels.PostableUserConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostAlertmanagerConfigValidate(ctx, conf)
+}
+func (f *ForkedProvisioningApi) RoutePostProvisioningBatch(ctx *models.ReqContext) response.Response {
+	conf := apimodels.ProvisioningBatch{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostProvisioningBatch(ctx, conf)
+}
+func (f *ForkedProvisioningApi) RoutePostAlertmanagerImport(ctx *models.ReqContext) response.Response {
+	conf := apimodels.AlertmanagerImport{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostAlertmanagerImport(ctx, conf)
+}
 func (f *ForkedProvisioningApi) RoutePostContactpoints(ctx *models.ReqContext) response.Response {
 	conf := apimodels.EmbeddedContactPoint{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -108,6 +287,85 @@ func (f *ForkedProvisioningApi) RoutePostMuteTiming(ctx *models.ReqContext) resp
 	}
 	return f.forkRoutePostMuteTiming(ctx, conf)
 }
+func (f *ForkedProvisioningApi) RoutePostMuteTimingRename(ctx *models.ReqContext) response.Response {
+	nameParam := web.Params(ctx.Req)[":name"]
+	conf := apimodels.MuteTimingRename{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostMuteTimingRename(ctx, conf, nameParam)
+}
+func (f *ForkedProvisioningApi) RoutePostPolicyMove(ctx *models.ReqContext) response.Response {
+	iDParam := web.Params(ctx.Req)[":ID"]
+	conf := apimodels.PolicyMove{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostPolicyMove(ctx, conf, iDParam)
+}
+func (f *ForkedProvisioningApi) RoutePostPolicyRepoint(ctx *models.ReqContext) response.Response {
+	conf := apimodels.PolicyRepoint{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostPolicyRepoint(ctx, conf)
+}
+func (f *ForkedProvisioningApi) RoutePostPolicyTreeLint(ctx *models.ReqContext) response.Response {
+	conf := apimodels.Route{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostPolicyTreeLint(ctx, conf)
+}
+func (f *ForkedProvisioningApi) RoutePostPolicyTreeRollback(ctx *models.ReqContext) response.Response {
+	versionParam := web.Params(ctx.Req)[":Version"]
+	return f.forkRoutePostPolicyTreeRollback(ctx, versionParam)
+}
+func (f *ForkedProvisioningApi) RoutePostPolicyTreeTemplate(ctx *models.ReqContext) response.Response {
+	nameParam := web.Params(ctx.Req)[":Name"]
+	conf := apimodels.PolicyTreeTemplateValues{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostPolicyTreeTemplate(ctx, conf, nameParam)
+}
+func (f *ForkedProvisioningApi) RoutePostPrometheusRulesImport(ctx *models.ReqContext) response.Response {
+	conf := apimodels.PrometheusRulesImport{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostPrometheusRulesImport(ctx, conf)
+}
+func (f *ForkedProvisioningApi) RoutePostProvisioningWebhook(ctx *models.ReqContext) response.Response {
+	conf := apimodels.ProvisioningWebhook{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostProvisioningWebhook(ctx, conf)
+}
+func (f *ForkedProvisioningApi) RoutePostRuleTemplateInstantiate(ctx *models.ReqContext) response.Response {
+	nameParam := web.Params(ctx.Req)[":name"]
+	conf := apimodels.RuleTemplateInstance{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostRuleTemplateInstantiate(ctx, conf, nameParam)
+}
+func (f *ForkedProvisioningApi) RoutePostTemplateRename(ctx *models.ReqContext) response.Response {
+	nameParam := web.Params(ctx.Req)[":name"]
+	conf := apimodels.MessageTemplateRename{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostTemplateRename(ctx, conf, nameParam)
+}
+func (f *ForkedProvisioningApi) RoutePostTemplatesImport(ctx *models.ReqContext) response.Response {
+	conf := apimodels.TemplatesExport{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostTemplatesImport(ctx, conf)
+}
 func (f *ForkedProvisioningApi) RoutePutAlertRule(ctx *models.ReqContext) response.Response {
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	conf := apimodels.AlertRule{}
@@ -125,6 +383,40 @@ func (f *ForkedProvisioningApi) RoutePutAlertRuleGroup(ctx *models.ReqContext) r
 	}
 	return f.forkRoutePutAlertRuleGroup(ctx, conf, folderUIDParam, groupParam)
 }
+func (f *ForkedProvisioningApi) RoutePutAlertRuleGroupOrder(ctx *models.ReqContext) response.Response {
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	conf := apimodels.AlertRuleGroupOrder{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutAlertRuleGroupOrder(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ForkedProvisioningApi) RoutePutAlertRuleGroupPause(ctx *models.ReqContext) response.Response {
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	conf := apimodels.AlertRulePause{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutAlertRuleGroupPause(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ForkedProvisioningApi) RoutePutAlertRulePause(ctx *models.ReqContext) response.Response {
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	conf := apimodels.AlertRulePause{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutAlertRulePause(ctx, conf, uIDParam)
+}
+func (f *ForkedProvisioningApi) RoutePutAlertRuleProvenance(ctx *models.ReqContext) response.Response {
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	conf := apimodels.AlertRuleProvenance{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutAlertRuleProvenance(ctx, conf, uIDParam)
+}
 func (f *ForkedProvisioningApi) RoutePutContactpoint(ctx *models.ReqContext) response.Response {
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	conf := apimodels.EmbeddedContactPoint{}
@@ -148,6 +440,22 @@ func (f *ForkedProvisioningApi) RoutePutPolicyTree(ctx *models.ReqContext) respo
 	}
 	return f.forkRoutePutPolicyTree(ctx, conf)
 }
+func (f *ForkedProvisioningApi) RoutePutProvisioningWebhook(ctx *models.ReqContext) response.Response {
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	conf := apimodels.ProvisioningWebhook{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutProvisioningWebhook(ctx, conf, uIDParam)
+}
+func (f *ForkedProvisioningApi) RoutePutRuleTemplate(ctx *models.ReqContext) response.Response {
+	nameParam := web.Params(ctx.Req)[":name"]
+	conf := apimodels.RuleTemplate{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutRuleTemplate(ctx, conf, nameParam)
+}
 func (f *ForkedProvisioningApi) RoutePutTemplate(ctx *models.ReqContext) response.Response {
 	nameParam := web.Params(ctx.Req)[":name"]
 	conf := apimodels.MessageTemplateContent{}
@@ -156,6 +464,9 @@ func (f *ForkedProvisioningApi) RoutePutTemplate(ctx *models.ReqContext) respons
 	}
 	return f.forkRoutePutTemplate(ctx, conf, nameParam)
 }
+func (f *ForkedProvisioningApi) RouteResetPolicyTree(ctx *models.ReqContext) response.Response {
+	return f.forkRouteResetPolicyTree(ctx)
+}
 
 func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingService, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
@@ -169,6 +480,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}"),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+				srv.RouteDeleteAlertRuleGroup,
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			api.authorize(http.MethodDelete, "/api/v1/provisioning/contact-points/{UID}"),
@@ -189,6 +510,36 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/policies"),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/policies"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/policies",
+				srv.RouteResetPolicyTree,
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/webhooks/{UID}"),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/webhooks/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/webhooks/{UID}",
+				srv.RouteDeleteProvisioningWebhook,
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{name}"),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/rule-templates/{name}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/rule-templates/{name}",
+				srv.RouteDeleteRuleTemplate,
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			api.authorize(http.MethodDelete, "/api/v1/provisioning/templates/{name}"),
@@ -219,6 +570,36 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/folder/{FolderUID}/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/folder/{FolderUID}/export",
+				srv.RouteGetAlertRuleGroupsExport,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules",
+				srv.RouteGetAlertRules,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/contact-points/duplicates"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points/duplicates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/contact-points/duplicates",
+				srv.RouteGetContactPointDuplicates,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points"),
@@ -229,6 +610,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/templates/defaults"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/defaults"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/templates/defaults",
+				srv.RouteGetDefaultTemplates,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/{name}"),
@@ -249,6 +640,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/mute-timings/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/mute-timings/export",
+				srv.RouteGetMuteTimingsExport,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/policies"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/policies"),
@@ -259,6 +660,116 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alertmanager/external/status"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alertmanager/external/status"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alertmanager/external/status",
+				srv.RouteGetExternalAlertmanagerSyncStatus,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies/effective"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/effective"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies/effective",
+				srv.RouteGetEffectivePolicies,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies/graph"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/graph"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies/graph",
+				srv.RouteGetPolicyTreeGraph,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies/history"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/history"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies/history",
+				srv.RouteGetPolicyTreeHistory,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies/history/{Version}"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/history/{Version}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies/history/{Version}",
+				srv.RouteGetPolicyTreeVersion,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies/search"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/search"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies/search",
+				srv.RouteGetPoliciesByReceiver,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies/templates"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/templates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies/templates",
+				srv.RouteGetPolicyTreeTemplates,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/webhooks/{UID}"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/webhooks/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/webhooks/{UID}",
+				srv.RouteGetProvisioningWebhook,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/webhooks"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/webhooks"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/webhooks",
+				srv.RouteGetProvisioningWebhooks,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{name}"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/rule-templates/{name}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/rule-templates/{name}",
+				srv.RouteGetRuleTemplate,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/rule-templates"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/rule-templates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/rule-templates",
+				srv.RouteGetRuleTemplates,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/{name}"),
@@ -269,6 +780,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/templates/{name}/usage"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/{name}/usage"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/templates/{name}/usage",
+				srv.RouteGetTemplateUsage,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/templates"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/templates"),
@@ -279,6 +800,26 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/templates/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/templates/export",
+				srv.RouteGetTemplatesExport,
+				m,
+			),
+		)
+		group.Patch(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"),
+			api.authorize(http.MethodPatch, "/api/v1/provisioning/alert-rules/{UID}"),
+			metrics.Instrument(
+				http.MethodPatch,
+				"/api/v1/provisioning/alert-rules/{UID}",
+				srv.RoutePatchAlertRule,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/alert-rules"),
 			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules"),
@@ -289,6 +830,76 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/clone"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/{UID}/clone"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/{UID}/clone",
+				srv.RoutePostAlertRuleClone,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/copy"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/copy"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/copy",
+				srv.RoutePostAlertRuleGroupCopy,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/drift"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/drift"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/drift",
+				srv.RoutePostAlertRulesDrift,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/provenance"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/provenance"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/provenance",
+				srv.RoutePostAlertRulesProvenance,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alertmanager/config/validate"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alertmanager/config/validate"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alertmanager/config/validate",
+				srv.RoutePostAlertmanagerConfigValidate,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/batch"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/batch"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/batch",
+				srv.RoutePostProvisioningBatch,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alertmanager/import"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alertmanager/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alertmanager/import",
+				srv.RoutePostAlertmanagerImport,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points"),
@@ -309,6 +920,116 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/mute-timings/{name}/rename"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/mute-timings/{name}/rename"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/mute-timings/{name}/rename",
+				srv.RoutePostMuteTimingRename,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/policies/history/{Version}/rollback"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/policies/history/{Version}/rollback"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/policies/history/{Version}/rollback",
+				srv.RoutePostPolicyTreeRollback,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/policies/lint"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/policies/lint"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/policies/lint",
+				srv.RoutePostPolicyTreeLint,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/policies/repoint"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/policies/repoint"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/policies/repoint",
+				srv.RoutePostPolicyRepoint,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/policies/templates/{Name}"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/policies/templates/{Name}"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/policies/templates/{Name}",
+				srv.RoutePostPolicyTreeTemplate,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/policies/{ID}/move"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/policies/{ID}/move"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/policies/{ID}/move",
+				srv.RoutePostPolicyMove,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/prometheus/import"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/prometheus/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/prometheus/import",
+				srv.RoutePostPrometheusRulesImport,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/webhooks"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/webhooks"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/webhooks",
+				srv.RoutePostProvisioningWebhook,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{name}/instantiate"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/rule-templates/{name}/instantiate"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/rule-templates/{name}/instantiate",
+				srv.RoutePostRuleTemplateInstantiate,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/templates/{name}/rename"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/templates/{name}/rename"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/templates/{name}/rename",
+				srv.RoutePostTemplateRename,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/templates/import"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/templates/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/templates/import",
+				srv.RoutePostTemplatesImport,
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"),
 			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rules/{UID}"),
@@ -329,6 +1050,46 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause"),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause",
+				srv.RoutePutAlertRuleGroupPause,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/order"),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/order"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/order",
+				srv.RoutePutAlertRuleGroupOrder,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/pause"),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rules/{UID}/pause"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alert-rules/{UID}/pause",
+				srv.RoutePutAlertRulePause,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/provenance"),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rules/{UID}/provenance"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alert-rules/{UID}/provenance",
+				srv.RoutePutAlertRuleProvenance,
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			api.authorize(http.MethodPut, "/api/v1/provisioning/contact-points/{UID}"),
@@ -359,6 +1120,26 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/webhooks/{UID}"),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/webhooks/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/webhooks/{UID}",
+				srv.RoutePutProvisioningWebhook,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{name}"),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/rule-templates/{name}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/rule-templates/{name}",
+				srv.RoutePutRuleTemplate,
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			api.authorize(http.MethodPut, "/api/v1/provisioning/templates/{name}"),
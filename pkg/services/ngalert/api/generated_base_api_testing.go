@@ -19,11 +19,20 @@ import (
 )
 
 type TestingApiForkingService interface {
+	RouteBacktestRule(*models.ReqContext) response.Response
 	RouteEvalQueries(*models.ReqContext) response.Response
 	RouteTestRuleConfig(*models.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*models.ReqContext) response.Response
+	RouteValidateGrafanaRuleGroup(*models.ReqContext) response.Response
 }
 
+func (f *ForkedTestingApi) RouteBacktestRule(ctx *models.ReqContext) response.Response {
+	conf := apimodels.BacktestPayload{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRouteBacktestRule(ctx, conf)
+}
 func (f *ForkedTestingApi) RouteEvalQueries(ctx *models.ReqContext) response.Response {
 	conf := apimodels.EvalQueriesPayload{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -46,6 +55,14 @@ func (f *ForkedTestingApi) RouteTestRuleGrafanaConfig(ctx *models.ReqContext) re
 	}
 	return f.forkRouteTestRuleGrafanaConfig(ctx, conf)
 }
+func (f *ForkedTestingApi) RouteValidateGrafanaRuleGroup(ctx *models.ReqContext) response.Response {
+	namespaceParam := web.Params(ctx.Req)[":Namespace"]
+	conf := apimodels.PostableRuleGroupConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRouteValidateGrafanaRuleGroup(ctx, conf, namespaceParam)
+}
 
 func (api *API) RegisterTestingApiEndpoints(srv TestingApiForkingService, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
@@ -59,6 +76,16 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApiForkingService, m *met
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/backtest"),
+			api.authorize(http.MethodPost, "/api/v1/rule/backtest"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backtest",
+				srv.RouteBacktestRule,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/rule/test/{DatasourceUID}"),
 			api.authorize(http.MethodPost, "/api/v1/rule/test/{DatasourceUID}"),
@@ -79,5 +106,15 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApiForkingService, m *met
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/validate/grafana/{Namespace}"),
+			api.authorize(http.MethodPost, "/api/v1/rule/validate/grafana/{Namespace}"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/validate/grafana/{Namespace}",
+				srv.RouteValidateGrafanaRuleGroup,
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
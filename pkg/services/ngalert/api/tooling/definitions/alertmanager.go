@@ -14,6 +14,7 @@ import (
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v3"
 
@@ -163,6 +164,21 @@ import (
 //       408: Failure
 //       409: AlertManagerNotReady
 
+// swagger:route POST /api/alertmanager/grafana/config/api/v1/templates/test alertmanager RoutePostTestGrafanaTemplates
+//
+// Render a notification template against test alerts without saving it.
+//
+// The template is rendered for each notifier format the way the notifiers would send it. It replaces the template
+// with the same name in the configuration, if any. Without alerts, sample alerts are generated from the rule with the
+// given UID, or a test alert is used.
+//
+//     Responses:
+//
+//       200: TestTemplatesResults
+//       400: TestTemplatesError
+//       404: AlertManagerNotFound
+//       409: AlertManagerNotReady
+
 // swagger:route GET /api/alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -280,6 +296,83 @@ type TestReceiverConfigResult struct {
 	Error  string `json:"error,omitempty"`
 }
 
+// swagger:parameters RoutePostTestGrafanaTemplates
+type TestTemplatesConfigParams struct {
+	// in:body
+	Body TestTemplatesConfigBodyParams
+}
+
+type TestTemplatesConfigBodyParams struct {
+	// Name of the template.
+	Name string `json:"name"`
+	// Template definitions, as saved in the configuration.
+	Template string `json:"template"`
+	// Title the Slack and email notifiers render, the default title if empty.
+	Title string `json:"title,omitempty"`
+	// Message the Slack and email notifiers render, the default message if empty.
+	Message string `json:"message,omitempty"`
+	// Alerts to render the template for.
+	Alerts []*TestTemplateAlert `json:"alerts,omitempty"`
+	// UID of an alert rule to generate sample alerts from, if no alerts are given.
+	RuleUID string `json:"ruleUID,omitempty"`
+	// Notifier formats to render, all of them if empty.
+	Formats []TestTemplateFormat `json:"formats,omitempty"`
+}
+
+type TestTemplateAlert struct {
+	Labels       model.LabelSet `json:"labels,omitempty"`
+	Annotations  model.LabelSet `json:"annotations,omitempty"`
+	StartsAt     time.Time      `json:"startsAt,omitempty"`
+	EndsAt       time.Time      `json:"endsAt,omitempty"`
+	GeneratorURL string         `json:"generatorURL,omitempty"`
+}
+
+// TestTemplateFormat is the format of the notifications of a notifier.
+type TestTemplateFormat string
+
+const (
+	TestTemplateFormatSlack   TestTemplateFormat = "slack"
+	TestTemplateFormatEmail   TestTemplateFormat = "email"
+	TestTemplateFormatWebhook TestTemplateFormat = "webhook"
+)
+
+// TestTemplateFormats are the notifier formats templates can be rendered for.
+var TestTemplateFormats = []TestTemplateFormat{TestTemplateFormatSlack, TestTemplateFormatEmail, TestTemplateFormatWebhook}
+
+// swagger:model
+type TestTemplatesResults struct {
+	Results []TestTemplatesResult `json:"results"`
+}
+
+// swagger:model
+type TestTemplatesResult struct {
+	Format TestTemplateFormat `json:"format"`
+	// Subject of the email, only set for the email format.
+	Subject string `json:"subject,omitempty"`
+	// Rendered notification: the JSON payload for slack and webhook, the HTML body for email.
+	Output string `json:"output"`
+	Error  string `json:"error,omitempty"`
+	// Location of the error in the template, if known.
+	ErrorLocation *TemplateErrorLocation `json:"errorLocation,omitempty"`
+}
+
+// swagger:model
+type TestTemplatesError struct {
+	Message string `json:"message"`
+	// Location of the error in the template, if known.
+	ErrorLocation *TemplateErrorLocation `json:"errorLocation,omitempty"`
+}
+
+// TemplateErrorLocation is the position of an error in a template.
+type TemplateErrorLocation struct {
+	// Name of the template the error is in. It is empty for errors in the title and message.
+	Template string `json:"template"`
+	// Line of the error, starting at 1.
+	Line int `json:"line"`
+	// Column of the error, starting at 1. It is only known for errors when rendering.
+	Column int `json:"column,omitempty"`
+}
+
 // swagger:parameters RouteCreateSilence RouteCreateGrafanaSilence
 type CreateSilenceParams struct {
 	// in:body
@@ -681,21 +774,130 @@ func (c *GettableApiAlertingConfig) validate() error {
 		}
 	}
 
+	tiNames := make(map[string]struct{}, len(c.MuteTimeIntervals))
+	for _, mt := range c.MuteTimeIntervals {
+		tiNames[mt.Name] = struct{}{}
+	}
+	for _, r := range c.Receivers {
+		if r.QuietHours == nil {
+			continue
+		}
+		if r.Type() == AlertmanagerReceiverType {
+			return fmt.Errorf("quiet hours are not supported by receiver (%s)", r.Name)
+		}
+		if len(r.QuietHours.TimeIntervals) == 0 {
+			return fmt.Errorf("quiet hours of receiver (%s) have no time interval", r.Name)
+		}
+		for _, ti := range r.QuietHours.TimeIntervals {
+			if _, ok := tiNames[ti]; !ok {
+				return fmt.Errorf("undefined time interval %q used in quiet hours of receiver (%s)", ti, r.Name)
+			}
+		}
+	}
+
 	return nil
 }
 
 // Config is the top-level configuration for Alertmanager's config files.
 type Config struct {
-	Global            *config.GlobalConfig      `yaml:"global,omitempty" json:"global,omitempty"`
-	Route             *Route                    `yaml:"route,omitempty" json:"route,omitempty"`
-	InhibitRules      []*config.InhibitRule     `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
-	MuteTimeIntervals []config.MuteTimeInterval `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
-	Templates         []string                  `yaml:"templates" json:"templates"`
+	Global            *config.GlobalConfig     `yaml:"global,omitempty" json:"global,omitempty"`
+	Route             *Route                   `yaml:"route,omitempty" json:"route,omitempty"`
+	InhibitRules      []*config.InhibitRule    `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
+	MuteTimeIntervals []MuteTimeIntervalConfig `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	Templates         []string                 `yaml:"templates" json:"templates"`
+}
+
+// MuteTimeIntervalConfig represents a named set of time intervals for which a route should be muted. This is
+// modified from the upstream alertmanager in that the time intervals can be in the time zone of a location.
+type MuteTimeIntervalConfig struct {
+	Name          string         `yaml:"name" json:"name"`
+	TimeIntervals []TimeInterval `yaml:"time_intervals" json:"time_intervals"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for MuteTimeIntervalConfig.
+func (mt *MuteTimeIntervalConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain MuteTimeIntervalConfig
+	if err := unmarshal((*plain)(mt)); err != nil {
+		return err
+	}
+	if mt.Name == "" {
+		return fmt.Errorf("missing name in mute time interval")
+	}
+	return nil
+}
+
+// TimeInterval describes intervals of time. This is modified from the upstream alertmanager in that it adds
+// the Location property, the times and days of the interval are in the time zone of the location.
+type TimeInterval struct {
+	Times       []timeinterval.TimeRange       `yaml:"times,omitempty" json:"times,omitempty"`
+	Weekdays    []timeinterval.WeekdayRange    `yaml:"weekdays,flow,omitempty" json:"weekdays,omitempty"`
+	DaysOfMonth []timeinterval.DayOfMonthRange `yaml:"days_of_month,flow,omitempty" json:"days_of_month,omitempty"`
+	Months      []timeinterval.MonthRange      `yaml:"months,flow,omitempty" json:"months,omitempty"`
+	Years       []timeinterval.YearRange       `yaml:"years,flow,omitempty" json:"years,omitempty"`
+	// Location is the name of a location in the IANA Time Zone database, such as Europe/Berlin. Defaults to UTC.
+	Location *Location `yaml:"location,omitempty" json:"location,omitempty"`
+}
+
+// ContainsTime returns true if the time is within the interval, in the time zone of its location.
+func (ti TimeInterval) ContainsTime(t time.Time) bool {
+	if ti.Location != nil && ti.Location.Location != nil {
+		t = t.In(ti.Location.Location)
+	} else {
+		t = t.UTC()
+	}
+	return timeinterval.TimeInterval{
+		Times:       ti.Times,
+		Weekdays:    ti.Weekdays,
+		DaysOfMonth: ti.DaysOfMonth,
+		Months:      ti.Months,
+		Years:       ti.Years,
+	}.ContainsTime(t)
+}
+
+// Location is a time.Location that is marshaled as its name.
+// swagger:strfmt location
+type Location struct {
+	*time.Location
+}
+
+func (l *Location) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err != nil {
+		return err
+	}
+	return l.setName(name)
+}
+
+func (l *Location) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	return l.setName(name)
+}
+
+func (l *Location) setName(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("invalid location %q: %w", name, err)
+	}
+	l.Location = loc
+	return nil
+}
+
+func (l Location) MarshalYAML() (interface{}, error) {
+	return l.String(), nil
+}
+
+func (l Location) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
 }
 
 // A Route is a node that contains definitions of how to handle alerts. This is modified
 // from the upstream alertmanager in that it adds the ObjectMatchers property.
 type Route struct {
+	// ID identifies a nested route within the routing tree. The root route has no ID.
+	ID       string `yaml:"id,omitempty" json:"id,omitempty"`
 	Receiver string `yaml:"receiver,omitempty" json:"receiver,omitempty"`
 
 	GroupByStr []string          `yaml:"group_by,omitempty" json:"group_by,omitempty"`
@@ -704,12 +906,13 @@ type Route struct {
 	// Deprecated. Remove before v1.0 release.
 	Match map[string]string `yaml:"match,omitempty" json:"match,omitempty"`
 	// Deprecated. Remove before v1.0 release.
-	MatchRE           config.MatchRegexps `yaml:"match_re,omitempty" json:"match_re,omitempty"`
-	Matchers          config.Matchers     `yaml:"matchers,omitempty" json:"matchers,omitempty"`
-	ObjectMatchers    ObjectMatchers      `yaml:"object_matchers,omitempty" json:"object_matchers,omitempty"`
-	MuteTimeIntervals []string            `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
-	Continue          bool                `yaml:"continue" json:"continue,omitempty"`
-	Routes            []*Route            `yaml:"routes,omitempty" json:"routes,omitempty"`
+	MatchRE             config.MatchRegexps `yaml:"match_re,omitempty" json:"match_re,omitempty"`
+	Matchers            config.Matchers     `yaml:"matchers,omitempty" json:"matchers,omitempty"`
+	ObjectMatchers      ObjectMatchers      `yaml:"object_matchers,omitempty" json:"object_matchers,omitempty"`
+	MuteTimeIntervals   []string            `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	ActiveTimeIntervals []string            `yaml:"active_time_intervals,omitempty" json:"active_time_intervals,omitempty"`
+	Continue            bool                `yaml:"continue" json:"continue,omitempty"`
+	Routes              []*Route            `yaml:"routes,omitempty" json:"routes,omitempty"`
 
 	GroupWait      *model.Duration `yaml:"group_wait,omitempty" json:"group_wait,omitempty"`
 	GroupInterval  *model.Duration `yaml:"group_interval,omitempty" json:"group_interval,omitempty"`
@@ -729,6 +932,7 @@ func (r *Route) UnmarshalYAML(unmarshal func(interface{}) error) error {
 }
 
 // AsAMRoute returns an Alertmanager route from a Grafana route. The ObjectMatchers are converted to Matchers.
+// Alertmanager routes have no active time intervals, they are dropped.
 func (r *Route) AsAMRoute() *config.Route {
 	amRoute := &config.Route{
 		Receiver:          r.Receiver,
@@ -785,7 +989,7 @@ func (r *Route) ResourceType() string {
 }
 
 func (r *Route) ResourceID() string {
-	return ""
+	return r.ID
 }
 
 // Config is the entrypoint for the embedded Alertmanager config with the exception of receivers.
@@ -831,24 +1035,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 		}
 		tiNames[mt.Name] = struct{}{}
 	}
-	return checkTimeInterval(c.Route, tiNames)
-}
-
-func checkTimeInterval(r *Route, timeIntervals map[string]struct{}) error {
-	for _, sr := range r.Routes {
-		if err := checkTimeInterval(sr, timeIntervals); err != nil {
-			return err
-		}
-	}
-	if len(r.MuteTimeIntervals) == 0 {
-		return nil
-	}
-	for _, mt := range r.MuteTimeIntervals {
-		if _, ok := timeIntervals[mt]; !ok {
-			return fmt.Errorf("undefined time interval %q used in route", mt)
-		}
-	}
-	return nil
+	return c.Route.ValidateMuteTimes(tiNames)
 }
 
 type PostableApiAlertingConfig struct {
@@ -921,6 +1108,27 @@ func (c *PostableApiAlertingConfig) validate() error {
 		}
 	}
 
+	tiNames := make(map[string]struct{}, len(c.MuteTimeIntervals))
+	for _, mt := range c.MuteTimeIntervals {
+		tiNames[mt.Name] = struct{}{}
+	}
+	for _, r := range c.Receivers {
+		if r.QuietHours == nil {
+			continue
+		}
+		if r.Type() == AlertmanagerReceiverType {
+			return fmt.Errorf("quiet hours are not supported by receiver (%s)", r.Name)
+		}
+		if len(r.QuietHours.TimeIntervals) == 0 {
+			return fmt.Errorf("quiet hours of receiver (%s) have no time interval", r.Name)
+		}
+		for _, ti := range r.QuietHours.TimeIntervals {
+			if _, ok := tiNames[ti]; !ok {
+				return fmt.Errorf("undefined time interval %q used in quiet hours of receiver (%s)", ti, r.Name)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -1144,10 +1352,23 @@ func (r *PostableApiReceiver) Type() ReceiverType {
 
 type GettableGrafanaReceivers struct {
 	GrafanaManagedReceivers []*GettableGrafanaReceiver `yaml:"grafana_managed_receiver_configs,omitempty" json:"grafana_managed_receiver_configs,omitempty"`
+	QuietHours              *QuietHours                `yaml:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
 }
 
 type PostableGrafanaReceivers struct {
 	GrafanaManagedReceivers []*PostableGrafanaReceiver `yaml:"grafana_managed_receiver_configs,omitempty" json:"grafana_managed_receiver_configs,omitempty"`
+	QuietHours              *QuietHours                `yaml:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
+}
+
+// QuietHours are the periods during which the notifications of a receiver are held back.
+// Unlike mute timings, which drop notifications, the held back notifications are delivered
+// together as a digest once the quiet hours are over.
+type QuietHours struct {
+	// TimeIntervals are the names of the mute time intervals that define the quiet hours.
+	TimeIntervals []string `yaml:"time_intervals" json:"time_intervals"`
+	// ObjectMatchers select the alerts that are held back. Notifications for alerts that do
+	// not match are sent right away. If empty, all notifications are held back.
+	ObjectMatchers ObjectMatchers `yaml:"object_matchers,omitempty" json:"object_matchers,omitempty"`
 }
 
 type EncryptFn func(ctx context.Context, payload []byte, scope secrets.EncryptionOptions) ([]byte, error)
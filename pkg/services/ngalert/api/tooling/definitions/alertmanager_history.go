@@ -0,0 +1,147 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/alertmanager/grafana/config/history alertmanager RouteGetGrafanaAlertingConfigHistory
+//
+// Get the stored versions of the Alertmanager configuration, newest first.
+//
+// Each version records what saved it and, when it was saved with the configuration API, the login of the user. The
+// versions can be paginated with the limit and page parameters.
+//
+//     Responses:
+//       200: AlertingConfigVersions
+//       400: ValidationError
+
+// swagger:route GET /api/alertmanager/grafana/config/history/diff alertmanager RouteGetGrafanaAlertingConfigDiff
+//
+// Compare two stored versions of the Alertmanager configuration, or a stored version with the current
+// configuration.
+//
+// The values of the secure settings of contact points are never returned, only the names of the secure settings
+// that changed.
+//
+//     Responses:
+//       200: AlertingConfigDiff
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route POST /api/alertmanager/grafana/config/history/{ID}/rollback alertmanager RoutePostGrafanaAlertingConfigHistoryRollback
+//
+// Replace the Alertmanager configuration with a stored version.
+//
+// The version is validated like a new configuration and saved as a new version, so the rollback is recorded in the
+// history and can be reverted. The provenance of the resources that are not in the version is removed.
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RoutePostGrafanaAlertingConfigHistoryRollback
+type AlertingConfigVersionParam struct {
+	// ID of the version
+	// in:path
+	// required: true
+	ID int64
+}
+
+// swagger:parameters RouteGetGrafanaAlertingConfigDiff
+type AlertingConfigDiffParams struct {
+	// ID of the version to compare from.
+	// in:query
+	// required: true
+	From int64 `json:"from"`
+	// ID of the version to compare to. The current configuration is compared if it is empty.
+	// in:query
+	// required: false
+	To int64 `json:"to"`
+}
+
+// swagger:parameters RouteGetGrafanaAlertingConfigHistory
+type AlertingConfigHistoryParams struct {
+	// Maximum number of versions to return. All versions are returned if it is 0.
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+	// Page of versions to return, starting from 1. Only used together with limit.
+	// in:query
+	// required: false
+	// default: 1
+	Page int `json:"page"`
+}
+
+// swagger:model
+type AlertingConfigVersions []AlertingConfigVersion
+
+// swagger:model
+type AlertingConfigVersion struct {
+	ID int64 `json:"id"`
+	// Hash is the MD5 hash of the stored configuration.
+	Hash    string    `json:"hash"`
+	Created time.Time `json:"created"`
+	// Default is true for the default configuration of the organization.
+	Default bool `json:"default"`
+	// Origin is what saved the version: api, provisioning, default or restore. It is empty for the versions saved
+	// before it was recorded.
+	Origin string `json:"origin,omitempty"`
+	// CreatedBy is the login of the user who saved the version with the configuration API.
+	CreatedBy string `json:"createdBy,omitempty"`
+}
+
+// ConfigChange is the kind of change of a part of the Alertmanager configuration.
+type ConfigChange string
+
+const (
+	ConfigChangeAdded   ConfigChange = "added"
+	ConfigChangeRemoved ConfigChange = "removed"
+	ConfigChangeChanged ConfigChange = "changed"
+)
+
+// swagger:model
+type AlertingConfigDiff struct {
+	From AlertingConfigVersion `json:"from"`
+	To   AlertingConfigVersion `json:"to"`
+	// Route are the changes of the notification policy tree.
+	Route             []ValueChange    `json:"route"`
+	Receivers         []ReceiverChange `json:"receivers"`
+	Templates         []NamedChange    `json:"templates"`
+	MuteTimeIntervals []NamedChange    `json:"muteTimeIntervals"`
+}
+
+// ValueChange is a change of a value of the configuration.
+type ValueChange struct {
+	// Path of the value, with the keys of objects and indices of arrays separated by dots.
+	Path   string       `json:"path"`
+	Change ConfigChange `json:"change"`
+	From   interface{}  `json:"from,omitempty"`
+	To     interface{}  `json:"to,omitempty"`
+}
+
+// NamedChange is a change of a named part of the configuration, such as a template.
+type NamedChange struct {
+	Name   string       `json:"name"`
+	Change ConfigChange `json:"change"`
+}
+
+// ReceiverChange is a change of a contact point.
+type ReceiverChange struct {
+	Name   string       `json:"name"`
+	Change ConfigChange `json:"change"`
+	// Integrations are the changes of the integrations of a changed contact point.
+	Integrations []IntegrationChange `json:"integrations,omitempty"`
+}
+
+// IntegrationChange is a change of an integration of a contact point.
+type IntegrationChange struct {
+	UID    string       `json:"uid"`
+	Type   string       `json:"type"`
+	Change ConfigChange `json:"change"`
+	// Changes are the changes of the fields and settings of a changed integration.
+	Changes []ValueChange `json:"changes,omitempty"`
+	// SecureSettings are the names of the secure settings of a changed integration that were added, removed or
+	// changed.
+	SecureSettings []string `json:"secureSettings,omitempty"`
+}
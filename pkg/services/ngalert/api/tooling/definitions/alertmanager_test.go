@@ -6,8 +6,10 @@ import (
 	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -340,6 +342,57 @@ func Test_ApiAlertingConfig_Marshaling(t *testing.T) {
 			},
 			err: true,
 		},
+		{
+			desc: "success graf quiet hours",
+			input: PostableApiAlertingConfig{
+				Config: Config{
+					Route: &Route{
+						Receiver: "graf",
+					},
+					MuteTimeIntervals: []MuteTimeIntervalConfig{{Name: "nights"}},
+				},
+				Receivers: []*PostableApiReceiver{
+					{
+						Receiver: config.Receiver{
+							Name: "graf",
+						},
+						PostableGrafanaReceivers: PostableGrafanaReceivers{
+							GrafanaManagedReceivers: []*PostableGrafanaReceiver{{}},
+							QuietHours: &QuietHours{
+								TimeIntervals:  []string{"nights"},
+								ObjectMatchers: ObjectMatchers{{Type: labels.MatchEqual, Name: "severity", Value: "info"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "failure graf quiet hours undefined time interval",
+			input: PostableApiAlertingConfig{
+				Config: Config{
+					Route: &Route{
+						Receiver: "graf",
+					},
+					MuteTimeIntervals: []MuteTimeIntervalConfig{{Name: "nights"}},
+				},
+				Receivers: []*PostableApiReceiver{
+					{
+						Receiver: config.Receiver{
+							Name: "graf",
+						},
+						PostableGrafanaReceivers: PostableGrafanaReceivers{
+							GrafanaManagedReceivers: []*PostableGrafanaReceiver{{}},
+							QuietHours: &QuietHours{
+								TimeIntervals:  []string{"weekends"},
+								ObjectMatchers: ObjectMatchers{{Type: labels.MatchEqual, Name: "severity", Value: "info"}},
+							},
+						},
+					},
+				},
+			},
+			err: true,
+		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 			encoded, err := json.Marshal(tc.input)
@@ -559,7 +612,7 @@ func Test_ConfigUnmashaling(t *testing.T) {
 		},
 		{
 			desc: "undefined mute time names in routes should error",
-			err:  errors.New("undefined time interval \"test2\" used in route"),
+			err:  &MissingTimeIntervalsError{Names: []string{"test2"}},
 			input: `
 				{
 				  "route": {
@@ -1050,3 +1103,62 @@ func Test_Marshaling_Validation(t *testing.T) {
 	expected := []model.LabelName{"alertname"}
 	require.Equal(t, expected, tmp.AlertmanagerConfig.Config.Route.GroupBy)
 }
+
+func TestTimeInterval_Location(t *testing.T) {
+	const intervalJSON = `{"times":[{"start_time":"09:00","end_time":"17:00"}],"weekdays":["monday:friday"],"location":"America/New_York"}`
+
+	t.Run("location is unmarshaled from JSON and YAML", func(t *testing.T) {
+		var fromJSON TimeInterval
+		require.NoError(t, json.Unmarshal([]byte(intervalJSON), &fromJSON))
+		require.NotNil(t, fromJSON.Location)
+		require.Equal(t, "America/New_York", fromJSON.Location.String())
+
+		var fromYAML TimeInterval
+		require.NoError(t, yaml.Unmarshal([]byte("times:\n  - start_time: '09:00'\n    end_time: '17:00'\nlocation: Asia/Tokyo\n"), &fromYAML))
+		require.NotNil(t, fromYAML.Location)
+		require.Equal(t, "Asia/Tokyo", fromYAML.Location.String())
+		require.Len(t, fromYAML.Times, 1)
+	})
+
+	t.Run("location is marshaled as its name", func(t *testing.T) {
+		var ti TimeInterval
+		require.NoError(t, json.Unmarshal([]byte(intervalJSON), &ti))
+
+		out, err := json.Marshal(ti)
+		require.NoError(t, err)
+		require.Contains(t, string(out), `"location":"America/New_York"`)
+
+		out, err = yaml.Marshal(ti)
+		require.NoError(t, err)
+		require.Contains(t, string(out), "location: America/New_York")
+
+		out, err = json.Marshal(TimeInterval{})
+		require.NoError(t, err)
+		require.NotContains(t, string(out), "location")
+	})
+
+	t.Run("unknown location is rejected", func(t *testing.T) {
+		var ti TimeInterval
+		err := json.Unmarshal([]byte(`{"location":"Mars/Olympus_Mons"}`), &ti)
+		require.ErrorContains(t, err, `invalid location "Mars/Olympus_Mons"`)
+
+		err = yaml.Unmarshal([]byte("location: Mars/Olympus_Mons\n"), &ti)
+		require.ErrorContains(t, err, `invalid location "Mars/Olympus_Mons"`)
+	})
+
+	t.Run("times are in the time zone of the location", func(t *testing.T) {
+		var ti TimeInterval
+		require.NoError(t, json.Unmarshal([]byte(intervalJSON), &ti))
+
+		// 09:30 in New York is 14:30 UTC in winter and 13:30 UTC in summer.
+		require.True(t, ti.ContainsTime(time.Date(2022, time.January, 10, 14, 30, 0, 0, time.UTC)))
+		require.False(t, ti.ContainsTime(time.Date(2022, time.January, 10, 13, 30, 0, 0, time.UTC)))
+		require.True(t, ti.ContainsTime(time.Date(2022, time.July, 11, 13, 30, 0, 0, time.UTC)))
+		// Saturday 02:00 UTC is still Friday evening in New York, after business hours.
+		require.False(t, ti.ContainsTime(time.Date(2022, time.January, 15, 2, 0, 0, 0, time.UTC)))
+
+		ti.Location = nil
+		require.True(t, ti.ContainsTime(time.Date(2022, time.January, 10, 9, 30, 0, 0, time.UTC)))
+		require.True(t, ti.ContainsTime(time.Date(2022, time.January, 10, 10, 30, 0, 0, time.FixedZone("UTC+1", 60*60))))
+	})
+}
@@ -3,10 +3,15 @@ package definitions
 import (
 	"fmt"
 	"html/template"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	amtemplate "github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v3"
 )
@@ -60,11 +65,16 @@ func (t *MessageTemplate) Validate() error {
 	if t.Name == "" {
 		return fmt.Errorf("template must have a name")
 	}
+	// templates are stored as files in the working directory of the alertmanager
+	if filepath.Base(t.Name) != t.Name || t.Name == "." || t.Name == ".." {
+		return fmt.Errorf("invalid template name %q", t.Name)
+	}
 	if t.Template == "" {
 		return fmt.Errorf("template must have content")
 	}
 
-	_, err := template.New("").Parse(t.Template)
+	// parse with the functions available to templates of notifications, so templates using them are accepted
+	_, err := template.New(t.Name).Funcs(template.FuncMap(amtemplate.DefaultFuncs)).Parse(t.Template)
 	if err != nil {
 		return fmt.Errorf("invalid template: %w", err)
 	}
@@ -98,6 +108,9 @@ func (r *Route) Validate() error {
 	if len(r.MuteTimeIntervals) > 0 {
 		return fmt.Errorf("root route must not have any mute time intervals")
 	}
+	if len(r.ActiveTimeIntervals) > 0 {
+		return fmt.Errorf("root route must not have any active time intervals")
+	}
 	return r.validateChild()
 }
 
@@ -114,28 +127,217 @@ func (r *Route) ValidateReceivers(receivers map[string]struct{}) error {
 	return nil
 }
 
+// MissingTimeIntervalsError is returned when routes use time intervals that are not defined.
+type MissingTimeIntervalsError struct {
+	// Names of the undefined time intervals, sorted.
+	Names []string
+}
+
+func (e *MissingTimeIntervalsError) Error() string {
+	quoted := make([]string, 0, len(e.Names))
+	for _, name := range e.Names {
+		quoted = append(quoted, fmt.Sprintf("%q", name))
+	}
+	return fmt.Sprintf("undefined time intervals used in routes: %s", strings.Join(quoted, ", "))
+}
+
+// ValidateMuteTimes checks that the time intervals used by r and its nested routes are all defined.
+// The undefined time intervals of the whole tree are reported at once in a MissingTimeIntervalsError.
 func (r *Route) ValidateMuteTimes(muteTimes map[string]struct{}) error {
+	missing := make(map[string]struct{})
+	r.collectMissingMuteTimes(muteTimes, missing)
+	if len(missing) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &MissingTimeIntervalsError{Names: names}
+}
+
+func (r *Route) collectMissingMuteTimes(muteTimes map[string]struct{}, missing map[string]struct{}) {
 	for _, name := range r.MuteTimeIntervals {
 		if _, exists := muteTimes[name]; !exists {
-			return fmt.Errorf("mute time interval '%s' does not exist", name)
+			missing[name] = struct{}{}
 		}
 	}
-	for _, child := range r.Routes {
-		err := child.ValidateMuteTimes(muteTimes)
-		if err != nil {
-			return err
+	for _, name := range r.ActiveTimeIntervals {
+		if _, exists := muteTimes[name]; !exists {
+			missing[name] = struct{}{}
 		}
 	}
-	return nil
+	for _, child := range r.Routes {
+		child.collectMissingMuteTimes(muteTimes, missing)
+	}
 }
 
 func (mt *MuteTimeInterval) Validate() error {
-	s, err := yaml.Marshal(mt.MuteTimeInterval)
+	if err := ValidateTimeIntervals(mt.TimeIntervals); err != nil {
+		return err
+	}
+	s, err := yaml.Marshal(mt.MuteTimeIntervalConfig)
 	if err != nil {
 		return err
 	}
-	if err = yaml.Unmarshal(s, &(mt.MuteTimeInterval)); err != nil {
+	if err = yaml.Unmarshal(s, &(mt.MuteTimeIntervalConfig)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateTimeIntervals checks the ranges of the time intervals of a mute timing. Besides the ranges the
+// Alertmanager rejects, it rejects overlapping ranges of the same kind. The error names the offending ranges.
+func ValidateTimeIntervals(intervals []TimeInterval) error {
+	for i, ti := range intervals {
+		if err := validateTimeInterval(ti); err != nil {
+			return fmt.Errorf("time_intervals[%d].%w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateTimeInterval(ti TimeInterval) error {
+	times := make([]indexedRange, 0, len(ti.Times))
+	for i, t := range ti.Times {
+		if t.StartMinute < 0 || t.StartMinute > 24*60 || t.EndMinute < 0 || t.EndMinute > 24*60 {
+			return fmt.Errorf("times[%d]: %s is out of range, times must be between 00:00 and 24:00", i, formatTimeRange(t))
+		}
+		if t.StartMinute >= t.EndMinute {
+			return fmt.Errorf("times[%d]: end time %s must be after start time %s", i, formatMinutes(t.EndMinute), formatMinutes(t.StartMinute))
+		}
+		// the end minute of a time range is exclusive
+		times = append(times, indexedRange{index: i, InclusiveRange: timeinterval.InclusiveRange{Begin: t.StartMinute, End: t.EndMinute - 1}})
+	}
+	if err := checkRangeOverlaps("times", times, func(r timeinterval.InclusiveRange) string {
+		return formatTimeRange(timeinterval.TimeRange{StartMinute: r.Begin, EndMinute: r.End + 1})
+	}); err != nil {
+		return err
+	}
+
+	weekdays := make([]indexedRange, 0, len(ti.Weekdays))
+	for i, r := range ti.Weekdays {
+		if r.Begin < 0 || r.Begin > 6 || r.End < 0 || r.End > 6 {
+			return fmt.Errorf("weekdays[%d]: days of the week must be between 0 (sunday) and 6 (saturday), got %d:%d", i, r.Begin, r.End)
+		}
+		if r.Begin > r.End {
+			return fmt.Errorf("weekdays[%d]: end day %s must not be before start day %s", i, formatWeekday(r.End), formatWeekday(r.Begin))
+		}
+		weekdays = append(weekdays, indexedRange{index: i, InclusiveRange: r.InclusiveRange})
+	}
+	if err := checkRangeOverlaps("weekdays", weekdays, formatRange(formatWeekday)); err != nil {
+		return err
+	}
+
+	// negative days count from the end of the month, they can only be compared with each other
+	var daysFromStart, daysFromEnd []indexedRange
+	for i, r := range ti.DaysOfMonth {
+		if r.Begin == 0 || r.Begin < -31 || r.Begin > 31 || r.End == 0 || r.End < -31 || r.End > 31 {
+			return fmt.Errorf("days_of_month[%d]: days must be between 1 and 31, or between -31 and -1 to count from the end of the month, got %d:%d", i, r.Begin, r.End)
+		}
+		if r.Begin < 0 && r.End > 0 {
+			return fmt.Errorf("days_of_month[%d]: end day %d must be negative if start day %d is negative", i, r.End, r.Begin)
+		}
+		checkBegin, checkEnd := r.Begin, r.End
+		if r.Begin < 0 {
+			checkBegin = 28 + r.Begin
+		}
+		if r.End < 0 {
+			checkEnd = 28 + r.End
+		}
+		if checkBegin > checkEnd {
+			return fmt.Errorf("days_of_month[%d]: end day %d is always before start day %d", i, r.End, r.Begin)
+		}
+		if r.Begin > 0 && r.End > 0 {
+			daysFromStart = append(daysFromStart, indexedRange{index: i, InclusiveRange: r.InclusiveRange})
+		} else if r.Begin < 0 && r.End < 0 {
+			daysFromEnd = append(daysFromEnd, indexedRange{index: i, InclusiveRange: r.InclusiveRange})
+		}
+	}
+	if err := checkRangeOverlaps("days_of_month", daysFromStart, formatRange(strconv.Itoa)); err != nil {
+		return err
+	}
+	if err := checkRangeOverlaps("days_of_month", daysFromEnd, formatRange(strconv.Itoa)); err != nil {
+		return err
+	}
+
+	months := make([]indexedRange, 0, len(ti.Months))
+	for i, r := range ti.Months {
+		if r.Begin < 1 || r.Begin > 12 || r.End < 1 || r.End > 12 {
+			return fmt.Errorf("months[%d]: months must be between 1 (january) and 12 (december), got %d:%d", i, r.Begin, r.End)
+		}
+		if r.Begin > r.End {
+			return fmt.Errorf("months[%d]: end month %s must not be before start month %s", i, formatMonth(r.End), formatMonth(r.Begin))
+		}
+		months = append(months, indexedRange{index: i, InclusiveRange: r.InclusiveRange})
+	}
+	if err := checkRangeOverlaps("months", months, formatRange(formatMonth)); err != nil {
 		return err
 	}
+
+	years := make([]indexedRange, 0, len(ti.Years))
+	for i, r := range ti.Years {
+		if r.Begin < 1 || r.End < 1 {
+			return fmt.Errorf("years[%d]: years must be positive, got %d:%d", i, r.Begin, r.End)
+		}
+		if r.Begin > r.End {
+			return fmt.Errorf("years[%d]: end year %d must not be before start year %d", i, r.End, r.Begin)
+		}
+		years = append(years, indexedRange{index: i, InclusiveRange: r.InclusiveRange})
+	}
+	return checkRangeOverlaps("years", years, formatRange(strconv.Itoa))
+}
+
+// indexedRange is a range with its position in the field of the time interval.
+type indexedRange struct {
+	index int
+	timeinterval.InclusiveRange
+}
+
+// checkRangeOverlaps returns an error for the first two ranges that overlap.
+func checkRangeOverlaps(field string, ranges []indexedRange, format func(timeinterval.InclusiveRange) string) error {
+	sorted := make([]indexedRange, len(ranges))
+	copy(sorted, ranges)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Begin < sorted[j].Begin
+	})
+	// latest is the range that ends last of the ranges that begin before the current one
+	for i, latest := 1, 0; i < len(sorted); i++ {
+		if sorted[i].Begin > sorted[latest].End {
+			latest = i
+			continue
+		}
+		first, second := sorted[latest], sorted[i]
+		if first.index > second.index {
+			first, second = second, first
+		}
+		return fmt.Errorf("%s[%d]: %s overlaps %s[%d] (%s)", field, second.index, format(second.InclusiveRange), field, first.index, format(first.InclusiveRange))
+	}
 	return nil
 }
+
+func formatRange(format func(int) string) func(timeinterval.InclusiveRange) string {
+	return func(r timeinterval.InclusiveRange) string {
+		if r.Begin == r.End {
+			return format(r.Begin)
+		}
+		return format(r.Begin) + ":" + format(r.End)
+	}
+}
+
+func formatTimeRange(r timeinterval.TimeRange) string {
+	return formatMinutes(r.StartMinute) + "-" + formatMinutes(r.EndMinute)
+}
+
+func formatMinutes(m int) string {
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
+
+func formatWeekday(d int) string {
+	return strings.ToLower(time.Weekday(d).String())
+}
+
+func formatMonth(m int) string {
+	return strings.ToLower(time.Month(m).String())
+}
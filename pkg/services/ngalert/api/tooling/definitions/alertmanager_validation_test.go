@@ -1,12 +1,14 @@
 package definitions
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
-	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestValidateRoutes(t *testing.T) {
@@ -258,6 +260,83 @@ func TestValidateRoutes(t *testing.T) {
 	})
 }
 
+func TestValidateMuteTimes(t *testing.T) {
+	muteTimes := map[string]struct{}{"weekends": {}}
+
+	t.Run("defined time intervals pass", func(t *testing.T) {
+		route := Route{
+			Receiver: "foo",
+			Routes:   []*Route{{Receiver: "foo", MuteTimeIntervals: []string{"weekends"}}},
+		}
+		require.NoError(t, route.ValidateMuteTimes(muteTimes))
+	})
+
+	t.Run("all undefined time intervals of the tree are reported", func(t *testing.T) {
+		route := Route{
+			Receiver: "foo",
+			Routes: []*Route{
+				{Receiver: "foo", MuteTimeIntervals: []string{"weekends", "nights"}},
+				{
+					Receiver: "foo",
+					Routes:   []*Route{{Receiver: "foo", MuteTimeIntervals: []string{"holidays", "nights"}}},
+				},
+			},
+		}
+
+		err := route.ValidateMuteTimes(muteTimes)
+
+		var missing *MissingTimeIntervalsError
+		require.ErrorAs(t, err, &missing)
+		require.Equal(t, []string{"holidays", "nights"}, missing.Names)
+		require.EqualError(t, err, `undefined time intervals used in routes: "holidays", "nights"`)
+	})
+
+	t.Run("undefined active time intervals are reported", func(t *testing.T) {
+		route := Route{
+			Receiver: "foo",
+			Routes: []*Route{
+				{Receiver: "foo", MuteTimeIntervals: []string{"nights"}, ActiveTimeIntervals: []string{"weekends", "business-hours"}},
+			},
+		}
+
+		err := route.ValidateMuteTimes(muteTimes)
+
+		var missing *MissingTimeIntervalsError
+		require.ErrorAs(t, err, &missing)
+		require.Equal(t, []string{"business-hours", "nights"}, missing.Names)
+	})
+}
+
+func TestRouteActiveTimeIntervals(t *testing.T) {
+	t.Run("root route must not have active time intervals", func(t *testing.T) {
+		route := Route{Receiver: "foo", ActiveTimeIntervals: []string{"weekends"}}
+		require.EqualError(t, route.Validate(), "root route must not have any active time intervals")
+	})
+
+	t.Run("active time intervals round trip through JSON and YAML", func(t *testing.T) {
+		route := Route{
+			Receiver: "foo",
+			Routes: []*Route{
+				{Receiver: "bar", MuteTimeIntervals: []string{"holidays"}, ActiveTimeIntervals: []string{"weekends"}},
+			},
+		}
+
+		raw, err := json.Marshal(route)
+		require.NoError(t, err)
+		require.Contains(t, string(raw), `"active_time_intervals":["weekends"]`)
+		var fromJSON Route
+		require.NoError(t, json.Unmarshal(raw, &fromJSON))
+		require.Equal(t, []string{"weekends"}, fromJSON.Routes[0].ActiveTimeIntervals)
+
+		raw, err = yaml.Marshal(route)
+		require.NoError(t, err)
+		var fromYAML Route
+		require.NoError(t, yaml.Unmarshal(raw, &fromYAML))
+		require.Equal(t, []string{"weekends"}, fromYAML.Routes[0].ActiveTimeIntervals)
+		require.Equal(t, []string{"holidays"}, fromYAML.Routes[0].MuteTimeIntervals)
+	})
+}
+
 func TestValidateMuteTimeInterval(t *testing.T) {
 	type testCase struct {
 		desc   string
@@ -270,7 +349,7 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 			{
 				desc: "nil intervals",
 				mti: MuteTimeInterval{
-					MuteTimeInterval: config.MuteTimeInterval{
+					MuteTimeIntervalConfig: MuteTimeIntervalConfig{
 						Name: "interval",
 					},
 				},
@@ -278,18 +357,18 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 			{
 				desc: "empty intervals",
 				mti: MuteTimeInterval{
-					MuteTimeInterval: config.MuteTimeInterval{
+					MuteTimeIntervalConfig: MuteTimeIntervalConfig{
 						Name:          "interval",
-						TimeIntervals: []timeinterval.TimeInterval{},
+						TimeIntervals: []TimeInterval{},
 					},
 				},
 			},
 			{
 				desc: "blank interval",
 				mti: MuteTimeInterval{
-					MuteTimeInterval: config.MuteTimeInterval{
+					MuteTimeIntervalConfig: MuteTimeIntervalConfig{
 						Name: "interval",
-						TimeIntervals: []timeinterval.TimeInterval{
+						TimeIntervals: []TimeInterval{
 							{},
 						},
 					},
@@ -298,9 +377,9 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 			{
 				desc: "simple",
 				mti: MuteTimeInterval{
-					MuteTimeInterval: config.MuteTimeInterval{
+					MuteTimeIntervalConfig: MuteTimeIntervalConfig{
 						Name: "interval",
-						TimeIntervals: []timeinterval.TimeInterval{
+						TimeIntervals: []TimeInterval{
 							{
 								Weekdays: []timeinterval.WeekdayRange{
 									{
@@ -336,9 +415,9 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 			{
 				desc: "empty",
 				mti: MuteTimeInterval{
-					MuteTimeInterval: config.MuteTimeInterval{
+					MuteTimeIntervalConfig: MuteTimeIntervalConfig{
 						Name: "interval",
-						TimeIntervals: []timeinterval.TimeInterval{
+						TimeIntervals: []TimeInterval{
 							{
 								Weekdays: []timeinterval.WeekdayRange{
 									{
@@ -352,7 +431,7 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 						},
 					},
 				},
-				expMsg: "unable to convert -1 into weekday",
+				expMsg: "time_intervals[0].weekdays[0]: days of the week must be between 0 (sunday) and 6 (saturday), got -1:7",
 			},
 		}
 
@@ -364,4 +443,149 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("location is kept", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		mti := MuteTimeInterval{
+			MuteTimeIntervalConfig: MuteTimeIntervalConfig{
+				Name:          "interval",
+				TimeIntervals: []TimeInterval{{Location: &Location{loc}}},
+			},
+		}
+
+		require.NoError(t, mti.Validate())
+		require.Equal(t, "Europe/Berlin", mti.TimeIntervals[0].Location.String())
+	})
+}
+
+func TestValidateTimeIntervals(t *testing.T) {
+	weekdays := func(ranges ...[2]int) []timeinterval.WeekdayRange {
+		result := make([]timeinterval.WeekdayRange, 0, len(ranges))
+		for _, r := range ranges {
+			result = append(result, timeinterval.WeekdayRange{InclusiveRange: timeinterval.InclusiveRange{Begin: r[0], End: r[1]}})
+		}
+		return result
+	}
+	daysOfMonth := func(ranges ...[2]int) []timeinterval.DayOfMonthRange {
+		result := make([]timeinterval.DayOfMonthRange, 0, len(ranges))
+		for _, r := range ranges {
+			result = append(result, timeinterval.DayOfMonthRange{InclusiveRange: timeinterval.InclusiveRange{Begin: r[0], End: r[1]}})
+		}
+		return result
+	}
+	months := func(ranges ...[2]int) []timeinterval.MonthRange {
+		result := make([]timeinterval.MonthRange, 0, len(ranges))
+		for _, r := range ranges {
+			result = append(result, timeinterval.MonthRange{InclusiveRange: timeinterval.InclusiveRange{Begin: r[0], End: r[1]}})
+		}
+		return result
+	}
+	years := func(ranges ...[2]int) []timeinterval.YearRange {
+		result := make([]timeinterval.YearRange, 0, len(ranges))
+		for _, r := range ranges {
+			result = append(result, timeinterval.YearRange{InclusiveRange: timeinterval.InclusiveRange{Begin: r[0], End: r[1]}})
+		}
+		return result
+	}
+	times := func(ranges ...[2]int) []timeinterval.TimeRange {
+		result := make([]timeinterval.TimeRange, 0, len(ranges))
+		for _, r := range ranges {
+			result = append(result, timeinterval.TimeRange{StartMinute: r[0], EndMinute: r[1]})
+		}
+		return result
+	}
+
+	cases := []struct {
+		desc      string
+		intervals []TimeInterval
+		expMsg    string
+	}{
+		{
+			desc: "valid intervals",
+			intervals: []TimeInterval{
+				{
+					Times:       times([2]int{9 * 60, 12 * 60}, [2]int{12 * 60, 13 * 60}),
+					Weekdays:    weekdays([2]int{1, 2}, [2]int{4, 5}),
+					DaysOfMonth: daysOfMonth([2]int{1, 5}, [2]int{-3, -1}),
+					Months:      months([2]int{1, 3}, [2]int{12, 12}),
+					Years:       years([2]int{2022, 2023}),
+				},
+				{
+					Weekdays: weekdays([2]int{1, 5}),
+				},
+			},
+		},
+		{
+			desc:      "end time before start time",
+			intervals: []TimeInterval{{Times: times([2]int{10 * 60, 9 * 60})}},
+			expMsg:    "time_intervals[0].times[0]: end time 09:00 must be after start time 10:00",
+		},
+		{
+			desc:      "time out of range",
+			intervals: []TimeInterval{{Times: times([2]int{10 * 60, 24*60 + 30})}},
+			expMsg:    "time_intervals[0].times[0]: 10:00-24:30 is out of range, times must be between 00:00 and 24:00",
+		},
+		{
+			desc:      "overlapping times",
+			intervals: []TimeInterval{{Times: times([2]int{11 * 60, 13 * 60}, [2]int{8 * 60, 9 * 60}, [2]int{9 * 60, 12 * 60})}},
+			expMsg:    "time_intervals[0].times[2]: 09:00-12:00 overlaps times[0] (11:00-13:00)",
+		},
+		{
+			desc:      "end day before start day",
+			intervals: []TimeInterval{{}, {Weekdays: weekdays([2]int{5, 1})}},
+			expMsg:    "time_intervals[1].weekdays[0]: end day monday must not be before start day friday",
+		},
+		{
+			desc:      "overlapping weekdays",
+			intervals: []TimeInterval{{Weekdays: weekdays([2]int{1, 5}, [2]int{6, 6}, [2]int{3, 3})}},
+			expMsg:    "time_intervals[0].weekdays[2]: wednesday overlaps weekdays[0] (monday:friday)",
+		},
+		{
+			desc:      "day of month out of range",
+			intervals: []TimeInterval{{DaysOfMonth: daysOfMonth([2]int{1, 32})}},
+			expMsg:    "time_intervals[0].days_of_month[0]: days must be between 1 and 31, or between -31 and -1 to count from the end of the month, got 1:32",
+		},
+		{
+			desc:      "day of month zero",
+			intervals: []TimeInterval{{DaysOfMonth: daysOfMonth([2]int{0, 5})}},
+			expMsg:    "time_intervals[0].days_of_month[0]: days must be between 1 and 31",
+		},
+		{
+			desc:      "negative start day with positive end day",
+			intervals: []TimeInterval{{DaysOfMonth: daysOfMonth([2]int{-5, 5})}},
+			expMsg:    "time_intervals[0].days_of_month[0]: end day 5 must be negative if start day -5 is negative",
+		},
+		{
+			desc:      "overlapping days counted from the end of the month",
+			intervals: []TimeInterval{{DaysOfMonth: daysOfMonth([2]int{1, 5}, [2]int{-7, -1}, [2]int{-2, -2})}},
+			expMsg:    "time_intervals[0].days_of_month[2]: -2 overlaps days_of_month[1] (-7:-1)",
+		},
+		{
+			desc:      "month out of range",
+			intervals: []TimeInterval{{Months: months([2]int{11, 13})}},
+			expMsg:    "time_intervals[0].months[0]: months must be between 1 (january) and 12 (december), got 11:13",
+		},
+		{
+			desc:      "overlapping months",
+			intervals: []TimeInterval{{Months: months([2]int{6, 8}, [2]int{1, 6})}},
+			expMsg:    "time_intervals[0].months[1]: january:june overlaps months[0] (june:august)",
+		},
+		{
+			desc:      "end year before start year",
+			intervals: []TimeInterval{{Years: years([2]int{2023, 2022})}},
+			expMsg:    "time_intervals[0].years[0]: end year 2022 must not be before start year 2023",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			err := ValidateTimeIntervals(c.intervals)
+			if c.expMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, c.expMsg)
+		})
+	}
 }
@@ -122,7 +122,7 @@ import (
 //     Responses:
 //       202: Ack
 
-// swagger:parameters RoutePostNameRulesConfig RoutePostNameGrafanaRulesConfig
+// swagger:parameters RoutePostNameRulesConfig RoutePostNameGrafanaRulesConfig RouteValidateGrafanaRuleGroup
 type NamespaceConfig struct {
 	// in:path
 	Namespace string
@@ -368,23 +368,33 @@ type PostableGrafanaRule struct {
 	UID          string              `json:"uid" yaml:"uid"`
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
+	// Webhooks are called when the alert instances of the rule start firing, are resolved or start failing.
+	Webhooks []models.AlertRuleWebhook `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	// Paused rules are not evaluated.
+	IsPaused bool `json:"is_paused" yaml:"is_paused"`
+	// Maximum number of alert instances of an evaluation. The alert instances are replaced with a single alert if
+	// there are more. The default limit of the server is used if it is 0.
+	MaxInstances int64 `json:"max_instances,omitempty" yaml:"max_instances,omitempty"`
 }
 
 // swagger:model
 type GettableGrafanaRule struct {
-	ID              int64               `json:"id" yaml:"id"`
-	OrgID           int64               `json:"orgId" yaml:"orgId"`
-	Title           string              `json:"title" yaml:"title"`
-	Condition       string              `json:"condition" yaml:"condition"`
-	Data            []models.AlertQuery `json:"data" yaml:"data"`
-	Updated         time.Time           `json:"updated" yaml:"updated"`
-	IntervalSeconds int64               `json:"intervalSeconds" yaml:"intervalSeconds"`
-	Version         int64               `json:"version" yaml:"version"`
-	UID             string              `json:"uid" yaml:"uid"`
-	NamespaceUID    string              `json:"namespace_uid" yaml:"namespace_uid"`
-	NamespaceID     int64               `json:"namespace_id" yaml:"namespace_id"`
-	RuleGroup       string              `json:"rule_group" yaml:"rule_group"`
-	NoDataState     NoDataState         `json:"no_data_state" yaml:"no_data_state"`
-	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
-	Provenance      models.Provenance   `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+	ID              int64                     `json:"id" yaml:"id"`
+	OrgID           int64                     `json:"orgId" yaml:"orgId"`
+	Title           string                    `json:"title" yaml:"title"`
+	Condition       string                    `json:"condition" yaml:"condition"`
+	Data            []models.AlertQuery       `json:"data" yaml:"data"`
+	Updated         time.Time                 `json:"updated" yaml:"updated"`
+	IntervalSeconds int64                     `json:"intervalSeconds" yaml:"intervalSeconds"`
+	Version         int64                     `json:"version" yaml:"version"`
+	UID             string                    `json:"uid" yaml:"uid"`
+	NamespaceUID    string                    `json:"namespace_uid" yaml:"namespace_uid"`
+	NamespaceID     int64                     `json:"namespace_id" yaml:"namespace_id"`
+	RuleGroup       string                    `json:"rule_group" yaml:"rule_group"`
+	NoDataState     NoDataState               `json:"no_data_state" yaml:"no_data_state"`
+	ExecErrState    ExecutionErrorState       `json:"exec_err_state" yaml:"exec_err_state"`
+	Webhooks        []models.AlertRuleWebhook `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	IsPaused        bool                      `json:"is_paused" yaml:"is_paused"`
+	MaxInstances    int64                     `json:"max_instances,omitempty" yaml:"max_instances,omitempty"`
+	Provenance      models.Provenance         `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 }
@@ -0,0 +1,38 @@
+package definitions
+
+// swagger:route GET /api/v1/ngalert/default_labels configuration RouteGetDefaultLabels
+//
+// Get the default labels of the user's organization.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: DefaultLabels
+//       500: Failure
+
+// swagger:route POST /api/v1/ngalert/default_labels configuration RoutePostDefaultLabels
+//
+// Replaces the default labels of the user's organization.
+//
+// The default labels are added to every alert instance of the organization that does not have a label with the
+// same name, either from its rule or from the query.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: Ack
+//       400: ValidationError
+
+// swagger:parameters RoutePostDefaultLabels
+type DefaultLabelsParams struct {
+	// in:body
+	Body DefaultLabels
+}
+
+// swagger:model
+type DefaultLabels struct {
+	// example: {"env": "prod", "org": "acme"}
+	Labels map[string]string `json:"labels"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/prometheus/common/model"
 )
 
 // swagger:route GET /api/v1/provisioning/alert-rules/{UID} provisioning stable RouteGetAlertRule
@@ -14,16 +15,32 @@ import (
 //       200: AlertRule
 //       404: description: Not found.
 
+// swagger:route GET /api/v1/provisioning/alert-rules provisioning stable RouteGetAlertRules
+//
+// Get the alert rules of the organization, optionally only the ones whose labels match label selectors.
+//
+// The rules are sorted by folder, rule group and position in the group. Their labels include the labels of their
+// rule group.
+//
+//     Responses:
+//       200: AlertRules
+//       400: ValidationError
+
 // swagger:route POST /api/v1/provisioning/alert-rules provisioning stable RoutePostAlertRule
 //
 // Create a new alert rule.
 //
+// The datasources of the queries must exist in the organization and support alerting. Otherwise, the request is
+// rejected and the response lists the UIDs of the missing datasources in missingDatasources, and of the datasources
+// that do not support alerting in incompatibleDatasources.
+//
 //     Consumes:
 //     - application/json
 //
 //     Responses:
 //       201: AlertRule
 //       400: ValidationError
+//       403: ValidationError
 
 // swagger:route PUT /api/v1/provisioning/alert-rules/{UID} provisioning stable RoutePutAlertRule
 //
@@ -35,6 +52,21 @@ import (
 //     Responses:
 //       200: AlertRule
 //       400: ValidationError
+//       403: ValidationError
+
+// swagger:route PATCH /api/v1/provisioning/alert-rules/{UID} provisioning stable RoutePatchAlertRule
+//
+// Change some fields of an existing alert rule, such as a threshold, a label or an annotation, without sending
+// the whole rule. Fields that are not set are not changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRule
+//       400: ValidationError
+//       403: ValidationError
+//       404: description: Not found.
 
 // swagger:route DELETE /api/v1/provisioning/alert-rules/{UID} provisioning stable RouteDeleteAlertRule
 //
@@ -43,13 +75,87 @@ import (
 //     Responses:
 //       204: description: The alert rule was deleted successfully.
 
-// swagger:parameters RouteGetAlertRule RoutePutAlertRule RouteDeleteAlertRule
+// swagger:route POST /api/v1/provisioning/alert-rules/{UID}/clone provisioning stable RoutePostAlertRuleClone
+//
+// Create a copy of an alert rule with a new UID.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: AlertRule
+//       400: ValidationError
+//       403: ValidationError
+//       404: description: Not found.
+
+// swagger:route PUT /api/v1/provisioning/alert-rules/{UID}/pause provisioning stable RoutePutAlertRulePause
+//
+// Pause or resume an alert rule. The provenance of the rule is neither checked nor changed, so that rules
+// provisioned from files can be paused as well.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRulePause
+//       404: description: Not found.
+
+// swagger:route PUT /api/v1/provisioning/alert-rules/{UID}/provenance provisioning stable RoutePutAlertRuleProvenance
+//
+// Change the provenance of an alert rule regardless of its current provenance, for example to manage a rule
+// through the API once the file that provisioned it is retired. Only organization administrators can change
+// the provenance.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleProvenance
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/alert-rules/provenance provisioning stable RoutePostAlertRulesProvenance
+//
+// Change the provenance of many alert rules at once, for example to manage the rules imported in a migration through
+// the API. The provenance of all the rules is changed in a single transaction, regardless of their current
+// provenance, and nothing is changed if one of the rules does not exist. Only organization administrators can change
+// the provenance.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRulesProvenanceResult
+//       400: ValidationError
+
+// swagger:parameters RouteGetAlertRule RoutePutAlertRule RoutePatchAlertRule RouteDeleteAlertRule RoutePostAlertRuleClone RoutePutAlertRulePause RoutePutAlertRuleProvenance
 type AlertRuleUIDReference struct {
 	// Alert rule UID
 	// in:path
 	UID string
 }
 
+// swagger:parameters RouteGetAlertRules
+type AlertRulesListParams struct {
+	// Label selectors the labels of the rules must all match, such as team=payments, severity!=info or
+	// team=~"payments|billing". The parameter can be repeated.
+	// in: query
+	// required: false
+	// example: team=payments
+	Label []string `json:"label"`
+	// Only return the rules of this folder.
+	// in: query
+	// required: false
+	FolderUID string `json:"folderUid"`
+	// Only return the rules of the rule groups with this name.
+	// in: query
+	// required: false
+	RuleGroup string `json:"ruleGroup"`
+}
+
+// swagger:model
+type AlertRules []AlertRule
+
 // swagger:parameters RoutePostAlertRule RoutePutAlertRule
 type AlertRulePayload struct {
 	// in:body
@@ -92,10 +198,79 @@ type AlertRule struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
 	Labels map[string]string `json:"labels,omitempty"`
+	// Webhooks are called when the alert instances of the rule start firing, are resolved or start failing.
+	Webhooks []models.AlertRuleWebhook `json:"webhooks,omitempty"`
+	// Paused rules are not evaluated.
+	IsPaused bool `json:"isPaused"`
+	// Maximum number of alert instances of an evaluation. The alert instances are replaced with a single alert if
+	// there are more. The default limit of the server is used if it is 0.
+	MaxInstances int64 `json:"maxInstances,omitempty"`
 	// readonly: true
 	Provenance models.Provenance `json:"provenance,omitempty"`
 }
 
+// swagger:parameters RoutePostAlertRuleClone
+type AlertRuleClonePayload struct {
+	// in:body
+	Body AlertRuleClone
+}
+
+// AlertRuleClone are the changes of the copy of an alert rule. Fields that are not set are copied from the rule.
+type AlertRuleClone struct {
+	// example: Always firing for service-b
+	Title string `json:"title,omitempty"`
+	// example: project_y
+	FolderUID string `json:"folderUID,omitempty"`
+	// example: eval_group_2
+	RuleGroup string `json:"ruleGroup,omitempty"`
+	// Labels are added to the labels of the rule, labels with an empty value are removed.
+	// example: {"service": "service-b"}
+	Labels map[string]string `json:"labels,omitempty"`
+	// Parameters change values in the models of the queries and expressions of the rule.
+	Parameters []AlertRuleCloneParameter `json:"parameters,omitempty"`
+	// Paused copies are not evaluated.
+	IsPaused bool `json:"isPaused"`
+}
+
+// AlertRuleCloneParameter changes a value in the model of a query or expression of an alert rule.
+type AlertRuleCloneParameter struct {
+	// required: true
+	// example: B
+	RefID string `json:"refId"`
+	// Path of the value in the model, with the keys of objects and indices of arrays separated by dots.
+	// required: true
+	// example: conditions.0.evaluator.params.0
+	Path string `json:"path"`
+	// required: true
+	// example: 90
+	Value interface{} `json:"value"`
+}
+
+// swagger:parameters RoutePatchAlertRule
+type AlertRulePatchPayload struct {
+	// in:body
+	Body AlertRulePatch
+}
+
+// AlertRulePatch are the changes of a partial update of an alert rule. Fields that are not set are not changed.
+type AlertRulePatch struct {
+	// example: Always firing
+	Title string `json:"title,omitempty"`
+	// example: B
+	Condition    string                     `json:"condition,omitempty"`
+	NoDataState  models.NoDataState         `json:"noDataState,omitempty"`
+	ExecErrState models.ExecutionErrorState `json:"execErrState,omitempty"`
+	For          *time.Duration             `json:"for,omitempty"`
+	// Annotations are added to the annotations of the rule, annotations with an empty value are removed.
+	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels are added to the labels of the rule, labels with an empty value are removed.
+	// example: {"severity": "critical"}
+	Labels map[string]string `json:"labels,omitempty"`
+	// Parameters change values in the models of the queries and expressions of the rule.
+	Parameters []AlertRuleCloneParameter `json:"parameters,omitempty"`
+}
+
 func (a *AlertRule) UpstreamModel() models.AlertRule {
 	return models.AlertRule{
 		ID:           a.ID,
@@ -112,6 +287,9 @@ func (a *AlertRule) UpstreamModel() models.AlertRule {
 		For:          a.For,
 		Annotations:  a.Annotations,
 		Labels:       a.Labels,
+		Webhooks:     a.Webhooks,
+		IsPaused:     a.IsPaused,
+		MaxInstances: a.MaxInstances,
 	}
 }
 
@@ -131,6 +309,9 @@ func NewAlertRule(rule models.AlertRule, provenance models.Provenance) AlertRule
 		ExecErrState: rule.ExecErrState,
 		Annotations:  rule.Annotations,
 		Labels:       rule.Labels,
+		Webhooks:     rule.Webhooks,
+		IsPaused:     rule.IsPaused,
+		MaxInstances: rule.MaxInstances,
 		Provenance:   provenance,
 	}
 }
@@ -139,13 +320,22 @@ func NewAlertRule(rule models.AlertRule, provenance models.Provenance) AlertRule
 //
 // Get a rule group.
 //
+// The ETag header of the response is the version of the rule group, which changes whenever a rule of the group is
+// changed, added or removed. It can be sent in the If-Match header of the requests that change the group.
+//
 //     Responses:
 //       200: AlertRuleGroup
 //       404: description: Not found.
 
 // swagger:route PUT /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RoutePutAlertRuleGroup
 //
-// Update the interval of a rule group.
+// Update the interval and the labels of a rule group.
+//
+// The interval must be a multiple of the base interval of the scheduler and not lower than the minimum interval
+// set by min_interval. Otherwise, the request is rejected and the error suggests the nearest valid interval.
+//
+// The labels of the group are merged into the labels of all its rules, and the labels of the rules override them.
+// They replace the previous labels of the group, which are removed from the rules unless the rules changed them.
 //
 //     Consumes:
 //     - application/json
@@ -153,19 +343,100 @@ func NewAlertRule(rule models.AlertRule, provenance models.Provenance) AlertRule
 //     Responses:
 //       200: AlertRuleGroupMetadata
 //       400: ValidationError
+//       409: description: The rule group was changed since it was read.
+
+// swagger:route DELETE /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteDeleteAlertRuleGroup
+//
+// Delete a rule group.
+//
+// The state of the rules is dropped with them. The response lists the alerts of the rules that are not normal, and
+// the silences that match the alerts or the rules. Unless force is set, the rule group is not deleted if there are
+// any, and they are returned with the status 409 so that they can be confirmed. The status is 409 as well, with an
+// error message, if the rule group was changed since it was read.
+//
+//     Responses:
+//       200: RuleGroupDeletion
+//       400: ValidationError
+//       404: description: Not found.
+//       409: RuleGroupDeletion
+
+// swagger:route GET /api/v1/provisioning/folder/{FolderUID}/export provisioning stable RouteGetAlertRuleGroupsExport
+//
+// Export the rule groups of a folder in the file provisioning format.
+//
+//     Produces:
+//     - application/yaml
+//     - application/json
+//     - text/plain
+//
+//     Responses:
+//       200: AlertRulesExport
+//       400: ValidationError
+
+// swagger:route PUT /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause provisioning stable RoutePutAlertRuleGroupPause
+//
+// Pause or resume all alert rules of a rule group. The provenance of the rules is neither checked nor changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRulePause
+//       404: description: Not found.
+//       409: description: The rule group was changed since it was read.
+
+// swagger:route PUT /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/order provisioning stable RoutePutAlertRuleGroupOrder
+//
+// Reorder the alert rules of a rule group. The rules are evaluated and displayed in the order of the UIDs, which
+// must be the UIDs of all the rules of the group. The provenance of the rules is neither checked nor changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleGroupOrder
+//       400: ValidationError
+//       404: description: Not found.
+//       409: description: The rule group was changed since it was read.
+
+// swagger:route POST /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/copy provisioning stable RoutePostAlertRuleGroupCopy
+//
+// Copy a rule group into another folder, and optionally another organization, for example to promote rules
+// from a staging organization to a production one. The copied rules get new UIDs, and the datasources of their
+// queries are replaced with the ones of the mapping. Only Grafana administrators can copy rule groups to
+// another organization. With deterministicUids, the UIDs of the copied rules are derived from the folder, the title
+// of the copy and the titles of the rules, so copies of a group into the same folder of several organizations get the
+// same UIDs.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: AlertRuleGroup
+//       400: ValidationError
+//       403: ValidationError
+//       404: description: Not found.
 
-// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteDeleteAlertRuleGroup RouteGetAlertRuleGroupsExport RoutePutAlertRuleGroupPause RoutePutAlertRuleGroupOrder RoutePostAlertRuleGroupCopy
 type FolderUIDPathParam struct {
 	// in:path
 	FolderUID string `json:"FolderUID"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteDeleteAlertRuleGroup RoutePutAlertRuleGroupPause RoutePutAlertRuleGroupOrder RoutePostAlertRuleGroupCopy
 type RuleGroupPathParam struct {
 	// in:path
 	Group string `json:"Group"`
 }
 
+// swagger:parameters RoutePutAlertRuleGroup RouteDeleteAlertRuleGroup RoutePutAlertRuleGroupPause RoutePutAlertRuleGroupOrder
+type RuleGroupIfMatchParam struct {
+	// The ETag of the rule group the change is based on. If set, the group is only changed if it was not changed
+	// since it was read.
+	// in:header
+	IfMatch string `json:"If-Match"`
+}
+
 // swagger:parameters RoutePutAlertRuleGroup
 type AlertRuleGroupPayload struct {
 	// in:body
@@ -174,11 +445,277 @@ type AlertRuleGroupPayload struct {
 
 type AlertRuleGroupMetadata struct {
 	Interval int64 `json:"interval"`
+	// Labels of the group, which are merged into the labels of all its rules. The labels of a rule override the
+	// labels of its group with the same name. The previous labels of the group are removed from the rules.
+	// example: {"team": "sre"}
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// swagger:parameters RouteDeleteAlertRuleGroup
+type DeleteAlertRuleGroupParams struct {
+	// Delete the rule group even if its rules have alerts that are not normal or silences.
+	// in:query
+	// required:false
+	// default:false
+	Force bool `json:"force"`
+}
+
+// RuleGroupDeletion lists the alerts and silences tied to the rules of a rule group that is deleted.
+// swagger:model
+type RuleGroupDeletion struct {
+	// Deleted is false if the rule group was not deleted because it has alerts or silences and force is not set.
+	Deleted  bool                       `json:"deleted"`
+	RuleUIDs []string                   `json:"ruleUids"`
+	Alerts   []RuleGroupDeletionAlert   `json:"alerts"`
+	Silences []RuleGroupDeletionSilence `json:"silences"`
+}
+
+// RuleGroupDeletionAlert is an alert of a rule that is not normal.
+type RuleGroupDeletionAlert struct {
+	RuleUID string            `json:"ruleUid"`
+	Labels  map[string]string `json:"labels"`
+	// enum: Alerting,Pending,NoData,Error
+	State string    `json:"state"`
+	Since time.Time `json:"since"`
+}
+
+// RuleGroupDeletionSilence is a silence that is not expired and matches alerts of the rules or the rules themselves.
+type RuleGroupDeletionSilence struct {
+	ID string `json:"id"`
+	// enum: active,pending
+	State     string    `json:"state"`
+	Comment   string    `json:"comment"`
+	CreatedBy string    `json:"createdBy"`
+	EndsAt    time.Time `json:"endsAt"`
+	// RuleUIDs are the rules of the group that the silence matches.
+	RuleUIDs []string `json:"ruleUids"`
+}
+
+// swagger:parameters RoutePutAlertRulePause RoutePutAlertRuleGroupPause
+type AlertRulePausePayload struct {
+	// in:body
+	Body AlertRulePause
+}
+
+// AlertRulePause sets whether alert rules are paused. Paused rules are not evaluated.
+// swagger:model
+type AlertRulePause struct {
+	IsPaused bool `json:"isPaused"`
+}
+
+// swagger:parameters RoutePutAlertRuleProvenance
+type AlertRuleProvenancePayload struct {
+	// in:body
+	Body AlertRuleProvenance
+}
+
+// AlertRuleProvenance is the provenance of an alert rule.
+// swagger:model
+type AlertRuleProvenance struct {
+	// Provenance is api or file. Rules without provenance can be edited in the UI.
+	Provenance models.Provenance `json:"provenance"`
+}
+
+// swagger:parameters RoutePostAlertRulesProvenance
+type AlertRulesProvenancePayload struct {
+	// in:body
+	Body AlertRulesProvenance
+}
+
+// AlertRulesProvenance is the provenance of many alert rules.
+// swagger:model
+type AlertRulesProvenance struct {
+	// RuleUIDs are the UIDs of the rules whose provenance is changed.
+	// required: true
+	// example: ["a1b2c3","b2c3d4"]
+	RuleUIDs []string `json:"ruleUids"`
+	// Provenance is api or file. Rules without provenance can be edited in the UI.
+	Provenance models.Provenance `json:"provenance"`
+}
+
+// AlertRulesProvenanceResult lists the rules whose provenance was changed.
+// swagger:model
+type AlertRulesProvenanceResult struct {
+	Provenance models.Provenance `json:"provenance"`
+	// Changed are the UIDs of the rules that had another provenance.
+	Changed []string `json:"changed"`
+	// Unchanged are the UIDs of the rules that already had the provenance.
+	Unchanged []string `json:"unchanged"`
+}
+
+// swagger:parameters RoutePutAlertRuleGroupOrder
+type AlertRuleGroupOrderPayload struct {
+	// in:body
+	Body AlertRuleGroupOrder
+}
+
+// AlertRuleGroupOrder is the order of the alert rules of a rule group.
+// swagger:model
+type AlertRuleGroupOrder struct {
+	// UIDs of all the rules of the group, in the order of evaluation.
+	// required: true
+	RuleUIDs []string `json:"ruleUids"`
+}
+
+// swagger:parameters RoutePostAlertRuleGroupCopy
+type AlertRuleGroupCopyPayload struct {
+	// in:body
+	Body AlertRuleGroupCopy
+}
+
+// AlertRuleGroupCopy is the destination of the copy of a rule group.
+// swagger:model
+type AlertRuleGroupCopy struct {
+	// Organization of the copy, the organization of the group if it is not set.
+	// example: 2
+	OrgID int64 `json:"orgId,omitempty"`
+	// required: true
+	// example: production
+	FolderUID string `json:"folderUid"`
+	// Title of the copy, the title of the group if it is not set.
+	// example: eval_group_1
+	Title string `json:"title,omitempty"`
+	// DatasourceUIDs maps the UIDs of the datasources of the queries to the UIDs of the datasources of the copy.
+	// Datasources that are not mapped keep their UID.
+	// example: {"staging-prometheus": "production-prometheus"}
+	DatasourceUIDs map[string]string `json:"datasourceUids,omitempty"`
+	// Pause all the copied rules, otherwise they keep the state of the original rules.
+	IsPaused bool `json:"isPaused,omitempty"`
+	// Derive the UIDs of the copied rules from the folder, the title of the copy and the titles of the rules instead of
+	// generating random ones.
+	DeterministicUIDs bool `json:"deterministicUids,omitempty"`
 }
 
 type AlertRuleGroup struct {
-	Title     string             `json:"title"`
-	FolderUID string             `json:"folderUid"`
-	Interval  int64              `json:"interval"`
-	Rules     []models.AlertRule `json:"rules"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+	Interval  int64  `json:"interval"`
+	// Labels of the group, which are merged into the labels of all its rules.
+	Labels map[string]string  `json:"labels,omitempty"`
+	Rules  []models.AlertRule `json:"rules"`
+}
+
+// swagger:parameters RouteGetAlertRuleGroupsExport
+type AlertRulesExportParams struct {
+	// Format of the exported document, either yaml, json, hcl or prometheus. The hcl format contains
+	// grafana_rule_group resources of the Terraform provider for Grafana. The prometheus format is a Prometheus rule
+	// file for the rulers of Prometheus, Mimir or Loki, and contains the rules that query a single Prometheus or Loki
+	// datasource with a condition that can be expressed in the query. The other rules are listed in a comment.
+	// in:query
+	// required:false
+	// default:yaml
+	// enum: yaml,json,hcl,prometheus
+	Format string `json:"format"`
+	// Serve the document as a file attachment.
+	// in:query
+	// required:false
+	// default:false
+	Download bool `json:"download"`
+}
+
+// swagger:route POST /api/v1/provisioning/alert-rules/drift provisioning stable RoutePostAlertRulesDrift
+//
+// Compare the rule groups of a file provisioning document with the stored rules, to detect the changes made to
+// provisioned rules outside of their provisioning files. Nothing is changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRulesDrift
+//       400: ValidationError
+
+// swagger:parameters RoutePostAlertRulesDrift
+type AlertRulesDriftPayload struct {
+	// in:body
+	Body AlertRulesExport
+}
+
+const (
+	// AlertRuleDriftChanged is the status of the rules whose stored definition differs from the document.
+	AlertRuleDriftChanged = "changed"
+	// AlertRuleDriftMissing is the status of the rules of the document that are not stored.
+	AlertRuleDriftMissing = "missing"
+	// AlertRuleDriftProvenance is the status of the rules of the document that are stored without the file
+	// provenance, and can therefore be changed outside of their provisioning files.
+	AlertRuleDriftProvenance = "provenance"
+	// AlertRuleDriftUndeclared is the status of the rules provisioned from files into a group of the document that
+	// the document does not declare.
+	AlertRuleDriftUndeclared = "undeclared"
+)
+
+// AlertRulesDrift lists the rules that differ between a file provisioning document and the stored rules.
+// swagger:model
+type AlertRulesDrift struct {
+	Drifted bool             `json:"drifted"`
+	Rules   []AlertRuleDrift `json:"rules"`
+}
+
+// AlertRuleDrift is a rule that differs between a file provisioning document and the stored rules.
+type AlertRuleDrift struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	// enum: changed,missing,provenance,undeclared
+	Status string `json:"status"`
+	// Provenance of the stored rule.
+	Provenance models.Provenance `json:"provenance,omitempty"`
+	// Changes are the fields of the stored rule that differ from the document.
+	Changes []AlertRuleFieldDrift `json:"changes,omitempty"`
+}
+
+// AlertRuleFieldDrift is a field of a stored rule that differs from a file provisioning document.
+type AlertRuleFieldDrift struct {
+	// example: Data[1].Model
+	Field  string      `json:"field"`
+	File   interface{} `json:"file"`
+	Stored interface{} `json:"stored"`
+}
+
+// AlertRulesExport is a file provisioning document containing alert rule groups.
+// swagger:model
+type AlertRulesExport struct {
+	APIVersion int64                  `json:"apiVersion" yaml:"apiVersion"`
+	Groups     []AlertRuleGroupExport `json:"groups" yaml:"groups"`
+}
+
+// AlertRuleGroupExport is a rule group in the file provisioning format.
+type AlertRuleGroupExport struct {
+	OrgID     int64          `json:"orgId" yaml:"orgId"`
+	Name      string         `json:"name" yaml:"name"`
+	FolderUID string         `json:"folderUid" yaml:"folderUid"`
+	Interval  model.Duration `json:"interval" yaml:"interval"`
+	// Labels of the group, which are merged into the labels of all its rules when it is provisioned. The labels of a
+	// rule override the labels of its group with the same name.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Rules  []AlertRuleExport `json:"rules" yaml:"rules"`
+}
+
+// AlertRuleExport is an alert rule in the file provisioning format.
+type AlertRuleExport struct {
+	UID          string                     `json:"uid" yaml:"uid"`
+	Title        string                     `json:"title" yaml:"title"`
+	Condition    string                     `json:"condition" yaml:"condition"`
+	Data         []AlertQueryExport         `json:"data" yaml:"data"`
+	DashboardUID string                     `json:"dashboardUid,omitempty" yaml:"dashboardUid,omitempty"`
+	PanelID      int64                      `json:"panelId,omitempty" yaml:"panelId,omitempty"`
+	NoDataState  models.NoDataState         `json:"noDataState" yaml:"noDataState"`
+	ExecErrState models.ExecutionErrorState `json:"execErrState" yaml:"execErrState"`
+	For          model.Duration             `json:"for" yaml:"for"`
+	Annotations  map[string]string          `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Labels       map[string]string          `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Webhooks     []models.AlertRuleWebhook  `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	IsPaused     bool                       `json:"isPaused,omitempty" yaml:"isPaused,omitempty"`
+	MaxInstances int64                      `json:"maxInstances,omitempty" yaml:"maxInstances,omitempty"`
+}
+
+// AlertQueryExport is a query or expression of an alert rule in the file provisioning format. The model is an
+// object rather than raw JSON, so that it stays readable in YAML documents.
+type AlertQueryExport struct {
+	RefID             string                   `json:"refId" yaml:"refId"`
+	QueryType         string                   `json:"queryType,omitempty" yaml:"queryType,omitempty"`
+	RelativeTimeRange models.RelativeTimeRange `json:"relativeTimeRange" yaml:"relativeTimeRange"`
+	DatasourceUID     string                   `json:"datasourceUid" yaml:"datasourceUid"`
+	Model             map[string]interface{}   `json:"model" yaml:"model"`
 }
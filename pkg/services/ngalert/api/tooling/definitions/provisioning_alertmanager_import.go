@@ -0,0 +1,56 @@
+package definitions
+
+// swagger:route POST /api/v1/provisioning/alertmanager/import provisioning stable RoutePostAlertmanagerImport
+//
+// Import the receivers, routes and mute time intervals of a Prometheus Alertmanager configuration.
+//
+// The receivers are imported as contact points, the mute time intervals as mute timings, and the routing tree
+// replaces the notification policies of the organization. The integrations and settings that Grafana does not support
+// are reported and left out. Nothing is imported if a contact point or mute timing of the same name exists.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertmanagerImportResult
+//       400: ValidationError
+//       409: AlertmanagerImportResult
+
+// swagger:parameters RoutePostAlertmanagerImport
+type AlertmanagerImportParams struct {
+	// Report what would be imported without saving anything.
+	// in:query
+	// required:false
+	// default:false
+	DryRun bool `json:"dryRun"`
+	// in:body
+	Body AlertmanagerImport
+}
+
+// swagger:model
+type AlertmanagerImport struct {
+	// Config is the content of the alertmanager.yml file.
+	// required: true
+	Config string `json:"config"`
+}
+
+// swagger:model
+type AlertmanagerImportResult struct {
+	// DryRun is true if nothing was saved.
+	DryRun        bool                             `json:"dryRun"`
+	ContactPoints []AlertmanagerImportContactPoint `json:"contactPoints"`
+	MuteTimings   []string                         `json:"muteTimings"`
+	// PoliciesImported is true if the routing tree replaces the notification policies.
+	PoliciesImported bool `json:"policiesImported"`
+	// Conflicts are the contact points and mute timings that exist with the same name.
+	Conflicts []string `json:"conflicts,omitempty"`
+	// Unsupported are the parts of the configuration that are not imported.
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+// AlertmanagerImportContactPoint is a contact point created from a receiver.
+type AlertmanagerImportContactPoint struct {
+	Name string `json:"name"`
+	// Types are the types of the integrations of the contact point.
+	Types []string `json:"types"`
+}
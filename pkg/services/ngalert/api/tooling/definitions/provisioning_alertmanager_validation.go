@@ -0,0 +1,30 @@
+package definitions
+
+// swagger:route POST /api/v1/provisioning/alertmanager/config/validate provisioning stable RoutePostAlertmanagerConfigValidate
+//
+// Check an Alertmanager configuration without saving it.
+//
+// The configuration is parsed and checked as it is when it is saved, but all the problems that are found are
+// returned instead of the first one. A configuration that cannot be parsed is rejected with a 400 response.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertmanagerConfigValidation
+//       400: ValidationError
+//       404: AlertManagerNotFound
+
+// swagger:parameters RoutePostAlertmanagerConfigValidate
+type AlertmanagerConfigValidateParams struct {
+	// in:body
+	Body PostableUserConfig
+}
+
+// swagger:model
+type AlertmanagerConfigValidation struct {
+	// Valid is true if the configuration can be saved.
+	Valid bool `json:"valid"`
+	// Errors are the problems that prevent the configuration from being saved.
+	Errors []string `json:"errors"`
+}
@@ -0,0 +1,59 @@
+package definitions
+
+// swagger:route POST /api/v1/provisioning/batch provisioning stable RoutePostProvisioningBatch
+//
+// Apply several changes to the contact points, message templates, mute timings and notification policies at once.
+//
+// The operations are applied in order, each one to the result of the previous ones, so a contact point can be created
+// and used by the notification policies of the same batch. The resulting configuration is saved once, and nothing is
+// saved if an operation fails.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ProvisioningBatchResult
+//       400: ValidationError
+//       404: description: Not found.
+//       409: description: The configuration was changed while the batch was applied, or a mute timing to delete is used by notification policies.
+
+// swagger:parameters RoutePostProvisioningBatch
+type ProvisioningBatchParams struct {
+	// in:body
+	Body ProvisioningBatch
+}
+
+// ProvisioningBatchAction is what an operation of a batch does with its object.
+type ProvisioningBatchAction string
+
+const (
+	ProvisioningBatchCreate ProvisioningBatchAction = "create"
+	ProvisioningBatchUpdate ProvisioningBatchAction = "update"
+	ProvisioningBatchDelete ProvisioningBatchAction = "delete"
+)
+
+// swagger:model
+type ProvisioningBatch struct {
+	// Operations are applied in order.
+	// required: true
+	Operations []ProvisioningBatchOperation `json:"operations"`
+}
+
+// ProvisioningBatchOperation is a change to one object. Exactly one object is set. Contact points are updated and
+// deleted by UID, message templates and mute timings by name. The notification policies can only be updated, a
+// message template is created or updated with either action.
+type ProvisioningBatchOperation struct {
+	// required: true
+	// enum: create,update,delete
+	Action       ProvisioningBatchAction `json:"action"`
+	ContactPoint *EmbeddedContactPoint   `json:"contactPoint,omitempty"`
+	Template     *MessageTemplate        `json:"template,omitempty"`
+	MuteTiming   *MuteTimeInterval       `json:"muteTiming,omitempty"`
+	Policies     *Route                  `json:"policies,omitempty"`
+}
+
+// swagger:model
+type ProvisioningBatchResult struct {
+	// ContactPoints are the contact points created or updated by the batch, in the order of the operations.
+	ContactPoints []EmbeddedContactPoint `json:"contactPoints"`
+}
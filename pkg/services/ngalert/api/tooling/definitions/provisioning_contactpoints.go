@@ -14,6 +14,13 @@ import (
 //     Responses:
 //       200: ContactPoints
 
+// swagger:route GET /api/v1/provisioning/contact-points/duplicates provisioning stable RouteGetContactPointDuplicates
+//
+// Get the groups of contact points with identical settings, which could be consolidated into one.
+//
+//     Responses:
+//       200: ContactPointDuplicates
+
 // swagger:route POST /api/v1/provisioning/contact-points provisioning stable RoutePostContactpoints
 //
 // Create a contact point.
@@ -84,6 +91,23 @@ type EmbeddedContactPoint struct {
 	DisableResolveMessage bool `json:"disableResolveMessage"`
 	// readonly: true
 	Provenance string `json:"provenance,omitempty"`
+	// UIDs of the other contact points with identical settings. Only set in the response of creating a contact point.
+	// readonly: true
+	DuplicateOf []string `json:"duplicateOf,omitempty"`
+}
+
+// swagger:model
+type ContactPointDuplicates []ContactPointDuplicateGroup
+
+type ContactPointDuplicateGroup struct {
+	// example: slack
+	Type          string                  `json:"type"`
+	ContactPoints []ContactPointReference `json:"contactPoints"`
+}
+
+type ContactPointReference struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
 }
 
 const RedactedValue = "[REDACTED]"
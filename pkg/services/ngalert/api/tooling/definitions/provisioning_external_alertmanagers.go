@@ -0,0 +1,37 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /api/v1/provisioning/alertmanager/external/status provisioning stable RouteGetExternalAlertmanagerSyncStatus
+//
+// Get the status of the push of the contact points, templates, mute timings and notification policies to the external Alertmanagers.
+//
+// When the organization sends its alerts to external Alertmanagers, such as the Alertmanager of Mimir, every change
+// of its notification configuration is translated and pushed to their configuration API. The status is the one of the
+// pushes made since Grafana started.
+//
+//     Responses:
+//       200: ExternalAlertmanagerSyncStatus
+
+// swagger:model
+type ExternalAlertmanagerSyncStatus struct {
+	// Enabled is true if the organization sends its alerts to external Alertmanagers.
+	Enabled bool `json:"enabled"`
+	// Alertmanagers are the results of the last push to each external Alertmanager.
+	Alertmanagers []ExternalAlertmanagerSync `json:"alertmanagers"`
+	// Unsupported are the parts of the configuration that were left out of the last push because the external
+	// Alertmanagers do not support them.
+	Unsupported []string `json:"unsupported"`
+}
+
+// ExternalAlertmanagerSync is the result of the last push to an external Alertmanager.
+type ExternalAlertmanagerSync struct {
+	// URL of the Alertmanager, without its credentials.
+	URL string `json:"url"`
+	// LastAttempt is the time of the last push.
+	LastAttempt time.Time `json:"lastAttempt"`
+	// LastSuccess is the time of the last successful push, it is absent if no push succeeded.
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	// Error of the last push, it is empty if the push succeeded.
+	Error string `json:"error,omitempty"`
+}
@@ -2,7 +2,6 @@ package definitions
 
 import (
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
-	"github.com/prometheus/alertmanager/config"
 )
 
 // swagger:route GET /api/v1/provisioning/mute-timings provisioning stable RouteGetMuteTimings
@@ -46,30 +45,107 @@ import (
 //
 // Delete a mute timing.
 //
+// A mute timing that is used by notification policies is only deleted if force is set.
+//
 //     Responses:
 //       204: description: The mute timing was deleted successfully.
+//       409: description: The mute timing is used by notification policies.
+
+// swagger:route POST /api/v1/provisioning/mute-timings/{name}/rename provisioning stable RoutePostMuteTimingRename
+//
+// Rename a mute timing.
+//
+// The notification policies that use the mute timing are changed to use the new name.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: MuteTimeInterval
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/mute-timings/export provisioning stable RouteGetMuteTimingsExport
+//
+// Export all the mute timings in the file provisioning format.
+//
+//     Produces:
+//     - application/yaml
+//     - application/json
+//
+//     Responses:
+//       200: MuteTimingsExport
+//       400: ValidationError
 
 // swagger:route
 
 // swagger:model
 type MuteTimings []MuteTimeInterval
 
-// swagger:parameters RouteGetTemplate RouteGetMuteTiming RoutePutMuteTiming stable RouteDeleteMuteTiming
+// swagger:parameters RouteGetMuteTimingsExport
+type MuteTimingsExportParams struct {
+	// Format of the exported document, either yaml or json.
+	// in:query
+	// required:false
+	// default:yaml
+	Format string `json:"format"`
+	// Serve the document as a file attachment.
+	// in:query
+	// required:false
+	// default:false
+	Download bool `json:"download"`
+}
+
+// MuteTimingsExport is a file provisioning document containing mute timings.
+// swagger:model
+type MuteTimingsExport struct {
+	APIVersion int64                   `json:"apiVersion" yaml:"apiVersion"`
+	MuteTimes  []MuteTimingExportEntry `json:"muteTimes" yaml:"muteTimes"`
+}
+
+// MuteTimingExportEntry is a mute timing of an organization in the file provisioning format.
+type MuteTimingExportEntry struct {
+	OrgID                  int64 `json:"orgId" yaml:"orgId"`
+	MuteTimeIntervalConfig `yaml:",inline"`
+}
+
+// swagger:parameters RouteGetTemplate RouteGetMuteTiming RoutePutMuteTiming stable RouteDeleteMuteTiming RoutePostMuteTimingRename
 type RouteGetMuteTimingParam struct {
 	// Mute timing name
 	// in:path
 	Name string `json:"name"`
 }
 
+// swagger:parameters RouteDeleteMuteTiming
+type DeleteMuteTimingParams struct {
+	// Remove the mute timing from the notification policies using it, instead of rejecting the deletion.
+	// in:query
+	// required:false
+	// default:false
+	Force bool `json:"force"`
+}
+
 // swagger:parameters RoutePostMuteTiming RoutePutMuteTiming
 type MuteTimingPayload struct {
 	// in:body
 	Body MuteTimeInterval
 }
 
+// swagger:parameters RoutePostMuteTimingRename
+type MuteTimingRenameParams struct {
+	// in:body
+	Body MuteTimingRename
+}
+
+// swagger:model
+type MuteTimingRename struct {
+	// Name is the new name of the mute timing.
+	Name string `json:"name"`
+}
+
 // swagger:model
 type MuteTimeInterval struct {
-	config.MuteTimeInterval
+	MuteTimeIntervalConfig
 	Provenance models.Provenance `json:"provenance,omitempty"`
 }
 
@@ -78,5 +154,5 @@ func (mt *MuteTimeInterval) ResourceType() string {
 }
 
 func (mt *MuteTimeInterval) ResourceID() string {
-	return mt.MuteTimeInterval.Name
+	return mt.MuteTimeIntervalConfig.Name
 }
@@ -1,9 +1,17 @@
 package definitions
 
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
 // swagger:route GET /api/v1/provisioning/policies provisioning stable RouteGetPolicyTree
 //
 // Get the notification policy tree.
 //
+// The ETag header of the response is the hash of the configuration the tree was read from.
+//
 //     Responses:
 //       200: Route
 //         description: The currently active notification routing tree
@@ -18,10 +26,347 @@ package definitions
 //     Responses:
 //       202: Ack
 //       400: ValidationError
+//       409: description: The configuration was changed since it was read.
 
 // swagger:parameters RoutePutPolicyTree
 type Policytree struct {
 	// The new notification routing tree to use
 	// in:body
 	Body Route
+	// The ETag of the policy tree the update is based on. If set, the tree is only updated if the
+	// configuration has not changed since it was read.
+	// in:header
+	IfMatch string `json:"If-Match"`
+}
+
+// swagger:route DELETE /api/v1/provisioning/policies provisioning stable RouteResetPolicyTree
+//
+// Resets the notification policy tree to the default.
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+
+// swagger:parameters RouteResetPolicyTree
+type PolicyTreeResetParams struct {
+	// Either "full" to replace the whole tree with the default one, or "provisioned" to restore only the
+	// provisioned policies to their defaults and keep the policies that are not provisioned.
+	// in:query
+	// required: false
+	// default: full
+	Mode string `json:"mode"`
+}
+
+// swagger:route GET /api/v1/provisioning/policies/effective provisioning stable RouteGetEffectivePolicies
+//
+// Get every notification policy with the values that apply to it once they are inherited from its parents.
+//
+//     Responses:
+//       200: EffectivePolicies
+//       404: description: Not found.
+
+// swagger:model
+type EffectivePolicies []EffectivePolicy
+
+// EffectivePolicy is a notification policy with the values that apply to the alerts it routes, after they are
+// inherited from its parents or taken from the defaults.
+// swagger:model
+type EffectivePolicy struct {
+	// ID of the policy, it is empty for the root policy.
+	ID string `json:"id,omitempty"`
+	// ParentID is the ID of the parent policy, it is empty for the root policy and its direct children.
+	ParentID string `json:"parent_id,omitempty"`
+	// ObjectMatchers are the matchers of the policy and of all its parents, an alert must match all of them to
+	// reach the policy.
+	ObjectMatchers ObjectMatchers `json:"object_matchers,omitempty"`
+	Receiver       string         `json:"receiver"`
+	GroupBy        []string       `json:"group_by"`
+	GroupWait      model.Duration `json:"group_wait"`
+	GroupInterval  model.Duration `json:"group_interval"`
+	RepeatInterval model.Duration `json:"repeat_interval"`
+	Continue       bool           `json:"continue"`
+}
+
+// swagger:route GET /api/v1/provisioning/policies/search provisioning stable RouteGetPoliciesByReceiver
+//
+// Get the notification policies that send their alerts to a contact point.
+//
+//     Responses:
+//       200: ReceiverPolicies
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RouteGetPoliciesByReceiver
+type PoliciesByReceiverParams struct {
+	// The name of the contact point.
+	// in:query
+	// required: true
+	Receiver string `json:"receiver"`
+}
+
+// swagger:model
+type ReceiverPolicies []ReceiverPolicy
+
+// ReceiverPolicy is a notification policy that sends its alerts to a contact point.
+// swagger:model
+type ReceiverPolicy struct {
+	// Path is the position of the policy in the tree: the index of the policy and of each of its parents among
+	// their siblings, starting from the root. It is empty for the root policy.
+	Path []int `json:"path"`
+	// ParentIDs are the IDs of the parents of the policy below the root, starting from the top.
+	ParentIDs []string `json:"parent_ids"`
+	// Inherited is set if the policy has no contact point of its own and inherits it from its parent.
+	Inherited bool `json:"inherited"`
+	// Route is the policy without its nested policies.
+	Route Route `json:"route"`
+}
+
+// swagger:route GET /api/v1/provisioning/policies/graph provisioning stable RouteGetPolicyTreeGraph
+//
+// Export the notification policy tree with its contact points and mute timings as a graph.
+//
+//     Produces:
+//     - application/json
+//     - text/vnd.graphviz
+//
+//     Responses:
+//       200: PolicyTreeGraph
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RouteGetPolicyTreeGraph
+type PolicyTreeGraphParams struct {
+	// Either "json" for a graph document, or "dot" for a Graphviz DOT document.
+	// in:query
+	// required: false
+	// default: json
+	Format string `json:"format"`
+}
+
+const (
+	PolicyTreeGraphNodePolicy       = "policy"
+	PolicyTreeGraphNodeContactPoint = "contact_point"
+	PolicyTreeGraphNodeMuteTiming   = "mute_timing"
+
+	PolicyTreeGraphEdgeNested       = "nested"
+	PolicyTreeGraphEdgeContactPoint = "contact_point"
+	PolicyTreeGraphEdgeMuteTiming   = "mute_timing"
+	PolicyTreeGraphEdgeActiveTiming = "active_timing"
+)
+
+// PolicyTreeGraph is the routing topology of an organization: the notification policies, the contact points and
+// the mute timings, and how they refer to each other.
+// swagger:model
+type PolicyTreeGraph struct {
+	Nodes []PolicyTreeGraphNode `json:"nodes"`
+	Edges []PolicyTreeGraphEdge `json:"edges"`
+}
+
+// swagger:model
+type PolicyTreeGraphNode struct {
+	// ID of the node, unique within the graph.
+	ID string `json:"id"`
+	// Kind is either "policy", "contact_point" or "mute_timing".
+	Kind  string `json:"kind"`
+	Label string `json:"label"`
+	// Metadata are the settings of the policy, contact point or mute timing the node stands for.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// swagger:model
+type PolicyTreeGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Kind is "nested" from a policy to its nested policies, "contact_point" from a policy to the contact point
+	// it sets, and "mute_timing" or "active_timing" from a policy to the mute timings it uses.
+	Kind string `json:"kind"`
+}
+
+// swagger:route POST /api/v1/provisioning/policies/lint provisioning stable RoutePostPolicyTreeLint
+//
+// Checks the label names used by the matchers of a notification policy tree.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: PolicyTreeLintWarnings
+
+// swagger:parameters RoutePostPolicyTreeLint
+type PolicyTreeLintParams struct {
+	// The notification routing tree to check
+	// in:body
+	Body Route
+}
+
+// swagger:model
+type PolicyTreeLintWarnings []PolicyTreeLintWarning
+
+// PolicyTreeLintWarning is a matcher of a notification policy that is unlikely to match any alert.
+// swagger:model
+type PolicyTreeLintWarning struct {
+	// PolicyID is the ID of the policy with the matcher. It is empty for the root policy and for new policies.
+	PolicyID string `json:"policyId,omitempty"`
+	Receiver string `json:"receiver"`
+	Matcher  string `json:"matcher"`
+	Label    string `json:"label"`
+	Message  string `json:"message"`
+}
+
+// swagger:route POST /api/v1/provisioning/policies/{ID}/move provisioning stable RoutePostPolicyMove
+//
+// Moves a nested notification policy to another position among its siblings.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RoutePostPolicyMove
+type PolicyMoveParams struct {
+	// ID of the notification policy
+	// in:path
+	// required: true
+	ID string
+	// in:body
+	Body PolicyMove
+}
+
+// swagger:model
+type PolicyMove struct {
+	// Index is the new position of the policy among the nested policies of its parent, starting at 0.
+	Index int `json:"index"`
+}
+
+// swagger:route POST /api/v1/provisioning/policies/repoint provisioning stable RoutePostPolicyRepoint
+//
+// Makes all notification policies that use a contact point use another one instead.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: PolicyRepointResult
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RoutePostPolicyRepoint
+type PolicyRepointParams struct {
+	// in:body
+	Body PolicyRepoint
+}
+
+// swagger:model
+type PolicyRepoint struct {
+	// From is the name of the contact point used by the policies.
+	From string `json:"from"`
+	// To is the name of the contact point the policies use instead.
+	To string `json:"to"`
+}
+
+// swagger:model
+type PolicyRepointResult struct {
+	// Updated is the number of policies that were changed.
+	Updated int `json:"updated"`
+}
+
+// swagger:route GET /api/v1/provisioning/policies/templates provisioning stable RouteGetPolicyTreeTemplates
+//
+// Get the templates a notification policy tree can be created from.
+//
+//     Responses:
+//       200: PolicyTreeTemplates
+
+// swagger:route POST /api/v1/provisioning/policies/templates/{Name} provisioning stable RoutePostPolicyTreeTemplate
+//
+// Replaces the notification policy tree with one created from a template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: Route
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RoutePostPolicyTreeTemplate
+type PolicyTreeTemplateParams struct {
+	// Name of the template
+	// in:path
+	// required: true
+	Name string
+	// in:body
+	Body PolicyTreeTemplateValues
+}
+
+// swagger:model
+type PolicyTreeTemplates []PolicyTreeTemplate
+
+// PolicyTreeTemplate is a starter notification policy tree with placeholders that are filled in when the
+// tree is created.
+// swagger:model
+type PolicyTreeTemplate struct {
+	Name         string                          `json:"name"`
+	Description  string                          `json:"description"`
+	Placeholders []PolicyTreeTemplatePlaceholder `json:"placeholders"`
+}
+
+// swagger:model
+type PolicyTreeTemplatePlaceholder struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Default is the value of the placeholder when none is given. Placeholders without a default are required.
+	Default string `json:"default,omitempty"`
+}
+
+// swagger:model
+type PolicyTreeTemplateValues struct {
+	// Values of the placeholders of the template by name.
+	Values map[string]string `json:"values"`
+}
+
+// swagger:route GET /api/v1/provisioning/policies/history provisioning stable RouteGetPolicyTreeHistory
+//
+// Get the previous versions of the notification policy tree, newest first.
+//
+//     Responses:
+//       200: NotificationPolicyVersions
+
+// swagger:route GET /api/v1/provisioning/policies/history/{Version} provisioning stable RouteGetPolicyTreeVersion
+//
+// Get a previous version of the notification policy tree.
+//
+//     Responses:
+//       200: NotificationPolicyVersion
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/policies/history/{Version}/rollback provisioning stable RoutePostPolicyTreeRollback
+//
+// Replaces the notification policy tree with a previous version.
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RouteGetPolicyTreeVersion RoutePostPolicyTreeRollback
+type PolicyTreeVersionParam struct {
+	// Version of the notification policy tree
+	// in:path
+	// required: true
+	Version int64
+}
+
+// swagger:model
+type NotificationPolicyVersions []NotificationPolicyVersion
+
+// swagger:model
+type NotificationPolicyVersion struct {
+	Version int64     `json:"version"`
+	Created time.Time `json:"created"`
+	// Policy is the notification policy tree, it is only set when a single version is requested.
+	Policy *Route `json:"policy,omitempty"`
 }
@@ -0,0 +1,91 @@
+package definitions
+
+// swagger:route POST /api/v1/provisioning/prometheus/import provisioning stable RoutePostPrometheusRulesImport
+//
+// Import the alerting rules of a Prometheus or Loki rule file as Grafana-managed alert rules.
+//
+// Every rule group of the file is imported as a rule group of the folder, and every alerting rule as an alert rule
+// that queries the datasource with its expression and fires for every series that the expression returns. The
+// duration, labels and annotations of the rules are kept. Recording rules are reported and left out.
+//
+// The UIDs of the imported rules are derived from the folder, the group and the name of the rules, so importing the
+// file again updates the rules instead of creating them again. The rules of the groups that are not in the file are
+// kept.
+//
+// The import fails if the folder does not exist, unless the folder is described in the request. The folder is then
+// created with the UID of the request and the title and permissions of the description, and it is deleted again if
+// the rules cannot be imported.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: PrometheusRulesImportResult
+//       400: ValidationError
+//       403: ValidationError
+
+// swagger:parameters RoutePostPrometheusRulesImport
+type PrometheusRulesImportParams struct {
+	// Report what would be imported without saving anything.
+	// in:query
+	// required:false
+	// default:false
+	DryRun bool `json:"dryRun"`
+	// in:body
+	Body PrometheusRulesImport
+}
+
+// swagger:model
+type PrometheusRulesImport struct {
+	// Config is the content of the rule file.
+	// required: true
+	Config string `json:"config"`
+	// FolderUID is the folder the rule groups are imported into.
+	// required: true
+	FolderUID string `json:"folderUid"`
+	// DatasourceUID is the Prometheus or Loki datasource queried by the imported rules.
+	// required: true
+	DatasourceUID string `json:"datasourceUid"`
+	// Folder describes the folder that is created if there is no folder with the UID FolderUID.
+	Folder *RuleImportFolder `json:"folder,omitempty"`
+}
+
+// RuleImportFolder describes a folder that is created when alert rules are imported into it.
+// swagger:model
+type RuleImportFolder struct {
+	// required: true
+	// example: Payments
+	Title string `json:"title"`
+	// Permissions of the folder. They replace the default permissions of new folders if they are set.
+	Permissions []RuleImportFolderPermission `json:"permissions,omitempty"`
+}
+
+// RuleImportFolderPermission grants a permission on a folder to a user, a team or the users of a role.
+type RuleImportFolderPermission struct {
+	UserID int64 `json:"userId,omitempty"`
+	TeamID int64 `json:"teamId,omitempty"`
+	// Role is Viewer, Editor or Admin.
+	Role string `json:"role,omitempty"`
+	// Permission is View, Edit or Admin.
+	// required: true
+	Permission string `json:"permission"`
+}
+
+// swagger:model
+type PrometheusRulesImportResult struct {
+	// DryRun is true if nothing was saved.
+	DryRun bool `json:"dryRun"`
+	// FolderCreated is true if the folder was created, or would be if nothing was saved.
+	FolderCreated bool `json:"folderCreated"`
+	// Groups are the imported rule groups in the file provisioning format.
+	Groups []AlertRuleGroupExport `json:"groups"`
+	// Skipped are the rules of the file that are not imported.
+	Skipped []PrometheusRulesImportSkippedRule `json:"skipped"`
+}
+
+type PrometheusRulesImportSkippedRule struct {
+	Group string `json:"group"`
+	// Name is the name of the recording rule or alert.
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,119 @@
+package definitions
+
+// swagger:route GET /api/v1/provisioning/rule-templates provisioning stable RouteGetRuleTemplates
+//
+// Get all rule templates.
+//
+//     Responses:
+//       200: RuleTemplates
+
+// swagger:route GET /api/v1/provisioning/rule-templates/{name} provisioning stable RouteGetRuleTemplate
+//
+// Get a rule template.
+//
+//     Responses:
+//       200: RuleTemplate
+//       404: description: Not found.
+
+// swagger:route PUT /api/v1/provisioning/rule-templates/{name} provisioning stable RoutePutRuleTemplate
+//
+// Create or update a rule template.
+//
+// The rule of the template is in the file provisioning format. Its strings can reference the variables of the
+// template as ${name}. A string that is only a reference is replaced by the value of the variable, which keeps
+// its type, so that numbers such as thresholds can be set in the models of the queries.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: RuleTemplate
+//       400: ValidationError
+
+// swagger:route DELETE /api/v1/provisioning/rule-templates/{name} provisioning stable RouteDeleteRuleTemplate
+//
+// Delete a rule template. The rules created from the template are not changed.
+//
+//     Responses:
+//       204: description: The rule template was deleted successfully.
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/rule-templates/{name}/instantiate provisioning stable RoutePostRuleTemplateInstantiate
+//
+// Create an alert rule from a rule template, with the values of its variables.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: AlertRule
+//       400: ValidationError
+//       403: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RouteGetRuleTemplate RoutePutRuleTemplate RouteDeleteRuleTemplate RoutePostRuleTemplateInstantiate
+type RuleTemplateParam struct {
+	// Rule template name
+	// in:path
+	Name string `json:"name"`
+}
+
+// swagger:parameters RoutePutRuleTemplate
+type RuleTemplatePayload struct {
+	// in:body
+	Body RuleTemplate
+}
+
+// swagger:parameters RoutePostRuleTemplateInstantiate
+type RuleTemplateInstancePayload struct {
+	// in:body
+	Body RuleTemplateInstance
+}
+
+const (
+	// RuleTemplateVariableString is the type of the variables whose values are strings.
+	RuleTemplateVariableString = "string"
+	// RuleTemplateVariableNumber is the type of the variables whose values are numbers, such as thresholds.
+	RuleTemplateVariableNumber = "number"
+	// RuleTemplateVariableDatasource is the type of the variables whose values are UIDs of data sources.
+	RuleTemplateVariableDatasource = "datasource"
+	// RuleTemplateVariableLabel is the type of the variables whose values are label values.
+	RuleTemplateVariableLabel = "label"
+)
+
+// RuleTemplate is an alert rule with variables, that is instantiated into rules by setting the values of the
+// variables.
+// swagger:model
+type RuleTemplate struct {
+	// The name is set from the path when the template is saved.
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Variables   []RuleTemplateVariable `json:"variables"`
+	Rule        AlertRuleExport        `json:"rule"`
+}
+
+// RuleTemplateVariable is a variable of a rule template.
+type RuleTemplateVariable struct {
+	// example: threshold
+	Name string `json:"name"`
+	// enum: string,number,datasource,label
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	// Default is the value of the variable when it is not set. Variables without default must be set.
+	Default interface{} `json:"default,omitempty"`
+}
+
+// swagger:model
+type RuleTemplates []RuleTemplate
+
+// RuleTemplateInstance is where and with which values a rule template is instantiated.
+type RuleTemplateInstance struct {
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	// UID of the rule. A UID is generated if it is not set.
+	UID string `json:"uid,omitempty"`
+	// Title of the rule, which overrides the title of the template.
+	Title string `json:"title,omitempty"`
+	// example: {"threshold": 80, "team": "payments"}
+	Values map[string]interface{} `json:"values"`
+}
@@ -37,8 +37,74 @@ import (
 //
 //     Responses:
 //       204: description: The template was deleted successfully.
+//       400: ValidationError
+
+// swagger:route GET /api/v1/provisioning/templates/{name}/usage provisioning stable RouteGetTemplateUsage
+//
+// Get the contact points and other templates that invoke a message template.
+//
+// A contact point invokes a template if one of its settings, such as the title or the message, invokes the
+// template or another template that invokes it.
+//
+//     Responses:
+//       200: TemplateUsage
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/templates/{name}/rename provisioning stable RoutePostTemplateRename
+//
+// Rename a message template.
+//
+// The template actions that invoke the message template, in other templates and in the settings of contact
+// points, are changed to use the new name. Templates shared by all organizations cannot be renamed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: MessageTemplate
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/templates/defaults provisioning stable RouteGetDefaultTemplates
+//
+// Get the message templates and the defaults of the templated contact point settings that Grafana provides.
+//
+// The templates can be invoked from message templates and contact point settings, or copied into message
+// templates to customize them. The notifier defaults are used for the templated settings of contact points that
+// are not set.
+//
+//     Responses:
+//       200: DefaultTemplates
+
+// swagger:route GET /api/v1/provisioning/templates/export provisioning stable RouteGetTemplatesExport
+//
+// Export the message templates of the organization in the file provisioning format.
+//
+//     Produces:
+//     - application/yaml
+//     - application/json
+//
+//     Responses:
+//       200: TemplatesExport
+//       400: ValidationError
 
-// swagger:parameters RouteGetTemplate RoutePutTemplate RouteDeleteTemplate
+// swagger:route POST /api/v1/provisioning/templates/import provisioning stable RoutePostTemplatesImport
+//
+// Import message templates from a file provisioning document in JSON format.
+//
+// Templates that exist with a different content and were not created with the provisioning API, such as
+// templates created in the UI, are reported as conflicts. Nothing is imported if there are conflicts, unless
+// overwrite is set to replace the templates created in the UI.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: TemplatesImportResult
+//       400: ValidationError
+//       409: TemplatesImportResult
+
+// swagger:parameters RouteGetTemplate RoutePutTemplate RouteDeleteTemplate RouteGetTemplateUsage RoutePostTemplateRename
 type RouteGetTemplateParam struct {
 	// Template Name
 	// in:path
@@ -50,6 +116,9 @@ type MessageTemplate struct {
 	Name       string            `json:"name"`
 	Template   string            `json:"template"`
 	Provenance models.Provenance `json:"provenance,omitempty"`
+	// Global is true for templates that are shared by all organizations. They cannot be changed or deleted, but
+	// an organization can save a template with the same name that is used instead.
+	Global bool `json:"global,omitempty"`
 }
 
 // swagger:model
@@ -65,6 +134,126 @@ type MessageTemplatePayload struct {
 	Body MessageTemplateContent
 }
 
+// swagger:parameters RoutePostTemplateRename
+type MessageTemplateRenameParams struct {
+	// in:body
+	Body MessageTemplateRename
+}
+
+// swagger:model
+type MessageTemplateRename struct {
+	// Name is the new name of the message template.
+	Name string `json:"name"`
+}
+
+// TemplateUsage is what invokes a message template.
+// swagger:model
+type DefaultTemplates struct {
+	Templates []DefaultTemplate          `json:"templates"`
+	Notifiers []NotifierDefaultTemplates `json:"notifiers"`
+}
+
+type DefaultTemplate struct {
+	Name string `json:"name"`
+	// The definition of the template, from its define action to the matching end action.
+	Template string `json:"template"`
+}
+
+type NotifierDefaultTemplates struct {
+	// The type of the contact point, such as slack.
+	Type string `json:"type"`
+	// The defaults of the templated settings, by setting name.
+	// example: {"title": "{{ template \"default.title\" . }}", "text": "{{ template \"default.message\" . }}"}
+	Settings map[string]string `json:"settings"`
+}
+
+// swagger:model
+type TemplateUsage struct {
+	Name string `json:"name"`
+	// Templates are the names of the other templates that invoke the template, directly or through other templates.
+	Templates []string `json:"templates"`
+	// ContactPoints are the contact points that invoke the template.
+	ContactPoints []TemplateContactPointUsage `json:"contactPoints"`
+}
+
+// TemplateContactPointUsage is a contact point that invokes a message template.
+type TemplateContactPointUsage struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Settings are the names of the settings that invoke the template.
+	Settings []string `json:"settings"`
+}
+
+// swagger:parameters RouteGetTemplatesExport
+type TemplatesExportParams struct {
+	// Format of the exported document, either yaml or json.
+	// in:query
+	// required:false
+	// default:yaml
+	Format string `json:"format"`
+	// Serve the document as a file attachment.
+	// in:query
+	// required:false
+	// default:false
+	Download bool `json:"download"`
+}
+
+// TemplatesExport is a file provisioning document containing message templates.
+// swagger:model
+type TemplatesExport struct {
+	APIVersion int64                 `json:"apiVersion" yaml:"apiVersion"`
+	Templates  []TemplateExportEntry `json:"templates" yaml:"templates"`
+}
+
+// TemplateExportEntry is a message template of an organization in the file provisioning format.
+type TemplateExportEntry struct {
+	OrgID    int64  `json:"orgId" yaml:"orgId"`
+	Name     string `json:"name" yaml:"name"`
+	Template string `json:"template" yaml:"template"`
+}
+
+// swagger:parameters RoutePostTemplatesImport
+type TemplatesImportParams struct {
+	// Report what would be imported without saving the templates.
+	// in:query
+	// required:false
+	// default:false
+	DryRun bool `json:"dryRun"`
+	// Replace the templates that conflict with the imported ones and were not provisioned.
+	// in:query
+	// required:false
+	// default:false
+	Overwrite bool `json:"overwrite"`
+	// in:body
+	Body TemplatesExport
+}
+
+// TemplateImportAction is what an import does with a template.
+type TemplateImportAction string
+
+const (
+	TemplateImportCreated   TemplateImportAction = "created"
+	TemplateImportUpdated   TemplateImportAction = "updated"
+	TemplateImportUnchanged TemplateImportAction = "unchanged"
+	TemplateImportConflict  TemplateImportAction = "conflict"
+)
+
+// swagger:model
+type TemplatesImportResult struct {
+	// DryRun is true if the templates were not saved.
+	DryRun    bool                   `json:"dryRun"`
+	Templates []TemplateImportResult `json:"templates"`
+}
+
+// TemplateImportResult is the outcome of the import of a template.
+type TemplateImportResult struct {
+	Name   string               `json:"name"`
+	Action TemplateImportAction `json:"action"`
+	// ExistingProvenance is the provenance of the template with the same name that existed before the import.
+	ExistingProvenance models.Provenance `json:"existingProvenance,omitempty"`
+}
+
 func (t *MessageTemplate) ResourceType() string {
 	return "template"
 }
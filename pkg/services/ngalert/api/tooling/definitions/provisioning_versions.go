@@ -0,0 +1,131 @@
+package definitions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ProvisioningVersionHeader is the header clients set to pin the payload schema version of the provisioning API.
+// The server sets it in the response to the version it used.
+const ProvisioningVersionHeader = "X-Grafana-Provisioning-Version"
+
+const (
+	// ProvisioningVersionV0Alpha1 is the schema of the first release of the provisioning API. It is deprecated.
+	ProvisioningVersionV0Alpha1 = "v0alpha1"
+	// ProvisioningVersionV1 is the current schema, used when a client does not pin a version.
+	ProvisioningVersionV1 = "v1"
+)
+
+// provisioningVersions are the supported schema versions, mapped to whether they are deprecated.
+var provisioningVersions = map[string]bool{
+	ProvisioningVersionV0Alpha1: true,
+	ProvisioningVersionV1:       false,
+}
+
+// ParseProvisioningVersion returns the schema version of the header value.
+func ParseProvisioningVersion(value string) (string, error) {
+	if value == "" {
+		return ProvisioningVersionV1, nil
+	}
+	if _, ok := provisioningVersions[value]; !ok {
+		return "", fmt.Errorf("unknown provisioning API version %q, expected either %s or %s", value, ProvisioningVersionV0Alpha1, ProvisioningVersionV1)
+	}
+	return value, nil
+}
+
+// ProvisioningVersionDeprecated returns true if the schema version is deprecated.
+func ProvisioningVersionDeprecated(version string) bool {
+	return provisioningVersions[version]
+}
+
+// swagger:parameters RouteGetAlertRule RouteGetAlertRules RoutePostAlertRule RoutePutAlertRule RoutePatchAlertRule RoutePostAlertRuleClone RoutePostRuleTemplateInstantiate
+type ProvisioningVersionParam struct {
+	// Version of the payload schema. Clients that pin a version keep getting and sending payloads in that schema
+	// when fields are added to newer versions.
+	// in:header
+	// required:false
+	// enum: v0alpha1,v1
+	// default:v1
+	Version string `json:"X-Grafana-Provisioning-Version"`
+}
+
+// AlertRuleV0Alpha1 is an alert rule in the v0alpha1 schema of the provisioning API. It does not have the webhooks,
+// pausing and instance limit of v1; updates made with it keep the values stored for them.
+// swagger:model
+type AlertRuleV0Alpha1 struct {
+	ID           int64                      `json:"id"`
+	UID          string                     `json:"uid"`
+	OrgID        int64                      `json:"orgID"`
+	FolderUID    string                     `json:"folderUID"`
+	RuleGroup    string                     `json:"ruleGroup"`
+	Title        string                     `json:"title"`
+	Condition    string                     `json:"condition"`
+	Data         []models.AlertQuery        `json:"data"`
+	Updated      time.Time                  `json:"updated,omitempty"`
+	NoDataState  models.NoDataState         `json:"noDataState"`
+	ExecErrState models.ExecutionErrorState `json:"execErrState"`
+	For          time.Duration              `json:"for"`
+	Annotations  map[string]string          `json:"annotations,omitempty"`
+	Labels       map[string]string          `json:"labels,omitempty"`
+	Provenance   models.Provenance          `json:"provenance,omitempty"`
+}
+
+// NewAlertRuleV0Alpha1 converts an alert rule to the v0alpha1 schema.
+func NewAlertRuleV0Alpha1(a AlertRule) AlertRuleV0Alpha1 {
+	return AlertRuleV0Alpha1{
+		ID:           a.ID,
+		UID:          a.UID,
+		OrgID:        a.OrgID,
+		FolderUID:    a.FolderUID,
+		RuleGroup:    a.RuleGroup,
+		Title:        a.Title,
+		Condition:    a.Condition,
+		Data:         a.Data,
+		Updated:      a.Updated,
+		NoDataState:  a.NoDataState,
+		ExecErrState: a.ExecErrState,
+		For:          a.For,
+		Annotations:  a.Annotations,
+		Labels:       a.Labels,
+		Provenance:   a.Provenance,
+	}
+}
+
+// V1 converts the alert rule to the v1 schema. The fields that v0alpha1 does not have are taken from stored, the
+// rule as it is stored before an update, so that clients of v0alpha1 do not reset them. They are left empty if
+// stored is nil.
+func (a AlertRuleV0Alpha1) V1(stored *models.AlertRule) AlertRule {
+	rule := AlertRule{
+		ID:           a.ID,
+		UID:          a.UID,
+		OrgID:        a.OrgID,
+		FolderUID:    a.FolderUID,
+		RuleGroup:    a.RuleGroup,
+		Title:        a.Title,
+		Condition:    a.Condition,
+		Data:         a.Data,
+		Updated:      a.Updated,
+		NoDataState:  a.NoDataState,
+		ExecErrState: a.ExecErrState,
+		For:          a.For,
+		Annotations:  a.Annotations,
+		Labels:       a.Labels,
+		Provenance:   a.Provenance,
+	}
+	if stored != nil {
+		rule.Webhooks = stored.Webhooks
+		rule.IsPaused = stored.IsPaused
+		rule.MaxInstances = stored.MaxInstances
+	}
+	return rule
+}
+
+// VersionedAlertRule returns the alert rule in the schema of the version.
+func VersionedAlertRule(version string, a AlertRule) interface{} {
+	if version == ProvisioningVersionV0Alpha1 {
+		return NewAlertRuleV0Alpha1(a)
+	}
+	return a
+}
@@ -0,0 +1,130 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /api/v1/provisioning/webhooks provisioning stable RouteGetProvisioningWebhooks
+//
+// Get all the webhooks that are notified of the changes of provisioned resources.
+//
+//     Responses:
+//       200: ProvisioningWebhooks
+
+// swagger:route GET /api/v1/provisioning/webhooks/{UID} provisioning stable RouteGetProvisioningWebhook
+//
+// Get a webhook that is notified of the changes of provisioned resources.
+//
+//     Responses:
+//       200: ProvisioningWebhook
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/webhooks provisioning stable RoutePostProvisioningWebhook
+//
+// Register a webhook that is notified of the changes of provisioned resources.
+//
+// Whenever an alert rule, a contact point or the notification policies are created, updated or deleted through the
+// provisioning API, a ProvisioningChange is posted to the URLs of the webhooks of the organization that subscribed to
+// the resource. The changes are posted in the background, once, and failures are only logged. If the webhook has a
+// secret, the X-Grafana-Signature header of the requests is the hex-encoded HMAC-SHA256 of the body with the secret.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisioningWebhook
+//       400: ValidationError
+
+// swagger:route PUT /api/v1/provisioning/webhooks/{UID} provisioning stable RoutePutProvisioningWebhook
+//
+// Update a webhook that is notified of the changes of provisioned resources. The secret is kept if it is not set.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisioningWebhook
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route DELETE /api/v1/provisioning/webhooks/{UID} provisioning stable RouteDeleteProvisioningWebhook
+//
+// Delete a webhook that is notified of the changes of provisioned resources.
+//
+//     Responses:
+//       204: description: The webhook was deleted successfully.
+//       404: description: Not found.
+
+// swagger:parameters RouteGetProvisioningWebhook RoutePutProvisioningWebhook RouteDeleteProvisioningWebhook
+type ProvisioningWebhookParam struct {
+	// Webhook UID
+	// in:path
+	UID string `json:"UID"`
+}
+
+// swagger:parameters RoutePostProvisioningWebhook RoutePutProvisioningWebhook
+type ProvisioningWebhookPayload struct {
+	// in:body
+	Body ProvisioningWebhook
+}
+
+const (
+	// ProvisioningResourceAlertRule is the resource of the changes of alert rules.
+	ProvisioningResourceAlertRule = "alert-rule"
+	// ProvisioningResourceContactPoint is the resource of the changes of contact points.
+	ProvisioningResourceContactPoint = "contact-point"
+	// ProvisioningResourceNotificationPolicy is the resource of the changes of the notification policy tree.
+	ProvisioningResourceNotificationPolicy = "notification-policy"
+)
+
+const (
+	ProvisioningChangeCreated = "created"
+	ProvisioningChangeUpdated = "updated"
+	ProvisioningChangeDeleted = "deleted"
+)
+
+// swagger:model
+type ProvisioningWebhooks []ProvisioningWebhook
+
+// ProvisioningWebhook is a URL that is notified of the changes of provisioned resources.
+// swagger:model
+type ProvisioningWebhook struct {
+	// The UID is generated when the webhook is registered.
+	UID string `json:"uid"`
+	// URL the changes are posted to, with the http or https scheme.
+	// required: true
+	// example: https://chatops.example.com/grafana
+	URL string `json:"url"`
+	// Resources the webhook is notified of, any of alert-rule, contact-point and notification-policy. The webhook is
+	// notified of the changes of all resources if it is empty.
+	// example: ["alert-rule"]
+	Resources []string `json:"resources,omitempty"`
+	// Secret the bodies of the requests are signed with. It is never returned.
+	Secret string `json:"secret,omitempty"`
+}
+
+// ProvisioningChange is the body of the requests posted to the webhooks when a provisioned resource changes.
+// swagger:model
+type ProvisioningChange struct {
+	// Action is either created, updated or deleted.
+	Action string `json:"action"`
+	// Resource is either alert-rule, contact-point or notification-policy.
+	Resource string `json:"resource"`
+	// UID of the alert rule or contact point. The notification policy tree has none.
+	UID       string    `json:"uid,omitempty"`
+	OrgID     int64     `json:"orgId"`
+	User      string    `json:"user,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Before is the resource before the change, it is not set when the resource is created.
+	Before interface{} `json:"before,omitempty"`
+	// After is the resource after the change, it is not set when the resource is deleted.
+	After interface{} `json:"after,omitempty"`
+	// Diff lists the fields that differ between Before and After.
+	Diff []ProvisioningChangeField `json:"diff"`
+}
+
+// ProvisioningChangeField is a field of a resource that was changed.
+type ProvisioningChangeField struct {
+	// Path of the field in the JSON representation of the resource, such as labels.team or data[0].model.expr.
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
@@ -0,0 +1,188 @@
+package definitions
+
+// swagger:route GET /api/v1/ngalert/rule_lint/config configuration RouteGetRuleLintConfig
+//
+// Get the alert rule lint configuration of the user's organization.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleLintConfig
+//       500: Failure
+
+// swagger:route POST /api/v1/ngalert/rule_lint/config configuration RoutePostRuleLintConfig
+//
+// Updates the alert rule lint configuration of the user's organization.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: Ack
+//       400: ValidationError
+
+// swagger:route POST /api/v1/ngalert/rule_lint configuration RoutePostRuleLint
+//
+// Lints the alert rules of the user's organization against the lint policies of the organization. If no rule UIDs
+// are sent, all rules visible to the user are linted.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleLintReport
+//       400: ValidationError
+
+// swagger:route GET /api/v1/ngalert/rule_lint/folders/{FolderUID} configuration RouteGetFolderRulePolicy
+//
+// Get the rule policy of a folder. The policy is empty if the folder has none.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: FolderRulePolicy
+//       500: Failure
+
+// swagger:route POST /api/v1/ngalert/rule_lint/folders/{FolderUID} configuration RoutePostFolderRulePolicy
+//
+// Sets the rule policy of a folder. Rules that are saved afterwards in the folder must comply with it, the rules that
+// are already in the folder are not changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: Ack
+//       400: ValidationError
+
+// swagger:route DELETE /api/v1/ngalert/rule_lint/folders/{FolderUID} configuration RouteDeleteFolderRulePolicy
+//
+// Removes the rule policy of a folder.
+//
+//     Responses:
+//       200: Ack
+//       500: Failure
+
+// swagger:parameters RouteGetFolderRulePolicy RoutePostFolderRulePolicy RouteDeleteFolderRulePolicy
+type FolderRulePolicyPathParams struct {
+	// in:path
+	FolderUID string `json:"FolderUID"`
+}
+
+// swagger:parameters RoutePostFolderRulePolicy
+type FolderRulePolicyParams struct {
+	// in:body
+	Body FolderRulePolicy
+}
+
+// swagger:parameters RoutePostRuleLintConfig
+type RuleLintConfigParams struct {
+	// in:body
+	Body RuleLintConfig
+}
+
+// swagger:parameters RoutePostRuleLint
+type RuleLintParams struct {
+	// in:body
+	Body RuleLintRequest
+}
+
+// swagger:enum RuleLintSeverity
+type RuleLintSeverity string
+
+const (
+	RuleLintSeverityOff     RuleLintSeverity = "off"
+	RuleLintSeverityWarning RuleLintSeverity = "warning"
+	RuleLintSeverityError   RuleLintSeverity = "error"
+)
+
+// Names of the rule lint policies, as reported in violations.
+const (
+	RuleLintPolicyRequireRunbook   = "requireRunbook"
+	RuleLintPolicyRequireTeamLabel = "requireTeamLabel"
+	RuleLintPolicyForbidZeroFor    = "forbidZeroFor"
+	RuleLintPolicyMaxQueries       = "maxQueries"
+
+	RuleLintPolicyFolderMinInterval        = "folderMinInterval"
+	RuleLintPolicyFolderRequiredLabels     = "folderRequiredLabels"
+	RuleLintPolicyFolderAllowedDatasources = "folderAllowedDatasources"
+)
+
+// swagger:model
+type RuleLintConfig struct {
+	// If enabled, rules violating a policy of severity error can't be saved.
+	Enforce bool `json:"enforce"`
+	// Requires the runbook_url annotation to be set.
+	RequireRunbook RuleLintPolicy `json:"requireRunbook"`
+	// Requires the label naming the team that owns the rule to be set.
+	RequireTeamLabel RuleLintLabelPolicy `json:"requireTeamLabel"`
+	// Forbids a pending period of 0s on rules with one of the given values in the severity label.
+	ForbidZeroFor RuleLintForbidZeroForPolicy `json:"forbidZeroFor"`
+	// Limits the number of data source queries of a rule.
+	MaxQueries RuleLintMaxQueriesPolicy `json:"maxQueries"`
+}
+
+// FolderRulePolicy constrains the alert rules of a folder. Unlike the lint policies of the organization, rules
+// violating it are always rejected.
+// swagger:model
+type FolderRulePolicy struct {
+	// Minimum evaluation interval of the rule groups of the folder, in seconds. There is no minimum if it is 0.
+	// example: 300
+	MinIntervalSeconds int64 `json:"minIntervalSeconds,omitempty"`
+	// Labels that every rule of the folder must set.
+	// example: ["team"]
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+	// Data sources that the queries of the rules of the folder can use. All data sources are allowed if it is empty.
+	// example: ["prometheus-prod"]
+	AllowedDatasourceUIDs []string `json:"allowedDatasourceUIDs,omitempty"`
+}
+
+type RuleLintPolicy struct {
+	Severity RuleLintSeverity `json:"severity"`
+}
+
+type RuleLintLabelPolicy struct {
+	Severity RuleLintSeverity `json:"severity"`
+	// example: team
+	Label string `json:"label"`
+}
+
+type RuleLintForbidZeroForPolicy struct {
+	Severity RuleLintSeverity `json:"severity"`
+	// example: severity
+	Label string `json:"label"`
+	// example: ["critical", "page"]
+	Values []string `json:"values"`
+}
+
+type RuleLintMaxQueriesPolicy struct {
+	Severity RuleLintSeverity `json:"severity"`
+	// example: 5
+	Max int `json:"max"`
+}
+
+// swagger:model
+type RuleLintRequest struct {
+	RuleUIDs []string `json:"ruleUIDs"`
+}
+
+// swagger:model
+type RuleLintReport struct {
+	// Whether rules violating a policy of severity error are rejected when saved.
+	Enforced   bool                `json:"enforced"`
+	Violations []RuleLintViolation `json:"violations"`
+}
+
+// swagger:model
+type RuleLintViolation struct {
+	RuleUID   string           `json:"ruleUID,omitempty"`
+	RuleTitle string           `json:"ruleTitle"`
+	Policy    string           `json:"policy"`
+	Severity  RuleLintSeverity `json:"severity"`
+	Message   string           `json:"message"`
+}
@@ -11,3 +11,28 @@ type ValidationError struct {
 	// example: error message
 	Msg string `json:"msg"`
 }
+
+// swagger:parameters RouteGetContactpoints RouteGetAlertRuleGroup RouteGetAlertRules
+type FieldsParam struct {
+	// Comma-separated list of fields to include in each returned object, e.g. uid,name. All fields are returned if empty.
+	// in: query
+	// required: false
+	Fields []string `json:"fields"`
+}
+
+// swagger:parameters RouteGetContactpoints RouteGetMuteTimings RouteGetTemplates RouteGetAlertRules
+type ListParams struct {
+	// Only return the objects whose name contains this value, ignoring case.
+	// in: query
+	// required: false
+	Name string `json:"name"`
+	// Maximum number of objects to return. All objects are returned if it is 0.
+	// in: query
+	// required: false
+	Limit int `json:"limit"`
+	// Page of objects to return, starting from 1. Only used together with limit.
+	// in: query
+	// required: false
+	// default: 1
+	Page int `json:"page"`
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/promql"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -52,6 +53,36 @@ import (
 //     Responses:
 //       200: EvalQueriesResponse
 
+// swagger:route Post /api/v1/rule/backtest testing RouteBacktestRule
+//
+// Evaluates a rule that is not saved at regular intervals over a past time range and returns the periods during
+// which its alert instances would have been firing.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestResult
+//       400: ValidationError
+
+// swagger:route Post /api/v1/rule/validate/grafana/{Namespace} testing RouteValidateGrafanaRuleGroup
+//
+// Validates a Grafana managed rule group without saving it. Besides the checks done when the group is saved, the
+// queries of the rules are executed so that the data sources report syntax errors. All problems are returned.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleGroupValidationResult
+//       404: description: Not found.
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -84,6 +115,30 @@ type EvalQueriesPayload struct {
 	Now  time.Time           `json:"now"`
 }
 
+// swagger:parameters RouteBacktestRule
+type BacktestRequest struct {
+	// in:body
+	Body BacktestPayload
+}
+
+// BacktestPayload is the condition of a rule and the time range over which it is evaluated.
+// swagger:model
+type BacktestPayload struct {
+	// required: true
+	// example: C
+	Condition string `json:"condition"`
+	// required: true
+	Data []models.AlertQuery `json:"data"`
+	// For is how long the condition must be met before an alert instance fires.
+	For model.Duration `json:"for,omitempty"`
+	// Interval is the time between two evaluations, the default evaluation interval of rules if absent.
+	Interval model.Duration `json:"interval,omitempty"`
+	// required: true
+	From time.Time `json:"from"`
+	// required: true
+	To time.Time `json:"to"`
+}
+
 func (p *TestRulePayload) UnmarshalJSON(b []byte) error {
 	type plain TestRulePayload
 	if err := json.Unmarshal(b, (*plain)(p)); err != nil {
@@ -123,9 +178,49 @@ type TestRuleResponse struct {
 	GrafanaAlertInstances AlertInstancesResponse `json:"grafana_alert_instances"`
 }
 
+// BacktestResult is the alert instances that would have fired during the time range of a backtest.
+// swagger:model
+type BacktestResult struct {
+	// Evaluations is the number of times the condition was evaluated.
+	Evaluations int                `json:"evaluations"`
+	Instances   []BacktestInstance `json:"instances"`
+}
+
+// BacktestInstance is an alert instance that would have fired and the periods during which it would have been firing.
+type BacktestInstance struct {
+	Labels map[string]string `json:"labels"`
+	Firing []BacktestPeriod  `json:"firing"`
+}
+
+// BacktestPeriod is a period during which an alert instance would have been firing.
+type BacktestPeriod struct {
+	// Start is the evaluation at which the instance started firing.
+	Start time.Time `json:"start"`
+	// End is the evaluation at which the instance stopped firing, absent if it was still firing at the end of the
+	// time range.
+	End *time.Time `json:"end,omitempty"`
+}
+
 // swagger:model
 type EvalQueriesResponse = backend.QueryDataResponse
 
+// RuleGroupValidationResult lists the problems of a rule group. The group can be saved if there are none.
+// swagger:model
+type RuleGroupValidationResult struct {
+	Valid    bool                    `json:"valid"`
+	Problems []RuleValidationProblem `json:"problems"`
+}
+
+// RuleValidationProblem is a problem of a rule group, of one of its rules or of one of the queries of a rule.
+type RuleValidationProblem struct {
+	// Index of the rule in the group, absent if the problem is with the group.
+	RuleIndex *int   `json:"ruleIndex,omitempty"`
+	RuleTitle string `json:"ruleTitle,omitempty"`
+	// RefID of the query or expression, absent if the problem is with the rule or the group.
+	RefID   string `json:"refId,omitempty"`
+	Message string `json:"message"`
+}
+
 // swagger:model
 type AlertInstancesResponse struct {
 	// Instances is an array of arrow encoded dataframes
@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -26,6 +27,9 @@ import (
 
 var searchRegex = regexp.MustCompile(`\{(\w+)\}`)
 
+// labelSelectorRegex matches label selectors such as team=payments, severity!="info" or team=~"payments|billing".
+var labelSelectorRegex = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*(.*?)\s*$`)
+
 var NotImplementedResp = ErrResp(http.StatusNotImplemented, errors.New("endpoint not implemented"), "")
 
 func toMacaronPath(path string) string {
@@ -35,6 +39,67 @@ func toMacaronPath(path string) string {
 	}))
 }
 
+// nameMatches returns true if the name contains the name query parameter of the request, ignoring case.
+func nameMatches(c *models.ReqContext, name string) bool {
+	filter := c.Query("name")
+	return filter == "" || strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+}
+
+// labelSelectors returns the matchers of the label query parameters of the request. The values of the selectors can
+// be quoted.
+func labelSelectors(c *models.ReqContext) (labels.Matchers, error) {
+	selectors := c.QueryStrings("label")
+	matchers := make(labels.Matchers, 0, len(selectors))
+	for _, selector := range selectors {
+		parts := labelSelectorRegex.FindStringSubmatch(selector)
+		if parts == nil {
+			return nil, fmt.Errorf("invalid label selector '%s'", selector)
+		}
+		value := parts[3]
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of label selector '%s': %w", selector, err)
+			}
+			value = unquoted
+		}
+		matchType := map[string]labels.MatchType{
+			"=":  labels.MatchEqual,
+			"!=": labels.MatchNotEqual,
+			"=~": labels.MatchRegexp,
+			"!~": labels.MatchNotRegexp,
+		}[parts[2]]
+		matcher, err := labels.NewMatcher(matchType, parts[1], value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector '%s': %w", selector, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
+
+// listPage returns the bounds of the page of a list of n objects requested by the limit and page query parameters
+// of the request. Pages start at 1, and the whole list is returned if there is no limit.
+func listPage(c *models.ReqContext, n int) (int, int) {
+	limit, page := c.QueryInt("limit"), c.QueryInt("page")
+	if limit <= 0 {
+		return 0, n
+	}
+	if page <= 0 {
+		page = 1
+	}
+	// pages past the end of the list are empty, this also keeps large pages from overflowing
+	if page-1 > n/limit {
+		return n, n
+	}
+	start := (page - 1) * limit
+	end := n
+	if n-start > limit {
+		end = start + limit
+	}
+	return start, end
+}
+
 func backendTypeByUID(ctx *models.ReqContext, cache datasources.CacheService) (apimodels.Backend, error) {
 	datasourceUID := web.Params(ctx.Req)[":DatasourceUID"]
 	if ds, err := cache.GetDatasourceByUID(ctx.Req.Context(), datasourceUID, ctx.SignedInUser, ctx.SkipCache); err == nil {
@@ -1,9 +1,14 @@
 package api
 
 import (
+	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func TestToMacaronPath(t *testing.T) {
@@ -25,3 +30,38 @@ func TestToMacaronPath(t *testing.T) {
 		assert.Equal(t, tc.expectedOutputPath, outputPath)
 	}
 }
+
+func TestListPage(t *testing.T) {
+	testCases := []struct {
+		query string
+		start int
+		end   int
+	}{
+		{query: "", start: 0, end: 5},
+		{query: "page=2", start: 0, end: 5},
+		{query: "limit=2", start: 0, end: 2},
+		{query: "limit=2&page=2", start: 2, end: 4},
+		{query: "limit=2&page=3", start: 4, end: 5},
+		{query: "limit=2&page=4", start: 5, end: 5},
+		{query: "limit=10", start: 0, end: 5},
+		{query: "limit=9223372036854775807&page=9223372036854775807", start: 5, end: 5},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			c := &models.ReqContext{Context: &web.Context{Req: &http.Request{URL: &url.URL{RawQuery: tc.query}}}}
+
+			start, end := listPage(c, 5)
+
+			assert.Equal(t, tc.start, start)
+			assert.Equal(t, tc.end, end)
+		})
+	}
+}
+
+func TestNameMatches(t *testing.T) {
+	c := &models.ReqContext{Context: &web.Context{Req: &http.Request{URL: &url.URL{RawQuery: "name=Team"}}}}
+
+	assert.True(t, nameMatches(c, "team-a"))
+	assert.True(t, nameMatches(c, "Other TEAM"))
+	assert.False(t, nameMatches(c, "other"))
+}
@@ -0,0 +1,131 @@
+package defaultlabels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	prometheusModel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	KVNamespace = "ngalert.default_labels"
+	labelsKey   = "labels"
+
+	// cacheTTL is how long the default labels of an organization are cached for the evaluation of its rules. Labels
+	// saved through another Grafana instance of a high availability setup are used after at most this long.
+	cacheTTL = time.Minute
+)
+
+var ErrInvalidLabels = errors.New("invalid default labels")
+
+// Service stores the default labels of every organization, which are added to all alert instances of its rules
+// that do not have a label with the same name.
+type Service struct {
+	kvStore kvstore.KVStore
+	clock   clock.Clock
+	log     log.Logger
+
+	mtx   sync.Mutex
+	cache map[int64]cachedLabels
+}
+
+type cachedLabels struct {
+	labels    map[string]string
+	fetchedAt time.Time
+}
+
+func NewService(kvStore kvstore.KVStore, clock clock.Clock, log log.Logger) *Service {
+	return &Service{
+		kvStore: kvStore,
+		clock:   clock,
+		log:     log,
+		cache:   make(map[int64]cachedLabels),
+	}
+}
+
+// GetLabels returns the default labels of the organization, it is empty if the organization has none.
+func (s *Service) GetLabels(ctx context.Context, orgID int64) (map[string]string, error) {
+	raw, ok, err := kvstore.WithNamespace(s.kvStore, orgID, KVNamespace).Get(ctx, labelsKey)
+	if err != nil {
+		return nil, err
+	}
+	labels := map[string]string{}
+	if !ok {
+		return labels, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &labels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal default labels: %w", err)
+	}
+	return labels, nil
+}
+
+// SaveLabels validates and stores the default labels of the organization, replacing the previous ones.
+func (s *Service) SaveLabels(ctx context.Context, orgID int64, labels map[string]string) error {
+	if err := Validate(labels); err != nil {
+		return err
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	raw, err := json.Marshal(labels)
+	if err != nil {
+		return err
+	}
+	if err := kvstore.WithNamespace(s.kvStore, orgID, KVNamespace).Set(ctx, labelsKey, string(raw)); err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.cache[orgID] = cachedLabels{labels: labels, fetchedAt: s.clock.Now()}
+	return nil
+}
+
+// DefaultLabels returns the default labels of the organization for the evaluation of its rules. They are cached, so
+// that the store is not queried on every evaluation.
+func (s *Service) DefaultLabels(ctx context.Context, orgID int64) (map[string]string, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if cached, ok := s.cache[orgID]; ok && s.clock.Since(cached.fetchedAt) < cacheTTL {
+		return cached.labels, nil
+	}
+	labels, err := s.GetLabels(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	s.cache[orgID] = cachedLabels{labels: labels, fetchedAt: s.clock.Now()}
+	return labels, nil
+}
+
+// Validate checks that the default labels have valid names and values, and that they do not use the names of the
+// labels Grafana adds to alerts.
+func Validate(labels map[string]string) error {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !prometheusModel.LabelName(name).IsValid() {
+			return fmt.Errorf("%w: %q is not a valid label name", ErrInvalidLabels, name)
+		}
+		if strings.HasPrefix(name, "__") || strings.HasPrefix(name, models.GrafanaReservedLabelPrefix) || name == prometheusModel.AlertNameLabel {
+			return fmt.Errorf("%w: the label name %q is reserved", ErrInvalidLabels, name)
+		}
+		if labels[name] == "" {
+			return fmt.Errorf("%w: the label %q has no value", ErrInvalidLabels, name)
+		}
+	}
+	return nil
+}
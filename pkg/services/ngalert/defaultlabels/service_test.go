@@ -0,0 +1,88 @@
+package defaultlabels
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("organizations without default labels get none", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), clock.NewMock(), log.NewNopLogger())
+
+		labels, err := sut.GetLabels(ctx, 1)
+
+		require.NoError(t, err)
+		require.Empty(t, labels)
+	})
+
+	t.Run("labels are saved per organization", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), clock.NewMock(), log.NewNopLogger())
+		labels := map[string]string{"env": "prod", "org": "acme"}
+
+		require.NoError(t, sut.SaveLabels(ctx, 1, labels))
+
+		saved, err := sut.GetLabels(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, labels, saved)
+		other, err := sut.GetLabels(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, other)
+	})
+
+	t.Run("invalid labels are not saved", func(t *testing.T) {
+		testCases := map[string]map[string]string{
+			"invalid name":  {"not-valid": "value"},
+			"internal name": {"__name__": "value"},
+			"grafana name":  {"grafana_folder": "value"},
+			"alert name":    {"alertname": "value"},
+			"empty value":   {"env": ""},
+		}
+		for name, labels := range testCases {
+			t.Run(name, func(t *testing.T) {
+				sut := NewService(notifier.NewFakeKVStore(t), clock.NewMock(), log.NewNopLogger())
+
+				err := sut.SaveLabels(ctx, 1, labels)
+
+				require.True(t, errors.Is(err, ErrInvalidLabels))
+				saved, err := sut.GetLabels(ctx, 1)
+				require.NoError(t, err)
+				require.Empty(t, saved)
+			})
+		}
+	})
+
+	t.Run("default labels are cached for the evaluation of rules", func(t *testing.T) {
+		kv := notifier.NewFakeKVStore(t)
+		clk := clock.NewMock()
+		sut := NewService(kv, clk, log.NewNopLogger())
+		require.NoError(t, sut.SaveLabels(ctx, 1, map[string]string{"env": "prod"}))
+
+		// labels saved through another instance are not used until the cache expires
+		require.NoError(t, kvstore.WithNamespace(kv, 1, KVNamespace).Set(ctx, labelsKey, `{"env":"dev"}`))
+		labels, err := sut.DefaultLabels(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "prod"}, labels)
+
+		clk.Add(cacheTTL + time.Second)
+		labels, err = sut.DefaultLabels(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "dev"}, labels)
+
+		// labels saved through this instance are used right away
+		require.NoError(t, sut.SaveLabels(ctx, 1, map[string]string{"env": "staging"}))
+		labels, err = sut.DefaultLabels(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "staging"}, labels)
+	})
+}
@@ -84,6 +84,9 @@ type ExecutionResults struct {
 	NoData map[string]string
 
 	Results data.Frames
+
+	// QueryStats contains the statistics of the data source queries by RefID.
+	QueryStats map[string]expr.QueryStats
 }
 
 // Results is a slice of evaluated alert instances states.
@@ -108,6 +111,10 @@ type Result struct {
 	// It does not contain values for classic conditions as the values
 	// in classic conditions do not have a RefID.
 	Values map[string]NumberValueCapture
+
+	// QueryStats contains the statistics of the data source queries of the evaluation by RefID.
+	// It is shared by all results of an evaluation.
+	QueryStats map[string]expr.QueryStats
 }
 
 // State is an enum of the evaluation State for an alert instance.
@@ -260,9 +267,11 @@ type NumberValueCapture struct {
 }
 
 func executeCondition(ctx AlertExecCtx, c *models.Condition, now time.Time, exprService *expr.Service, dsCacheService datasources.CacheService, secretsService secrets.Service) ExecutionResults {
+	stats := expr.NewQueryStatsCollector()
+	ctx.Ctx = expr.WithQueryStatsCollector(ctx.Ctx, stats)
 	execResp, err := executeQueriesAndExpressions(ctx, c.Data, now, exprService, dsCacheService, secretsService)
 	if err != nil {
-		return ExecutionResults{Error: err}
+		return ExecutionResults{Error: err, QueryStats: stats.Stats()}
 	}
 
 	// eval captures for the '__value_string__' annotation and the Value property of the API response.
@@ -284,7 +293,7 @@ func executeCondition(ctx AlertExecCtx, c *models.Condition, now time.Time, expr
 	// datasourceExprUID is a special DatasourceUID for expressions
 	datasourceExprUID := strconv.FormatInt(expr.DatasourceID, 10)
 
-	var result ExecutionResults
+	result := ExecutionResults{QueryStats: stats.Stats()}
 	for refID, res := range execResp.Responses {
 		if len(res.Frames) == 0 {
 			// to ensure that NoData is consistent with Results we do not initialize NoData
@@ -432,6 +441,7 @@ func evaluateExecutionResult(execResults ExecutionResults, ts time.Time) Results
 			Error:              e,
 			EvaluatedAt:        ts,
 			EvaluationDuration: time.Since(ts),
+			QueryStats:         execResults.QueryStats,
 		})
 	}
 
@@ -441,6 +451,7 @@ func evaluateExecutionResult(execResults ExecutionResults, ts time.Time) Results
 			Instance:           labels,
 			EvaluatedAt:        ts,
 			EvaluationDuration: time.Since(ts),
+			QueryStats:         execResults.QueryStats,
 		})
 	}
 
@@ -511,6 +522,7 @@ func evaluateExecutionResult(execResults ExecutionResults, ts time.Time) Results
 			EvaluationDuration: time.Since(ts),
 			EvaluationString:   extractEvalString(f),
 			Values:             extractValues(f),
+			QueryStats:         execResults.QueryStats,
 		}
 
 		switch {
@@ -537,6 +549,7 @@ func evaluateExecutionResult(execResults ExecutionResults, ts time.Time) Results
 					EvaluatedAt:        ts,
 					EvaluationDuration: time.Since(ts),
 					Error:              &invalidEvalResultFormatError{reason: fmt.Sprintf("frame cannot uniquely be identified by its labels: has duplicate results with labels {%s}", labelsStr)},
+					QueryStats:         execResults.QueryStats,
 				},
 			}
 		}
@@ -603,10 +616,13 @@ func (e *evaluatorImpl) ConditionEval(condition *models.Condition, now time.Time
 	return evalResults, nil
 }
 
-// QueriesAndExpressionsEval executes queries and expressions and returns the result.
+// QueriesAndExpressionsEval executes queries and expressions and returns the result. The statistics of each data
+// source query are added to the metadata of the first frame of its response.
 func (e *evaluatorImpl) QueriesAndExpressionsEval(orgID int64, data []models.AlertQuery, now time.Time) (*backend.QueryDataResponse, error) {
 	alertCtx, cancelFn := context.WithTimeout(context.Background(), e.cfg.UnifiedAlerting.EvaluationTimeout)
 	defer cancelFn()
+	stats := expr.NewQueryStatsCollector()
+	alertCtx = expr.WithQueryStatsCollector(alertCtx, stats)
 
 	alertExecCtx := AlertExecCtx{OrgID: orgID, Ctx: alertCtx, ExpressionsEnabled: e.cfg.ExpressionsEnabled, Log: e.log}
 
@@ -614,6 +630,28 @@ func (e *evaluatorImpl) QueriesAndExpressionsEval(orgID int64, data []models.Ale
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute conditions: %w", err)
 	}
+	addQueryStats(execResult, stats.Stats())
 
 	return execResult, nil
 }
+
+// addQueryStats adds the statistics of the queries to the metadata of the first frame of their responses,
+// so that they are shown with the query results.
+func addQueryStats(resp *backend.QueryDataResponse, stats map[string]expr.QueryStats) {
+	for refID, s := range stats {
+		res, ok := resp.Responses[refID]
+		if !ok || len(res.Frames) == 0 || res.Frames[0] == nil {
+			continue
+		}
+		frame := res.Frames[0]
+		if frame.Meta == nil {
+			frame.SetMeta(&data.FrameMeta{})
+		}
+		frame.Meta.Stats = append(frame.Meta.Stats,
+			data.QueryStat{FieldConfig: data.FieldConfig{DisplayName: "Series"}, Value: float64(s.SeriesCount)},
+			data.QueryStat{FieldConfig: data.FieldConfig{DisplayName: "Samples"}, Value: float64(s.SampleCount)},
+			data.QueryStat{FieldConfig: data.FieldConfig{DisplayName: "Bytes", Unit: "decbytes"}, Value: float64(s.Bytes)},
+			data.QueryStat{FieldConfig: data.FieldConfig{DisplayName: "Data source latency", Unit: "ms"}, Value: float64(s.Latency) / float64(time.Millisecond)},
+		)
+	}
+}
@@ -1,13 +1,17 @@
 package eval
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 	ptr "github.com/xorcare/pointer"
+
+	"github.com/grafana/grafana/pkg/expr"
 )
 
 func TestEvaluateExecutionResult(t *testing.T) {
@@ -340,3 +344,57 @@ func TestEvaluateExecutionResultsNoData(t *testing.T) {
 		require.ElementsMatch(t, []string{"A,B", "C"}, refIDs)
 	})
 }
+
+func TestEvaluateExecutionResultsQueryStats(t *testing.T) {
+	stats := map[string]expr.QueryStats{
+		"A": {SeriesCount: 2, SampleCount: 10, Bytes: 512, Latency: time.Second},
+	}
+
+	t.Run("query stats are added to every result", func(t *testing.T) {
+		results := ExecutionResults{
+			Results: []*data.Frame{
+				data.NewFrame("", data.NewField("", data.Labels{"a": "b"}, []*float64{ptr.Float64(1)})),
+				data.NewFrame("", data.NewField("", data.Labels{"a": "c"}, []*float64{ptr.Float64(0)})),
+			},
+			QueryStats: stats,
+		}
+		v := evaluateExecutionResult(results, time.Time{})
+		require.Len(t, v, 2)
+		for _, r := range v {
+			require.Equal(t, stats, r.QueryStats)
+		}
+	})
+
+	t.Run("query stats are added to errors", func(t *testing.T) {
+		results := ExecutionResults{
+			Error:      errors.New("query B failed"),
+			QueryStats: stats,
+		}
+		v := evaluateExecutionResult(results, time.Time{})
+		require.Len(t, v, 1)
+		require.Equal(t, Error, v[0].State)
+		require.Equal(t, stats, v[0].QueryStats)
+	})
+}
+
+func TestAddQueryStats(t *testing.T) {
+	resp := backend.NewQueryDataResponse()
+	resp.Responses["A"] = backend.DataResponse{Frames: data.Frames{data.NewFrame("A"), data.NewFrame("A")}}
+	resp.Responses["B"] = backend.DataResponse{}
+
+	addQueryStats(resp, map[string]expr.QueryStats{
+		"A": {SeriesCount: 2, SampleCount: 10, Bytes: 512, Latency: 1500 * time.Microsecond},
+		"B": {},
+	})
+
+	frames := resp.Responses["A"].Frames
+	require.NotNil(t, frames[0].Meta)
+	require.Equal(t, []data.QueryStat{
+		{FieldConfig: data.FieldConfig{DisplayName: "Series"}, Value: 2},
+		{FieldConfig: data.FieldConfig{DisplayName: "Samples"}, Value: 10},
+		{FieldConfig: data.FieldConfig{DisplayName: "Bytes", Unit: "decbytes"}, Value: 512},
+		{FieldConfig: data.FieldConfig{DisplayName: "Data source latency", Unit: "ms"}, Value: 1.5},
+	}, frames[0].Meta.Stats)
+	require.Nil(t, frames[1].Meta)
+	require.Empty(t, resp.Responses["B"].Frames)
+}
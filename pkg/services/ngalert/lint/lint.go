@@ -0,0 +1,212 @@
+// Package lint checks alert rules against the lint policies configured for their organization.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RunbookURLAnnotation is the annotation required by the requireRunbook policy.
+const RunbookURLAnnotation = "runbook_url"
+
+var (
+	ErrInvalidConfig       = errors.New("invalid rule lint configuration")
+	ErrInvalidFolderPolicy = errors.New("invalid folder rule policy")
+)
+
+// DefaultConfig returns the configuration of organizations that did not configure rule linting. All policies are
+// off, their parameters default to the most common conventions.
+func DefaultConfig() definitions.RuleLintConfig {
+	return definitions.RuleLintConfig{
+		RequireRunbook: definitions.RuleLintPolicy{
+			Severity: definitions.RuleLintSeverityOff,
+		},
+		RequireTeamLabel: definitions.RuleLintLabelPolicy{
+			Severity: definitions.RuleLintSeverityOff,
+			Label:    "team",
+		},
+		ForbidZeroFor: definitions.RuleLintForbidZeroForPolicy{
+			Severity: definitions.RuleLintSeverityOff,
+			Label:    "severity",
+			Values:   []string{"critical", "page"},
+		},
+		MaxQueries: definitions.RuleLintMaxQueriesPolicy{
+			Severity: definitions.RuleLintSeverityOff,
+			Max:      5,
+		},
+	}
+}
+
+// ValidateConfig checks that the severities of the policies are known and that the policies that are not off have
+// all their parameters.
+func ValidateConfig(cfg definitions.RuleLintConfig) error {
+	severities := map[string]definitions.RuleLintSeverity{
+		definitions.RuleLintPolicyRequireRunbook:   cfg.RequireRunbook.Severity,
+		definitions.RuleLintPolicyRequireTeamLabel: cfg.RequireTeamLabel.Severity,
+		definitions.RuleLintPolicyForbidZeroFor:    cfg.ForbidZeroFor.Severity,
+		definitions.RuleLintPolicyMaxQueries:       cfg.MaxQueries.Severity,
+	}
+	for policy, severity := range severities {
+		switch severity {
+		case "", definitions.RuleLintSeverityOff, definitions.RuleLintSeverityWarning, definitions.RuleLintSeverityError:
+		default:
+			return fmt.Errorf("%w: unknown severity %q of policy %s", ErrInvalidConfig, severity, policy)
+		}
+	}
+
+	if enabled(cfg.RequireTeamLabel.Severity) && cfg.RequireTeamLabel.Label == "" {
+		return fmt.Errorf("%w: policy %s requires a label", ErrInvalidConfig, definitions.RuleLintPolicyRequireTeamLabel)
+	}
+	if enabled(cfg.ForbidZeroFor.Severity) && (cfg.ForbidZeroFor.Label == "" || len(cfg.ForbidZeroFor.Values) == 0) {
+		return fmt.Errorf("%w: policy %s requires a label and at least one value", ErrInvalidConfig, definitions.RuleLintPolicyForbidZeroFor)
+	}
+	if enabled(cfg.MaxQueries.Severity) && cfg.MaxQueries.Max < 1 {
+		return fmt.Errorf("%w: policy %s requires a maximum of at least 1", ErrInvalidConfig, definitions.RuleLintPolicyMaxQueries)
+	}
+	return nil
+}
+
+// Lint returns the violations of the policies of the configuration by the rule.
+func Lint(cfg definitions.RuleLintConfig, rule *models.AlertRule) []definitions.RuleLintViolation {
+	violations := make([]definitions.RuleLintViolation, 0)
+	report := func(policy string, severity definitions.RuleLintSeverity, format string, args ...interface{}) {
+		violations = append(violations, definitions.RuleLintViolation{
+			RuleUID:   rule.UID,
+			RuleTitle: rule.Title,
+			Policy:    policy,
+			Severity:  severity,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	if p := cfg.RequireRunbook; enabled(p.Severity) && strings.TrimSpace(rule.Annotations[RunbookURLAnnotation]) == "" {
+		report(definitions.RuleLintPolicyRequireRunbook, p.Severity, "the annotation %s is required", RunbookURLAnnotation)
+	}
+
+	if p := cfg.RequireTeamLabel; enabled(p.Severity) && strings.TrimSpace(rule.Labels[p.Label]) == "" {
+		report(definitions.RuleLintPolicyRequireTeamLabel, p.Severity, "the label %s is required", p.Label)
+	}
+
+	if p := cfg.ForbidZeroFor; enabled(p.Severity) && rule.For == 0 {
+		value, ok := rule.Labels[p.Label]
+		if ok && containsFold(p.Values, value) {
+			report(definitions.RuleLintPolicyForbidZeroFor, p.Severity, "rules with %s=%s must have a pending period greater than 0s", p.Label, value)
+		}
+	}
+
+	if p := cfg.MaxQueries; enabled(p.Severity) {
+		queries := 0
+		for _, q := range rule.Data {
+			if !expr.IsDataSource(q.DatasourceUID) {
+				queries++
+			}
+		}
+		if queries > p.Max {
+			report(definitions.RuleLintPolicyMaxQueries, p.Severity, "the rule has %d queries, at most %d are allowed", queries, p.Max)
+		}
+	}
+
+	return violations
+}
+
+// ValidateFolderPolicy checks that the minimum interval is not negative and that the required labels and allowed
+// data sources are not empty strings.
+func ValidateFolderPolicy(policy definitions.FolderRulePolicy) error {
+	if policy.MinIntervalSeconds < 0 {
+		return fmt.Errorf("%w: the minimum interval must not be negative", ErrInvalidFolderPolicy)
+	}
+	for _, label := range policy.RequiredLabels {
+		if strings.TrimSpace(label) == "" {
+			return fmt.Errorf("%w: required labels must not be empty", ErrInvalidFolderPolicy)
+		}
+	}
+	for _, uid := range policy.AllowedDatasourceUIDs {
+		if strings.TrimSpace(uid) == "" {
+			return fmt.Errorf("%w: allowed data sources must not be empty", ErrInvalidFolderPolicy)
+		}
+	}
+	return nil
+}
+
+// LintFolderPolicy returns the violations of the rule policy of its folder by the rule. The interval is only checked
+// for rules that have one, rules that are added to a new group by the provisioning API get theirs when saved.
+func LintFolderPolicy(policy definitions.FolderRulePolicy, rule *models.AlertRule) []definitions.RuleLintViolation {
+	violations := make([]definitions.RuleLintViolation, 0)
+	report := func(p string, format string, args ...interface{}) {
+		violations = append(violations, definitions.RuleLintViolation{
+			RuleUID:   rule.UID,
+			RuleTitle: rule.Title,
+			Policy:    p,
+			Severity:  definitions.RuleLintSeverityError,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	if rule.IntervalSeconds > 0 && rule.IntervalSeconds < policy.MinIntervalSeconds {
+		report(definitions.RuleLintPolicyFolderMinInterval, "the folder requires an interval of at least %ds, the rule group has %ds", policy.MinIntervalSeconds, rule.IntervalSeconds)
+	}
+
+	for _, label := range policy.RequiredLabels {
+		if strings.TrimSpace(rule.Labels[label]) == "" {
+			report(definitions.RuleLintPolicyFolderRequiredLabels, "the folder requires the label %s", label)
+		}
+	}
+
+	if len(policy.AllowedDatasourceUIDs) > 0 {
+		for _, q := range rule.Data {
+			if expr.IsDataSource(q.DatasourceUID) || contains(policy.AllowedDatasourceUIDs, q.DatasourceUID) {
+				continue
+			}
+			report(definitions.RuleLintPolicyFolderAllowedDatasources, "the folder does not allow the data source %s of query %s", q.DatasourceUID, q.RefID)
+		}
+	}
+
+	return violations
+}
+
+// HasErrors returns true if any of the violations has the severity error.
+func HasErrors(violations []definitions.RuleLintViolation) bool {
+	for _, v := range violations {
+		if v.Severity == definitions.RuleLintSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func enabled(severity definitions.RuleLintSeverity) bool {
+	return severity == definitions.RuleLintSeverityWarning || severity == definitions.RuleLintSeverityError
+}
+
+func isFolderPolicy(policy string) bool {
+	switch policy {
+	case definitions.RuleLintPolicyFolderMinInterval,
+		definitions.RuleLintPolicyFolderRequiredLabels,
+		definitions.RuleLintPolicyFolderAllowedDatasources:
+		return true
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
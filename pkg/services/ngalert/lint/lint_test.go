@@ -0,0 +1,218 @@
+package lint
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestLint(t *testing.T) {
+	allPolicies := func(severity definitions.RuleLintSeverity) definitions.RuleLintConfig {
+		cfg := DefaultConfig()
+		cfg.RequireRunbook.Severity = severity
+		cfg.RequireTeamLabel.Severity = severity
+		cfg.ForbidZeroFor.Severity = severity
+		cfg.MaxQueries.Severity = severity
+		cfg.MaxQueries.Max = 1
+		return cfg
+	}
+
+	t.Run("no violations by default", func(t *testing.T) {
+		violations := Lint(DefaultConfig(), &models.AlertRule{UID: "rule"})
+
+		require.Empty(t, violations)
+	})
+
+	t.Run("compliant rule has no violations", func(t *testing.T) {
+		rule := &models.AlertRule{
+			UID:         "rule",
+			For:         time.Minute,
+			Labels:      map[string]string{"team": "alerting", "severity": "critical"},
+			Annotations: map[string]string{RunbookURLAnnotation: "https://runbooks.example.com/rule"},
+			Data: []models.AlertQuery{
+				{RefID: "A", DatasourceUID: "prometheus"},
+				{RefID: "B", DatasourceUID: expr.DatasourceUID},
+			},
+		}
+
+		violations := Lint(allPolicies(definitions.RuleLintSeverityError), rule)
+
+		require.Empty(t, violations)
+	})
+
+	t.Run("reports a violation for every policy", func(t *testing.T) {
+		rule := &models.AlertRule{
+			UID:    "rule",
+			Title:  "High latency",
+			Labels: map[string]string{"severity": "Page"},
+			Data: []models.AlertQuery{
+				{RefID: "A", DatasourceUID: "prometheus"},
+				{RefID: "B", DatasourceUID: "loki"},
+				{RefID: "C", DatasourceUID: expr.DatasourceUID},
+			},
+		}
+
+		violations := Lint(allPolicies(definitions.RuleLintSeverityWarning), rule)
+
+		policies := make([]string, 0, len(violations))
+		for _, v := range violations {
+			require.Equal(t, "rule", v.RuleUID)
+			require.Equal(t, "High latency", v.RuleTitle)
+			require.Equal(t, definitions.RuleLintSeverityWarning, v.Severity)
+			require.NotEmpty(t, v.Message)
+			policies = append(policies, v.Policy)
+		}
+		require.Equal(t, []string{
+			definitions.RuleLintPolicyRequireRunbook,
+			definitions.RuleLintPolicyRequireTeamLabel,
+			definitions.RuleLintPolicyForbidZeroFor,
+			definitions.RuleLintPolicyMaxQueries,
+		}, policies)
+		require.False(t, HasErrors(violations))
+	})
+
+	t.Run("zero pending period is allowed for other severities", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.ForbidZeroFor.Severity = definitions.RuleLintSeverityError
+		rule := &models.AlertRule{Labels: map[string]string{"severity": "info"}}
+
+		require.Empty(t, Lint(cfg, rule))
+	})
+}
+
+func TestValidateConfig(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(cfg *definitions.RuleLintConfig)
+		valid  bool
+	}{
+		{
+			name:   "default config",
+			modify: func(cfg *definitions.RuleLintConfig) {},
+			valid:  true,
+		},
+		{
+			name:   "empty severity is off",
+			modify: func(cfg *definitions.RuleLintConfig) { cfg.RequireRunbook.Severity = "" },
+			valid:  true,
+		},
+		{
+			name:   "unknown severity",
+			modify: func(cfg *definitions.RuleLintConfig) { cfg.RequireRunbook.Severity = "fatal" },
+		},
+		{
+			name: "required label without name",
+			modify: func(cfg *definitions.RuleLintConfig) {
+				cfg.RequireTeamLabel.Severity = definitions.RuleLintSeverityWarning
+				cfg.RequireTeamLabel.Label = ""
+			},
+		},
+		{
+			name: "forbidden zero pending period without values",
+			modify: func(cfg *definitions.RuleLintConfig) {
+				cfg.ForbidZeroFor.Severity = definitions.RuleLintSeverityError
+				cfg.ForbidZeroFor.Values = nil
+			},
+		},
+		{
+			name: "maximum of queries below 1",
+			modify: func(cfg *definitions.RuleLintConfig) {
+				cfg.MaxQueries.Severity = definitions.RuleLintSeverityError
+				cfg.MaxQueries.Max = 0
+			},
+		},
+		{
+			name:   "missing parameters of policies that are off",
+			modify: func(cfg *definitions.RuleLintConfig) { cfg.MaxQueries.Max = 0 },
+			valid:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			c.modify(&cfg)
+
+			err := ValidateConfig(cfg)
+
+			if c.valid {
+				require.NoError(t, err)
+			} else {
+				require.True(t, errors.Is(err, ErrInvalidConfig))
+			}
+		})
+	}
+}
+
+func TestLintFolderPolicy(t *testing.T) {
+	policy := definitions.FolderRulePolicy{
+		MinIntervalSeconds:    300,
+		RequiredLabels:        []string{"team", "service"},
+		AllowedDatasourceUIDs: []string{"prometheus"},
+	}
+
+	t.Run("compliant rule has no violations", func(t *testing.T) {
+		rule := &models.AlertRule{
+			UID:             "rule",
+			IntervalSeconds: 300,
+			Labels:          map[string]string{"team": "alerting", "service": "api"},
+			Data: []models.AlertQuery{
+				{RefID: "A", DatasourceUID: "prometheus"},
+				{RefID: "B", DatasourceUID: expr.DatasourceUID},
+			},
+		}
+
+		require.Empty(t, LintFolderPolicy(policy, rule))
+	})
+
+	t.Run("reports a violation for every broken constraint", func(t *testing.T) {
+		rule := &models.AlertRule{
+			UID:             "rule",
+			IntervalSeconds: 60,
+			Labels:          map[string]string{"team": "alerting"},
+			Data: []models.AlertQuery{
+				{RefID: "A", DatasourceUID: "loki"},
+			},
+		}
+
+		violations := LintFolderPolicy(policy, rule)
+
+		policies := make([]string, 0, len(violations))
+		for _, v := range violations {
+			require.Equal(t, definitions.RuleLintSeverityError, v.Severity)
+			policies = append(policies, v.Policy)
+		}
+		require.Equal(t, []string{
+			definitions.RuleLintPolicyFolderMinInterval,
+			definitions.RuleLintPolicyFolderRequiredLabels,
+			definitions.RuleLintPolicyFolderAllowedDatasources,
+		}, policies)
+	})
+
+	t.Run("rules without interval are not checked for the minimum interval", func(t *testing.T) {
+		rule := &models.AlertRule{
+			UID:    "rule",
+			Labels: map[string]string{"team": "alerting", "service": "api"},
+		}
+
+		require.Empty(t, LintFolderPolicy(policy, rule))
+	})
+}
+
+func TestValidateFolderPolicy(t *testing.T) {
+	require.NoError(t, ValidateFolderPolicy(definitions.FolderRulePolicy{}))
+	require.NoError(t, ValidateFolderPolicy(definitions.FolderRulePolicy{MinIntervalSeconds: 60, RequiredLabels: []string{"team"}}))
+
+	err := ValidateFolderPolicy(definitions.FolderRulePolicy{MinIntervalSeconds: -1})
+	require.True(t, errors.Is(err, ErrInvalidFolderPolicy))
+	err = ValidateFolderPolicy(definitions.FolderRulePolicy{RequiredLabels: []string{" "}})
+	require.True(t, errors.Is(err, ErrInvalidFolderPolicy))
+	err = ValidateFolderPolicy(definitions.FolderRulePolicy{AllowedDatasourceUIDs: []string{""}})
+	require.True(t, errors.Is(err, ErrInvalidFolderPolicy))
+}
@@ -0,0 +1,157 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	KVNamespace     = "ngalert.rule_lint"
+	configKey       = "config"
+	folderKeyPrefix = "folder/"
+)
+
+// ErrRulesRejected is returned by CheckRules when rule linting is enforced and a rule violates a policy of severity
+// error, or when a rule violates the rule policy of its folder.
+var ErrRulesRejected = errors.New("alert rules violate lint policies")
+
+// Service stores the rule lint configuration of every organization and the rule policies of its folders, and lints
+// rules against them.
+type Service struct {
+	kvStore kvstore.KVStore
+	log     log.Logger
+}
+
+func NewService(kvStore kvstore.KVStore, log log.Logger) *Service {
+	return &Service{
+		kvStore: kvStore,
+		log:     log,
+	}
+}
+
+// GetConfig returns the rule lint configuration of the organization, or the default configuration if the
+// organization has none.
+func (s *Service) GetConfig(ctx context.Context, orgID int64) (definitions.RuleLintConfig, error) {
+	raw, ok, err := kvstore.WithNamespace(s.kvStore, orgID, KVNamespace).Get(ctx, configKey)
+	if err != nil {
+		return definitions.RuleLintConfig{}, err
+	}
+	if !ok {
+		return DefaultConfig(), nil
+	}
+	cfg := DefaultConfig()
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return definitions.RuleLintConfig{}, fmt.Errorf("failed to unmarshal rule lint configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// SaveConfig validates and stores the rule lint configuration of the organization.
+func (s *Service) SaveConfig(ctx context.Context, orgID int64, cfg definitions.RuleLintConfig) error {
+	if err := ValidateConfig(cfg); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return kvstore.WithNamespace(s.kvStore, orgID, KVNamespace).Set(ctx, configKey, string(raw))
+}
+
+// GetFolderPolicy returns the rule policy of the folder, or an empty policy if the folder has none.
+func (s *Service) GetFolderPolicy(ctx context.Context, orgID int64, folderUID string) (definitions.FolderRulePolicy, error) {
+	raw, ok, err := kvstore.WithNamespace(s.kvStore, orgID, KVNamespace).Get(ctx, folderKeyPrefix+folderUID)
+	if err != nil {
+		return definitions.FolderRulePolicy{}, err
+	}
+	var policy definitions.FolderRulePolicy
+	if !ok {
+		return policy, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+		return definitions.FolderRulePolicy{}, fmt.Errorf("failed to unmarshal rule policy of folder %s: %w", folderUID, err)
+	}
+	return policy, nil
+}
+
+// SaveFolderPolicy validates and stores the rule policy of the folder.
+func (s *Service) SaveFolderPolicy(ctx context.Context, orgID int64, folderUID string, policy definitions.FolderRulePolicy) error {
+	if err := ValidateFolderPolicy(policy); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return kvstore.WithNamespace(s.kvStore, orgID, KVNamespace).Set(ctx, folderKeyPrefix+folderUID, string(raw))
+}
+
+// DeleteFolderPolicy removes the rule policy of the folder.
+func (s *Service) DeleteFolderPolicy(ctx context.Context, orgID int64, folderUID string) error {
+	return kvstore.WithNamespace(s.kvStore, orgID, KVNamespace).Del(ctx, folderKeyPrefix+folderUID)
+}
+
+// CheckFolderPolicy returns the violations of the rule policy of its folder by the rule.
+func (s *Service) CheckFolderPolicy(ctx context.Context, orgID int64, rule *models.AlertRule) ([]definitions.RuleLintViolation, error) {
+	policy, err := s.GetFolderPolicy(ctx, orgID, rule.NamespaceUID)
+	if err != nil {
+		return nil, err
+	}
+	return LintFolderPolicy(policy, rule), nil
+}
+
+// LintRules lints the rules against the configuration of the organization and the rule policies of their folders.
+func (s *Service) LintRules(ctx context.Context, orgID int64, rules []*models.AlertRule) (definitions.RuleLintReport, error) {
+	cfg, err := s.GetConfig(ctx, orgID)
+	if err != nil {
+		return definitions.RuleLintReport{}, err
+	}
+	report := definitions.RuleLintReport{
+		Enforced:   cfg.Enforce,
+		Violations: make([]definitions.RuleLintViolation, 0),
+	}
+	policies := make(map[string]definitions.FolderRulePolicy)
+	for _, rule := range rules {
+		report.Violations = append(report.Violations, Lint(cfg, rule)...)
+
+		policy, ok := policies[rule.NamespaceUID]
+		if !ok {
+			policy, err = s.GetFolderPolicy(ctx, orgID, rule.NamespaceUID)
+			if err != nil {
+				return definitions.RuleLintReport{}, err
+			}
+			policies[rule.NamespaceUID] = policy
+		}
+		report.Violations = append(report.Violations, LintFolderPolicy(policy, rule)...)
+	}
+	return report, nil
+}
+
+// CheckRules lints rules that are about to be saved. It returns the violations together with ErrRulesRejected if
+// linting is enforced in the organization and any of the violations is an error, or if any of the rules violates
+// the rule policy of its folder.
+func (s *Service) CheckRules(ctx context.Context, orgID int64, rules []*models.AlertRule) ([]definitions.RuleLintViolation, error) {
+	report, err := s.LintRules(ctx, orgID, rules)
+	if err != nil {
+		return nil, err
+	}
+	if report.Enforced && HasErrors(report.Violations) {
+		return report.Violations, ErrRulesRejected
+	}
+	for _, v := range report.Violations {
+		if isFolderPolicy(v.Policy) {
+			return report.Violations, ErrRulesRejected
+		}
+	}
+	if len(report.Violations) > 0 {
+		s.log.Debug("saving alert rules with lint violations", "orgID", orgID, "violations", len(report.Violations))
+	}
+	return report.Violations, nil
+}
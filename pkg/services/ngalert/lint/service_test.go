@@ -0,0 +1,137 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	noRunbook := []*models.AlertRule{{UID: "rule"}}
+
+	t.Run("organizations without configuration get the default one", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), log.NewNopLogger())
+
+		cfg, err := sut.GetConfig(ctx, 1)
+
+		require.NoError(t, err)
+		require.Equal(t, DefaultConfig(), cfg)
+	})
+
+	t.Run("configuration is saved per organization", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), log.NewNopLogger())
+		cfg := DefaultConfig()
+		cfg.Enforce = true
+		cfg.RequireRunbook.Severity = definitions.RuleLintSeverityError
+
+		require.NoError(t, sut.SaveConfig(ctx, 1, cfg))
+
+		saved, err := sut.GetConfig(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, cfg, saved)
+		other, err := sut.GetConfig(ctx, 2)
+		require.NoError(t, err)
+		require.Equal(t, DefaultConfig(), other)
+	})
+
+	t.Run("invalid configuration is not saved", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), log.NewNopLogger())
+		cfg := DefaultConfig()
+		cfg.RequireRunbook.Severity = "fatal"
+
+		err := sut.SaveConfig(ctx, 1, cfg)
+
+		require.True(t, errors.Is(err, ErrInvalidConfig))
+		saved, err := sut.GetConfig(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, DefaultConfig(), saved)
+	})
+
+	t.Run("rules with errors are rejected if enforced", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), log.NewNopLogger())
+		cfg := DefaultConfig()
+		cfg.Enforce = true
+		cfg.RequireRunbook.Severity = definitions.RuleLintSeverityError
+		require.NoError(t, sut.SaveConfig(ctx, 1, cfg))
+
+		violations, err := sut.CheckRules(ctx, 1, noRunbook)
+
+		require.True(t, errors.Is(err, ErrRulesRejected))
+		require.Len(t, violations, 1)
+	})
+
+	t.Run("rules with errors are accepted if not enforced", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), log.NewNopLogger())
+		cfg := DefaultConfig()
+		cfg.RequireRunbook.Severity = definitions.RuleLintSeverityError
+		require.NoError(t, sut.SaveConfig(ctx, 1, cfg))
+
+		violations, err := sut.CheckRules(ctx, 1, noRunbook)
+
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+	})
+
+	t.Run("rules with warnings are accepted if enforced", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), log.NewNopLogger())
+		cfg := DefaultConfig()
+		cfg.Enforce = true
+		cfg.RequireRunbook.Severity = definitions.RuleLintSeverityWarning
+		require.NoError(t, sut.SaveConfig(ctx, 1, cfg))
+
+		violations, err := sut.CheckRules(ctx, 1, noRunbook)
+
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+	})
+
+	t.Run("folder policies are saved per folder and can be deleted", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), log.NewNopLogger())
+		policy := definitions.FolderRulePolicy{MinIntervalSeconds: 300, RequiredLabels: []string{"team"}}
+
+		require.NoError(t, sut.SaveFolderPolicy(ctx, 1, "folder", policy))
+
+		saved, err := sut.GetFolderPolicy(ctx, 1, "folder")
+		require.NoError(t, err)
+		require.Equal(t, policy, saved)
+		other, err := sut.GetFolderPolicy(ctx, 1, "other-folder")
+		require.NoError(t, err)
+		require.Equal(t, definitions.FolderRulePolicy{}, other)
+
+		require.NoError(t, sut.DeleteFolderPolicy(ctx, 1, "folder"))
+		saved, err = sut.GetFolderPolicy(ctx, 1, "folder")
+		require.NoError(t, err)
+		require.Equal(t, definitions.FolderRulePolicy{}, saved)
+	})
+
+	t.Run("invalid folder policy is not saved", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), log.NewNopLogger())
+
+		err := sut.SaveFolderPolicy(ctx, 1, "folder", definitions.FolderRulePolicy{MinIntervalSeconds: -1})
+
+		require.True(t, errors.Is(err, ErrInvalidFolderPolicy))
+	})
+
+	t.Run("rules violating the policy of their folder are rejected even if not enforced", func(t *testing.T) {
+		sut := NewService(notifier.NewFakeKVStore(t), log.NewNopLogger())
+		require.NoError(t, sut.SaveFolderPolicy(ctx, 1, "folder", definitions.FolderRulePolicy{RequiredLabels: []string{"team"}}))
+
+		violations, err := sut.CheckRules(ctx, 1, []*models.AlertRule{{UID: "rule", NamespaceUID: "folder"}})
+
+		require.True(t, errors.Is(err, ErrRulesRejected))
+		require.Len(t, violations, 1)
+		require.Equal(t, definitions.RuleLintPolicyFolderRequiredLabels, violations[0].Policy)
+
+		violations, err = sut.CheckRules(ctx, 1, []*models.AlertRule{{UID: "rule", NamespaceUID: "other-folder"}})
+		require.NoError(t, err)
+		require.Empty(t, violations)
+	})
+}
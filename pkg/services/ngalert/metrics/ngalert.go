@@ -43,6 +43,7 @@ type NGAlert struct {
 	stateMetrics                *State
 	multiOrgAlertmanagerMetrics *MultiOrgAlertmanager
 	apiMetrics                  *API
+	provisioningMetrics         *Provisioning
 }
 
 type Scheduler struct {
@@ -63,6 +64,8 @@ type MultiOrgAlertmanager struct {
 	Registerer               prometheus.Registerer
 	ActiveConfigurations     prometheus.Gauge
 	DiscoveredConfigurations prometheus.Gauge
+	ConfigHistoryPurged      prometheus.Counter
+	ConfigSize               *prometheus.GaugeVec
 	registries               *OrgRegistries
 }
 
@@ -70,14 +73,19 @@ type API struct {
 	RequestDuration *prometheus.HistogramVec
 }
 
+type Provisioning struct {
+	RuleLimitExceeded *prometheus.CounterVec
+}
+
 type Alertmanager struct {
 	Registerer prometheus.Registerer
 	*metrics.Alerts
 }
 
 type State struct {
-	GroupRules *prometheus.GaugeVec
-	AlertState *prometheus.GaugeVec
+	GroupRules            *prometheus.GaugeVec
+	AlertState            *prometheus.GaugeVec
+	InstanceLimitExceeded *prometheus.CounterVec
 }
 
 func (ng *NGAlert) GetSchedulerMetrics() *Scheduler {
@@ -92,6 +100,10 @@ func (ng *NGAlert) GetAPIMetrics() *API {
 	return ng.apiMetrics
 }
 
+func (ng *NGAlert) GetProvisioningMetrics() *Provisioning {
+	return ng.provisioningMetrics
+}
+
 func (ng *NGAlert) GetMultiOrgAlertmanagerMetrics() *MultiOrgAlertmanager {
 	return ng.multiOrgAlertmanagerMetrics
 }
@@ -104,6 +116,7 @@ func NewNGAlert(r prometheus.Registerer) *NGAlert {
 		stateMetrics:                newStateMetrics(r),
 		multiOrgAlertmanagerMetrics: newMultiOrgAlertmanagerMetrics(r),
 		apiMetrics:                  newAPIMetrics(r),
+		provisioningMetrics:         newProvisioningMetrics(r),
 	}
 }
 
@@ -231,6 +244,12 @@ func newStateMetrics(r prometheus.Registerer) *State {
 			Name:      "alerts",
 			Help:      "How many alerts by state.",
 		}, []string{"state"}),
+		InstanceLimitExceeded: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "alert_instance_limit_exceeded_total",
+			Help:      "The number of rule evaluations that produced more alert instances than the limit of the rule.",
+		}, []string{"org"}),
 	}
 }
 
@@ -250,6 +269,18 @@ func newMultiOrgAlertmanagerMetrics(r prometheus.Registerer) *MultiOrgAlertmanag
 			Name:      "active_configurations",
 			Help:      "The number of active Alertmanager configurations.",
 		}),
+		ConfigHistoryPurged: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "config_history_purged_total",
+			Help:      "The number of stored versions of Alertmanager configurations removed by the retention of the configuration history.",
+		}),
+		ConfigSize: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "alertmanager_config_size_bytes",
+			Help:      "The size in bytes of the serialized Alertmanager configuration applied to the Alertmanager of an organization.",
+		}, []string{"org"}),
 	}
 }
 
@@ -268,6 +299,17 @@ func newAPIMetrics(r prometheus.Registerer) *API {
 	}
 }
 
+func newProvisioningMetrics(r prometheus.Registerer) *Provisioning {
+	return &Provisioning{
+		RuleLimitExceeded: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "provisioning_rule_limit_exceeded_total",
+			Help:      "The number of changes to provisioned alert rules that were rejected because they exceed a limit on the alert rules.",
+		}, []string{"org", "limit"}),
+	}
+}
+
 // OrgRegistries represents a map of registries per org.
 type OrgRegistries struct {
 	regsMu sync.Mutex
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -280,3 +282,54 @@ func (aq *AlertQuery) PreSave() error {
 	}
 	return nil
 }
+
+// SetModelValue sets the value at the given path of the model. The path is a dot-separated list of object
+// keys and array indices, e.g. "conditions.0.evaluator.params.0". All but the last element of the path must
+// exist in the model.
+func (aq *AlertQuery) SetModelValue(path string, value interface{}) error {
+	if path == "" {
+		return errors.New("path must not be empty")
+	}
+	var model interface{}
+	if err := json.Unmarshal(aq.Model, &model); err != nil {
+		return fmt.Errorf("failed to unmarshal query model: %w", err)
+	}
+
+	keys := strings.Split(path, ".")
+	current := model
+	for i, key := range keys {
+		last := i == len(keys)-1
+		switch node := current.(type) {
+		case map[string]interface{}:
+			if last {
+				node[key] = value
+				break
+			}
+			next, ok := node[key]
+			if !ok {
+				return fmt.Errorf("path %q does not exist in the query model: missing key %q", path, key)
+			}
+			current = next
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return fmt.Errorf("path %q does not exist in the query model: invalid index %q", path, key)
+			}
+			if last {
+				node[idx] = value
+				break
+			}
+			current = node[idx]
+		default:
+			return fmt.Errorf("path %q does not exist in the query model: %q is not an object or array", path, strings.Join(keys[:i], "."))
+		}
+	}
+
+	b, err := json.Marshal(model)
+	if err != nil {
+		return fmt.Errorf("unable to marshal query model: %w", err)
+	}
+	aq.Model = b
+	aq.modelProps = nil
+	return nil
+}
@@ -281,3 +281,66 @@ func TestAlertQuery_GetQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestAlertQuery_SetModelValue(t *testing.T) {
+	model := `{"expr": "up", "conditions": [{"evaluator": {"params": [3], "type": "gt"}}]}`
+
+	tc := []struct {
+		name     string
+		path     string
+		value    interface{}
+		expected string
+		err      string
+	}{
+		{
+			name:     "sets an existing key",
+			path:     "expr",
+			value:    "down",
+			expected: `{"expr": "down", "conditions": [{"evaluator": {"params": [3], "type": "gt"}}]}`,
+		},
+		{
+			name:     "adds a new key",
+			path:     "conditions.0.evaluator.unit",
+			value:    "ms",
+			expected: `{"expr": "up", "conditions": [{"evaluator": {"params": [3], "type": "gt", "unit": "ms"}}]}`,
+		},
+		{
+			name:     "sets an array element",
+			path:     "conditions.0.evaluator.params.0",
+			value:    10,
+			expected: `{"expr": "up", "conditions": [{"evaluator": {"params": [10], "type": "gt"}}]}`,
+		},
+		{
+			name:  "fails for a missing intermediate key",
+			path:  "missing.key",
+			value: 1,
+			err:   `path "missing.key" does not exist in the query model: missing key "missing"`,
+		},
+		{
+			name:  "fails for an index out of range",
+			path:  "conditions.1.evaluator",
+			value: 1,
+			err:   `path "conditions.1.evaluator" does not exist in the query model: invalid index "1"`,
+		},
+		{
+			name:  "fails for a path through a scalar",
+			path:  "expr.value",
+			value: 1,
+			err:   `path "expr.value" does not exist in the query model: "expr" is not an object or array`,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			q := AlertQuery{Model: json.RawMessage(model)}
+			err := q.SetModelValue(tt.path, tt.value)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				require.JSONEq(t, model, string(q.Model))
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(q.Model))
+		})
+	}
+}
@@ -96,6 +96,13 @@ const (
 
 	// FolderTitleLabel is the label that will contain the title of an alert's folder/namespace.
 	FolderTitleLabel = GrafanaReservedLabelPrefix + "folder"
+
+	// InstanceLimitExceededLabel is the label of the alert that replaces the alert instances of an evaluation that
+	// produced more alert instances than allowed.
+	InstanceLimitExceededLabel = GrafanaReservedLabelPrefix + "instance_limit_exceeded"
+	// InstanceCountAnnotation is the annotation with the number of alert instances of the evaluation on the alert that
+	// replaces them.
+	InstanceCountAnnotation = GrafanaReservedLabelPrefix + "instance_count"
 )
 
 var (
@@ -134,6 +141,15 @@ type AlertRule struct {
 	For         time.Duration
 	Annotations map[string]string
 	Labels      map[string]string
+	Webhooks    []AlertRuleWebhook
+	// IsPaused rules are not evaluated.
+	IsPaused bool
+	// MaxInstances is the maximum number of alert instances of an evaluation of the rule. The alert instances are
+	// replaced with a single alert if there are more. The default limit of the server is used if it is zero.
+	MaxInstances int64
+	// GroupLabels are the labels declared by the rule group when it was provisioned. They are merged into Labels,
+	// and are only kept to tell them apart from the labels of the rule in exports.
+	GroupLabels map[string]string
 }
 
 type SchedulableAlertRule struct {
@@ -273,6 +289,15 @@ type AlertRuleVersion struct {
 	For         time.Duration
 	Annotations map[string]string
 	Labels      map[string]string
+	Webhooks    []AlertRuleWebhook
+	// IsPaused rules are not evaluated.
+	IsPaused bool
+	// MaxInstances is the maximum number of alert instances of an evaluation of the rule. The alert instances are
+	// replaced with a single alert if there are more. The default limit of the server is used if it is zero.
+	MaxInstances int64
+	// GroupLabels are the labels declared by the rule group when it was provisioned. They are merged into Labels,
+	// and are only kept to tell them apart from the labels of the rule in exports.
+	GroupLabels map[string]string
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
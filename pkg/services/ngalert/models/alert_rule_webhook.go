@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// AlertRuleWebhookEvent is a state transition of an alert instance that can be sent to the webhooks of its rule.
+type AlertRuleWebhookEvent string
+
+const (
+	// AlertRuleWebhookFiring is sent when an alert instance starts firing.
+	AlertRuleWebhookFiring AlertRuleWebhookEvent = "firing"
+	// AlertRuleWebhookResolved is sent when a firing alert instance is resolved.
+	AlertRuleWebhookResolved AlertRuleWebhookEvent = "resolved"
+	// AlertRuleWebhookError is sent when the evaluation of an alert instance starts failing.
+	AlertRuleWebhookError AlertRuleWebhookEvent = "error"
+)
+
+// AlertRuleWebhook is a URL that the state manager calls when the alert instances of a rule change state.
+// Unlike contact points, rule webhooks do not go through the notification policies of the Alertmanager.
+type AlertRuleWebhook struct {
+	URL string `json:"url" yaml:"url"`
+	// Events are the state transitions sent to the webhook. All of them are sent if it is empty.
+	Events []AlertRuleWebhookEvent `json:"events,omitempty" yaml:"events,omitempty"`
+}
+
+// Subscribed returns true if the event should be sent to the webhook.
+func (w AlertRuleWebhook) Subscribed(event AlertRuleWebhookEvent) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the URL of the webhook is an absolute HTTP URL and that its events are known.
+func (w AlertRuleWebhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return fmt.Errorf("%w: invalid webhook URL: %s", ErrAlertRuleFailedValidation, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: webhook URL '%s' must be an absolute http or https URL", ErrAlertRuleFailedValidation, w.URL)
+	}
+	for _, e := range w.Events {
+		switch e {
+		case AlertRuleWebhookFiring, AlertRuleWebhookResolved, AlertRuleWebhookError:
+		default:
+			return fmt.Errorf("%w: unknown webhook event '%s'", ErrAlertRuleFailedValidation, e)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleWebhook(t *testing.T) {
+	t.Run("all events are sent if none is selected", func(t *testing.T) {
+		w := AlertRuleWebhook{URL: "http://localhost"}
+		require.True(t, w.Subscribed(AlertRuleWebhookFiring))
+		require.True(t, w.Subscribed(AlertRuleWebhookError))
+
+		w.Events = []AlertRuleWebhookEvent{AlertRuleWebhookResolved}
+		require.False(t, w.Subscribed(AlertRuleWebhookFiring))
+		require.True(t, w.Subscribed(AlertRuleWebhookResolved))
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			webhook AlertRuleWebhook
+			valid   bool
+		}{
+			{name: "valid", webhook: AlertRuleWebhook{URL: "https://example.com/hook", Events: []AlertRuleWebhookEvent{AlertRuleWebhookFiring}}, valid: true},
+			{name: "relative URL", webhook: AlertRuleWebhook{URL: "/hook"}},
+			{name: "unsupported scheme", webhook: AlertRuleWebhook{URL: "ftp://example.com"}},
+			{name: "unknown event", webhook: AlertRuleWebhook{URL: "https://example.com", Events: []AlertRuleWebhookEvent{"pending"}}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				err := tc.webhook.Validate()
+				if tc.valid {
+					require.NoError(t, err)
+				} else {
+					require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+				}
+			})
+		}
+	})
+}
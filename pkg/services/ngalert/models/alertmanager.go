@@ -2,6 +2,22 @@ package models
 
 const AlertConfigurationVersion = 1
 
+// AlertConfigurationOrigin is what saved a version of the Alerting Engine Configuration.
+type AlertConfigurationOrigin string
+
+const (
+	// AlertConfigurationOriginAPI is a configuration saved with the Alertmanager configuration API.
+	AlertConfigurationOriginAPI AlertConfigurationOrigin = "api"
+	// AlertConfigurationOriginProvisioning is a configuration changed by the provisioning of one of its parts.
+	AlertConfigurationOriginProvisioning AlertConfigurationOrigin = "provisioning"
+	// AlertConfigurationOriginDefault is the default configuration saved for an organization.
+	AlertConfigurationOriginDefault AlertConfigurationOrigin = "default"
+	// AlertConfigurationOriginRestore is a configuration restored from a backup.
+	AlertConfigurationOriginRestore AlertConfigurationOrigin = "restore"
+	// AlertConfigurationOriginRollback is a previous version of the configuration saved again.
+	AlertConfigurationOriginRollback AlertConfigurationOrigin = "rollback"
+)
+
 // AlertConfiguration represents a single version of the Alerting Engine Configuration.
 type AlertConfiguration struct {
 	ID int64 `xorm:"pk autoincr 'id'"`
@@ -12,6 +28,10 @@ type AlertConfiguration struct {
 	CreatedAt                 int64 `xorm:"created"`
 	Default                   bool
 	OrgID                     int64 `xorm:"org_id"`
+	// Origin is empty for the versions saved before it was recorded.
+	Origin AlertConfigurationOrigin
+	// CreatedBy is the login of the user who saved the version, if it was saved by a user.
+	CreatedBy string
 }
 
 // GetLatestAlertmanagerConfigurationQuery is the query to get the latest alertmanager configuration.
@@ -27,4 +47,6 @@ type SaveAlertmanagerConfigurationCmd struct {
 	ConfigurationVersion      string
 	Default                   bool
 	OrgID                     int64
+	Origin                    AlertConfigurationOrigin
+	CreatedBy                 string
 }
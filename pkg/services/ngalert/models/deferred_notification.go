@@ -0,0 +1,19 @@
+package models
+
+// DeferredNotification is a notification for an alert that was held back during the quiet hours
+// of a receiver. There is at most one per alert and receiver, holding the latest state of the alert.
+type DeferredNotification struct {
+	ID          int64  `xorm:"pk autoincr 'id'"`
+	OrgID       int64  `xorm:"org_id"`
+	Receiver    string `xorm:"receiver"`
+	Fingerprint string `xorm:"fingerprint"`
+	// Alert is the JSON representation of the alert.
+	Alert     string `xorm:"alert"`
+	CreatedAt int64  `xorm:"created_at"`
+	UpdatedAt int64  `xorm:"updated_at"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (n *DeferredNotification) TableName() string {
+	return "alert_deferred_notification"
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"errors"
+)
+
+// ErrNotificationPolicyVersionNotFound is returned when a version of the notification policy tree does not exist.
+var ErrNotificationPolicyVersionNotFound = errors.New("notification policy version not found")
+
+// NotificationPolicyVersion is a previous version of the notification policy tree of an organization.
+// The versions are independent of the history of the Alertmanager configuration, a new one is stored
+// every time the policy tree is replaced.
+type NotificationPolicyVersion struct {
+	ID      int64 `xorm:"pk autoincr 'id'"`
+	OrgID   int64 `xorm:"org_id"`
+	Version int64 `xorm:"'version'"`
+	// PolicyTree is the JSON representation of the replaced policy tree.
+	PolicyTree string `xorm:"policy_tree"`
+	CreatedAt  int64  `xorm:"created_at"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (v *NotificationPolicyVersion) TableName() string {
+	return "alert_notification_policy_history"
+}
@@ -204,6 +204,8 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		IsPaused:        r.IsPaused,
+		MaxInstances:    r.MaxInstances,
 	}
 
 	if r.DashboardUID != nil {
@@ -241,5 +243,17 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	if r.GroupLabels != nil {
+		result.GroupLabels = make(map[string]string, len(r.GroupLabels))
+		for s, s2 := range r.GroupLabels {
+			result.GroupLabels[s] = s2
+		}
+	}
+
+	for _, w := range r.Webhooks {
+		w.Events = append([]AlertRuleWebhookEvent(nil), w.Events...)
+		result.Webhooks = append(result.Webhooks, w)
+	}
+
 	return &result
 }
@@ -12,13 +12,16 @@ import (
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
+	"github.com/grafana/grafana/pkg/services/ngalert/defaultlabels"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
@@ -37,7 +40,7 @@ func ProvideService(cfg *setting.Cfg, dataSourceCache datasources.CacheService,
 	sqlStore *sqlstore.SQLStore, kvStore kvstore.KVStore, expressionService *expr.Service, dataProxy *datasourceproxy.DataSourceProxyService,
 	quotaService *quota.QuotaService, secretsService secrets.Service, notificationService notifications.Service, m *metrics.NGAlert,
 	folderService dashboards.FolderService, ac accesscontrol.AccessControl, dashboardService dashboards.DashboardService, renderService rendering.Service,
-	bus bus.Bus) (*AlertNG, error) {
+	bus bus.Bus, pluginStore plugins.Store, folderPermissions accesscontrol.FolderPermissionsService) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                 cfg,
 		DataSourceCache:     dataSourceCache,
@@ -56,6 +59,8 @@ func ProvideService(cfg *setting.Cfg, dataSourceCache datasources.CacheService,
 		dashboardService:    dashboardService,
 		renderService:       renderService,
 		bus:                 bus,
+		pluginStore:         pluginStore,
+		folderPermissions:   folderPermissions,
 	}
 
 	if ng.IsDisabled() {
@@ -88,13 +93,15 @@ type AlertNG struct {
 	schedule            schedule.ScheduleService
 	stateManager        *state.Manager
 	folderService       dashboards.FolderService
+	folderPermissions   accesscontrol.FolderPermissionsService
 	dashboardService    dashboards.DashboardService
 
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	accesscontrol        accesscontrol.AccessControl
 
-	bus bus.Bus
+	bus         bus.Bus
+	pluginStore plugins.Store
 }
 
 func (ng *AlertNG) init() error {
@@ -104,7 +111,7 @@ func (ng *AlertNG) init() error {
 		BaseInterval:     ng.Cfg.UnifiedAlerting.BaseInterval,
 		DefaultInterval:  ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval,
 		SQLStore:         ng.SQLStore,
-		Logger:           ng.Log,
+		Logger:           log.New("ngalert.store"),
 		FolderService:    ng.folderService,
 		AccessControl:    ng.accesscontrol,
 		DashboardService: ng.dashboardService,
@@ -131,9 +138,9 @@ func (ng *AlertNG) init() error {
 	schedCfg := schedule.SchedulerCfg{
 		C:                       clock.New(),
 		BaseInterval:            ng.Cfg.UnifiedAlerting.BaseInterval,
-		Logger:                  ng.Log,
+		Logger:                  log.New("ngalert.scheduler"),
 		MaxAttempts:             ng.Cfg.UnifiedAlerting.MaxAttempts,
-		Evaluator:               eval.NewEvaluator(ng.Cfg, ng.Log, ng.DataSourceCache, ng.SecretsService, ng.ExpressionService),
+		Evaluator:               eval.NewEvaluator(ng.Cfg, log.New("ngalert.eval"), ng.DataSourceCache, ng.SecretsService, ng.ExpressionService),
 		InstanceStore:           store,
 		RuleStore:               store,
 		AdminConfigStore:        store,
@@ -151,20 +158,34 @@ func (ng *AlertNG) init() error {
 		appUrl = nil
 	}
 
-	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, ng.dashboardService, ng.imageService, clock.New())
+	stateManager := state.NewManager(log.New("ngalert.state.manager"), ng.Metrics.GetStateMetrics(), appUrl, store, store, ng.dashboardService, ng.imageService, clock.New())
+	stateManager.MaxInstancesPerRule = ng.Cfg.UnifiedAlerting.MaxAlertInstancesPerRule
+	stateManager.WarmupPeriod = ng.Cfg.UnifiedAlerting.WarmupPeriod
+	defaultLabelsService := defaultlabels.NewService(ng.KVStore, clock.New(), log.New("ngalert.default_labels"))
+	stateManager.DefaultLabels = defaultLabelsService
 	scheduler := schedule.NewScheduler(schedCfg, appUrl, stateManager, ng.bus)
 
 	ng.stateManager = stateManager
 	ng.schedule = scheduler
 
 	// Provisioning
-	policyService := provisioning.NewNotificationPolicyService(store, store, store, ng.Log)
-	contactPointService := provisioning.NewContactPointService(store, ng.SecretsService, store, store, ng.Log)
-	templateService := provisioning.NewTemplateService(store, store, store, ng.Log)
-	muteTimingService := provisioning.NewMuteTimingService(store, store, store, ng.Log)
-	alertRuleService := provisioning.NewAlertRuleService(store, store, store,
-		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
-		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log)
+	// Configurations saved by the provisioning services are subject to the same size limits as the ones saved through
+	// the Alertmanager API, and are pushed to the external Alertmanagers of the organizations that use them.
+	externalAlertmanagerSyncService := provisioning.NewExternalAlertmanagerSyncService(store, ng.SecretsService, log.New("provisioning.externalalertmanagers"))
+	amConfigStore := provisioning.NewExternalAlertmanagerSyncStore(provisioning.NewSizeLimitedAMConfigStore(store, ng.Cfg.UnifiedAlerting), externalAlertmanagerSyncService)
+	policyService := provisioning.NewNotificationPolicyService(amConfigStore, store, store, store, store, ng.Cfg.UnifiedAlerting, log.New("provisioning.notificationpolicies"))
+	contactPointService := provisioning.NewContactPointService(amConfigStore, ng.SecretsService, store, store, log.New("provisioning.contactpoints"))
+	templateService := provisioning.NewTemplateService(amConfigStore, store, store, ng.MultiOrgAlertmanager.GlobalTemplates(), log.New("provisioning.templates"))
+	muteTimingService := provisioning.NewMuteTimingService(amConfigStore, store, store, log.New("provisioning.mutetimings"))
+	alertmanagerImportService := provisioning.NewAlertmanagerImportService(amConfigStore, contactPointService, muteTimingService, policyService, store, log.New("provisioning.alertmanagerimport"))
+	batchService := provisioning.NewBatchService(amConfigStore, contactPointService, templateService, muteTimingService, policyService, store, log.New("provisioning.batch"))
+	ruleLintService := lint.NewService(ng.KVStore, log.New("ngalert.lint"))
+	alertRuleService := provisioning.NewAlertRuleService(store, store, store, ng.QuotaService, ruleLintService, store, ng.MultiOrgAlertmanager,
+		ng.SQLStore, ng.pluginStore, ng.dashboardService, ng.Cfg.UnifiedAlerting, ng.Metrics.GetProvisioningMetrics(), log.New("provisioning.alertrules"))
+	ruleTemplateService := provisioning.NewRuleTemplateService(ng.KVStore, log.New("provisioning.ruletemplates"))
+	provisioningWebhookService := provisioning.NewProvisioningWebhookService(ng.KVStore, log.New("provisioning.webhooks"))
+	ruleFolderService := provisioning.NewRuleFolderService(ng.folderService, ng.dashboardService, ng.folderPermissions, ng.accesscontrol,
+		log.New("provisioning.rulefolders"))
 
 	api := api.API{
 		Cfg:                  ng.Cfg,
@@ -188,7 +209,15 @@ func (ng *AlertNG) init() error {
 		ContactPointService:  contactPointService,
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
+		AlertmanagerImport:   alertmanagerImportService,
+		ProvisioningBatch:    batchService,
+		ExternalAMSync:       externalAlertmanagerSyncService,
 		AlertRules:           alertRuleService,
+		RuleLint:             ruleLintService,
+		RuleTemplates:        ruleTemplateService,
+		ProvisioningWebhooks: provisioningWebhookService,
+		RuleFolders:          ruleFolderService,
+		DefaultLabels:        defaultLabelsService,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -17,7 +17,6 @@ import (
 
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/cluster"
-	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/dispatch"
 	"github.com/prometheus/alertmanager/inhibit"
 	"github.com/prometheus/alertmanager/nflog"
@@ -26,7 +25,6 @@ import (
 	"github.com/prometheus/alertmanager/provider/mem"
 	"github.com/prometheus/alertmanager/silence"
 	"github.com/prometheus/alertmanager/template"
-	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
@@ -39,6 +37,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
@@ -89,6 +88,7 @@ type ClusterPeer interface {
 type AlertingStore interface {
 	store.AlertingStore
 	store.ImageStore
+	store.DeferredNotificationStore
 }
 
 type Alertmanager struct {
@@ -120,7 +120,12 @@ type Alertmanager struct {
 
 	// muteTimes is a map where the key is the name of the mute_time_interval
 	// and the value represents all configured time_interval(s)
-	muteTimes map[string][]timeinterval.TimeInterval
+	muteTimes map[string][]apimodels.TimeInterval
+
+	// integrations are the integrations of each receiver, by receiver name.
+	integrations map[string][]notify.Integration
+	// quietHours are the quiet hours of the receivers that have them, by receiver name.
+	quietHours map[string]*apimodels.QuietHours
 
 	stageMetrics      *notify.Metrics
 	dispatcherMetrics *dispatch.DispatcherMetrics
@@ -131,14 +136,18 @@ type Alertmanager struct {
 	orgID           int64
 
 	decryptFn channels.GetDecryptedValueFn
+
+	// globalTemplates are the message templates shared by all organizations. They are added to the templates of
+	// the configuration unless the organization has a template with the same name.
+	globalTemplates map[string]string
 }
 
 func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store AlertingStore, kvStore kvstore.KVStore,
-	peer ClusterPeer, decryptFn channels.GetDecryptedValueFn, ns notifications.Service, m *metrics.Alertmanager) (*Alertmanager, error) {
+	peer ClusterPeer, decryptFn channels.GetDecryptedValueFn, ns notifications.Service, m *metrics.Alertmanager, globalTemplates map[string]string) (*Alertmanager, error) {
 	am := &Alertmanager{
 		Settings:            cfg,
 		stopc:               make(chan struct{}),
-		logger:              log.New("alertmanager", "org", orgID),
+		logger:              log.New("ngalert.notifier.alertmanager").New("orgID", orgID),
 		marker:              types.NewMarker(m.Registerer),
 		stageMetrics:        notify.NewMetrics(m.Registerer),
 		dispatcherMetrics:   dispatch.NewDispatcherMetrics(false, m.Registerer),
@@ -149,6 +158,7 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		NotificationService: ns,
 		orgID:               orgID,
 		decryptFn:           decryptFn,
+		globalTemplates:     globalTemplates,
 	}
 
 	am.fileStore = NewFileStore(am.orgID, kvStore, am.WorkingDirPath())
@@ -205,6 +215,12 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		am.wg.Done()
 	}()
 
+	am.wg.Add(1)
+	go func() {
+		defer am.wg.Done()
+		am.runQuietHoursFlush(ctx)
+	}()
+
 	// Initialize in-memory alerts
 	am.alerts, err = mem.NewAlerts(context.Background(), am.marker, memoryAlertsGCInterval, nil, am.logger)
 	if err != nil {
@@ -255,6 +271,7 @@ func (am *Alertmanager) SaveAndApplyDefaultConfig(ctx context.Context) error {
 		Default:                   true,
 		ConfigurationVersion:      fmt.Sprintf("v%d", ngmodels.AlertConfigurationVersion),
 		OrgID:                     am.orgID,
+		Origin:                    ngmodels.AlertConfigurationOriginDefault,
 	}
 
 	cfg, err := Load([]byte(am.Settings.UnifiedAlerting.DefaultConfiguration))
@@ -276,12 +293,19 @@ func (am *Alertmanager) SaveAndApplyDefaultConfig(ctx context.Context) error {
 }
 
 // SaveAndApplyConfig saves the configuration the database and applies the configuration to the Alertmanager.
-// It rollbacks the save if we fail to apply the configuration.
-func (am *Alertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.PostableUserConfig) error {
+// It rollbacks the save if we fail to apply the configuration. createdBy is the login of the user saving it.
+func (am *Alertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.PostableUserConfig, createdBy string) error {
+	return am.saveAndApplyConfig(ctx, cfg, ngmodels.AlertConfigurationOriginAPI, createdBy)
+}
+
+func (am *Alertmanager) saveAndApplyConfig(ctx context.Context, cfg *apimodels.PostableUserConfig, origin ngmodels.AlertConfigurationOrigin, createdBy string) error {
 	rawConfig, err := json.Marshal(&cfg)
 	if err != nil {
 		return fmt.Errorf("failed to serialize to the Alertmanager configuration: %w", err)
 	}
+	if err := provisioning.CheckConfigurationSize(len(rawConfig), am.Settings.UnifiedAlerting.ConfigLimitsForOrg(am.orgID)); err != nil {
+		return err
+	}
 
 	am.reloadConfigMtx.Lock()
 	defer am.reloadConfigMtx.Unlock()
@@ -290,6 +314,8 @@ func (am *Alertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.P
 		AlertmanagerConfiguration: string(rawConfig),
 		ConfigurationVersion:      fmt.Sprintf("v%d", ngmodels.AlertConfigurationVersion),
 		OrgID:                     am.orgID,
+		Origin:                    origin,
+		CreatedBy:                 createdBy,
 	}
 
 	err = am.Store.SaveAlertmanagerConfigurationWithCallback(ctx, cmd, func() error {
@@ -348,8 +374,8 @@ func (am *Alertmanager) templateFromPaths(paths ...string) (*template.Template,
 	return tmpl, nil
 }
 
-func (am *Alertmanager) buildMuteTimesMap(muteTimeIntervals []config.MuteTimeInterval) map[string][]timeinterval.TimeInterval {
-	muteTimes := make(map[string][]timeinterval.TimeInterval, len(muteTimeIntervals))
+func (am *Alertmanager) buildMuteTimesMap(muteTimeIntervals []apimodels.MuteTimeIntervalConfig) map[string][]apimodels.TimeInterval {
+	muteTimes := make(map[string][]apimodels.TimeInterval, len(muteTimeIntervals))
 	for _, ti := range muteTimeIntervals {
 		muteTimes[ti.Name] = ti.TimeIntervals
 	}
@@ -377,6 +403,7 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 	if cfg.TemplateFiles == nil {
 		cfg.TemplateFiles = map[string]string{}
 	}
+	withGlobalTemplates(cfg, am.globalTemplates)
 	cfg.TemplateFiles["__default__.tmpl"] = channels.DefaultTemplateString
 
 	// next, we need to make sure we persist the templates to disk.
@@ -416,17 +443,24 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 	am.inhibitor = inhibit.NewInhibitor(am.alerts, cfg.AlertmanagerConfig.InhibitRules, am.marker, am.logger)
 	am.muteTimes = am.buildMuteTimesMap(cfg.AlertmanagerConfig.MuteTimeIntervals)
 	am.silencer = silence.NewSilencer(am.silences, am.marker, am.logger)
+	am.integrations = integrationsMap
+	am.quietHours = buildQuietHoursMap(cfg.AlertmanagerConfig.Receivers)
 
 	meshStage := notify.NewGossipSettleStage(am.peer)
 	inhibitionStage := notify.NewMuteStage(am.inhibitor)
-	timeMuteStage := notify.NewTimeMuteStage(am.muteTimes)
+	amRoute, activeTimes := buildAMRoute(cfg.AlertmanagerConfig.Route)
+	timeMuteStage := newTimeIntervalsStage(am.muteTimes, activeTimes)
 	silencingStage := notify.NewMuteStage(am.silencer)
 	for name := range integrationsMap {
-		stage := am.createReceiverStage(name, integrationsMap[name], am.waitFunc, am.notificationLog)
-		routingStage[name] = notify.MultiStage{meshStage, silencingStage, timeMuteStage, inhibitionStage, stage}
+		stages := notify.MultiStage{meshStage, silencingStage, timeMuteStage, inhibitionStage}
+		if qh, ok := am.quietHours[name]; ok {
+			stages = append(stages, newQuietHoursStage(am.orgID, name, qh, am.muteTimes, am.Store, am.logger))
+		}
+		stages = append(stages, am.createReceiverStage(name, integrationsMap[name], am.waitFunc, am.notificationLog))
+		routingStage[name] = stages
 	}
 
-	am.route = dispatch.NewRoute(cfg.AlertmanagerConfig.Route.AsAMRoute(), nil)
+	am.route = dispatch.NewRoute(amRoute, nil)
 	am.dispatcher = dispatch.NewDispatcher(am.alerts, am.route, routingStage, am.marker, am.timeoutFunc, &nilLimits{}, am.logger, am.dispatcherMetrics)
 
 	am.wg.Add(1)
@@ -465,6 +499,21 @@ func (am *Alertmanager) buildIntegrationsMap(receivers []*apimodels.PostableApiR
 	return integrationsMap, nil
 }
 
+// receiversErrors builds the integrations of the receivers as applying the configuration does, and returns the
+// errors of all the integrations that cannot be built.
+func (am *Alertmanager) receiversErrors(receivers []*apimodels.PostableApiReceiver) []error {
+	var errs []error
+	for _, receiver := range receivers {
+		for _, r := range receiver.GrafanaManagedReceivers {
+			// The integrations are not used to send notifications, they do not need the templates.
+			if _, err := am.buildReceiverIntegration(r, nil); err != nil {
+				errs = append(errs, fmt.Errorf("receiver %q: %w", receiver.Name, err))
+			}
+		}
+	}
+	return errs
+}
+
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
 func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableApiReceiver, tmpl *template.Template) ([]notify.Integration, error) {
 	var integrations []notify.Integration
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 )
 
@@ -75,6 +77,7 @@ func (moa *MultiOrgAlertmanager) GetAlertmanagerConfiguration(ctx context.Contex
 		gettableApiReceiver := definitions.GettableApiReceiver{
 			GettableGrafanaReceivers: definitions.GettableGrafanaReceivers{
 				GrafanaManagedReceivers: receivers,
+				QuietHours:              recv.QuietHours,
 			},
 		}
 		gettableApiReceiver.Name = recv.Name
@@ -89,7 +92,9 @@ func (moa *MultiOrgAlertmanager) GetAlertmanagerConfiguration(ctx context.Contex
 	return result, nil
 }
 
-func (moa *MultiOrgAlertmanager) ApplyAlertmanagerConfiguration(ctx context.Context, org int64, config definitions.PostableUserConfig) error {
+// ApplyAlertmanagerConfiguration validates, saves and applies the configuration of the organization. createdBy is the
+// login of the user saving it, it is recorded in the history of the configuration.
+func (moa *MultiOrgAlertmanager) ApplyAlertmanagerConfiguration(ctx context.Context, org int64, config definitions.PostableUserConfig, createdBy string) error {
 	// Get the last known working configuration
 	query := models.GetLatestAlertmanagerConfigurationQuery{OrgID: org}
 	if err := moa.configStore.GetLatestAlertmanagerConfiguration(ctx, &query); err != nil {
@@ -99,6 +104,10 @@ func (moa *MultiOrgAlertmanager) ApplyAlertmanagerConfiguration(ctx context.Cont
 		}
 	}
 
+	if err := moa.validateAlertmanagerConfiguration(org, config); err != nil {
+		return err
+	}
+
 	if err := moa.Crypto.LoadSecureSettings(ctx, org, config.AlertmanagerConfig.Receivers); err != nil {
 		return err
 	}
@@ -115,7 +124,7 @@ func (moa *MultiOrgAlertmanager) ApplyAlertmanagerConfiguration(ctx context.Cont
 		}
 	}
 
-	if err := am.SaveAndApplyConfig(ctx, &config); err != nil {
+	if err := am.SaveAndApplyConfig(ctx, &config, createdBy); err != nil {
 		moa.logger.Error("unable to save and apply alertmanager configuration", "err", err)
 		return AlertmanagerConfigRejectedError{err}
 	}
@@ -123,13 +132,77 @@ func (moa *MultiOrgAlertmanager) ApplyAlertmanagerConfiguration(ctx context.Cont
 	return nil
 }
 
+// ValidateAlertmanagerConfiguration runs the checks of ApplyAlertmanagerConfiguration on a configuration without saving
+// it. Unlike ApplyAlertmanagerConfiguration, it does not stop at the first invalid part of the configuration and returns
+// all the problems that are found. The returned error is only set if the checks could not be run.
+func (moa *MultiOrgAlertmanager) ValidateAlertmanagerConfiguration(ctx context.Context, org int64, config definitions.PostableUserConfig) ([]error, error) {
+	am, err := moa.AlertmanagerFor(org)
+	if err != nil {
+		// The configuration of an Alertmanager that isn't ready yet can still be saved, so it can be validated.
+		if !errors.Is(err, ErrAlertmanagerNotReady) {
+			return nil, err
+		}
+	}
+
+	errs := moa.alertmanagerConfigurationErrors(org, config)
+
+	names := make([]string, 0, len(config.TemplateFiles))
+	for name := range config.TemplateFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := parseTemplate(name, config.TemplateFiles[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := moa.Crypto.LoadSecureSettings(ctx, org, config.AlertmanagerConfig.Receivers); err != nil {
+		var unknownReceiverError UnknownReceiverError
+		if !errors.As(err, &unknownReceiverError) {
+			return nil, err
+		}
+		// Without the secure settings of the existing receivers the integrations cannot be checked.
+		return append(errs, err), nil
+	}
+
+	if err := config.ProcessConfig(moa.Crypto.Encrypt); err != nil {
+		return nil, fmt.Errorf("failed to post process Alertmanager configuration: %w", err)
+	}
+
+	return append(errs, am.receiversErrors(config.AlertmanagerConfig.Receivers)...), nil
+}
+
+// validateAlertmanagerConfiguration checks the limits of the policy tree and the mute timings of a configuration before
+// it is saved.
+func (moa *MultiOrgAlertmanager) validateAlertmanagerConfiguration(org int64, config definitions.PostableUserConfig) error {
+	if errs := moa.alertmanagerConfigurationErrors(org, config); len(errs) > 0 {
+		return AlertmanagerConfigRejectedError{errs[0]}
+	}
+	return nil
+}
+
+func (moa *MultiOrgAlertmanager) alertmanagerConfigurationErrors(org int64, config definitions.PostableUserConfig) []error {
+	var errs []error
+	if err := provisioning.CheckPolicyTreeLimits(config.AlertmanagerConfig.Route, moa.settings.UnifiedAlerting.PolicyTreeLimitsForOrg(org)); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, mt := range config.AlertmanagerConfig.MuteTimeIntervals {
+		if err := definitions.ValidateTimeIntervals(mt.TimeIntervals); err != nil {
+			errs = append(errs, fmt.Errorf("invalid mute timing %q: %w", mt.Name, err))
+		}
+	}
+	return errs
+}
+
 func (moa *MultiOrgAlertmanager) mergeProvenance(ctx context.Context, config definitions.GettableUserConfig, org int64) (definitions.GettableUserConfig, error) {
 	if config.AlertmanagerConfig.Route != nil {
-		provenance, err := moa.ProvStore.GetProvenance(ctx, config.AlertmanagerConfig.Route, org)
+		routeProvs, err := moa.ProvStore.GetProvenances(ctx, org, config.AlertmanagerConfig.Route.ResourceType())
 		if err != nil {
 			return definitions.GettableUserConfig{}, err
 		}
-		config.AlertmanagerConfig.Route.Provenance = provenance
+		provisioning.ApplyRouteProvenances(config.AlertmanagerConfig.Route, routeProvs)
 	}
 
 	cp := definitions.EmbeddedContactPoint{}
@@ -0,0 +1,449 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// configHistoryCleanupInterval is the interval between the removals of the versions of the Alertmanager
+// configurations that are beyond the retention of the configuration history.
+const configHistoryCleanupInterval = 10 * time.Minute
+
+// GetAlertmanagerConfigurationVersions returns the stored versions of the Alertmanager configuration of the
+// organization, newest first. At most limit versions are returned, after skipping offset versions, or all versions
+// if limit is 0.
+func (moa *MultiOrgAlertmanager) GetAlertmanagerConfigurationVersions(ctx context.Context, org int64, limit, offset int) (definitions.AlertingConfigVersions, error) {
+	configs, err := moa.configStore.GetAlertmanagerConfigurationVersions(ctx, org, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configuration versions: %w", err)
+	}
+	result := make(definitions.AlertingConfigVersions, 0, len(configs))
+	for _, c := range configs {
+		result = append(result, newAlertingConfigVersion(c))
+	}
+	return result, nil
+}
+
+// DiffAlertmanagerConfigurations compares two stored versions of the Alertmanager configuration of the organization.
+// The values of secure settings are compared, but only the names of the changed secure settings are returned.
+func (moa *MultiOrgAlertmanager) DiffAlertmanagerConfigurations(ctx context.Context, org int64, fromID, toID int64) (definitions.AlertingConfigDiff, error) {
+	from, err := moa.configStore.GetAlertmanagerConfigurationVersion(ctx, org, fromID)
+	if err != nil {
+		return definitions.AlertingConfigDiff{}, fmt.Errorf("failed to get configuration version %d: %w", fromID, err)
+	}
+	to, err := moa.configStore.GetAlertmanagerConfigurationVersion(ctx, org, toID)
+	if err != nil {
+		return definitions.AlertingConfigDiff{}, fmt.Errorf("failed to get configuration version %d: %w", toID, err)
+	}
+	return moa.diffAlertmanagerConfigurationVersions(from, to)
+}
+
+// DiffAlertmanagerConfigurationWithCurrent compares a stored version of the Alertmanager configuration of the
+// organization with its current configuration, the latest stored version.
+func (moa *MultiOrgAlertmanager) DiffAlertmanagerConfigurationWithCurrent(ctx context.Context, org int64, fromID int64) (definitions.AlertingConfigDiff, error) {
+	from, err := moa.configStore.GetAlertmanagerConfigurationVersion(ctx, org, fromID)
+	if err != nil {
+		return definitions.AlertingConfigDiff{}, fmt.Errorf("failed to get configuration version %d: %w", fromID, err)
+	}
+	query := models.GetLatestAlertmanagerConfigurationQuery{OrgID: org}
+	if err := moa.configStore.GetLatestAlertmanagerConfiguration(ctx, &query); err != nil {
+		return definitions.AlertingConfigDiff{}, fmt.Errorf("failed to get the current configuration: %w", err)
+	}
+	return moa.diffAlertmanagerConfigurationVersions(from, query.Result)
+}
+
+func (moa *MultiOrgAlertmanager) diffAlertmanagerConfigurationVersions(from, to *models.AlertConfiguration) (definitions.AlertingConfigDiff, error) {
+	fromCfg, err := Load([]byte(from.AlertmanagerConfiguration))
+	if err != nil {
+		return definitions.AlertingConfigDiff{}, fmt.Errorf("failed to unmarshal alertmanager configuration: %w", err)
+	}
+	toCfg, err := Load([]byte(to.AlertmanagerConfiguration))
+	if err != nil {
+		return definitions.AlertingConfigDiff{}, fmt.Errorf("failed to unmarshal alertmanager configuration: %w", err)
+	}
+
+	diff, err := diffAlertmanagerConfigurations(fromCfg, toCfg, moa.Crypto.getDecryptedSecret)
+	if err != nil {
+		return definitions.AlertingConfigDiff{}, err
+	}
+	diff.From = newAlertingConfigVersion(from)
+	diff.To = newAlertingConfigVersion(to)
+	return diff, nil
+}
+
+// RollbackAlertmanagerConfiguration saves a stored version of the Alertmanager configuration of the organization again
+// as its latest version, and applies it. The version is validated like a new configuration, and the rollback is
+// recorded in the history. The provenance of the policies, contact points, templates and mute timings of the version is
+// kept, while the provenance of the ones that are not in the version is removed.
+func (moa *MultiOrgAlertmanager) RollbackAlertmanagerConfiguration(ctx context.Context, org int64, id int64, createdBy string) error {
+	version, err := moa.configStore.GetAlertmanagerConfigurationVersion(ctx, org, id)
+	if err != nil {
+		return fmt.Errorf("failed to get configuration version %d: %w", id, err)
+	}
+	cfg, err := Load([]byte(version.AlertmanagerConfiguration))
+	if err != nil {
+		return AlertmanagerConfigRejectedError{fmt.Errorf("failed to unmarshal alertmanager configuration: %w", err)}
+	}
+	if err := moa.validateAlertmanagerConfiguration(org, *cfg); err != nil {
+		return err
+	}
+
+	am, err := moa.AlertmanagerFor(org)
+	if err != nil {
+		// It's okay if the alertmanager isn't ready yet, we're changing its config anyway.
+		if !errors.Is(err, ErrAlertmanagerNotReady) {
+			return err
+		}
+	}
+	// The secure settings of the version are already encrypted.
+	if err := am.saveAndApplyConfig(ctx, cfg, models.AlertConfigurationOriginRollback, createdBy); err != nil {
+		moa.logger.Error("unable to save and apply alertmanager configuration", "err", err)
+		return AlertmanagerConfigRejectedError{err}
+	}
+
+	if err := moa.removeStaleProvenances(ctx, org, cfg); err != nil {
+		return fmt.Errorf("failed to remove the provenance of the resources that were rolled back: %w", err)
+	}
+	return nil
+}
+
+// removeStaleProvenances removes the provenance of the resources that are not in the configuration.
+func (moa *MultiOrgAlertmanager) removeStaleProvenances(ctx context.Context, org int64, cfg *definitions.PostableUserConfig) error {
+	routes := map[string]bool{}
+	var collectRoutes func(r *definitions.Route)
+	collectRoutes = func(r *definitions.Route) {
+		routes[r.ID] = true
+		for _, child := range r.Routes {
+			collectRoutes(child)
+		}
+	}
+	if cfg.AlertmanagerConfig.Route != nil {
+		collectRoutes(cfg.AlertmanagerConfig.Route)
+	}
+	contactPoints := map[string]bool{}
+	for _, receiver := range cfg.AlertmanagerConfig.Receivers {
+		for _, integration := range receiver.GrafanaManagedReceivers {
+			contactPoints[integration.UID] = true
+		}
+	}
+	templates := map[string]bool{}
+	for name := range cfg.TemplateFiles {
+		templates[name] = true
+	}
+	muteTimings := map[string]bool{}
+	for _, mt := range cfg.AlertmanagerConfig.MuteTimeIntervals {
+		muteTimings[mt.Name] = true
+	}
+
+	for _, resource := range []struct {
+		existing map[string]bool
+		object   func(id string) models.Provisionable
+	}{
+		{routes, func(id string) models.Provisionable { return &definitions.Route{ID: id} }},
+		{contactPoints, func(id string) models.Provisionable { return &definitions.EmbeddedContactPoint{UID: id} }},
+		{templates, func(id string) models.Provisionable { return &definitions.MessageTemplate{Name: id} }},
+		{muteTimings, func(id string) models.Provisionable {
+			return &definitions.MuteTimeInterval{MuteTimeIntervalConfig: definitions.MuteTimeIntervalConfig{Name: id}}
+		}},
+	} {
+		provenances, err := moa.ProvStore.GetProvenances(ctx, org, resource.object("").ResourceType())
+		if err != nil {
+			return err
+		}
+		for id := range provenances {
+			if resource.existing[id] {
+				continue
+			}
+			if err := moa.ProvStore.DeleteProvenance(ctx, resource.object(id), org); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cleanUpConfigurationHistory removes the stored versions of the Alertmanager configuration of every organization that
+// are beyond the maximum number of versions or the maximum age of the configuration history. The latest version of
+// an organization is always kept.
+func (moa *MultiOrgAlertmanager) cleanUpConfigurationHistory(ctx context.Context) {
+	maxVersions, maxAge := moa.settings.UnifiedAlerting.ConfigHistoryMaxVersions, moa.settings.UnifiedAlerting.ConfigHistoryMaxAge
+	if maxVersions <= 0 && maxAge <= 0 {
+		return
+	}
+	var olderThan time.Time
+	if maxAge > 0 {
+		olderThan = time.Now().Add(-maxAge)
+	}
+
+	orgIDs, err := moa.orgStore.GetOrgs(ctx)
+	if err != nil {
+		moa.logger.Error("failed to get the organizations to clean up the configuration history of", "err", err)
+		return
+	}
+	for _, orgID := range orgIDs {
+		deleted, err := moa.configStore.DeleteOldAlertmanagerConfigurations(ctx, orgID, int(maxVersions), olderThan)
+		if err != nil {
+			moa.logger.Error("failed to remove old versions of the Alertmanager configuration", "org", orgID, "err", err)
+			continue
+		}
+		if deleted > 0 {
+			moa.metrics.ConfigHistoryPurged.Add(float64(deleted))
+			moa.logger.Debug("removed old versions of the Alertmanager configuration", "org", orgID, "count", deleted)
+		}
+	}
+}
+
+func newAlertingConfigVersion(c *models.AlertConfiguration) definitions.AlertingConfigVersion {
+	return definitions.AlertingConfigVersion{
+		ID:        c.ID,
+		Hash:      c.ConfigurationHash,
+		Created:   time.Unix(c.CreatedAt, 0).UTC(),
+		Default:   c.Default,
+		Origin:    string(c.Origin),
+		CreatedBy: c.CreatedBy,
+	}
+}
+
+type secretDecrypter func(r *definitions.PostableGrafanaReceiver, key string) (string, error)
+
+func diffAlertmanagerConfigurations(from, to *definitions.PostableUserConfig, decrypt secretDecrypter) (definitions.AlertingConfigDiff, error) {
+	diff := definitions.AlertingConfigDiff{
+		Templates:         diffNamed(from.TemplateFiles, to.TemplateFiles),
+		MuteTimeIntervals: diffNamed(muteTimeIntervalsByName(from), muteTimeIntervalsByName(to)),
+	}
+
+	var err error
+	if diff.Route, err = diffJSON(from.AlertmanagerConfig.Route, to.AlertmanagerConfig.Route); err != nil {
+		return definitions.AlertingConfigDiff{}, err
+	}
+	if diff.Receivers, err = diffReceivers(from.AlertmanagerConfig.Receivers, to.AlertmanagerConfig.Receivers, decrypt); err != nil {
+		return definitions.AlertingConfigDiff{}, err
+	}
+	return diff, nil
+}
+
+func muteTimeIntervalsByName(c *definitions.PostableUserConfig) map[string]definitions.MuteTimeIntervalConfig {
+	result := make(map[string]definitions.MuteTimeIntervalConfig, len(c.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range c.AlertmanagerConfig.MuteTimeIntervals {
+		result[mt.Name] = mt
+	}
+	return result
+}
+
+// diffNamed compares the values with the same name of two maps with string keys.
+func diffNamed(from, to interface{}) []definitions.NamedChange {
+	f, t := reflect.ValueOf(from), reflect.ValueOf(to)
+	result := make([]definitions.NamedChange, 0)
+	for _, name := range unionKeys(from, to) {
+		fv, tv := f.MapIndex(reflect.ValueOf(name)), t.MapIndex(reflect.ValueOf(name))
+		switch {
+		case !tv.IsValid():
+			result = append(result, definitions.NamedChange{Name: name, Change: definitions.ConfigChangeRemoved})
+		case !fv.IsValid():
+			result = append(result, definitions.NamedChange{Name: name, Change: definitions.ConfigChangeAdded})
+		case !reflect.DeepEqual(fv.Interface(), tv.Interface()):
+			result = append(result, definitions.NamedChange{Name: name, Change: definitions.ConfigChangeChanged})
+		}
+	}
+	return result
+}
+
+func diffReceivers(from, to []*definitions.PostableApiReceiver, decrypt secretDecrypter) ([]definitions.ReceiverChange, error) {
+	fromByName := make(map[string]*definitions.PostableApiReceiver, len(from))
+	for _, r := range from {
+		fromByName[r.Name] = r
+	}
+	toByName := make(map[string]*definitions.PostableApiReceiver, len(to))
+	for _, r := range to {
+		toByName[r.Name] = r
+	}
+
+	result := make([]definitions.ReceiverChange, 0)
+	for _, name := range unionKeys(fromByName, toByName) {
+		f, inFrom := fromByName[name]
+		t, inTo := toByName[name]
+		switch {
+		case !inTo:
+			result = append(result, definitions.ReceiverChange{Name: name, Change: definitions.ConfigChangeRemoved})
+		case !inFrom:
+			result = append(result, definitions.ReceiverChange{Name: name, Change: definitions.ConfigChangeAdded})
+		default:
+			integrations, err := diffIntegrations(f.GrafanaManagedReceivers, t.GrafanaManagedReceivers, decrypt)
+			if err != nil {
+				return nil, err
+			}
+			if len(integrations) > 0 {
+				result = append(result, definitions.ReceiverChange{Name: name, Change: definitions.ConfigChangeChanged, Integrations: integrations})
+			}
+		}
+	}
+	return result, nil
+}
+
+func diffIntegrations(from, to []*definitions.PostableGrafanaReceiver, decrypt secretDecrypter) ([]definitions.IntegrationChange, error) {
+	fromByUID := make(map[string]*definitions.PostableGrafanaReceiver, len(from))
+	for _, r := range from {
+		fromByUID[r.UID] = r
+	}
+	toByUID := make(map[string]*definitions.PostableGrafanaReceiver, len(to))
+	for _, r := range to {
+		toByUID[r.UID] = r
+	}
+
+	result := make([]definitions.IntegrationChange, 0)
+	for _, uid := range unionKeys(fromByUID, toByUID) {
+		f, inFrom := fromByUID[uid]
+		t, inTo := toByUID[uid]
+		switch {
+		case !inTo:
+			result = append(result, definitions.IntegrationChange{UID: uid, Type: f.Type, Change: definitions.ConfigChangeRemoved})
+		case !inFrom:
+			result = append(result, definitions.IntegrationChange{UID: uid, Type: t.Type, Change: definitions.ConfigChangeAdded})
+		default:
+			change, err := diffIntegration(f, t, decrypt)
+			if err != nil {
+				return nil, err
+			}
+			if len(change.Changes) > 0 || len(change.SecureSettings) > 0 {
+				result = append(result, change)
+			}
+		}
+	}
+	return result, nil
+}
+
+func diffIntegration(from, to *definitions.PostableGrafanaReceiver, decrypt secretDecrypter) (definitions.IntegrationChange, error) {
+	change := definitions.IntegrationChange{UID: to.UID, Type: to.Type, Change: definitions.ConfigChangeChanged}
+
+	type fields struct {
+		Name                  string      `json:"name"`
+		Type                  string      `json:"type"`
+		DisableResolveMessage bool        `json:"disableResolveMessage"`
+		Settings              interface{} `json:"settings"`
+	}
+	var err error
+	change.Changes, err = diffJSON(
+		fields{Name: from.Name, Type: from.Type, DisableResolveMessage: from.DisableResolveMessage, Settings: from.Settings},
+		fields{Name: to.Name, Type: to.Type, DisableResolveMessage: to.DisableResolveMessage, Settings: to.Settings},
+	)
+	if err != nil {
+		return definitions.IntegrationChange{}, err
+	}
+
+	for _, key := range unionKeys(from.SecureSettings, to.SecureSettings) {
+		f, err := decrypt(from, key)
+		if err != nil {
+			return definitions.IntegrationChange{}, fmt.Errorf("failed to decrypt stored secure setting: %w", err)
+		}
+		t, err := decrypt(to, key)
+		if err != nil {
+			return definitions.IntegrationChange{}, fmt.Errorf("failed to decrypt stored secure setting: %w", err)
+		}
+		if f != t {
+			change.SecureSettings = append(change.SecureSettings, key)
+		}
+	}
+	return change, nil
+}
+
+// diffJSON compares the JSON representations of two values.
+func diffJSON(from, to interface{}) ([]definitions.ValueChange, error) {
+	f, err := toJSONValue(from)
+	if err != nil {
+		return nil, err
+	}
+	t, err := toJSONValue(to)
+	if err != nil {
+		return nil, err
+	}
+	return diffValues("", f, t, make([]definitions.ValueChange, 0)), nil
+}
+
+func toJSONValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// diffValues appends the changes between two decoded JSON values to changes. Objects are compared by key and arrays
+// by index, other values are compared as a whole.
+func diffValues(path string, from, to interface{}, changes []definitions.ValueChange) []definitions.ValueChange {
+	switch f := from.(type) {
+	case map[string]interface{}:
+		if t, ok := to.(map[string]interface{}); ok {
+			for _, key := range unionKeys(f, t) {
+				fv, inFrom := f[key]
+				tv, inTo := t[key]
+				changes = diffPresence(joinPath(path, key), fv, inFrom, tv, inTo, changes)
+			}
+			return changes
+		}
+	case []interface{}:
+		if t, ok := to.([]interface{}); ok {
+			for i := 0; i < len(f) || i < len(t); i++ {
+				var fv, tv interface{}
+				if i < len(f) {
+					fv = f[i]
+				}
+				if i < len(t) {
+					tv = t[i]
+				}
+				changes = diffPresence(joinPath(path, strconv.Itoa(i)), fv, i < len(f), tv, i < len(t), changes)
+			}
+			return changes
+		}
+	}
+	if !reflect.DeepEqual(from, to) {
+		changes = append(changes, definitions.ValueChange{Path: path, Change: definitions.ConfigChangeChanged, From: from, To: to})
+	}
+	return changes
+}
+
+func diffPresence(path string, from interface{}, inFrom bool, to interface{}, inTo bool, changes []definitions.ValueChange) []definitions.ValueChange {
+	switch {
+	case !inTo:
+		return append(changes, definitions.ValueChange{Path: path, Change: definitions.ConfigChangeRemoved, From: from})
+	case !inFrom:
+		return append(changes, definitions.ValueChange{Path: path, Change: definitions.ConfigChangeAdded, To: to})
+	default:
+		return diffValues(path, from, to, changes)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// unionKeys returns the sorted keys of two maps with string keys.
+func unionKeys(a, b interface{}) []string {
+	set := make(map[string]struct{})
+	for _, m := range []interface{}{a, b} {
+		for _, k := range reflect.ValueOf(m).MapKeys() {
+			set[k.String()] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
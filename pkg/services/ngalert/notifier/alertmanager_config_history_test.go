@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestDiffAlertmanagerConfigurations(t *testing.T) {
+	from, err := Load([]byte(`{
+		"template_files": {"a": "{{ define \"a\" }}a{{ end }}", "b": "{{ define \"b\" }}b{{ end }}"},
+		"alertmanager_config": {
+			"route": {"receiver": "email", "routes": [{"receiver": "slack", "object_matchers": [["team", "=", "a"]]}]},
+			"mute_time_intervals": [{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]}],
+			"receivers": [
+				{"name": "email", "grafana_managed_receiver_configs": [{"uid": "email-1", "name": "email", "type": "email", "settings": {"addresses": "a@example.com"}}]},
+				{"name": "slack", "grafana_managed_receiver_configs": [{"uid": "slack-1", "name": "slack", "type": "slack", "settings": {"recipient": "#alerts"}, "secureSettings": {"url": "secret-1", "token": "token-1"}}]},
+				{"name": "webhook", "grafana_managed_receiver_configs": [{"uid": "webhook-1", "name": "webhook", "type": "webhook", "settings": {"url": "http://localhost"}}]}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+	to, err := Load([]byte(`{
+		"template_files": {"a": "{{ define \"a\" }}a{{ end }}", "b": "{{ define \"b\" }}changed{{ end }}", "c": "{{ define \"c\" }}c{{ end }}"},
+		"alertmanager_config": {
+			"route": {"receiver": "email", "group_wait": "1m", "routes": [{"receiver": "pager", "object_matchers": [["team", "=", "a"]]}]},
+			"receivers": [
+				{"name": "email", "grafana_managed_receiver_configs": [{"uid": "email-1", "name": "email", "type": "email", "settings": {"addresses": "a@example.com"}}]},
+				{"name": "slack", "grafana_managed_receiver_configs": [{"uid": "slack-1", "name": "slack", "type": "slack", "disableResolveMessage": true, "settings": {"recipient": "#incidents"}, "secureSettings": {"url": "secret-2", "token": "token-1"}}]},
+				{"name": "pager", "grafana_managed_receiver_configs": [{"uid": "pager-1", "name": "pager", "type": "pagerduty", "settings": {}}]}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+	decrypt := func(r *definitions.PostableGrafanaReceiver, key string) (string, error) {
+		return r.SecureSettings[key], nil
+	}
+
+	diff, err := diffAlertmanagerConfigurations(from, to, decrypt)
+	require.NoError(t, err)
+
+	require.Equal(t, []definitions.NamedChange{
+		{Name: "b", Change: definitions.ConfigChangeChanged},
+		{Name: "c", Change: definitions.ConfigChangeAdded},
+	}, diff.Templates)
+	require.Equal(t, []definitions.NamedChange{
+		{Name: "weekends", Change: definitions.ConfigChangeRemoved},
+	}, diff.MuteTimeIntervals)
+	require.Equal(t, []definitions.ValueChange{
+		{Path: "group_wait", Change: definitions.ConfigChangeAdded, To: "1m"},
+		{Path: "routes.0.receiver", Change: definitions.ConfigChangeChanged, From: "slack", To: "pager"},
+	}, diff.Route)
+	require.Equal(t, []definitions.ReceiverChange{
+		{Name: "pager", Change: definitions.ConfigChangeAdded},
+		{
+			Name:   "slack",
+			Change: definitions.ConfigChangeChanged,
+			Integrations: []definitions.IntegrationChange{{
+				UID:    "slack-1",
+				Type:   "slack",
+				Change: definitions.ConfigChangeChanged,
+				Changes: []definitions.ValueChange{
+					{Path: "disableResolveMessage", Change: definitions.ConfigChangeChanged, From: false, To: true},
+					{Path: "settings.recipient", Change: definitions.ConfigChangeChanged, From: "#alerts", To: "#incidents"},
+				},
+				SecureSettings: []string{"url"},
+			}},
+		},
+		{Name: "webhook", Change: definitions.ConfigChangeRemoved},
+	}, diff.Receivers)
+
+	t.Run("identical configurations have no changes", func(t *testing.T) {
+		diff, err := diffAlertmanagerConfigurations(from, from, decrypt)
+		require.NoError(t, err)
+		require.Empty(t, diff.Templates)
+		require.Empty(t, diff.MuteTimeIntervals)
+		require.Empty(t, diff.Route)
+		require.Empty(t, diff.Receivers)
+	})
+}
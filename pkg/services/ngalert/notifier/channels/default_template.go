@@ -9,7 +9,11 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-const DefaultMessageTitleEmbed = `{{ template "default.title" . }}`
+const (
+	DefaultMessageTitleEmbed = `{{ template "default.title" . }}`
+	DefaultMessageEmbed      = `{{ template "default.message" . }}`
+	TeamsDefaultMessageEmbed = `{{ template "teams.default.message" . }}`
+)
 
 var DefaultTemplateString = `
 {{ define "__subject" }}[{{ .Status | toUpper }}{{ if eq .Status "firing" }}:{{ .Alerts.Firing | len }}{{ if gt (.Alerts.Resolved | len) 0 }}, RESOLVED:{{ .Alerts.Resolved | len }}{{ end }}{{ end }}] {{ .GroupLabels.SortedPairs.Values | join " " }} {{ if gt (len .CommonLabels) (len .GroupLabels) }}({{ with .CommonLabels.Remove .GroupLabels.Names }}{{ .Values | join " " }}{{ end }}){{ end }}{{ end }}
@@ -0,0 +1,59 @@
+package channels
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultTemplatedSettings are the defaults of the templated settings of notifiers, by notifier type and setting
+// name. Templated settings that are not in here have no default.
+var DefaultTemplatedSettings = map[string]map[string]string{
+	"dingding":   {"message": DefaultMessageEmbed},
+	"discord":    {"message": DefaultMessageEmbed},
+	"email":      {"subject": DefaultMessageTitleEmbed},
+	"googlechat": {"message": DefaultMessageEmbed},
+	"opsgenie":   {"message": DefaultMessageTitleEmbed},
+	"pagerduty":  {"summary": DefaultMessageTitleEmbed},
+	"pushover":   {"message": DefaultMessageEmbed},
+	"sensugo":    {"message": DefaultMessageEmbed},
+	"slack":      {"title": DefaultMessageTitleEmbed, "text": DefaultMessageEmbed},
+	"teams":      {"title": DefaultMessageTitleEmbed, "message": TeamsDefaultMessageEmbed},
+	"telegram":   {"message": DefaultMessageEmbed},
+	"wecom":      {"title": DefaultMessageTitleEmbed, "message": DefaultMessageEmbed},
+}
+
+// DefaultTemplateDefinition is a named template of DefaultTemplateString.
+type DefaultTemplateDefinition struct {
+	Name string
+	// Template is the definition of the template, from its define action to the matching end action.
+	Template string
+}
+
+// blockActionRegex matches the start of the actions that open or close a block, the name of the template is
+// captured for define actions.
+var blockActionRegex = regexp.MustCompile(`\{\{-?\s*(define|block|if|range|with|end)\b(?:\s+"([^"]*)")?`)
+
+// DefaultTemplateDefinitions splits DefaultTemplateString into the definitions of its named templates, in the order
+// they are defined.
+func DefaultTemplateDefinitions() []DefaultTemplateDefinition {
+	var definitions []DefaultTemplateDefinition
+	depth, start, name := 0, 0, ""
+	for _, m := range blockActionRegex.FindAllStringSubmatchIndex(DefaultTemplateString, -1) {
+		switch DefaultTemplateString[m[2]:m[3]] {
+		case "define":
+			if depth == 0 {
+				start, name = m[0], DefaultTemplateString[m[4]:m[5]]
+			}
+			depth++
+		case "end":
+			depth--
+			if depth == 0 {
+				end := m[1] + strings.Index(DefaultTemplateString[m[1]:], "}}") + len("}}")
+				definitions = append(definitions, DefaultTemplateDefinition{Name: name, Template: DefaultTemplateString[start:end]})
+			}
+		default:
+			depth++
+		}
+	}
+	return definitions
+}
@@ -0,0 +1,34 @@
+package channels
+
+import (
+	"testing"
+	tmpltext "text/template"
+
+	"github.com/prometheus/alertmanager/template"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTemplateDefinitions(t *testing.T) {
+	all, err := tmpltext.New("").Funcs(tmpltext.FuncMap(template.DefaultFuncs)).Parse(DefaultTemplateString)
+	require.NoError(t, err)
+	var expected []string
+	for _, tmpl := range all.Templates() {
+		if tmpl.Name() != "" {
+			expected = append(expected, tmpl.Name())
+		}
+	}
+
+	definitions := DefaultTemplateDefinitions()
+
+	names := make([]string, 0, len(definitions))
+	for _, def := range definitions {
+		names = append(names, def.Name)
+		// every definition can be used on its own, such as to copy it into a message template
+		parsed, err := tmpltext.New("").Funcs(tmpltext.FuncMap(template.DefaultFuncs)).Parse(def.Template)
+		require.NoError(t, err, def.Name)
+		require.NotNil(t, parsed.Lookup(def.Name))
+	}
+	require.ElementsMatch(t, expected, names)
+	require.Equal(t, "default.title", definitions[2].Name)
+	require.Equal(t, `{{ define "default.title" }}{{ template "__subject" . }}{{ end }}`, definitions[2].Template)
+}
@@ -87,7 +87,7 @@ func NewOpsgenieConfig(config *NotificationChannelConfig, decryptFunc GetDecrypt
 		APIUrl:                    config.Settings.Get("apiUrl").MustString(OpsgenieAlertURL),
 		AutoClose:                 config.Settings.Get("autoClose").MustBool(true),
 		OverridePriority:          config.Settings.Get("overridePriority").MustBool(true),
-		Message:                   config.Settings.Get("message").MustString(`{{ template "default.title" . }}`),
+		Message:                   config.Settings.Get("message").MustString(DefaultMessageTitleEmbed),
 		Description:               config.Settings.Get("description").MustString(""),
 		SendTagsAs:                sendTagsAs,
 	}, nil
@@ -0,0 +1,132 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+// ErrNoEmailRenderer is returned when emails are rendered without a renderer.
+var ErrNoEmailRenderer = errors.New("emails cannot be rendered")
+
+// EmailRenderer renders emails without sending them.
+type EmailRenderer interface {
+	RenderEmailCommand(cmd *models.SendEmailCommand) (*notifications.Message, error)
+}
+
+// RenderedEmail is the email the email notifier sends for a group of alerts.
+type RenderedEmail struct {
+	Subject string
+	HTML    string
+}
+
+// recordingSender keeps the last webhook and email it is given instead of sending them.
+type recordingSender struct {
+	webhook *models.SendWebhookSync
+	email   *models.SendEmailCommand
+}
+
+func (s *recordingSender) SendWebhookSync(_ context.Context, cmd *models.SendWebhookSync) error {
+	s.webhook = cmd
+	return nil
+}
+
+func (s *recordingSender) SendEmailCommandHandlerSync(_ context.Context, cmd *models.SendEmailCommandSync) error {
+	s.email = &cmd.SendEmailCommand
+	return nil
+}
+
+func (s *recordingSender) SendEmailCommandHandler(_ context.Context, cmd *models.SendEmailCommand) error {
+	s.email = cmd
+	return nil
+}
+
+// RenderSlackMessage returns the JSON document the Slack notifier posts for the alerts, with the given title and
+// text templates.
+func RenderSlackMessage(ctx context.Context, tmpl *template.Template, title, text string, alerts ...*types.Alert) ([]byte, error) {
+	l := log.New("alerting.notifier.slack")
+	if err := checkTemplates(ctx, tmpl, l, alerts, title, text); err != nil {
+		return nil, err
+	}
+	sn := &SlackNotifier{
+		log:    l,
+		tmpl:   tmpl,
+		images: &UnavailableImageStore{},
+		Title:  title,
+		Text:   text,
+	}
+	msg, err := sn.buildSlackMessage(ctx, alerts)
+	if err != nil {
+		return nil, fmt.Errorf("build slack message: %w", err)
+	}
+	return json.MarshalIndent(msg, "", "  ")
+}
+
+// RenderWebhookMessage returns the JSON document the webhook notifier sends for the alerts. The webhook notifier
+// always uses the default title and message templates.
+func RenderWebhookMessage(ctx context.Context, tmpl *template.Template, alerts ...*types.Alert) ([]byte, error) {
+	l := log.New("alerting.notifier.webhook")
+	if err := checkTemplates(ctx, tmpl, l, alerts, DefaultMessageTitleEmbed, `{{ template "default.message" . }}`); err != nil {
+		return nil, err
+	}
+	sender := &recordingSender{}
+	wn := NewWebHookNotifier(&WebhookConfig{
+		NotificationChannelConfig: &NotificationChannelConfig{Type: "webhook"},
+		HTTPMethod:                "POST",
+	}, sender, &UnavailableImageStore{}, tmpl)
+	if _, err := wn.Notify(ctx, alerts...); err != nil {
+		return nil, err
+	}
+
+	var msg interface{}
+	if err := json.Unmarshal([]byte(sender.webhook.Body), &msg); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(msg, "", "  ")
+}
+
+// RenderEmail returns the email the email notifier sends for the alerts, with the given subject and message
+// templates. An empty message renders the list of alerts.
+func RenderEmail(ctx context.Context, tmpl *template.Template, renderer EmailRenderer, subject, message string, alerts ...*types.Alert) (RenderedEmail, error) {
+	if renderer == nil {
+		return RenderedEmail{}, ErrNoEmailRenderer
+	}
+	l := log.New("alerting.notifier.email")
+	if err := checkTemplates(ctx, tmpl, l, alerts, subject, message); err != nil {
+		return RenderedEmail{}, err
+	}
+	sender := &recordingSender{}
+	en := NewEmailNotifier(&EmailConfig{
+		NotificationChannelConfig: &NotificationChannelConfig{Type: "email"},
+		Subject:                   subject,
+		Message:                   message,
+	}, sender, &UnavailableImageStore{}, tmpl)
+	if _, err := en.Notify(ctx, alerts...); err != nil {
+		return RenderedEmail{}, err
+	}
+
+	msg, err := renderer.RenderEmailCommand(sender.email)
+	if err != nil {
+		return RenderedEmail{}, err
+	}
+	return RenderedEmail{Subject: msg.Subject, HTML: msg.Body["text/html"]}, nil
+}
+
+// checkTemplates executes the templates for the alerts and returns the first error. The notifiers only log
+// these errors and send what could be rendered.
+func checkTemplates(ctx context.Context, tmpl *template.Template, l log.Logger, alerts []*types.Alert, texts ...string) error {
+	var tmplErr error
+	text, _ := TmplText(ctx, tmpl, alerts, l, &tmplErr)
+	for _, t := range texts {
+		text(t)
+	}
+	return tmplErr
+}
@@ -0,0 +1,73 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tmpl := templateForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	t.Run("slack message uses the given title and text", func(t *testing.T) {
+		out, err := RenderSlackMessage(ctx, tmpl, `{{ .CommonLabels.alertname }} is firing`, `{{ len .Alerts }} alerts`, alerts...)
+		require.NoError(t, err)
+
+		var msg slackMessage
+		require.NoError(t, json.Unmarshal(out, &msg))
+		require.Len(t, msg.Attachments, 1)
+		require.Equal(t, "alert1 is firing", msg.Attachments[0].Title)
+		require.Equal(t, "1 alerts", msg.Attachments[0].Text)
+	})
+
+	t.Run("webhook message uses the default templates", func(t *testing.T) {
+		out, err := RenderWebhookMessage(ctx, tmpl, alerts...)
+		require.NoError(t, err)
+
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(out, &msg))
+		require.Equal(t, "[FIRING:1]  (val1)", msg["title"])
+		require.Equal(t, "alertname", msg["groupKey"])
+	})
+
+	t.Run("email is rendered by the renderer", func(t *testing.T) {
+		email, err := RenderEmail(ctx, tmpl, createCoreEmailService(t), `{{ .CommonLabels.alertname }} is firing`, "Custom message", alerts...)
+		require.NoError(t, err)
+
+		require.Equal(t, "alert1 is firing", email.Subject)
+		require.Contains(t, email.HTML, "Custom message")
+	})
+
+	t.Run("email cannot be rendered without renderer", func(t *testing.T) {
+		_, err := RenderEmail(ctx, tmpl, nil, DefaultMessageTitleEmbed, "", alerts...)
+		require.ErrorIs(t, err, ErrNoEmailRenderer)
+	})
+
+	t.Run("template errors are returned", func(t *testing.T) {
+		_, err := RenderSlackMessage(ctx, tmpl, `{{ template "does-not-exist" . }}`, "", alerts...)
+		require.Error(t, err)
+
+		_, err = RenderEmail(ctx, tmpl, createCoreEmailService(t), DefaultMessageTitleEmbed, `{{ .Missing.Field }}`, alerts...)
+		require.Error(t, err)
+	})
+}
@@ -3,12 +3,10 @@ package channels
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -18,6 +16,7 @@ import (
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -126,7 +125,7 @@ func NewSlackConfig(factoryConfig FactoryConfig) (*SlackConfig, error) {
 		IconEmoji:                 channelConfig.Settings.Get("icon_emoji").MustString(),
 		IconURL:                   channelConfig.Settings.Get("icon_url").MustString(),
 		Token:                     token,
-		Text:                      channelConfig.Settings.Get("text").MustString(`{{ template "default.message" . }}`),
+		Text:                      channelConfig.Settings.Get("text").MustString(DefaultMessageEmbed),
 		Title:                     channelConfig.Settings.Get("title").MustString(DefaultMessageTitleEmbed),
 	}, nil
 }
@@ -233,20 +232,10 @@ var sendSlackRequest = func(request *http.Request, logger log.Logger) (retErr er
 		}
 	}()
 
-	netTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			Renegotiation: tls.RenegotiateFreelyAsClient,
-		},
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout: 30 * time.Second,
-		}).DialContext,
-		TLSHandshakeTimeout: 5 * time.Second,
-	}
-	netClient := &http.Client{
-		Timeout:   time.Second * 30,
-		Transport: netTransport,
-	}
+	netClient := httpclient.NewOutboundClient(httpclient.OutboundOptions{
+		Name:    "alerting_notifications",
+		Timeout: 30 * time.Second,
+	})
 	resp, err := netClient.Do(request)
 	if err != nil {
 		return err
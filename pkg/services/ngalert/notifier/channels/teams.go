@@ -55,7 +55,7 @@ func NewTeamsConfig(config *NotificationChannelConfig) (*TeamsConfig, error) {
 	return &TeamsConfig{
 		NotificationChannelConfig: config,
 		URL:                       URL,
-		Message:                   config.Settings.Get("message").MustString(`{{ template "teams.default.message" .}}`),
+		Message:                   config.Settings.Get("message").MustString(TeamsDefaultMessageEmbed),
 		Title:                     config.Settings.Get("title").MustString(DefaultMessageTitleEmbed),
 		SectionTitle:              config.Settings.Get("sectiontitle").MustString(""),
 	}, nil
@@ -33,7 +33,7 @@ func NewFileStore(orgID int64, store kvstore.KVStore, workingDirPath string) *Fi
 		workingDirPath: workingDirPath,
 		orgID:          orgID,
 		kv:             kvstore.WithNamespace(store, orgID, KVNamespace),
-		logger:         log.New("filestore", "org", orgID),
+		logger:         log.New("ngalert.notifier.filestore").New("orgID", orgID),
 	}
 }
 
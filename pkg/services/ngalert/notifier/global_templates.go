@@ -0,0 +1,68 @@
+package notifier
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const globalTemplateExtension = ".tmpl"
+
+// LoadGlobalTemplates reads the message templates that are shared by all organizations from the *.tmpl files of the
+// directory. The name of a template is the name of its file without the extension. No templates are returned if the
+// path is empty.
+func LoadGlobalTemplates(path string) (map[string]string, error) {
+	templates := map[string]string{}
+	if path == "" {
+		return templates, nil
+	}
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read global templates directory %q: %w", path, err)
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != globalTemplateExtension {
+			continue
+		}
+		// We can safely ignore gosec here as the file is in the directory configured by the server admin
+		// nolint:gosec
+		content, err := ioutil.ReadFile(filepath.Join(path, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read global template %q: %w", file.Name(), err)
+		}
+		tmpl := apimodels.MessageTemplate{
+			Name:     strings.TrimSuffix(file.Name(), globalTemplateExtension),
+			Template: string(content),
+		}
+		if err := tmpl.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid global template %q: %w", file.Name(), err)
+		}
+		templates[tmpl.Name] = tmpl.Template
+	}
+	return templates, nil
+}
+
+// GlobalTemplates returns the message templates that are shared by all organizations.
+func (moa *MultiOrgAlertmanager) GlobalTemplates() map[string]string {
+	return moa.globalTemplates
+}
+
+// withGlobalTemplates adds the global templates that the organization does not override to the templates of the
+// configuration.
+func withGlobalTemplates(cfg *apimodels.PostableUserConfig, global map[string]string) {
+	if len(global) == 0 {
+		return
+	}
+	if cfg.TemplateFiles == nil {
+		cfg.TemplateFiles = make(map[string]string, len(global))
+	}
+	for name, content := range global {
+		if _, ok := cfg.TemplateFiles[name]; !ok {
+			cfg.TemplateFiles[name] = content
+		}
+	}
+}
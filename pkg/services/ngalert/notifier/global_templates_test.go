@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestLoadGlobalTemplates(t *testing.T) {
+	t.Run("no templates without a path", func(t *testing.T) {
+		templates, err := LoadGlobalTemplates("")
+		require.NoError(t, err)
+		require.Empty(t, templates)
+	})
+
+	t.Run("loads the templates of the directory", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "slack.tmpl"), []byte(`{{ define "slack.title" }}{{ .Status }}{{ end }}`), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "footer.tmpl"), []byte(`Sent by Grafana`), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte(`not a template`), 0600))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "nested.tmpl"), 0750))
+
+		templates, err := LoadGlobalTemplates(dir)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"slack":  `{{ define "slack.title" }}{{ .Status }}{{ end }}`,
+			"footer": "{{ define \"footer\" }}\n  Sent by Grafana\n{{ end }}",
+		}, templates)
+	})
+
+	t.Run("fails for invalid templates", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte(`{{ define "broken" }}{{ end`), 0600))
+
+		_, err := LoadGlobalTemplates(dir)
+		require.ErrorContains(t, err, `invalid global template "broken.tmpl"`)
+	})
+
+	t.Run("fails if the directory does not exist", func(t *testing.T) {
+		_, err := LoadGlobalTemplates(filepath.Join(t.TempDir(), "missing"))
+		require.Error(t, err)
+	})
+}
+
+func TestWithGlobalTemplates(t *testing.T) {
+	cfg := &apimodels.PostableUserConfig{TemplateFiles: map[string]string{"a": "org a"}}
+
+	withGlobalTemplates(cfg, map[string]string{"a": "global a", "b": "global b"})
+
+	require.Equal(t, map[string]string{"a": "org a", "b": "global b"}, cfg.TemplateFiles)
+}
@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -52,6 +54,9 @@ type MultiOrgAlertmanager struct {
 
 	metrics *metrics.MultiOrgAlertmanager
 	ns      notifications.Service
+
+	// globalTemplates are the message templates that are shared by all organizations.
+	globalTemplates map[string]string
 }
 
 func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgStore store.OrgStore,
@@ -73,6 +78,12 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 		ns:            ns,
 	}
 
+	globalTemplates, err := LoadGlobalTemplates(cfg.UnifiedAlerting.GlobalTemplatesPath)
+	if err != nil {
+		return nil, err
+	}
+	moa.globalTemplates = globalTemplates
+
 	clusterLogger := l.New("component", "cluster")
 	moa.peer = &NilPeer{}
 	if len(cfg.UnifiedAlerting.HAPeers) > 0 {
@@ -114,6 +125,8 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 func (moa *MultiOrgAlertmanager) Run(ctx context.Context) error {
 	moa.logger.Info("starting MultiOrg Alertmanager")
 
+	historyCleanup := time.NewTicker(configHistoryCleanupInterval)
+	defer historyCleanup.Stop()
 	for {
 		select {
 		case <-ctx.Done():
@@ -123,6 +136,8 @@ func (moa *MultiOrgAlertmanager) Run(ctx context.Context) error {
 			if err := moa.LoadAndSyncAlertmanagersForOrgs(ctx); err != nil {
 				moa.logger.Error("error while synchronizing Alertmanager orgs", "err", err)
 			}
+		case <-historyCleanup.C:
+			moa.cleanUpConfigurationHistory(ctx)
 		}
 	}
 }
@@ -170,7 +185,7 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 	moa.alertmanagersMtx.Lock()
 	for _, orgID := range orgIDs {
 		if _, isDisabledOrg := moa.settings.UnifiedAlerting.DisabledOrgs[orgID]; isDisabledOrg {
-			moa.logger.Debug("skipping syncing Alertmanger for disabled org", "org", orgID)
+			moa.logger.Debug("skipping syncing Alertmanger for disabled org", "orgID", orgID)
 			continue
 		}
 		orgsFound[orgID] = struct{}{}
@@ -182,9 +197,9 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 			// To export them, we need to translate the metrics from each individual registry and,
 			// then aggregate them on the main registry.
 			m := metrics.NewAlertmanagerMetrics(moa.metrics.GetOrCreateOrgRegistry(orgID))
-			am, err := newAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m)
+			am, err := newAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m, moa.globalTemplates)
 			if err != nil {
-				moa.logger.Error("unable to create Alertmanager for org", "org", orgID, "err", err)
+				moa.logger.Error("unable to create Alertmanager for org", "orgID", orgID, "err", err)
 			}
 			moa.alertmanagers[orgID] = am
 			alertmanager = am
@@ -194,11 +209,11 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 		if !cfgFound {
 			if found {
 				// This means that the configuration is gone but the organization, as well as the Alertmanager, exists.
-				moa.logger.Warn("Alertmanager exists for org but the configuration is gone. Applying the default configuration", "org", orgID)
+				moa.logger.Warn("Alertmanager exists for org but the configuration is gone. Applying the default configuration", "orgID", orgID)
 			}
 			err := alertmanager.SaveAndApplyDefaultConfig(ctx)
 			if err != nil {
-				moa.logger.Error("failed to apply the default Alertmanager configuration", "org", orgID)
+				moa.logger.Error("failed to apply the default Alertmanager configuration", "orgID", orgID)
 				continue
 			}
 			moa.alertmanagers[orgID] = alertmanager
@@ -207,9 +222,10 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 
 		err := alertmanager.ApplyConfig(dbConfig)
 		if err != nil {
-			moa.logger.Error("failed to apply Alertmanager config for org", "org", orgID, "id", dbConfig.ID, "err", err)
+			moa.logger.Error("failed to apply Alertmanager config for org", "orgID", orgID, "id", dbConfig.ID, "err", err)
 			continue
 		}
+		moa.metrics.ConfigSize.WithLabelValues(fmt.Sprint(orgID)).Set(float64(len(dbConfig.AlertmanagerConfiguration)))
 		moa.alertmanagers[orgID] = alertmanager
 	}
 
@@ -219,6 +235,7 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 			amsToStop[orgId] = am
 			delete(moa.alertmanagers, orgId)
 			moa.metrics.RemoveOrgRegistry(orgId)
+			moa.metrics.ConfigSize.DeleteLabelValues(fmt.Sprint(orgId))
 		}
 	}
 	moa.metrics.ActiveConfigurations.Set(float64(len(moa.alertmanagers)))
@@ -226,9 +243,9 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 
 	// Now, we can stop the Alertmanagers without having to hold a lock.
 	for orgID, am := range amsToStop {
-		moa.logger.Info("stopping Alertmanager", "org", orgID)
+		moa.logger.Info("stopping Alertmanager", "orgID", orgID)
 		am.StopAndWait()
-		moa.logger.Info("stopped Alertmanager", "org", orgID)
+		moa.logger.Info("stopped Alertmanager", "orgID", orgID)
 		// Cleanup all the remaining resources from this alertmanager.
 		am.fileStore.CleanUp()
 	}
@@ -328,6 +345,19 @@ func (moa *MultiOrgAlertmanager) AlertmanagerFor(orgID int64) (*Alertmanager, er
 	return orgAM, nil
 }
 
+// ListSilences returns the silences of the Alertmanager of the organization. An organization that does not have an
+// Alertmanager yet has no silences.
+func (moa *MultiOrgAlertmanager) ListSilences(_ context.Context, orgID int64) (apimodels.GettableSilences, error) {
+	am, err := moa.AlertmanagerFor(orgID)
+	if errors.Is(err, ErrNoAlertmanagerForOrg) {
+		return apimodels.GettableSilences{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return am.ListSilences(nil)
+}
+
 // NilPeer and NilChannel implements the Alertmanager clustering interface.
 type NilPeer struct{}
 
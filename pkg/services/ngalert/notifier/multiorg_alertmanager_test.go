@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
@@ -266,6 +267,91 @@ func TestMultiOrgAlertmanager_AlertmanagerFor(t *testing.T) {
 	}
 }
 
+func TestMultiOrgAlertmanager_ValidateAlertmanagerConfiguration(t *testing.T) {
+	configStore := &FakeConfigStore{
+		configs: map[int64]*models.AlertConfiguration{},
+	}
+	orgStore := &FakeOrgStore{
+		orgs: []int64{1},
+	}
+	tmpDir := t.TempDir()
+	cfg := &setting.Cfg{
+		DataPath:        tmpDir,
+		UnifiedAlerting: setting.UnifiedAlertingSettings{AlertmanagerConfigPollInterval: 3 * time.Minute, DefaultConfiguration: setting.GetAlertmanagerDefaultConfiguration()}, // do not poll in tests.
+	}
+	kvStore := NewFakeKVStore(t)
+	provStore := provisioning.NewFakeProvisioningStore()
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	decryptFn := secretsService.GetDecryptedValue
+	reg := prometheus.NewPedanticRegistry()
+	m := metrics.NewNGAlert(reg)
+	mam, err := NewMultiOrgAlertmanager(cfg, configStore, orgStore, kvStore, provStore, decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, mam.LoadAndSyncAlertmanagersForOrgs(ctx))
+
+	t.Run("a valid configuration has no errors", func(t *testing.T) {
+		config, err := Load([]byte(`{
+			"template_files": {"a": "{{ define \"a\" }}a{{ end }}"},
+			"alertmanager_config": {
+				"route": {"receiver": "webhook"},
+				"receivers": [{"name": "webhook", "grafana_managed_receiver_configs": [{"name": "webhook", "type": "webhook", "settings": {"url": "http://localhost"}}]}]
+			}
+		}`))
+		require.NoError(t, err)
+
+		errs, err := mam.ValidateAlertmanagerConfiguration(ctx, 1, *config)
+		require.NoError(t, err)
+		require.Empty(t, errs)
+	})
+
+	t.Run("all the errors are returned", func(t *testing.T) {
+		config, err := Load([]byte(`{
+			"template_files": {"a": "{{ define \"a\" }}a", "b": "{{ define \"b\" }}b{{ end }}"},
+			"alertmanager_config": {
+				"route": {"receiver": "webhook"},
+				"mute_time_intervals": [{"name": "night", "time_intervals": [{"times": [{"start_time": "00:00", "end_time": "12:00"}, {"start_time": "06:00", "end_time": "18:00"}]}]}],
+				"receivers": [
+					{"name": "webhook", "grafana_managed_receiver_configs": [{"name": "webhook", "type": "webhook", "settings": {}}]},
+					{"name": "unknown", "grafana_managed_receiver_configs": [{"name": "unknown", "type": "unknown", "settings": {}}]}
+				]
+			}
+		}`))
+		require.NoError(t, err)
+
+		errs, err := mam.ValidateAlertmanagerConfiguration(ctx, 1, *config)
+		require.NoError(t, err)
+		msgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			msgs = append(msgs, err.Error())
+		}
+		require.Len(t, msgs, 4)
+		require.Contains(t, msgs[0], `invalid mute timing "night"`)
+		require.Contains(t, msgs[1], "template: a:1: unexpected EOF")
+		require.Equal(t, `receiver "webhook": the receiver is invalid: failed to validate receiver "webhook" of type "webhook": could not find url property in settings`, msgs[2])
+		require.Equal(t, `receiver "unknown": the receiver is invalid: notifier unknown is not supported`, msgs[3])
+	})
+
+	t.Run("receivers that do not exist are reported", func(t *testing.T) {
+		config, err := Load([]byte(`{
+			"alertmanager_config": {
+				"route": {"receiver": "webhook"},
+				"receivers": [{"name": "webhook", "grafana_managed_receiver_configs": [{"uid": "missing", "name": "webhook", "type": "webhook", "settings": {}}]}]
+			}
+		}`))
+		require.NoError(t, err)
+
+		errs, err := mam.ValidateAlertmanagerConfiguration(ctx, 1, *config)
+		require.NoError(t, err)
+		require.Equal(t, []error{UnknownReceiverError{UID: "missing"}}, errs)
+	})
+
+	t.Run("fails for an org without Alertmanager", func(t *testing.T) {
+		_, err := mam.ValidateAlertmanagerConfiguration(ctx, 2, definitions.PostableUserConfig{})
+		require.ErrorIs(t, err, ErrNoAlertmanagerForOrg)
+	})
+}
+
 var brokenConfig = `
 	"alertmanager_config": {
 		"route": {
@@ -0,0 +1,221 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// quietHoursFlushInterval is how often the notifications held back during quiet hours are checked for delivery.
+var quietHoursFlushInterval = time.Minute
+
+// quietHoursStage holds back the notifications of the alerts selected by the quiet hours of a receiver while
+// one of their time intervals is active. The notifications are queued in the database, so they are not lost
+// on restarts, and are delivered as a digest by flushQuietHours once the quiet hours are over.
+type quietHoursStage struct {
+	orgID      int64
+	receiver   string
+	quietHours *apimodels.QuietHours
+	muteTimes  map[string][]apimodels.TimeInterval
+	store      store.DeferredNotificationStore
+	logger     log.Logger
+}
+
+func newQuietHoursStage(orgID int64, receiver string, qh *apimodels.QuietHours, muteTimes map[string][]apimodels.TimeInterval, store store.DeferredNotificationStore, l log.Logger) *quietHoursStage {
+	return &quietHoursStage{
+		orgID:      orgID,
+		receiver:   receiver,
+		quietHours: qh,
+		muteTimes:  muteTimes,
+		store:      store,
+		logger:     l,
+	}
+}
+
+func (s *quietHoursStage) Exec(ctx context.Context, _ gokitlog.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	now, ok := notify.Now(ctx)
+	if !ok {
+		return ctx, alerts, errors.New("missing now timestamp")
+	}
+	if !quietHoursActive(s.quietHours, s.muteTimes, now) {
+		return ctx, alerts, nil
+	}
+
+	send := make([]*types.Alert, 0, len(alerts))
+	deferred := make([]ngmodels.DeferredNotification, 0, len(alerts))
+	for _, a := range alerts {
+		if !labels.Matchers(s.quietHours.ObjectMatchers).Matches(a.Labels) {
+			send = append(send, a)
+			continue
+		}
+		b, err := json.Marshal(a)
+		if err != nil {
+			s.logger.Error("failed to encode alert, it is sent right away", "receiver", s.receiver, "err", err)
+			send = append(send, a)
+			continue
+		}
+		deferred = append(deferred, ngmodels.DeferredNotification{Fingerprint: a.Fingerprint().String(), Alert: string(b)})
+	}
+
+	if len(deferred) == 0 {
+		return ctx, send, nil
+	}
+	// If the notifications cannot be queued, they are sent rather than lost.
+	if err := s.store.DeferNotifications(ctx, s.orgID, s.receiver, deferred); err != nil {
+		s.logger.Error("failed to defer notifications during quiet hours, they are sent right away", "receiver", s.receiver, "err", err)
+		return ctx, alerts, nil
+	}
+	s.logger.Debug("notifications deferred during quiet hours", "receiver", s.receiver, "deferred", len(deferred), "sent", len(send))
+	return ctx, send, nil
+}
+
+// quietHoursActive returns true if one of the time intervals of the quiet hours contains the given time.
+func quietHoursActive(qh *apimodels.QuietHours, muteTimes map[string][]apimodels.TimeInterval, now time.Time) bool {
+	return timeIntervalsActive(qh.TimeIntervals, muteTimes, now)
+}
+
+// buildQuietHoursMap returns the quiet hours of the receivers that have them, by receiver name.
+func buildQuietHoursMap(receivers []*apimodels.PostableApiReceiver) map[string]*apimodels.QuietHours {
+	quietHours := make(map[string]*apimodels.QuietHours)
+	for _, r := range receivers {
+		if r.QuietHours != nil {
+			quietHours[r.Name] = r.QuietHours
+		}
+	}
+	return quietHours
+}
+
+func (am *Alertmanager) runQuietHoursFlush(ctx context.Context) {
+	ticker := time.NewTicker(quietHoursFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-am.stopc:
+			return
+		case <-ticker.C:
+			if am.Ready() {
+				am.flushQuietHours(ctx, time.Now())
+			}
+		}
+	}
+}
+
+// flushQuietHours delivers the notifications held back for the receivers whose quiet hours are over.
+// Notifications of receivers that no longer exist are dropped.
+func (am *Alertmanager) flushQuietHours(ctx context.Context, now time.Time) {
+	// In a cluster all instances share the queue, only the first one delivers the digests.
+	if am.peer.Position() != 0 {
+		return
+	}
+
+	queued, err := am.Store.GetDeferredNotifications(ctx, am.orgID)
+	if err != nil {
+		am.logger.Error("failed to get deferred notifications", "err", err)
+		return
+	}
+	if len(queued) == 0 {
+		return
+	}
+
+	byReceiver := make(map[string][]ngmodels.DeferredNotification)
+	for _, n := range queued {
+		byReceiver[n.Receiver] = append(byReceiver[n.Receiver], n)
+	}
+
+	am.reloadConfigMtx.RLock()
+	integrations := make(map[string][]notify.Integration, len(byReceiver))
+	for name := range byReceiver {
+		if qh, ok := am.quietHours[name]; ok && quietHoursActive(qh, am.muteTimes, now) {
+			delete(byReceiver, name)
+			continue
+		}
+		integrations[name] = am.integrations[name]
+	}
+	am.reloadConfigMtx.RUnlock()
+
+	for name, notifications := range byReceiver {
+		if err := am.sendQuietHoursDigest(ctx, name, integrations[name], notifications, now); err != nil {
+			am.logger.Error("failed to send notifications deferred during quiet hours", "receiver", name, "err", err)
+			continue
+		}
+		ids := make([]int64, 0, len(notifications))
+		for _, n := range notifications {
+			ids = append(ids, n.ID)
+		}
+		if err := am.Store.DeleteDeferredNotifications(ctx, am.orgID, ids); err != nil {
+			am.logger.Error("failed to delete deferred notifications", "receiver", name, "err", err)
+		}
+	}
+}
+
+// sendQuietHoursDigest sends the deferred notifications of a receiver as a single notification to each of its
+// integrations. Alerts that are still known use their current state. It only fails if no integration succeeded,
+// so the notifications are kept for the next attempt.
+func (am *Alertmanager) sendQuietHoursDigest(ctx context.Context, receiver string, integrations []notify.Integration, notifications []ngmodels.DeferredNotification, now time.Time) error {
+	if len(integrations) == 0 {
+		am.logger.Warn("dropping notifications deferred during quiet hours, the receiver has no integrations", "receiver", receiver, "count", len(notifications))
+		return nil
+	}
+
+	alerts := make([]*types.Alert, 0, len(notifications))
+	for _, n := range notifications {
+		if fp, err := model.ParseFingerprint(n.Fingerprint); err == nil {
+			if current, err := am.alerts.Get(fp); err == nil {
+				alerts = append(alerts, current)
+				continue
+			}
+		}
+		var a types.Alert
+		if err := json.Unmarshal([]byte(n.Alert), &a); err != nil {
+			am.logger.Error("failed to decode deferred notification, dropping it", "receiver", receiver, "fingerprint", n.Fingerprint, "err", err)
+			continue
+		}
+		alerts = append(alerts, &a)
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	ctx = notify.WithGroupKey(ctx, fmt.Sprintf("{}/quiet_hours:{receiver=%q}", receiver))
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{})
+	ctx = notify.WithReceiverName(ctx, receiver)
+	ctx = notify.WithNow(ctx, now)
+
+	var errs []error
+	for i := range integrations {
+		toSend := alerts
+		if !integrations[i].SendResolved() {
+			toSend = make([]*types.Alert, 0, len(alerts))
+			for _, a := range alerts {
+				if !a.ResolvedAt(now) {
+					toSend = append(toSend, a)
+				}
+			}
+			if len(toSend) == 0 {
+				continue
+			}
+		}
+		if _, err := integrations[i].Notify(ctx, toSend...); err != nil {
+			am.logger.Error("failed to send quiet hours digest", "receiver", receiver, "integration", integrations[i].String(), "err", err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 && len(errs) == len(integrations) {
+		return fmt.Errorf("all %d integrations failed: %w", len(errs), errs[0])
+	}
+	am.logger.Info("sent notifications deferred during quiet hours", "receiver", receiver, "alerts", len(alerts))
+	return nil
+}
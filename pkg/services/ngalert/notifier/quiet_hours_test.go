@@ -0,0 +1,142 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+var quietHoursTestMuteTimes = map[string][]apimodels.TimeInterval{
+	"always": {{}},
+	"never":  {{Years: []timeinterval.YearRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 2000, End: 2000}}}}},
+}
+
+func quietHoursTestAlert(name, severity string, endsAt time.Time) *types.Alert {
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": model.LabelValue(name), "severity": model.LabelValue(severity)},
+			StartsAt: endsAt.Add(-time.Hour),
+			EndsAt:   endsAt,
+		},
+	}
+}
+
+func TestQuietHoursStage(t *testing.T) {
+	matcher, err := labels.NewMatcher(labels.MatchNotEqual, "severity", "critical")
+	require.NoError(t, err)
+	now := time.Now()
+	ctx := notify.WithNow(context.Background(), now)
+	info := quietHoursTestAlert("info", "info", now.Add(time.Hour))
+	critical := quietHoursTestAlert("critical", "critical", now.Add(time.Hour))
+
+	t.Run("alerts are sent outside of the quiet hours", func(t *testing.T) {
+		fakeStore := &FakeConfigStore{}
+		qh := &apimodels.QuietHours{TimeIntervals: []string{"never"}, ObjectMatchers: apimodels.ObjectMatchers{matcher}}
+		stage := newQuietHoursStage(1, "team", qh, quietHoursTestMuteTimes, fakeStore, log.NewNopLogger())
+
+		_, alerts, err := stage.Exec(ctx, nil, info, critical)
+		require.NoError(t, err)
+		require.Equal(t, []*types.Alert{info, critical}, alerts)
+		require.Empty(t, fakeStore.deferred)
+	})
+
+	t.Run("selected alerts are deferred during the quiet hours", func(t *testing.T) {
+		fakeStore := &FakeConfigStore{}
+		qh := &apimodels.QuietHours{TimeIntervals: []string{"never", "always"}, ObjectMatchers: apimodels.ObjectMatchers{matcher}}
+		stage := newQuietHoursStage(1, "team", qh, quietHoursTestMuteTimes, fakeStore, log.NewNopLogger())
+
+		_, alerts, err := stage.Exec(ctx, nil, info, critical)
+		require.NoError(t, err)
+		require.Equal(t, []*types.Alert{critical}, alerts)
+		require.Len(t, fakeStore.deferred, 1)
+		require.Equal(t, "team", fakeStore.deferred[0].Receiver)
+		require.Equal(t, info.Fingerprint().String(), fakeStore.deferred[0].Fingerprint)
+
+		// the alert is only queued once
+		_, _, err = stage.Exec(ctx, nil, info)
+		require.NoError(t, err)
+		require.Len(t, fakeStore.deferred, 1)
+	})
+}
+
+type fakeDigestNotifier struct {
+	sendResolved bool
+	err          error
+	received     [][]*types.Alert
+}
+
+func (n *fakeDigestNotifier) Notify(_ context.Context, alerts ...*types.Alert) (bool, error) {
+	n.received = append(n.received, alerts)
+	return false, n.err
+}
+
+func (n *fakeDigestNotifier) SendResolved() bool {
+	return n.sendResolved
+}
+
+func TestFlushQuietHours(t *testing.T) {
+	now := time.Now()
+	setup := func(t *testing.T, quietHours string, notifiers ...*fakeDigestNotifier) (*Alertmanager, *FakeConfigStore) {
+		am := setupAMTest(t)
+		fakeStore := &FakeConfigStore{}
+		am.Store = fakeStore
+		am.muteTimes = quietHoursTestMuteTimes
+		am.quietHours = map[string]*apimodels.QuietHours{"team": {TimeIntervals: []string{quietHours}}}
+		am.integrations = map[string][]notify.Integration{}
+		for i, n := range notifiers {
+			am.integrations["team"] = append(am.integrations["team"], notify.NewIntegration(n, n, "fake", i))
+		}
+
+		stage := newQuietHoursStage(1, "team", &apimodels.QuietHours{TimeIntervals: []string{"always"}}, quietHoursTestMuteTimes, fakeStore, log.NewNopLogger())
+		_, _, err := stage.Exec(notify.WithNow(context.Background(), now), nil,
+			quietHoursTestAlert("firing", "info", now.Add(time.Hour)),
+			quietHoursTestAlert("resolved", "info", now.Add(-time.Minute)),
+		)
+		require.NoError(t, err)
+		require.Len(t, fakeStore.deferred, 2)
+		return am, fakeStore
+	}
+
+	t.Run("notifications are kept during the quiet hours", func(t *testing.T) {
+		n := &fakeDigestNotifier{}
+		am, fakeStore := setup(t, "always", n)
+
+		am.flushQuietHours(context.Background(), now)
+		require.Empty(t, n.received)
+		require.Len(t, fakeStore.deferred, 2)
+	})
+
+	t.Run("notifications are sent as a digest once the quiet hours are over", func(t *testing.T) {
+		withResolved := &fakeDigestNotifier{sendResolved: true}
+		withoutResolved := &fakeDigestNotifier{}
+		am, fakeStore := setup(t, "never", withResolved, withoutResolved)
+
+		am.flushQuietHours(context.Background(), now)
+		require.Len(t, withResolved.received, 1)
+		require.Len(t, withResolved.received[0], 2)
+		require.Len(t, withoutResolved.received, 1)
+		require.Len(t, withoutResolved.received[0], 1)
+		require.Equal(t, model.LabelValue("firing"), withoutResolved.received[0][0].Labels["alertname"])
+		require.Empty(t, fakeStore.deferred)
+	})
+
+	t.Run("notifications are kept if the digest could not be sent", func(t *testing.T) {
+		n := &fakeDigestNotifier{sendResolved: true, err: errors.New("unavailable")}
+		am, fakeStore := setup(t, "never", n)
+
+		am.flushQuietHours(context.Background(), now)
+		require.Len(t, n.received, 1)
+		require.Len(t, fakeStore.deferred, 2)
+	})
+}
@@ -0,0 +1,206 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	tmpltext "text/template"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+)
+
+var (
+	ErrInvalidTemplate = errors.New("invalid template")
+
+	// templateErrorLocationRegexp matches the location of text/template parse and execution errors, e.g.
+	// "template: name:3: ..." or "template: name:3:12: executing ...".
+	templateErrorLocationRegexp = regexp.MustCompile(`template: ([^:\n]*):(\d+)(?::(\d+))?: `)
+)
+
+type TestTemplatesResult struct {
+	Format  apimodels.TestTemplateFormat
+	Subject string
+	Output  string
+	Error   error
+}
+
+// TemplateErrorLocation returns the location in the template of a template parse or execution error, or nil if the
+// error has no location.
+func TemplateErrorLocation(err error) *apimodels.TemplateErrorLocation {
+	if err == nil {
+		return nil
+	}
+	m := templateErrorLocationRegexp.FindStringSubmatch(err.Error())
+	if m == nil {
+		return nil
+	}
+	loc := &apimodels.TemplateErrorLocation{Template: m[1]}
+	loc.Line, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		loc.Column, _ = strconv.Atoi(m[3])
+	}
+	return loc
+}
+
+// TestTemplate renders the template of the request for each requested notifier format, together with the
+// templates of the configuration. Failing to render a format is reported in its result.
+func (am *Alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) ([]TestTemplatesResult, error) {
+	formats := c.Formats
+	if len(formats) == 0 {
+		formats = apimodels.TestTemplateFormats
+	}
+	for _, format := range formats {
+		if !isTestTemplateFormat(format) {
+			return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidTemplate, format)
+		}
+	}
+
+	mt := apimodels.MessageTemplate{Name: c.Name, Template: c.Template}
+	if err := mt.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTemplate, err.Error())
+	}
+	tmpl, err := am.templateWith(mt.Name, mt.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	alerts := newTestTemplateAlerts(c.Alerts, now)
+	ctx = notify.WithGroupKey(ctx, fmt.Sprintf("template-test-%s-%d", mt.Name, now.UnixNano()))
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{})
+	ctx = notify.WithReceiverName(ctx, "template-test")
+
+	title := c.Title
+	if title == "" {
+		title = channels.DefaultMessageTitleEmbed
+	}
+	message := c.Message
+	if message == "" {
+		message = `{{ template "default.message" . }}`
+	}
+
+	results := make([]TestTemplatesResult, 0, len(formats))
+	for _, format := range formats {
+		result := TestTemplatesResult{Format: format}
+		switch format {
+		case apimodels.TestTemplateFormatSlack:
+			var out []byte
+			out, result.Error = channels.RenderSlackMessage(ctx, tmpl, title, message, alerts...)
+			result.Output = string(out)
+		case apimodels.TestTemplateFormatEmail:
+			// the email notifier renders the list of alerts if no message is configured
+			renderer, _ := am.NotificationService.(channels.EmailRenderer)
+			var email channels.RenderedEmail
+			email, result.Error = channels.RenderEmail(ctx, tmpl, renderer, title, c.Message, alerts...)
+			result.Subject, result.Output = email.Subject, email.HTML
+		case apimodels.TestTemplateFormatWebhook:
+			var out []byte
+			out, result.Error = channels.RenderWebhookMessage(ctx, tmpl, alerts...)
+			result.Output = string(out)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// templateWith returns the templates of the configuration, where the template with the given name is replaced
+// with the given content.
+func (am *Alertmanager) templateWith(name, content string) (*template.Template, error) {
+	if filepath.Base(name) != name || name == "." || name == ".." {
+		return nil, fmt.Errorf("%w: invalid name %q", ErrInvalidTemplate, name)
+	}
+
+	dir, err := os.MkdirTemp("", "grafana-template-test-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			am.logger.Warn("failed to remove template test directory", "dir", dir, "err", err)
+		}
+	}()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		return nil, err
+	}
+
+	am.reloadConfigMtx.RLock()
+	if !am.ready() {
+		am.reloadConfigMtx.RUnlock()
+		return nil, errors.New("alertmanager is not initialized")
+	}
+	paths := make([]string, 0, len(am.config.TemplateFiles)+1)
+	for other := range am.config.TemplateFiles {
+		if other != name {
+			paths = append(paths, filepath.Join(am.WorkingDirPath(), other))
+		}
+	}
+	am.reloadConfigMtx.RUnlock()
+
+	tmpl, err := am.templateFromPaths(append(paths, file)...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTemplate, err.Error())
+	}
+	return tmpl, nil
+}
+
+// parseTemplate checks the name and parses the content of a template file of a configuration, as saving the
+// configuration does. The errors of text/template contain the name of the template.
+func parseTemplate(name, content string) error {
+	if name != filepath.Base(filepath.Clean(name)) {
+		return fmt.Errorf("template file name '%s' is not valid", name)
+	}
+	_, err := tmpltext.New(name).Option("missingkey=zero").Funcs(tmpltext.FuncMap(template.DefaultFuncs)).Parse(content)
+	return err
+}
+
+// newTestTemplateAlerts returns the alerts to render templates for. Alerts without a start are firing since now,
+// and a test alert is returned if there are none.
+func newTestTemplateAlerts(fixtures []*apimodels.TestTemplateAlert, now time.Time) []*types.Alert {
+	if len(fixtures) == 0 {
+		alert := newTestAlert(apimodels.TestReceiversConfigBodyParams{}, now, now)
+		return []*types.Alert{&alert}
+	}
+
+	alerts := make([]*types.Alert, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		if fixture == nil {
+			continue
+		}
+		alert := &types.Alert{
+			Alert: model.Alert{
+				Labels:       fixture.Labels.Clone(),
+				Annotations:  fixture.Annotations.Clone(),
+				StartsAt:     fixture.StartsAt,
+				EndsAt:       fixture.EndsAt,
+				GeneratorURL: fixture.GeneratorURL,
+			},
+			UpdatedAt: now,
+		}
+		if alert.StartsAt.IsZero() {
+			alert.StartsAt = now
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+func isTestTemplateFormat(format apimodels.TestTemplateFormat) bool {
+	for _, f := range apimodels.TestTemplateFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestNewTestTemplateAlerts(t *testing.T) {
+	now := time.Now()
+
+	t.Run("test alert is used without fixtures", func(t *testing.T) {
+		alerts := newTestTemplateAlerts(nil, now)
+
+		require.Len(t, alerts, 1)
+		require.Equal(t, model.LabelValue("TestAlert"), alerts[0].Labels[model.AlertNameLabel])
+		require.Equal(t, now, alerts[0].StartsAt)
+	})
+
+	t.Run("fixtures without start are firing since now", func(t *testing.T) {
+		resolvedAt := now.Add(-time.Minute)
+		alerts := newTestTemplateAlerts([]*apimodels.TestTemplateAlert{
+			{Labels: model.LabelSet{"alertname": "firing"}},
+			{
+				Labels:   model.LabelSet{"alertname": "resolved"},
+				StartsAt: now.Add(-time.Hour),
+				EndsAt:   resolvedAt,
+			},
+		}, now)
+
+		require.Len(t, alerts, 2)
+		require.Equal(t, now, alerts[0].StartsAt)
+		require.Equal(t, model.AlertFiring, alerts[0].Status())
+		require.Equal(t, model.AlertResolved, alerts[1].Status())
+		require.NotNil(t, alerts[1].Annotations)
+	})
+}
+
+func TestTemplateErrorLocation(t *testing.T) {
+	tc := []struct {
+		name     string
+		err      error
+		expected *apimodels.TemplateErrorLocation
+	}{
+		{
+			name:     "parse error has the line",
+			err:      fmt.Errorf("%w: template: custom:3: function \"nope\" not defined", ErrInvalidTemplate),
+			expected: &apimodels.TemplateErrorLocation{Template: "custom", Line: 3},
+		},
+		{
+			name:     "execution error has the line and column",
+			err:      errors.New(`template: custom:2:14: executing "custom.title" at <.Missing.Field>: nil pointer evaluating`),
+			expected: &apimodels.TemplateErrorLocation{Template: "custom", Line: 2, Column: 14},
+		},
+		{
+			name:     "error in the title has no template name",
+			err:      errors.New(`template: :1:3: executing "" at <.Foo>: can't evaluate field Foo`),
+			expected: &apimodels.TemplateErrorLocation{Template: "", Line: 1, Column: 3},
+		},
+		{
+			name: "error without location",
+			err:  errors.New("email renderer is not configured"),
+		},
+		{
+			name: "no error",
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, TemplateErrorLocation(tt.err))
+		})
+	}
+}
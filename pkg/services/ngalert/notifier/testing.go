@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -15,7 +16,9 @@ import (
 )
 
 type FakeConfigStore struct {
-	configs map[int64]*models.AlertConfiguration
+	configs    map[int64]*models.AlertConfiguration
+	deferred   []models.DeferredNotification
+	deferredID int64
 }
 
 // Saves the image or returns an error.
@@ -31,6 +34,52 @@ func (f *FakeConfigStore) GetImages(ctx context.Context, tokens []string) ([]mod
 	return nil, models.ErrImageNotFound
 }
 
+func (f *FakeConfigStore) DeferNotifications(_ context.Context, orgID int64, receiver string, notifications []models.DeferredNotification) error {
+	for _, n := range notifications {
+		found := false
+		for i := range f.deferred {
+			if f.deferred[i].OrgID == orgID && f.deferred[i].Receiver == receiver && f.deferred[i].Fingerprint == n.Fingerprint {
+				f.deferred[i].Alert = n.Alert
+				found = true
+			}
+		}
+		if !found {
+			f.deferredID++
+			n.ID = f.deferredID
+			n.OrgID = orgID
+			n.Receiver = receiver
+			f.deferred = append(f.deferred, n)
+		}
+	}
+	return nil
+}
+
+func (f *FakeConfigStore) GetDeferredNotifications(_ context.Context, orgID int64) ([]models.DeferredNotification, error) {
+	var result []models.DeferredNotification
+	for _, n := range f.deferred {
+		if n.OrgID == orgID {
+			result = append(result, n)
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeConfigStore) DeleteDeferredNotifications(_ context.Context, orgID int64, ids []int64) error {
+	deleted := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		deleted[id] = struct{}{}
+	}
+	kept := f.deferred[:0]
+	for _, n := range f.deferred {
+		if _, ok := deleted[n.ID]; ok && n.OrgID == orgID {
+			continue
+		}
+		kept = append(kept, n)
+	}
+	f.deferred = kept
+	return nil
+}
+
 func NewFakeConfigStore(t *testing.T, configs map[int64]*models.AlertConfiguration) FakeConfigStore {
 	t.Helper()
 
@@ -47,6 +96,27 @@ func (f *FakeConfigStore) GetAllLatestAlertmanagerConfiguration(context.Context)
 	return result, nil
 }
 
+// GetAlertmanagerConfigurationVersions returns the latest configuration of the organization, the fake store does
+// not keep previous versions.
+func (f *FakeConfigStore) GetAlertmanagerConfigurationVersions(_ context.Context, orgID int64, _, offset int) ([]*models.AlertConfiguration, error) {
+	if c, ok := f.configs[orgID]; ok && offset == 0 {
+		return []*models.AlertConfiguration{c}, nil
+	}
+	return nil, nil
+}
+
+// DeleteOldAlertmanagerConfigurations removes nothing, the fake store does not keep previous versions.
+func (f *FakeConfigStore) DeleteOldAlertmanagerConfigurations(context.Context, int64, int, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *FakeConfigStore) GetAlertmanagerConfigurationVersion(_ context.Context, orgID int64, id int64) (*models.AlertConfiguration, error) {
+	if c, ok := f.configs[orgID]; ok && c.ID == id {
+		return c, nil
+	}
+	return nil, store.ErrNoAlertmanagerConfiguration
+}
+
 func (f *FakeConfigStore) GetLatestAlertmanagerConfiguration(_ context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error {
 	var ok bool
 	query.Result, ok = f.configs[query.OrgID]
@@ -63,6 +133,8 @@ func (f *FakeConfigStore) SaveAlertmanagerConfiguration(_ context.Context, cmd *
 		OrgID:                     cmd.OrgID,
 		ConfigurationVersion:      "v1",
 		Default:                   cmd.Default,
+		Origin:                    cmd.Origin,
+		CreatedBy:                 cmd.CreatedBy,
 	}
 
 	return nil
@@ -74,6 +146,8 @@ func (f *FakeConfigStore) SaveAlertmanagerConfigurationWithCallback(_ context.Co
 		OrgID:                     cmd.OrgID,
 		ConfigurationVersion:      "v1",
 		Default:                   cmd.Default,
+		Origin:                    cmd.Origin,
+		CreatedBy:                 cmd.CreatedBy,
 	}
 
 	if err := callback(); err != nil {
@@ -0,0 +1,99 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// activeTimeIntervalsPrefix is the prefix of the names that stand for the active time intervals of a route
+// among the mute time intervals of the Alertmanager routes. The vendored Alertmanager has no active time
+// intervals, so they reach timeIntervalsStage as a mute time interval.
+const activeTimeIntervalsPrefix = "__grafana_active_time_intervals_"
+
+// buildAMRoute returns the Alertmanager routing tree of a Grafana routing tree, and the active time intervals
+// of its routes by the name that stands for them among the mute time intervals of the Alertmanager routes.
+func buildAMRoute(r *apimodels.Route) (*config.Route, map[string][]string) {
+	amRoute := r.AsAMRoute()
+	activeTimes := make(map[string][]string)
+	addActiveTimeIntervals(r, amRoute, activeTimes)
+	return amRoute, activeTimes
+}
+
+func addActiveTimeIntervals(r *apimodels.Route, amRoute *config.Route, activeTimes map[string][]string) {
+	if len(r.ActiveTimeIntervals) > 0 {
+		name := fmt.Sprintf("%s%d", activeTimeIntervalsPrefix, len(activeTimes))
+		activeTimes[name] = r.ActiveTimeIntervals
+		// The slice is shared with the Grafana route, it must not be appended to in place.
+		muteTimeIntervals := make([]string, 0, len(amRoute.MuteTimeIntervals)+1)
+		amRoute.MuteTimeIntervals = append(append(muteTimeIntervals, amRoute.MuteTimeIntervals...), name)
+	}
+	for i, child := range r.Routes {
+		addActiveTimeIntervals(child, amRoute.Routes[i], activeTimes)
+	}
+}
+
+// timeIntervalsStage replaces the time mute stage of Alertmanager. It removes the notifications of routes
+// that are within one of their mute time intervals, or outside of all of their active time intervals.
+type timeIntervalsStage struct {
+	muteTimes   map[string][]apimodels.TimeInterval
+	activeTimes map[string][]string
+}
+
+func newTimeIntervalsStage(muteTimes map[string][]apimodels.TimeInterval, activeTimes map[string][]string) *timeIntervalsStage {
+	return &timeIntervalsStage{
+		muteTimes:   muteTimes,
+		activeTimes: activeTimes,
+	}
+}
+
+func (s *timeIntervalsStage) Exec(ctx context.Context, l gokitlog.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	names, ok := notify.MuteTimeIntervalNames(ctx)
+	if !ok {
+		return ctx, alerts, nil
+	}
+	now, ok := notify.Now(ctx)
+	if !ok {
+		return ctx, alerts, errors.New("missing now timestamp")
+	}
+
+	for _, name := range names {
+		if active, ok := s.activeTimes[name]; ok {
+			if !timeIntervalsActive(active, s.muteTimes, now) {
+				level.Debug(l).Log("msg", "Notifications not sent, route is not within active time")
+				return ctx, nil, nil
+			}
+			continue
+		}
+		if _, ok := s.muteTimes[name]; !ok {
+			return ctx, alerts, fmt.Errorf("mute time %s doesn't exist in config", name)
+		}
+		if timeIntervalsActive([]string{name}, s.muteTimes, now) {
+			level.Debug(l).Log("msg", "Notifications not sent, route is within mute time")
+			return ctx, nil, nil
+		}
+	}
+	return ctx, alerts, nil
+}
+
+// timeIntervalsActive returns true if one of the named time intervals contains the given time.
+// Unknown time intervals are ignored, the configuration is validated when it is saved.
+func timeIntervalsActive(names []string, muteTimes map[string][]apimodels.TimeInterval, now time.Time) bool {
+	for _, name := range names {
+		for _, ti := range muteTimes[name] {
+			if ti.ContainsTime(now.UTC()) {
+				return true
+			}
+		}
+	}
+	return false
+}
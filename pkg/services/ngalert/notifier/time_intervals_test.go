@@ -0,0 +1,81 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestBuildAMRoute(t *testing.T) {
+	route := &apimodels.Route{
+		Receiver: "default",
+		Routes: []*apimodels.Route{
+			{Receiver: "a", MuteTimeIntervals: []string{"never"}, ActiveTimeIntervals: []string{"always"}},
+			{Receiver: "b", Routes: []*apimodels.Route{{Receiver: "c", ActiveTimeIntervals: []string{"never"}}}},
+		},
+	}
+
+	amRoute, activeTimes := buildAMRoute(route)
+
+	require.Equal(t, map[string][]string{
+		activeTimeIntervalsPrefix + "0": {"always"},
+		activeTimeIntervalsPrefix + "1": {"never"},
+	}, activeTimes)
+	require.Equal(t, []string{"never", activeTimeIntervalsPrefix + "0"}, amRoute.Routes[0].MuteTimeIntervals)
+	require.Empty(t, amRoute.Routes[1].MuteTimeIntervals)
+	require.Equal(t, []string{activeTimeIntervalsPrefix + "1"}, amRoute.Routes[1].Routes[0].MuteTimeIntervals)
+	// the Grafana routes are not changed
+	require.Equal(t, []string{"never"}, route.Routes[0].MuteTimeIntervals)
+}
+
+func TestTimeIntervalsStage(t *testing.T) {
+	now := time.Now()
+	alert := &types.Alert{}
+	activeTimes := map[string][]string{
+		activeTimeIntervalsPrefix + "always": {"never", "always"},
+		activeTimeIntervalsPrefix + "never":  {"never"},
+	}
+	stage := newTimeIntervalsStage(quietHoursTestMuteTimes, activeTimes)
+	exec := func(names ...string) ([]*types.Alert, error) {
+		ctx := notify.WithNow(context.Background(), now)
+		ctx = notify.WithMuteTimeIntervals(ctx, names)
+		_, alerts, err := stage.Exec(ctx, gokitlog.NewNopLogger(), alert)
+		return alerts, err
+	}
+
+	t.Run("notifications are sent outside of mute time intervals", func(t *testing.T) {
+		alerts, err := exec("never")
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+	})
+
+	t.Run("notifications are muted within a mute time interval", func(t *testing.T) {
+		alerts, err := exec("never", "always")
+		require.NoError(t, err)
+		require.Empty(t, alerts)
+	})
+
+	t.Run("notifications are sent within an active time interval", func(t *testing.T) {
+		alerts, err := exec(activeTimeIntervalsPrefix + "always")
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+	})
+
+	t.Run("notifications are muted outside of the active time intervals", func(t *testing.T) {
+		alerts, err := exec(activeTimeIntervalsPrefix + "never")
+		require.NoError(t, err)
+		require.Empty(t, alerts)
+	})
+
+	t.Run("unknown mute time intervals are an error", func(t *testing.T) {
+		_, err := exec("unknown")
+		require.EqualError(t, err, "mute time unknown doesn't exist in config")
+	})
+}
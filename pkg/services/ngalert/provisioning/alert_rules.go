@@ -2,38 +2,77 @@ package provisioning
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	gfcore "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	prommodel "github.com/prometheus/common/model"
 )
 
 type AlertRuleService struct {
 	defaultIntervalSeconds int64
 	baseIntervalSeconds    int64
+	minIntervalSeconds     int64
 	ruleStore              RuleStore
 	provenanceStore        ProvisioningStore
 	xact                   TransactionManager
+	quotas                 QuotaChecker
+	folderPolicies         FolderPolicyChecker
+	instances              AlertInstanceReader
+	silences               SilenceReader
+	datasources            DatasourceReader
+	plugins                PluginReader
+	folders                DashboardReader
+	settings               setting.UnifiedAlertingSettings
+	metrics                *metrics.Provisioning
 	log                    log.Logger
 }
 
 func NewAlertRuleService(ruleStore RuleStore,
 	provenanceStore ProvisioningStore,
 	xact TransactionManager,
-	defaultIntervalSeconds int64,
-	baseIntervalSeconds int64,
+	quotas QuotaChecker,
+	folderPolicies FolderPolicyChecker,
+	instances AlertInstanceReader,
+	silences SilenceReader,
+	datasources DatasourceReader,
+	plugins PluginReader,
+	folders DashboardReader,
+	settings setting.UnifiedAlertingSettings,
+	metrics *metrics.Provisioning,
 	log log.Logger) *AlertRuleService {
 	return &AlertRuleService{
-		defaultIntervalSeconds: defaultIntervalSeconds,
-		baseIntervalSeconds:    baseIntervalSeconds,
+		defaultIntervalSeconds: int64(settings.DefaultRuleEvaluationInterval.Seconds()),
+		baseIntervalSeconds:    int64(settings.BaseInterval.Seconds()),
+		minIntervalSeconds:     int64(settings.MinInterval.Seconds()),
 		ruleStore:              ruleStore,
 		provenanceStore:        provenanceStore,
 		xact:                   xact,
+		quotas:                 quotas,
+		folderPolicies:         folderPolicies,
+		instances:              instances,
+		silences:               silences,
+		datasources:            datasources,
+		plugins:                plugins,
+		folders:                folders,
+		settings:               settings,
+		metrics:                metrics,
 		log:                    log,
 	}
 }
@@ -54,9 +93,44 @@ func (service *AlertRuleService) GetAlertRule(ctx context.Context, orgID int64,
 	return *query.Result, provenance, nil
 }
 
+// GetAlertRules returns the alert rules of the organization whose labels match all the matchers, sorted by folder,
+// rule group and position in the group. The rules can be restricted to a folder and to a rule group. The labels of
+// the rules include the labels of their group.
+func (service *AlertRuleService) GetAlertRules(ctx context.Context, orgID int64, folderUID, group string, matchers labels.Matchers) ([]definitions.AlertRule, error) {
+	q := models.ListAlertRulesQuery{
+		OrgID:     orgID,
+		RuleGroup: group,
+	}
+	if folderUID != "" {
+		q.NamespaceUIDs = []string{folderUID}
+	}
+	if err := service.ruleStore.ListAlertRules(ctx, &q); err != nil {
+		return nil, err
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.AlertRule, 0, len(q.Result))
+	for _, r := range q.Result {
+		ruleLabels := make(prommodel.LabelSet, len(r.Labels))
+		for k, v := range r.Labels {
+			ruleLabels[prommodel.LabelName(k)] = prommodel.LabelValue(v)
+		}
+		if !matchers.Matches(ruleLabels) {
+			continue
+		}
+		result = append(result, definitions.NewAlertRule(*r, provenances[r.UID]))
+	}
+	return result, nil
+}
+
 // CreateAlertRule creates a new alert rule. This function will ignore any
 // interval that is set in the rule struct and use the already existing group
-// interval or the default one.
+// interval or the default one, raised to the minimum interval of the folder.
+// The labels of the group are merged into the labels of the rule. The
+// datasources of the queries must exist and support alerting, otherwise a
+// DatasourceReferenceError is returned.
 func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
 	if rule.UID == "" {
 		rule.UID = util.GenerateShortUID()
@@ -64,13 +138,31 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 	interval, err := service.ruleStore.GetRuleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
 	// if the alert group does not exists we just use the default interval
 	if err != nil && errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+		policy, err := service.folderPolicies.GetFolderPolicy(ctx, rule.OrgID, rule.NamespaceUID)
+		if err != nil {
+			return models.AlertRule{}, err
+		}
 		interval = service.defaultIntervalSeconds
+		if interval < policy.MinIntervalSeconds {
+			interval = policy.MinIntervalSeconds
+		}
 	} else if err != nil {
 		return models.AlertRule{}, err
 	}
 	rule.IntervalSeconds = interval
 	rule.Updated = time.Now()
+	groupLabels, err := service.ruleGroupLabels(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	setGroupLabels(&rule, groupLabels)
+	if err := service.checkDatasources(ctx, rule.OrgID, rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.checkRuleLimits(ctx, rule.OrgID, rule); err != nil {
+			return err
+		}
 		ids, err := service.ruleStore.InsertAlertRules(ctx, []models.AlertRule{
 			rule,
 		})
@@ -90,6 +182,274 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 	return rule, nil
 }
 
+// CloneAlertRule returns a copy of the alert rule with the overrides of the clone applied. The copy has no
+// UID and is not saved, use CreateAlertRule to store it.
+func (service *AlertRuleService) CloneAlertRule(ctx context.Context, orgID int64, ruleUID string, clone definitions.AlertRuleClone) (models.AlertRule, error) {
+	query := &models.GetAlertRuleByUIDQuery{
+		OrgID: orgID,
+		UID:   ruleUID,
+	}
+	if err := service.ruleStore.GetAlertRuleByUID(ctx, query); err != nil {
+		return models.AlertRule{}, err
+	}
+	original := query.Result
+
+	rule := copyAlertRule(original)
+	rule.IsPaused = clone.IsPaused
+	if clone.Title != "" {
+		rule.Title = clone.Title
+	}
+	if clone.FolderUID != "" {
+		rule.NamespaceUID = clone.FolderUID
+	}
+	if clone.RuleGroup != "" {
+		rule.RuleGroup = clone.RuleGroup
+	}
+	if rule.Title == original.Title && rule.NamespaceUID == original.NamespaceUID {
+		return models.AlertRule{}, fmt.Errorf("%w: the clone must have a different title or folder than the original rule", models.ErrAlertRuleFailedValidation)
+	}
+	for k, v := range clone.Labels {
+		if v == "" {
+			delete(rule.Labels, k)
+			continue
+		}
+		rule.Labels[k] = v
+	}
+
+	if err := setRuleParameters(&rule, clone.Parameters); err != nil {
+		return models.AlertRule{}, err
+	}
+	return rule, nil
+}
+
+// PatchAlertRule returns the alert rule with the changes of the patch applied. Fields that are not set in the patch
+// are not changed. The rule is not saved, use UpdateAlertRule to store it with the same provenance.
+func (service *AlertRuleService) PatchAlertRule(ctx context.Context, orgID int64, ruleUID string, patch definitions.AlertRulePatch, provenance models.Provenance) (models.AlertRule, error) {
+	original, storedProvenance, err := service.GetAlertRule(ctx, orgID, ruleUID)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return models.AlertRule{}, fmt.Errorf("%w: cannot patch rule '%s' with provided provenance '%s', needs '%s'", ErrValidation, ruleUID, provenance, storedProvenance)
+	}
+
+	rule := copyAlertRule(&original)
+	rule.ID = original.ID
+	rule.UID = original.UID
+	rule.Version = original.Version
+	if patch.Title != "" {
+		rule.Title = patch.Title
+	}
+	if patch.Condition != "" {
+		rule.Condition = patch.Condition
+	}
+	if patch.NoDataState != "" {
+		state, err := models.NoDataStateFromString(string(patch.NoDataState))
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("%w: %s", models.ErrAlertRuleFailedValidation, err.Error())
+		}
+		rule.NoDataState = state
+	}
+	if patch.ExecErrState != "" {
+		state, err := models.ErrStateFromString(string(patch.ExecErrState))
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("%w: %s", models.ErrAlertRuleFailedValidation, err.Error())
+		}
+		rule.ExecErrState = state
+	}
+	if patch.For != nil {
+		if *patch.For < 0 {
+			return models.AlertRule{}, fmt.Errorf("%w: for cannot be negative", models.ErrAlertRuleFailedValidation)
+		}
+		rule.For = *patch.For
+	}
+	for k, v := range patch.Annotations {
+		if v == "" {
+			delete(rule.Annotations, k)
+			continue
+		}
+		rule.Annotations[k] = v
+	}
+	for k, v := range patch.Labels {
+		if v == "" {
+			delete(rule.Labels, k)
+			continue
+		}
+		rule.Labels[k] = v
+	}
+	if err := setRuleParameters(&rule, patch.Parameters); err != nil {
+		return models.AlertRule{}, err
+	}
+	for _, q := range rule.Data {
+		if q.RefID == rule.Condition {
+			return rule, nil
+		}
+	}
+	return models.AlertRule{}, fmt.Errorf("%w: condition '%s' is not the refId of a query", models.ErrAlertRuleFailedValidation, rule.Condition)
+}
+
+// setRuleParameters changes values in the models of the queries and expressions of the rule.
+func setRuleParameters(rule *models.AlertRule, parameters []definitions.AlertRuleCloneParameter) error {
+	for _, p := range parameters {
+		idx := -1
+		for i := range rule.Data {
+			if rule.Data[i].RefID == p.RefID {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return fmt.Errorf("%w: no query with refId '%s'", models.ErrAlertRuleFailedValidation, p.RefID)
+		}
+		if err := rule.Data[idx].SetModelValue(p.Path, p.Value); err != nil {
+			return fmt.Errorf("%w: query '%s': %s", models.ErrAlertRuleFailedValidation, p.RefID, err.Error())
+		}
+	}
+	return nil
+}
+
+// copyAlertRule returns a deep copy of the alert rule without its ID, UID and version, so it can be stored as a new
+// rule.
+func copyAlertRule(original *models.AlertRule) models.AlertRule {
+	rule := *original
+	rule.ID = 0
+	rule.UID = ""
+	rule.Version = 0
+	rule.Data = make([]models.AlertQuery, 0, len(original.Data))
+	for _, q := range original.Data {
+		q.Model = append([]byte(nil), q.Model...)
+		rule.Data = append(rule.Data, q)
+	}
+	rule.Annotations = make(map[string]string, len(original.Annotations))
+	for k, v := range original.Annotations {
+		rule.Annotations[k] = v
+	}
+	rule.Labels = make(map[string]string, len(original.Labels))
+	for k, v := range original.Labels {
+		rule.Labels[k] = v
+	}
+	rule.Webhooks = append([]models.AlertRuleWebhook(nil), original.Webhooks...)
+	return rule
+}
+
+// CopyRuleGroup copies a rule group into another folder, and optionally another organization. The copies get new
+// UIDs and the API provenance, and their queries use the datasources of the mapping instead of the original ones.
+// The new UIDs are derived from the target folder, group and rule title if cp.DeterministicUIDs is set, see
+// ImportRuleGroups. Datasources that are not in the mapping keep their UID. The copies are stored like imported rule groups, so they
+// are validated and checked against the folder policies, the limits and the quota of the target organization.
+//
+// The target folder must exist in the target organization. The copy cannot be stored in an existing group, or in a
+// folder that has rules with the same titles.
+func (service *AlertRuleService) CopyRuleGroup(ctx context.Context, orgID int64, folderUID, group string, cp definitions.AlertRuleGroupCopy) (definitions.AlertRuleGroup, error) {
+	source, err := service.GetRuleGroup(ctx, orgID, folderUID, group)
+	if err != nil {
+		return definitions.AlertRuleGroup{}, err
+	}
+	if cp.FolderUID == "" {
+		return definitions.AlertRuleGroup{}, fmt.Errorf("%w: the folder of the copy is required", ErrValidation)
+	}
+	targetOrgID := orgID
+	if cp.OrgID != 0 {
+		targetOrgID = cp.OrgID
+	}
+	if err := service.checkFolder(ctx, targetOrgID, cp.FolderUID); err != nil {
+		return definitions.AlertRuleGroup{}, err
+	}
+	target := definitions.AlertRuleGroup{
+		Title:     source.Title,
+		FolderUID: cp.FolderUID,
+		Interval:  source.Interval,
+		Labels:    source.Labels,
+		Rules:     make([]models.AlertRule, 0, len(source.Rules)),
+	}
+	if cp.Title != "" {
+		target.Title = cp.Title
+	}
+
+	query := &models.ListAlertRulesQuery{OrgID: targetOrgID, NamespaceUIDs: []string{target.FolderUID}}
+	if err := service.ruleStore.ListAlertRules(ctx, query); err != nil {
+		return definitions.AlertRuleGroup{}, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	titles := make(map[string]struct{}, len(query.Result))
+	for _, rule := range query.Result {
+		if rule.RuleGroup == target.Title {
+			return definitions.AlertRuleGroup{}, fmt.Errorf("%w: the group '%s' already exists in the folder '%s'", ErrValidation, target.Title, target.FolderUID)
+		}
+		titles[rule.Title] = struct{}{}
+	}
+
+	for i := range source.Rules {
+		rule := copyAlertRule(&source.Rules[i])
+		if _, ok := titles[rule.Title]; ok {
+			return definitions.AlertRuleGroup{}, fmt.Errorf("%w: a rule titled '%s' already exists in the folder '%s'", ErrValidation, rule.Title, target.FolderUID)
+		}
+		if cp.IsPaused {
+			rule.IsPaused = true
+		}
+		if targetOrgID != orgID {
+			// the dashboards of an organization cannot be linked from another one
+			rule.DashboardUID = nil
+			rule.PanelID = nil
+			delete(rule.Annotations, models.DashboardUIDAnnotation)
+			delete(rule.Annotations, models.PanelIDAnnotation)
+		}
+		for j := range rule.Data {
+			if err := remapDatasource(&rule.Data[j], cp.DatasourceUIDs); err != nil {
+				return definitions.AlertRuleGroup{}, fmt.Errorf("%w: rule '%s', query '%s': %s", ErrValidation, rule.Title, rule.Data[j].RefID, err.Error())
+			}
+		}
+		target.Rules = append(target.Rules, rule)
+	}
+
+	if err := service.ImportRuleGroups(ctx, targetOrgID, []definitions.AlertRuleGroup{target}, models.ProvenanceAPI, cp.DeterministicUIDs); err != nil {
+		return definitions.AlertRuleGroup{}, err
+	}
+	service.log.Info("copied rule group", "orgID", orgID, "folderUID", folderUID, "group", group, "targetOrgID", targetOrgID, "targetFolderUID", target.FolderUID, "targetGroup", target.Title)
+	return service.GetRuleGroup(ctx, targetOrgID, target.FolderUID, target.Title)
+}
+
+// checkFolder returns ErrValidation if there is no folder with the UID in the organization.
+func (service *AlertRuleService) checkFolder(ctx context.Context, orgID int64, uid string) error {
+	query := &gfcore.GetDashboardQuery{OrgId: orgID, Uid: uid}
+	if err := service.folders.GetDashboard(ctx, query); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return fmt.Errorf("%w: the folder '%s' does not exist in organization %d", ErrValidation, uid, orgID)
+		}
+		return fmt.Errorf("failed to get the folder '%s': %w", uid, err)
+	}
+	if !query.Result.IsFolder {
+		return fmt.Errorf("%w: the folder '%s' does not exist in organization %d", ErrValidation, uid, orgID)
+	}
+	return nil
+}
+
+// remapDatasource replaces the datasource of a query with the one it is mapped to, in the query and in its model.
+// Expressions are not remapped.
+func remapDatasource(q *models.AlertQuery, datasourceUIDs map[string]string) error {
+	if isExpression, _ := q.IsExpression(); isExpression {
+		return nil
+	}
+	uid, ok := datasourceUIDs[q.DatasourceUID]
+	if !ok {
+		return nil
+	}
+	var model struct {
+		Datasource *struct {
+			UID string `json:"uid"`
+		} `json:"datasource"`
+	}
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return fmt.Errorf("failed to unmarshal query model: %w", err)
+	}
+	if model.Datasource != nil && model.Datasource.UID == q.DatasourceUID {
+		if err := q.SetModelValue("datasource.uid", uid); err != nil {
+			return err
+		}
+	}
+	q.DatasourceUID = uid
+	return nil
+}
+
 func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64, folder, group string) (definitions.AlertRuleGroup, error) {
 	q := models.ListAlertRulesQuery{
 		OrgID:         orgID,
@@ -106,6 +466,7 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 		Title:     q.Result[0].RuleGroup,
 		FolderUID: q.Result[0].NamespaceUID,
 		Interval:  q.Result[0].IntervalSeconds,
+		Labels:    commonGroupLabels(q.Result),
 		Rules:     []models.AlertRule{},
 	}
 	for _, r := range q.Result {
@@ -116,11 +477,150 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 	return res, nil
 }
 
-// UpdateRuleGroup will update the interval for all rules in the group.
-func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, interval int64) error {
-	if err := models.ValidateRuleGroupInterval(interval, service.baseIntervalSeconds); err != nil {
+// GetRuleGroupWithVersion returns the rule group and its version, which can be given to the methods that change
+// the group to make sure it was not changed in the meantime.
+func (service *AlertRuleService) GetRuleGroupWithVersion(ctx context.Context, orgID int64, folder, group string) (definitions.AlertRuleGroup, string, error) {
+	res, err := service.GetRuleGroup(ctx, orgID, folder, group)
+	if err != nil {
+		return definitions.AlertRuleGroup{}, "", err
+	}
+	rules := make([]*models.AlertRule, 0, len(res.Rules))
+	for i := range res.Rules {
+		rules = append(rules, &res.Rules[i])
+	}
+	return res, ruleGroupVersion(rules), nil
+}
+
+// ruleGroupVersion returns a hash of the UIDs and versions of the rules of a group, which changes whenever a rule
+// of the group is changed, added or removed.
+func ruleGroupVersion(rules []*models.AlertRule) string {
+	keys := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		keys = append(keys, fmt.Sprintf("%s:%d", rule.UID, rule.Version))
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		_, _ = h.Write([]byte(k))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:40]
+}
+
+// checkRuleGroupVersion returns ErrVersionConflict if the version is set and is not the version of the rules.
+func checkRuleGroupVersion(rules []*models.AlertRule, version string) error {
+	if version != "" && version != ruleGroupVersion(rules) {
+		return fmt.Errorf("%w: the rule group was changed since it was read", ErrVersionConflict)
+	}
+	return nil
+}
+
+// ExportRuleGroups returns all rule groups within the specified folder as a file provisioning document, so they
+// can be managed as files instead. The labels of the groups are exported with the groups rather than with every
+// rule.
+func (service *AlertRuleService) ExportRuleGroups(ctx context.Context, orgID int64, folderUID string) (definitions.AlertRulesExport, error) {
+	q := models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{folderUID},
+	}
+	if err := service.ruleStore.ListAlertRules(ctx, &q); err != nil {
+		return definitions.AlertRulesExport{}, err
+	}
+
+	groups := make(map[string][]*models.AlertRule)
+	for _, r := range q.Result {
+		if r != nil {
+			groups[r.RuleGroup] = append(groups[r.RuleGroup], r)
+		}
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	export := definitions.AlertRulesExport{
+		APIVersion: fileProvisioningAPIVersion,
+		Groups:     make([]definitions.AlertRuleGroupExport, 0, len(names)),
+	}
+	for _, name := range names {
+		rules := groups[name]
+		sort.SliceStable(rules, func(i, j int) bool {
+			return rules[i].RuleGroupIndex < rules[j].RuleGroupIndex
+		})
+		group := definitions.AlertRuleGroupExport{
+			OrgID:     orgID,
+			Name:      name,
+			FolderUID: folderUID,
+			Interval:  prommodel.Duration(time.Duration(rules[0].IntervalSeconds) * time.Second),
+			Labels:    commonGroupLabels(rules),
+			Rules:     make([]definitions.AlertRuleExport, 0, len(rules)),
+		}
+		for _, r := range rules {
+			rule, err := exportAlertRule(*r)
+			if err != nil {
+				return definitions.AlertRulesExport{}, err
+			}
+			rule.Labels = ruleOwnLabels(rule.Labels, group.Labels)
+			group.Rules = append(group.Rules, rule)
+		}
+		export.Groups = append(export.Groups, group)
+	}
+	return export, nil
+}
+
+func exportAlertRule(rule models.AlertRule) (definitions.AlertRuleExport, error) {
+	data := make([]definitions.AlertQueryExport, 0, len(rule.Data))
+	for _, q := range rule.Data {
+		var queryModel map[string]interface{}
+		if err := json.Unmarshal(q.Model, &queryModel); err != nil {
+			return definitions.AlertRuleExport{}, fmt.Errorf("failed to unmarshal model of query '%s' of alert rule '%s': %w", q.RefID, rule.UID, err)
+		}
+		data = append(data, definitions.AlertQueryExport{
+			RefID:             q.RefID,
+			QueryType:         q.QueryType,
+			RelativeTimeRange: q.RelativeTimeRange,
+			DatasourceUID:     q.DatasourceUID,
+			Model:             queryModel,
+		})
+	}
+	export := definitions.AlertRuleExport{
+		UID:          rule.UID,
+		Title:        rule.Title,
+		Condition:    rule.Condition,
+		Data:         data,
+		NoDataState:  rule.NoDataState,
+		ExecErrState: rule.ExecErrState,
+		For:          prommodel.Duration(rule.For),
+		Annotations:  rule.Annotations,
+		Labels:       rule.Labels,
+		Webhooks:     rule.Webhooks,
+		IsPaused:     rule.IsPaused,
+		MaxInstances: rule.MaxInstances,
+	}
+	if rule.DashboardUID != nil {
+		export.DashboardUID = *rule.DashboardUID
+	}
+	if rule.PanelID != nil {
+		export.PanelID = *rule.PanelID
+	}
+	return export, nil
+}
+
+// UpdateRuleGroup will update the interval and the labels for all rules in the group. The labels replace the previous
+// labels of the group in the labels of the rules. If version is set, the group is only updated if it was not changed
+// since the version was read with GetRuleGroupWithVersion, otherwise ErrVersionConflict is returned.
+func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, interval int64, labels map[string]string, version string) error {
+	if err := service.validateRuleGroupInterval(interval); err != nil {
 		return err
 	}
+	policy, err := service.folderPolicies.GetFolderPolicy(ctx, orgID, namespaceUID)
+	if err != nil {
+		return err
+	}
+	if interval < policy.MinIntervalSeconds {
+		return fmt.Errorf("%w: the folder requires an interval of at least %ds", ErrValidation, policy.MinIntervalSeconds)
+	}
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		query := &models.ListAlertRulesQuery{
 			OrgID:         orgID,
@@ -131,13 +631,17 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int6
 		if err != nil {
 			return fmt.Errorf("failed to list alert rules: %w", err)
 		}
+		if err := checkRuleGroupVersion(query.Result, version); err != nil {
+			return err
+		}
 		updateRules := make([]store.UpdateRule, 0, len(query.Result))
 		for _, rule := range query.Result {
-			if rule.IntervalSeconds == interval {
-				continue
-			}
 			newRule := *rule
 			newRule.IntervalSeconds = interval
+			setGroupLabels(&newRule, labels)
+			if rule.IntervalSeconds == interval && labelsEqual(rule.Labels, newRule.Labels) && labelsEqual(rule.GroupLabels, newRule.GroupLabels) {
+				continue
+			}
 			updateRules = append(updateRules, store.UpdateRule{
 				Existing: rule,
 				New:      newRule,
@@ -147,6 +651,216 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int6
 	})
 }
 
+// validateRuleGroupInterval checks that the interval of a rule group is a multiple of the base interval of the
+// scheduler and is not below the minimum interval set by 'min_interval'. The scheduler would otherwise evaluate the
+// rules at another interval than the one that was provisioned, so the error suggests the nearest valid interval.
+func (service *AlertRuleService) validateRuleGroupInterval(interval int64) error {
+	if err := models.ValidateRuleGroupInterval(interval, service.baseIntervalSeconds); err != nil {
+		return fmt.Errorf("%w, the nearest valid interval is %ds", err, service.nearestValidInterval(interval))
+	}
+	if interval < service.minIntervalSeconds {
+		return fmt.Errorf("%w: interval (%ds) is lower than the minimum interval (%ds), the nearest valid interval is %ds",
+			models.ErrAlertRuleFailedValidation, interval, service.minIntervalSeconds, service.nearestValidInterval(interval))
+	}
+	return nil
+}
+
+// nearestValidInterval returns the multiple of the base interval that is closest to the interval and not below the
+// minimum interval.
+func (service *AlertRuleService) nearestValidInterval(interval int64) int64 {
+	nearest := service.baseIntervalSeconds
+	if interval > 0 {
+		nearest = (interval + service.baseIntervalSeconds/2) / service.baseIntervalSeconds * service.baseIntervalSeconds
+	}
+	if nearest < service.baseIntervalSeconds {
+		nearest = service.baseIntervalSeconds
+	}
+	if nearest < service.minIntervalSeconds {
+		nearest = service.minIntervalSeconds
+	}
+	return nearest
+}
+
+// ImportRuleGroups creates or updates many rule groups of an organization in a single transaction. Rules are matched
+// to the existing rules of the organization by UID, rules without UID or with an unknown UID are created. The labels
+// of the groups are merged into the labels of their rules. The other rules of the imported groups are kept and get the
+// interval and the labels of their group.
+//
+// All groups and rules are validated before anything is stored and the invalid ones are reported at once by a
+// RuleGroupsValidationError. The alert rules quota and the limits on the alert rules are checked once for all the
+// imported rules. The datasources referenced by the queries of the rules are validated like in CreateAlertRule.
+//
+// If deterministicUIDs is true, the rules without UID get a UID derived from their folder, group and title instead of
+// a random one, so that importing the same groups again updates the rules instead of creating them again.
+func (service *AlertRuleService) ImportRuleGroups(ctx context.Context, orgID int64, groups []definitions.AlertRuleGroup, provenance models.Provenance, deterministicUIDs bool) error {
+	query := &models.ListAlertRulesQuery{OrgID: orgID}
+	if err := service.ruleStore.ListAlertRules(ctx, query); err != nil {
+		return fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	existing := make(map[string]*models.AlertRule, len(query.Result))
+	for _, rule := range query.Result {
+		existing[rule.UID] = rule
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var failures []error
+	inserts := make([]models.AlertRule, 0)
+	updates := make([]store.UpdateRule, 0)
+	importedRules := map[string]struct{}{}
+	importedTitles := map[string]struct{}{}
+	importedGroups := map[models.AlertRuleGroupKey]definitions.AlertRuleGroup{}
+	for _, group := range groups {
+		key := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: group.FolderUID, RuleGroup: group.Title}
+		if _, ok := importedGroups[key]; ok {
+			failures = append(failures, fmt.Errorf("folder '%s', group '%s': the group is imported more than once", group.FolderUID, group.Title))
+			continue
+		}
+		importedGroups[key] = group
+		if err := service.validateRuleGroupInterval(group.Interval); err != nil {
+			failures = append(failures, fmt.Errorf("folder '%s', group '%s': %w", group.FolderUID, group.Title, err))
+			continue
+		}
+		for i, rule := range group.Rules {
+			rule.OrgID = orgID
+			rule.NamespaceUID = group.FolderUID
+			rule.RuleGroup = group.Title
+			rule.RuleGroupIndex = i + 1
+			rule.IntervalSeconds = group.Interval
+			rule.Updated = now
+			setGroupLabels(&rule, group.Labels)
+
+			fail := func(err error) {
+				failures = append(failures, fmt.Errorf("folder '%s', group '%s', rule '%s': %w", group.FolderUID, group.Title, rule.Title, err))
+			}
+			if err := store.ValidateAlertRule(rule, service.baseIntervalSeconds); err != nil {
+				fail(err)
+				continue
+			}
+			violations, err := service.folderPolicies.CheckFolderPolicy(ctx, orgID, &rule)
+			if err != nil {
+				return err
+			}
+			if len(violations) > 0 {
+				for _, v := range violations {
+					fail(fmt.Errorf("%w: %s", models.ErrAlertRuleFailedValidation, v.Message))
+				}
+				continue
+			}
+			if err := service.checkDatasources(ctx, orgID, rule); err != nil {
+				if !errors.Is(err, ErrValidation) {
+					return err
+				}
+				fail(err)
+				continue
+			}
+			if _, ok := importedTitles[rule.NamespaceUID+"/"+rule.Title]; ok {
+				fail(fmt.Errorf("%w: the title is used by another imported rule of the folder", models.ErrAlertRuleFailedValidation))
+				continue
+			}
+			importedTitles[rule.NamespaceUID+"/"+rule.Title] = struct{}{}
+			if rule.UID == "" && deterministicUIDs {
+				rule.UID = deterministicRuleUID(rule.NamespaceUID, rule.RuleGroup, rule.Title)
+			}
+			if rule.UID != "" {
+				if _, ok := importedRules[rule.UID]; ok {
+					fail(fmt.Errorf("%w: the UID '%s' is used by another imported rule", models.ErrAlertRuleFailedValidation, rule.UID))
+					continue
+				}
+				importedRules[rule.UID] = struct{}{}
+			}
+
+			stored, ok := existing[rule.UID]
+			if !ok {
+				if rule.UID == "" {
+					rule.UID = util.GenerateShortUID()
+				}
+				inserts = append(inserts, rule)
+				continue
+			}
+			if storedProvenance := provenances[rule.UID]; storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+				fail(fmt.Errorf("cannot change provenance from '%s' to '%s'", storedProvenance, provenance))
+				continue
+			}
+			updates = append(updates, store.UpdateRule{Existing: stored, New: rule})
+		}
+	}
+	if len(failures) > 0 {
+		return RuleGroupsValidationError{Errors: failures}
+	}
+
+	changed := make([]models.AlertRule, 0, len(inserts)+len(updates))
+	for _, update := range updates {
+		changed = append(changed, update.New)
+	}
+	for _, rule := range query.Result {
+		group, ok := importedGroups[rule.GetGroupKey()]
+		if _, imported := importedRules[rule.UID]; !ok || imported {
+			continue
+		}
+		newRule := *rule
+		newRule.IntervalSeconds = group.Interval
+		setGroupLabels(&newRule, group.Labels)
+		if rule.IntervalSeconds == group.Interval && labelsEqual(rule.Labels, newRule.Labels) && labelsEqual(rule.GroupLabels, newRule.GroupLabels) {
+			continue
+		}
+		updates = append(updates, store.UpdateRule{Existing: rule, New: newRule})
+	}
+
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.checkRuleLimits(ctx, orgID, append(inserts, changed...)...); err != nil {
+			return err
+		}
+		if len(updates) > 0 {
+			if err := service.ruleStore.UpdateAlertRules(ctx, updates); err != nil {
+				return fmt.Errorf("failed to update rules: %w", err)
+			}
+		}
+		if len(inserts) > 0 {
+			ids, err := service.ruleStore.InsertAlertRules(ctx, inserts)
+			if err != nil {
+				return fmt.Errorf("failed to add rules: %w", err)
+			}
+			for _, rule := range inserts {
+				rule.ID = ids[rule.UID]
+				changed = append(changed, rule)
+			}
+			limitReached, err := service.quotas.CheckQuotaReached(ctx, "alert_rule", &quota.ScopeParameters{
+				OrgId: orgID,
+			}) // alert rule is table name
+			if err != nil {
+				return fmt.Errorf("failed to get alert rules quota: %w", err)
+			}
+			if limitReached {
+				return ErrQuotaReached
+			}
+		}
+		for i := range changed {
+			if provenances[changed[i].UID] == provenance {
+				continue
+			}
+			if err := service.provenanceStore.SetProvenance(ctx, &changed[i], orgID, provenance); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deterministicRuleUID derives the UID of a rule from its folder, group and title. The hash is cut to the maximum
+// length of a UID.
+func deterministicRuleUID(folderUID, group, title string) string {
+	h := sha256.New()
+	for _, s := range []string{folderUID, group, title} {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:40]
+}
+
 // CreateAlertRule creates a new alert rule. This function will ignore any
 // interval that is set in the rule struct and fetch the current group interval
 // from database.
@@ -164,8 +878,20 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	groupLabels, err := service.ruleGroupLabels(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	rule.GroupLabels = storedRule.GroupLabels
+	setGroupLabels(&rule, groupLabels)
+	if err := service.checkDatasources(ctx, rule.OrgID, rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	service.log.Info("update rule", "ID", storedRule.ID, "labels", fmt.Sprintf("%+v", rule.Labels))
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.checkRuleLimits(ctx, rule.OrgID, rule); err != nil {
+			return err
+		}
 		err := service.ruleStore.UpdateAlertRules(ctx, []store.UpdateRule{
 			{
 				Existing: &storedRule,
@@ -183,6 +909,270 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	return rule, err
 }
 
+// SetAlertRulePaused pauses or resumes an alert rule. Unlike UpdateAlertRule, it does not check or change the
+// provenance of the rule, so that rules that are provisioned from files can be paused too.
+func (service *AlertRuleService) SetAlertRulePaused(ctx context.Context, orgID int64, ruleUID string, paused bool) (models.AlertRule, error) {
+	var rule models.AlertRule
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		query := &models.GetAlertRuleByUIDQuery{
+			OrgID: orgID,
+			UID:   ruleUID,
+		}
+		if err := service.ruleStore.GetAlertRuleByUID(ctx, query); err != nil {
+			return err
+		}
+		rule = *query.Result
+		if rule.IsPaused == paused {
+			return nil
+		}
+		rule.IsPaused = paused
+		rule.Updated = time.Now()
+		return service.ruleStore.UpdateAlertRules(ctx, []store.UpdateRule{{Existing: query.Result, New: rule}})
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return rule, nil
+}
+
+// SetAlertRuleProvenance changes the provenance of an alert rule regardless of its current provenance, for example to
+// manage a rule through the API once the file that provisioned it is retired. It is meant for administrators, the
+// other operations refuse to change the provenance of a rule provisioned otherwise.
+func (service *AlertRuleService) SetAlertRuleProvenance(ctx context.Context, orgID int64, ruleUID string, provenance models.Provenance) (models.AlertRule, error) {
+	if err := validateProvenance(provenance); err != nil {
+		return models.AlertRule{}, err
+	}
+
+	var rule models.AlertRule
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		query := &models.GetAlertRuleByUIDQuery{
+			OrgID: orgID,
+			UID:   ruleUID,
+		}
+		if err := service.ruleStore.GetAlertRuleByUID(ctx, query); err != nil {
+			return err
+		}
+		rule = *query.Result
+		storedProvenance, err := service.provenanceStore.GetProvenance(ctx, &rule, orgID)
+		if err != nil {
+			return err
+		}
+		if storedProvenance == provenance {
+			return nil
+		}
+		service.log.Info("changing the provenance of alert rule", "orgID", orgID, "ruleUID", ruleUID, "from", storedProvenance, "to", provenance)
+		if provenance == models.ProvenanceNone {
+			return service.provenanceStore.DeleteProvenance(ctx, &rule, orgID)
+		}
+		return service.provenanceStore.SetProvenance(ctx, &rule, orgID, provenance)
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return rule, nil
+}
+
+// SetAlertRulesProvenance changes the provenance of many alert rules like SetAlertRuleProvenance, in a single
+// transaction. Nothing is changed if one of the rules does not exist.
+func (service *AlertRuleService) SetAlertRulesProvenance(ctx context.Context, orgID int64, ruleUIDs []string, provenance models.Provenance) (definitions.AlertRulesProvenanceResult, error) {
+	if err := validateProvenance(provenance); err != nil {
+		return definitions.AlertRulesProvenanceResult{}, err
+	}
+	if len(ruleUIDs) == 0 {
+		return definitions.AlertRulesProvenanceResult{}, fmt.Errorf("%w: the UIDs of the rules are required", ErrValidation)
+	}
+
+	result := definitions.AlertRulesProvenanceResult{
+		Provenance: provenance,
+		Changed:    []string{},
+		Unchanged:  []string{},
+	}
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		query := &models.ListAlertRulesQuery{OrgID: orgID}
+		if err := service.ruleStore.ListAlertRules(ctx, query); err != nil {
+			return err
+		}
+		rules := make(map[string]*models.AlertRule, len(query.Result))
+		for _, rule := range query.Result {
+			rules[rule.UID] = rule
+		}
+		provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+
+		var missing []string
+		changed := make([]*models.AlertRule, 0, len(ruleUIDs))
+		seen := make(map[string]struct{}, len(ruleUIDs))
+		for _, uid := range ruleUIDs {
+			if _, ok := seen[uid]; ok {
+				continue
+			}
+			seen[uid] = struct{}{}
+			rule, ok := rules[uid]
+			switch {
+			case !ok:
+				missing = append(missing, uid)
+			case provenances[uid] == provenance:
+				result.Unchanged = append(result.Unchanged, uid)
+			default:
+				result.Changed = append(result.Changed, uid)
+				changed = append(changed, rule)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%w: the alert rules %s do not exist", ErrValidation, strings.Join(missing, ", "))
+		}
+		for _, rule := range changed {
+			if provenance == models.ProvenanceNone {
+				err = service.provenanceStore.DeleteProvenance(ctx, rule, orgID)
+			} else {
+				err = service.provenanceStore.SetProvenance(ctx, rule, orgID, provenance)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return definitions.AlertRulesProvenanceResult{}, err
+	}
+	service.log.Info("changed the provenance of alert rules", "orgID", orgID, "provenance", provenance, "changed", len(result.Changed), "unchanged", len(result.Unchanged))
+	return result, nil
+}
+
+func validateProvenance(provenance models.Provenance) error {
+	switch provenance {
+	case models.ProvenanceNone, models.ProvenanceAPI, models.ProvenanceFile:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown provenance '%s'", ErrValidation, provenance)
+	}
+}
+
+// SetRuleGroupPaused pauses or resumes all alert rules of a rule group without checking or changing their provenance.
+// The version is checked like in UpdateRuleGroup.
+func (service *AlertRuleService) SetRuleGroupPaused(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, paused bool, version string) error {
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		query := &models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{namespaceUID},
+			RuleGroup:     ruleGroup,
+		}
+		if err := service.ruleStore.ListAlertRules(ctx, query); err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		if len(query.Result) == 0 {
+			return store.ErrAlertRuleGroupNotFound
+		}
+		if err := checkRuleGroupVersion(query.Result, version); err != nil {
+			return err
+		}
+		updated := time.Now()
+		updateRules := make([]store.UpdateRule, 0, len(query.Result))
+		for _, rule := range query.Result {
+			if rule.IsPaused == paused {
+				continue
+			}
+			newRule := *rule
+			newRule.IsPaused = paused
+			newRule.Updated = updated
+			updateRules = append(updateRules, store.UpdateRule{
+				Existing: rule,
+				New:      newRule,
+			})
+		}
+		return service.ruleStore.UpdateAlertRules(ctx, updateRules)
+	})
+}
+
+// SetRuleGroupOrder sets the order of the rules of a rule group to the order of the given UIDs, which must be the UIDs
+// of all the rules of the group. Only the rules whose position changes are updated. The version is checked like in
+// UpdateRuleGroup.
+func (service *AlertRuleService) SetRuleGroupOrder(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, ruleUIDs []string, version string) error {
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		query := &models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{namespaceUID},
+			RuleGroup:     ruleGroup,
+		}
+		if err := service.ruleStore.ListAlertRules(ctx, query); err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		if len(query.Result) == 0 {
+			return store.ErrAlertRuleGroupNotFound
+		}
+		if err := checkRuleGroupVersion(query.Result, version); err != nil {
+			return err
+		}
+		rules := make(map[string]*models.AlertRule, len(query.Result))
+		for _, rule := range query.Result {
+			rules[rule.UID] = rule
+		}
+		positions := make(map[string]int, len(ruleUIDs))
+		for i, uid := range ruleUIDs {
+			if _, ok := rules[uid]; !ok {
+				return fmt.Errorf("%w: rule '%s' is not in the group", ErrValidation, uid)
+			}
+			if _, ok := positions[uid]; ok {
+				return fmt.Errorf("%w: rule '%s' is listed more than once", ErrValidation, uid)
+			}
+			positions[uid] = i + 1
+		}
+		if len(positions) != len(rules) {
+			return fmt.Errorf("%w: the UIDs of all %d rules of the group are required, got %d", ErrValidation, len(rules), len(positions))
+		}
+
+		updated := time.Now()
+		updateRules := make([]store.UpdateRule, 0, len(query.Result))
+		for _, rule := range query.Result {
+			if rule.RuleGroupIndex == positions[rule.UID] {
+				continue
+			}
+			newRule := *rule
+			newRule.RuleGroupIndex = positions[rule.UID]
+			newRule.Updated = updated
+			updateRules = append(updateRules, store.UpdateRule{
+				Existing: rule,
+				New:      newRule,
+			})
+		}
+		return service.ruleStore.UpdateAlertRules(ctx, updateRules)
+	})
+}
+
+// checkRuleLimits returns a RuleLimitError if the alert rules of the organization exceed one of its limits once the
+// given rules are created or updated, and counts the exceeded limit in the metrics.
+func (service *AlertRuleService) checkRuleLimits(ctx context.Context, orgID int64, rules ...models.AlertRule) error {
+	limits := service.settings.RuleLimitsForOrg(orgID)
+	if limits.MaxRules <= 0 && limits.MaxRulesPerGroup <= 0 && limits.MaxGroupsPerFolder <= 0 {
+		return nil
+	}
+	query := &models.ListAlertRulesQuery{OrgID: orgID}
+	if err := service.ruleStore.ListAlertRules(ctx, query); err != nil {
+		return fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	changed := make(map[string]struct{}, len(rules))
+	after := make([]*models.AlertRule, 0, len(query.Result)+len(rules))
+	for i := range rules {
+		changed[rules[i].UID] = struct{}{}
+		after = append(after, &rules[i])
+	}
+	for _, rule := range query.Result {
+		if _, ok := changed[rule.UID]; !ok {
+			after = append(after, rule)
+		}
+	}
+
+	err := CheckRuleLimits(query.Result, after, limits)
+	var limitErr RuleLimitError
+	if errors.As(err, &limitErr) && service.metrics != nil {
+		service.metrics.RuleLimitExceeded.WithLabelValues(fmt.Sprint(orgID), limitErr.Limit).Inc()
+	}
+	return err
+}
+
 func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance models.Provenance) error {
 	rule := &models.AlertRule{
 		OrgID: orgID,
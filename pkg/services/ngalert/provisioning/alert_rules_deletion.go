@@ -0,0 +1,189 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	prommodel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// DeleteRuleGroup deletes the rules of a rule group, and returns the alerts of the rules that are not normal and the
+// silences that match the alerts or the rules. The state of the rules is dropped with them, so unless force is set
+// the group is not deleted if there are any, and a RuleGroupInUseError is returned instead. The version is checked
+// like in UpdateRuleGroup.
+func (service *AlertRuleService) DeleteRuleGroup(ctx context.Context, orgID int64, folderUID, ruleGroup string, provenance models.Provenance, force bool, version string) (definitions.RuleGroupDeletion, error) {
+	query := &models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{folderUID},
+		RuleGroup:     ruleGroup,
+	}
+	if err := service.ruleStore.ListAlertRules(ctx, query); err != nil {
+		return definitions.RuleGroupDeletion{}, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	if len(query.Result) == 0 {
+		return definitions.RuleGroupDeletion{}, store.ErrAlertRuleGroupNotFound
+	}
+	if err := checkRuleGroupVersion(query.Result, version); err != nil {
+		return definitions.RuleGroupDeletion{}, err
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return definitions.RuleGroupDeletion{}, err
+	}
+	for _, rule := range query.Result {
+		// check that provenance is not changed in a invalid way
+		if stored := provenances[rule.UID]; stored != provenance && stored != models.ProvenanceNone {
+			return definitions.RuleGroupDeletion{}, fmt.Errorf("%w: cannot delete rule '%s' with provided provenance '%s', needs '%s'", ErrValidation, rule.UID, provenance, stored)
+		}
+	}
+
+	deletion, err := service.ruleGroupDeletion(ctx, orgID, query.Result)
+	if err != nil {
+		return definitions.RuleGroupDeletion{}, err
+	}
+	if !force && (len(deletion.Alerts) > 0 || len(deletion.Silences) > 0) {
+		return deletion, RuleGroupInUseError{Deletion: deletion}
+	}
+
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.ruleStore.DeleteAlertRulesByUID(ctx, orgID, deletion.RuleUIDs...); err != nil {
+			return err
+		}
+		for _, rule := range query.Result {
+			if err := service.provenanceStore.DeleteProvenance(ctx, rule, orgID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return definitions.RuleGroupDeletion{}, err
+	}
+	deletion.Deleted = true
+	return deletion, nil
+}
+
+// ruleGroupDeletion returns the alerts of the rules that are not normal and the silences that are not expired and
+// match any alert of the rules, normal or not, or the labels that every alert of a rule has.
+func (service *AlertRuleService) ruleGroupDeletion(ctx context.Context, orgID int64, rules []*models.AlertRule) (definitions.RuleGroupDeletion, error) {
+	deletion := definitions.RuleGroupDeletion{
+		RuleUIDs: make([]string, 0, len(rules)),
+		Alerts:   []definitions.RuleGroupDeletionAlert{},
+		Silences: []definitions.RuleGroupDeletionSilence{},
+	}
+	// labelSets are the label sets of the alerts of every rule.
+	labelSets := make(map[string][]prommodel.LabelSet, len(rules))
+	for _, rule := range rules {
+		deletion.RuleUIDs = append(deletion.RuleUIDs, rule.UID)
+		ruleLabels := prommodel.LabelSet{
+			models.RuleUIDLabel:      prommodel.LabelValue(rule.UID),
+			models.NamespaceUIDLabel: prommodel.LabelValue(rule.NamespaceUID),
+			prommodel.AlertNameLabel: prommodel.LabelValue(rule.Title),
+		}
+		for k, v := range rule.Labels {
+			ruleLabels[prommodel.LabelName(k)] = prommodel.LabelValue(v)
+		}
+		labelSets[rule.UID] = append(labelSets[rule.UID], ruleLabels)
+
+		instances := &models.ListAlertInstancesQuery{RuleOrgID: orgID, RuleUID: rule.UID}
+		if err := service.instances.ListAlertInstances(ctx, instances); err != nil {
+			return definitions.RuleGroupDeletion{}, fmt.Errorf("failed to list the alerts of rule '%s': %w", rule.UID, err)
+		}
+		for _, instance := range instances.Result {
+			alertLabels := make(prommodel.LabelSet, len(instance.Labels))
+			for k, v := range instance.Labels {
+				alertLabels[prommodel.LabelName(k)] = prommodel.LabelValue(v)
+			}
+			labelSets[rule.UID] = append(labelSets[rule.UID], alertLabels)
+			if instance.CurrentState == models.InstanceStateNormal {
+				continue
+			}
+			deletion.Alerts = append(deletion.Alerts, definitions.RuleGroupDeletionAlert{
+				RuleUID: rule.UID,
+				Labels:  instance.Labels,
+				State:   string(instance.CurrentState),
+				Since:   instance.CurrentStateSince,
+			})
+		}
+	}
+
+	silences, err := service.silences.ListSilences(ctx, orgID)
+	if err != nil {
+		return definitions.RuleGroupDeletion{}, fmt.Errorf("failed to list silences: %w", err)
+	}
+	for _, silence := range silences {
+		if silence.ID == nil || silence.Status == nil || silence.Status.State == nil || *silence.Status.State == "expired" {
+			continue
+		}
+		matchers, err := silenceMatchers(silence)
+		if err != nil {
+			service.log.Warn("failed to parse the matchers of a silence", "silenceId", *silence.ID, "err", err)
+			continue
+		}
+		var ruleUIDs []string
+		for _, rule := range rules {
+			for _, ls := range labelSets[rule.UID] {
+				if matchers.Matches(ls) {
+					ruleUIDs = append(ruleUIDs, rule.UID)
+					break
+				}
+			}
+		}
+		if len(ruleUIDs) == 0 {
+			continue
+		}
+		s := definitions.RuleGroupDeletionSilence{
+			ID:       *silence.ID,
+			State:    *silence.Status.State,
+			RuleUIDs: ruleUIDs,
+		}
+		if silence.Comment != nil {
+			s.Comment = *silence.Comment
+		}
+		if silence.CreatedBy != nil {
+			s.CreatedBy = *silence.CreatedBy
+		}
+		if silence.EndsAt != nil {
+			s.EndsAt = time.Time(*silence.EndsAt)
+		}
+		deletion.Silences = append(deletion.Silences, s)
+	}
+	sort.Slice(deletion.Silences, func(i, j int) bool {
+		return deletion.Silences[i].ID < deletion.Silences[j].ID
+	})
+	return deletion, nil
+}
+
+// silenceMatchers returns the matchers of a silence.
+func silenceMatchers(silence *definitions.GettableSilence) (labels.Matchers, error) {
+	matchers := make(labels.Matchers, 0, len(silence.Matchers))
+	for _, m := range silence.Matchers {
+		if m.Name == nil || m.Value == nil {
+			return nil, fmt.Errorf("matcher without name or value")
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		isRegex := m.IsRegex != nil && *m.IsRegex
+		t := labels.MatchEqual
+		switch {
+		case isRegex && isEqual:
+			t = labels.MatchRegexp
+		case isRegex:
+			t = labels.MatchNotRegexp
+		case !isEqual:
+			t = labels.MatchNotEqual
+		}
+		matcher, err := labels.NewMatcher(t, *m.Name, *m.Value)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
@@ -0,0 +1,141 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestDeleteRuleGroup(t *testing.T) {
+	ctx := context.Background()
+	var orgID int64 = 1
+
+	t.Run("should delete groups without alerts or silences", func(t *testing.T) {
+		sut := createAlertRuleService(t)
+		rule, err := sut.CreateAlertRule(ctx, dummyRule("rule", orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		deletion, err := sut.DeleteRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, models.ProvenanceAPI, false, "")
+
+		require.NoError(t, err)
+		require.True(t, deletion.Deleted)
+		require.Equal(t, []string{rule.UID}, deletion.RuleUIDs)
+		_, err = sut.GetRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("should not delete groups with alerts or silences unless forced", func(t *testing.T) {
+		sut := createAlertRuleService(t)
+		firing, err := sut.CreateAlertRule(ctx, dummyRule("firing", orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+		silenced, err := sut.CreateAlertRule(ctx, dummyRule("silenced", orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+		since := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+		for uid, state := range map[string]models.InstanceStateType{firing.UID: models.InstanceStateFiring, silenced.UID: models.InstanceStateNormal} {
+			err := sut.instances.(store.DBstore).SaveAlertInstance(ctx, &models.SaveAlertInstanceCommand{
+				RuleOrgID:         orgID,
+				RuleUID:           uid,
+				Labels:            models.InstanceLabels{"instance": uid},
+				State:             state,
+				CurrentStateSince: since,
+			})
+			require.NoError(t, err)
+		}
+		sut.silences = fakeSilenceReader{orgID: {
+			dummySilence("active", "active", "alertname", "silenced"),
+			dummySilence("expired", "expired", "alertname", "firing"),
+			dummySilence("other", "pending", "alertname", "other"),
+		}}
+
+		deletion, err := sut.DeleteRuleGroup(ctx, orgID, firing.NamespaceUID, firing.RuleGroup, models.ProvenanceAPI, false, "")
+
+		var inUse RuleGroupInUseError
+		require.ErrorAs(t, err, &inUse)
+		require.Equal(t, deletion, inUse.Deletion)
+		require.False(t, deletion.Deleted)
+		require.Equal(t, []definitions.RuleGroupDeletionAlert{{
+			RuleUID: firing.UID,
+			Labels:  map[string]string{"instance": firing.UID},
+			State:   string(models.InstanceStateFiring),
+			Since:   since,
+		}}, deletion.Alerts)
+		require.Len(t, deletion.Silences, 1)
+		require.Equal(t, "active", deletion.Silences[0].ID)
+		require.Equal(t, []string{silenced.UID}, deletion.Silences[0].RuleUIDs)
+		_, err = sut.GetRuleGroup(ctx, orgID, firing.NamespaceUID, firing.RuleGroup)
+		require.NoError(t, err)
+
+		deletion, err = sut.DeleteRuleGroup(ctx, orgID, firing.NamespaceUID, firing.RuleGroup, models.ProvenanceAPI, true, "")
+
+		require.NoError(t, err)
+		require.True(t, deletion.Deleted)
+		require.Len(t, deletion.Alerts, 1)
+		require.Len(t, deletion.Silences, 1)
+		_, err = sut.GetRuleGroup(ctx, orgID, firing.NamespaceUID, firing.RuleGroup)
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("should match silences with the labels of the alerts", func(t *testing.T) {
+		sut := createAlertRuleService(t)
+		rule, err := sut.CreateAlertRule(ctx, dummyRule("rule", orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+		err = sut.instances.(store.DBstore).SaveAlertInstance(ctx, &models.SaveAlertInstanceCommand{
+			RuleOrgID: orgID,
+			RuleUID:   rule.UID,
+			Labels:    models.InstanceLabels{"instance": "server-1"},
+			State:     models.InstanceStateNormal,
+		})
+		require.NoError(t, err)
+		sut.silences = fakeSilenceReader{orgID: {dummySilence("server", "active", "instance", "server-1")}}
+
+		deletion, err := sut.DeleteRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, models.ProvenanceAPI, false, "")
+
+		require.ErrorAs(t, err, &RuleGroupInUseError{})
+		require.Empty(t, deletion.Alerts)
+		require.Len(t, deletion.Silences, 1)
+	})
+
+	t.Run("should return ErrAlertRuleGroupNotFound for missing groups", func(t *testing.T) {
+		sut := createAlertRuleService(t)
+
+		_, err := sut.DeleteRuleGroup(ctx, orgID, "folder", "missing", models.ProvenanceAPI, false, "")
+
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("should not delete rules with another provenance", func(t *testing.T) {
+		sut := createAlertRuleService(t)
+		rule, err := sut.CreateAlertRule(ctx, dummyRule("rule", orgID), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = sut.DeleteRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, models.ProvenanceAPI, true, "")
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func dummySilence(id, state, label, value string) *definitions.GettableSilence {
+	isEqual, isRegex := true, false
+	comment, createdBy := "maintenance", "admin"
+	endsAt := strfmt.DateTime(time.Now().Add(time.Hour))
+	return &definitions.GettableSilence{
+		ID:     &id,
+		Status: &amv2.SilenceStatus{State: &state},
+		Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			EndsAt:    &endsAt,
+			Matchers: amv2.Matchers{
+				{Name: &label, Value: &value, IsEqual: &isEqual, IsRegex: &isRegex},
+			},
+		},
+	}
+}
@@ -0,0 +1,174 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// driftIgnoredFields are the fields of the stored rules that are not declared by file provisioning documents. The
+// order of the rules in their group is not compared either, since the rules created one by one have no index.
+var driftIgnoredFields = []string{"ID", "Version", "Updated", "RuleGroupIndex"}
+
+// DetectRuleDrift compares the rule groups of a file provisioning document with the stored rules of an organization.
+// It reports the rules of the document that are not stored or are stored with another definition or provenance, and
+// the rules that are provisioned from files into the groups of the document but are not declared by it. The groups
+// of other organizations are ignored.
+func (service *AlertRuleService) DetectRuleDrift(ctx context.Context, orgID int64, document definitions.AlertRulesExport) (definitions.AlertRulesDrift, error) {
+	query := &models.ListAlertRulesQuery{OrgID: orgID}
+	if err := service.ruleStore.ListAlertRules(ctx, query); err != nil {
+		return definitions.AlertRulesDrift{}, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	stored := make(map[string]*models.AlertRule, len(query.Result))
+	for _, rule := range query.Result {
+		stored[rule.UID] = rule
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return definitions.AlertRulesDrift{}, err
+	}
+
+	drift := definitions.AlertRulesDrift{Rules: []definitions.AlertRuleDrift{}}
+	declared := map[string]struct{}{}
+	groups := map[models.AlertRuleGroupKey]struct{}{}
+	for _, group := range document.Groups {
+		if group.OrgID != 0 && group.OrgID != orgID {
+			continue
+		}
+		groups[models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: group.FolderUID, RuleGroup: group.Name}] = struct{}{}
+		for _, r := range group.Rules {
+			if r.UID == "" {
+				return definitions.AlertRulesDrift{}, fmt.Errorf("%w: folder '%s', group '%s', rule '%s': the rule has no UID", ErrValidation, group.FolderUID, group.Name, r.Title)
+			}
+			declared[r.UID] = struct{}{}
+			item := definitions.AlertRuleDrift{UID: r.UID, Title: r.Title, FolderUID: group.FolderUID, RuleGroup: group.Name}
+			existing, ok := stored[r.UID]
+			if !ok {
+				item.Status = definitions.AlertRuleDriftMissing
+				drift.Rules = append(drift.Rules, item)
+				continue
+			}
+
+			rule, err := importAlertRule(r)
+			if err != nil {
+				return definitions.AlertRulesDrift{}, fmt.Errorf("%w: folder '%s', group '%s', rule '%s': %s", ErrValidation, group.FolderUID, group.Name, r.Title, err)
+			}
+			rule.OrgID = orgID
+			rule.NamespaceUID = group.FolderUID
+			rule.RuleGroup = group.Name
+			rule.IntervalSeconds = int64(time.Duration(group.Interval).Seconds())
+			rule.Labels = mergeGroupLabels(rule.Labels, group.Labels)
+			if len(group.Labels) > 0 {
+				rule.GroupLabels = group.Labels
+			}
+			for _, d := range existing.Diff(&rule, driftIgnoredFields...) {
+				item.Changes = append(item.Changes, definitions.AlertRuleFieldDrift{
+					Field:  d.Path,
+					File:   driftValue(d.Right),
+					Stored: driftValue(d.Left),
+				})
+			}
+
+			item.Provenance = provenances[r.UID]
+			switch {
+			case item.Provenance != models.ProvenanceFile:
+				item.Status = definitions.AlertRuleDriftProvenance
+			case len(item.Changes) > 0:
+				item.Status = definitions.AlertRuleDriftChanged
+			default:
+				continue
+			}
+			drift.Rules = append(drift.Rules, item)
+		}
+	}
+
+	for _, rule := range query.Result {
+		if _, ok := declared[rule.UID]; ok || provenances[rule.UID] != models.ProvenanceFile {
+			continue
+		}
+		if _, ok := groups[rule.GetGroupKey()]; !ok {
+			continue
+		}
+		drift.Rules = append(drift.Rules, definitions.AlertRuleDrift{
+			UID:        rule.UID,
+			Title:      rule.Title,
+			FolderUID:  rule.NamespaceUID,
+			RuleGroup:  rule.RuleGroup,
+			Status:     definitions.AlertRuleDriftUndeclared,
+			Provenance: models.ProvenanceFile,
+		})
+	}
+	drift.Drifted = len(drift.Rules) > 0
+	return drift, nil
+}
+
+// importAlertRule is the inverse of exportAlertRule. The queries get the same defaults as when they are stored, so
+// that they can be compared with the stored ones.
+func importAlertRule(export definitions.AlertRuleExport) (models.AlertRule, error) {
+	data := make([]models.AlertQuery, 0, len(export.Data))
+	for _, q := range export.Data {
+		model, err := json.Marshal(q.Model)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to marshal model of query '%s': %w", q.RefID, err)
+		}
+		query := models.AlertQuery{
+			RefID:             q.RefID,
+			QueryType:         q.QueryType,
+			RelativeTimeRange: q.RelativeTimeRange,
+			DatasourceUID:     q.DatasourceUID,
+			Model:             model,
+		}
+		if err := query.PreSave(); err != nil {
+			return models.AlertRule{}, fmt.Errorf("invalid query '%s': %w", q.RefID, err)
+		}
+		data = append(data, query)
+	}
+	rule := models.AlertRule{
+		UID:          export.UID,
+		Title:        export.Title,
+		Condition:    export.Condition,
+		Data:         data,
+		NoDataState:  export.NoDataState,
+		ExecErrState: export.ExecErrState,
+		For:          time.Duration(export.For),
+		Annotations:  export.Annotations,
+		Labels:       export.Labels,
+		Webhooks:     export.Webhooks,
+		IsPaused:     export.IsPaused,
+		MaxInstances: export.MaxInstances,
+	}
+	if export.DashboardUID != "" {
+		dashboardUID := export.DashboardUID
+		rule.DashboardUID = &dashboardUID
+	}
+	if export.PanelID != 0 {
+		panelID := export.PanelID
+		rule.PanelID = &panelID
+	}
+	return rule, nil
+}
+
+// driftValue returns the value of a field of a rule in a form that reads like the provisioning documents: durations
+// are formatted and the models of the queries are JSON objects.
+func driftValue(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	switch value := v.Interface().(type) {
+	case time.Duration:
+		return value.String()
+	case string:
+		if strings.HasPrefix(value, "{") && json.Valid([]byte(value)) {
+			return json.RawMessage(value)
+		}
+		return value
+	default:
+		return value
+	}
+}
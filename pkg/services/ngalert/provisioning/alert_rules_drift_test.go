@@ -0,0 +1,111 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestAlertRuleService_DetectRuleDrift(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 40
+
+	create := func(title string, provenance models.Provenance) models.AlertRule {
+		rule := dummyRule(title, orgID)
+		rule.NamespaceUID = "drift-folder"
+		rule, err := ruleService.CreateAlertRule(ctx, rule, provenance)
+		require.NoError(t, err)
+		return rule
+	}
+	provisioned := create("provisioned", models.ProvenanceFile)
+	document, err := ruleService.ExportRuleGroups(ctx, orgID, "drift-folder")
+	require.NoError(t, err)
+
+	t.Run("should not report rules that match the document", func(t *testing.T) {
+		drift, err := ruleService.DetectRuleDrift(ctx, orgID, document)
+		require.NoError(t, err)
+		require.False(t, drift.Drifted)
+		require.Empty(t, drift.Rules)
+	})
+
+	t.Run("should ignore the groups of other organizations", func(t *testing.T) {
+		other := document
+		other.Groups = []definitions.AlertRuleGroupExport{document.Groups[0]}
+		other.Groups[0].OrgID = orgID + 1
+		other.Groups[0].Rules = []definitions.AlertRuleExport{{UID: "unknown"}}
+		drift, err := ruleService.DetectRuleDrift(ctx, orgID, other)
+		require.NoError(t, err)
+		require.False(t, drift.Drifted)
+	})
+
+	t.Run("should report the changes made outside of the document", func(t *testing.T) {
+		changed := provisioned
+		changed.Labels = map[string]string{"team": "manual"}
+		require.NoError(t, ruleService.ruleStore.UpdateAlertRules(ctx, []store.UpdateRule{{Existing: &provisioned, New: changed}}))
+		undeclared := create("undeclared", models.ProvenanceFile)
+		unprovisioned, _, err := ruleService.GetAlertRule(ctx, orgID, create("unprovisioned", models.ProvenanceNone).UID)
+		require.NoError(t, err)
+		create("other", models.ProvenanceNone)
+
+		declared := document
+		declared.Groups = []definitions.AlertRuleGroupExport{document.Groups[0]}
+		declared.Groups[0].Rules = append([]definitions.AlertRuleExport{}, document.Groups[0].Rules...)
+		unprovisionedExport, err := exportAlertRule(unprovisioned)
+		require.NoError(t, err)
+		missing := unprovisionedExport
+		missing.UID, missing.Title = "missing", "missing"
+		declared.Groups[0].Rules = append(declared.Groups[0].Rules, unprovisionedExport, missing)
+
+		drift, err := ruleService.DetectRuleDrift(ctx, orgID, declared)
+		require.NoError(t, err)
+		require.True(t, drift.Drifted)
+		require.Equal(t, []definitions.AlertRuleDrift{
+			{
+				UID:        provisioned.UID,
+				Title:      "provisioned",
+				FolderUID:  "drift-folder",
+				RuleGroup:  "my-cool-group",
+				Status:     definitions.AlertRuleDriftChanged,
+				Provenance: models.ProvenanceFile,
+				Changes:    []definitions.AlertRuleFieldDrift{{Field: "Labels", File: map[string]string(nil), Stored: map[string]string{"team": "manual"}}},
+			},
+			{
+				UID:        unprovisioned.UID,
+				Title:      "unprovisioned",
+				FolderUID:  "drift-folder",
+				RuleGroup:  "my-cool-group",
+				Status:     definitions.AlertRuleDriftProvenance,
+				Provenance: models.ProvenanceNone,
+			},
+			{
+				UID:       "missing",
+				Title:     "missing",
+				FolderUID: "drift-folder",
+				RuleGroup: "my-cool-group",
+				Status:    definitions.AlertRuleDriftMissing,
+			},
+			{
+				UID:        undeclared.UID,
+				Title:      "undeclared",
+				FolderUID:  "drift-folder",
+				RuleGroup:  "my-cool-group",
+				Status:     definitions.AlertRuleDriftUndeclared,
+				Provenance: models.ProvenanceFile,
+			},
+		}, drift.Rules)
+	})
+
+	t.Run("should reject rules without UID", func(t *testing.T) {
+		declared := document
+		declared.Groups = []definitions.AlertRuleGroupExport{document.Groups[0]}
+		declared.Groups[0].Rules = []definitions.AlertRuleExport{{Title: "no uid"}}
+		_, err := ruleService.DetectRuleDrift(ctx, orgID, declared)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
@@ -3,13 +3,23 @@ package provisioning
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	prommodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,7 +49,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, int64(60), rule.IntervalSeconds)
 
 		var interval int64 = 120
-		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120)
+		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120, nil, "")
 		require.NoError(t, err)
 
 		rule, _, err = ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
@@ -54,7 +64,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.NoError(t, err)
 
 		var interval int64 = 120
-		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120)
+		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120, nil, "")
 		require.NoError(t, err)
 
 		rule = dummyRule("test#4-1", orgID)
@@ -83,7 +93,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, int64(1), rule.Version)
 		require.Equal(t, int64(60), rule.IntervalSeconds)
 
-		err = ruleService.UpdateRuleGroup(context.Background(), orgID, namespaceUID, ruleGroup, newInterval)
+		err = ruleService.UpdateRuleGroup(context.Background(), orgID, namespaceUID, ruleGroup, newInterval, nil, "")
 		require.NoError(t, err)
 
 		rule, _, err = ruleService.GetAlertRule(context.Background(), orgID, ruleUID)
@@ -91,6 +101,129 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, int64(2), rule.Version)
 		require.Equal(t, newInterval, rule.IntervalSeconds)
 	})
+	t.Run("cloned alert rule should have the overrides applied", func(t *testing.T) {
+		var orgID int64 = 1
+		rule := dummyRule("test#clone", orgID)
+		rule.Labels = map[string]string{"team": "a", "service": "checkout"}
+		rule.Data[0].Model = json.RawMessage(`{"conditions":[{"evaluator":{"params":[3]}}]}`)
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		clone, err := ruleService.CloneAlertRule(context.Background(), orgID, rule.UID, definitions.AlertRuleClone{
+			Title:      "test#clone-1",
+			Labels:     map[string]string{"service": "cart", "team": ""},
+			Parameters: []definitions.AlertRuleCloneParameter{{RefID: "A", Path: "conditions.0.evaluator.params.0", Value: 10}},
+			IsPaused:   true,
+		})
+		require.NoError(t, err)
+		require.Empty(t, clone.UID)
+		require.Equal(t, "test#clone-1", clone.Title)
+		require.Equal(t, rule.NamespaceUID, clone.NamespaceUID)
+		require.Equal(t, rule.RuleGroup, clone.RuleGroup)
+		require.Equal(t, map[string]string{"service": "cart"}, clone.Labels)
+		require.JSONEq(t, `{"conditions":[{"evaluator":{"params":[10]}}],"intervalMs":1000,"maxDataPoints":43200}`, string(clone.Data[0].Model))
+		require.True(t, clone.IsPaused)
+
+		created, err := ruleService.CreateAlertRule(context.Background(), clone, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NotEqual(t, rule.UID, created.UID)
+
+		original, _, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"team": "a", "service": "checkout"}, original.Labels)
+		require.False(t, original.IsPaused)
+	})
+	t.Run("cloning an alert rule should fail for invalid overrides", func(t *testing.T) {
+		var orgID int64 = 1
+		rule, err := ruleService.CreateAlertRule(context.Background(), dummyRule("test#clone-invalid", orgID), models.ProvenanceNone)
+		require.NoError(t, err)
+
+		_, err = ruleService.CloneAlertRule(context.Background(), orgID, rule.UID, definitions.AlertRuleClone{})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		_, err = ruleService.CloneAlertRule(context.Background(), orgID, rule.UID, definitions.AlertRuleClone{
+			Title:      "test#clone-invalid-1",
+			Parameters: []definitions.AlertRuleCloneParameter{{RefID: "B", Path: "expr", Value: "up"}},
+		})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		_, err = ruleService.CloneAlertRule(context.Background(), orgID, rule.UID, definitions.AlertRuleClone{
+			Title:      "test#clone-invalid-1",
+			Parameters: []definitions.AlertRuleCloneParameter{{RefID: "A", Path: "conditions.0", Value: 1}},
+		})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		_, err = ruleService.CloneAlertRule(context.Background(), orgID, "does-not-exist", definitions.AlertRuleClone{Title: "x"})
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+	t.Run("patched alert rule should only have the changes of the patch", func(t *testing.T) {
+		var orgID int64 = 1
+		rule := dummyRule("test#patch", orgID)
+		rule.Labels = map[string]string{"team": "a", "service": "checkout"}
+		rule.Annotations = map[string]string{"summary": "checkout is slow"}
+		rule.Data[0].Model = json.RawMessage(`{"conditions":[{"evaluator":{"params":[3]}}]}`)
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+		forDuration := 5 * time.Minute
+
+		patched, err := ruleService.PatchAlertRule(context.Background(), orgID, rule.UID, definitions.AlertRulePatch{
+			NoDataState: models.Alerting,
+			For:         &forDuration,
+			Annotations: map[string]string{"runbook_url": "https://example.com/runbook", "summary": ""},
+			Labels:      map[string]string{"service": "cart", "team": ""},
+			Parameters:  []definitions.AlertRuleCloneParameter{{RefID: "A", Path: "conditions.0.evaluator.params.0", Value: 10}},
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, rule.UID, patched.UID)
+		require.Equal(t, rule.Title, patched.Title)
+		require.Equal(t, rule.Condition, patched.Condition)
+		require.Equal(t, rule.ExecErrState, patched.ExecErrState)
+		require.Equal(t, models.Alerting, patched.NoDataState)
+		require.Equal(t, forDuration, patched.For)
+		require.Equal(t, map[string]string{"runbook_url": "https://example.com/runbook"}, patched.Annotations)
+		require.Equal(t, map[string]string{"service": "cart"}, patched.Labels)
+		require.JSONEq(t, `{"conditions":[{"evaluator":{"params":[10]}}],"intervalMs":1000,"maxDataPoints":43200}`, string(patched.Data[0].Model))
+
+		original, _, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"team": "a", "service": "checkout"}, original.Labels)
+
+		_, err = ruleService.UpdateAlertRule(context.Background(), patched, models.ProvenanceAPI)
+		require.NoError(t, err)
+		updated, _, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"service": "cart"}, updated.Labels)
+		require.Equal(t, rule.Version+1, updated.Version)
+	})
+	t.Run("patching an alert rule should fail for invalid changes", func(t *testing.T) {
+		var orgID int64 = 1
+		rule, err := ruleService.CreateAlertRule(context.Background(), dummyRule("test#patch-invalid", orgID), models.ProvenanceNone)
+		require.NoError(t, err)
+		negative := -time.Minute
+
+		for name, patch := range map[string]definitions.AlertRulePatch{
+			"unknown condition":      {Condition: "B"},
+			"unknown no data state":  {NoDataState: "Unknown"},
+			"unknown error state":    {ExecErrState: "Unknown"},
+			"negative for":           {For: &negative},
+			"unknown query":          {Parameters: []definitions.AlertRuleCloneParameter{{RefID: "B", Path: "expr", Value: "up"}}},
+			"invalid parameter path": {Parameters: []definitions.AlertRuleCloneParameter{{RefID: "A", Path: "conditions.0", Value: 1}}},
+		} {
+			_, err = ruleService.PatchAlertRule(context.Background(), orgID, rule.UID, patch, models.ProvenanceAPI)
+			require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation, name)
+		}
+
+		_, err = ruleService.PatchAlertRule(context.Background(), orgID, "does-not-exist", definitions.AlertRulePatch{Title: "x"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+	t.Run("patching an alert rule should fail for rules provisioned otherwise", func(t *testing.T) {
+		var orgID int64 = 1
+		rule, err := ruleService.CreateAlertRule(context.Background(), dummyRule("test#patch-file", orgID), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = ruleService.PatchAlertRule(context.Background(), orgID, rule.UID, definitions.AlertRulePatch{Title: "x"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
 	t.Run("alert rule provenace should be correctly checked", func(t *testing.T) {
 		tests := []struct {
 			name   string
@@ -153,17 +286,791 @@ func TestAlertRuleService(t *testing.T) {
 	})
 }
 
+func TestAlertRuleService_ImportRuleGroups(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	getGroup := func(t *testing.T, orgID int64, folder, group string) definitions.AlertRuleGroup {
+		t.Helper()
+		g, err := ruleService.GetRuleGroup(ctx, orgID, folder, group)
+		require.NoError(t, err)
+		return g
+	}
+
+	t.Run("should create and update the rules of many groups", func(t *testing.T) {
+		var orgID int64 = 10
+		kept := dummyRule("kept", orgID)
+		kept.NamespaceUID, kept.RuleGroup = "folder-a", "group-a"
+		kept, err := ruleService.CreateAlertRule(ctx, kept, models.ProvenanceNone)
+		require.NoError(t, err)
+		updated := dummyRule("updated", orgID)
+		updated.NamespaceUID, updated.RuleGroup = "folder-a", "group-a"
+		updated, err = ruleService.CreateAlertRule(ctx, updated, models.ProvenanceNone)
+		require.NoError(t, err)
+
+		updated.Title = "updated with import"
+		created := dummyRule("created", orgID)
+		other := dummyRule("other", orgID)
+		err = ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{
+			{Title: "group-a", FolderUID: "folder-a", Interval: 120, Rules: []models.AlertRule{updated, created}},
+			{Title: "group-b", FolderUID: "folder-b", Interval: 30, Rules: []models.AlertRule{other}},
+		}, models.ProvenanceAPI, false)
+		require.NoError(t, err)
+
+		groupA := getGroup(t, orgID, "folder-a", "group-a")
+		require.Len(t, groupA.Rules, 3)
+		for _, rule := range groupA.Rules {
+			require.Equal(t, int64(120), rule.IntervalSeconds, rule.Title)
+		}
+		groupB := getGroup(t, orgID, "folder-b", "group-b")
+		require.Len(t, groupB.Rules, 1)
+		require.Equal(t, int64(30), groupB.Rules[0].IntervalSeconds)
+
+		rule, provenance, err := ruleService.GetAlertRule(ctx, orgID, updated.UID)
+		require.NoError(t, err)
+		require.Equal(t, "updated with import", rule.Title)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+		// rules of imported groups that are not imported keep their provenance
+		_, provenance, err = ruleService.GetAlertRule(ctx, orgID, kept.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+	})
+
+	t.Run("should report all invalid groups and rules and store nothing", func(t *testing.T) {
+		var orgID int64 = 11
+		noTitle := dummyRule("", orgID)
+		negativeFor := dummyRule("negative for", orgID)
+		negativeFor.For = -time.Second
+		duplicate := dummyRule("valid", orgID)
+
+		err := ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{
+			{Title: "group-a", FolderUID: "folder-a", Interval: 60, Rules: []models.AlertRule{noTitle, negativeFor, dummyRule("valid", orgID), duplicate}},
+			{Title: "group-b", FolderUID: "folder-a", Interval: 15, Rules: []models.AlertRule{dummyRule("invalid interval", orgID)}},
+		}, models.ProvenanceAPI, false)
+
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		var validationErr RuleGroupsValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Len(t, validationErr.Errors, 4)
+		_, err = ruleService.GetRuleGroup(ctx, orgID, "folder-a", "group-a")
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("should not change provenance of rules provisioned otherwise", func(t *testing.T) {
+		var orgID int64 = 12
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("from file", orgID), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		err = ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{
+			{Title: rule.RuleGroup, FolderUID: rule.NamespaceUID, Interval: 60, Rules: []models.AlertRule{rule}},
+		}, models.ProvenanceAPI, false)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should check the quota once and store nothing when it is reached", func(t *testing.T) {
+		var orgID int64 = 13
+		quotas := &fakeQuotaChecker{reached: true}
+		ruleService := ruleService
+		ruleService.quotas = quotas
+
+		err := ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{
+			{Title: "group-a", FolderUID: "folder-a", Interval: 60, Rules: []models.AlertRule{dummyRule("a", orgID), dummyRule("b", orgID)}},
+			{Title: "group-b", FolderUID: "folder-a", Interval: 60, Rules: []models.AlertRule{dummyRule("c", orgID)}},
+		}, models.ProvenanceAPI, false)
+
+		require.ErrorIs(t, err, ErrQuotaReached)
+		require.Equal(t, 1, quotas.calls)
+		_, err = ruleService.GetRuleGroup(ctx, orgID, "folder-a", "group-a")
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("should update the rules without UID on repeated imports with deterministic UIDs", func(t *testing.T) {
+		var orgID int64 = 14
+		importGroup := func(t *testing.T, deterministicUIDs bool, rules ...models.AlertRule) {
+			t.Helper()
+			err := ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{
+				{Title: "group-a", FolderUID: "folder-a", Interval: 60, Rules: rules},
+			}, models.ProvenanceAPI, deterministicUIDs)
+			require.NoError(t, err)
+		}
+
+		importGroup(t, true, dummyRule("a", orgID), dummyRule("b", orgID))
+		first := getGroup(t, orgID, "folder-a", "group-a")
+		require.Len(t, first.Rules, 2)
+		require.Equal(t, deterministicRuleUID("folder-a", "group-a", "a"), first.Rules[0].UID)
+
+		changed := dummyRule("a", orgID)
+		changed.For = time.Minute
+		importGroup(t, true, changed, dummyRule("b", orgID))
+		second := getGroup(t, orgID, "folder-a", "group-a")
+		require.Len(t, second.Rules, 2)
+		require.Equal(t, first.Rules[0].UID, second.Rules[0].UID)
+		require.Equal(t, first.Rules[1].UID, second.Rules[1].UID)
+		require.Equal(t, time.Minute, second.Rules[0].For)
+
+		importGroup(t, false, dummyRule("c", orgID))
+		third := getGroup(t, orgID, "folder-a", "group-a")
+		require.Len(t, third.Rules, 3)
+		require.NotEqual(t, deterministicRuleUID("folder-a", "group-a", "c"), third.Rules[2].UID)
+	})
+}
+
+func TestDeterministicRuleUID(t *testing.T) {
+	uid := deterministicRuleUID("folder", "group", "title")
+	require.Len(t, uid, 40)
+	require.True(t, util.IsValidShortUID(uid))
+	require.Equal(t, uid, deterministicRuleUID("folder", "group", "title"))
+	require.NotEqual(t, uid, deterministicRuleUID("folder", "group", "other"))
+	require.NotEqual(t, deterministicRuleUID("ab", "c", "title"), deterministicRuleUID("a", "bc", "title"))
+}
+
+func TestAlertRuleService_CopyRuleGroup(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var stagingOrgID, productionOrgID int64 = 30, 31
+	ruleService.datasources = fakeDatasourceReader{
+		{OrgId: stagingOrgID, Uid: "staging-prometheus", Type: "prometheus"},
+		{OrgId: productionOrgID, Uid: "production-prometheus", Type: "prometheus"},
+	}
+	ruleService.folders = fakeDashboardReader{
+		{OrgId: stagingOrgID, Uid: "staging", IsFolder: true},
+		{OrgId: stagingOrgID, Uid: "staging-2", IsFolder: true},
+		{OrgId: stagingOrgID, Uid: "staging-dashboard"},
+		{OrgId: productionOrgID, Uid: "production", IsFolder: true},
+	}
+
+	source := make([]models.AlertRule, 0, 2)
+	for _, title := range []string{"high latency", "errors"} {
+		rule := dummyRule(title, stagingOrgID)
+		rule.NamespaceUID, rule.RuleGroup = "staging", "checkout"
+		rule.Data[0].DatasourceUID = "staging-prometheus"
+		rule.Data[0].RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(10 * time.Minute)}
+		rule.Data[0].Model = json.RawMessage(`{"expr":"up","datasource":{"type":"prometheus","uid":"staging-prometheus"}}`)
+		dashboardUID, panelID := "checkout-dashboard", int64(2)
+		rule.DashboardUID, rule.PanelID = &dashboardUID, &panelID
+		rule.Annotations = map[string]string{models.DashboardUIDAnnotation: "checkout-dashboard", models.PanelIDAnnotation: "2", "summary": "checkout"}
+		rule, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceNone)
+		require.NoError(t, err)
+		source = append(source, rule)
+	}
+
+	t.Run("should copy the group to another organization with new UIDs and datasources", func(t *testing.T) {
+		copied, err := ruleService.CopyRuleGroup(ctx, stagingOrgID, "staging", "checkout", definitions.AlertRuleGroupCopy{
+			OrgID:          productionOrgID,
+			FolderUID:      "production",
+			DatasourceUIDs: map[string]string{"staging-prometheus": "production-prometheus"},
+			IsPaused:       true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "checkout", copied.Title)
+		require.Equal(t, "production", copied.FolderUID)
+		require.Len(t, copied.Rules, 2)
+		for i, rule := range copied.Rules {
+			require.Equal(t, source[i].Title, rule.Title)
+			require.Equal(t, productionOrgID, rule.OrgID)
+			require.NotEqual(t, source[i].UID, rule.UID)
+			require.True(t, rule.IsPaused)
+			require.Nil(t, rule.DashboardUID)
+			require.Nil(t, rule.PanelID)
+			require.Equal(t, map[string]string{"summary": "checkout"}, rule.Annotations)
+			require.Equal(t, "production-prometheus", rule.Data[0].DatasourceUID)
+			require.JSONEq(t, `{"expr":"up","datasource":{"type":"prometheus","uid":"production-prometheus"},"intervalMs":1000,"maxDataPoints":43200}`, string(rule.Data[0].Model))
+
+			_, provenance, err := ruleService.GetAlertRule(ctx, productionOrgID, rule.UID)
+			require.NoError(t, err)
+			require.Equal(t, models.ProvenanceAPI, provenance)
+		}
+
+		original, _, err := ruleService.GetAlertRule(ctx, stagingOrgID, source[0].UID)
+		require.NoError(t, err)
+		require.Equal(t, "staging-prometheus", original.Data[0].DatasourceUID)
+		require.False(t, original.IsPaused)
+	})
+
+	t.Run("should copy the group to another folder of the organization under a new title", func(t *testing.T) {
+		copied, err := ruleService.CopyRuleGroup(ctx, stagingOrgID, "staging", "checkout", definitions.AlertRuleGroupCopy{
+			FolderUID: "staging-2",
+			Title:     "checkout-2",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "checkout-2", copied.Title)
+		require.Len(t, copied.Rules, 2)
+		require.Equal(t, stagingOrgID, copied.Rules[0].OrgID)
+		require.Equal(t, "staging-prometheus", copied.Rules[0].Data[0].DatasourceUID)
+		// the dashboards can still be linked in the same organization
+		require.Equal(t, source[0].DashboardUID, copied.Rules[0].DashboardUID)
+	})
+
+	t.Run("should not copy into an existing group or over rules with the same titles", func(t *testing.T) {
+		_, err := ruleService.CopyRuleGroup(ctx, stagingOrgID, "staging", "checkout", definitions.AlertRuleGroupCopy{FolderUID: "staging-2", Title: "checkout-2"})
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = ruleService.CopyRuleGroup(ctx, stagingOrgID, "staging", "checkout", definitions.AlertRuleGroupCopy{FolderUID: "staging", Title: "checkout-3"})
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = ruleService.CopyRuleGroup(ctx, stagingOrgID, "staging", "checkout", definitions.AlertRuleGroupCopy{})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should not copy into a folder that does not exist in the target organization", func(t *testing.T) {
+		_, err := ruleService.CopyRuleGroup(ctx, stagingOrgID, "staging", "checkout", definitions.AlertRuleGroupCopy{FolderUID: "unknown", Title: "checkout-4"})
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = ruleService.CopyRuleGroup(ctx, stagingOrgID, "staging", "checkout", definitions.AlertRuleGroupCopy{FolderUID: "staging-dashboard", Title: "checkout-4"})
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = ruleService.CopyRuleGroup(ctx, stagingOrgID, "staging", "checkout", definitions.AlertRuleGroupCopy{OrgID: productionOrgID, FolderUID: "staging-2", Title: "checkout-4"})
+		require.ErrorIs(t, err, ErrValidation)
+
+		query := &models.ListAlertRulesQuery{OrgID: productionOrgID, NamespaceUIDs: []string{"staging-2"}}
+		require.NoError(t, ruleService.ruleStore.ListAlertRules(ctx, query))
+		require.Empty(t, query.Result)
+	})
+
+	t.Run("copying an unknown group fails", func(t *testing.T) {
+		_, err := ruleService.CopyRuleGroup(ctx, stagingOrgID, "staging", "unknown", definitions.AlertRuleGroupCopy{FolderUID: "production"})
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+}
+
+func TestAlertRuleService_Pause(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 31
+
+	t.Run("rules provisioned from files can be paused and resumed without changing their provenance", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("from file", orgID), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		paused, err := ruleService.SetAlertRulePaused(ctx, orgID, rule.UID, true)
+		require.NoError(t, err)
+		require.True(t, paused.IsPaused)
+
+		stored, provenance, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.True(t, stored.IsPaused)
+		require.Equal(t, rule.Version+1, stored.Version)
+		require.Equal(t, models.ProvenanceFile, provenance)
+
+		_, err = ruleService.SetAlertRulePaused(ctx, orgID, rule.UID, false)
+		require.NoError(t, err)
+		stored, _, err = ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.False(t, stored.IsPaused)
+	})
+
+	t.Run("pausing an unknown rule fails", func(t *testing.T) {
+		_, err := ruleService.SetAlertRulePaused(ctx, orgID, "unknown", true)
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+
+	t.Run("all rules of a group can be paused", func(t *testing.T) {
+		a := dummyRule("a", orgID)
+		a.RuleGroup = "paused-group"
+		b := dummyRule("b", orgID)
+		b.RuleGroup = "paused-group"
+		_, err := ruleService.CreateAlertRule(ctx, a, models.ProvenanceFile)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(ctx, b, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		err = ruleService.SetRuleGroupPaused(ctx, orgID, a.NamespaceUID, "paused-group", true, "")
+		require.NoError(t, err)
+
+		group, err := ruleService.GetRuleGroup(ctx, orgID, a.NamespaceUID, "paused-group")
+		require.NoError(t, err)
+		require.Len(t, group.Rules, 2)
+		for _, rule := range group.Rules {
+			require.True(t, rule.IsPaused)
+		}
+	})
+
+	t.Run("pausing an unknown group fails", func(t *testing.T) {
+		err := ruleService.SetRuleGroupPaused(ctx, orgID, "folder", "unknown", true, "")
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+}
+
+func TestAlertRuleService_GetAlertRules(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 41
+
+	for _, r := range []struct {
+		title, folder string
+		labels        map[string]string
+	}{
+		{"payments critical", "folder", map[string]string{"team": "payments", "severity": "critical"}},
+		{"payments info", "folder", map[string]string{"team": "payments", "severity": "info"}},
+		{"billing critical", "folder", map[string]string{"team": "billing", "severity": "critical"}},
+		{"payments other folder", "other-folder", map[string]string{"team": "payments", "severity": "critical"}},
+	} {
+		rule := dummyRule(r.title, orgID)
+		rule.NamespaceUID = r.folder
+		rule.Labels = r.labels
+		_, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+	}
+	titles := func(rules []definitions.AlertRule) []string {
+		result := make([]string, 0, len(rules))
+		for _, r := range rules {
+			result = append(result, r.Title)
+		}
+		return result
+	}
+	matcher := func(matchType labels.MatchType, name, value string) *labels.Matcher {
+		m, err := labels.NewMatcher(matchType, name, value)
+		require.NoError(t, err)
+		return m
+	}
+
+	t.Run("all rules are returned without matchers", func(t *testing.T) {
+		rules, err := ruleService.GetAlertRules(ctx, orgID, "", "", nil)
+		require.NoError(t, err)
+		require.Len(t, rules, 4)
+		require.Equal(t, models.ProvenanceAPI, rules[0].Provenance)
+	})
+
+	t.Run("the rules must match all the matchers", func(t *testing.T) {
+		rules, err := ruleService.GetAlertRules(ctx, orgID, "", "", labels.Matchers{matcher(labels.MatchEqual, "team", "payments"), matcher(labels.MatchNotEqual, "severity", "info")})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"payments critical", "payments other folder"}, titles(rules))
+
+		rules, err = ruleService.GetAlertRules(ctx, orgID, "", "", labels.Matchers{matcher(labels.MatchRegexp, "team", "payments|billing"), matcher(labels.MatchEqual, "severity", "critical")})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"payments critical", "billing critical", "payments other folder"}, titles(rules))
+	})
+
+	t.Run("the rules can be restricted to a folder", func(t *testing.T) {
+		rules, err := ruleService.GetAlertRules(ctx, orgID, "other-folder", "", labels.Matchers{matcher(labels.MatchEqual, "team", "payments")})
+		require.NoError(t, err)
+		require.Equal(t, []string{"payments other folder"}, titles(rules))
+	})
+
+	t.Run("labels that the rules do not have match empty values", func(t *testing.T) {
+		rules, err := ruleService.GetAlertRules(ctx, orgID, "", "", labels.Matchers{matcher(labels.MatchEqual, "env", "")})
+		require.NoError(t, err)
+		require.Len(t, rules, 4)
+	})
+}
+
+func TestAlertRuleService_SetProvenance(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 32
+
+	t.Run("rules provisioned from files can be taken over by the API", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("from file", orgID), models.ProvenanceFile)
+		require.NoError(t, err)
+		rule.Title = "changed"
+		_, err = ruleService.UpdateAlertRule(ctx, rule, models.ProvenanceAPI)
+		require.Error(t, err)
+
+		_, err = ruleService.SetAlertRuleProvenance(ctx, orgID, rule.UID, models.ProvenanceAPI)
+		require.NoError(t, err)
+		_, provenance, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+		_, err = ruleService.UpdateAlertRule(ctx, rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+	})
+
+	t.Run("the provenance can be removed", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("from api", orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = ruleService.SetAlertRuleProvenance(ctx, orgID, rule.UID, models.ProvenanceNone)
+		require.NoError(t, err)
+		_, provenance, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+	})
+
+	t.Run("unknown rules and provenances are rejected", func(t *testing.T) {
+		_, err := ruleService.SetAlertRuleProvenance(ctx, orgID, "unknown", models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("other", orgID), models.ProvenanceFile)
+		require.NoError(t, err)
+		_, err = ruleService.SetAlertRuleProvenance(ctx, orgID, rule.UID, models.Provenance("terraform"))
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func TestAlertRuleService_SetAlertRulesProvenance(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 33
+
+	provenanceOf := func(t *testing.T, uid string) models.Provenance {
+		t.Helper()
+		_, provenance, err := ruleService.GetAlertRule(ctx, orgID, uid)
+		require.NoError(t, err)
+		return provenance
+	}
+
+	t.Run("the provenance of all the rules is changed at once", func(t *testing.T) {
+		none, err := ruleService.CreateAlertRule(ctx, dummyRule("bulk none", orgID), models.ProvenanceNone)
+		require.NoError(t, err)
+		file, err := ruleService.CreateAlertRule(ctx, dummyRule("bulk file", orgID), models.ProvenanceFile)
+		require.NoError(t, err)
+		api, err := ruleService.CreateAlertRule(ctx, dummyRule("bulk api", orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		result, err := ruleService.SetAlertRulesProvenance(ctx, orgID, []string{none.UID, file.UID, api.UID, none.UID}, models.ProvenanceAPI)
+
+		require.NoError(t, err)
+		require.Equal(t, definitions.AlertRulesProvenanceResult{
+			Provenance: models.ProvenanceAPI,
+			Changed:    []string{none.UID, file.UID},
+			Unchanged:  []string{api.UID},
+		}, result)
+		for _, uid := range []string{none.UID, file.UID, api.UID} {
+			require.Equal(t, models.ProvenanceAPI, provenanceOf(t, uid))
+		}
+	})
+
+	t.Run("nothing is changed if a rule does not exist", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("bulk missing", orgID), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = ruleService.SetAlertRulesProvenance(ctx, orgID, []string{rule.UID, "unknown"}, models.ProvenanceAPI)
+
+		require.ErrorIs(t, err, ErrValidation)
+		require.Contains(t, err.Error(), "unknown")
+		require.Equal(t, models.ProvenanceFile, provenanceOf(t, rule.UID))
+	})
+
+	t.Run("unknown provenances and empty lists are rejected", func(t *testing.T) {
+		_, err := ruleService.SetAlertRulesProvenance(ctx, orgID, []string{"a"}, models.Provenance("terraform"))
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = ruleService.SetAlertRulesProvenance(ctx, orgID, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func TestAlertRuleService_FolderPolicies(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	ruleService.folderPolicies = fakeFolderPolicyChecker{
+		"strict-folder": {MinIntervalSeconds: 300, RequiredLabels: []string{"team"}},
+	}
+	var orgID int64 = 30
+
+	t.Run("new groups get the minimum interval of the folder if it is above the default", func(t *testing.T) {
+		rule := dummyRule("strict", orgID)
+		rule.NamespaceUID, rule.RuleGroup = "strict-folder", "new-group"
+		rule, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceNone)
+		require.NoError(t, err)
+		require.Equal(t, int64(300), rule.IntervalSeconds)
+
+		rule = dummyRule("lenient", orgID)
+		rule.NamespaceUID, rule.RuleGroup = "other-folder", "new-group"
+		rule, err = ruleService.CreateAlertRule(ctx, rule, models.ProvenanceNone)
+		require.NoError(t, err)
+		require.Equal(t, int64(60), rule.IntervalSeconds)
+	})
+
+	t.Run("group intervals below the minimum of the folder are rejected", func(t *testing.T) {
+		rule := dummyRule("interval", orgID)
+		rule.NamespaceUID, rule.RuleGroup = "strict-folder", "interval-group"
+		_, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceNone)
+		require.NoError(t, err)
+
+		err = ruleService.UpdateRuleGroup(ctx, orgID, "strict-folder", "interval-group", 60, nil, "")
+		require.ErrorIs(t, err, ErrValidation)
+		require.NoError(t, ruleService.UpdateRuleGroup(ctx, orgID, "strict-folder", "interval-group", 600, nil, ""))
+	})
+
+	t.Run("imported rules violating the policy of their folder are rejected", func(t *testing.T) {
+		rule := dummyRule("imported", orgID)
+
+		err := ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{
+			{Title: "imported-group", FolderUID: "strict-folder", Interval: 300, Rules: []models.AlertRule{rule}},
+		}, models.ProvenanceAPI, false)
+
+		require.ErrorIs(t, err, ErrValidation)
+		require.Contains(t, err.Error(), "the folder requires the label team")
+	})
+}
+
+func TestAlertRuleService_MinInterval(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	ruleService.minIntervalSeconds = 60
+	var orgID int64 = 32
+
+	rule, err := ruleService.CreateAlertRule(ctx, dummyRule("min interval", orgID), models.ProvenanceNone)
+	require.NoError(t, err)
+
+	t.Run("group intervals below the minimum interval are rejected with the nearest valid interval", func(t *testing.T) {
+		err := ruleService.UpdateRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, 30, nil, "")
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.Contains(t, err.Error(), "the nearest valid interval is 60s")
+		require.NoError(t, ruleService.UpdateRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, 60, nil, ""))
+	})
+
+	t.Run("group intervals that are not a multiple of the base interval are rejected with the nearest valid interval", func(t *testing.T) {
+		err := ruleService.UpdateRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, 124, nil, "")
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.Contains(t, err.Error(), "the nearest valid interval is 120s")
+	})
+
+	t.Run("imported groups below the minimum interval are rejected", func(t *testing.T) {
+		err := ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{
+			{Title: "fast-group", FolderUID: "folder-a", Interval: 10, Rules: []models.AlertRule{dummyRule("fast", orgID)}},
+		}, models.ProvenanceAPI, false)
+		require.ErrorIs(t, err, ErrValidation)
+		require.Contains(t, err.Error(), "the nearest valid interval is 60s")
+	})
+}
+
+func TestNearestValidInterval(t *testing.T) {
+	ruleService := AlertRuleService{baseIntervalSeconds: 10, minIntervalSeconds: 30}
+	for interval, expected := range map[int64]int64{
+		-10: 30,
+		0:   30,
+		14:  30,
+		44:  40,
+		45:  50,
+		120: 120,
+	} {
+		require.Equal(t, expected, ruleService.nearestValidInterval(interval), "interval %d", interval)
+	}
+}
+
+func TestAlertRuleService_ExportRuleGroups(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 20
+	create := func(t *testing.T, title, folder, group string) models.AlertRule {
+		t.Helper()
+		rule := dummyRule(title, orgID)
+		rule.NamespaceUID, rule.RuleGroup = folder, group
+		rule.Labels = map[string]string{"team": "a"}
+		rule.Webhooks = []models.AlertRuleWebhook{{URL: "https://example.com/hook"}}
+		rule.Data[0].Model = json.RawMessage(`{"expression":"1 == 1"}`)
+		rule, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceNone)
+		require.NoError(t, err)
+		return rule
+	}
+	first := create(t, "first", "export-folder", "group-b")
+	second := create(t, "second", "export-folder", "group-a")
+	create(t, "other folder", "other-folder", "group-a")
+	require.NoError(t, ruleService.UpdateRuleGroup(ctx, orgID, "export-folder", "group-a", 120, nil, ""))
+
+	t.Run("should export the rule groups of the folder sorted by name", func(t *testing.T) {
+		export, err := ruleService.ExportRuleGroups(ctx, orgID, "export-folder")
+		require.NoError(t, err)
+
+		require.Equal(t, int64(1), export.APIVersion)
+		require.Len(t, export.Groups, 2)
+		groupA, groupB := export.Groups[0], export.Groups[1]
+		require.Equal(t, "group-a", groupA.Name)
+		require.Equal(t, "export-folder", groupA.FolderUID)
+		require.Equal(t, orgID, groupA.OrgID)
+		require.Equal(t, prommodel.Duration(2*time.Minute), groupA.Interval)
+		require.Len(t, groupA.Rules, 1)
+		require.Equal(t, second.UID, groupA.Rules[0].UID)
+		require.Equal(t, "group-b", groupB.Name)
+		require.Equal(t, prommodel.Duration(time.Minute), groupB.Interval)
+		require.Len(t, groupB.Rules, 1)
+
+		rule := groupB.Rules[0]
+		require.Equal(t, first.UID, rule.UID)
+		require.Equal(t, "first", rule.Title)
+		require.Equal(t, prommodel.Duration(time.Minute), rule.For)
+		require.Equal(t, map[string]string{"team": "a"}, rule.Labels)
+		require.Equal(t, []models.AlertRuleWebhook{{URL: "https://example.com/hook"}}, rule.Webhooks)
+		require.Len(t, rule.Data, 1)
+		require.Equal(t, "1 == 1", rule.Data[0].Model["expression"])
+	})
+
+	t.Run("should export no groups for a folder without rules", func(t *testing.T) {
+		export, err := ruleService.ExportRuleGroups(ctx, orgID, "empty-folder")
+		require.NoError(t, err)
+		require.Empty(t, export.Groups)
+	})
+}
+
+func TestAlertRuleService_RuleLimits(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	ruleService.settings.RuleLimitsPerOrg = map[int64]setting.UnifiedAlertingRuleLimits{
+		40: {MaxRules: 3, MaxRulesPerGroup: 2},
+	}
+	reg := prometheus.NewPedanticRegistry()
+	ruleService.metrics = metrics.NewNGAlert(reg).GetProvisioningMetrics()
+	var orgID int64 = 40
+
+	first, err := ruleService.CreateAlertRule(ctx, dummyRule("first", orgID), models.ProvenanceAPI)
+	require.NoError(t, err)
+	second, err := ruleService.CreateAlertRule(ctx, dummyRule("second", orgID), models.ProvenanceAPI)
+	require.NoError(t, err)
+	other := dummyRule("other", orgID)
+	other.RuleGroup = "other-group"
+	_, err = ruleService.CreateAlertRule(ctx, other, models.ProvenanceAPI)
+	require.NoError(t, err)
+
+	t.Run("creating a rule above a limit fails", func(t *testing.T) {
+		_, err := ruleService.CreateAlertRule(ctx, dummyRule("third", orgID), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrQuotaReached)
+		require.Contains(t, err.Error(), "the organization would have 4 alert rules, at most 3 are allowed")
+	})
+
+	t.Run("moving a rule to a group above a limit fails", func(t *testing.T) {
+		first.RuleGroup = "other-group"
+		_, err := ruleService.UpdateAlertRule(ctx, first, models.ProvenanceAPI)
+		require.NoError(t, err)
+		first.Title = "first in other group"
+		_, err = ruleService.UpdateAlertRule(ctx, first, models.ProvenanceAPI)
+		require.NoError(t, err, "the rules of a group at its limit can be updated")
+
+		second.RuleGroup = "other-group"
+		_, err = ruleService.UpdateAlertRule(ctx, second, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrQuotaReached)
+		require.Contains(t, err.Error(), "the rule group 'other-group' of folder '' would have 3 alert rules, at most 2 are allowed")
+	})
+
+	t.Run("importing rules above a limit fails and stores nothing", func(t *testing.T) {
+		err := ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{
+			{Title: "imported", FolderUID: "folder-a", Interval: 60, Rules: []models.AlertRule{dummyRule("a", orgID), dummyRule("b", orgID)}},
+		}, models.ProvenanceAPI, false)
+		require.ErrorIs(t, err, ErrQuotaReached)
+		require.Contains(t, err.Error(), "the organization would have 5 alert rules, at most 3 are allowed")
+		_, err = ruleService.GetRuleGroup(ctx, orgID, "folder-a", "imported")
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("other organizations are not limited", func(t *testing.T) {
+		for _, title := range []string{"a", "b", "c", "d"} {
+			_, err := ruleService.CreateAlertRule(ctx, dummyRule(title, 41), models.ProvenanceAPI)
+			require.NoError(t, err)
+		}
+	})
+
+	expectedMetric := `
+		# HELP grafana_alerting_provisioning_rule_limit_exceeded_total The number of changes to provisioned alert rules that were rejected because they exceed a limit on the alert rules.
+		# TYPE grafana_alerting_provisioning_rule_limit_exceeded_total counter
+		grafana_alerting_provisioning_rule_limit_exceeded_total{limit="max_rules",org="40"} 2
+		grafana_alerting_provisioning_rule_limit_exceeded_total{limit="max_rules_per_group",org="40"} 1
+	`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expectedMetric), "grafana_alerting_provisioning_rule_limit_exceeded_total"))
+}
+
+func TestAlertRuleService_SetRuleGroupOrder(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 32
+
+	uids := make([]string, 0, 3)
+	for _, title := range []string{"a", "b", "c"} {
+		rule := dummyRule(title, orgID)
+		rule.RuleGroup = "ordered-group"
+		rule.NamespaceUID = "my-namespace"
+		rule, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceFile)
+		require.NoError(t, err)
+		uids = append(uids, rule.UID)
+	}
+	titles := func(t *testing.T) []string {
+		group, err := ruleService.GetRuleGroup(ctx, orgID, "my-namespace", "ordered-group")
+		require.NoError(t, err)
+		result := make([]string, 0, len(group.Rules))
+		for _, rule := range group.Rules {
+			result = append(result, rule.Title)
+		}
+		return result
+	}
+
+	t.Run("rules are reordered", func(t *testing.T) {
+		err := ruleService.SetRuleGroupOrder(ctx, orgID, "my-namespace", "ordered-group", []string{uids[2], uids[0], uids[1]}, "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"c", "a", "b"}, titles(t))
+	})
+
+	t.Run("the UIDs of all rules of the group are required", func(t *testing.T) {
+		err := ruleService.SetRuleGroupOrder(ctx, orgID, "my-namespace", "ordered-group", []string{uids[0], uids[1]}, "")
+		require.ErrorIs(t, err, ErrValidation)
+		err = ruleService.SetRuleGroupOrder(ctx, orgID, "my-namespace", "ordered-group", []string{uids[0], uids[1], uids[1]}, "")
+		require.ErrorIs(t, err, ErrValidation)
+		err = ruleService.SetRuleGroupOrder(ctx, orgID, "my-namespace", "ordered-group", []string{uids[0], uids[1], uids[2], "unknown"}, "")
+		require.ErrorIs(t, err, ErrValidation)
+		require.Equal(t, []string{"c", "a", "b"}, titles(t))
+	})
+
+	t.Run("reordering an unknown group fails", func(t *testing.T) {
+		err := ruleService.SetRuleGroupOrder(ctx, orgID, "my-namespace", "unknown", nil, "")
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+}
+
+func TestAlertRuleService_RuleGroupVersion(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 33
+
+	rule, err := ruleService.CreateAlertRule(ctx, dummyRule("versioned", orgID), models.ProvenanceAPI)
+	require.NoError(t, err)
+	_, version, err := ruleService.GetRuleGroupWithVersion(ctx, orgID, rule.NamespaceUID, rule.RuleGroup)
+	require.NoError(t, err)
+	require.NotEmpty(t, version)
+
+	t.Run("the version is stable until the group changes", func(t *testing.T) {
+		_, same, err := ruleService.GetRuleGroupWithVersion(ctx, orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, version, same)
+
+		_, err = ruleService.CreateAlertRule(ctx, dummyRule("versioned-2", orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+		_, changed, err := ruleService.GetRuleGroupWithVersion(ctx, orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.NotEqual(t, version, changed)
+		version = changed
+	})
+
+	t.Run("writes with the current version succeed and change the version", func(t *testing.T) {
+		require.NoError(t, ruleService.UpdateRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, 120, nil, version))
+		_, changed, err := ruleService.GetRuleGroupWithVersion(ctx, orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.NotEqual(t, version, changed)
+
+		require.NoError(t, ruleService.SetRuleGroupPaused(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, true, changed))
+	})
+
+	t.Run("writes with a stale version fail and change nothing", func(t *testing.T) {
+		err := ruleService.UpdateRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, 180, nil, version)
+		require.ErrorIs(t, err, ErrVersionConflict)
+		err = ruleService.SetRuleGroupPaused(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, false, version)
+		require.ErrorIs(t, err, ErrVersionConflict)
+		err = ruleService.SetRuleGroupOrder(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, []string{rule.UID}, version)
+		require.ErrorIs(t, err, ErrVersionConflict)
+		_, err = ruleService.DeleteRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup, models.ProvenanceAPI, true, version)
+		require.ErrorIs(t, err, ErrVersionConflict)
+
+		group, err := ruleService.GetRuleGroup(ctx, orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, int64(120), group.Interval)
+		require.Len(t, group.Rules, 2)
+		require.True(t, group.Rules[0].IsPaused)
+	})
+}
+
 func createAlertRuleService(t *testing.T) AlertRuleService {
 	t.Helper()
 	sqlStore := sqlstore.InitTestDB(t)
 	store := store.DBstore{
 		SQLStore:     sqlStore,
 		BaseInterval: time.Second * 10,
+		Logger:       log.NewNopLogger(),
 	}
 	return AlertRuleService{
 		ruleStore:              store,
 		provenanceStore:        store,
 		xact:                   sqlStore,
+		quotas:                 &fakeQuotaChecker{},
+		folderPolicies:         fakeFolderPolicyChecker{},
+		instances:              store,
+		silences:               fakeSilenceReader{},
+		datasources:            fakeDatasourceReader{},
+		plugins:                fakePluginReader{"prometheus": {JSONData: plugins.JSONData{ID: "prometheus", Backend: true, Alerting: true}}},
+		folders:                fakeDashboardReader{},
 		log:                    log.New("testing"),
 		baseIntervalSeconds:    10,
 		defaultIntervalSeconds: 60,
@@ -0,0 +1,517 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// AlertmanagerImportService imports the configuration of a standalone Prometheus Alertmanager, to ease the migration
+// to the Grafana Alertmanager. It creates the contact points, mute timings and notification policies with the
+// provisioning services, so they are validated and saved like the ones created with the API.
+type AlertmanagerImportService struct {
+	config        AMConfigStore
+	contactPoints *ContactPointService
+	muteTimings   *MuteTimingService
+	policies      *NotificationPolicyService
+	xact          TransactionManager
+	log           log.Logger
+}
+
+func NewAlertmanagerImportService(config AMConfigStore, contactPoints *ContactPointService, muteTimings *MuteTimingService,
+	policies *NotificationPolicyService, xact TransactionManager, log log.Logger) *AlertmanagerImportService {
+	return &AlertmanagerImportService{
+		config:        config,
+		contactPoints: contactPoints,
+		muteTimings:   muteTimings,
+		policies:      policies,
+		xact:          xact,
+		log:           log,
+	}
+}
+
+// Import converts the Alertmanager configuration and saves the result to the given org with the API provenance. The
+// receivers are created as contact points with the integrations that Grafana supports, and the mute time intervals as
+// mute timings. The routing tree replaces the notification policies of the org, unless one of its routes uses a
+// receiver that has no supported integration. Everything that is left out is reported in the result.
+// If a contact point or mute timing of the same name exists, ErrImportConflict is returned together with the result
+// and nothing is saved. With dryRun, the result is returned without saving anything.
+func (s *AlertmanagerImportService) Import(ctx context.Context, orgID int64, yml string, dryRun bool) (definitions.AlertmanagerImportResult, error) {
+	amConfig, err := config.Load(yml)
+	if err != nil {
+		return definitions.AlertmanagerImportResult{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	conv := &alertmanagerConverter{}
+	imported := conv.convert(amConfig)
+
+	result := definitions.AlertmanagerImportResult{
+		DryRun:        dryRun,
+		ContactPoints: make([]definitions.AlertmanagerImportContactPoint, 0, len(imported.receivers)),
+		MuteTimings:   make([]string, 0, len(imported.muteTimings)),
+		Unsupported:   conv.unsupported,
+	}
+	for _, receiver := range imported.receivers {
+		cp := definitions.AlertmanagerImportContactPoint{Name: receiver.name, Types: make([]string, 0, len(receiver.integrations))}
+		for i := range receiver.integrations {
+			integration := receiver.integrations[i]
+			if err := integration.Valid(s.contactPoints.encryptionService.GetDecryptedValue); err != nil {
+				return definitions.AlertmanagerImportResult{}, fmt.Errorf("%w: receiver '%s': %s", ErrValidation, receiver.name, err.Error())
+			}
+			cp.Types = append(cp.Types, integration.Type)
+		}
+		result.ContactPoints = append(result.ContactPoints, cp)
+	}
+	for _, mt := range imported.muteTimings {
+		if err := mt.Validate(); err != nil {
+			return definitions.AlertmanagerImportResult{}, fmt.Errorf("%w: mute time interval '%s': %s", ErrValidation, mt.Name, err.Error())
+		}
+		result.MuteTimings = append(result.MuteTimings, mt.Name)
+	}
+	if imported.tree != nil {
+		if err := imported.tree.Validate(); err != nil {
+			return definitions.AlertmanagerImportResult{}, fmt.Errorf("%w: route: %s", ErrValidation, err.Error())
+		}
+		result.PoliciesImported = true
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, s.config)
+	if err != nil {
+		return definitions.AlertmanagerImportResult{}, err
+	}
+	existing := make(map[string]struct{}, len(revision.cfg.AlertmanagerConfig.Receivers))
+	for _, receiver := range revision.cfg.AlertmanagerConfig.Receivers {
+		existing[receiver.Name] = struct{}{}
+	}
+	for _, cp := range result.ContactPoints {
+		if _, ok := existing[cp.Name]; ok {
+			result.Conflicts = append(result.Conflicts, fmt.Sprintf("contact point '%s' already exists", cp.Name))
+		}
+	}
+	existing = make(map[string]struct{}, len(revision.cfg.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		existing[mt.Name] = struct{}{}
+	}
+	for _, name := range result.MuteTimings {
+		if _, ok := existing[name]; ok {
+			result.Conflicts = append(result.Conflicts, fmt.Sprintf("mute timing '%s' already exists", name))
+		}
+	}
+	if len(result.Conflicts) > 0 {
+		return result, ErrImportConflict
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	err = s.xact.InTransaction(ctx, func(ctx context.Context) error {
+		for _, mt := range imported.muteTimings {
+			if _, err := s.muteTimings.CreateMuteTiming(ctx, mt, orgID); err != nil {
+				return fmt.Errorf("failed to create mute timing '%s': %w", mt.Name, err)
+			}
+		}
+		for _, receiver := range imported.receivers {
+			for _, integration := range receiver.integrations {
+				if _, err := s.contactPoints.CreateContactPoint(ctx, orgID, integration, models.ProvenanceAPI); err != nil {
+					return fmt.Errorf("failed to create contact point '%s': %w", receiver.name, err)
+				}
+			}
+		}
+		if imported.tree != nil {
+			if err := s.policies.UpdatePolicyTree(ctx, orgID, *imported.tree, models.ProvenanceAPI); err != nil {
+				return fmt.Errorf("failed to update the notification policies: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return definitions.AlertmanagerImportResult{}, err
+	}
+	return result, nil
+}
+
+type importedReceiver struct {
+	name         string
+	integrations []definitions.EmbeddedContactPoint
+}
+
+type importedConfig struct {
+	receivers   []importedReceiver
+	muteTimings []definitions.MuteTimeInterval
+	// tree is nil if the routing tree cannot be imported.
+	tree *definitions.Route
+}
+
+// alertmanagerConverter converts an Alertmanager configuration to Grafana objects, and collects the messages about
+// what cannot be converted.
+type alertmanagerConverter struct {
+	unsupported []string
+	// emailReported is true once the SMTP settings of email integrations are reported, they are reported only once.
+	emailReported bool
+}
+
+func (c *alertmanagerConverter) report(format string, args ...interface{}) {
+	c.unsupported = append(c.unsupported, fmt.Sprintf(format, args...))
+}
+
+func (c *alertmanagerConverter) convert(amConfig *config.Config) importedConfig {
+	var result importedConfig
+	names := make(map[string]struct{}, len(amConfig.Receivers))
+	for _, receiver := range amConfig.Receivers {
+		imported := c.convertReceiver(receiver)
+		if len(imported.integrations) == 0 {
+			c.report("receiver '%s': not imported because it has no supported integration", receiver.Name)
+			continue
+		}
+		names[receiver.Name] = struct{}{}
+		result.receivers = append(result.receivers, imported)
+	}
+
+	for _, mti := range amConfig.MuteTimeIntervals {
+		mt := definitions.MuteTimeInterval{
+			MuteTimeIntervalConfig: definitions.MuteTimeIntervalConfig{Name: mti.Name},
+			Provenance:             models.ProvenanceAPI,
+		}
+		for _, ti := range mti.TimeIntervals {
+			mt.TimeIntervals = append(mt.TimeIntervals, definitions.TimeInterval{
+				Times:       ti.Times,
+				Weekdays:    ti.Weekdays,
+				DaysOfMonth: ti.DaysOfMonth,
+				Months:      ti.Months,
+				Years:       ti.Years,
+			})
+		}
+		result.muteTimings = append(result.muteTimings, mt)
+	}
+
+	if amConfig.Route != nil {
+		tree := definitions.AsGrafanaRoute(amConfig.Route)
+		normalizeMatchers(tree)
+		missing := map[string]struct{}{}
+		collectMissingReceivers(tree, names, missing)
+		if len(missing) == 0 {
+			result.tree = tree
+		} else {
+			for _, name := range sortedKeys(missing) {
+				c.report("route: the notification policies are not imported because they use receiver '%s', which is not imported", name)
+			}
+		}
+	}
+
+	if len(amConfig.InhibitRules) > 0 {
+		c.report("inhibit_rules: inhibition rules are not supported")
+	}
+	if len(amConfig.Templates) > 0 {
+		c.report("templates: template files are not imported, create the templates used by the contact points as message templates")
+	}
+	return result
+}
+
+func (c *alertmanagerConverter) convertReceiver(receiver *config.Receiver) importedReceiver {
+	result := importedReceiver{name: receiver.Name}
+	add := func(typ string, notifier config.NotifierConfig, settings *simplejson.Json) {
+		result.integrations = append(result.integrations, definitions.EmbeddedContactPoint{
+			Name:                  receiver.Name,
+			Type:                  typ,
+			Settings:              settings,
+			DisableResolveMessage: !notifier.SendResolved(),
+		})
+	}
+
+	for i, cfg := range receiver.EmailConfigs {
+		where := fmt.Sprintf("receiver '%s': email_configs[%d]", receiver.Name, i)
+		if !c.emailReported {
+			c.emailReported = true
+			c.report("%s: the SMTP settings are not imported, emails are sent with the SMTP settings of Grafana", where)
+		}
+		settings := simplejson.New()
+		settings.Set("addresses", cfg.To)
+		settings.Set("singleEmail", true)
+		for header, value := range cfg.Headers {
+			switch header {
+			case "Subject":
+				if value != config.DefaultEmailSubject {
+					settings.Set("subject", value)
+				}
+			case "To", "From":
+			default:
+				c.report("%s: header '%s' is not supported", where, header)
+			}
+		}
+		if cfg.Text != "" {
+			settings.Set("message", cfg.Text)
+		}
+		if cfg.HTML != config.DefaultEmailConfig.HTML {
+			c.report("%s: html is not supported, emails use the template of Grafana", where)
+		}
+		add("email", cfg.NotifierConfig, settings)
+	}
+
+	for i, cfg := range receiver.SlackConfigs {
+		where := fmt.Sprintf("receiver '%s': slack_configs[%d]", receiver.Name, i)
+		c.reportHTTPConfig(where, cfg.HTTPConfig, false)
+		if cfg.APIURL == nil {
+			c.report("%s: not imported because api_url_file is not supported", where)
+			continue
+		}
+		defaults := config.DefaultSlackConfig
+		settings := simplejson.New()
+		settings.Set("url", cfg.APIURL.String())
+		setIfNotDefault(settings, "recipient", cfg.Channel, "")
+		setIfNotDefault(settings, "username", cfg.Username, defaults.Username)
+		setIfNotDefault(settings, "icon_emoji", cfg.IconEmoji, defaults.IconEmoji)
+		setIfNotDefault(settings, "icon_url", cfg.IconURL, defaults.IconURL)
+		setIfNotDefault(settings, "title", cfg.Title, defaults.Title)
+		setIfNotDefault(settings, "text", cfg.Text, defaults.Text)
+		c.reportIfNotDefault(where, "color", cfg.Color, defaults.Color)
+		c.reportIfNotDefault(where, "title_link", cfg.TitleLink, defaults.TitleLink)
+		c.reportIfNotDefault(where, "pretext", cfg.Pretext, defaults.Pretext)
+		c.reportIfNotDefault(where, "footer", cfg.Footer, defaults.Footer)
+		c.reportIfNotDefault(where, "fallback", cfg.Fallback, defaults.Fallback)
+		c.reportIfNotDefault(where, "callback_id", cfg.CallbackID, defaults.CallbackID)
+		c.reportIfNotDefault(where, "image_url", cfg.ImageURL, "")
+		c.reportIfNotDefault(where, "thumb_url", cfg.ThumbURL, "")
+		if len(cfg.Fields) > 0 {
+			c.report("%s: fields are not supported", where)
+		}
+		if len(cfg.Actions) > 0 {
+			c.report("%s: actions are not supported", where)
+		}
+		add("slack", cfg.NotifierConfig, settings)
+	}
+
+	for i, cfg := range receiver.PagerdutyConfigs {
+		where := fmt.Sprintf("receiver '%s': pagerduty_configs[%d]", receiver.Name, i)
+		c.reportHTTPConfig(where, cfg.HTTPConfig, false)
+		if cfg.RoutingKey == "" {
+			c.report("%s: not imported because only routing_key of the Events API v2 is supported", where)
+			continue
+		}
+		defaults := config.DefaultPagerdutyConfig
+		settings := simplejson.New()
+		settings.Set("integrationKey", string(cfg.RoutingKey))
+		setIfNotDefault(settings, "severity", cfg.Severity, "")
+		setIfNotDefault(settings, "class", cfg.Class, "")
+		setIfNotDefault(settings, "component", cfg.Component, "")
+		setIfNotDefault(settings, "group", cfg.Group, "")
+		setIfNotDefault(settings, "summary", cfg.Description, defaults.Description)
+		c.reportIfNotDefault(where, "client", cfg.Client, defaults.Client)
+		c.reportIfNotDefault(where, "client_url", cfg.ClientURL, defaults.ClientURL)
+		if len(cfg.Details) > 0 && !isDefaultPagerdutyDetails(cfg.Details) {
+			c.report("%s: details are not supported", where)
+		}
+		if len(cfg.Images) > 0 || len(cfg.Links) > 0 {
+			c.report("%s: images and links are not supported", where)
+		}
+		add("pagerduty", cfg.NotifierConfig, settings)
+	}
+
+	for i, cfg := range receiver.WebhookConfigs {
+		where := fmt.Sprintf("receiver '%s': webhook_configs[%d]", receiver.Name, i)
+		c.reportHTTPConfig(where, cfg.HTTPConfig, true)
+		settings := simplejson.New()
+		settings.Set("url", cfg.URL.String())
+		settings.Set("httpMethod", "POST")
+		if cfg.HTTPConfig != nil && cfg.HTTPConfig.BasicAuth != nil {
+			settings.Set("username", cfg.HTTPConfig.BasicAuth.Username)
+			settings.Set("password", string(cfg.HTTPConfig.BasicAuth.Password))
+		}
+		if cfg.MaxAlerts > 0 {
+			settings.Set("maxAlerts", cfg.MaxAlerts)
+		}
+		add("webhook", cfg.NotifierConfig, settings)
+	}
+
+	for i, cfg := range receiver.OpsGenieConfigs {
+		where := fmt.Sprintf("receiver '%s': opsgenie_configs[%d]", receiver.Name, i)
+		c.reportHTTPConfig(where, cfg.HTTPConfig, false)
+		defaults := config.DefaultOpsGenieConfig
+		settings := simplejson.New()
+		settings.Set("apiKey", string(cfg.APIKey))
+		if cfg.APIURL != nil {
+			settings.Set("apiUrl", strings.TrimSuffix(cfg.APIURL.String(), "/")+"/v2/alerts")
+		}
+		setIfNotDefault(settings, "message", cfg.Message, defaults.Message)
+		setIfNotDefault(settings, "description", cfg.Description, defaults.Description)
+		c.reportIfNotDefault(where, "source", cfg.Source, defaults.Source)
+		c.reportIfNotDefault(where, "entity", cfg.Entity, "")
+		c.reportIfNotDefault(where, "actions", cfg.Actions, "")
+		c.reportIfNotDefault(where, "tags", cfg.Tags, "")
+		c.reportIfNotDefault(where, "note", cfg.Note, "")
+		c.reportIfNotDefault(where, "priority", cfg.Priority, "")
+		if len(cfg.Details) > 0 {
+			c.report("%s: details are not supported", where)
+		}
+		if len(cfg.Responders) > 0 {
+			c.report("%s: responders are not supported", where)
+		}
+		add("opsgenie", cfg.NotifierConfig, settings)
+	}
+
+	for i, cfg := range receiver.VictorOpsConfigs {
+		where := fmt.Sprintf("receiver '%s': victorops_configs[%d]", receiver.Name, i)
+		c.reportHTTPConfig(where, cfg.HTTPConfig, false)
+		if cfg.APIURL == nil || cfg.APIKey == "" {
+			c.report("%s: not imported because api_key_file is not supported", where)
+			continue
+		}
+		defaults := config.DefaultVictorOpsConfig
+		settings := simplejson.New()
+		settings.Set("url", fmt.Sprintf("%s%s/%s", cfg.APIURL.String(), cfg.APIKey, cfg.RoutingKey))
+		setIfNotDefault(settings, "messageType", cfg.MessageType, defaults.MessageType)
+		c.reportIfNotDefault(where, "state_message", cfg.StateMessage, defaults.StateMessage)
+		c.reportIfNotDefault(where, "entity_display_name", cfg.EntityDisplayName, defaults.EntityDisplayName)
+		c.reportIfNotDefault(where, "monitoring_tool", cfg.MonitoringTool, defaults.MonitoringTool)
+		if len(cfg.CustomFields) > 0 {
+			c.report("%s: custom_fields are not supported", where)
+		}
+		add("victorops", cfg.NotifierConfig, settings)
+	}
+
+	for i, cfg := range receiver.PushoverConfigs {
+		where := fmt.Sprintf("receiver '%s': pushover_configs[%d]", receiver.Name, i)
+		c.reportHTTPConfig(where, cfg.HTTPConfig, false)
+		defaults := config.DefaultPushoverConfig
+		settings := simplejson.New()
+		settings.Set("userKey", string(cfg.UserKey))
+		settings.Set("apiToken", string(cfg.Token))
+		switch {
+		case cfg.Priority == defaults.Priority:
+			// The default of Alertmanager sends firing alerts with the emergency priority and resolved alerts
+			// with the normal priority.
+			settings.Set("priority", "2")
+			settings.Set("okPriority", "0")
+		case isInteger(cfg.Priority):
+			settings.Set("priority", cfg.Priority)
+		default:
+			c.report("%s: priority is not supported unless it is a number", where)
+		}
+		settings.Set("retry", strconv.FormatInt(int64(time.Duration(cfg.Retry).Seconds()), 10))
+		settings.Set("expire", strconv.FormatInt(int64(time.Duration(cfg.Expire).Seconds()), 10))
+		setIfNotDefault(settings, "message", cfg.Message, defaults.Message)
+		setIfNotDefault(settings, "sound", cfg.Sound, "")
+		c.reportIfNotDefault(where, "title", cfg.Title, defaults.Title)
+		c.reportIfNotDefault(where, "url", cfg.URL, defaults.URL)
+		c.reportIfNotDefault(where, "url_title", cfg.URLTitle, "")
+		add("pushover", cfg.NotifierConfig, settings)
+	}
+
+	if len(receiver.WechatConfigs) > 0 {
+		c.report("receiver '%s': wechat_configs are not supported", receiver.Name)
+	}
+	if len(receiver.SNSConfigs) > 0 {
+		c.report("receiver '%s': sns_configs are not supported", receiver.Name)
+	}
+	return result
+}
+
+func (c *alertmanagerConverter) reportIfNotDefault(where, field, value, def string) {
+	if value != def {
+		c.report("%s: %s is not supported", where, field)
+	}
+}
+
+// reportHTTPConfig reports the settings of the HTTP client that are not supported. Contact points use the HTTP client
+// of Grafana, only webhooks support basic authentication.
+func (c *alertmanagerConverter) reportHTTPConfig(where string, cfg *commoncfg.HTTPClientConfig, basicAuth bool) {
+	if cfg == nil {
+		return
+	}
+	if cfg.BasicAuth != nil && !basicAuth {
+		c.report("%s: http_config.basic_auth is not supported", where)
+	}
+	if cfg.BasicAuth != nil && basicAuth && cfg.BasicAuth.PasswordFile != "" {
+		c.report("%s: http_config.basic_auth.password_file is not supported", where)
+	}
+	if cfg.Authorization != nil || cfg.BearerToken != "" || cfg.BearerTokenFile != "" || cfg.OAuth2 != nil {
+		c.report("%s: http_config authorization is not supported", where)
+	}
+	if cfg.ProxyURL.URL != nil {
+		c.report("%s: http_config.proxy_url is not supported", where)
+	}
+	if cfg.TLSConfig != (commoncfg.TLSConfig{}) {
+		c.report("%s: http_config.tls_config is not supported", where)
+	}
+}
+
+func setIfNotDefault(settings *simplejson.Json, key, value, def string) {
+	if value != def {
+		settings.Set(key, value)
+	}
+}
+
+func isDefaultPagerdutyDetails(details map[string]string) bool {
+	if len(details) != len(config.DefaultPagerdutyDetails) {
+		return false
+	}
+	for k, v := range config.DefaultPagerdutyDetails {
+		if details[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func isInteger(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// normalizeMatchers replaces the deprecated match and match_re of the routes with object matchers, which are the
+// matchers the UI can edit.
+func normalizeMatchers(r *definitions.Route) {
+	names := make([]string, 0, len(r.Match))
+	for name := range r.Match {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r.ObjectMatchers = append(r.ObjectMatchers, &labels.Matcher{Type: labels.MatchEqual, Name: name, Value: r.Match[name]})
+	}
+	names = make([]string, 0, len(r.MatchRE))
+	for name := range r.MatchRE {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// The regular expressions are anchored when they are parsed.
+		value := strings.TrimSuffix(strings.TrimPrefix(r.MatchRE[name].String(), "^(?:"), ")$")
+		if m, err := labels.NewMatcher(labels.MatchRegexp, name, value); err == nil {
+			r.ObjectMatchers = append(r.ObjectMatchers, m)
+		}
+	}
+	r.Match = nil
+	r.MatchRE = nil
+	for _, child := range r.Routes {
+		normalizeMatchers(child)
+	}
+}
+
+func collectMissingReceivers(r *definitions.Route, receivers map[string]struct{}, missing map[string]struct{}) {
+	if _, ok := receivers[r.Receiver]; !ok && r.Receiver != "" {
+		missing[r.Receiver] = struct{}{}
+	}
+	for _, child := range r.Routes {
+		collectMissingReceivers(child, receivers, missing)
+	}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
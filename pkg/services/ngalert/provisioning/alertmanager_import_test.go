@@ -0,0 +1,208 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const testAlertmanagerConfig = `
+global:
+  smtp_smarthost: smtp.example.com:587
+  smtp_from: alertmanager@example.com
+  slack_api_url: https://hooks.slack.com/services/T00/B00/XXX
+route:
+  receiver: team-email
+  group_by: [alertname]
+  routes:
+    - receiver: team-slack
+      match:
+        team: frontend
+      match_re:
+        service: web|api
+      mute_time_intervals: [weekends]
+    - receiver: oncall
+      matchers: ['severity="critical"']
+      continue: true
+receivers:
+  - name: team-email
+    email_configs:
+      - to: team@example.com
+  - name: team-slack
+    slack_configs:
+      - channel: '#alerts'
+        send_resolved: true
+        actions:
+          - type: button
+            text: Silence
+            url: https://example.com
+  - name: oncall
+    pagerduty_configs:
+      - routing_key: abc
+        severity: critical
+    webhook_configs:
+      - url: https://example.com/hook
+        max_alerts: 10
+    wechat_configs:
+      - api_secret: secret
+        corp_id: corp
+        api_url: https://qyapi.weixin.qq.com/cgi-bin/
+mute_time_intervals:
+  - name: weekends
+    time_intervals:
+      - weekdays: [saturday, sunday]
+inhibit_rules:
+  - source_match:
+      severity: critical
+    target_match:
+      severity: warning
+    equal: [alertname]
+`
+
+func TestAlertmanagerImport(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	createSut := func() (*AlertmanagerImportService, *ContactPointService, *MuteTimingService, *NotificationPolicyService) {
+		amStore := newFakeAMConfigStore()
+		prov := NewFakeProvisioningStore()
+		xact := newNopTransactionManager()
+		cp := NewContactPointService(amStore, secretsService, prov, xact, log.NewNopLogger())
+		mt := NewMuteTimingService(amStore, prov, xact, log.NewNopLogger())
+		np := &NotificationPolicyService{
+			amStore:         amStore,
+			provenanceStore: prov,
+			historyStore:    newFakePolicyHistoryStore(),
+			ruleStore:       &fakeRuleReader{},
+			xact:            xact,
+			log:             log.NewNopLogger(),
+		}
+		return NewAlertmanagerImportService(amStore, cp, mt, np, xact, log.NewNopLogger()), cp, mt, np
+	}
+
+	t.Run("converts receivers, routes and mute time intervals", func(t *testing.T) {
+		sut, cps, mts, nps := createSut()
+
+		result, err := sut.Import(context.Background(), 1, testAlertmanagerConfig, false)
+		require.NoError(t, err)
+		require.False(t, result.DryRun)
+		require.Equal(t, []definitions.AlertmanagerImportContactPoint{
+			{Name: "team-email", Types: []string{"email"}},
+			{Name: "team-slack", Types: []string{"slack"}},
+			{Name: "oncall", Types: []string{"pagerduty", "webhook"}},
+		}, result.ContactPoints)
+		require.Equal(t, []string{"weekends"}, result.MuteTimings)
+		require.True(t, result.PoliciesImported)
+		require.Empty(t, result.Conflicts)
+		require.Equal(t, []string{
+			"receiver 'team-email': email_configs[0]: the SMTP settings are not imported, emails are sent with the SMTP settings of Grafana",
+			"receiver 'team-slack': slack_configs[0]: actions are not supported",
+			"receiver 'oncall': wechat_configs are not supported",
+			"inhibit_rules: inhibition rules are not supported",
+		}, result.Unsupported)
+
+		contactPoints, err := cps.GetContactPoints(context.Background(), 1)
+		require.NoError(t, err)
+		byName := map[string][]definitions.EmbeddedContactPoint{}
+		for _, cp := range contactPoints {
+			byName[cp.Name] = append(byName[cp.Name], cp)
+		}
+		require.Len(t, byName["team-email"], 1)
+		require.Equal(t, "team@example.com", byName["team-email"][0].Settings.Get("addresses").MustString())
+		require.True(t, byName["team-email"][0].DisableResolveMessage)
+		require.Len(t, byName["team-slack"], 1)
+		require.Equal(t, "#alerts", byName["team-slack"][0].Settings.Get("recipient").MustString())
+		require.False(t, byName["team-slack"][0].DisableResolveMessage)
+		require.Len(t, byName["oncall"], 2)
+
+		timing, err := mts.GetMuteTiming(context.Background(), "weekends", 1)
+		require.NoError(t, err)
+		require.Len(t, timing.TimeIntervals, 1)
+
+		tree, err := nps.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "team-email", tree.Receiver)
+		require.Len(t, tree.Routes, 2)
+		slack := tree.Routes[0]
+		require.Equal(t, "team-slack", slack.Receiver)
+		require.Nil(t, slack.Match)
+		require.Nil(t, slack.MatchRE)
+		matchers := make([]string, 0, len(slack.ObjectMatchers))
+		for _, m := range slack.ObjectMatchers {
+			matchers = append(matchers, m.String())
+		}
+		require.ElementsMatch(t, []string{`team="frontend"`, `service=~"web|api"`}, matchers)
+		require.Equal(t, []string{"weekends"}, slack.MuteTimeIntervals)
+		require.True(t, tree.Routes[1].Continue)
+	})
+
+	t.Run("dry run saves nothing", func(t *testing.T) {
+		sut, _, mts, _ := createSut()
+
+		result, err := sut.Import(context.Background(), 1, testAlertmanagerConfig, true)
+		require.NoError(t, err)
+		require.True(t, result.DryRun)
+		require.Len(t, result.ContactPoints, 3)
+
+		timings, err := mts.GetMuteTimings(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, timings)
+	})
+
+	t.Run("existing contact points and mute timings are conflicts", func(t *testing.T) {
+		sut, _, _, _ := createSut()
+		_, err := sut.Import(context.Background(), 1, testAlertmanagerConfig, false)
+		require.NoError(t, err)
+
+		result, err := sut.Import(context.Background(), 1, testAlertmanagerConfig, false)
+		require.ErrorIs(t, err, ErrImportConflict)
+		require.Equal(t, []string{
+			"contact point 'team-email' already exists",
+			"contact point 'team-slack' already exists",
+			"contact point 'oncall' already exists",
+			"mute timing 'weekends' already exists",
+		}, result.Conflicts)
+	})
+
+	t.Run("policies are not imported if a route uses a receiver that is not imported", func(t *testing.T) {
+		sut, _, _, nps := createSut()
+		yml := `
+route:
+  receiver: team-email
+  routes:
+    - receiver: blackhole
+      match:
+        severity: none
+receivers:
+  - name: blackhole
+  - name: team-email
+    webhook_configs:
+      - url: https://example.com/hook
+`
+
+		result, err := sut.Import(context.Background(), 1, yml, false)
+		require.NoError(t, err)
+		require.False(t, result.PoliciesImported)
+		require.Equal(t, []string{
+			"receiver 'blackhole': not imported because it has no supported integration",
+			"route: the notification policies are not imported because they use receiver 'blackhole', which is not imported",
+		}, result.Unsupported)
+
+		tree, err := nps.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "grafana-default-email", tree.Receiver)
+	})
+
+	t.Run("invalid configuration is a validation error", func(t *testing.T) {
+		sut, _, _, _ := createSut()
+
+		_, err := sut.Import(context.Background(), 1, "route: {}", false)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
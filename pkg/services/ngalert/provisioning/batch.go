@@ -0,0 +1,229 @@
+package provisioning
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// BatchService applies several changes to the contact points, message templates, mute timings and notification
+// policies of an organization as one. The changes are made with the provisioning services, so they are validated like
+// the changes made one by one, but the resulting configuration is saved once, in the same transaction as the
+// provenances of the changed objects. A batch that fails leaves nothing changed.
+type BatchService struct {
+	config        AMConfigStore
+	contactPoints *ContactPointService
+	templates     *TemplateService
+	muteTimings   *MuteTimingService
+	policies      *NotificationPolicyService
+	xact          TransactionManager
+	log           log.Logger
+}
+
+func NewBatchService(config AMConfigStore, contactPoints *ContactPointService, templates *TemplateService,
+	muteTimings *MuteTimingService, policies *NotificationPolicyService, xact TransactionManager, log log.Logger) *BatchService {
+	return &BatchService{
+		config:        config,
+		contactPoints: contactPoints,
+		templates:     templates,
+		muteTimings:   muteTimings,
+		policies:      policies,
+		xact:          xact,
+		log:           log,
+	}
+}
+
+// Apply applies the operations of the batch in order, with the API provenance. The error of the first operation that
+// fails is returned with its index, and nothing is saved. If the configuration is changed by someone else while the
+// batch is applied, store.ErrVersionLockedObjectNotFound is returned.
+func (s *BatchService) Apply(ctx context.Context, orgID int64, batch definitions.ProvisioningBatch) (definitions.ProvisioningBatchResult, error) {
+	result := definitions.ProvisioningBatchResult{ContactPoints: []definitions.EmbeddedContactPoint{}}
+	for i, op := range batch.Operations {
+		if err := validateBatchOperation(op); err != nil {
+			return definitions.ProvisioningBatchResult{}, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	// The services of the batch save to a staged configuration, which is saved once all operations are applied.
+	staged := &stagedAMConfigStore{store: s.config, orgID: orgID}
+	contactPoints, templates, muteTimings, policies := *s.contactPoints, *s.templates, *s.muteTimings, *s.policies
+	contactPoints.amStore = staged
+	templates.config = staged
+	muteTimings.config = staged
+	policies.amStore = staged
+
+	err := s.xact.InTransaction(ctx, func(ctx context.Context) error {
+		for i, op := range batch.Operations {
+			var err error
+			switch {
+			case op.ContactPoint != nil:
+				var cp *definitions.EmbeddedContactPoint
+				cp, err = applyContactPointOperation(ctx, &contactPoints, orgID, op.Action, *op.ContactPoint)
+				if cp != nil {
+					result.ContactPoints = append(result.ContactPoints, *cp)
+				}
+			case op.Template != nil:
+				err = applyTemplateOperation(ctx, &templates, orgID, op.Action, *op.Template)
+			case op.MuteTiming != nil:
+				err = applyMuteTimingOperation(ctx, &muteTimings, orgID, op.Action, *op.MuteTiming)
+			case op.Policies != nil:
+				err = policies.UpdatePolicyTree(ctx, orgID, *op.Policies, models.ProvenanceAPI)
+			}
+			if err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+		}
+		return staged.commit(ctx)
+	})
+	if err != nil {
+		return definitions.ProvisioningBatchResult{}, err
+	}
+	s.log.Debug("applied provisioning batch", "org", orgID, "operations", len(batch.Operations))
+	return result, nil
+}
+
+func validateBatchOperation(op definitions.ProvisioningBatchOperation) error {
+	objects := 0
+	for _, set := range []bool{op.ContactPoint != nil, op.Template != nil, op.MuteTiming != nil, op.Policies != nil} {
+		if set {
+			objects++
+		}
+	}
+	if objects != 1 {
+		return fmt.Errorf("%w: an operation must have exactly one object, it has %d", ErrValidation, objects)
+	}
+	switch op.Action {
+	case definitions.ProvisioningBatchCreate, definitions.ProvisioningBatchUpdate, definitions.ProvisioningBatchDelete:
+	default:
+		return fmt.Errorf("%w: unknown action '%s'", ErrValidation, op.Action)
+	}
+	if op.Policies != nil && op.Action != definitions.ProvisioningBatchUpdate {
+		return fmt.Errorf("%w: the notification policies can only be updated", ErrValidation)
+	}
+	return nil
+}
+
+func applyContactPointOperation(ctx context.Context, svc *ContactPointService, orgID int64, action definitions.ProvisioningBatchAction,
+	cp definitions.EmbeddedContactPoint) (*definitions.EmbeddedContactPoint, error) {
+	switch action {
+	case definitions.ProvisioningBatchCreate:
+		created, err := svc.CreateContactPoint(ctx, orgID, cp, models.ProvenanceAPI)
+		if err != nil {
+			return nil, err
+		}
+		return &created, nil
+	case definitions.ProvisioningBatchUpdate:
+		if err := svc.UpdateContactPoint(ctx, orgID, cp, models.ProvenanceAPI); err != nil {
+			return nil, err
+		}
+		keys, err := cp.SecretKeys()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			cp.Settings.Set(key, definitions.RedactedValue)
+		}
+		cp.Provenance = string(models.ProvenanceAPI)
+		return &cp, nil
+	default:
+		return nil, svc.DeleteContactPoint(ctx, orgID, cp.UID)
+	}
+}
+
+func applyTemplateOperation(ctx context.Context, svc *TemplateService, orgID int64, action definitions.ProvisioningBatchAction,
+	tmpl definitions.MessageTemplate) error {
+	if action == definitions.ProvisioningBatchDelete {
+		return svc.DeleteTemplate(ctx, orgID, tmpl.Name, models.ProvenanceAPI)
+	}
+	tmpl.Provenance = models.ProvenanceAPI
+	_, err := svc.SetTemplate(ctx, orgID, tmpl)
+	return err
+}
+
+func applyMuteTimingOperation(ctx context.Context, svc *MuteTimingService, orgID int64, action definitions.ProvisioningBatchAction,
+	mt definitions.MuteTimeInterval) error {
+	mt.Provenance = models.ProvenanceAPI
+	switch action {
+	case definitions.ProvisioningBatchCreate:
+		_, err := svc.CreateMuteTiming(ctx, mt, orgID)
+		return err
+	case definitions.ProvisioningBatchUpdate:
+		updated, err := svc.UpdateMuteTiming(ctx, mt, orgID)
+		if err != nil {
+			return err
+		}
+		if updated == nil {
+			return fmt.Errorf("%w: mute timing %q", ErrNotFound, mt.Name)
+		}
+		return nil
+	default:
+		return svc.DeleteMuteTiming(ctx, mt.Name, orgID, false)
+	}
+}
+
+// stagedAMConfigStore keeps the configurations saved to it in memory, on top of the latest configuration of an
+// organization in store. The last staged configuration is saved to store by commit.
+type stagedAMConfigStore struct {
+	store AMConfigStore
+	orgID int64
+	// latest is the staged configuration, it is nil until the configuration is first read.
+	latest *models.AlertConfiguration
+	// fetchedHash is the hash of the configuration read from store.
+	fetchedHash string
+	// changed is true if a configuration was saved to the staged store.
+	changed bool
+}
+
+func (s *stagedAMConfigStore) GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error {
+	if query.OrgID != s.orgID {
+		return fmt.Errorf("the staged configuration is the configuration of organization %d, not %d", s.orgID, query.OrgID)
+	}
+	if s.latest == nil {
+		q := models.GetLatestAlertmanagerConfigurationQuery{OrgID: s.orgID}
+		if err := s.store.GetLatestAlertmanagerConfiguration(ctx, &q); err != nil {
+			return err
+		}
+		if q.Result == nil {
+			return fmt.Errorf("no alertmanager configuration present in this org")
+		}
+		latest := *q.Result
+		s.latest = &latest
+		s.fetchedHash = latest.ConfigurationHash
+	}
+	result := *s.latest
+	query.Result = &result
+	return nil
+}
+
+func (s *stagedAMConfigStore) UpdateAlertmanagerConfiguration(_ context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	if cmd.OrgID != s.orgID || s.latest == nil || cmd.FetchedConfigurationHash != s.latest.ConfigurationHash {
+		return store.ErrVersionLockedObjectNotFound
+	}
+	s.latest.AlertmanagerConfiguration = cmd.AlertmanagerConfiguration
+	s.latest.ConfigurationHash = fmt.Sprintf("%x", md5.Sum([]byte(cmd.AlertmanagerConfiguration)))
+	s.latest.ConfigurationVersion = cmd.ConfigurationVersion
+	s.latest.Default = false
+	s.changed = true
+	return nil
+}
+
+// commit saves the staged configuration to store, if it was changed. It is only saved if the configuration in store
+// was not changed since it was read.
+func (s *stagedAMConfigStore) commit(ctx context.Context) error {
+	if !s.changed {
+		return nil
+	}
+	return s.store.UpdateAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: s.latest.AlertmanagerConfiguration,
+		FetchedConfigurationHash:  s.fetchedHash,
+		ConfigurationVersion:      s.latest.ConfigurationVersion,
+		Default:                   false,
+		OrgID:                     s.orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
+	})
+}
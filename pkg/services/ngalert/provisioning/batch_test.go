@@ -0,0 +1,110 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestBatchService(t *testing.T) {
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlstore.InitTestDB(t)))
+
+	createSut := func() (*BatchService, *countingAMConfigStore, *fakeProvisioningStore) {
+		config := &countingAMConfigStore{fakeAMConfigStore: newFakeAMConfigStore()}
+		prov := NewFakeProvisioningStore()
+		xact := newNopTransactionManager()
+		nopLog := log.NewNopLogger()
+		sut := NewBatchService(config,
+			NewContactPointService(config, secretsService, prov, xact, nopLog),
+			NewTemplateService(config, prov, xact, nil, nopLog),
+			NewMuteTimingService(config, prov, xact, nopLog),
+			NewNotificationPolicyService(config, prov, newFakePolicyHistoryStore(), &fakeRuleReader{}, xact, setting.UnifiedAlertingSettings{}, nopLog),
+			xact, nopLog)
+		return sut, config, prov
+	}
+
+	t.Run("saves all the changes at once", func(t *testing.T) {
+		sut, config, prov := createSut()
+		cp := createTestContactPoint()
+		cp.UID = "batch-cp"
+		tmpl := createMessageTemplate()
+		batch := definitions.ProvisioningBatch{Operations: []definitions.ProvisioningBatchOperation{
+			{Action: definitions.ProvisioningBatchCreate, ContactPoint: &cp},
+			{Action: definitions.ProvisioningBatchCreate, Template: &tmpl},
+			{Action: definitions.ProvisioningBatchUpdate, Policies: &definitions.Route{Receiver: cp.Name}},
+		}}
+
+		result, err := sut.Apply(context.Background(), 1, batch)
+
+		require.NoError(t, err)
+		require.Len(t, result.ContactPoints, 1)
+		require.Equal(t, "batch-cp", result.ContactPoints[0].UID)
+		require.Equal(t, 1, config.saves)
+		require.Equal(t, models.AlertConfigurationOriginProvisioning, config.lastSaveCommand.Origin)
+		require.Contains(t, config.lastSaveCommand.AlertmanagerConfiguration, "batch-cp")
+		require.Contains(t, config.lastSaveCommand.AlertmanagerConfiguration, tmpl.Template)
+		provenance, err := prov.GetProvenance(context.Background(), &cp, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+	})
+
+	t.Run("saves nothing if an operation fails", func(t *testing.T) {
+		sut, config, _ := createSut()
+		cp := createTestContactPoint()
+		batch := definitions.ProvisioningBatch{Operations: []definitions.ProvisioningBatchOperation{
+			{Action: definitions.ProvisioningBatchCreate, ContactPoint: &cp},
+			{Action: definitions.ProvisioningBatchUpdate, Policies: &definitions.Route{Receiver: "does not exist"}},
+		}}
+
+		_, err := sut.Apply(context.Background(), 1, batch)
+
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "operation 1")
+		require.Zero(t, config.saves)
+	})
+
+	t.Run("rejects invalid operations before applying any", func(t *testing.T) {
+		cp := createTestContactPoint()
+		tmpl := createMessageTemplate()
+		cases := map[string]definitions.ProvisioningBatchOperation{
+			"without object":        {Action: definitions.ProvisioningBatchCreate},
+			"with several objects":  {Action: definitions.ProvisioningBatchCreate, ContactPoint: &cp, Template: &tmpl},
+			"with unknown action":   {Action: "replace", Template: &tmpl},
+			"that creates policies": {Action: definitions.ProvisioningBatchCreate, Policies: &definitions.Route{Receiver: cp.Name}},
+		}
+		for name, op := range cases {
+			t.Run(name, func(t *testing.T) {
+				sut, config, _ := createSut()
+				batch := definitions.ProvisioningBatch{Operations: []definitions.ProvisioningBatchOperation{
+					{Action: definitions.ProvisioningBatchCreate, ContactPoint: &cp},
+					op,
+				}}
+
+				_, err := sut.Apply(context.Background(), 1, batch)
+
+				require.ErrorIs(t, err, ErrValidation)
+				require.ErrorContains(t, err, "operation 1")
+				require.Zero(t, config.saves)
+			})
+		}
+	})
+}
+
+type countingAMConfigStore struct {
+	*fakeAMConfigStore
+	saves int
+}
+
+func (c *countingAMConfigStore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	c.saves++
+	return c.fakeAMConfigStore.UpdateAlertmanagerConfiguration(ctx, cmd)
+}
@@ -0,0 +1,38 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var ErrConfigLimitExceeded = fmt.Errorf("the Alertmanager configuration exceeds a limit")
+
+// CheckConfigurationSize returns an ErrValidation wrapping ErrConfigLimitExceeded if the serialized configuration of
+// size bytes is larger than allowed by limits.
+func CheckConfigurationSize(size int, limits setting.UnifiedAlertingConfigLimits) error {
+	if limits.MaxSize <= 0 || int64(size) <= limits.MaxSize {
+		return nil
+	}
+	return validationError{fmt.Errorf("%w: the configuration is %d bytes, at most %d are allowed", ErrConfigLimitExceeded, size, limits.MaxSize)}
+}
+
+// NewSizeLimitedAMConfigStore returns an AMConfigStore that rejects configurations larger than the configuration
+// limits of their organization before they are saved to store.
+func NewSizeLimitedAMConfigStore(store AMConfigStore, settings setting.UnifiedAlertingSettings) AMConfigStore {
+	return &sizeLimitedAMConfigStore{AMConfigStore: store, settings: settings}
+}
+
+type sizeLimitedAMConfigStore struct {
+	AMConfigStore
+	settings setting.UnifiedAlertingSettings
+}
+
+func (s *sizeLimitedAMConfigStore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	if err := CheckConfigurationSize(len(cmd.AlertmanagerConfiguration), s.settings.ConfigLimitsForOrg(cmd.OrgID)); err != nil {
+		return err
+	}
+	return s.AMConfigStore.UpdateAlertmanagerConfiguration(ctx, cmd)
+}
@@ -0,0 +1,52 @@
+package provisioning
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCheckConfigurationSize(t *testing.T) {
+	t.Run("configurations within the limit are accepted", func(t *testing.T) {
+		require.NoError(t, CheckConfigurationSize(100, setting.UnifiedAlertingConfigLimits{MaxSize: 100}))
+	})
+
+	t.Run("limits of zero or less are not enforced", func(t *testing.T) {
+		require.NoError(t, CheckConfigurationSize(100, setting.UnifiedAlertingConfigLimits{}))
+		require.NoError(t, CheckConfigurationSize(100, setting.UnifiedAlertingConfigLimits{MaxSize: -1}))
+	})
+
+	t.Run("too large configurations are rejected", func(t *testing.T) {
+		err := CheckConfigurationSize(101, setting.UnifiedAlertingConfigLimits{MaxSize: 100})
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorIs(t, err, ErrConfigLimitExceeded)
+		require.Contains(t, err.Error(), "the configuration is 101 bytes, at most 100 are allowed")
+	})
+}
+
+func TestSizeLimitedAMConfigStore(t *testing.T) {
+	settings := setting.UnifiedAlertingSettings{
+		ConfigLimits:       setting.UnifiedAlertingConfigLimits{MaxSize: 10},
+		ConfigLimitsPerOrg: map[int64]setting.UnifiedAlertingConfigLimits{2: {}},
+	}
+	fake := newFakeAMConfigStore()
+	sut := NewSizeLimitedAMConfigStore(fake, settings)
+	large := strings.Repeat("x", 11)
+
+	t.Run("rejects configurations larger than the limits of their organization", func(t *testing.T) {
+		err := sut.UpdateAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{OrgID: 1, AlertmanagerConfiguration: large})
+		require.ErrorIs(t, err, ErrConfigLimitExceeded)
+		require.Nil(t, fake.lastSaveCommand)
+	})
+
+	t.Run("saves configurations within the limits of their organization", func(t *testing.T) {
+		err := sut.UpdateAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{OrgID: 2, AlertmanagerConfiguration: large})
+		require.NoError(t, err)
+		require.Equal(t, large, fake.lastSaveCommand.AlertmanagerConfiguration)
+	})
+}
@@ -0,0 +1,88 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// GetContactPointDuplicates returns the groups of contact points of the organization whose type and decrypted settings
+// are identical, so they could be consolidated into one. Contact points without duplicates are not returned.
+func (ecp *ContactPointService) GetContactPointDuplicates(ctx context.Context, orgID int64) (apimodels.ContactPointDuplicates, error) {
+	contactPoints, err := ecp.getContactPointsDecrypted(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string]*apimodels.ContactPointDuplicateGroup{}
+	keys := make([]string, 0)
+	for _, cp := range contactPoints {
+		key, err := contactPointFingerprint(cp)
+		if err != nil {
+			return nil, err
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &apimodels.ContactPointDuplicateGroup{Type: cp.Type}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.ContactPoints = append(group.ContactPoints, apimodels.ContactPointReference{UID: cp.UID, Name: cp.Name})
+	}
+
+	result := apimodels.ContactPointDuplicates{}
+	for _, key := range keys {
+		if group := groups[key]; len(group.ContactPoints) > 1 {
+			result = append(result, *group)
+		}
+	}
+	return result, nil
+}
+
+// GetContactPointDuplicatesOf returns the UIDs of the other contact points of the organization whose type and
+// decrypted settings are identical to the ones of the contact point with the given UID.
+func (ecp *ContactPointService) GetContactPointDuplicatesOf(ctx context.Context, orgID int64, uid string) ([]string, error) {
+	contactPoints, err := ecp.getContactPointsDecrypted(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints := make(map[string]string, len(contactPoints))
+	for _, cp := range contactPoints {
+		key, err := contactPointFingerprint(cp)
+		if err != nil {
+			return nil, err
+		}
+		fingerprints[cp.UID] = key
+	}
+	key, ok := fingerprints[uid]
+	if !ok {
+		return nil, fmt.Errorf("%w: contact point with uid '%s' not found", ErrNotFound, uid)
+	}
+
+	duplicates := make([]string, 0)
+	for other, otherKey := range fingerprints {
+		if other != uid && otherKey == key {
+			duplicates = append(duplicates, other)
+		}
+	}
+	sort.Strings(duplicates)
+	return duplicates, nil
+}
+
+// contactPointFingerprint returns a key that is equal for contact points that notify the same way. Names, UIDs and
+// provenance are ignored.
+func contactPointFingerprint(cp apimodels.EmbeddedContactPoint) (string, error) {
+	settings := []byte("null")
+	if cp.Settings != nil {
+		var err error
+		// the keys of the settings are sorted by the encoder
+		settings, err = cp.Settings.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s/%t/%s", cp.Type, cp.DisableResolveMessage, settings), nil
+}
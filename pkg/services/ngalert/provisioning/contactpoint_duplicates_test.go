@@ -0,0 +1,80 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func TestContactPointDuplicates(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	createContactPoints := func(t *testing.T, sut *ContactPointService) {
+		t.Helper()
+		for _, cp := range []struct{ uid, name, token string }{
+			{uid: "slack-1", name: "team-a", token: "token-1"},
+			{uid: "slack-2", name: "team-b", token: "token-1"},
+			{uid: "slack-3", name: "team-c", token: "token-2"},
+		} {
+			newCp := createTestContactPoint()
+			newCp.UID = cp.uid
+			newCp.Name = cp.name
+			newCp.Settings = simplejson.NewFromAny(map[string]interface{}{"recipient": "#alerts", "token": cp.token})
+			_, err := sut.CreateContactPoint(ctx, 1, newCp, models.ProvenanceAPI)
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("groups contact points with identical decrypted settings", func(t *testing.T) {
+		sut := createContactPointServiceSut(secretsService)
+		createContactPoints(t, sut)
+
+		duplicates, err := sut.GetContactPointDuplicates(ctx, 1)
+
+		require.NoError(t, err)
+		require.Equal(t, definitions.ContactPointDuplicates{
+			{
+				Type: "slack",
+				ContactPoints: []definitions.ContactPointReference{
+					{UID: "slack-1", Name: "team-a"},
+					{UID: "slack-2", Name: "team-b"},
+				},
+			},
+		}, duplicates)
+	})
+
+	t.Run("returns no groups without duplicates", func(t *testing.T) {
+		sut := createContactPointServiceSut(secretsService)
+
+		duplicates, err := sut.GetContactPointDuplicates(ctx, 1)
+
+		require.NoError(t, err)
+		require.Empty(t, duplicates)
+	})
+
+	t.Run("returns the duplicates of a contact point", func(t *testing.T) {
+		sut := createContactPointServiceSut(secretsService)
+		createContactPoints(t, sut)
+
+		duplicates, err := sut.GetContactPointDuplicatesOf(ctx, 1, "slack-2")
+		require.NoError(t, err)
+		require.Equal(t, []string{"slack-1"}, duplicates)
+
+		duplicates, err = sut.GetContactPointDuplicatesOf(ctx, 1, "slack-3")
+		require.NoError(t, err)
+		require.Empty(t, duplicates)
+
+		_, err = sut.GetContactPointDuplicatesOf(ctx, 1, "does not exist")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
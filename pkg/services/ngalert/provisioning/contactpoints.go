@@ -4,17 +4,23 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/prometheus/alertmanager/config"
 )
 
+// maxConfigSaveAttempts is the number of times a change to the contact points is applied to the latest Alertmanager
+// configuration when the configuration keeps being changed while it is saved.
+const maxConfigSaveAttempts = 3
+
 type ContactPointService struct {
 	amStore           AMConfigStore
 	encryptionService secrets.Service
@@ -39,7 +45,7 @@ func (ecp *ContactPointService) GetContactPoints(ctx context.Context, orgID int6
 	if err != nil {
 		return nil, err
 	}
-	provenances, err := ecp.provenanceStore.GetProvenances(ctx, orgID, "contactPoint")
+	provenances, err := ecp.provenanceStore.GetProvenances(ctx, orgID, (&apimodels.EmbeddedContactPoint{}).ResourceType())
 	if err != nil {
 		return nil, err
 	}
@@ -77,14 +83,27 @@ func (ecp *ContactPointService) GetContactPoints(ctx context.Context, orgID int6
 // getContactPointDecrypted is an internal-only function that gets full contact point info, included encrypted fields.
 // nil is returned if no matching contact point exists.
 func (ecp *ContactPointService) getContactPointDecrypted(ctx context.Context, orgID int64, uid string) (apimodels.EmbeddedContactPoint, error) {
-	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	contactPoints, err := ecp.getContactPointsDecrypted(ctx, orgID)
 	if err != nil {
 		return apimodels.EmbeddedContactPoint{}, err
 	}
-	for _, receiver := range revision.cfg.GetGrafanaReceiverMap() {
-		if receiver.UID != uid {
-			continue
+	for _, contactPoint := range contactPoints {
+		if contactPoint.UID == uid {
+			return contactPoint, nil
 		}
+	}
+	return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: contact point with uid '%s' not found", ErrNotFound, uid)
+}
+
+// getContactPointsDecrypted is an internal-only function that gets the full info of all contact points, included
+// encrypted fields, sorted by name.
+func (ecp *ContactPointService) getContactPointsDecrypted(ctx context.Context, orgID int64) ([]apimodels.EmbeddedContactPoint, error) {
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return nil, err
+	}
+	contactPoints := []apimodels.EmbeddedContactPoint{}
+	for _, receiver := range revision.cfg.GetGrafanaReceiverMap() {
 		embeddedContactPoint := apimodels.EmbeddedContactPoint{
 			UID:                   receiver.UID,
 			Type:                  receiver.Type,
@@ -103,9 +122,15 @@ func (ecp *ContactPointService) getContactPointDecrypted(ctx context.Context, or
 			}
 			embeddedContactPoint.Settings.Set(k, decryptedValue)
 		}
-		return embeddedContactPoint, nil
+		contactPoints = append(contactPoints, embeddedContactPoint)
 	}
-	return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: contact point with uid '%s' not found", ErrNotFound, uid)
+	sort.SliceStable(contactPoints, func(i, j int) bool {
+		if contactPoints[i].Name != contactPoints[j].Name {
+			return contactPoints[i].Name < contactPoints[j].Name
+		}
+		return contactPoints[i].UID < contactPoints[j].UID
+	})
+	return contactPoints, nil
 }
 
 func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID int64,
@@ -114,11 +139,6 @@ func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID in
 		return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
 
-	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
-	if err != nil {
-		return apimodels.EmbeddedContactPoint{}, err
-	}
-
 	extractedSecrets, err := contactPoint.ExtractSecrets()
 	if err != nil {
 		return apimodels.EmbeddedContactPoint{}, err
@@ -144,60 +164,42 @@ func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID in
 		SecureSettings:        extractedSecrets,
 	}
 
-	receiverFound := false
-	for _, receiver := range revision.cfg.AlertmanagerConfig.Receivers {
-		// check if uid is already used in receiver
-		for _, rec := range receiver.PostableGrafanaReceivers.GrafanaManagedReceivers {
-			if grafanaReceiver.UID == rec.UID {
-				return apimodels.EmbeddedContactPoint{}, fmt.Errorf(
-					"receiver configuration with UID '%s' already exist in contact point '%s'. Please use unique identifiers for receivers across all contact points",
-					rec.UID,
-					rec.Name)
+	err = ecp.updateConfiguration(ctx, orgID, func(cfg *apimodels.PostableUserConfig) error {
+		receiverFound := false
+		for _, receiver := range cfg.AlertmanagerConfig.Receivers {
+			// check if uid is already used in receiver
+			for _, rec := range receiver.PostableGrafanaReceivers.GrafanaManagedReceivers {
+				if grafanaReceiver.UID == rec.UID {
+					return fmt.Errorf(
+						"receiver configuration with UID '%s' already exist in contact point '%s'. Please use unique identifiers for receivers across all contact points",
+						rec.UID,
+						rec.Name)
+				}
+			}
+			if receiver.Name == contactPoint.Name {
+				receiver.PostableGrafanaReceivers.GrafanaManagedReceivers = append(receiver.PostableGrafanaReceivers.GrafanaManagedReceivers, grafanaReceiver)
+				receiverFound = true
 			}
 		}
-		if receiver.Name == contactPoint.Name {
-			receiver.PostableGrafanaReceivers.GrafanaManagedReceivers = append(receiver.PostableGrafanaReceivers.GrafanaManagedReceivers, grafanaReceiver)
-			receiverFound = true
-		}
-	}
-
-	if !receiverFound {
-		revision.cfg.AlertmanagerConfig.Receivers = append(revision.cfg.AlertmanagerConfig.Receivers, &apimodels.PostableApiReceiver{
-			Receiver: config.Receiver{
-				Name: grafanaReceiver.Name,
-			},
-			PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{
-				GrafanaManagedReceivers: []*apimodels.PostableGrafanaReceiver{grafanaReceiver},
-			},
-		})
-	}
-
-	data, err := json.Marshal(revision.cfg)
-	if err != nil {
-		return apimodels.EmbeddedContactPoint{}, err
-	}
 
-	err = ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = ecp.amStore.UpdateAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
-			AlertmanagerConfiguration: string(data),
-			FetchedConfigurationHash:  revision.concurrencyToken,
-			ConfigurationVersion:      revision.version,
-			Default:                   false,
-			OrgID:                     orgID,
-		})
-		if err != nil {
-			return err
+		if !receiverFound {
+			cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers, &apimodels.PostableApiReceiver{
+				Receiver: config.Receiver{
+					Name: grafanaReceiver.Name,
+				},
+				PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{
+					GrafanaManagedReceivers: []*apimodels.PostableGrafanaReceiver{grafanaReceiver},
+				},
+			})
 		}
-		err = ecp.provenanceStore.SetProvenance(ctx, &contactPoint, orgID, provenance)
-		if err != nil {
-			return err
-		}
-		contactPoint.Provenance = string(provenance)
 		return nil
+	}, func(ctx context.Context) error {
+		return ecp.provenanceStore.SetProvenance(ctx, &contactPoint, orgID, provenance)
 	})
 	if err != nil {
 		return apimodels.EmbeddedContactPoint{}, err
 	}
+	contactPoint.Provenance = string(provenance)
 	for k := range extractedSecrets {
 		contactPoint.Settings.Set(k, apimodels.RedactedValue)
 	}
@@ -258,89 +260,92 @@ func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID in
 		SecureSettings:        extractedSecrets,
 	}
 	// save to store
-	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
-	if err != nil {
-		return err
-	}
-
-	configModified := stitchReceiver(revision.cfg, mergedReceiver)
-	if !configModified {
-		return fmt.Errorf("contact point with uid '%s' not found", mergedReceiver.UID)
-	}
-
-	data, err := json.Marshal(revision.cfg)
-	if err != nil {
-		return err
-	}
-	return ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = ecp.amStore.UpdateAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
-			AlertmanagerConfiguration: string(data),
-			FetchedConfigurationHash:  revision.concurrencyToken,
-			ConfigurationVersion:      revision.version,
-			Default:                   false,
-			OrgID:                     orgID,
-		})
-		if err != nil {
-			return err
-		}
-		err = ecp.provenanceStore.SetProvenance(ctx, &contactPoint, orgID, provenance)
-		if err != nil {
-			return err
+	err = ecp.updateConfiguration(ctx, orgID, func(cfg *apimodels.PostableUserConfig) error {
+		if !stitchReceiver(cfg, mergedReceiver) {
+			return fmt.Errorf("contact point with uid '%s' not found", mergedReceiver.UID)
 		}
-		contactPoint.Provenance = string(provenance)
 		return nil
+	}, func(ctx context.Context) error {
+		return ecp.provenanceStore.SetProvenance(ctx, &contactPoint, orgID, provenance)
 	})
-}
-
-func (ecp *ContactPointService) DeleteContactPoint(ctx context.Context, orgID int64, uid string) error {
-	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
 	if err != nil {
 		return err
 	}
-	// Indicates if the full contact point is removed or just one of the
-	// configurations, as a contactpoint can consist of any number of
-	// configurations.
-	fullRemoval := false
-	// Name of the contact point that will be removed, might be used if a
-	// full removal is done to check if it's referenced in any route.
-	name := ""
-	for i, receiver := range revision.cfg.AlertmanagerConfig.Receivers {
-		for j, grafanaReceiver := range receiver.GrafanaManagedReceivers {
-			if grafanaReceiver.UID == uid {
-				name = grafanaReceiver.Name
-				receiver.GrafanaManagedReceivers = append(receiver.GrafanaManagedReceivers[:j], receiver.GrafanaManagedReceivers[j+1:]...)
-				// if this was the last receiver we removed, we remove the whole receiver
-				if len(receiver.GrafanaManagedReceivers) == 0 {
-					fullRemoval = true
-					revision.cfg.AlertmanagerConfig.Receivers = append(revision.cfg.AlertmanagerConfig.Receivers[:i], revision.cfg.AlertmanagerConfig.Receivers[i+1:]...)
+	contactPoint.Provenance = string(provenance)
+	return nil
+}
+
+func (ecp *ContactPointService) DeleteContactPoint(ctx context.Context, orgID int64, uid string) error {
+	return ecp.updateConfiguration(ctx, orgID, func(cfg *apimodels.PostableUserConfig) error {
+		// Indicates if the full contact point is removed or just one of the
+		// configurations, as a contactpoint can consist of any number of
+		// configurations.
+		fullRemoval := false
+		// Name of the contact point that will be removed, might be used if a
+		// full removal is done to check if it's referenced in any route.
+		name := ""
+		for i, receiver := range cfg.AlertmanagerConfig.Receivers {
+			for j, grafanaReceiver := range receiver.GrafanaManagedReceivers {
+				if grafanaReceiver.UID == uid {
+					name = grafanaReceiver.Name
+					receiver.GrafanaManagedReceivers = append(receiver.GrafanaManagedReceivers[:j], receiver.GrafanaManagedReceivers[j+1:]...)
+					// if this was the last receiver we removed, we remove the whole receiver
+					if len(receiver.GrafanaManagedReceivers) == 0 {
+						fullRemoval = true
+						cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers[:i], cfg.AlertmanagerConfig.Receivers[i+1:]...)
+					}
+					break
 				}
-				break
 			}
 		}
-	}
-	if fullRemoval && isContactPointInUse(name, []*apimodels.Route{revision.cfg.AlertmanagerConfig.Route}) {
-		return fmt.Errorf("contact point '%s' is currently used by a notification policy", name)
-	}
-	data, err := json.Marshal(revision.cfg)
-	if err != nil {
-		return err
-	}
-	return ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if fullRemoval && isContactPointInUse(name, []*apimodels.Route{cfg.AlertmanagerConfig.Route}) {
+			return fmt.Errorf("contact point '%s' is currently used by a notification policy", name)
+		}
+		return nil
+	}, func(ctx context.Context) error {
 		target := &apimodels.EmbeddedContactPoint{
 			UID: uid,
 		}
-		err := ecp.provenanceStore.DeleteProvenance(ctx, target, orgID)
+		return ecp.provenanceStore.DeleteProvenance(ctx, target, orgID)
+	})
+}
+
+// updateConfiguration applies change to the latest Alertmanager configuration of the organization, and saves the
+// configuration and the provenance changes of persist in a transaction. If the configuration was changed since it was
+// read, the change is applied again to the new configuration and saved, so changes to other parts of the configuration
+// made in the meantime are kept rather than failing the save. It gives up after maxConfigSaveAttempts attempts.
+func (ecp *ContactPointService) updateConfiguration(ctx context.Context, orgID int64, change func(cfg *apimodels.PostableUserConfig) error, persist func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
 		if err != nil {
 			return err
 		}
-		return ecp.amStore.UpdateAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
-			AlertmanagerConfiguration: string(data),
-			FetchedConfigurationHash:  revision.concurrencyToken,
-			ConfigurationVersion:      revision.version,
-			Default:                   false,
-			OrgID:                     orgID,
+		if err := change(revision.cfg); err != nil {
+			return err
+		}
+		data, err := json.Marshal(revision.cfg)
+		if err != nil {
+			return err
+		}
+		err = ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+			err := ecp.amStore.UpdateAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+				AlertmanagerConfiguration: string(data),
+				FetchedConfigurationHash:  revision.concurrencyToken,
+				ConfigurationVersion:      revision.version,
+				Default:                   false,
+				OrgID:                     orgID,
+				Origin:                    models.AlertConfigurationOriginProvisioning,
+			})
+			if err != nil {
+				return err
+			}
+			return persist(ctx)
 		})
-	})
+		if !errors.Is(err, store.ErrVersionLockedObjectNotFound) || attempt == maxConfigSaveAttempts {
+			return err
+		}
+		ecp.log.Debug("the Alertmanager configuration was changed since it was read, applying the change again", "org", orgID, "attempt", attempt)
+	}
 }
 
 func isContactPointInUse(name string, routes []*apimodels.Route) bool {
@@ -381,6 +386,7 @@ func (ecp *ContactPointService) encryptValue(value string) (string, error) {
 }
 
 // stitchReceiver modifies a receiver, target, in an alertmanager config. It modifies the given config in-place.
+// If the receiver group is renamed, the routes that use it are updated to the new name.
 // Returns true if the config was altered in any way, and false otherwise.
 func stitchReceiver(cfg *apimodels.PostableUserConfig, target *apimodels.PostableGrafanaReceiver) bool {
 	// Algorithm to fix up receivers. Receivers are very complex and depend heavily on internal consistency.
@@ -407,6 +413,9 @@ groupLoop:
 				// If we're renaming, we'll need to fix up the macro receiver group for consistency.
 				// Firstly, if we're the only receiver in the group, simply rename the group to match. Done!
 				if len(receiverGroup.GrafanaManagedReceivers) == 1 {
+					if route := cfg.AlertmanagerConfig.Route; route != nil {
+						repointRoutes(route, receiverGroup.Name, target.Name)
+					}
 					receiverGroup.Name = target.Name
 					receiverGroup.GrafanaManagedReceivers[i] = target
 					configModified = true
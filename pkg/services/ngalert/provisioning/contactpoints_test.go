@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/database"
 	"github.com/grafana/grafana/pkg/services/secrets/manager"
@@ -217,6 +218,68 @@ func TestContactPointService(t *testing.T) {
 		intercepted := fake.lastSaveCommand
 		require.Equal(t, expectedConcurrencyToken, intercepted.FetchedConfigurationHash)
 	})
+
+	t.Run("service applies the change again when the configuration changed since it was read", func(t *testing.T) {
+		sut := createContactPointServiceSut(secretsService)
+		fake := sut.amStore.(*fakeAMConfigStore)
+		// Another contact point is created while the first save is in flight.
+		conflicting := &conflictingAMConfigStore{fakeAMConfigStore: fake, conflicts: 1, concurrentChange: func(cfg *definitions.PostableUserConfig) {
+			cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers, &definitions.PostableApiReceiver{
+				Receiver: config.Receiver{Name: "concurrent"},
+				PostableGrafanaReceivers: definitions.PostableGrafanaReceivers{
+					GrafanaManagedReceivers: []*definitions.PostableGrafanaReceiver{{UID: "concurrent", Name: "concurrent", Type: "email", Settings: simplejson.NewFromAny(map[string]interface{}{"addresses": "test@test.com"})}},
+				},
+			})
+		}}
+		sut.amStore = conflicting
+
+		_, err := sut.CreateContactPoint(context.Background(), 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		cps, err := sut.GetContactPoints(context.Background(), 1)
+		require.NoError(t, err)
+		names := make([]string, 0, len(cps))
+		for _, cp := range cps {
+			names = append(names, cp.Name)
+		}
+		require.ElementsMatch(t, []string{"email receiver", "concurrent", "test-contact-point"}, names)
+	})
+
+	t.Run("service gives up when the configuration keeps changing", func(t *testing.T) {
+		sut := createContactPointServiceSut(secretsService)
+		conflicting := &conflictingAMConfigStore{fakeAMConfigStore: sut.amStore.(*fakeAMConfigStore), conflicts: maxConfigSaveAttempts, concurrentChange: func(*definitions.PostableUserConfig) {}}
+		sut.amStore = conflicting
+
+		_, err := sut.CreateContactPoint(context.Background(), 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.ErrorIs(t, err, store.ErrVersionLockedObjectNotFound)
+		require.Equal(t, 0, conflicting.conflicts)
+	})
+}
+
+// conflictingAMConfigStore fails the first conflicts saves like a configuration that was changed since it was read,
+// applying concurrentChange to the stored configuration each time.
+type conflictingAMConfigStore struct {
+	*fakeAMConfigStore
+	conflicts        int
+	concurrentChange func(cfg *definitions.PostableUserConfig)
+}
+
+func (f *conflictingAMConfigStore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	if f.conflicts == 0 {
+		return f.fakeAMConfigStore.UpdateAlertmanagerConfiguration(ctx, cmd)
+	}
+	f.conflicts--
+	cfg, err := deserializeAlertmanagerConfig([]byte(f.config.AlertmanagerConfiguration))
+	if err != nil {
+		return err
+	}
+	f.concurrentChange(cfg)
+	data, err := serializeAlertmanagerConfig(*cfg)
+	if err != nil {
+		return err
+	}
+	f.config.AlertmanagerConfiguration = string(data)
+	return store.ErrVersionLockedObjectNotFound
 }
 
 func TestContactPointInUse(t *testing.T) {
@@ -576,6 +639,26 @@ func TestStitchReceivers(t *testing.T) {
 			require.Equal(t, c.expCfg, cfg.AlertmanagerConfig)
 		})
 	}
+
+	t.Run("rename of a group repoints the routes that use it", func(t *testing.T) {
+		cfg := createTestConfigWithReceivers()
+		cfg.AlertmanagerConfig.Route = &definitions.Route{
+			Receiver: "receiver-1",
+			Routes: []*definitions.Route{
+				{Receiver: "receiver-2"},
+				{Receiver: "receiver-1", Routes: []*definitions.Route{{Receiver: "receiver-1"}}},
+			},
+		}
+
+		modified := stitchReceiver(cfg, &definitions.PostableGrafanaReceiver{UID: "abc", Name: "new-receiver", Type: "slack"})
+
+		require.True(t, modified)
+		route := cfg.AlertmanagerConfig.Route
+		require.Equal(t, "new-receiver", route.Receiver)
+		require.Equal(t, "receiver-2", route.Routes[0].Receiver)
+		require.Equal(t, "new-receiver", route.Routes[1].Receiver)
+		require.Equal(t, "new-receiver", route.Routes[1].Routes[0].Receiver)
+	})
 }
 
 func createTestConfigWithReceivers() *definitions.PostableUserConfig {
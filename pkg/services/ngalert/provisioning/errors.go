@@ -1,6 +1,71 @@
 package provisioning
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
 
 var ErrValidation = fmt.Errorf("invalid object specification")
 var ErrNotFound = fmt.Errorf("object not found")
+var ErrVersionConflict = fmt.Errorf("the configuration was changed since it was read")
+var ErrImportConflict = fmt.Errorf("the import conflicts with existing objects")
+var ErrQuotaReached = fmt.Errorf("quota has been exceeded")
+
+// validationError is an ErrValidation that keeps the error of the failed validation, so callers can inspect it.
+type validationError struct {
+	err error
+}
+
+func (e validationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrValidation, e.err)
+}
+
+func (e validationError) Unwrap() error {
+	return e.err
+}
+
+func (e validationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// RuleGroupsValidationError is an ErrValidation returned by an import of rule groups. It reports all the invalid
+// groups and rules of the import rather than only the first.
+type RuleGroupsValidationError struct {
+	Errors []error
+}
+
+func (e RuleGroupsValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(msgs, "; "))
+}
+
+func (e RuleGroupsValidationError) Is(target error) bool {
+	return target == ErrValidation || target == models.ErrAlertRuleFailedValidation
+}
+
+// MuteTimingInUseError is returned when a mute timing that is used by notification policies is deleted.
+type MuteTimingInUseError struct {
+	Name string
+	// Policies describes the notification policies using the mute timing.
+	Policies []string
+}
+
+func (e MuteTimingInUseError) Error() string {
+	return fmt.Sprintf("mute timing '%s' is currently used by %s", e.Name, strings.Join(e.Policies, ", "))
+}
+
+// RuleGroupInUseError is returned when a rule group whose rules have alerts that are not normal or silences is
+// deleted without force.
+type RuleGroupInUseError struct {
+	Deletion definitions.RuleGroupDeletion
+}
+
+func (e RuleGroupInUseError) Error() string {
+	return fmt.Sprintf("the rules of the group have %d alerts that are not normal and %d silences", len(e.Deletion.Alerts), len(e.Deletion.Silences))
+}
@@ -0,0 +1,452 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+// externalAlertmanagerConfigPath is the path of the configuration API of the Alertmanagers of Mimir and Cortex,
+// relative to the root of the URL of the Alertmanager.
+const externalAlertmanagerConfigPath = "/api/v1/alerts"
+
+// AdminConfigurationReader represents the ability to query the Alertmanagers that the organizations send their
+// alerts to.
+type AdminConfigurationReader interface {
+	GetAdminConfiguration(orgID int64) (*models.AdminConfiguration, error)
+}
+
+// ExternalAlertmanagerSyncService pushes the notification configuration of the organizations that send their alerts
+// to external Alertmanagers to these Alertmanagers, so that the contact points, templates, mute timings and
+// notification policies managed in Grafana are the ones that the external Alertmanagers use. The Grafana contact
+// points are translated to the receivers of the Prometheus Alertmanager, the integrations that cannot be translated
+// are left out and reported in the status of the organization.
+type ExternalAlertmanagerSyncService struct {
+	adminConfigs      AdminConfigurationReader
+	encryptionService secrets.Service
+	client            *http.Client
+	log               log.Logger
+
+	// pushMtx serializes the pushes, so that the external Alertmanagers end up with the last saved configuration.
+	pushMtx sync.Mutex
+	mtx     sync.RWMutex
+	// pending are the configurations to push, by organization.
+	pending  map[int64]string
+	statuses map[int64]definitions.ExternalAlertmanagerSyncStatus
+}
+
+func NewExternalAlertmanagerSyncService(adminConfigs AdminConfigurationReader, encryptionService secrets.Service, log log.Logger) *ExternalAlertmanagerSyncService {
+	return &ExternalAlertmanagerSyncService{
+		adminConfigs:      adminConfigs,
+		encryptionService: encryptionService,
+		client:            httpclient.NewOutboundClient(httpclient.OutboundOptions{Name: "alerting_external_alertmanagers", Timeout: 30 * time.Second}),
+		log:               log,
+		pending:           map[int64]string{},
+		statuses:          map[int64]definitions.ExternalAlertmanagerSyncStatus{},
+	}
+}
+
+// GetSyncStatus returns the status of the pushes of the configuration of the organization.
+func (s *ExternalAlertmanagerSyncService) GetSyncStatus(_ context.Context, orgID int64) (definitions.ExternalAlertmanagerSyncStatus, error) {
+	urls, err := s.externalAlertmanagers(orgID)
+	if err != nil {
+		return definitions.ExternalAlertmanagerSyncStatus{}, err
+	}
+	result := definitions.ExternalAlertmanagerSyncStatus{
+		Enabled:       len(urls) > 0,
+		Alertmanagers: []definitions.ExternalAlertmanagerSync{},
+		Unsupported:   []string{},
+	}
+	if !result.Enabled {
+		return result, nil
+	}
+	s.mtx.RLock()
+	status, ok := s.statuses[orgID]
+	s.mtx.RUnlock()
+	if ok {
+		result.Unsupported = append(result.Unsupported, status.Unsupported...)
+	}
+	// Only the Alertmanagers that the organization currently sends its alerts to are reported.
+	pushed := make(map[string]definitions.ExternalAlertmanagerSync, len(status.Alertmanagers))
+	for _, am := range status.Alertmanagers {
+		pushed[am.URL] = am
+	}
+	for _, u := range urls {
+		if am, ok := pushed[redactedURL(u)]; ok {
+			result.Alertmanagers = append(result.Alertmanagers, am)
+		}
+	}
+	return result, nil
+}
+
+// Sync pushes the serialized configuration of the organization to its external Alertmanagers, if it has any. The
+// configuration is pushed in the background and the failures are recorded in the status of the organization, so that
+// they never fail the change of the configuration in Grafana.
+func (s *ExternalAlertmanagerSyncService) Sync(orgID int64, rawConfig string) {
+	s.mtx.Lock()
+	s.pending[orgID] = rawConfig
+	s.mtx.Unlock()
+	go s.push(orgID)
+}
+
+// push pushes the last configuration passed to Sync for the organization, if it is not pushed yet.
+func (s *ExternalAlertmanagerSyncService) push(orgID int64) {
+	s.pushMtx.Lock()
+	defer s.pushMtx.Unlock()
+
+	s.mtx.Lock()
+	rawConfig, ok := s.pending[orgID]
+	delete(s.pending, orgID)
+	previous := s.statuses[orgID]
+	s.mtx.Unlock()
+	if !ok {
+		return
+	}
+
+	urls, err := s.externalAlertmanagers(orgID)
+	if err != nil {
+		s.log.Error("failed to get the external Alertmanagers", "org", orgID, "err", err)
+		return
+	}
+	if len(urls) == 0 {
+		return
+	}
+	cfg, err := deserializeAlertmanagerConfig([]byte(rawConfig))
+	if err != nil {
+		s.log.Error("failed to read the configuration for the external Alertmanagers", "org", orgID, "err", err)
+		return
+	}
+	conv := &externalAlertmanagerConverter{decrypt: s.decrypt}
+	body, err := conv.convert(context.Background(), cfg)
+	if err != nil {
+		s.log.Error("failed to translate the configuration for the external Alertmanagers", "org", orgID, "err", err)
+		return
+	}
+
+	lastSuccess := make(map[string]*time.Time, len(previous.Alertmanagers))
+	for _, am := range previous.Alertmanagers {
+		lastSuccess[am.URL] = am.LastSuccess
+	}
+	status := definitions.ExternalAlertmanagerSyncStatus{Enabled: true, Unsupported: conv.unsupported}
+	for _, u := range urls {
+		am := definitions.ExternalAlertmanagerSync{
+			URL:         redactedURL(u),
+			LastAttempt: time.Now().UTC(),
+		}
+		am.LastSuccess = lastSuccess[am.URL]
+		if err := s.pushTo(u, body); err != nil {
+			s.log.Error("failed to push the configuration to an external Alertmanager", "org", orgID, "alertmanager", am.URL, "err", err)
+			am.Error = err.Error()
+		} else {
+			am.LastSuccess = &am.LastAttempt
+		}
+		status.Alertmanagers = append(status.Alertmanagers, am)
+	}
+
+	s.mtx.Lock()
+	s.statuses[orgID] = status
+	s.mtx.Unlock()
+}
+
+func (s *ExternalAlertmanagerSyncService) pushTo(u *url.URL, body []byte) error {
+	target := *u
+	target.Path = externalAlertmanagerConfigPath
+	target.RawQuery = ""
+	req, err := http.NewRequest(http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.log.Warn("failed to close the response body of an external Alertmanager", "alertmanager", redactedURL(u), "err", err)
+		}
+	}()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// externalAlertmanagers returns the URLs of the Alertmanagers that the organization sends its alerts to, other than
+// the Alertmanager of Grafana.
+func (s *ExternalAlertmanagerSyncService) externalAlertmanagers(orgID int64) ([]*url.URL, error) {
+	cfg, err := s.adminConfigs.GetAdminConfiguration(orgID)
+	if errors.Is(err, store.ErrNoAdminConfiguration) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SendAlertsTo == models.InternalAlertmanager {
+		return nil, nil
+	}
+	urls := make([]*url.URL, 0, len(cfg.Alertmanagers))
+	for _, raw := range cfg.Alertmanagers {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Alertmanager URL: %w", err)
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+func (s *ExternalAlertmanagerSyncService) decrypt(ctx context.Context, receiver *definitions.PostableGrafanaReceiver, key string) (string, error) {
+	encrypted, ok := receiver.SecureSettings[key]
+	if !ok {
+		if receiver.Settings == nil {
+			return "", nil
+		}
+		return receiver.Settings.Get(key).MustString(), nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := s.encryptionService.Decrypt(ctx, decoded)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+func redactedURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	return redacted.String()
+}
+
+// NewExternalAlertmanagerSyncStore returns an AMConfigStore that pushes the configurations saved to store to the
+// external Alertmanagers of their organization with sync.
+func NewExternalAlertmanagerSyncStore(store AMConfigStore, sync *ExternalAlertmanagerSyncService) AMConfigStore {
+	return &externalAlertmanagerSyncStore{AMConfigStore: store, sync: sync}
+}
+
+type externalAlertmanagerSyncStore struct {
+	AMConfigStore
+	sync *ExternalAlertmanagerSyncService
+}
+
+func (s *externalAlertmanagerSyncStore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	if err := s.AMConfigStore.UpdateAlertmanagerConfiguration(ctx, cmd); err != nil {
+		return err
+	}
+	s.sync.Sync(cmd.OrgID, cmd.AlertmanagerConfiguration)
+	return nil
+}
+
+// externalAlertmanagerConfig is the configuration accepted by the configuration API of the Alertmanagers of Mimir
+// and Cortex.
+type externalAlertmanagerConfig struct {
+	TemplateFiles      map[string]string `yaml:"template_files,omitempty"`
+	AlertmanagerConfig string            `yaml:"alertmanager_config"`
+}
+
+// externalAlertmanagerConverter converts a Grafana Alertmanager configuration to the configuration of a Prometheus
+// Alertmanager, and collects the messages about what cannot be converted.
+type externalAlertmanagerConverter struct {
+	decrypt     func(ctx context.Context, receiver *definitions.PostableGrafanaReceiver, key string) (string, error)
+	unsupported []string
+}
+
+func (c *externalAlertmanagerConverter) report(format string, args ...interface{}) {
+	c.unsupported = append(c.unsupported, fmt.Sprintf(format, args...))
+}
+
+func (c *externalAlertmanagerConverter) convert(ctx context.Context, cfg *definitions.PostableUserConfig) ([]byte, error) {
+	amConfig := map[string]interface{}{}
+	if cfg.AlertmanagerConfig.Route != nil {
+		if hasActiveTimeIntervals(cfg.AlertmanagerConfig.Route) {
+			c.report("route: active time intervals are not supported")
+		}
+		amConfig["route"] = cfg.AlertmanagerConfig.Route.AsAMRoute()
+	}
+
+	receivers := make([]map[string]interface{}, 0, len(cfg.AlertmanagerConfig.Receivers))
+	for _, receiver := range cfg.AlertmanagerConfig.Receivers {
+		converted, err := c.convertReceiver(ctx, receiver)
+		if err != nil {
+			return nil, err
+		}
+		receivers = append(receivers, converted)
+	}
+	amConfig["receivers"] = receivers
+
+	if len(cfg.AlertmanagerConfig.MuteTimeIntervals) > 0 {
+		intervals := make([]config.MuteTimeInterval, 0, len(cfg.AlertmanagerConfig.MuteTimeIntervals))
+		for _, mti := range cfg.AlertmanagerConfig.MuteTimeIntervals {
+			interval := config.MuteTimeInterval{Name: mti.Name}
+			for _, ti := range mti.TimeIntervals {
+				if ti.Location != nil && ti.Location.Location != nil && ti.Location.Location != time.UTC {
+					c.report("mute timing '%s': the location is not supported, the times are in UTC", mti.Name)
+				}
+				interval.TimeIntervals = append(interval.TimeIntervals, timeinterval.TimeInterval{
+					Times:       ti.Times,
+					Weekdays:    ti.Weekdays,
+					DaysOfMonth: ti.DaysOfMonth,
+					Months:      ti.Months,
+					Years:       ti.Years,
+				})
+			}
+			intervals = append(intervals, interval)
+		}
+		amConfig["mute_time_intervals"] = intervals
+	}
+
+	templates := make([]string, 0, len(cfg.TemplateFiles))
+	for name := range cfg.TemplateFiles {
+		templates = append(templates, name)
+	}
+	sort.Strings(templates)
+	amConfig["templates"] = templates
+
+	raw, err := yaml.Marshal(amConfig)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(externalAlertmanagerConfig{TemplateFiles: cfg.TemplateFiles, AlertmanagerConfig: string(raw)})
+}
+
+func (c *externalAlertmanagerConverter) convertReceiver(ctx context.Context, receiver *definitions.PostableApiReceiver) (map[string]interface{}, error) {
+	result := map[string]interface{}{"name": receiver.Name}
+	add := func(field string, integration *definitions.PostableGrafanaReceiver, settings map[string]interface{}) {
+		settings["send_resolved"] = !integration.DisableResolveMessage
+		configs, _ := result[field].([]map[string]interface{})
+		result[field] = append(configs, settings)
+	}
+
+	for _, integration := range receiver.GrafanaManagedReceivers {
+		where := fmt.Sprintf("contact point '%s': integration '%s'", receiver.Name, integration.UID)
+		secret := func(key string) (string, error) {
+			value, err := c.decrypt(ctx, integration, key)
+			if err != nil {
+				return "", fmt.Errorf("%s: failed to decrypt '%s': %w", where, key, err)
+			}
+			return value, nil
+		}
+		settings := integration.Settings
+		if settings == nil {
+			settings = simplejson.New()
+		}
+
+		switch integration.Type {
+		case "slack":
+			apiURL, err := secret("url")
+			if err != nil {
+				return nil, err
+			}
+			if apiURL == "" {
+				c.report("%s: slack integrations that use a token are not supported", where)
+				continue
+			}
+			converted := map[string]interface{}{"api_url": apiURL}
+			setIfPresent(converted, "channel", settings.Get("recipient").MustString())
+			setIfPresent(converted, "username", settings.Get("username").MustString())
+			setIfPresent(converted, "icon_emoji", settings.Get("icon_emoji").MustString())
+			setIfPresent(converted, "icon_url", settings.Get("icon_url").MustString())
+			setIfPresent(converted, "title", settings.Get("title").MustString())
+			setIfPresent(converted, "text", settings.Get("text").MustString())
+			add("slack_configs", integration, converted)
+
+		case "pagerduty":
+			key, err := secret("integrationKey")
+			if err != nil {
+				return nil, err
+			}
+			converted := map[string]interface{}{
+				"routing_key": key,
+				"severity":    settings.Get("severity").MustString("critical"),
+				"class":       settings.Get("class").MustString("default"),
+				"component":   settings.Get("component").MustString("Grafana"),
+				"group":       settings.Get("group").MustString("default"),
+			}
+			setIfPresent(converted, "description", settings.Get("summary").MustString())
+			add("pagerduty_configs", integration, converted)
+
+		case "webhook":
+			if method := settings.Get("httpMethod").MustString(http.MethodPost); method != http.MethodPost {
+				c.report("%s: the %s method is not supported, webhooks are posted", where, method)
+				continue
+			}
+			converted := map[string]interface{}{"url": settings.Get("url").MustString()}
+			if username := settings.Get("username").MustString(); username != "" {
+				password, err := secret("password")
+				if err != nil {
+					return nil, err
+				}
+				converted["http_config"] = map[string]interface{}{
+					"basic_auth": map[string]interface{}{"username": username, "password": password},
+				}
+			}
+			if maxAlerts := settings.Get("maxAlerts").MustInt(0); maxAlerts > 0 {
+				converted["max_alerts"] = maxAlerts
+			}
+			add("webhook_configs", integration, converted)
+
+		case "opsgenie":
+			key, err := secret("apiKey")
+			if err != nil {
+				return nil, err
+			}
+			converted := map[string]interface{}{"api_key": key}
+			if apiURL := settings.Get("apiUrl").MustString(); apiURL != "" {
+				converted["api_url"] = strings.TrimSuffix(apiURL, "v2/alerts")
+			}
+			setIfPresent(converted, "message", settings.Get("message").MustString())
+			setIfPresent(converted, "description", settings.Get("description").MustString())
+			add("opsgenie_configs", integration, converted)
+
+		case "email":
+			c.report("%s: email integrations are not supported, the SMTP settings of Grafana are not pushed", where)
+
+		default:
+			c.report("%s: integrations of type '%s' are not supported", where, integration.Type)
+		}
+	}
+	return result, nil
+}
+
+func setIfPresent(settings map[string]interface{}, key, value string) {
+	if value != "" {
+		settings[key] = value
+	}
+}
+
+func hasActiveTimeIntervals(r *definitions.Route) bool {
+	if len(r.ActiveTimeIntervals) > 0 {
+		return true
+	}
+	for _, child := range r.Routes {
+		if hasActiveTimeIntervals(child) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func TestExternalAlertmanagerSync(t *testing.T) {
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlstore.InitTestDB(t)))
+	rawConfig := createExternalAlertmanagerTestConfig(t, secretsService)
+
+	t.Run("pushes the translated configuration to the external Alertmanagers", func(t *testing.T) {
+		am := newFakeExternalAlertmanager(t, http.StatusCreated)
+		adminConfigs := &fakeAdminConfigurationReader{configs: map[int64]*models.AdminConfiguration{
+			1: {OrgID: 1, Alertmanagers: []string{am.URL + "/alertmanager"}, SendAlertsTo: models.ExternalAlertmanagers},
+		}}
+		sut := NewExternalAlertmanagerSyncService(adminConfigs, secretsService, log.NewNopLogger())
+		amStore := NewExternalAlertmanagerSyncStore(newFakeAMConfigStore(), sut)
+
+		err := amStore.UpdateAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{OrgID: 1, AlertmanagerConfiguration: rawConfig})
+		require.NoError(t, err)
+
+		status := waitForExternalAlertmanagerSync(t, sut, 1)
+		require.True(t, status.Enabled)
+		require.Equal(t, am.URL+"/alertmanager", status.Alertmanagers[0].URL)
+		require.Empty(t, status.Alertmanagers[0].Error)
+		require.NotNil(t, status.Alertmanagers[0].LastSuccess)
+		require.Equal(t, []string{
+			"contact point 'team': integration 'email-uid': email integrations are not supported, the SMTP settings of Grafana are not pushed",
+		}, status.Unsupported)
+
+		require.Equal(t, "/api/v1/alerts", am.path())
+		var pushed externalAlertmanagerConfig
+		require.NoError(t, yaml.Unmarshal(am.body(), &pushed))
+		require.Equal(t, map[string]string{"team.tmpl": `{{ define "team.title" }}Alerts{{ end }}`}, pushed.TemplateFiles)
+		amConfig, err := config.Load(pushed.AlertmanagerConfig)
+		require.NoError(t, err)
+		require.Equal(t, "team", amConfig.Route.Receiver)
+		require.Equal(t, []string{"weekends"}, amConfig.Route.Routes[0].MuteTimeIntervals)
+		require.Equal(t, "weekends", amConfig.MuteTimeIntervals[0].Name)
+		require.Equal(t, []string{"team.tmpl"}, amConfig.Templates)
+		require.Len(t, amConfig.Receivers, 1)
+		require.Len(t, amConfig.Receivers[0].EmailConfigs, 0)
+		require.Equal(t, "https://hooks.slack.com/services/secret", amConfig.Receivers[0].SlackConfigs[0].APIURL.String())
+		require.Equal(t, "#alerts", amConfig.Receivers[0].SlackConfigs[0].Channel)
+		require.False(t, amConfig.Receivers[0].SlackConfigs[0].SendResolved())
+	})
+
+	t.Run("reports the failed pushes", func(t *testing.T) {
+		am := newFakeExternalAlertmanager(t, http.StatusBadRequest)
+		adminConfigs := &fakeAdminConfigurationReader{configs: map[int64]*models.AdminConfiguration{
+			1: {OrgID: 1, Alertmanagers: []string{am.URL}, SendAlertsTo: models.AllAlertmanagers},
+		}}
+		sut := NewExternalAlertmanagerSyncService(adminConfigs, secretsService, log.NewNopLogger())
+
+		sut.Sync(1, rawConfig)
+
+		status := waitForExternalAlertmanagerSync(t, sut, 1)
+		require.Contains(t, status.Alertmanagers[0].Error, "unexpected status 400")
+		require.Nil(t, status.Alertmanagers[0].LastSuccess)
+	})
+
+	t.Run("does not push for the organizations that only use the Grafana Alertmanager", func(t *testing.T) {
+		am := newFakeExternalAlertmanager(t, http.StatusCreated)
+		adminConfigs := &fakeAdminConfigurationReader{configs: map[int64]*models.AdminConfiguration{
+			1: {OrgID: 1, Alertmanagers: []string{am.URL}, SendAlertsTo: models.InternalAlertmanager},
+		}}
+		sut := NewExternalAlertmanagerSyncService(adminConfigs, secretsService, log.NewNopLogger())
+
+		sut.Sync(1, rawConfig)
+		sut.Sync(2, rawConfig)
+
+		// The pushes are done with the lock held, so the configurations are handled once it is acquired after them.
+		require.Eventually(t, func() bool {
+			sut.pushMtx.Lock()
+			defer sut.pushMtx.Unlock()
+			sut.mtx.RLock()
+			defer sut.mtx.RUnlock()
+			return len(sut.pending) == 0
+		}, time.Second, 10*time.Millisecond)
+		require.Nil(t, am.body())
+		status, err := sut.GetSyncStatus(context.Background(), 1)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+	})
+}
+
+func createExternalAlertmanagerTestConfig(t *testing.T, secretsService secrets.Service) string {
+	t.Helper()
+	encrypted, err := secretsService.Encrypt(context.Background(), []byte("https://hooks.slack.com/services/secret"), secrets.WithoutScope())
+	require.NoError(t, err)
+	slackSettings := simplejson.New()
+	slackSettings.Set("recipient", "#alerts")
+	emailSettings := simplejson.New()
+	emailSettings.Set("addresses", "team@example.com")
+
+	cfg := definitions.PostableUserConfig{
+		TemplateFiles: map[string]string{"team.tmpl": `{{ define "team.title" }}Alerts{{ end }}`},
+	}
+	cfg.AlertmanagerConfig.Route = &definitions.Route{
+		Receiver: "team",
+		Routes:   []*definitions.Route{{Receiver: "team", MuteTimeIntervals: []string{"weekends"}}},
+	}
+	cfg.AlertmanagerConfig.MuteTimeIntervals = []definitions.MuteTimeIntervalConfig{{Name: "weekends"}}
+	cfg.AlertmanagerConfig.Templates = []string{"team.tmpl"}
+	cfg.AlertmanagerConfig.Receivers = []*definitions.PostableApiReceiver{{
+		Receiver: config.Receiver{Name: "team"},
+		PostableGrafanaReceivers: definitions.PostableGrafanaReceivers{GrafanaManagedReceivers: []*definitions.PostableGrafanaReceiver{
+			{UID: "slack-uid", Name: "team", Type: "slack", DisableResolveMessage: true, Settings: slackSettings,
+				SecureSettings: map[string]string{"url": base64.StdEncoding.EncodeToString(encrypted)}},
+			{UID: "email-uid", Name: "team", Type: "email", Settings: emailSettings},
+		}},
+	}}
+	raw, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	return string(raw)
+}
+
+func waitForExternalAlertmanagerSync(t *testing.T, sut *ExternalAlertmanagerSyncService, orgID int64) definitions.ExternalAlertmanagerSyncStatus {
+	t.Helper()
+	var status definitions.ExternalAlertmanagerSyncStatus
+	require.Eventually(t, func() bool {
+		var err error
+		status, err = sut.GetSyncStatus(context.Background(), orgID)
+		require.NoError(t, err)
+		return len(status.Alertmanagers) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	return status
+}
+
+type fakeAdminConfigurationReader struct {
+	configs map[int64]*models.AdminConfiguration
+}
+
+func (f *fakeAdminConfigurationReader) GetAdminConfiguration(orgID int64) (*models.AdminConfiguration, error) {
+	cfg, ok := f.configs[orgID]
+	if !ok {
+		return nil, store.ErrNoAdminConfiguration
+	}
+	return cfg, nil
+}
+
+// fakeExternalAlertmanager records the last configuration posted to it, and answers with status.
+type fakeExternalAlertmanager struct {
+	*httptest.Server
+	mtx      sync.Mutex
+	lastPath string
+	lastBody []byte
+}
+
+func newFakeExternalAlertmanager(t *testing.T, status int) *fakeExternalAlertmanager {
+	am := &fakeExternalAlertmanager{}
+	am.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		am.mtx.Lock()
+		am.lastPath, am.lastBody = r.URL.Path, body
+		am.mtx.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(am.Close)
+	return am
+}
+
+func (am *fakeExternalAlertmanager) path() string {
+	am.mtx.Lock()
+	defer am.mtx.Unlock()
+	return am.lastPath
+}
+
+func (am *fakeExternalAlertmanager) body() []byte {
+	am.mtx.Lock()
+	defer am.mtx.Unlock()
+	return am.lastBody
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
-	"github.com/prometheus/alertmanager/config"
 )
 
+// fileProvisioningAPIVersion is the version of the file provisioning format produced by the exports.
+const fileProvisioningAPIVersion = 1
+
 type MuteTimingService struct {
 	config AMConfigStore
 	prov   ProvisioningStore
@@ -37,13 +39,57 @@ func (svc *MuteTimingService) GetMuteTimings(ctx context.Context, orgID int64) (
 		return []definitions.MuteTimeInterval{}, nil
 	}
 
+	provenances, err := svc.prov.GetProvenances(ctx, orgID, (&definitions.MuteTimeInterval{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]definitions.MuteTimeInterval, 0, len(rev.cfg.AlertmanagerConfig.MuteTimeIntervals))
 	for _, interval := range rev.cfg.AlertmanagerConfig.MuteTimeIntervals {
-		result = append(result, definitions.MuteTimeInterval{MuteTimeInterval: interval})
+		result = append(result, definitions.MuteTimeInterval{
+			MuteTimeIntervalConfig: interval,
+			Provenance:             provenances[interval.Name],
+		})
 	}
 	return result, nil
 }
 
+// GetMuteTiming returns the mute timing with the given name within the specified org. ErrNotFound is returned if
+// the mute timing does not exist.
+func (svc *MuteTimingService) GetMuteTiming(ctx context.Context, name string, orgID int64) (definitions.MuteTimeInterval, error) {
+	timings, err := svc.GetMuteTimings(ctx, orgID)
+	if err != nil {
+		return definitions.MuteTimeInterval{}, err
+	}
+	for _, timing := range timings {
+		if timing.Name == name {
+			return timing, nil
+		}
+	}
+	return definitions.MuteTimeInterval{}, fmt.Errorf("%w: mute timing %q", ErrNotFound, name)
+}
+
+// ExportMuteTimings returns all mute timings within the specified org as a file provisioning document, so they
+// can be managed as files instead.
+func (svc *MuteTimingService) ExportMuteTimings(ctx context.Context, orgID int64) (definitions.MuteTimingsExport, error) {
+	timings, err := svc.GetMuteTimings(ctx, orgID)
+	if err != nil {
+		return definitions.MuteTimingsExport{}, err
+	}
+
+	export := definitions.MuteTimingsExport{
+		APIVersion: fileProvisioningAPIVersion,
+		MuteTimes:  make([]definitions.MuteTimingExportEntry, 0, len(timings)),
+	}
+	for _, timing := range timings {
+		export.MuteTimes = append(export.MuteTimes, definitions.MuteTimingExportEntry{
+			OrgID:                  orgID,
+			MuteTimeIntervalConfig: timing.MuteTimeIntervalConfig,
+		})
+	}
+	return export, nil
+}
+
 // CreateMuteTiming adds a new mute timing within the specified org. The created mute timing is returned.
 func (svc *MuteTimingService) CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error) {
 	if err := mt.Validate(); err != nil {
@@ -56,14 +102,14 @@ func (svc *MuteTimingService) CreateMuteTiming(ctx context.Context, mt definitio
 	}
 
 	if revision.cfg.AlertmanagerConfig.MuteTimeIntervals == nil {
-		revision.cfg.AlertmanagerConfig.MuteTimeIntervals = []config.MuteTimeInterval{}
+		revision.cfg.AlertmanagerConfig.MuteTimeIntervals = []definitions.MuteTimeIntervalConfig{}
 	}
 	for _, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
 		if mt.Name == existing.Name {
 			return nil, fmt.Errorf("%w: %s", ErrValidation, "a mute timing with this name already exists")
 		}
 	}
-	revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(revision.cfg.AlertmanagerConfig.MuteTimeIntervals, mt.MuteTimeInterval)
+	revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(revision.cfg.AlertmanagerConfig.MuteTimeIntervals, mt.MuteTimeIntervalConfig)
 
 	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
 	if err != nil {
@@ -75,6 +121,7 @@ func (svc *MuteTimingService) CreateMuteTiming(ctx context.Context, mt definitio
 		FetchedConfigurationHash:  revision.concurrencyToken,
 		Default:                   false,
 		OrgID:                     orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
 	}
 	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err = svc.config.UpdateAlertmanagerConfiguration(ctx, &cmd)
@@ -111,7 +158,7 @@ func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitio
 	updated := false
 	for i, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
 		if mt.Name == existing.Name {
-			revision.cfg.AlertmanagerConfig.MuteTimeIntervals[i] = mt.MuteTimeInterval
+			revision.cfg.AlertmanagerConfig.MuteTimeIntervals[i] = mt.MuteTimeIntervalConfig
 			updated = true
 			break
 		}
@@ -120,6 +167,15 @@ func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitio
 		return nil, nil
 	}
 
+	// check that provenance is not changed in a invalid way
+	storedProvenance, err := svc.prov.GetProvenance(ctx, &mt, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if storedProvenance != mt.Provenance && storedProvenance != models.ProvenanceNone {
+		return nil, fmt.Errorf("cannot changed provenance from '%s' to '%s'", storedProvenance, mt.Provenance)
+	}
+
 	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
 	if err != nil {
 		return nil, err
@@ -130,6 +186,7 @@ func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitio
 		FetchedConfigurationHash:  revision.concurrencyToken,
 		Default:                   false,
 		OrgID:                     orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
 	}
 	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err = svc.config.UpdateAlertmanagerConfiguration(ctx, &cmd)
@@ -150,7 +207,9 @@ func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitio
 }
 
 // DeleteMuteTiming deletes the mute timing with the given name in the given org. If the mute timing does not exist, no error is returned.
-func (svc *MuteTimingService) DeleteMuteTiming(ctx context.Context, name string, orgID int64) error {
+// A mute timing that is used by notification policies is only deleted if force is set, the policies stop using it then.
+// Otherwise a MuteTimingInUseError is returned.
+func (svc *MuteTimingService) DeleteMuteTiming(ctx context.Context, name string, orgID int64, force bool) error {
 	revision, err := getLastConfiguration(ctx, orgID, svc.config)
 	if err != nil {
 		return err
@@ -159,8 +218,19 @@ func (svc *MuteTimingService) DeleteMuteTiming(ctx context.Context, name string,
 	if revision.cfg.AlertmanagerConfig.MuteTimeIntervals == nil {
 		return nil
 	}
-	if isMuteTimeInUse(name, []*definitions.Route{revision.cfg.AlertmanagerConfig.Route}) {
-		return fmt.Errorf("mute time '%s' is currently used by a notification policy", name)
+	if routes := routesUsingMuteTiming(name, revision.cfg.AlertmanagerConfig.Route); len(routes) > 0 {
+		if !force {
+			inUse := MuteTimingInUseError{Name: name}
+			for _, r := range routes {
+				inUse.Policies = append(inUse.Policies, describeRoute(r))
+			}
+			return inUse
+		}
+		for _, r := range routes {
+			r.MuteTimeIntervals = removeName(r.MuteTimeIntervals, name)
+			r.ActiveTimeIntervals = removeName(r.ActiveTimeIntervals, name)
+		}
+		svc.log.Info("removing mute timing from notification policies", "name", name, "policies", len(routes), "orgID", orgID)
 	}
 	for i, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
 		if name == existing.Name {
@@ -179,13 +249,14 @@ func (svc *MuteTimingService) DeleteMuteTiming(ctx context.Context, name string,
 		FetchedConfigurationHash:  revision.concurrencyToken,
 		Default:                   false,
 		OrgID:                     orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
 	}
 	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err = svc.config.UpdateAlertmanagerConfiguration(ctx, &cmd)
 		if err != nil {
 			return err
 		}
-		target := definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: name}}
+		target := definitions.MuteTimeInterval{MuteTimeIntervalConfig: definitions.MuteTimeIntervalConfig{Name: name}}
 		err := svc.prov.DeleteProvenance(ctx, &target, orgID)
 		if err != nil {
 			return err
@@ -194,19 +265,127 @@ func (svc *MuteTimingService) DeleteMuteTiming(ctx context.Context, name string,
 	})
 }
 
-func isMuteTimeInUse(name string, routes []*definitions.Route) bool {
-	if len(routes) == 0 {
-		return false
+// RenameMuteTiming renames the mute timing with the given name within the specified org. The notification policies
+// that use the mute timing are changed to use the new name, in the same configuration save, so that they keep working.
+// ErrNotFound is returned if the mute timing does not exist. The renamed mute timing is returned.
+func (svc *MuteTimingService) RenameMuteTiming(ctx context.Context, name, newName string, orgID int64, p models.Provenance) (*definitions.MuteTimeInterval, error) {
+	if newName == "" {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, "missing name")
 	}
-	for _, route := range routes {
-		for _, mtName := range route.MuteTimeIntervals {
-			if mtName == name {
-				return true
-			}
+
+	revision, err := getLastConfiguration(ctx, orgID, svc.config)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		if existing.Name == name {
+			index = i
+		}
+		if existing.Name == newName && newName != name {
+			return nil, fmt.Errorf("%w: %s", ErrValidation, "a mute timing with this name already exists")
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("%w: mute timing %q", ErrNotFound, name)
+	}
+
+	old := definitions.MuteTimeInterval{MuteTimeIntervalConfig: revision.cfg.AlertmanagerConfig.MuteTimeIntervals[index]}
+	// check that provenance is not changed in a invalid way
+	storedProvenance, err := svc.prov.GetProvenance(ctx, &old, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if storedProvenance != p && storedProvenance != models.ProvenanceNone {
+		return nil, fmt.Errorf("cannot changed provenance from '%s' to '%s'", storedProvenance, p)
+	}
+
+	renamed := definitions.MuteTimeInterval{MuteTimeIntervalConfig: old.MuteTimeIntervalConfig, Provenance: p}
+	renamed.Name = newName
+	if name == newName {
+		return &renamed, nil
+	}
+	revision.cfg.AlertmanagerConfig.MuteTimeIntervals[index] = renamed.MuteTimeIntervalConfig
+	routes := routesUsingMuteTiming(name, revision.cfg.AlertmanagerConfig.Route)
+	for _, r := range routes {
+		r.MuteTimeIntervals = replaceName(r.MuteTimeIntervals, name, newName)
+		r.ActiveTimeIntervals = replaceName(r.ActiveTimeIntervals, name, newName)
+	}
+
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return nil, err
+	}
+	cmd := models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		ConfigurationVersion:      revision.version,
+		FetchedConfigurationHash:  revision.concurrencyToken,
+		Default:                   false,
+		OrgID:                     orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
+	}
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err = svc.config.UpdateAlertmanagerConfiguration(ctx, &cmd)
+		if err != nil {
+			return err
 		}
-		if isMuteTimeInUse(name, route.Routes) {
+		err = svc.prov.DeleteProvenance(ctx, &old, orgID)
+		if err != nil {
+			return err
+		}
+		return svc.prov.SetProvenance(ctx, &renamed, orgID, renamed.Provenance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	svc.log.Info("renamed mute timing", "name", name, "new_name", newName, "policies", len(routes), "orgID", orgID)
+
+	return &renamed, nil
+}
+
+// routesUsingMuteTiming returns the routes of the tree that are muted or active during the mute timing.
+func routesUsingMuteTiming(name string, root *definitions.Route) []*definitions.Route {
+	var routes []*definitions.Route
+	if root == nil {
+		return routes
+	}
+	walkRoutes(root, nil, func(r, _ *definitions.Route) {
+		if containsName(r.MuteTimeIntervals, name) || containsName(r.ActiveTimeIntervals, name) {
+			routes = append(routes, r)
+		}
+	})
+	return routes
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
 			return true
 		}
 	}
 	return false
 }
+
+// removeName returns the names without name.
+func removeName(names []string, name string) []string {
+	result := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// replaceName returns the names with name replaced by newName.
+func replaceName(names []string, name, newName string) []string {
+	result := make([]string, 0, len(names))
+	for _, n := range names {
+		if n == name {
+			n = newName
+		}
+		result = append(result, n)
+	}
+	return result
+}
@@ -2,13 +2,14 @@ package provisioning
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
-	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
 	mock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -21,11 +22,52 @@ func TestMuteTimingService(t *testing.T) {
 				AlertmanagerConfiguration: configWithMuteTimings,
 			})
 
+		sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{
+			"asdf": models.ProvenanceFile,
+		})
+
 		result, err := sut.GetMuteTimings(context.Background(), 1)
 
 		require.NoError(t, err)
 		require.Len(t, result, 1)
 		require.Equal(t, "asdf", result[0].Name)
+		require.Equal(t, models.ProvenanceFile, result[0].Provenance)
+	})
+
+	t.Run("service returns a single timing by name", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithMuteTimings,
+			})
+		sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{})
+
+		result, err := sut.GetMuteTiming(context.Background(), "asdf", 1)
+		require.NoError(t, err)
+		require.Equal(t, "asdf", result.Name)
+		require.Equal(t, models.ProvenanceNone, result.Provenance)
+
+		_, err = sut.GetMuteTiming(context.Background(), "does not exist", 1)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("service exports timings in the file provisioning format", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithMuteTimings,
+			})
+		sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{})
+
+		result, err := sut.ExportMuteTimings(context.Background(), 2)
+
+		require.NoError(t, err)
+		require.EqualValues(t, 1, result.APIVersion)
+		require.Len(t, result.MuteTimes, 1)
+		require.EqualValues(t, 2, result.MuteTimes[0].OrgID)
+		require.Equal(t, "asdf", result.MuteTimes[0].Name)
+		require.Len(t, result.MuteTimes[0].TimeIntervals, 1)
+		require.Equal(t, "Europe/Berlin", result.MuteTimes[0].TimeIntervals[0].Location.String())
 	})
 
 	t.Run("service returns empty list when config file contains no mute timings", func(t *testing.T) {
@@ -81,7 +123,7 @@ func TestMuteTimingService(t *testing.T) {
 		t.Run("rejects mute timings that fail validation", func(t *testing.T) {
 			sut := createMuteTimingSvcSut()
 			timing := definitions.MuteTimeInterval{
-				MuteTimeInterval: config.MuteTimeInterval{
+				MuteTimeIntervalConfig: definitions.MuteTimeIntervalConfig{
 					Name: "",
 				},
 			}
@@ -91,6 +133,22 @@ func TestMuteTimingService(t *testing.T) {
 			require.ErrorIs(t, err, ErrValidation)
 		})
 
+		t.Run("rejects mute timings with overlapping ranges", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			timing := createMuteTiming()
+			timing.TimeIntervals = []definitions.TimeInterval{{
+				Times: []timeinterval.TimeRange{
+					{StartMinute: 9 * 60, EndMinute: 12 * 60},
+					{StartMinute: 11 * 60, EndMinute: 13 * 60},
+				},
+			}}
+
+			_, err := sut.CreateMuteTiming(context.Background(), timing, 1)
+
+			require.ErrorIs(t, err, ErrValidation)
+			require.ErrorContains(t, err, "time_intervals[0].times[1]: 11:00-13:00 overlaps times[0] (09:00-12:00)")
+		})
+
 		t.Run("propagates errors", func(t *testing.T) {
 			t.Run("when unable to read config", func(t *testing.T) {
 				sut := createMuteTimingSvcSut()
@@ -169,7 +227,7 @@ func TestMuteTimingService(t *testing.T) {
 		t.Run("rejects mute timings that fail validation", func(t *testing.T) {
 			sut := createMuteTimingSvcSut()
 			timing := definitions.MuteTimeInterval{
-				MuteTimeInterval: config.MuteTimeInterval{
+				MuteTimeIntervalConfig: definitions.MuteTimeIntervalConfig{
 					Name: "",
 				},
 			}
@@ -196,6 +254,44 @@ func TestMuteTimingService(t *testing.T) {
 			require.Nil(t, updated)
 		})
 
+		t.Run("rejects changing the provenance of provisioned timings", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			timing := createMuteTiming()
+			timing.Name = "asdf"
+			timing.Provenance = models.ProvenanceAPI
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimings,
+				})
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceFile)
+
+			_, err := sut.UpdateMuteTiming(context.Background(), timing, 1)
+
+			require.ErrorContains(t, err, "cannot changed provenance")
+		})
+
+		t.Run("respects concurrency token", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			timing := createMuteTiming()
+			timing.Name = "asdf"
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimings,
+					ConfigurationHash:         "fetched-hash",
+				})
+			sut.config.(*MockAMConfigStore).EXPECT().
+				UpdateAlertmanagerConfiguration(mock.Anything, mock.MatchedBy(func(cmd *models.SaveAlertmanagerConfigurationCmd) bool {
+					return cmd.FetchedConfigurationHash == "fetched-hash"
+				})).
+				Return(nil)
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone)
+			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+
+			_, err := sut.UpdateMuteTiming(context.Background(), timing, 1)
+
+			require.NoError(t, err)
+		})
+
 		t.Run("propagates errors", func(t *testing.T) {
 			t.Run("when unable to read config", func(t *testing.T) {
 				sut := createMuteTimingSvcSut()
@@ -241,6 +337,7 @@ func TestMuteTimingService(t *testing.T) {
 				sut := createMuteTimingSvcSut()
 				timing := createMuteTiming()
 				timing.Name = "asdf"
+				sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone)
 				sut.config.(*MockAMConfigStore).EXPECT().
 					GetsConfig(models.AlertConfiguration{
 						AlertmanagerConfiguration: configWithMuteTimings,
@@ -259,6 +356,7 @@ func TestMuteTimingService(t *testing.T) {
 				sut := createMuteTimingSvcSut()
 				timing := createMuteTiming()
 				timing.Name = "asdf"
+				sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone)
 				sut.config.(*MockAMConfigStore).EXPECT().
 					GetsConfig(models.AlertConfiguration{
 						AlertmanagerConfiguration: configWithMuteTimings,
@@ -285,7 +383,7 @@ func TestMuteTimingService(t *testing.T) {
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
 			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
 
-			err := sut.DeleteMuteTiming(context.Background(), "does not exist", 1)
+			err := sut.DeleteMuteTiming(context.Background(), "does not exist", 1, false)
 
 			require.NoError(t, err)
 		})
@@ -297,7 +395,7 @@ func TestMuteTimingService(t *testing.T) {
 					GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).
 					Return(fmt.Errorf("failed"))
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, false)
 
 				require.Error(t, err)
 			})
@@ -309,7 +407,7 @@ func TestMuteTimingService(t *testing.T) {
 						AlertmanagerConfiguration: brokenConfig,
 					})
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, false)
 
 				require.ErrorContains(t, err, "failed to deserialize")
 			})
@@ -320,7 +418,7 @@ func TestMuteTimingService(t *testing.T) {
 					GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).
 					Return(nil)
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, false)
 
 				require.ErrorContains(t, err, "no alertmanager configuration")
 			})
@@ -336,7 +434,7 @@ func TestMuteTimingService(t *testing.T) {
 					DeleteProvenance(mock.Anything, mock.Anything, mock.Anything).
 					Return(fmt.Errorf("failed to save provenance"))
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, false)
 
 				require.ErrorContains(t, err, "failed to save provenance")
 			})
@@ -352,7 +450,7 @@ func TestMuteTimingService(t *testing.T) {
 					Return(fmt.Errorf("failed to save config"))
 				sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, false)
 
 				require.ErrorContains(t, err, "failed to save config")
 			})
@@ -364,14 +462,143 @@ func TestMuteTimingService(t *testing.T) {
 						AlertmanagerConfiguration: configWithMuteTimingsInRoute,
 					})
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, false)
 
-				require.Error(t, err)
+				var inUse MuteTimingInUseError
+				require.True(t, errors.As(err, &inUse))
+				require.Equal(t, "asdf", inUse.Name)
+				require.Equal(t, []string{"policy with receiver 'grafana-default-email'"}, inUse.Policies)
 			})
 		})
+
+		t.Run("removes the mute timing from routes when forced", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimingsInRoute,
+				})
+			var saved *models.SaveAlertmanagerConfigurationCmd
+			sut.config.(*MockAMConfigStore).EXPECT().
+				UpdateAlertmanagerConfiguration(mock.Anything, mock.Anything).
+				Run(func(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) {
+					saved = cmd
+				}).
+				Return(nil)
+			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+
+			err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, true)
+
+			require.NoError(t, err)
+			require.NotNil(t, saved)
+			cfg, err := deserializeAlertmanagerConfig([]byte(saved.AlertmanagerConfiguration))
+			require.NoError(t, err)
+			require.Empty(t, cfg.AlertmanagerConfig.MuteTimeIntervals)
+			require.Empty(t, routesUsingMuteTiming("asdf", cfg.AlertmanagerConfig.Route))
+		})
+	})
+
+	t.Run("renaming mute timings", func(t *testing.T) {
+		t.Run("renames the mute timing in the routes using it", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimingsInRoute,
+				})
+			var saved *models.SaveAlertmanagerConfigurationCmd
+			sut.config.(*MockAMConfigStore).EXPECT().
+				UpdateAlertmanagerConfiguration(mock.Anything, mock.Anything).
+				Run(func(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) {
+					saved = cmd
+				}).
+				Return(nil)
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone)
+			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+
+			renamed, err := sut.RenameMuteTiming(context.Background(), "asdf", "mondays", 1, models.ProvenanceAPI)
+
+			require.NoError(t, err)
+			require.Equal(t, "mondays", renamed.Name)
+			require.Equal(t, models.ProvenanceAPI, renamed.Provenance)
+			require.NotNil(t, saved)
+			cfg, err := deserializeAlertmanagerConfig([]byte(saved.AlertmanagerConfiguration))
+			require.NoError(t, err)
+			require.Len(t, cfg.AlertmanagerConfig.MuteTimeIntervals, 1)
+			require.Equal(t, "mondays", cfg.AlertmanagerConfig.MuteTimeIntervals[0].Name)
+			require.Len(t, cfg.AlertmanagerConfig.MuteTimeIntervals[0].TimeIntervals, 1)
+			require.Empty(t, routesUsingMuteTiming("asdf", cfg.AlertmanagerConfig.Route))
+			require.Len(t, routesUsingMuteTiming("mondays", cfg.AlertmanagerConfig.Route), 1)
+			sut.prov.(*MockProvisioningStore).AssertCalled(t, "DeleteProvenance", mock.Anything, mock.MatchedBy(func(mt *definitions.MuteTimeInterval) bool {
+				return mt.Name == "asdf"
+			}), int64(1))
+			sut.prov.(*MockProvisioningStore).AssertCalled(t, "SetProvenance", mock.Anything, mock.MatchedBy(func(mt *definitions.MuteTimeInterval) bool {
+				return mt.Name == "mondays"
+			}), int64(1), models.ProvenanceAPI)
+		})
+
+		t.Run("returns ErrNotFound if timing does not exist", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimings,
+				})
+
+			_, err := sut.RenameMuteTiming(context.Background(), "does not exist", "mondays", 1, models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("rejects names of existing mute timings", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimings,
+				})
+
+			_, err := sut.RenameMuteTiming(context.Background(), "does not exist", "asdf", 1, models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, ErrValidation)
+		})
+
+		t.Run("rejects empty names", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+
+			_, err := sut.RenameMuteTiming(context.Background(), "asdf", "", 1, models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, ErrValidation)
+		})
+
+		t.Run("rejects changing the provenance of provisioned timings", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimings,
+				})
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceFile)
+
+			_, err := sut.RenameMuteTiming(context.Background(), "asdf", "mondays", 1, models.ProvenanceAPI)
+
+			require.ErrorContains(t, err, "cannot changed provenance")
+		})
 	})
 }
 
+func TestRoutesUsingMuteTiming(t *testing.T) {
+	weekends := &definitions.Route{Receiver: "a", MuteTimeIntervals: []string{"weekends"}}
+	businessHours := &definitions.Route{Receiver: "a", ActiveTimeIntervals: []string{"business-hours"}}
+	root := &definitions.Route{
+		Receiver: "a",
+		Routes: []*definitions.Route{
+			weekends,
+			{Receiver: "a", Routes: []*definitions.Route{businessHours}},
+		},
+	}
+
+	require.Equal(t, []*definitions.Route{weekends}, routesUsingMuteTiming("weekends", root))
+	require.Equal(t, []*definitions.Route{businessHours}, routesUsingMuteTiming("business-hours", root))
+	require.Empty(t, routesUsingMuteTiming("holidays", root))
+	require.Empty(t, routesUsingMuteTiming("holidays", nil))
+}
+
 func createMuteTimingSvcSut() *MuteTimingService {
 	return &MuteTimingService{
 		config: &MockAMConfigStore{},
@@ -383,7 +610,7 @@ func createMuteTimingSvcSut() *MuteTimingService {
 
 func createMuteTiming() definitions.MuteTimeInterval {
 	return definitions.MuteTimeInterval{
-		MuteTimeInterval: config.MuteTimeInterval{
+		MuteTimeIntervalConfig: definitions.MuteTimeIntervalConfig{
 			Name: "interval",
 		},
 	}
@@ -402,7 +629,8 @@ var configWithMuteTimings = `
 			"name": "asdf",
 			"time_intervals": [{
 				"times": [],
-				"weekdays": ["monday"]
+				"weekdays": ["monday"],
+				"location": "Europe/Berlin"
 			}]
 		}],
 		"receivers": [{
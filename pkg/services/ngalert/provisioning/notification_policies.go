@@ -2,26 +2,46 @@ package provisioning
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// PolicyResetMode controls which parts of the policy tree are restored by ResetPolicyTree.
+type PolicyResetMode string
+
+const (
+	// PolicyResetFull replaces the whole policy tree with the default one.
+	PolicyResetFull PolicyResetMode = "full"
+	// PolicyResetProvisioned restores the provisioned routes to their defaults and keeps the routes that are not provisioned.
+	PolicyResetProvisioned PolicyResetMode = "provisioned"
 )
 
 type NotificationPolicyService struct {
 	amStore         AMConfigStore
 	provenanceStore ProvisioningStore
+	historyStore    PolicyHistoryStore
+	ruleStore       RuleReader
 	xact            TransactionManager
+	settings        setting.UnifiedAlertingSettings
 	log             log.Logger
 }
 
-func NewNotificationPolicyService(am AMConfigStore, prov ProvisioningStore,
-	xact TransactionManager, log log.Logger) *NotificationPolicyService {
+func NewNotificationPolicyService(am AMConfigStore, prov ProvisioningStore, history PolicyHistoryStore, rules RuleReader,
+	xact TransactionManager, settings setting.UnifiedAlertingSettings, log log.Logger) *NotificationPolicyService {
 	return &NotificationPolicyService{
 		amStore:         am,
 		provenanceStore: prov,
+		historyStore:    history,
+		ruleStore:       rules,
 		xact:            xact,
+		settings:        settings,
 		log:             log,
 	}
 }
@@ -31,35 +51,48 @@ func (nps *NotificationPolicyService) GetAMConfigStore() AMConfigStore {
 }
 
 func (nps *NotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
+	tree, _, err := nps.GetPolicyTreeWithHash(ctx, orgID)
+	return tree, err
+}
+
+// GetPolicyTreeWithHash returns the policy tree and the hash of the configuration it was read from, which can be
+// given to UpdatePolicyTreeWithHash to make sure the tree was not changed in the meantime.
+func (nps *NotificationPolicyService) GetPolicyTreeWithHash(ctx context.Context, orgID int64) (definitions.Route, string, error) {
 	q := models.GetLatestAlertmanagerConfigurationQuery{
 		OrgID: orgID,
 	}
 	err := nps.amStore.GetLatestAlertmanagerConfiguration(ctx, &q)
 	if err != nil {
-		return definitions.Route{}, err
+		return definitions.Route{}, "", err
 	}
 
 	cfg, err := deserializeAlertmanagerConfig([]byte(q.Result.AlertmanagerConfiguration))
 	if err != nil {
-		return definitions.Route{}, err
+		return definitions.Route{}, "", err
 	}
 
 	if cfg.AlertmanagerConfig.Config.Route == nil {
-		return definitions.Route{}, fmt.Errorf("no route present in current alertmanager config")
+		return definitions.Route{}, "", fmt.Errorf("no route present in current alertmanager config")
 	}
 
-	provenance, err := nps.provenanceStore.GetProvenance(ctx, cfg.AlertmanagerConfig.Route, orgID)
+	provenances, err := nps.provenanceStore.GetProvenances(ctx, orgID, cfg.AlertmanagerConfig.Route.ResourceType())
 	if err != nil {
-		return definitions.Route{}, err
+		return definitions.Route{}, "", err
 	}
 
 	result := *cfg.AlertmanagerConfig.Route
-	result.Provenance = provenance
+	ApplyRouteProvenances(&result, provenances)
 
-	return result, nil
+	return result, q.Result.ConfigurationHash, nil
 }
 
 func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
+	return nps.UpdatePolicyTreeWithHash(ctx, orgID, tree, p, "")
+}
+
+// UpdatePolicyTreeWithHash replaces the policy tree like UpdatePolicyTree. If fetchedConfigurationHash is set, the
+// tree is only replaced if it is the hash of the current configuration, otherwise ErrVersionConflict is returned.
+func (nps *NotificationPolicyService) UpdatePolicyTreeWithHash(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance, fetchedConfigurationHash string) error {
 	err := tree.Validate()
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
@@ -69,6 +102,9 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 	if err != nil {
 		return err
 	}
+	if fetchedConfigurationHash != "" && fetchedConfigurationHash != revision.concurrencyToken {
+		return ErrVersionConflict
+	}
 
 	receivers, err := nps.receiversToMap(revision.cfg.AlertmanagerConfig.Receivers)
 	err = tree.ValidateReceivers(receivers)
@@ -81,11 +117,109 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 		muteTimes[mt.Name] = struct{}{}
 	}
 	err = tree.ValidateMuteTimes(muteTimes)
+	if err != nil {
+		return validationError{err}
+	}
+
+	err = validateRouteIDs(&tree)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
 
-	revision.cfg.AlertmanagerConfig.Config.Route = &tree
+	provenances, err := nps.provenanceStore.GetProvenances(ctx, orgID, tree.ResourceType())
+	if err != nil {
+		return err
+	}
+	// routes provisioned by another source must be kept as they are, everything else is replaced by the new tree
+	lockedRoutes := map[*definitions.Route]models.Provenance{}
+	if current := revision.cfg.AlertmanagerConfig.Config.Route; current != nil {
+		ApplyRouteProvenances(current, provenances)
+		lockedRoutes, err = matchProvisionedRoutes(current, &tree, p)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+		}
+	}
+
+	return nps.saveTree(ctx, orgID, revision, &tree, provenances, lockedRoutes, p)
+}
+
+// ResetPolicyTree restores the default policy tree. Depending on mode, either the whole tree is replaced,
+// or only the provisioned routes are restored to their defaults while the routes created in the UI are kept.
+// The provenance of the routes is not checked, and all routes of the resulting tree are no longer provisioned.
+func (nps *NotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64, mode PolicyResetMode) error {
+	defaultCfg, err := deserializeAlertmanagerConfig([]byte(setting.GetAlertmanagerDefaultConfiguration()))
+	if err != nil {
+		return fmt.Errorf("failed to load the default configuration: %w", err)
+	}
+	defaultRoute := defaultCfg.AlertmanagerConfig.Config.Route
+
+	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
+	if err != nil {
+		return err
+	}
+
+	provenances, err := nps.provenanceStore.GetProvenances(ctx, orgID, defaultRoute.ResourceType())
+	if err != nil {
+		return err
+	}
+
+	tree := *defaultRoute
+	current := revision.cfg.AlertmanagerConfig.Config.Route
+	switch mode {
+	case PolicyResetFull:
+	case PolicyResetProvisioned:
+		if current == nil {
+			break
+		}
+		// the current tree is added to the history as it is, so the routes to keep are taken from a copy
+		var kept definitions.Route
+		if err := copyRoute(current, &kept); err != nil {
+			return err
+		}
+		ApplyRouteProvenances(&kept, provenances)
+		if kept.Provenance == models.ProvenanceNone {
+			tree = kept
+		}
+		tree.Routes = unprovisionedRoutes(kept.Routes)
+	default:
+		return fmt.Errorf("%w: unknown reset mode '%s'", ErrValidation, mode)
+	}
+
+	// the default root route sends to the default receiver, which might have been removed in the meantime
+	receivers, _ := nps.receiversToMap(revision.cfg.AlertmanagerConfig.Receivers)
+	if _, ok := receivers[tree.Receiver]; !ok {
+		for _, r := range defaultCfg.AlertmanagerConfig.Receivers {
+			if r.Name == tree.Receiver {
+				revision.cfg.AlertmanagerConfig.Receivers = append(revision.cfg.AlertmanagerConfig.Receivers, r)
+			}
+		}
+	}
+
+	return nps.saveTree(ctx, orgID, revision, &tree, provenances, nil, models.ProvenanceNone)
+}
+
+// saveTree replaces the policy tree of revision with tree, adds the replaced tree to the history and stores
+// the provenance of the routes. Routes in locked keep their provenance, all others get the provenance p.
+// The tree is rejected if it exceeds the policy tree limits of the organization.
+func (nps *NotificationPolicyService) saveTree(ctx context.Context, orgID int64, revision *cfgRevision, tree *definitions.Route,
+	provenances map[string]models.Provenance, locked map[*definitions.Route]models.Provenance, p models.Provenance) error {
+	if err := CheckPolicyTreeLimits(tree, nps.settings.PolicyTreeLimitsForOrg(orgID)); err != nil {
+		return err
+	}
+	var previous []byte
+	if current := revision.cfg.AlertmanagerConfig.Config.Route; current != nil {
+		walkRoutes(current, nil, func(r, _ *definitions.Route) {
+			r.Provenance = models.ProvenanceNone
+		})
+		var err error
+		previous, err = json.Marshal(current)
+		if err != nil {
+			return err
+		}
+	}
+	prepareRoutesForSave(tree)
+
+	revision.cfg.AlertmanagerConfig.Config.Route = tree
 
 	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
 	if err != nil {
@@ -97,22 +231,207 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 		FetchedConfigurationHash:  revision.concurrencyToken,
 		Default:                   false,
 		OrgID:                     orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
 	}
-	err = nps.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = nps.amStore.UpdateAlertmanagerConfiguration(ctx, &cmd)
+	return nps.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := nps.amStore.UpdateAlertmanagerConfiguration(ctx, &cmd)
 		if err != nil {
 			return err
 		}
-		err = nps.provenanceStore.SetProvenance(ctx, &tree, orgID, p)
-		if err != nil {
-			return err
+		if previous != nil {
+			err = nps.historyStore.SaveNotificationPolicyVersion(ctx, &models.NotificationPolicyVersion{
+				OrgID:      orgID,
+				PolicyTree: string(previous),
+			})
+			if err != nil {
+				return err
+			}
 		}
-		return nil
+		return nps.saveRouteProvenances(ctx, orgID, tree, provenances, locked, p)
 	})
+}
+
+// GetPolicyTreeHistory returns the previous versions of the policy tree, newest first.
+// The returned versions do not contain the policy trees, see GetPolicyTreeVersion.
+func (nps *NotificationPolicyService) GetPolicyTreeHistory(ctx context.Context, orgID int64) ([]definitions.NotificationPolicyVersion, error) {
+	versions, err := nps.historyStore.GetNotificationPolicyVersions(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.NotificationPolicyVersion, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, definitions.NotificationPolicyVersion{
+			Version: v.Version,
+			Created: time.Unix(v.CreatedAt, 0).UTC(),
+		})
+	}
+	return result, nil
+}
+
+// GetPolicyTreeVersion returns a previous version of the policy tree.
+func (nps *NotificationPolicyService) GetPolicyTreeVersion(ctx context.Context, orgID int64, version int64) (definitions.NotificationPolicyVersion, error) {
+	v, err := nps.historyStore.GetNotificationPolicyVersion(ctx, orgID, version)
+	if errors.Is(err, models.ErrNotificationPolicyVersionNotFound) {
+		return definitions.NotificationPolicyVersion{}, fmt.Errorf("%w: %s", ErrNotFound, err.Error())
+	}
 	if err != nil {
+		return definitions.NotificationPolicyVersion{}, err
+	}
+
+	var tree definitions.Route
+	if err := json.Unmarshal([]byte(v.PolicyTree), &tree); err != nil {
+		return definitions.NotificationPolicyVersion{}, fmt.Errorf("failed to deserialize policy tree of version %d: %w", version, err)
+	}
+	return definitions.NotificationPolicyVersion{
+		Version: v.Version,
+		Created: time.Unix(v.CreatedAt, 0).UTC(),
+		Policy:  &tree,
+	}, nil
+}
+
+// RollbackPolicyTree replaces the policy tree with a previous version. The same rules as for UpdatePolicyTree apply,
+// and the replaced tree is added to the history, so a rollback can be reverted as well.
+func (nps *NotificationPolicyService) RollbackPolicyTree(ctx context.Context, orgID int64, version int64, p models.Provenance) error {
+	v, err := nps.GetPolicyTreeVersion(ctx, orgID, version)
+	if err != nil {
+		return err
+	}
+	return nps.UpdatePolicyTree(ctx, orgID, *v.Policy, p)
+}
+
+// MoveRoute moves the nested route with the given ID to position index among its siblings. The routes keep their
+// provenance, routes provisioned by another source can be reordered as well, as they stay attached to the same parent.
+func (nps *NotificationPolicyService) MoveRoute(ctx context.Context, orgID int64, routeID string, index int) error {
+	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
+	if err != nil {
+		return err
+	}
+	current := revision.cfg.AlertmanagerConfig.Config.Route
+	if current == nil {
+		return fmt.Errorf("no route present in current alertmanager config")
+	}
+
+	provenances, err := nps.provenanceStore.GetProvenances(ctx, orgID, current.ResourceType())
+	if err != nil {
+		return err
+	}
+
+	// the current tree is added to the history as it is, so the routes are moved in a copy
+	var tree definitions.Route
+	if err := copyRoute(current, &tree); err != nil {
 		return err
 	}
+	ApplyRouteProvenances(&tree, provenances)
+
+	var route, parent *definitions.Route
+	locked := map[*definitions.Route]models.Provenance{}
+	walkRoutes(&tree, nil, func(r, p *definitions.Route) {
+		locked[r] = r.Provenance
+		if routeID != "" && r.ID == routeID {
+			route, parent = r, p
+		}
+	})
+	if route == nil {
+		return fmt.Errorf("%w: policy '%s' does not exist", ErrNotFound, routeID)
+	}
+	if index < 0 || index >= len(parent.Routes) {
+		return fmt.Errorf("%w: index %d is out of range, the policy has %d siblings", ErrValidation, index, len(parent.Routes)-1)
+	}
+
+	siblings := make([]*definitions.Route, 0, len(parent.Routes))
+	for _, r := range parent.Routes {
+		if r != route {
+			siblings = append(siblings, r)
+		}
+	}
+	siblings = append(siblings[:index], append([]*definitions.Route{route}, siblings[index:]...)...)
+	parent.Routes = siblings
+
+	return nps.saveTree(ctx, orgID, revision, &tree, provenances, locked, models.ProvenanceNone)
+}
+
+// RepointRoutes makes all routes that use the receiver from use the receiver to instead. The routes keep their
+// provenance, routes provisioned by another source are updated as well so they do not reference a receiver that
+// is about to be removed. Returns the number of routes that were changed.
+func (nps *NotificationPolicyService) RepointRoutes(ctx context.Context, orgID int64, from, to string) (int, error) {
+	if from == "" || to == "" {
+		return 0, fmt.Errorf("%w: both receivers must be set", ErrValidation)
+	}
+	if from == to {
+		return 0, fmt.Errorf("%w: the receivers must be different", ErrValidation)
+	}
 
+	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
+	if err != nil {
+		return 0, err
+	}
+	current := revision.cfg.AlertmanagerConfig.Config.Route
+	if current == nil {
+		return 0, fmt.Errorf("no route present in current alertmanager config")
+	}
+
+	receivers, err := nps.receiversToMap(revision.cfg.AlertmanagerConfig.Receivers)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := receivers[to]; !ok {
+		return 0, fmt.Errorf("%w: receiver '%s' does not exist", ErrValidation, to)
+	}
+
+	provenances, err := nps.provenanceStore.GetProvenances(ctx, orgID, current.ResourceType())
+	if err != nil {
+		return 0, err
+	}
+
+	// the current tree is added to the history as it is, so the routes are changed in a copy
+	var tree definitions.Route
+	if err := copyRoute(current, &tree); err != nil {
+		return 0, err
+	}
+	ApplyRouteProvenances(&tree, provenances)
+
+	locked := map[*definitions.Route]models.Provenance{}
+	walkRoutes(&tree, nil, func(r, _ *definitions.Route) {
+		locked[r] = r.Provenance
+	})
+	changed := repointRoutes(&tree, from, to)
+	if changed == 0 {
+		return 0, nil
+	}
+
+	if err := nps.saveTree(ctx, orgID, revision, &tree, provenances, locked, models.ProvenanceNone); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// saveRouteProvenances stores the provenance of every route in the tree and removes the records of routes that no longer exist.
+func (nps *NotificationPolicyService) saveRouteProvenances(ctx context.Context, orgID int64, tree *definitions.Route,
+	stored map[string]models.Provenance, locked map[*definitions.Route]models.Provenance, p models.Provenance) error {
+	ids := map[string]struct{}{}
+	var err error
+	walkRoutes(tree, nil, func(r, _ *definitions.Route) {
+		ids[r.ID] = struct{}{}
+		if err != nil {
+			return
+		}
+		provenance := p
+		if lockedProvenance, ok := locked[r]; ok {
+			provenance = lockedProvenance
+		}
+		err = nps.provenanceStore.SetProvenance(ctx, r, orgID, provenance)
+	})
+	if err != nil {
+		return err
+	}
+	for id := range stored {
+		if _, ok := ids[id]; ok {
+			continue
+		}
+		if err := nps.provenanceStore.DeleteProvenance(ctx, &definitions.Route{ID: id}, orgID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -7,8 +7,8 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/prometheus/alertmanager/config"
-	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -31,9 +31,9 @@ func TestNotificationPolicyService(t *testing.T) {
 			Return(
 				func(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error {
 					cfg, _ := deserializeAlertmanagerConfig([]byte(defaultConfig))
-					mti := config.MuteTimeInterval{
+					mti := definitions.MuteTimeIntervalConfig{
 						Name:          "not-the-one-we-need",
-						TimeIntervals: []timeinterval.TimeInterval{},
+						TimeIntervals: []definitions.TimeInterval{},
 					}
 					cfg.AlertmanagerConfig.MuteTimeIntervals = append(cfg.AlertmanagerConfig.MuteTimeIntervals, mti)
 					cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers,
@@ -59,7 +59,10 @@ func TestNotificationPolicyService(t *testing.T) {
 		})
 
 		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrValidation)
+		var missing *definitions.MissingTimeIntervalsError
+		require.ErrorAs(t, err, &missing)
+		require.Equal(t, []string{"not-existing"}, missing.Names)
 	})
 
 	t.Run("pass if referenced mute time interval is existing", func(t *testing.T) {
@@ -69,9 +72,9 @@ func TestNotificationPolicyService(t *testing.T) {
 			Return(
 				func(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error {
 					cfg, _ := deserializeAlertmanagerConfig([]byte(defaultConfig))
-					mti := config.MuteTimeInterval{
+					mti := definitions.MuteTimeIntervalConfig{
 						Name:          "existing",
-						TimeIntervals: []timeinterval.TimeInterval{},
+						TimeIntervals: []definitions.TimeInterval{},
 					}
 					cfg.AlertmanagerConfig.MuteTimeIntervals = append(cfg.AlertmanagerConfig.MuteTimeIntervals, mti)
 					cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers,
@@ -184,6 +187,337 @@ func TestNotificationPolicyService(t *testing.T) {
 		require.Equal(t, models.ProvenanceAPI, updated.Provenance)
 	})
 
+	t.Run("nested routes get an id and their own provenance", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{
+			Receiver: "a new receiver",
+		})
+
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		updated, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, updated.ID)
+		require.Len(t, updated.Routes, 1)
+		require.NotEmpty(t, updated.Routes[0].ID)
+		require.Equal(t, models.ProvenanceAPI, updated.Routes[0].Provenance)
+	})
+
+	t.Run("routes can be added below a route provisioned by another source", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		err := sut.UpdatePolicyTree(context.Background(), 1, createTestRoutingTree(), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{
+			Receiver: "a new receiver",
+		})
+		err = sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		updated, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceFile, updated.Provenance)
+		require.Len(t, updated.Routes, 1)
+		require.Equal(t, models.ProvenanceAPI, updated.Routes[0].Provenance)
+	})
+
+	t.Run("routes provisioned by another source cannot be changed", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{
+			Receiver: "a new receiver",
+		})
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceFile)
+		require.NoError(t, err)
+		provisioned, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+
+		changed := createTestRoutingTree()
+		changed.Routes = append(changed.Routes, &definitions.Route{
+			ID:       provisioned.Routes[0].ID,
+			Receiver: "grafana-default-email",
+		})
+		err = sut.UpdatePolicyTree(context.Background(), 1, changed, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+
+		err = sut.UpdatePolicyTree(context.Background(), 1, createTestRoutingTree(), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("provenance of deleted routes is removed", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{
+			Receiver: "a new receiver",
+		})
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		err = sut.UpdatePolicyTree(context.Background(), 1, createTestRoutingTree(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		provenances, err := sut.provenanceStore.GetProvenances(context.Background(), 1, (&definitions.Route{}).ResourceType())
+		require.NoError(t, err)
+		require.Len(t, provenances, 1)
+	})
+
+	t.Run("duplicated route ids return ValidationError", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes,
+			&definitions.Route{ID: "dup", Receiver: "a new receiver"},
+			&definitions.Route{ID: "dup", Receiver: "a new receiver"},
+		)
+
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("replaced policy trees are added to the history", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{
+			Receiver: "a new receiver",
+		})
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		require.NoError(t, err)
+		err = sut.UpdatePolicyTree(context.Background(), 1, createTestRoutingTree(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		history, err := sut.GetPolicyTreeHistory(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.Equal(t, int64(2), history[0].Version)
+		require.Nil(t, history[0].Policy)
+
+		v, err := sut.GetPolicyTreeVersion(context.Background(), 1, 1)
+		require.NoError(t, err)
+		require.Equal(t, "grafana-default-email", v.Policy.Receiver)
+		v, err = sut.GetPolicyTreeVersion(context.Background(), 1, 2)
+		require.NoError(t, err)
+		require.Len(t, v.Policy.Routes, 1)
+		require.NotEmpty(t, v.Policy.Routes[0].ID)
+		require.Equal(t, models.ProvenanceNone, v.Policy.Routes[0].Provenance)
+	})
+
+	t.Run("rollback restores a previous policy tree", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{
+			Receiver: "a new receiver",
+		})
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		require.NoError(t, err)
+		expected, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		err = sut.UpdatePolicyTree(context.Background(), 1, createTestRoutingTree(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		err = sut.RollbackPolicyTree(context.Background(), 1, 2, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		restored, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, expected, restored)
+		history, err := sut.GetPolicyTreeHistory(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+	})
+
+	t.Run("rollback to an unknown version returns NotFound", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		err := sut.RollbackPolicyTree(context.Background(), 1, 1, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("routes can be moved among their siblings", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes,
+			&definitions.Route{ID: "first", Receiver: "a new receiver"},
+			&definitions.Route{ID: "second", Receiver: "a new receiver"},
+			&definitions.Route{ID: "third", Receiver: "a new receiver"},
+		)
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		err = sut.MoveRoute(context.Background(), 1, "third", 0)
+		require.NoError(t, err)
+
+		tree, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, tree.Routes, 3)
+		require.Equal(t, "third", tree.Routes[0].ID)
+		require.Equal(t, "first", tree.Routes[1].ID)
+		require.Equal(t, "second", tree.Routes[2].ID)
+		walkRoutes(&tree, nil, func(r, _ *definitions.Route) {
+			require.Equal(t, models.ProvenanceFile, r.Provenance)
+		})
+		history, err := sut.GetPolicyTreeHistory(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+
+		err = sut.MoveRoute(context.Background(), 1, "third", 2)
+		require.NoError(t, err)
+		tree, err = sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "first", tree.Routes[0].ID)
+		require.Equal(t, "third", tree.Routes[2].ID)
+	})
+
+	t.Run("moving an unknown route returns NotFound", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		err := sut.MoveRoute(context.Background(), 1, "unknown", 0)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("moving a route out of range returns ValidationError", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{ID: "only", Receiver: "a new receiver"})
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		err = sut.MoveRoute(context.Background(), 1, "only", 1)
+		require.ErrorIs(t, err, ErrValidation)
+		err = sut.MoveRoute(context.Background(), 1, "only", -1)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("routes can be repointed to another receiver", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes,
+			&definitions.Route{ID: "first", Receiver: "a new receiver"},
+			&definitions.Route{ID: "second", Receiver: "grafana-default-email"},
+		)
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		updated, err := sut.RepointRoutes(context.Background(), 1, "a new receiver", "grafana-default-email")
+		require.NoError(t, err)
+		require.Equal(t, 2, updated)
+
+		tree, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		walkRoutes(&tree, nil, func(r, _ *definitions.Route) {
+			require.Equal(t, "grafana-default-email", r.Receiver)
+			require.Equal(t, models.ProvenanceFile, r.Provenance)
+		})
+		history, err := sut.GetPolicyTreeHistory(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+
+		updated, err = sut.RepointRoutes(context.Background(), 1, "a new receiver", "grafana-default-email")
+		require.NoError(t, err)
+		require.Equal(t, 0, updated)
+	})
+
+	t.Run("repointing routes to an unknown receiver returns ValidationError", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		_, err := sut.RepointRoutes(context.Background(), 1, "grafana-default-email", "unknown")
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = sut.RepointRoutes(context.Background(), 1, "grafana-default-email", "grafana-default-email")
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("full reset restores the default policy tree", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{
+			Receiver: "a new receiver",
+		})
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		err = sut.ResetPolicyTree(context.Background(), 1, PolicyResetFull)
+		require.NoError(t, err)
+
+		tree, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "grafana-default-email", tree.Receiver)
+		require.Empty(t, tree.Routes)
+		require.Equal(t, models.ProvenanceNone, tree.Provenance)
+		history, err := sut.GetPolicyTreeHistory(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+	})
+
+	t.Run("provisioned reset keeps routes that are not provisioned", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{
+			ID:       "provisioned",
+			Receiver: "a new receiver",
+		})
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		withUIRoutes := createTestRoutingTree()
+		withUIRoutes.Routes = append(withUIRoutes.Routes,
+			&definitions.Route{
+				ID:       "provisioned",
+				Receiver: "a new receiver",
+				Routes:   []*definitions.Route{{ID: "nested", Receiver: "a new receiver"}},
+			},
+			&definitions.Route{ID: "sibling", Receiver: "a new receiver"},
+		)
+		err = sut.UpdatePolicyTree(context.Background(), 1, withUIRoutes, models.ProvenanceNone)
+		require.NoError(t, err)
+
+		err = sut.ResetPolicyTree(context.Background(), 1, PolicyResetProvisioned)
+		require.NoError(t, err)
+
+		tree, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "grafana-default-email", tree.Receiver)
+		require.Len(t, tree.Routes, 2)
+		require.Equal(t, "nested", tree.Routes[0].ID)
+		require.Equal(t, "sibling", tree.Routes[1].ID)
+		walkRoutes(&tree, nil, func(r, _ *definitions.Route) {
+			require.Equal(t, models.ProvenanceNone, r.Provenance)
+		})
+		provenances, err := sut.provenanceStore.GetProvenances(context.Background(), 1, tree.ResourceType())
+		require.NoError(t, err)
+		require.NotContains(t, provenances, "provisioned")
+
+		v, err := sut.GetPolicyTreeVersion(context.Background(), 1, 3)
+		require.NoError(t, err)
+		require.Equal(t, "provisioned", v.Policy.Routes[0].ID)
+		require.Len(t, v.Policy.Routes[0].Routes, 1)
+	})
+
+	t.Run("provisioned reset keeps the root route if it is not provisioned", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		newRoute.Routes = append(newRoute.Routes, &definitions.Route{
+			Receiver: "a new receiver",
+		})
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
+		require.NoError(t, err)
+
+		err = sut.ResetPolicyTree(context.Background(), 1, PolicyResetProvisioned)
+		require.NoError(t, err)
+
+		tree, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "a new receiver", tree.Receiver)
+		require.Len(t, tree.Routes, 1)
+	})
+
+	t.Run("unknown reset mode returns ValidationError", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		err := sut.ResetPolicyTree(context.Background(), 1, PolicyResetMode("everything"))
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
 	t.Run("service respects concurrency token when updating", func(t *testing.T) {
 		sut := createNotificationPolicyServiceSut()
 		newRoute := createTestRoutingTree()
@@ -202,6 +536,34 @@ func TestNotificationPolicyService(t *testing.T) {
 		require.Equal(t, expectedConcurrencyToken, intercepted.FetchedConfigurationHash)
 	})
 
+	t.Run("update with the hash of the current configuration succeeds", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		_, hash, err := sut.GetPolicyTreeWithHash(context.Background(), 1)
+		require.NoError(t, err)
+
+		err = sut.UpdatePolicyTreeWithHash(context.Background(), 1, createTestRoutingTree(), models.ProvenanceAPI, hash)
+		require.NoError(t, err)
+
+		updated, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "a new receiver", updated.Receiver)
+	})
+
+	t.Run("update with an outdated hash returns VersionConflict", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		_, hash, err := sut.GetPolicyTreeWithHash(context.Background(), 1)
+		require.NoError(t, err)
+		err = sut.UpdatePolicyTree(context.Background(), 1, createTestRoutingTree(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		err = sut.UpdatePolicyTreeWithHash(context.Background(), 1, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI, hash)
+
+		require.ErrorIs(t, err, ErrVersionConflict)
+		current, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "a new receiver", current.Receiver)
+	})
+
 	t.Run("updating invalid route returns ValidationError", func(t *testing.T) {
 		sut := createNotificationPolicyServiceSut()
 		invalid := createTestRoutingTree()
@@ -213,12 +575,34 @@ func TestNotificationPolicyService(t *testing.T) {
 		require.Error(t, err)
 		require.ErrorIs(t, err, ErrValidation)
 	})
+
+	t.Run("trees exceeding the limits of the org are rejected", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		sut.settings.PolicyTreeLimitsPerOrg = map[int64]setting.UnifiedAlertingPolicyTreeLimits{1: {MaxRoutes: 2}}
+		tree := createTestRoutingTree()
+		tree.Routes = []*definitions.Route{{Receiver: "a new receiver"}, {Receiver: "a new receiver"}}
+		before, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+
+		err = sut.UpdatePolicyTree(context.Background(), 1, tree, models.ProvenanceNone)
+
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorIs(t, err, ErrPolicyTreeLimitExceeded)
+		after, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+
+		tree.Routes = tree.Routes[:1]
+		require.NoError(t, sut.UpdatePolicyTree(context.Background(), 1, tree, models.ProvenanceNone))
+	})
 }
 
 func createNotificationPolicyServiceSut() *NotificationPolicyService {
 	return &NotificationPolicyService{
 		amStore:         newFakeAMConfigStore(),
 		provenanceStore: NewFakeProvisioningStore(),
+		historyStore:    newFakePolicyHistoryStore(),
+		ruleStore:       &fakeRuleReader{},
 		xact:            newNopTransactionManager(),
 		log:             log.NewNopLogger(),
 	}
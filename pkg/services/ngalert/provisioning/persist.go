@@ -3,8 +3,14 @@ package provisioning
 import (
 	"context"
 
+	gfcore "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
 )
 
 // AMStore is a store of Alertmanager configurations.
@@ -23,6 +29,71 @@ type ProvisioningStore interface {
 	DeleteProvenance(ctx context.Context, o models.Provisionable, org int64) error
 }
 
+// PolicyHistoryStore is a store of previous versions of notification policy trees.
+type PolicyHistoryStore interface {
+	SaveNotificationPolicyVersion(ctx context.Context, v *models.NotificationPolicyVersion) error
+	GetNotificationPolicyVersions(ctx context.Context, orgID int64) ([]models.NotificationPolicyVersion, error)
+	GetNotificationPolicyVersion(ctx context.Context, orgID int64, version int64) (*models.NotificationPolicyVersion, error)
+}
+
+// RuleReader represents the ability to query alert rules.
+type RuleReader interface {
+	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) error
+}
+
+// AlertInstanceReader represents the ability to query the stored state of alert rules.
+type AlertInstanceReader interface {
+	ListAlertInstances(ctx context.Context, cmd *models.ListAlertInstancesQuery) error
+}
+
+// SilenceReader represents the ability to query the silences of the Alertmanager of an organization.
+type SilenceReader interface {
+	ListSilences(ctx context.Context, orgID int64) (definitions.GettableSilences, error)
+}
+
+// DatasourceReader represents the ability to query the datasources of an organization.
+type DatasourceReader interface {
+	GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) error
+}
+
+// PluginReader represents the ability to query the installed plugins.
+type PluginReader interface {
+	Plugin(ctx context.Context, pluginID string) (plugins.PluginDTO, bool)
+}
+
+// DashboardReader represents the ability to query the dashboards and folders of an organization.
+type DashboardReader interface {
+	GetDashboard(ctx context.Context, query *gfcore.GetDashboardQuery) error
+}
+
+// QuotaChecker represents the ability to evaluate whether quotas are met.
+type QuotaChecker interface {
+	CheckQuotaReached(ctx context.Context, target string, scopeParams *quota.ScopeParameters) (bool, error)
+}
+
+// FolderPolicyChecker returns the rule policies of folders and checks alert rules against them.
+type FolderPolicyChecker interface {
+	GetFolderPolicy(ctx context.Context, orgID int64, folderUID string) (definitions.FolderRulePolicy, error)
+	CheckFolderPolicy(ctx context.Context, orgID int64, rule *models.AlertRule) ([]definitions.RuleLintViolation, error)
+}
+
+// FolderService represents the ability to query, create and delete the folders of an organization.
+type FolderService interface {
+	GetFolderByUID(ctx context.Context, user *gfcore.SignedInUser, orgID int64, uid string) (*gfcore.Folder, error)
+	CreateFolder(ctx context.Context, user *gfcore.SignedInUser, orgID int64, title, uid string) (*gfcore.Folder, error)
+	DeleteFolder(ctx context.Context, user *gfcore.SignedInUser, orgID int64, uid string, forceDeleteRules bool) (*gfcore.Folder, error)
+}
+
+// FolderACLStore represents the ability to replace the permissions of a folder when access control is disabled.
+type FolderACLStore interface {
+	UpdateDashboardACL(ctx context.Context, folderID int64, items []*gfcore.DashboardAcl) error
+}
+
+// FolderPermissionSetter represents the ability to set the permissions of a folder when access control is enabled.
+type FolderPermissionSetter interface {
+	SetPermissions(ctx context.Context, orgID int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error)
+}
+
 // TransactionManager represents the ability to issue and close transactions through contexts.
 type TransactionManager interface {
 	InTransaction(ctx context.Context, work func(ctx context.Context) error) error
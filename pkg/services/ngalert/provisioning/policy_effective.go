@@ -0,0 +1,63 @@
+package provisioning
+
+import (
+	"context"
+	"sort"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// GetEffectivePolicies returns every policy of the notification policy tree, in depth-first order, with the
+// values that apply to it once they are inherited from its parents or taken from the defaults.
+func (nps *NotificationPolicyService) GetEffectivePolicies(ctx context.Context, orgID int64) ([]definitions.EffectivePolicy, error) {
+	tree, err := nps.GetPolicyTree(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return EffectivePolicies(&tree), nil
+}
+
+// EffectivePolicies resolves the values of every policy of tree the same way the Alertmanager does.
+func EffectivePolicies(tree *definitions.Route) []definitions.EffectivePolicy {
+	var result []definitions.EffectivePolicy
+	var walk func(r *definitions.Route, resolved *dispatch.Route, parentID string, parentMatchers labels.Matchers)
+	walk = func(r *definitions.Route, resolved *dispatch.Route, parentID string, parentMatchers labels.Matchers) {
+		matchers := make(labels.Matchers, 0, len(parentMatchers)+len(resolved.Matchers))
+		matchers = append(matchers, parentMatchers...)
+		matchers = append(matchers, resolved.Matchers...)
+
+		result = append(result, definitions.EffectivePolicy{
+			ID:             r.ID,
+			ParentID:       parentID,
+			ObjectMatchers: definitions.ObjectMatchers(matchers),
+			Receiver:       resolved.RouteOpts.Receiver,
+			GroupBy:        effectiveGroupBy(resolved.RouteOpts),
+			GroupWait:      model.Duration(resolved.RouteOpts.GroupWait),
+			GroupInterval:  model.Duration(resolved.RouteOpts.GroupInterval),
+			RepeatInterval: model.Duration(resolved.RouteOpts.RepeatInterval),
+			Continue:       resolved.Continue,
+		})
+		// the resolved routes are built from the routes of the tree, in the same order
+		for i, child := range r.Routes {
+			walk(child, resolved.Routes[i], r.ID, matchers)
+		}
+	}
+	walk(tree, dispatch.NewRoute(tree.AsAMRoute(), nil), "", nil)
+	return result
+}
+
+func effectiveGroupBy(opts dispatch.RouteOpts) []string {
+	if opts.GroupByAll {
+		return []string{"..."}
+	}
+	result := make([]string, 0, len(opts.GroupBy))
+	for name := range opts.GroupBy {
+		result = append(result, string(name))
+	}
+	sort.Strings(result)
+	return result
+}
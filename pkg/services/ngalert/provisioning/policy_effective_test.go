@@ -0,0 +1,64 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestEffectivePolicies(t *testing.T) {
+	groupWait := model.Duration(time.Minute)
+	repeatInterval := model.Duration(time.Hour)
+	team, err := labels.NewMatcher(labels.MatchEqual, "team", "a")
+	require.NoError(t, err)
+
+	tree := definitions.Route{
+		Receiver:   "default",
+		GroupByStr: []string{"alertname", "cluster"},
+		GroupWait:  &groupWait,
+		Routes: []*definitions.Route{
+			{
+				ID:             "team",
+				Receiver:       "team",
+				ObjectMatchers: definitions.ObjectMatchers{team},
+				RepeatInterval: &repeatInterval,
+				Routes: []*definitions.Route{
+					{ID: "critical", Match: map[string]string{"severity": "critical"}, GroupByStr: []string{"..."}, Continue: true},
+				},
+			},
+		},
+	}
+	require.NoError(t, tree.Validate())
+
+	policies := EffectivePolicies(&tree)
+
+	require.Len(t, policies, 3)
+	require.Equal(t, definitions.EffectivePolicy{
+		Receiver:       "default",
+		GroupBy:        []string{"alertname", "cluster"},
+		GroupWait:      groupWait,
+		GroupInterval:  model.Duration(5 * time.Minute),
+		RepeatInterval: model.Duration(4 * time.Hour),
+		ObjectMatchers: definitions.ObjectMatchers{},
+	}, policies[0])
+
+	require.Equal(t, "team", policies[1].ID)
+	require.Equal(t, "team", policies[1].Receiver)
+	require.Equal(t, []string{"alertname", "cluster"}, policies[1].GroupBy)
+	require.Equal(t, groupWait, policies[1].GroupWait)
+	require.Equal(t, repeatInterval, policies[1].RepeatInterval)
+
+	require.Equal(t, "critical", policies[2].ID)
+	require.Equal(t, "team", policies[2].ParentID)
+	require.Equal(t, "team", policies[2].Receiver)
+	require.Equal(t, []string{"..."}, policies[2].GroupBy)
+	require.Equal(t, repeatInterval, policies[2].RepeatInterval)
+	require.True(t, policies[2].Continue)
+	require.Equal(t, `team="a"`, policies[2].ObjectMatchers[0].String())
+	require.Equal(t, `severity="critical"`, policies[2].ObjectMatchers[1].String())
+}
@@ -0,0 +1,203 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// GetPolicyTreeGraph returns the notification policy tree with the contact points and mute timings of the
+// organization as a graph.
+func (nps *NotificationPolicyService) GetPolicyTreeGraph(ctx context.Context, orgID int64) (definitions.PolicyTreeGraph, error) {
+	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
+	if err != nil {
+		return definitions.PolicyTreeGraph{}, err
+	}
+	tree := revision.cfg.AlertmanagerConfig.Config.Route
+	if tree == nil {
+		return definitions.PolicyTreeGraph{}, fmt.Errorf("no route present in current alertmanager config")
+	}
+	provenances, err := nps.provenanceStore.GetProvenances(ctx, orgID, tree.ResourceType())
+	if err != nil {
+		return definitions.PolicyTreeGraph{}, err
+	}
+	ApplyRouteProvenances(tree, provenances)
+
+	return PolicyTreeGraph(tree, revision.cfg.AlertmanagerConfig.Receivers, revision.cfg.AlertmanagerConfig.MuteTimeIntervals), nil
+}
+
+// PolicyTreeGraph builds the graph of a policy tree. Every contact point and mute timing is part of the graph, also
+// the ones that are not used by any policy.
+func PolicyTreeGraph(tree *definitions.Route, receivers []*definitions.PostableApiReceiver, muteTimings []definitions.MuteTimeIntervalConfig) definitions.PolicyTreeGraph {
+	graph := definitions.PolicyTreeGraph{
+		Nodes: []definitions.PolicyTreeGraphNode{},
+		Edges: []definitions.PolicyTreeGraphEdge{},
+	}
+
+	var walk func(r *definitions.Route, nodeID string)
+	walk = func(r *definitions.Route, nodeID string) {
+		graph.Nodes = append(graph.Nodes, policyNode(r, nodeID, r == tree))
+		if r.Receiver != "" {
+			graph.Edges = append(graph.Edges, definitions.PolicyTreeGraphEdge{
+				From: nodeID, To: contactPointNodeID(r.Receiver), Kind: definitions.PolicyTreeGraphEdgeContactPoint,
+			})
+		}
+		for _, name := range r.MuteTimeIntervals {
+			graph.Edges = append(graph.Edges, definitions.PolicyTreeGraphEdge{
+				From: nodeID, To: muteTimingNodeID(name), Kind: definitions.PolicyTreeGraphEdgeMuteTiming,
+			})
+		}
+		for _, name := range r.ActiveTimeIntervals {
+			graph.Edges = append(graph.Edges, definitions.PolicyTreeGraphEdge{
+				From: nodeID, To: muteTimingNodeID(name), Kind: definitions.PolicyTreeGraphEdgeActiveTiming,
+			})
+		}
+		for i, child := range r.Routes {
+			childID := nodeID + "/" + strconv.Itoa(i)
+			graph.Edges = append(graph.Edges, definitions.PolicyTreeGraphEdge{
+				From: nodeID, To: childID, Kind: definitions.PolicyTreeGraphEdgeNested,
+			})
+			walk(child, childID)
+		}
+	}
+	walk(tree, definitions.PolicyTreeGraphNodePolicy)
+
+	for _, r := range receivers {
+		integrations := make([]string, 0, len(r.GrafanaManagedReceivers))
+		for _, integration := range r.GrafanaManagedReceivers {
+			integrations = append(integrations, integration.Type)
+		}
+		sort.Strings(integrations)
+		graph.Nodes = append(graph.Nodes, definitions.PolicyTreeGraphNode{
+			ID:       contactPointNodeID(r.Name),
+			Kind:     definitions.PolicyTreeGraphNodeContactPoint,
+			Label:    r.Name,
+			Metadata: map[string]string{"integrations": strings.Join(integrations, ", ")},
+		})
+	}
+	for _, mt := range muteTimings {
+		graph.Nodes = append(graph.Nodes, definitions.PolicyTreeGraphNode{
+			ID:       muteTimingNodeID(mt.Name),
+			Kind:     definitions.PolicyTreeGraphNodeMuteTiming,
+			Label:    mt.Name,
+			Metadata: map[string]string{"time_intervals": strconv.Itoa(len(mt.TimeIntervals))},
+		})
+	}
+	return graph
+}
+
+func policyNode(r *definitions.Route, nodeID string, root bool) definitions.PolicyTreeGraphNode {
+	matcherList := make([]string, 0)
+	for _, m := range routeMatchers(r) {
+		matcherList = append(matcherList, m.String())
+	}
+	matchers := strings.Join(matcherList, ", ")
+	label := "Default policy"
+	if !root {
+		label = matchers
+		if matchers == "" {
+			label = "No matchers"
+		}
+	}
+
+	metadata := map[string]string{}
+	setIfNotEmpty := func(key, value string) {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	setIfNotEmpty("id", r.ID)
+	setIfNotEmpty("receiver", r.Receiver)
+	setIfNotEmpty("matchers", matchers)
+	setIfNotEmpty("group_by", strings.Join(r.GroupByStr, ", "))
+	if r.GroupWait != nil {
+		metadata["group_wait"] = r.GroupWait.String()
+	}
+	if r.GroupInterval != nil {
+		metadata["group_interval"] = r.GroupInterval.String()
+	}
+	if r.RepeatInterval != nil {
+		metadata["repeat_interval"] = r.RepeatInterval.String()
+	}
+	if r.Continue {
+		metadata["continue"] = "true"
+	}
+	setIfNotEmpty("provenance", string(r.Provenance))
+
+	return definitions.PolicyTreeGraphNode{
+		ID:       nodeID,
+		Kind:     definitions.PolicyTreeGraphNodePolicy,
+		Label:    label,
+		Metadata: metadata,
+	}
+}
+
+func contactPointNodeID(name string) string {
+	return definitions.PolicyTreeGraphNodeContactPoint + "/" + name
+}
+
+func muteTimingNodeID(name string) string {
+	return definitions.PolicyTreeGraphNodeMuteTiming + "/" + name
+}
+
+// PolicyTreeGraphDOT renders a policy tree graph as a Graphviz DOT document. The metadata of the nodes are shown
+// as their tooltips.
+func PolicyTreeGraphDOT(graph definitions.PolicyTreeGraph) string {
+	shapes := map[string]string{
+		definitions.PolicyTreeGraphNodePolicy:       "box",
+		definitions.PolicyTreeGraphNodeContactPoint: "ellipse",
+		definitions.PolicyTreeGraphNodeMuteTiming:   "note",
+	}
+	edgeStyles := map[string]string{
+		definitions.PolicyTreeGraphEdgeNested:       "solid",
+		definitions.PolicyTreeGraphEdgeContactPoint: "dashed",
+		definitions.PolicyTreeGraphEdgeMuteTiming:   "dotted",
+		definitions.PolicyTreeGraphEdgeActiveTiming: "dotted",
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph \"notification_policies\" {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, n := range graph.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s", dotQuote(n.ID), dotQuote(n.Label), shapes[n.Kind])
+		if len(n.Metadata) > 0 {
+			fmt.Fprintf(&b, ", tooltip=%s", dotQuote(metadataText(n.Metadata)))
+		}
+		b.WriteString("];\n")
+	}
+	for _, e := range graph.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [style=%s", dotQuote(e.From), dotQuote(e.To), edgeStyles[e.Kind])
+		if e.Kind == definitions.PolicyTreeGraphEdgeMuteTiming || e.Kind == definitions.PolicyTreeGraphEdgeActiveTiming {
+			fmt.Fprintf(&b, ", label=%s", dotQuote(strings.TrimSuffix(e.Kind, "_timing")))
+		}
+		b.WriteString("];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// metadataText returns the metadata as one "key: value" line per entry, sorted by key.
+func metadataText(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, k+": "+metadata[k])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,77 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestPolicyTreeGraph(t *testing.T) {
+	tree := &definitions.Route{
+		Receiver:   "default",
+		GroupByStr: []string{"alertname"},
+		Routes: []*definitions.Route{
+			{
+				ID:                "team",
+				Receiver:          "team",
+				Match:             map[string]string{"team": "a"},
+				MuteTimeIntervals: []string{"weekends"},
+				Routes: []*definitions.Route{
+					{ID: "critical", Match: map[string]string{"severity": "critical"}, Continue: true},
+				},
+			},
+		},
+	}
+	receivers := []*definitions.PostableApiReceiver{
+		{
+			Receiver: config.Receiver{Name: "default"},
+			PostableGrafanaReceivers: definitions.PostableGrafanaReceivers{
+				GrafanaManagedReceivers: []*definitions.PostableGrafanaReceiver{{Type: "slack"}, {Type: "email"}},
+			},
+		},
+		{Receiver: config.Receiver{Name: "team"}},
+	}
+	muteTimings := []definitions.MuteTimeIntervalConfig{{Name: "weekends", TimeIntervals: []definitions.TimeInterval{{}}}}
+
+	graph := PolicyTreeGraph(tree, receivers, muteTimings)
+
+	require.Equal(t, []definitions.PolicyTreeGraphNode{
+		{ID: "policy", Kind: "policy", Label: "Default policy", Metadata: map[string]string{"receiver": "default", "group_by": "alertname"}},
+		{ID: "policy/0", Kind: "policy", Label: `team="a"`, Metadata: map[string]string{"id": "team", "receiver": "team", "matchers": `team="a"`}},
+		{ID: "policy/0/0", Kind: "policy", Label: `severity="critical"`, Metadata: map[string]string{"id": "critical", "matchers": `severity="critical"`, "continue": "true"}},
+		{ID: "contact_point/default", Kind: "contact_point", Label: "default", Metadata: map[string]string{"integrations": "email, slack"}},
+		{ID: "contact_point/team", Kind: "contact_point", Label: "team", Metadata: map[string]string{"integrations": ""}},
+		{ID: "mute_timing/weekends", Kind: "mute_timing", Label: "weekends", Metadata: map[string]string{"time_intervals": "1"}},
+	}, graph.Nodes)
+	require.Equal(t, []definitions.PolicyTreeGraphEdge{
+		{From: "policy", To: "contact_point/default", Kind: "contact_point"},
+		{From: "policy", To: "policy/0", Kind: "nested"},
+		{From: "policy/0", To: "contact_point/team", Kind: "contact_point"},
+		{From: "policy/0", To: "mute_timing/weekends", Kind: "mute_timing"},
+		{From: "policy/0", To: "policy/0/0", Kind: "nested"},
+	}, graph.Edges)
+
+	t.Run("DOT document", func(t *testing.T) {
+		dot := PolicyTreeGraphDOT(graph)
+
+		require.Contains(t, dot, "digraph \"notification_policies\" {\n")
+		require.Contains(t, dot, `  "policy/0" [label="team=\"a\"", shape=box, tooltip="id: team\nmatchers: team=\"a\"\nreceiver: team"];`)
+		require.Contains(t, dot, `  "mute_timing/weekends" [label="weekends", shape=note, tooltip="time_intervals: 1"];`)
+		require.Contains(t, dot, `  "policy/0" -> "mute_timing/weekends" [style=dotted, label="mute"];`)
+		require.Contains(t, dot, `  "policy" -> "policy/0" [style=solid];`)
+	})
+}
+
+func TestGetPolicyTreeGraph(t *testing.T) {
+	sut := createNotificationPolicyServiceSut()
+
+	graph, err := sut.GetPolicyTreeGraph(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.Equal(t, "policy", graph.Nodes[0].ID)
+	require.Contains(t, graph.Edges, definitions.PolicyTreeGraphEdge{From: "policy", To: "contact_point/grafana-default-email", Kind: "contact_point"})
+}
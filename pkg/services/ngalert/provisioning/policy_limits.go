@@ -0,0 +1,60 @@
+package provisioning
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var ErrPolicyTreeLimitExceeded = fmt.Errorf("the notification policy tree exceeds a limit")
+
+// CheckPolicyTreeLimits returns an ErrValidation wrapping ErrPolicyTreeLimitExceeded if tree has more routes, more
+// levels of routes or routes with more matchers than allowed by limits.
+func CheckPolicyTreeLimits(tree *definitions.Route, limits setting.UnifiedAlertingPolicyTreeLimits) error {
+	if tree == nil {
+		return nil
+	}
+	var routes, depth int64
+	var tooManyMatchers *definitions.Route
+	var walk func(r *definitions.Route, level int64)
+	walk = func(r *definitions.Route, level int64) {
+		routes++
+		if level > depth {
+			depth = level
+		}
+		if tooManyMatchers == nil && limits.MaxMatchersPerRoute > 0 && routeMatcherCount(r) > limits.MaxMatchersPerRoute {
+			tooManyMatchers = r
+		}
+		for _, child := range r.Routes {
+			walk(child, level+1)
+		}
+	}
+	walk(tree, 0)
+
+	if limits.MaxRoutes > 0 && routes > limits.MaxRoutes {
+		return policyTreeLimitError("the tree has %d routes, at most %d are allowed", routes, limits.MaxRoutes)
+	}
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return policyTreeLimitError("the tree has %d levels of nested routes, at most %d are allowed", depth, limits.MaxDepth)
+	}
+	if tooManyMatchers != nil {
+		name := "the root route"
+		if tooManyMatchers.ID != "" {
+			name = fmt.Sprintf("the route %q", tooManyMatchers.ID)
+		} else if tooManyMatchers != tree {
+			name = fmt.Sprintf("a route of the receiver %q", tooManyMatchers.Receiver)
+		}
+		return policyTreeLimitError("%s has %d matchers, at most %d are allowed", name, routeMatcherCount(tooManyMatchers), limits.MaxMatchersPerRoute)
+	}
+	return nil
+}
+
+// routeMatcherCount returns the number of matchers of a route in all of the formats they can be given in.
+func routeMatcherCount(r *definitions.Route) int64 {
+	return int64(len(r.Match) + len(r.MatchRE) + len(r.Matchers) + len(r.ObjectMatchers))
+}
+
+func policyTreeLimitError(format string, args ...interface{}) error {
+	return validationError{fmt.Errorf("%w: "+format, append([]interface{}{ErrPolicyTreeLimitExceeded}, args...)...)}
+}
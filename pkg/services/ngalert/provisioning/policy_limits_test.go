@@ -0,0 +1,56 @@
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCheckPolicyTreeLimits(t *testing.T) {
+	tree := &definitions.Route{
+		Receiver: "default",
+		Routes: []*definitions.Route{
+			{
+				ID:    "team",
+				Match: map[string]string{"team": "a"},
+				Routes: []*definitions.Route{
+					{ID: "critical", Match: map[string]string{"team": "a", "severity": "critical"}},
+				},
+			},
+			{Receiver: "other", Match: map[string]string{"team": "b", "env": "prod", "region": "eu"}},
+		},
+	}
+
+	t.Run("trees within the limits are accepted", func(t *testing.T) {
+		require.NoError(t, CheckPolicyTreeLimits(tree, setting.UnifiedAlertingPolicyTreeLimits{MaxRoutes: 4, MaxDepth: 2, MaxMatchersPerRoute: 3}))
+	})
+
+	t.Run("limits of zero or less are not enforced", func(t *testing.T) {
+		require.NoError(t, CheckPolicyTreeLimits(tree, setting.UnifiedAlertingPolicyTreeLimits{MaxRoutes: -1}))
+	})
+
+	t.Run("too many routes are rejected", func(t *testing.T) {
+		err := CheckPolicyTreeLimits(tree, setting.UnifiedAlertingPolicyTreeLimits{MaxRoutes: 3})
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorIs(t, err, ErrPolicyTreeLimitExceeded)
+		require.Contains(t, err.Error(), "the tree has 4 routes, at most 3 are allowed")
+	})
+
+	t.Run("too deeply nested routes are rejected", func(t *testing.T) {
+		err := CheckPolicyTreeLimits(tree, setting.UnifiedAlertingPolicyTreeLimits{MaxDepth: 1})
+		require.ErrorIs(t, err, ErrPolicyTreeLimitExceeded)
+		require.Contains(t, err.Error(), "the tree has 2 levels of nested routes, at most 1 are allowed")
+	})
+
+	t.Run("routes with too many matchers are rejected", func(t *testing.T) {
+		err := CheckPolicyTreeLimits(tree, setting.UnifiedAlertingPolicyTreeLimits{MaxMatchersPerRoute: 2})
+		require.ErrorIs(t, err, ErrPolicyTreeLimitExceeded)
+		require.Contains(t, err.Error(), `a route of the receiver "other" has 3 matchers, at most 2 are allowed`)
+
+		err = CheckPolicyTreeLimits(tree, setting.UnifiedAlertingPolicyTreeLimits{MaxMatchersPerRoute: 1})
+		require.Contains(t, err.Error(), `the route "critical" has 2 matchers`)
+	})
+}
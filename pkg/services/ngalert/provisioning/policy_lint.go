@@ -0,0 +1,90 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	prommodel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// builtinAlertLabels are the labels that Grafana adds to every alert, in addition to the labels of the alert rule.
+var builtinAlertLabels = []string{
+	prommodel.AlertNameLabel,
+	models.FolderTitleLabel,
+	models.RuleUIDLabel,
+	models.NamespaceUIDLabel,
+}
+
+// LintPolicyTree checks the label names used by the matchers of the policy tree. It returns a warning for every matcher
+// with a label name that is not a valid Prometheus label name, and for every matcher with a label that none of the
+// alert rules of the organization has. Labels coming from the results of the queries of a rule are not known in
+// advance, matchers on such labels are reported as well.
+func (nps *NotificationPolicyService) LintPolicyTree(ctx context.Context, orgID int64, tree definitions.Route) ([]definitions.PolicyTreeLintWarning, error) {
+	q := models.ListAlertRulesQuery{OrgID: orgID}
+	if err := nps.ruleStore.ListAlertRules(ctx, &q); err != nil {
+		return nil, err
+	}
+	known := make(map[string]struct{}, len(builtinAlertLabels))
+	for _, l := range builtinAlertLabels {
+		known[l] = struct{}{}
+	}
+	for _, rule := range q.Result {
+		for l := range rule.Labels {
+			known[l] = struct{}{}
+		}
+	}
+
+	warnings := []definitions.PolicyTreeLintWarning{}
+	walkRoutes(&tree, nil, func(r, _ *definitions.Route) {
+		for _, m := range routeMatchers(r) {
+			warning := definitions.PolicyTreeLintWarning{
+				PolicyID: r.ID,
+				Receiver: r.Receiver,
+				Matcher:  m.String(),
+				Label:    m.Name,
+			}
+			if !prommodel.LabelName(m.Name).IsValid() {
+				warning.Message = fmt.Sprintf("label name '%s' is not a valid Prometheus label name", m.Name)
+				warnings = append(warnings, warning)
+			}
+			if _, ok := known[m.Name]; !ok {
+				warning.Message = fmt.Sprintf("no alert rule has the label '%s'", m.Name)
+				warnings = append(warnings, warning)
+			}
+		}
+	})
+	return warnings, nil
+}
+
+// routeMatchers returns all matchers of a route, including the deprecated match and match_re ones.
+func routeMatchers(r *definitions.Route) labels.Matchers {
+	var result labels.Matchers
+	result = append(result, r.Matchers...)
+	result = append(result, r.ObjectMatchers...)
+
+	// maps are sorted to report the matchers in a stable order
+	names := make([]string, 0, len(r.Match))
+	for name := range r.Match {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result = append(result, &labels.Matcher{Type: labels.MatchEqual, Name: name, Value: r.Match[name]})
+	}
+	names = names[:0]
+	for name := range r.MatchRE {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, _ := r.MatchRE[name].MarshalYAML()
+		s, _ := value.(string)
+		result = append(result, &labels.Matcher{Type: labels.MatchRegexp, Name: name, Value: s})
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestLintPolicyTree(t *testing.T) {
+	sut := createNotificationPolicyServiceSut()
+	sut.ruleStore = &fakeRuleReader{rules: []*models.AlertRule{
+		{OrgID: 1, Labels: map[string]string{"team": "a"}},
+		{OrgID: 1, Labels: map[string]string{"severity": "critical"}},
+		{OrgID: 2, Labels: map[string]string{"cluster": "eu"}},
+	}}
+
+	lintMatcher := func(t *testing.T, typ labels.MatchType, name string) *labels.Matcher {
+		m, err := labels.NewMatcher(typ, name, "value")
+		require.NoError(t, err)
+		return m
+	}
+
+	t.Run("no warnings for labels of the rules and labels added by grafana", func(t *testing.T) {
+		tree := definitions.Route{
+			Receiver: "a receiver",
+			Routes: []*definitions.Route{
+				{ObjectMatchers: definitions.ObjectMatchers{lintMatcher(t, labels.MatchEqual, "team")}},
+				{Matchers: config.Matchers{lintMatcher(t, labels.MatchNotEqual, "severity")}},
+				{Match: map[string]string{"alertname": "test"}},
+				{ObjectMatchers: definitions.ObjectMatchers{lintMatcher(t, labels.MatchEqual, models.FolderTitleLabel)}},
+			},
+		}
+
+		warnings, err := sut.LintPolicyTree(context.Background(), 1, tree)
+		require.NoError(t, err)
+		require.NotNil(t, warnings)
+		require.Empty(t, warnings)
+	})
+
+	t.Run("warnings for labels that no rule has", func(t *testing.T) {
+		tree := definitions.Route{
+			Receiver: "a receiver",
+			Routes: []*definitions.Route{
+				{ID: "team-route", Receiver: "team", ObjectMatchers: definitions.ObjectMatchers{lintMatcher(t, labels.MatchEqual, "teams")}},
+				{ObjectMatchers: definitions.ObjectMatchers{lintMatcher(t, labels.MatchEqual, "cluster")}},
+			},
+		}
+
+		warnings, err := sut.LintPolicyTree(context.Background(), 1, tree)
+		require.NoError(t, err)
+		require.Len(t, warnings, 2)
+		require.Equal(t, "team-route", warnings[0].PolicyID)
+		require.Equal(t, "team", warnings[0].Receiver)
+		require.Equal(t, "teams", warnings[0].Label)
+		require.Equal(t, `teams="value"`, warnings[0].Matcher)
+		// the labels of rules in other organizations are not known
+		require.Equal(t, "cluster", warnings[1].Label)
+	})
+
+	t.Run("warnings for invalid label names", func(t *testing.T) {
+		tree := definitions.Route{
+			Receiver: "a receiver",
+			Routes: []*definitions.Route{
+				{ObjectMatchers: definitions.ObjectMatchers{lintMatcher(t, labels.MatchEqual, "team-name")}},
+			},
+		}
+
+		warnings, err := sut.LintPolicyTree(context.Background(), 1, tree)
+		require.NoError(t, err)
+		require.Len(t, warnings, 2)
+		require.Contains(t, warnings[0].Message, "not a valid Prometheus label name")
+		require.Contains(t, warnings[1].Message, "no alert rule has the label")
+	})
+}
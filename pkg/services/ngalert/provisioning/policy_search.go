@@ -0,0 +1,48 @@
+package provisioning
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// GetPoliciesByReceiver returns the policies of the notification policy tree that send their alerts to the
+// receiver, in depth-first order.
+func (nps *NotificationPolicyService) GetPoliciesByReceiver(ctx context.Context, orgID int64, receiver string) ([]definitions.ReceiverPolicy, error) {
+	tree, err := nps.GetPolicyTree(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return PoliciesByReceiver(&tree, receiver), nil
+}
+
+// PoliciesByReceiver returns the policies of tree that use the receiver, either as their own receiver or
+// inherited from their parent.
+func PoliciesByReceiver(tree *definitions.Route, receiver string) []definitions.ReceiverPolicy {
+	result := []definitions.ReceiverPolicy{}
+	var walk func(r *definitions.Route, inheritedReceiver string, path []int, parentIDs []string)
+	walk = func(r *definitions.Route, inheritedReceiver string, path []int, parentIDs []string) {
+		effective := r.Receiver
+		if effective == "" {
+			effective = inheritedReceiver
+		}
+		if effective == receiver {
+			route := *r
+			route.Routes = nil
+			result = append(result, definitions.ReceiverPolicy{
+				Path:      append([]int{}, path...),
+				ParentIDs: append([]string{}, parentIDs...),
+				Inherited: r.Receiver == "",
+				Route:     route,
+			})
+		}
+		if r != tree {
+			parentIDs = append(parentIDs, r.ID)
+		}
+		for i, child := range r.Routes {
+			walk(child, effective, append(path, i), parentIDs)
+		}
+	}
+	walk(tree, "", []int{}, []string{})
+	return result
+}
@@ -0,0 +1,61 @@
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestPoliciesByReceiver(t *testing.T) {
+	tree := definitions.Route{
+		Receiver: "default",
+		Routes: []*definitions.Route{
+			{
+				ID:       "team",
+				Receiver: "team",
+				Match:    map[string]string{"team": "a"},
+				Routes: []*definitions.Route{
+					{ID: "critical", Receiver: "pager", Match: map[string]string{"severity": "critical"}},
+					{ID: "inherited", Match: map[string]string{"severity": "warning"}},
+				},
+			},
+			{ID: "fallback", Match: map[string]string{"team": "b"}},
+		},
+	}
+
+	t.Run("returns the policies using the receiver with their path", func(t *testing.T) {
+		policies := PoliciesByReceiver(&tree, "team")
+
+		require.Len(t, policies, 2)
+		require.Equal(t, []int{0}, policies[0].Path)
+		require.Equal(t, []string{}, policies[0].ParentIDs)
+		require.False(t, policies[0].Inherited)
+		require.Equal(t, "team", policies[0].Route.ID)
+		require.Nil(t, policies[0].Route.Routes)
+		require.Equal(t, []int{0, 1}, policies[1].Path)
+		require.Equal(t, []string{"team"}, policies[1].ParentIDs)
+		require.True(t, policies[1].Inherited)
+		require.Equal(t, "inherited", policies[1].Route.ID)
+	})
+
+	t.Run("includes the root policy", func(t *testing.T) {
+		policies := PoliciesByReceiver(&tree, "default")
+
+		require.Len(t, policies, 2)
+		require.Equal(t, []int{}, policies[0].Path)
+		require.Equal(t, "", policies[0].Route.ID)
+		require.Equal(t, []int{1}, policies[1].Path)
+		require.True(t, policies[1].Inherited)
+	})
+
+	t.Run("returns an empty list for an unused receiver", func(t *testing.T) {
+		require.Empty(t, PoliciesByReceiver(&tree, "unused"))
+	})
+
+	t.Run("does not change the tree", func(t *testing.T) {
+		_ = PoliciesByReceiver(&tree, "pager")
+		require.Len(t, tree.Routes[0].Routes, 2)
+	})
+}
@@ -0,0 +1,194 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// policyTreeTemplate is a starter policy tree, built from the values of its placeholders.
+type policyTreeTemplate struct {
+	definitions.PolicyTreeTemplate
+	build func(values map[string]string) (definitions.Route, error)
+}
+
+var policyTreeTemplates = []policyTreeTemplate{
+	{
+		PolicyTreeTemplate: definitions.PolicyTreeTemplate{
+			Name:        "per-team",
+			Description: "Sends the alerts of each team to the contact point of the team, and all other alerts to the default contact point.",
+			Placeholders: []definitions.PolicyTreeTemplatePlaceholder{
+				{Name: "default_receiver", Description: "Contact point of the alerts that do not belong to any of the teams."},
+				{Name: "team_label", Description: "Label with the team of an alert.", Default: "team"},
+				{Name: "teams", Description: "Comma separated list of teams. Each team is the value of the team label, optionally followed by '=' and the contact point of the team. The contact point has the name of the team if it is not given."},
+			},
+		},
+		build: buildPerTeamPolicyTree,
+	},
+	{
+		PolicyTreeTemplate: definitions.PolicyTreeTemplate{
+			Name:        "severity-escalation",
+			Description: "Sends critical alerts quickly and repeats them often, warnings less often, and all other alerts to the default contact point.",
+			Placeholders: []definitions.PolicyTreeTemplatePlaceholder{
+				{Name: "default_receiver", Description: "Contact point of the alerts that are neither critical nor warnings."},
+				{Name: "critical_receiver", Description: "Contact point of the critical alerts."},
+				{Name: "warning_receiver", Description: "Contact point of the warnings."},
+				{Name: "severity_label", Description: "Label with the severity of an alert.", Default: "severity"},
+				{Name: "critical_value", Description: "Value of the severity label of critical alerts.", Default: "critical"},
+				{Name: "warning_value", Description: "Value of the severity label of warnings.", Default: "warning"},
+			},
+		},
+		build: buildSeverityEscalationPolicyTree,
+	},
+}
+
+// PolicyTreeTemplates returns the templates a policy tree can be created from.
+func PolicyTreeTemplates() []definitions.PolicyTreeTemplate {
+	result := make([]definitions.PolicyTreeTemplate, 0, len(policyTreeTemplates))
+	for _, t := range policyTreeTemplates {
+		result = append(result, t.PolicyTreeTemplate)
+	}
+	return result
+}
+
+// ApplyPolicyTreeTemplate replaces the policy tree of an org with the tree created from a template, with its
+// placeholders filled in by values. The tree is saved like any other update of the policy tree, so the contact
+// points it uses must exist. Returns the new tree.
+func (nps *NotificationPolicyService) ApplyPolicyTreeTemplate(ctx context.Context, orgID int64, name string, values map[string]string, p models.Provenance) (definitions.Route, error) {
+	var template *policyTreeTemplate
+	for i := range policyTreeTemplates {
+		if policyTreeTemplates[i].Name == name {
+			template = &policyTreeTemplates[i]
+			break
+		}
+	}
+	if template == nil {
+		return definitions.Route{}, fmt.Errorf("%w: policy tree template '%s' does not exist", ErrNotFound, name)
+	}
+
+	values, err := placeholderValues(template.Placeholders, values)
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	tree, err := template.build(values)
+	if err != nil {
+		return definitions.Route{}, err
+	}
+
+	if err := nps.UpdatePolicyTree(ctx, orgID, tree, p); err != nil {
+		return definitions.Route{}, err
+	}
+	return nps.GetPolicyTree(ctx, orgID)
+}
+
+// placeholderValues returns the values of all placeholders, with the defaults of the placeholders that have no value.
+func placeholderValues(placeholders []definitions.PolicyTreeTemplatePlaceholder, values map[string]string) (map[string]string, error) {
+	known := make(map[string]struct{}, len(placeholders))
+	result := make(map[string]string, len(placeholders))
+	var missing []string
+	for _, ph := range placeholders {
+		known[ph.Name] = struct{}{}
+		value := strings.TrimSpace(values[ph.Name])
+		if value == "" {
+			value = ph.Default
+		}
+		if value == "" {
+			missing = append(missing, ph.Name)
+		}
+		result[ph.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing values for the placeholders %s", ErrValidation, strings.Join(missing, ", "))
+	}
+
+	var unknown []string
+	for name := range values {
+		if _, ok := known[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: unknown placeholders %s", ErrValidation, strings.Join(unknown, ", "))
+	}
+	return result, nil
+}
+
+func buildPerTeamPolicyTree(values map[string]string) (definitions.Route, error) {
+	tree := templateRootRoute(values["default_receiver"])
+	seen := map[string]struct{}{}
+	for _, entry := range strings.Split(values["teams"], ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		team, receiver := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[0])
+		if len(parts) == 2 {
+			receiver = strings.TrimSpace(parts[1])
+		}
+		if team == "" || receiver == "" {
+			return definitions.Route{}, fmt.Errorf("%w: invalid team '%s'", ErrValidation, strings.TrimSpace(entry))
+		}
+		if _, ok := seen[team]; ok {
+			return definitions.Route{}, fmt.Errorf("%w: team '%s' is listed more than once", ErrValidation, team)
+		}
+		seen[team] = struct{}{}
+
+		route, err := templateRoute(receiver, values["team_label"], team)
+		if err != nil {
+			return definitions.Route{}, err
+		}
+		tree.Routes = append(tree.Routes, route)
+	}
+	return tree, nil
+}
+
+func buildSeverityEscalationPolicyTree(values map[string]string) (definitions.Route, error) {
+	tree := templateRootRoute(values["default_receiver"])
+
+	critical, err := templateRoute(values["critical_receiver"], values["severity_label"], values["critical_value"])
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	critical.GroupWait = durationPtr(10 * time.Second)
+	critical.GroupInterval = durationPtr(time.Minute)
+	critical.RepeatInterval = durationPtr(time.Hour)
+
+	warning, err := templateRoute(values["warning_receiver"], values["severity_label"], values["warning_value"])
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	warning.RepeatInterval = durationPtr(12 * time.Hour)
+
+	tree.Routes = []*definitions.Route{critical, warning}
+	return tree, nil
+}
+
+// templateRootRoute returns the root route of a template, it groups the alerts by folder and alert rule.
+func templateRootRoute(receiver string) definitions.Route {
+	return definitions.Route{
+		Receiver:   receiver,
+		GroupByStr: []string{models.FolderTitleLabel, model.AlertNameLabel},
+	}
+}
+
+func templateRoute(receiver, label, value string) (*definitions.Route, error) {
+	m, err := labels.NewMatcher(labels.MatchEqual, label, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	return &definitions.Route{
+		Receiver:       receiver,
+		ObjectMatchers: definitions.ObjectMatchers{m},
+	}, nil
+}
+
+func durationPtr(d time.Duration) *model.Duration {
+	md := model.Duration(d)
+	return &md
+}
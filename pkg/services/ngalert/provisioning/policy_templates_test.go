@@ -0,0 +1,99 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestPolicyTreeTemplates(t *testing.T) {
+	templates := PolicyTreeTemplates()
+	require.Len(t, templates, len(policyTreeTemplates))
+	for _, template := range templates {
+		require.NotEmpty(t, template.Description)
+		require.NotEmpty(t, template.Placeholders)
+	}
+}
+
+func TestApplyPolicyTreeTemplate(t *testing.T) {
+	t.Run("per-team template routes each team to its contact point", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		tree, err := sut.ApplyPolicyTreeTemplate(context.Background(), 1, "per-team", map[string]string{
+			"default_receiver": "grafana-default-email",
+			"teams":            "a new receiver, backend=grafana-default-email",
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Equal(t, "grafana-default-email", tree.Receiver)
+		require.Equal(t, models.ProvenanceAPI, tree.Provenance)
+		require.Len(t, tree.Routes, 2)
+		require.Equal(t, "a new receiver", tree.Routes[0].Receiver)
+		require.Equal(t, `team="a new receiver"`, tree.Routes[0].ObjectMatchers[0].String())
+		require.Equal(t, "grafana-default-email", tree.Routes[1].Receiver)
+		require.Equal(t, `team="backend"`, tree.Routes[1].ObjectMatchers[0].String())
+		require.NotEmpty(t, tree.Routes[0].ID)
+		history, err := sut.GetPolicyTreeHistory(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+	})
+
+	t.Run("severity escalation template uses the values of the placeholders", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		tree, err := sut.ApplyPolicyTreeTemplate(context.Background(), 1, "severity-escalation", map[string]string{
+			"default_receiver":  "grafana-default-email",
+			"critical_receiver": "a new receiver",
+			"warning_receiver":  "grafana-default-email",
+			"severity_label":    "level",
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Len(t, tree.Routes, 2)
+		require.Equal(t, `level="critical"`, tree.Routes[0].ObjectMatchers[0].String())
+		require.Equal(t, model.Duration(time.Hour), *tree.Routes[0].RepeatInterval)
+		require.Equal(t, `level="warning"`, tree.Routes[1].ObjectMatchers[0].String())
+	})
+
+	t.Run("unknown template returns ErrNotFound", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		_, err := sut.ApplyPolicyTreeTemplate(context.Background(), 1, "unknown", nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("invalid values return ErrValidation", func(t *testing.T) {
+		tests := map[string]map[string]string{
+			"missing placeholder": {"default_receiver": "grafana-default-email"},
+			"unknown placeholder": {"default_receiver": "grafana-default-email", "teams": "a", "other": "b"},
+			"empty team":          {"default_receiver": "grafana-default-email", "teams": "a,,b"},
+			"duplicate team":      {"default_receiver": "grafana-default-email", "teams": "a,a=b"},
+			"unknown receiver":    {"default_receiver": "grafana-default-email", "teams": "unknown"},
+		}
+		for name, values := range tests {
+			t.Run(name, func(t *testing.T) {
+				sut := createNotificationPolicyServiceSut()
+
+				_, err := sut.ApplyPolicyTreeTemplate(context.Background(), 1, "per-team", values, models.ProvenanceAPI)
+				require.ErrorIs(t, err, ErrValidation)
+			})
+		}
+	})
+}
+
+func TestPlaceholderValues(t *testing.T) {
+	placeholders := []definitions.PolicyTreeTemplatePlaceholder{
+		{Name: "required"},
+		{Name: "optional", Default: "default"},
+	}
+
+	values, err := placeholderValues(placeholders, map[string]string{"required": " value "})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"required": "value", "optional": "default"}, values)
+}
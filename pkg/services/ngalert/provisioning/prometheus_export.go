@@ -0,0 +1,162 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/classic"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// RuleGroupsPrometheus renders the rule groups of an export as a Prometheus rule file that can be loaded by the
+// rulers of Prometheus, Mimir or Loki. Only the rules that query a single Prometheus or Loki datasource can be
+// converted, and their condition must either be the one of the rules imported from a rule file, or a classic
+// condition with a single threshold on the last value of the query. The rules that cannot be converted are left
+// out and listed in a comment at the top of the file, and the groups without any converted rule are left out. The
+// labels of the groups are merged into the labels of their rules.
+func RuleGroupsPrometheus(export definitions.AlertRulesExport) ([]byte, error) {
+	file := prometheusRuleFile{Groups: make([]prometheusRuleGroup, 0, len(export.Groups))}
+	var skipped []string
+	for _, g := range export.Groups {
+		group := prometheusRuleGroup{
+			Name:     g.Name,
+			Interval: g.Interval,
+			Rules:    make([]definitions.ApiRuleNode, 0, len(g.Rules)),
+		}
+		for _, r := range g.Rules {
+			expression, err := prometheusRuleExpr(r)
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("# - group '%s', rule '%s': %s", g.Name, r.Title, err.Error()))
+				continue
+			}
+			node := definitions.ApiRuleNode{
+				Alert:       r.Title,
+				Expr:        expression,
+				Labels:      mergeGroupLabels(r.Labels, g.Labels),
+				Annotations: r.Annotations,
+			}
+			if r.For > 0 {
+				duration := r.For
+				node.For = &duration
+			}
+			group.Rules = append(group.Rules, node)
+		}
+		if len(group.Rules) > 0 {
+			file.Groups = append(file.Groups, group)
+		}
+	}
+
+	body, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) == 0 {
+		return body, nil
+	}
+	header := "# The following rules cannot be converted and are left out:\n" + strings.Join(skipped, "\n") + "\n"
+	return append([]byte(header), body...), nil
+}
+
+// prometheusRuleExpr returns the PromQL or LogQL expression that fires for the same series as the rule, or an error
+// explaining why the rule cannot be converted.
+func prometheusRuleExpr(r definitions.AlertRuleExport) (string, error) {
+	if r.IsPaused {
+		return "", errors.New("the rule is paused")
+	}
+	var query, condition *definitions.AlertQueryExport
+	for i := range r.Data {
+		q := &r.Data[i]
+		if q.RefID == r.Condition {
+			condition = q
+		}
+		if expr.IsDataSource(q.DatasourceUID) {
+			continue
+		}
+		if query != nil {
+			return "", errors.New("the rule queries more than one datasource")
+		}
+		query = q
+	}
+	if query == nil {
+		return "", errors.New("the rule has no query")
+	}
+	queryExpr, ok := query.Model["expr"].(string)
+	if !ok || queryExpr == "" {
+		return "", errors.New("the query is not a Prometheus or Loki query")
+	}
+	if condition == nil || condition == query || len(r.Data) != 2 {
+		return "", errors.New("the condition must be a single expression on the query")
+	}
+
+	switch condition.Model["type"] {
+	case "math":
+		if condition.Model["expression"] != strings.ReplaceAll(prometheusCondition, "$A", "$"+query.RefID) {
+			return "", errors.New("the math expression is not supported")
+		}
+		return queryExpr, nil
+	case "classic_conditions":
+		return classicConditionExpr(queryExpr, query, condition.Model["conditions"])
+	default:
+		return "", fmt.Errorf("expressions of type '%v' are not supported", condition.Model["type"])
+	}
+}
+
+// classicConditionExpr appends the threshold of a classic condition to the expression of the query. The reducer must
+// be last, or any reducer that keeps the value of a single sample if the query is an instant query.
+func classicConditionExpr(queryExpr string, query *definitions.AlertQueryExport, raw interface{}) (string, error) {
+	body, err := json.Marshal(raw)
+	if err != nil {
+		return "", err
+	}
+	var conditions []classic.ClassicConditionJSON
+	if err := json.Unmarshal(body, &conditions); err != nil {
+		return "", fmt.Errorf("invalid classic condition: %w", err)
+	}
+	if len(conditions) != 1 {
+		return "", errors.New("classic conditions with more than one condition are not supported")
+	}
+	c := conditions[0]
+	if len(c.Query.Params) == 0 || c.Query.Params[0] != query.RefID {
+		return "", errors.New("the classic condition does not reference the query")
+	}
+	instant, _ := query.Model["instant"].(bool)
+	switch c.Reducer.Type {
+	case "last":
+	case "avg", "min", "max", "median":
+		if !instant {
+			return "", fmt.Errorf("the reducer '%s' is only supported for instant queries", c.Reducer.Type)
+		}
+	default:
+		return "", fmt.Errorf("the reducer '%s' is not supported", c.Reducer.Type)
+	}
+
+	params := c.Evaluator.Params
+	switch {
+	case (c.Evaluator.Type == "gt" || c.Evaluator.Type == "lt") && len(params) > 0:
+		operator := ">"
+		if c.Evaluator.Type == "lt" {
+			operator = "<"
+		}
+		return fmt.Sprintf("(%s) %s %s", queryExpr, operator, formatThreshold(params[0])), nil
+	case (c.Evaluator.Type == "within_range" || c.Evaluator.Type == "outside_range") && len(params) == 2:
+		// the bounds of ranges can be in any order
+		lower, upper := formatThreshold(math.Min(params[0], params[1])), formatThreshold(math.Max(params[0], params[1]))
+		if c.Evaluator.Type == "within_range" {
+			return fmt.Sprintf("(%s) > %s < %s", queryExpr, lower, upper), nil
+		}
+		return fmt.Sprintf("(%s) < %s or (%s) > %s", queryExpr, lower, queryExpr, upper), nil
+	default:
+		return "", fmt.Errorf("the evaluator '%s' is not supported", c.Evaluator.Type)
+	}
+}
+
+func formatThreshold(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,129 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRuleGroupsPrometheus(t *testing.T) {
+	t.Run("imported rules are converted back", func(t *testing.T) {
+		groups, _, err := ConvertPrometheusRules(prometheusRulesConfig, "folder", "prometheus", 60)
+		require.NoError(t, err)
+		export := definitions.AlertRulesExport{}
+		for _, g := range groups {
+			group := definitions.AlertRuleGroupExport{Name: g.Title, Interval: model.Duration(time.Duration(g.Interval) * time.Second), Labels: g.Labels}
+			for _, r := range g.Rules {
+				rule, err := exportAlertRule(r)
+				require.NoError(t, err)
+				group.Rules = append(group.Rules, rule)
+			}
+			export.Groups = append(export.Groups, group)
+		}
+
+		body, err := RuleGroupsPrometheus(export)
+
+		require.NoError(t, err)
+		require.Equal(t, `groups:
+    - name: api
+      interval: 30s
+      rules:
+        - alert: HighErrorRate
+          expr: job:http_errors:rate5m / job:http_requests:rate5m > 0.05
+          for: 10m
+          labels:
+            severity: page
+          annotations:
+            summary: '{{ $labels.job }} has a high error rate'
+    - name: nodes
+      interval: 1m
+      rules:
+        - alert: InstanceDown
+          expr: up == 0
+          labels:
+            team: infra
+`, string(body))
+	})
+
+	t.Run("classic conditions are converted to thresholds", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			reducer   string
+			evaluator string
+			params    []interface{}
+			instant   bool
+			expected  string
+		}{
+			"greater than":         {reducer: "last", evaluator: "gt", params: []interface{}{0.5}, expected: "(up) > 0.5"},
+			"lower than":           {reducer: "last", evaluator: "lt", params: []interface{}{1}, expected: "(up) < 1"},
+			"within range":         {reducer: "last", evaluator: "within_range", params: []interface{}{10, 1}, expected: "(up) > 1 < 10"},
+			"outside range":        {reducer: "last", evaluator: "outside_range", params: []interface{}{1, 10}, expected: "(up) < 1 or (up) > 10"},
+			"instant query reduce": {reducer: "max", evaluator: "gt", params: []interface{}{1e6}, instant: true, expected: "(up) > 1e+06"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				rule := classicConditionRule(tc.reducer, tc.evaluator, tc.params, tc.instant)
+				expression, err := prometheusRuleExpr(rule)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, expression)
+			})
+		}
+	})
+
+	t.Run("rules that cannot be converted are listed", func(t *testing.T) {
+		paused := classicConditionRule("last", "gt", []interface{}{1}, false)
+		paused.Title = "paused"
+		paused.IsPaused = true
+		rangeReducer := classicConditionRule("avg", "gt", []interface{}{1}, false)
+		rangeReducer.Title = "range reducer"
+		noValue := classicConditionRule("last", "no_value", nil, false)
+		noValue.Title = "no value"
+		twoQueries := classicConditionRule("last", "gt", []interface{}{1}, false)
+		twoQueries.Title = "two queries"
+		twoQueries.Data = append(twoQueries.Data, definitions.AlertQueryExport{RefID: "C", DatasourceUID: "loki", Model: map[string]interface{}{"expr": "{job=\"a\"}"}})
+		export := definitions.AlertRulesExport{Groups: []definitions.AlertRuleGroupExport{{
+			Name:     "group",
+			Interval: model.Duration(time.Minute),
+			Rules:    []definitions.AlertRuleExport{paused, rangeReducer, noValue, twoQueries, classicConditionRule("last", "gt", []interface{}{1}, false)},
+		}}}
+
+		body, err := RuleGroupsPrometheus(export)
+
+		require.NoError(t, err)
+		require.Equal(t, `# The following rules cannot be converted and are left out:
+# - group 'group', rule 'paused': the rule is paused
+# - group 'group', rule 'range reducer': the reducer 'avg' is only supported for instant queries
+# - group 'group', rule 'no value': the evaluator 'no_value' is not supported
+# - group 'group', rule 'two queries': the rule queries more than one datasource
+groups:
+    - name: group
+      interval: 1m
+      rules:
+        - alert: up
+          expr: (up) > 1
+`, string(body))
+	})
+}
+
+func classicConditionRule(reducer, evaluator string, params []interface{}, instant bool) definitions.AlertRuleExport {
+	return definitions.AlertRuleExport{
+		Title:     "up",
+		Condition: "B",
+		Data: []definitions.AlertQueryExport{
+			{RefID: "A", DatasourceUID: "prometheus", Model: map[string]interface{}{"refId": "A", "expr": "up", "instant": instant}},
+			{RefID: "B", DatasourceUID: expr.DatasourceUID, Model: map[string]interface{}{
+				"refId": "B",
+				"type":  "classic_conditions",
+				"conditions": []interface{}{map[string]interface{}{
+					"evaluator": map[string]interface{}{"type": evaluator, "params": params},
+					"operator":  map[string]interface{}{"type": "and"},
+					"query":     map[string]interface{}{"params": []interface{}{"A"}},
+					"reducer":   map[string]interface{}{"type": reducer},
+				}},
+			}},
+		},
+	}
+}
@@ -0,0 +1,182 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// prometheusQueryRange is the time range of the queries of the imported rules. Their expressions are evaluated as
+// instant queries, so it only needs to cover the range vectors that they select.
+const prometheusQueryRange = 10 * time.Minute
+
+// prometheusCondition fires for every series returned by the query, like a Prometheus alerting rule does.
+const prometheusCondition = "is_number($A) || is_nan($A) || is_inf($A)"
+
+type prometheusRuleFile struct {
+	Groups []prometheusRuleGroup `yaml:"groups"`
+}
+
+type prometheusRuleGroup struct {
+	Name     string                    `yaml:"name"`
+	Interval prommodel.Duration        `yaml:"interval"`
+	Labels   map[string]string         `yaml:"labels,omitempty"`
+	Rules    []definitions.ApiRuleNode `yaml:"rules"`
+}
+
+// ImportPrometheusRules converts the alerting rules of a Prometheus or Loki rule file to Grafana-managed rules of the
+// folder that query the datasource, and imports them with ImportRuleGroups with deterministic UIDs, so importing the
+// file again updates them. Recording rules are not imported and are reported in the result. With
+// dryRun, the result is returned without saving anything.
+func (service *AlertRuleService) ImportPrometheusRules(ctx context.Context, orgID int64, imp definitions.PrometheusRulesImport, dryRun bool) (definitions.PrometheusRulesImportResult, error) {
+	if imp.FolderUID == "" || imp.DatasourceUID == "" {
+		return definitions.PrometheusRulesImportResult{}, fmt.Errorf("%w: the folder and the datasource are required", ErrValidation)
+	}
+	groups, skipped, err := ConvertPrometheusRules(imp.Config, imp.FolderUID, imp.DatasourceUID, service.defaultIntervalSeconds)
+	if err != nil {
+		return definitions.PrometheusRulesImportResult{}, err
+	}
+
+	result := definitions.PrometheusRulesImportResult{
+		DryRun:  dryRun,
+		Groups:  make([]definitions.AlertRuleGroupExport, 0, len(groups)),
+		Skipped: skipped,
+	}
+	for _, group := range groups {
+		export := definitions.AlertRuleGroupExport{
+			OrgID:     orgID,
+			Name:      group.Title,
+			FolderUID: group.FolderUID,
+			Interval:  prommodel.Duration(time.Duration(group.Interval) * time.Second),
+			Labels:    group.Labels,
+			Rules:     make([]definitions.AlertRuleExport, 0, len(group.Rules)),
+		}
+		for _, rule := range group.Rules {
+			// the UID that the rule gets from ImportRuleGroups
+			rule.UID = deterministicRuleUID(group.FolderUID, group.Title, rule.Title)
+			r, err := exportAlertRule(rule)
+			if err != nil {
+				return definitions.PrometheusRulesImportResult{}, err
+			}
+			export.Rules = append(export.Rules, r)
+		}
+		result.Groups = append(result.Groups, export)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	if err := service.ImportRuleGroups(ctx, orgID, groups, models.ProvenanceAPI, true); err != nil {
+		return definitions.PrometheusRulesImportResult{}, err
+	}
+	service.log.Info("imported Prometheus rules", "orgID", orgID, "folderUID", imp.FolderUID, "groups", len(groups), "skipped", len(skipped))
+	return result, nil
+}
+
+// ConvertPrometheusRules converts the alerting rules of a Prometheus or Loki rule file to rule groups of the folder.
+// Every rule queries the datasource with its expression and fires for every series that the expression returns. The
+// groups without interval get the default interval, and the labels of the groups are kept as group labels. Recording
+// rules are returned as skipped rules.
+func ConvertPrometheusRules(config string, folderUID, datasourceUID string, defaultIntervalSeconds int64) ([]definitions.AlertRuleGroup, []definitions.PrometheusRulesImportSkippedRule, error) {
+	var file prometheusRuleFile
+	if err := yaml.Unmarshal([]byte(config), &file); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	groups := make([]definitions.AlertRuleGroup, 0, len(file.Groups))
+	skipped := make([]definitions.PrometheusRulesImportSkippedRule, 0)
+	for _, g := range file.Groups {
+		if g.Name == "" {
+			return nil, nil, fmt.Errorf("%w: a rule group has no name", ErrValidation)
+		}
+		group := definitions.AlertRuleGroup{
+			Title:     g.Name,
+			FolderUID: folderUID,
+			Interval:  int64(time.Duration(g.Interval).Seconds()),
+			Labels:    g.Labels,
+			Rules:     make([]models.AlertRule, 0, len(g.Rules)),
+		}
+		if group.Interval == 0 {
+			group.Interval = defaultIntervalSeconds
+		}
+		for _, r := range g.Rules {
+			if r.Record != "" {
+				skipped = append(skipped, definitions.PrometheusRulesImportSkippedRule{
+					Group:  g.Name,
+					Name:   r.Record,
+					Reason: "recording rules are not supported",
+				})
+				continue
+			}
+			if r.Alert == "" || r.Expr == "" {
+				return nil, nil, fmt.Errorf("%w: group '%s': the rules must have an alert name and an expression", ErrValidation, g.Name)
+			}
+			rule, err := convertPrometheusRule(r, datasourceUID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("group '%s', rule '%s': %w", g.Name, r.Alert, err)
+			}
+			group.Rules = append(group.Rules, rule)
+		}
+		if len(group.Rules) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups, skipped, nil
+}
+
+func convertPrometheusRule(r definitions.ApiRuleNode, datasourceUID string) (models.AlertRule, error) {
+	query, err := json.Marshal(map[string]interface{}{
+		"refId":      "A",
+		"expr":       r.Expr,
+		"instant":    true,
+		"queryType":  "instant",
+		"datasource": map[string]string{"uid": datasourceUID},
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	condition, err := json.Marshal(map[string]interface{}{
+		"refId":      "B",
+		"type":       "math",
+		"expression": prometheusCondition,
+		"datasource": map[string]string{"type": expr.DatasourceType, "uid": expr.DatasourceUID},
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+
+	rule := models.AlertRule{
+		Title:     r.Alert,
+		Condition: "B",
+		Data: []models.AlertQuery{
+			{
+				RefID:             "A",
+				DatasourceUID:     datasourceUID,
+				RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(prometheusQueryRange)},
+				Model:             query,
+			},
+			{
+				RefID:         "B",
+				DatasourceUID: expr.DatasourceUID,
+				Model:         condition,
+			},
+		},
+		// a Prometheus alert is not firing if its expression returns nothing
+		NoDataState:  models.OK,
+		ExecErrState: models.ErrorErrState,
+		Labels:       r.Labels,
+		Annotations:  r.Annotations,
+	}
+	if r.For != nil {
+		rule.For = time.Duration(*r.For)
+	}
+	return rule, nil
+}
@@ -0,0 +1,148 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const prometheusRulesConfig = `
+groups:
+  - name: api
+    interval: 30s
+    rules:
+      - record: job:http_requests:rate5m
+        expr: sum by (job) (rate(http_requests_total[5m]))
+      - alert: HighErrorRate
+        expr: job:http_errors:rate5m / job:http_requests:rate5m > 0.05
+        for: 10m
+        labels:
+          severity: page
+        annotations:
+          summary: "{{ $labels.job }} has a high error rate"
+  - name: recordings
+    rules:
+      - record: instance:up:count
+        expr: count by (instance) (up)
+  - name: nodes
+    labels:
+      team: infra
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+`
+
+func TestConvertPrometheusRules(t *testing.T) {
+	t.Run("alerting rules are converted and recording rules are skipped", func(t *testing.T) {
+		groups, skipped, err := ConvertPrometheusRules(prometheusRulesConfig, "folder", "prometheus", 60)
+
+		require.NoError(t, err)
+		require.Equal(t, []definitions.PrometheusRulesImportSkippedRule{
+			{Group: "api", Name: "job:http_requests:rate5m", Reason: "recording rules are not supported"},
+			{Group: "recordings", Name: "instance:up:count", Reason: "recording rules are not supported"},
+		}, skipped)
+		require.Len(t, groups, 2)
+
+		api := groups[0]
+		require.Equal(t, "api", api.Title)
+		require.Equal(t, "folder", api.FolderUID)
+		require.Equal(t, int64(30), api.Interval)
+		require.Len(t, api.Rules, 1)
+		rule := api.Rules[0]
+		require.Equal(t, "HighErrorRate", rule.Title)
+		require.Empty(t, rule.UID)
+		require.Equal(t, 10*time.Minute, rule.For)
+		require.Equal(t, map[string]string{"severity": "page"}, rule.Labels)
+		require.Equal(t, map[string]string{"summary": "{{ $labels.job }} has a high error rate"}, rule.Annotations)
+		require.Equal(t, models.OK, rule.NoDataState)
+		require.Equal(t, "B", rule.Condition)
+		require.Len(t, rule.Data, 2)
+		require.Equal(t, "prometheus", rule.Data[0].DatasourceUID)
+		require.JSONEq(t, `{"refId":"A","expr":"job:http_errors:rate5m / job:http_requests:rate5m > 0.05","instant":true,"queryType":"instant","datasource":{"uid":"prometheus"}}`, string(rule.Data[0].Model))
+		require.Equal(t, expr.DatasourceUID, rule.Data[1].DatasourceUID)
+		require.Contains(t, string(rule.Data[1].Model), prometheusCondition)
+
+		nodes := groups[1]
+		require.Equal(t, "nodes", nodes.Title)
+		require.Equal(t, int64(60), nodes.Interval, "groups without interval get the default interval")
+		require.Equal(t, map[string]string{"team": "infra"}, nodes.Labels)
+		require.Zero(t, nodes.Rules[0].For)
+	})
+
+	t.Run("invalid rule files are rejected", func(t *testing.T) {
+		for name, config := range map[string]string{
+			"invalid YAML":       "groups: [",
+			"group without name": "groups:\n  - rules:\n      - alert: A\n        expr: up == 0\n",
+			"rule without expr":  "groups:\n  - name: g\n    rules:\n      - alert: A\n",
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, _, err := ConvertPrometheusRules(config, "folder", "prometheus", 60)
+				require.ErrorIs(t, err, ErrValidation)
+			})
+		}
+	})
+}
+
+func TestAlertRuleService_ImportPrometheusRules(t *testing.T) {
+	ctx := context.Background()
+	var orgID int64 = 1
+	ruleService := createAlertRuleService(t)
+	ruleService.datasources = fakeDatasourceReader{{OrgId: orgID, Uid: "prometheus", Type: "prometheus"}}
+	imp := definitions.PrometheusRulesImport{Config: prometheusRulesConfig, FolderUID: "folder", DatasourceUID: "prometheus"}
+
+	t.Run("dry runs save nothing", func(t *testing.T) {
+		result, err := ruleService.ImportPrometheusRules(ctx, orgID, imp, true)
+
+		require.NoError(t, err)
+		require.True(t, result.DryRun)
+		require.Len(t, result.Groups, 2)
+		require.Len(t, result.Skipped, 2)
+		_, err = ruleService.GetRuleGroup(ctx, orgID, "folder", "api")
+		require.Error(t, err)
+	})
+
+	t.Run("rules are created, and updated when imported again", func(t *testing.T) {
+		result, err := ruleService.ImportPrometheusRules(ctx, orgID, imp, false)
+
+		require.NoError(t, err)
+		require.False(t, result.DryRun)
+		group, err := ruleService.GetRuleGroup(ctx, orgID, "folder", "api")
+		require.NoError(t, err)
+		require.Len(t, group.Rules, 1)
+		require.Equal(t, result.Groups[0].Rules[0].UID, group.Rules[0].UID)
+		require.Equal(t, deterministicRuleUID("folder", "api", "HighErrorRate"), group.Rules[0].UID)
+		_, provenance, err := ruleService.GetAlertRule(ctx, orgID, group.Rules[0].UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+
+		_, err = ruleService.ImportPrometheusRules(ctx, orgID, imp, false)
+
+		require.NoError(t, err)
+		group, err = ruleService.GetRuleGroup(ctx, orgID, "folder", "api")
+		require.NoError(t, err)
+		require.Len(t, group.Rules, 1)
+		require.Equal(t, int64(2), group.Rules[0].Version)
+	})
+
+	t.Run("the folder and datasource are required", func(t *testing.T) {
+		_, err := ruleService.ImportPrometheusRules(ctx, orgID, definitions.PrometheusRulesImport{Config: prometheusRulesConfig}, true)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("unknown datasources are rejected", func(t *testing.T) {
+		unknown := imp
+		unknown.DatasourceUID = "loki"
+
+		_, err := ruleService.ImportPrometheusRules(ctx, orgID, unknown, false)
+
+		require.ErrorIs(t, err, ErrValidation)
+		require.NotErrorIs(t, err, datasources.ErrDataSourceNotFound)
+	})
+}
@@ -0,0 +1,187 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ApplyRouteProvenances sets the provenance of every route in the tree rooted at root.
+// The root route is tracked with an empty ID, all other routes by their ID. Routes without
+// an ID were stored before provenance was tracked per route, they inherit the provenance of their parent.
+func ApplyRouteProvenances(root *definitions.Route, provenances map[string]models.Provenance) {
+	root.Provenance = provenances[root.ID]
+	applyChildRouteProvenances(root, provenances)
+}
+
+func applyChildRouteProvenances(parent *definitions.Route, provenances map[string]models.Provenance) {
+	for _, child := range parent.Routes {
+		if child.ID == "" {
+			child.Provenance = parent.Provenance
+		} else {
+			child.Provenance = provenances[child.ID]
+		}
+		applyChildRouteProvenances(child, provenances)
+	}
+}
+
+// CheckProvisionedRoutes verifies that updated preserves every route of current which is provisioned
+// with a provenance other than p. Such routes must stay attached to the same parent and keep their settings,
+// while their nested routes can be changed if they are not provisioned themselves.
+// The provenance of the routes in current is expected to be set, see ApplyRouteProvenances.
+func CheckProvisionedRoutes(current, updated *definitions.Route, p models.Provenance) error {
+	_, err := matchProvisionedRoutes(current, updated, p)
+	return err
+}
+
+type indexedRoute struct {
+	route  *definitions.Route
+	parent *definitions.Route
+}
+
+// matchProvisionedRoutes runs the checks of CheckProvisionedRoutes and returns the provenance of every route in updated
+// that replaces a route provisioned with a provenance other than p.
+func matchProvisionedRoutes(current, updated *definitions.Route, p models.Provenance) (map[*definitions.Route]models.Provenance, error) {
+	index := map[string]indexedRoute{}
+	walkRoutes(updated, nil, func(r, parent *definitions.Route) {
+		if r.ID != "" {
+			index[r.ID] = indexedRoute{route: r, parent: parent}
+		}
+	})
+
+	matched := map[*definitions.Route]models.Provenance{}
+	var check func(r *definitions.Route, counterpart, parentCounterpart *definitions.Route) error
+	check = func(r *definitions.Route, counterpart, parentCounterpart *definitions.Route) error {
+		locked := r.Provenance != models.ProvenanceNone && r.Provenance != p
+		if locked {
+			if counterpart == nil {
+				return fmt.Errorf("cannot delete %s provisioned with provenance '%s'", describeRoute(r), r.Provenance)
+			}
+			if r.ID != "" && index[r.ID].parent != parentCounterpart {
+				return fmt.Errorf("cannot move %s provisioned with provenance '%s'", describeRoute(r), r.Provenance)
+			}
+			if !equalRouteSettings(r, counterpart) {
+				return fmt.Errorf("cannot change %s provisioned with provenance '%s'", describeRoute(r), r.Provenance)
+			}
+			matched[counterpart] = r.Provenance
+		}
+		for i, child := range r.Routes {
+			var childCounterpart *definitions.Route
+			if child.ID != "" {
+				childCounterpart = index[child.ID].route
+			} else if counterpart != nil && i < len(counterpart.Routes) && counterpart.Routes[i].ID == "" {
+				// routes stored before they had an ID can only be matched by their position
+				childCounterpart = counterpart.Routes[i]
+			}
+			if err := check(child, childCounterpart, counterpart); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(current, updated, nil); err != nil {
+		return nil, err
+	}
+	return matched, nil
+}
+
+// validateRouteIDs checks that the IDs of the nested routes are unique and that the root route has no ID.
+func validateRouteIDs(root *definitions.Route) error {
+	if root.ID != "" {
+		return fmt.Errorf("root route must not have an id")
+	}
+	seen := map[string]struct{}{}
+	var err error
+	walkRoutes(root, nil, func(r, _ *definitions.Route) {
+		if r == root || r.ID == "" || err != nil {
+			return
+		}
+		if _, ok := seen[r.ID]; ok {
+			err = fmt.Errorf("route id '%s' is not unique", r.ID)
+			return
+		}
+		seen[r.ID] = struct{}{}
+	})
+	return err
+}
+
+// prepareRoutesForSave generates an ID for every nested route that does not have one yet and clears the provenance
+// of all routes, as provenance is tracked in the provisioning store rather than in the configuration.
+func prepareRoutesForSave(root *definitions.Route) {
+	walkRoutes(root, nil, func(r, _ *definitions.Route) {
+		if r != root && r.ID == "" {
+			r.ID = util.GenerateShortUID()
+		}
+		r.Provenance = models.ProvenanceNone
+	})
+}
+
+// unprovisionedRoutes returns the routes that are not provisioned. Provisioned routes are removed, their nested
+// routes which are not provisioned take their place in the parent.
+// The provenance of the routes is expected to be set, see ApplyRouteProvenances.
+func unprovisionedRoutes(routes []*definitions.Route) []*definitions.Route {
+	var result []*definitions.Route
+	for _, r := range routes {
+		children := unprovisionedRoutes(r.Routes)
+		if r.Provenance != models.ProvenanceNone {
+			result = append(result, children...)
+			continue
+		}
+		r.Routes = children
+		result = append(result, r)
+	}
+	return result
+}
+
+// copyRoute copies src and all its nested routes into dst.
+func copyRoute(src, dst *definitions.Route) error {
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+// walkRoutes calls fn for r and all its nested routes, depth first.
+func walkRoutes(r, parent *definitions.Route, fn func(r, parent *definitions.Route)) {
+	fn(r, parent)
+	for _, child := range r.Routes {
+		walkRoutes(child, r, fn)
+	}
+}
+
+// repointRoutes replaces the receiver from by the receiver to in all routes of the tree r.
+// Returns the number of routes that were changed.
+func repointRoutes(r *definitions.Route, from, to string) int {
+	changed := 0
+	walkRoutes(r, nil, func(r, _ *definitions.Route) {
+		if r.Receiver == from {
+			r.Receiver = to
+			changed++
+		}
+	})
+	return changed
+}
+
+// equalRouteSettings compares two routes without their IDs, provenance and nested routes.
+func equalRouteSettings(a, b *definitions.Route) bool {
+	return cmp.Equal(a, b,
+		cmpopts.EquateEmpty(),
+		cmpopts.IgnoreUnexported(labels.Matcher{}),
+		cmpopts.IgnoreFields(definitions.Route{}, "ID", "Provenance", "Routes", "GroupBy", "GroupByAll"),
+	)
+}
+
+func describeRoute(r *definitions.Route) string {
+	if r.ID != "" {
+		return fmt.Sprintf("policy '%s'", r.ID)
+	}
+	return fmt.Sprintf("policy with receiver '%s'", r.Receiver)
+}
@@ -0,0 +1,73 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// DatasourceReferenceError is an ErrValidation returned when the queries of alert rules reference datasources that
+// do not exist in the organization, or whose type does not support alerting.
+type DatasourceReferenceError struct {
+	// Missing are the UIDs of the referenced datasources that do not exist.
+	Missing []string
+	// Incompatible are the UIDs of the referenced datasources whose type does not support alerting.
+	Incompatible []string
+}
+
+func (e DatasourceReferenceError) Error() string {
+	reasons := make([]string, 0, 2)
+	if len(e.Missing) > 0 {
+		reasons = append(reasons, fmt.Sprintf("the datasources %s do not exist", strings.Join(e.Missing, ", ")))
+	}
+	if len(e.Incompatible) > 0 {
+		reasons = append(reasons, fmt.Sprintf("the datasources %s do not support alerting", strings.Join(e.Incompatible, ", ")))
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(reasons, " and "))
+}
+
+func (e DatasourceReferenceError) Is(target error) bool {
+	return target == ErrValidation || target == models.ErrAlertRuleFailedValidation
+}
+
+// checkDatasources returns a DatasourceReferenceError if the queries of the alert rules reference datasources that
+// do not exist in the organization or do not support alerting. Expressions are not checked.
+func (service *AlertRuleService) checkDatasources(ctx context.Context, orgID int64, rules ...models.AlertRule) error {
+	checked := make(map[string]struct{})
+	var refErr DatasourceReferenceError
+	for _, rule := range rules {
+		for _, q := range rule.Data {
+			if isExpression, _ := q.IsExpression(); isExpression {
+				continue
+			}
+			if _, ok := checked[q.DatasourceUID]; ok {
+				continue
+			}
+			checked[q.DatasourceUID] = struct{}{}
+
+			query := &datasources.GetDataSourceQuery{OrgId: orgID, Uid: q.DatasourceUID}
+			err := service.datasources.GetDataSource(ctx, query)
+			if errors.Is(err, datasources.ErrDataSourceNotFound) || errors.Is(err, datasources.ErrDataSourceIdentifierNotSet) {
+				refErr.Missing = append(refErr.Missing, q.DatasourceUID)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get datasource '%s': %w", q.DatasourceUID, err)
+			}
+			if plugin, ok := service.plugins.Plugin(ctx, query.Result.Type); !ok || !plugin.Backend || !plugin.Alerting {
+				refErr.Incompatible = append(refErr.Incompatible, q.DatasourceUID)
+			}
+		}
+	}
+	if len(refErr.Missing) == 0 && len(refErr.Incompatible) == 0 {
+		return nil
+	}
+	sort.Strings(refErr.Missing)
+	sort.Strings(refErr.Incompatible)
+	return refErr
+}
@@ -0,0 +1,82 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestAlertRuleService_CheckDatasources(t *testing.T) {
+	ctx := context.Background()
+	var orgID int64 = 1
+	ruleService := createAlertRuleService(t)
+	ruleService.datasources = fakeDatasourceReader{
+		{OrgId: orgID, Uid: "prometheus", Type: "prometheus"},
+		{OrgId: orgID, Uid: "clock", Type: "grafana-clock-panel"},
+		{OrgId: 2, Uid: "other-org", Type: "prometheus"},
+	}
+	ruleService.plugins = fakePluginReader{
+		"prometheus":          {JSONData: plugins.JSONData{ID: "prometheus", Backend: true, Alerting: true}},
+		"grafana-clock-panel": {JSONData: plugins.JSONData{ID: "grafana-clock-panel"}},
+	}
+	ruleWithDatasources := func(title string, uids ...string) models.AlertRule {
+		rule := dummyRule(title, orgID)
+		for i, uid := range uids {
+			rule.Data = append(rule.Data, models.AlertQuery{
+				RefID:         string(rune('B' + i)),
+				Model:         json.RawMessage("{}"),
+				DatasourceUID: uid,
+				RelativeTimeRange: models.RelativeTimeRange{
+					From: models.Duration(60),
+				},
+			})
+		}
+		return rule
+	}
+
+	t.Run("rules with existing alerting datasources are saved", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(ctx, ruleWithDatasources("valid", "prometheus"), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		rule.Data = append(rule.Data, ruleWithDatasources("", "missing").Data[1])
+		_, err = ruleService.UpdateAlertRule(ctx, rule, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.Equal(t, DatasourceReferenceError{Missing: []string{"missing"}}, err)
+	})
+
+	t.Run("missing and incompatible datasources are listed", func(t *testing.T) {
+		_, err := ruleService.CreateAlertRule(ctx, ruleWithDatasources("invalid", "other-org", "clock", "missing", "other-org"), models.ProvenanceAPI)
+
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.Equal(t, DatasourceReferenceError{Missing: []string{"missing", "other-org"}, Incompatible: []string{"clock"}}, err)
+		require.EqualError(t, err, "invalid object specification: the datasources missing, other-org do not exist and the datasources clock do not support alerting")
+	})
+
+	t.Run("imports report the rules with invalid datasources", func(t *testing.T) {
+		err := ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{{
+			Title:     "imported",
+			FolderUID: "folder",
+			Interval:  60,
+			Rules:     []models.AlertRule{ruleWithDatasources("valid", "prometheus"), ruleWithDatasources("invalid", "clock")},
+		}}, models.ProvenanceAPI, false)
+
+		var validationErr RuleGroupsValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Len(t, validationErr.Errors, 1)
+		require.ErrorIs(t, validationErr.Errors[0], ErrValidation)
+		require.Contains(t, validationErr.Errors[0].Error(), "rule 'invalid'")
+	})
+}
+
+func TestDatasourceReferenceError(t *testing.T) {
+	require.ErrorIs(t, DatasourceReferenceError{Missing: []string{"a"}}, ErrValidation)
+	require.NotErrorIs(t, DatasourceReferenceError{Missing: []string{"a"}}, datasources.ErrDataSourceNotFound)
+}
@@ -0,0 +1,171 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	gfcore "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+var folderPermissions = []gfcore.PermissionType{gfcore.PERMISSION_VIEW, gfcore.PERMISSION_EDIT, gfcore.PERMISSION_ADMIN}
+
+// RuleFolderService creates the missing folders of imported alert rules.
+type RuleFolderService struct {
+	folders     FolderService
+	acls        FolderACLStore
+	permissions FolderPermissionSetter
+	ac          accesscontrol.AccessControl
+	log         log.Logger
+}
+
+func NewRuleFolderService(folders FolderService, acls FolderACLStore, permissions FolderPermissionSetter, ac accesscontrol.AccessControl,
+	log log.Logger) *RuleFolderService {
+	return &RuleFolderService{
+		folders:     folders,
+		acls:        acls,
+		permissions: permissions,
+		ac:          ac,
+		log:         log,
+	}
+}
+
+// EnsureFolder checks that the folder with the UID exists and that the user can see it. If the folder does not exist,
+// it is created with the title and the permissions of the description, and true is returned. The import of the rules
+// fails with ErrValidation if the folder does not exist and there is no description. With dryRun, the description is
+// validated but the folder is not created.
+func (s *RuleFolderService) EnsureFolder(ctx context.Context, user *gfcore.SignedInUser, orgID int64, uid string, folder *definitions.RuleImportFolder, dryRun bool) (bool, error) {
+	_, err := s.folders.GetFolderByUID(ctx, user, orgID, uid)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, dashboards.ErrFolderNotFound) {
+		return false, err
+	}
+	if folder == nil {
+		return false, fmt.Errorf("%w: the folder '%s' does not exist", ErrValidation, uid)
+	}
+	if err := validateRuleImportFolder(*folder); err != nil {
+		return false, err
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	created, err := s.folders.CreateFolder(ctx, user, orgID, folder.Title, uid)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrFolderSameNameExists) || errors.Is(err, dashboards.ErrFolderWithSameUIDExists) ||
+			errors.Is(err, dashboards.ErrFolderInvalidUID) || errors.Is(err, dashboards.ErrFolderTitleEmpty) {
+			return false, fmt.Errorf("%w: %s", ErrValidation, err)
+		}
+		return false, err
+	}
+	if len(folder.Permissions) > 0 {
+		if err := s.setPermissions(ctx, user, orgID, created, folder.Permissions); err != nil {
+			s.DeleteFolder(ctx, user, orgID, uid)
+			return false, fmt.Errorf("failed to set the permissions of the folder: %w", err)
+		}
+	}
+	s.log.Info("created folder of imported rules", "orgID", orgID, "folderUID", uid, "title", folder.Title)
+	return true, nil
+}
+
+// DeleteFolder deletes a folder created by EnsureFolder, when the rules cannot be imported into it. Failures are only
+// logged.
+func (s *RuleFolderService) DeleteFolder(ctx context.Context, user *gfcore.SignedInUser, orgID int64, uid string) {
+	if _, err := s.folders.DeleteFolder(ctx, user, orgID, uid, false); err != nil {
+		s.log.Error("failed to delete the folder of rules that were not imported", "orgID", orgID, "folderUID", uid, "error", err)
+	}
+}
+
+// setPermissions replaces the default permissions of a new folder.
+func (s *RuleFolderService) setPermissions(ctx context.Context, user *gfcore.SignedInUser, orgID int64, folder *gfcore.Folder, permissions []definitions.RuleImportFolderPermission) error {
+	if s.ac.IsDisabled() {
+		now := time.Now()
+		items := make([]*gfcore.DashboardAcl, 0, len(permissions))
+		for _, p := range permissions {
+			item := &gfcore.DashboardAcl{
+				OrgID:       orgID,
+				DashboardID: folder.Id,
+				UserID:      p.UserID,
+				TeamID:      p.TeamID,
+				Permission:  folderPermission(p.Permission),
+				Created:     now,
+				Updated:     now,
+			}
+			if p.Role != "" {
+				role := gfcore.RoleType(p.Role)
+				item.Role = &role
+			}
+			items = append(items, item)
+		}
+		return s.acls.UpdateDashboardACL(ctx, folder.Id, items)
+	}
+
+	// The default permissions of the folder are removed, unless they are replaced.
+	commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(permissions)+3)
+	users, roles := map[int64]bool{}, map[string]bool{}
+	for _, p := range permissions {
+		commands = append(commands, accesscontrol.SetResourcePermissionCommand{
+			UserID:      p.UserID,
+			TeamID:      p.TeamID,
+			BuiltinRole: p.Role,
+			Permission:  p.Permission,
+		})
+		users[p.UserID] = true
+		roles[p.Role] = true
+	}
+	if user.UserId > 0 && !users[user.UserId] {
+		commands = append(commands, accesscontrol.SetResourcePermissionCommand{UserID: user.UserId})
+	}
+	for _, role := range []string{string(gfcore.ROLE_EDITOR), string(gfcore.ROLE_VIEWER)} {
+		if !roles[role] {
+			commands = append(commands, accesscontrol.SetResourcePermissionCommand{BuiltinRole: role})
+		}
+	}
+	_, err := s.permissions.SetPermissions(ctx, orgID, folder.Uid, commands...)
+	return err
+}
+
+func validateRuleImportFolder(folder definitions.RuleImportFolder) error {
+	if folder.Title == "" {
+		return fmt.Errorf("%w: the title of the folder is required", ErrValidation)
+	}
+	for i, p := range folder.Permissions {
+		targets := 0
+		if p.UserID != 0 {
+			targets++
+		}
+		if p.TeamID != 0 {
+			targets++
+		}
+		if p.Role != "" {
+			targets++
+			if !gfcore.RoleType(p.Role).IsValid() {
+				return fmt.Errorf("%w: permission %d of the folder: invalid role '%s'", ErrValidation, i, p.Role)
+			}
+		}
+		if targets != 1 {
+			return fmt.Errorf("%w: permission %d of the folder: exactly one of the user, the team and the role is required", ErrValidation, i)
+		}
+		if folderPermission(p.Permission) == 0 {
+			return fmt.Errorf("%w: permission %d of the folder: invalid permission '%s', it must be View, Edit or Admin", ErrValidation, i, p.Permission)
+		}
+	}
+	return nil
+}
+
+// folderPermission returns the permission with the name, or 0 if there is none.
+func folderPermission(name string) gfcore.PermissionType {
+	for _, p := range folderPermissions {
+		if p.String() == name {
+			return p
+		}
+	}
+	return 0
+}
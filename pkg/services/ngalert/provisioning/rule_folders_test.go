@@ -0,0 +1,158 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	gfcore "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRuleFolderService(t *testing.T) {
+	ctx := context.Background()
+	user := &gfcore.SignedInUser{UserId: 7, OrgId: 1}
+	folder := &definitions.RuleImportFolder{Title: "Payments"}
+
+	t.Run("should not create existing folders", func(t *testing.T) {
+		folders := &fakeFolderService{folders: map[string]*gfcore.Folder{"payments": {Id: 1, Uid: "payments"}}}
+		sut := NewRuleFolderService(folders, &fakeFolderACLStore{}, &fakeFolderPermissionSetter{}, acmock.New(), log.NewNopLogger())
+
+		created, err := sut.EnsureFolder(ctx, user, 1, "payments", folder, false)
+
+		require.NoError(t, err)
+		require.False(t, created)
+	})
+
+	t.Run("should fail if a missing folder is not described", func(t *testing.T) {
+		sut := NewRuleFolderService(&fakeFolderService{}, &fakeFolderACLStore{}, &fakeFolderPermissionSetter{}, acmock.New(), log.NewNopLogger())
+
+		_, err := sut.EnsureFolder(ctx, user, 1, "payments", nil, false)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should create missing folders", func(t *testing.T) {
+		folders := &fakeFolderService{}
+		permissions := &fakeFolderPermissionSetter{}
+		sut := NewRuleFolderService(folders, &fakeFolderACLStore{}, permissions, acmock.New(), log.NewNopLogger())
+
+		created, err := sut.EnsureFolder(ctx, user, 1, "payments", folder, true)
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Empty(t, folders.folders)
+
+		created, err = sut.EnsureFolder(ctx, user, 1, "payments", folder, false)
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, "Payments", folders.folders["payments"].Title)
+		require.Empty(t, permissions.commands)
+	})
+
+	t.Run("should replace the default permissions of the folder", func(t *testing.T) {
+		withPermissions := &definitions.RuleImportFolder{Title: "Payments", Permissions: []definitions.RuleImportFolderPermission{
+			{TeamID: 3, Permission: "Edit"},
+			{Role: "Viewer", Permission: "View"},
+		}}
+		permissions := &fakeFolderPermissionSetter{}
+		sut := NewRuleFolderService(&fakeFolderService{}, &fakeFolderACLStore{}, permissions, acmock.New(), log.NewNopLogger())
+
+		_, err := sut.EnsureFolder(ctx, user, 1, "payments", withPermissions, false)
+
+		require.NoError(t, err)
+		require.Equal(t, []accesscontrol.SetResourcePermissionCommand{
+			{TeamID: 3, Permission: "Edit"},
+			{BuiltinRole: "Viewer", Permission: "View"},
+			{UserID: 7},
+			{BuiltinRole: "Editor"},
+		}, permissions.commands)
+	})
+
+	t.Run("should replace the ACL of the folder if access control is disabled", func(t *testing.T) {
+		withPermissions := &definitions.RuleImportFolder{Title: "Payments", Permissions: []definitions.RuleImportFolderPermission{
+			{UserID: 5, Permission: "Admin"},
+			{Role: "Editor", Permission: "Edit"},
+		}}
+		acls := &fakeFolderACLStore{}
+		sut := NewRuleFolderService(&fakeFolderService{}, acls, &fakeFolderPermissionSetter{}, acmock.New().WithDisabled(), log.NewNopLogger())
+
+		_, err := sut.EnsureFolder(ctx, user, 1, "payments", withPermissions, false)
+
+		require.NoError(t, err)
+		require.Len(t, acls.items, 2)
+		require.Equal(t, int64(5), acls.items[0].UserID)
+		require.Equal(t, gfcore.PERMISSION_ADMIN, acls.items[0].Permission)
+		require.Equal(t, gfcore.ROLE_EDITOR, *acls.items[1].Role)
+		require.Equal(t, gfcore.PERMISSION_EDIT, acls.items[1].Permission)
+	})
+
+	t.Run("should reject invalid folder descriptions", func(t *testing.T) {
+		folders := &fakeFolderService{}
+		sut := NewRuleFolderService(folders, &fakeFolderACLStore{}, &fakeFolderPermissionSetter{}, acmock.New(), log.NewNopLogger())
+
+		for _, invalid := range []definitions.RuleImportFolder{
+			{},
+			{Title: "Payments", Permissions: []definitions.RuleImportFolderPermission{{Permission: "View"}}},
+			{Title: "Payments", Permissions: []definitions.RuleImportFolderPermission{{UserID: 1, TeamID: 2, Permission: "View"}}},
+			{Title: "Payments", Permissions: []definitions.RuleImportFolderPermission{{Role: "Owner", Permission: "View"}}},
+			{Title: "Payments", Permissions: []definitions.RuleImportFolderPermission{{UserID: 1, Permission: "Read"}}},
+		} {
+			invalid := invalid
+			_, err := sut.EnsureFolder(ctx, user, 1, "payments", &invalid, false)
+			require.ErrorIs(t, err, ErrValidation)
+		}
+		require.Empty(t, folders.folders)
+	})
+}
+
+type fakeFolderService struct {
+	folders map[string]*gfcore.Folder
+}
+
+func (f *fakeFolderService) GetFolderByUID(_ context.Context, _ *gfcore.SignedInUser, _ int64, uid string) (*gfcore.Folder, error) {
+	if folder, ok := f.folders[uid]; ok {
+		return folder, nil
+	}
+	return nil, dashboards.ErrFolderNotFound
+}
+
+func (f *fakeFolderService) CreateFolder(_ context.Context, _ *gfcore.SignedInUser, _ int64, title, uid string) (*gfcore.Folder, error) {
+	if f.folders == nil {
+		f.folders = map[string]*gfcore.Folder{}
+	}
+	folder := &gfcore.Folder{Id: int64(len(f.folders) + 1), Uid: uid, Title: title}
+	f.folders[uid] = folder
+	return folder, nil
+}
+
+func (f *fakeFolderService) DeleteFolder(_ context.Context, _ *gfcore.SignedInUser, _ int64, uid string, _ bool) (*gfcore.Folder, error) {
+	folder, ok := f.folders[uid]
+	if !ok {
+		return nil, dashboards.ErrFolderNotFound
+	}
+	delete(f.folders, uid)
+	return folder, nil
+}
+
+type fakeFolderACLStore struct {
+	items []*gfcore.DashboardAcl
+}
+
+func (f *fakeFolderACLStore) UpdateDashboardACL(_ context.Context, _ int64, items []*gfcore.DashboardAcl) error {
+	f.items = items
+	return nil
+}
+
+type fakeFolderPermissionSetter struct {
+	commands []accesscontrol.SetResourcePermissionCommand
+}
+
+func (f *fakeFolderPermissionSetter) SetPermissions(_ context.Context, _ int64, _ string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error) {
+	f.commands = commands
+	return nil, nil
+}
@@ -0,0 +1,113 @@
+package provisioning
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// setGroupLabels replaces the group labels of the rule with the labels of its group. The labels of the rule that
+// come from its previous group labels are removed, unless the rule changed their value, and the labels of the group
+// are added, unless the rule has a label with the same name.
+func setGroupLabels(rule *models.AlertRule, groupLabels map[string]string) {
+	if len(rule.GroupLabels) == 0 && len(groupLabels) == 0 {
+		return
+	}
+	labels := ruleOwnLabels(rule.Labels, rule.GroupLabels)
+	if labels == nil {
+		labels = make(map[string]string, len(groupLabels))
+	}
+	for k, v := range groupLabels {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+	rule.Labels = labels
+	rule.GroupLabels = nil
+	if len(groupLabels) > 0 {
+		rule.GroupLabels = make(map[string]string, len(groupLabels))
+		for k, v := range groupLabels {
+			rule.GroupLabels[k] = v
+		}
+	}
+}
+
+// ruleOwnLabels returns the labels of a rule without the ones that have the value of the group label with the same
+// name.
+func ruleOwnLabels(labels, groupLabels map[string]string) map[string]string {
+	if len(groupLabels) == 0 || labels == nil {
+		return labels
+	}
+	own := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if gv, ok := groupLabels[k]; ok && gv == v {
+			continue
+		}
+		own[k] = v
+	}
+	return own
+}
+
+// mergeGroupLabels returns the labels of a rule with the labels of its group that the rule does not override.
+func mergeGroupLabels(labels, groupLabels map[string]string) map[string]string {
+	if len(groupLabels) == 0 {
+		return labels
+	}
+	merged := make(map[string]string, len(labels)+len(groupLabels))
+	for k, v := range groupLabels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
+
+// commonGroupLabels returns the group labels that all the rules have. The rules that were added to the group
+// without provisioning it, for example with the ruler API, have no group labels, and so the group has none.
+func commonGroupLabels(rules []*models.AlertRule) map[string]string {
+	if len(rules) == 0 || len(rules[0].GroupLabels) == 0 {
+		return nil
+	}
+	common := make(map[string]string, len(rules[0].GroupLabels))
+	for k, v := range rules[0].GroupLabels {
+		common[k] = v
+	}
+	for _, rule := range rules[1:] {
+		for k, v := range common {
+			if gv, ok := rule.GroupLabels[k]; !ok || gv != v {
+				delete(common, k)
+			}
+		}
+	}
+	if len(common) == 0 {
+		return nil
+	}
+	return common
+}
+
+// labelsEqual returns true if both label sets have the same labels. Nil and empty label sets are equal.
+func labelsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleGroupLabels returns the labels of a rule group, or nil if the group does not exist.
+func (service *AlertRuleService) ruleGroupLabels(ctx context.Context, orgID int64, folderUID, group string) (map[string]string, error) {
+	q := models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{folderUID},
+		RuleGroup:     group,
+	}
+	if err := service.ruleStore.ListAlertRules(ctx, &q); err != nil {
+		return nil, err
+	}
+	return commonGroupLabels(q.Result), nil
+}
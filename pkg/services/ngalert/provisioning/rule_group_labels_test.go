@@ -0,0 +1,113 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestAlertRuleService_GroupLabels(t *testing.T) {
+	ctx := context.Background()
+	var orgID int64 = 1
+	ruleService := createAlertRuleService(t)
+
+	ruleWithLabels := func(title string, labels map[string]string) models.AlertRule {
+		rule := dummyRule(title, orgID)
+		rule.Labels = labels
+		return rule
+	}
+	groupRules := func(folderUID, group string) map[string]models.AlertRule {
+		g, err := ruleService.GetRuleGroup(ctx, orgID, folderUID, group)
+		require.NoError(t, err)
+		rules := make(map[string]models.AlertRule, len(g.Rules))
+		for _, r := range g.Rules {
+			rules[r.Title] = r
+		}
+		return rules
+	}
+
+	t.Run("imported group labels are merged into the rules and exported with the group", func(t *testing.T) {
+		err := ruleService.ImportRuleGroups(ctx, orgID, []definitions.AlertRuleGroup{{
+			Title:     "group",
+			FolderUID: "import-folder",
+			Interval:  60,
+			Labels:    map[string]string{"team": "sre", "severity": "warning"},
+			Rules: []models.AlertRule{
+				ruleWithLabels("a", nil),
+				ruleWithLabels("b", map[string]string{"severity": "critical"}),
+			},
+		}}, models.ProvenanceAPI, true)
+		require.NoError(t, err)
+
+		rules := groupRules("import-folder", "group")
+		require.Equal(t, map[string]string{"team": "sre", "severity": "warning"}, rules["a"].Labels)
+		require.Equal(t, map[string]string{"team": "sre", "severity": "critical"}, rules["b"].Labels)
+
+		export, err := ruleService.ExportRuleGroups(ctx, orgID, "import-folder")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"team": "sre", "severity": "warning"}, export.Groups[0].Labels)
+		require.Empty(t, export.Groups[0].Rules[0].Labels)
+		require.Equal(t, map[string]string{"severity": "critical"}, export.Groups[0].Rules[1].Labels)
+
+		drift, err := ruleService.DetectRuleDrift(ctx, orgID, export)
+		require.NoError(t, err)
+		for _, r := range drift.Rules {
+			require.Empty(t, r.Changes)
+		}
+	})
+
+	t.Run("updating the group replaces its labels", func(t *testing.T) {
+		_, err := ruleService.CreateAlertRule(ctx, ruleWithLabels("c", map[string]string{"owner": "me"}), models.ProvenanceAPI)
+		require.NoError(t, err)
+		created, err := ruleService.CreateAlertRule(ctx, ruleWithLabels("d", nil), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, ruleService.UpdateRuleGroup(ctx, orgID, created.NamespaceUID, created.RuleGroup, 60, map[string]string{"team": "sre", "owner": "group"}, ""))
+
+		rules := groupRules(created.NamespaceUID, created.RuleGroup)
+		require.Equal(t, map[string]string{"team": "sre", "owner": "me"}, rules["c"].Labels)
+		require.Equal(t, map[string]string{"team": "sre", "owner": "group"}, rules["d"].Labels)
+
+		require.NoError(t, ruleService.UpdateRuleGroup(ctx, orgID, created.NamespaceUID, created.RuleGroup, 60, map[string]string{"env": "prod"}, ""))
+
+		group, err := ruleService.GetRuleGroup(ctx, orgID, created.NamespaceUID, created.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "prod"}, group.Labels)
+		rules = groupRules(created.NamespaceUID, created.RuleGroup)
+		require.Equal(t, map[string]string{"env": "prod", "owner": "me"}, rules["c"].Labels)
+		require.Equal(t, map[string]string{"env": "prod"}, rules["d"].Labels)
+	})
+
+	t.Run("rules created and updated in a group get its labels", func(t *testing.T) {
+		created, err := ruleService.CreateAlertRule(ctx, ruleWithLabels("e", nil), models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "prod"}, created.Labels)
+
+		created.Labels = map[string]string{"owner": "you"}
+		updated, err := ruleService.UpdateAlertRule(ctx, created, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "prod", "owner": "you"}, updated.Labels)
+		require.Equal(t, map[string]string{"env": "prod"}, updated.GroupLabels)
+	})
+}
+
+func TestSetGroupLabels(t *testing.T) {
+	rule := models.AlertRule{
+		Labels:      map[string]string{"team": "sre", "severity": "critical", "owner": "me"},
+		GroupLabels: map[string]string{"team": "sre", "severity": "warning"},
+	}
+
+	setGroupLabels(&rule, map[string]string{"env": "prod", "owner": "group"})
+
+	require.Equal(t, map[string]string{"severity": "critical", "owner": "me", "env": "prod"}, rule.Labels)
+	require.Equal(t, map[string]string{"env": "prod", "owner": "group"}, rule.GroupLabels)
+
+	setGroupLabels(&rule, nil)
+
+	require.Equal(t, map[string]string{"severity": "critical", "owner": "me"}, rule.Labels)
+	require.Nil(t, rule.GroupLabels)
+}
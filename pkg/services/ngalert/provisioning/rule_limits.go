@@ -0,0 +1,108 @@
+package provisioning
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// RuleLimitError is an ErrQuotaReached returned when a change of alert rules exceeds one of the limits on the alert
+// rules of the organization.
+type RuleLimitError struct {
+	// Limit is the name of the setting of the exceeded limit.
+	Limit  string
+	Reason string
+}
+
+func (e RuleLimitError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrQuotaReached, e.Reason)
+}
+
+func (e RuleLimitError) Is(target error) bool {
+	return target == ErrQuotaReached
+}
+
+// CheckRuleLimits returns a RuleLimitError if the alert rules of an organization exceed one of the limits after a
+// change. A limit is exceeded only if the change adds to the count it limits, so that an organization that exceeds a
+// limit when it is introduced can still change its rules without adding to the excess.
+func CheckRuleLimits(before, after []*models.AlertRule, limits setting.UnifiedAlertingRuleLimits) error {
+	current, next := countRules(before), countRules(after)
+
+	if limits.MaxRules > 0 && next.rules > limits.MaxRules && next.rules > current.rules {
+		return RuleLimitError{
+			Limit:  "max_rules",
+			Reason: fmt.Sprintf("the organization would have %d alert rules, at most %d are allowed", next.rules, limits.MaxRules),
+		}
+	}
+	if limits.MaxRulesPerGroup > 0 {
+		for _, key := range next.groupKeys() {
+			if n := next.rulesPerGroup[key]; n > limits.MaxRulesPerGroup && n > current.rulesPerGroup[key] {
+				return RuleLimitError{
+					Limit:  "max_rules_per_group",
+					Reason: fmt.Sprintf("the rule group '%s' of folder '%s' would have %d alert rules, at most %d are allowed", key.RuleGroup, key.NamespaceUID, n, limits.MaxRulesPerGroup),
+				}
+			}
+		}
+	}
+	if limits.MaxGroupsPerFolder > 0 {
+		for _, folder := range next.folders() {
+			if n := next.groupsPerFolder[folder]; n > limits.MaxGroupsPerFolder && n > current.groupsPerFolder[folder] {
+				return RuleLimitError{
+					Limit:  "max_groups_per_folder",
+					Reason: fmt.Sprintf("the folder '%s' would have %d rule groups, at most %d are allowed", folder, n, limits.MaxGroupsPerFolder),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ruleCounts are the counts of alert rules that are limited.
+type ruleCounts struct {
+	rules           int64
+	rulesPerGroup   map[models.AlertRuleGroupKey]int64
+	groupsPerFolder map[string]int64
+}
+
+func countRules(rules []*models.AlertRule) ruleCounts {
+	counts := ruleCounts{
+		rules:           int64(len(rules)),
+		rulesPerGroup:   map[models.AlertRuleGroupKey]int64{},
+		groupsPerFolder: map[string]int64{},
+	}
+	for _, rule := range rules {
+		key := rule.GetGroupKey()
+		if counts.rulesPerGroup[key] == 0 {
+			counts.groupsPerFolder[key.NamespaceUID]++
+		}
+		counts.rulesPerGroup[key]++
+	}
+	return counts
+}
+
+// groupKeys returns the keys of the rule groups in order, so that the same limit is reported for the same rules.
+func (c ruleCounts) groupKeys() []models.AlertRuleGroupKey {
+	keys := make([]models.AlertRuleGroupKey, 0, len(c.rulesPerGroup))
+	for key := range c.rulesPerGroup {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].NamespaceUID != keys[j].NamespaceUID {
+			return keys[i].NamespaceUID < keys[j].NamespaceUID
+		}
+		return keys[i].RuleGroup < keys[j].RuleGroup
+	})
+	return keys
+}
+
+// folders returns the UIDs of the folders in order.
+func (c ruleCounts) folders() []string {
+	folders := make([]string, 0, len(c.groupsPerFolder))
+	for folder := range c.groupsPerFolder {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+	return folders
+}
@@ -0,0 +1,50 @@
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCheckRuleLimits(t *testing.T) {
+	rule := func(folder, group string) *models.AlertRule {
+		return &models.AlertRule{OrgID: 1, NamespaceUID: folder, RuleGroup: group}
+	}
+	before := []*models.AlertRule{rule("folder-a", "group-a"), rule("folder-a", "group-a"), rule("folder-a", "group-b")}
+	after := append(before, rule("folder-a", "group-a"), rule("folder-a", "group-c"))
+
+	t.Run("rules within the limits are accepted", func(t *testing.T) {
+		require.NoError(t, CheckRuleLimits(before, after, setting.UnifiedAlertingRuleLimits{MaxRules: 5, MaxRulesPerGroup: 3, MaxGroupsPerFolder: 3}))
+	})
+
+	t.Run("limits of zero or less are not enforced", func(t *testing.T) {
+		require.NoError(t, CheckRuleLimits(before, after, setting.UnifiedAlertingRuleLimits{MaxRules: -1}))
+	})
+
+	t.Run("too many rules are rejected", func(t *testing.T) {
+		err := CheckRuleLimits(before, after, setting.UnifiedAlertingRuleLimits{MaxRules: 4})
+		require.ErrorIs(t, err, ErrQuotaReached)
+		require.Equal(t, RuleLimitError{Limit: "max_rules", Reason: "the organization would have 5 alert rules, at most 4 are allowed"}, err)
+	})
+
+	t.Run("too many rules in a group are rejected", func(t *testing.T) {
+		err := CheckRuleLimits(before, after, setting.UnifiedAlertingRuleLimits{MaxRulesPerGroup: 2})
+		require.ErrorIs(t, err, ErrQuotaReached)
+		require.Equal(t, RuleLimitError{Limit: "max_rules_per_group", Reason: "the rule group 'group-a' of folder 'folder-a' would have 3 alert rules, at most 2 are allowed"}, err)
+	})
+
+	t.Run("too many groups in a folder are rejected", func(t *testing.T) {
+		err := CheckRuleLimits(before, after, setting.UnifiedAlertingRuleLimits{MaxGroupsPerFolder: 2})
+		require.ErrorIs(t, err, ErrQuotaReached)
+		require.Equal(t, RuleLimitError{Limit: "max_groups_per_folder", Reason: "the folder 'folder-a' would have 3 rule groups, at most 2 are allowed"}, err)
+	})
+
+	t.Run("changes that do not add to an exceeded limit are accepted", func(t *testing.T) {
+		limits := setting.UnifiedAlertingRuleLimits{MaxRules: 1, MaxRulesPerGroup: 1, MaxGroupsPerFolder: 1}
+		require.NoError(t, CheckRuleLimits(before, before, limits))
+		require.NoError(t, CheckRuleLimits(before, before[1:], limits))
+	})
+}
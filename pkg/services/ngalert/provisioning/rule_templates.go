@@ -0,0 +1,355 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const RuleTemplatesKVNamespace = "ngalert.rule_templates"
+
+var (
+	ruleTemplateNameRegexp     = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+	ruleTemplateVariableRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	// ruleTemplateReference matches the references to the variables of a rule template, such as ${threshold}.
+	ruleTemplateReference = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+)
+
+// RuleTemplateService stores the rule templates of every organization and instantiates them into alert rules.
+type RuleTemplateService struct {
+	kvStore kvstore.KVStore
+	log     log.Logger
+}
+
+func NewRuleTemplateService(kvStore kvstore.KVStore, log log.Logger) *RuleTemplateService {
+	return &RuleTemplateService{
+		kvStore: kvStore,
+		log:     log,
+	}
+}
+
+// GetRuleTemplates returns the rule templates of the organization, sorted by name.
+func (s *RuleTemplateService) GetRuleTemplates(ctx context.Context, orgID int64) ([]definitions.RuleTemplate, error) {
+	store := kvstore.WithNamespace(s.kvStore, orgID, RuleTemplatesKVNamespace)
+	keys, err := store.Keys(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.RuleTemplate, 0, len(keys))
+	for _, key := range keys {
+		tmpl, err := s.GetRuleTemplate(ctx, orgID, key.Key)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tmpl)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// GetRuleTemplate returns a rule template of the organization, or ErrNotFound if it does not exist.
+func (s *RuleTemplateService) GetRuleTemplate(ctx context.Context, orgID int64, name string) (definitions.RuleTemplate, error) {
+	raw, ok, err := kvstore.WithNamespace(s.kvStore, orgID, RuleTemplatesKVNamespace).Get(ctx, name)
+	if err != nil {
+		return definitions.RuleTemplate{}, err
+	}
+	if !ok {
+		return definitions.RuleTemplate{}, fmt.Errorf("%w: rule template '%s' does not exist", ErrNotFound, name)
+	}
+	var tmpl definitions.RuleTemplate
+	if err := json.Unmarshal([]byte(raw), &tmpl); err != nil {
+		return definitions.RuleTemplate{}, fmt.Errorf("failed to unmarshal rule template '%s': %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// SetRuleTemplate validates and stores a rule template, replacing the template with the same name.
+func (s *RuleTemplateService) SetRuleTemplate(ctx context.Context, orgID int64, tmpl definitions.RuleTemplate) (definitions.RuleTemplate, error) {
+	if err := validateRuleTemplate(tmpl); err != nil {
+		return definitions.RuleTemplate{}, err
+	}
+	raw, err := json.Marshal(tmpl)
+	if err != nil {
+		return definitions.RuleTemplate{}, err
+	}
+	if err := kvstore.WithNamespace(s.kvStore, orgID, RuleTemplatesKVNamespace).Set(ctx, tmpl.Name, string(raw)); err != nil {
+		return definitions.RuleTemplate{}, err
+	}
+	return tmpl, nil
+}
+
+// DeleteRuleTemplate deletes a rule template of the organization. The rules created from it are not changed.
+func (s *RuleTemplateService) DeleteRuleTemplate(ctx context.Context, orgID int64, name string) error {
+	if _, err := s.GetRuleTemplate(ctx, orgID, name); err != nil {
+		return err
+	}
+	return kvstore.WithNamespace(s.kvStore, orgID, RuleTemplatesKVNamespace).Del(ctx, name)
+}
+
+// RenderRuleTemplate returns the alert rule created from a rule template with the values of the instance. The rule
+// is not saved, use AlertRuleService.CreateAlertRule to store it.
+func (s *RuleTemplateService) RenderRuleTemplate(ctx context.Context, orgID int64, name string, instance definitions.RuleTemplateInstance) (models.AlertRule, error) {
+	tmpl, err := s.GetRuleTemplate(ctx, orgID, name)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	if instance.FolderUID == "" || instance.RuleGroup == "" {
+		return models.AlertRule{}, fmt.Errorf("%w: the folder and the rule group of the rule must be set", ErrValidation)
+	}
+	values, err := ruleTemplateValues(tmpl.Variables, instance.Values)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	export, err := renderRuleTemplate(tmpl.Rule, values)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	rule, err := importAlertRule(export)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("%w: %s", ErrValidation, err)
+	}
+	rule.OrgID = orgID
+	rule.NamespaceUID = instance.FolderUID
+	rule.RuleGroup = instance.RuleGroup
+	rule.UID = instance.UID
+	if instance.Title != "" {
+		rule.Title = instance.Title
+	}
+	return rule, nil
+}
+
+func validateRuleTemplate(tmpl definitions.RuleTemplate) error {
+	if !ruleTemplateNameRegexp.MatchString(tmpl.Name) {
+		return fmt.Errorf("%w: the name of a rule template must only contain letters, digits, '_', '.' and '-'", ErrValidation)
+	}
+	seen := make(map[string]struct{}, len(tmpl.Variables))
+	// the rule is rendered with sample values, so that references to undeclared variables are rejected
+	samples := make(map[string]interface{}, len(tmpl.Variables))
+	for _, v := range tmpl.Variables {
+		if !ruleTemplateVariableRegexp.MatchString(v.Name) {
+			return fmt.Errorf("%w: invalid variable name '%s'", ErrValidation, v.Name)
+		}
+		if _, ok := seen[v.Name]; ok {
+			return fmt.Errorf("%w: variable '%s' is declared more than once", ErrValidation, v.Name)
+		}
+		seen[v.Name] = struct{}{}
+		switch v.Type {
+		case definitions.RuleTemplateVariableString, definitions.RuleTemplateVariableNumber,
+			definitions.RuleTemplateVariableDatasource, definitions.RuleTemplateVariableLabel:
+		default:
+			return fmt.Errorf("%w: variable '%s' has an unknown type '%s'", ErrValidation, v.Name, v.Type)
+		}
+		switch {
+		case v.Default != nil:
+			sample, err := ruleTemplateValue(v, v.Default)
+			if err != nil {
+				return err
+			}
+			samples[v.Name] = sample
+		case v.Type == definitions.RuleTemplateVariableNumber:
+			samples[v.Name] = float64(0)
+		default:
+			samples[v.Name] = v.Name
+		}
+	}
+	export, err := renderRuleTemplate(tmpl.Rule, samples)
+	if err != nil {
+		return err
+	}
+	if _, err := importAlertRule(export); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err)
+	}
+	return nil
+}
+
+// ruleTemplateValues returns the values of the variables of a rule template, with the defaults of the variables
+// that are not set. Every value is checked against the type of its variable.
+func ruleTemplateValues(variables []definitions.RuleTemplateVariable, values map[string]interface{}) (map[string]interface{}, error) {
+	known := make(map[string]struct{}, len(variables))
+	result := make(map[string]interface{}, len(variables))
+	var missing []string
+	for _, v := range variables {
+		known[v.Name] = struct{}{}
+		value, ok := values[v.Name]
+		if !ok || value == nil {
+			value = v.Default
+		}
+		if value == nil {
+			missing = append(missing, v.Name)
+			continue
+		}
+		value, err := ruleTemplateValue(v, value)
+		if err != nil {
+			return nil, err
+		}
+		result[v.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing values for the variables %s", ErrValidation, strings.Join(missing, ", "))
+	}
+
+	var unknown []string
+	for name := range values {
+		if _, ok := known[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: unknown variables %s", ErrValidation, strings.Join(unknown, ", "))
+	}
+	return result, nil
+}
+
+// ruleTemplateValue checks a value against the type of its variable. Numbers are returned as float64, the type of
+// the numbers of the query models.
+func ruleTemplateValue(v definitions.RuleTemplateVariable, value interface{}) (interface{}, error) {
+	if v.Type == definitions.RuleTemplateVariableNumber {
+		switch n := value.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		case int64:
+			return float64(n), nil
+		case json.Number:
+			if f, err := n.Float64(); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: the value of variable '%s' must be a number", ErrValidation, v.Name)
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: the value of variable '%s' must be a string", ErrValidation, v.Name)
+	}
+	switch v.Type {
+	case definitions.RuleTemplateVariableDatasource:
+		if s == "" {
+			return nil, fmt.Errorf("%w: the value of variable '%s' must be the UID of a data source", ErrValidation, v.Name)
+		}
+	case definitions.RuleTemplateVariableLabel:
+		if !model.LabelValue(s).IsValid() {
+			return nil, fmt.Errorf("%w: the value of variable '%s' is not a valid label value", ErrValidation, v.Name)
+		}
+	}
+	return s, nil
+}
+
+// renderRuleTemplate replaces the references to variables in the strings of the rule of a template by their values.
+// The template rule is not modified.
+func renderRuleTemplate(rule definitions.AlertRuleExport, values map[string]interface{}) (definitions.AlertRuleExport, error) {
+	var err error
+	expand := func(s string) string {
+		if err != nil {
+			return s
+		}
+		var result string
+		result, err = expandRuleTemplateString(s, values)
+		return result
+	}
+	expandMap := func(m map[string]string) map[string]string {
+		if m == nil {
+			return nil
+		}
+		result := make(map[string]string, len(m))
+		for k, v := range m {
+			result[k] = expand(v)
+		}
+		return result
+	}
+
+	result := rule
+	result.Title = expand(rule.Title)
+	result.Condition = expand(rule.Condition)
+	result.DashboardUID = expand(rule.DashboardUID)
+	result.Annotations = expandMap(rule.Annotations)
+	result.Labels = expandMap(rule.Labels)
+	result.Data = make([]definitions.AlertQueryExport, 0, len(rule.Data))
+	for _, q := range rule.Data {
+		q.QueryType = expand(q.QueryType)
+		q.DatasourceUID = expand(q.DatasourceUID)
+		if err == nil {
+			var expanded interface{}
+			expanded, err = expandRuleTemplateValue(q.Model, values)
+			q.Model, _ = expanded.(map[string]interface{})
+		}
+		result.Data = append(result.Data, q)
+	}
+	if err != nil {
+		return definitions.AlertRuleExport{}, err
+	}
+	return result, nil
+}
+
+// expandRuleTemplateValue replaces the references to variables in a query model. A string that is only a reference
+// is replaced by the value of the variable, so that numbers stay numbers.
+func expandRuleTemplateValue(value interface{}, values map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if m := ruleTemplateReference.FindStringSubmatchIndex(v); m != nil && m[0] == 0 && m[1] == len(v) {
+			name := v[m[2]:m[3]]
+			if value, ok := values[name]; ok {
+				return value, nil
+			}
+			return nil, fmt.Errorf("%w: variable '%s' is not declared", ErrValidation, name)
+		}
+		return expandRuleTemplateString(v, values)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			expanded, err := expandRuleTemplateValue(item, values)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = expanded
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			expanded, err := expandRuleTemplateValue(item, values)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, expanded)
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+// expandRuleTemplateString replaces the references to variables in a string by their values.
+func expandRuleTemplateString(s string, values map[string]interface{}) (string, error) {
+	var err error
+	result := ruleTemplateReference.ReplaceAllStringFunc(s, func(ref string) string {
+		name := ref[2 : len(ref)-1]
+		value, ok := values[name]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("%w: variable '%s' is not declared", ErrValidation, name)
+			}
+			return ref
+		}
+		if f, ok := value.(float64); ok {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return fmt.Sprint(value)
+	})
+	return result, err
+}
@@ -0,0 +1,160 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func TestRuleTemplateService(t *testing.T) {
+	ctx := context.Background()
+	sut := NewRuleTemplateService(kvstore.ProvideService(sqlstore.InitTestDB(t)), log.NewNopLogger())
+	var orgID int64 = 1
+
+	t.Run("should store, list and delete templates", func(t *testing.T) {
+		for _, name := range []string{"b", "a"} {
+			tmpl := dummyRuleTemplate()
+			tmpl.Name = name
+			_, err := sut.SetRuleTemplate(ctx, orgID, tmpl)
+			require.NoError(t, err)
+		}
+
+		templates, err := sut.GetRuleTemplates(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, templates, 2)
+		require.Equal(t, "a", templates[0].Name)
+		require.Equal(t, dummyRuleTemplate().Variables, templates[1].Variables)
+
+		other, err := sut.GetRuleTemplates(ctx, orgID+1)
+		require.NoError(t, err)
+		require.Empty(t, other)
+
+		require.NoError(t, sut.DeleteRuleTemplate(ctx, orgID, "a"))
+		_, err = sut.GetRuleTemplate(ctx, orgID, "a")
+		require.ErrorIs(t, err, ErrNotFound)
+		require.ErrorIs(t, sut.DeleteRuleTemplate(ctx, orgID, "a"), ErrNotFound)
+	})
+
+	t.Run("should reject invalid templates", func(t *testing.T) {
+		cases := map[string]func(tmpl *definitions.RuleTemplate){
+			"invalid name": func(tmpl *definitions.RuleTemplate) {
+				tmpl.Name = "a/b"
+			},
+			"invalid variable name": func(tmpl *definitions.RuleTemplate) {
+				tmpl.Variables[0].Name = "1st"
+			},
+			"duplicated variable": func(tmpl *definitions.RuleTemplate) {
+				tmpl.Variables = append(tmpl.Variables, tmpl.Variables[0])
+			},
+			"unknown type": func(tmpl *definitions.RuleTemplate) {
+				tmpl.Variables[0].Type = "duration"
+			},
+			"default of another type": func(tmpl *definitions.RuleTemplate) {
+				tmpl.Variables[0].Default = "high"
+			},
+			"undeclared variable": func(tmpl *definitions.RuleTemplate) {
+				tmpl.Rule.Labels["env"] = "${env}"
+			},
+		}
+		for name, change := range cases {
+			t.Run(name, func(t *testing.T) {
+				tmpl := dummyRuleTemplate()
+				change(&tmpl)
+				_, err := sut.SetRuleTemplate(ctx, orgID, tmpl)
+				require.ErrorIs(t, err, ErrValidation)
+			})
+		}
+	})
+
+	t.Run("should render rules with the values of the variables", func(t *testing.T) {
+		_, err := sut.SetRuleTemplate(ctx, orgID, dummyRuleTemplate())
+		require.NoError(t, err)
+
+		rule, err := sut.RenderRuleTemplate(ctx, orgID, "high-cpu", definitions.RuleTemplateInstance{
+			FolderUID: "folder",
+			RuleGroup: "group",
+			UID:       "payments-cpu",
+			Values:    map[string]interface{}{"team": "payments", "datasource": "prometheus"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, orgID, rule.OrgID)
+		require.Equal(t, "folder", rule.NamespaceUID)
+		require.Equal(t, "group", rule.RuleGroup)
+		require.Equal(t, "payments-cpu", rule.UID)
+		require.Equal(t, "High CPU of payments", rule.Title)
+		require.Equal(t, map[string]string{"team": "payments"}, rule.Labels)
+		require.Equal(t, "prometheus", rule.Data[0].DatasourceUID)
+		require.JSONEq(t, `{"expr": "cpu{team=\"payments\"} > 80", "threshold": 80, "intervalMs": 1000, "maxDataPoints": 43200}`, string(rule.Data[0].Model))
+
+		rule, err = sut.RenderRuleTemplate(ctx, orgID, "high-cpu", definitions.RuleTemplateInstance{
+			FolderUID: "folder",
+			RuleGroup: "group",
+			Title:     "CPU",
+			Values:    map[string]interface{}{"team": "search", "datasource": "prometheus", "threshold": 95.5},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "CPU", rule.Title)
+		require.JSONEq(t, `{"expr": "cpu{team=\"search\"} > 95.5", "threshold": 95.5, "intervalMs": 1000, "maxDataPoints": 43200}`, string(rule.Data[0].Model))
+	})
+
+	t.Run("should reject invalid values", func(t *testing.T) {
+		cases := map[string]definitions.RuleTemplateInstance{
+			"missing value":    {Values: map[string]interface{}{"team": "payments"}},
+			"unknown variable": {Values: map[string]interface{}{"team": "payments", "datasource": "prometheus", "env": "prod"}},
+			"wrong type":       {Values: map[string]interface{}{"team": "payments", "datasource": "prometheus", "threshold": "80"}},
+			"invalid label":    {Values: map[string]interface{}{"team": "\xff", "datasource": "prometheus"}},
+			"empty datasource": {Values: map[string]interface{}{"team": "payments", "datasource": ""}},
+		}
+		for name, instance := range cases {
+			t.Run(name, func(t *testing.T) {
+				instance.FolderUID, instance.RuleGroup = "folder", "group"
+				_, err := sut.RenderRuleTemplate(ctx, orgID, "high-cpu", instance)
+				require.ErrorIs(t, err, ErrValidation)
+			})
+		}
+	})
+
+	t.Run("should return ErrNotFound for unknown templates", func(t *testing.T) {
+		_, err := sut.RenderRuleTemplate(ctx, orgID, "unknown", definitions.RuleTemplateInstance{FolderUID: "folder", RuleGroup: "group"})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func dummyRuleTemplate() definitions.RuleTemplate {
+	return definitions.RuleTemplate{
+		Name: "high-cpu",
+		Variables: []definitions.RuleTemplateVariable{
+			{Name: "threshold", Type: definitions.RuleTemplateVariableNumber, Default: float64(80)},
+			{Name: "team", Type: definitions.RuleTemplateVariableLabel},
+			{Name: "datasource", Type: definitions.RuleTemplateVariableDatasource},
+		},
+		Rule: definitions.AlertRuleExport{
+			Title:     "High CPU of ${team}",
+			Condition: "A",
+			Data: []definitions.AlertQueryExport{
+				{
+					RefID:             "A",
+					RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(10 * time.Minute)},
+					DatasourceUID:     "${datasource}",
+					Model: map[string]interface{}{
+						"expr":      `cpu{team="${team}"} > ${threshold}`,
+						"threshold": "${threshold}",
+					},
+				},
+			},
+			NoDataState:  models.NoData,
+			ExecErrState: models.ErrorErrState,
+			For:          model.Duration(5 * time.Minute),
+			Labels:       map[string]string{"team": "${team}"},
+		},
+	}
+}
@@ -0,0 +1,181 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// GetTemplateUsage returns the contact points and other templates of the given org that invoke the message template
+// with the given name, or ErrNotFound if there is none. A template is invoked by its name or by the name of a
+// template that it defines. Invocations through other templates are included, so that the usage is everything that
+// is affected by a change of the template.
+func (t *TemplateService) GetTemplateUsage(ctx context.Context, orgID int64, name string) (definitions.TemplateUsage, error) {
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return definitions.TemplateUsage{}, err
+	}
+
+	templates := make(map[string]string, len(revision.cfg.TemplateFiles)+len(t.global))
+	for n, tmpl := range t.global {
+		templates[n] = tmpl
+	}
+	for n, tmpl := range revision.cfg.TemplateFiles {
+		templates[n] = tmpl
+	}
+	content, ok := templates[name]
+	if !ok {
+		return definitions.TemplateUsage{}, fmt.Errorf("%w: template '%s'", ErrNotFound, name)
+	}
+
+	// invoked are the names that invoke the template, they grow with the templates that invoke one of them
+	invoked := definedTemplates(name, content)
+	users := map[string]struct{}{}
+	for changed := true; changed; {
+		changed = false
+		for other, otherContent := range templates {
+			if _, ok := users[other]; ok || other == name {
+				continue
+			}
+			if invokesAny(other, otherContent, invoked) {
+				users[other] = struct{}{}
+				for n := range definedTemplates(other, otherContent) {
+					invoked[n] = struct{}{}
+				}
+				changed = true
+			}
+		}
+	}
+
+	usage := definitions.TemplateUsage{
+		Name:          name,
+		Templates:     make([]string, 0, len(users)),
+		ContactPoints: make([]definitions.TemplateContactPointUsage, 0),
+	}
+	for other := range users {
+		usage.Templates = append(usage.Templates, other)
+	}
+	sort.Strings(usage.Templates)
+
+	for _, receiver := range revision.cfg.AlertmanagerConfig.Receivers {
+		for _, cp := range receiver.PostableGrafanaReceivers.GrafanaManagedReceivers {
+			if cp.Settings == nil {
+				continue
+			}
+			settings := make([]string, 0)
+			invokingSettings("", cp.Settings.Interface(), invoked, &settings)
+			if len(settings) == 0 {
+				continue
+			}
+			sort.Strings(settings)
+			usage.ContactPoints = append(usage.ContactPoints, definitions.TemplateContactPointUsage{
+				UID:      cp.UID,
+				Name:     cp.Name,
+				Type:     cp.Type,
+				Settings: settings,
+			})
+		}
+	}
+	sort.SliceStable(usage.ContactPoints, func(i, j int) bool {
+		return usage.ContactPoints[i].Name < usage.ContactPoints[j].Name
+	})
+	return usage, nil
+}
+
+// invokingSettings adds the path of each string of the settings that invokes one of the templates to paths.
+func invokingSettings(path string, value interface{}, templates map[string]struct{}, paths *[]string) {
+	switch v := value.(type) {
+	case string:
+		if invokesAny(path, v, templates) {
+			*paths = append(*paths, path)
+		}
+	case map[string]interface{}:
+		for key, nested := range v {
+			nestedPath := key
+			if path != "" {
+				nestedPath = path + "." + key
+			}
+			invokingSettings(nestedPath, nested, templates, paths)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			invokingSettings(fmt.Sprintf("%s[%d]", path, i), nested, templates, paths)
+		}
+	}
+}
+
+// definedTemplates returns the name of the template with the given content together with the names of the templates
+// it defines.
+func definedTemplates(name, content string) map[string]struct{} {
+	defined := map[string]struct{}{name: {}}
+	trees, err := parseTemplate(name, content)
+	if err != nil {
+		return defined
+	}
+	for n := range trees {
+		defined[n] = struct{}{}
+	}
+	return defined
+}
+
+// invokesAny returns true if the content invokes one of the templates. Content that cannot be parsed does not invoke
+// templates, as it fails before it can.
+func invokesAny(name, content string, templates map[string]struct{}) bool {
+	if !strings.Contains(content, "{{") {
+		return false
+	}
+	trees, err := parseTemplate(name, content)
+	if err != nil {
+		return false
+	}
+	invoked := map[string]struct{}{}
+	for _, tree := range trees {
+		invokedTemplates(tree.Root, invoked)
+	}
+	for n := range invoked {
+		if _, ok := templates[n]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTemplate parses the content of a template without checking that the functions it calls exist, as the
+// functions of the notifiers are not known here.
+func parseTemplate(name, content string) (map[string]*parse.Tree, error) {
+	trees := map[string]*parse.Tree{}
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(content, "", "", trees); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+// invokedTemplates adds the names of the templates invoked by the node to invoked.
+func invokedTemplates(node parse.Node, invoked map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			invokedTemplates(child, invoked)
+		}
+	case *parse.TemplateNode:
+		invoked[n.Name] = struct{}{}
+	case *parse.IfNode:
+		invokedTemplates(n.List, invoked)
+		invokedTemplates(n.ElseList, invoked)
+	case *parse.RangeNode:
+		invokedTemplates(n.List, invoked)
+		invokedTemplates(n.ElseList, invoked)
+	case *parse.WithNode:
+		invokedTemplates(n.List, invoked)
+		invokedTemplates(n.ElseList, invoked)
+	}
+}
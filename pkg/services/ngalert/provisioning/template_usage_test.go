@@ -0,0 +1,122 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestTemplateUsage(t *testing.T) {
+	createSut := func() *TemplateService {
+		sut := createTemplateServiceSut()
+		sut.global = map[string]string{
+			"global": `{{ define "global.title" }}{{ template "slack.title" . }}{{ end }}`,
+		}
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithTemplateUsage,
+			})
+		return sut
+	}
+
+	t.Run("returns the contact points that invoke the template", func(t *testing.T) {
+		sut := createSut()
+
+		usage, err := sut.GetTemplateUsage(context.Background(), 1, "email")
+
+		require.NoError(t, err)
+		require.Equal(t, definitions.TemplateUsage{
+			Name:      "email",
+			Templates: []string{},
+			ContactPoints: []definitions.TemplateContactPointUsage{
+				{UID: "email-1", Name: "email receiver", Type: "email", Settings: []string{"message", "subject"}},
+			},
+		}, usage)
+	})
+
+	t.Run("includes the contact points that invoke the template through other templates", func(t *testing.T) {
+		sut := createSut()
+
+		usage, err := sut.GetTemplateUsage(context.Background(), 1, "slack")
+
+		require.NoError(t, err)
+		require.Equal(t, definitions.TemplateUsage{
+			Name:      "slack",
+			Templates: []string{"global", "wrapper"},
+			ContactPoints: []definitions.TemplateContactPointUsage{
+				{UID: "slack-1", Name: "slack receiver", Type: "slack", Settings: []string{"text"}},
+				{UID: "webhook-1", Name: "webhook receiver", Type: "webhook", Settings: []string{"fields.summary", "title"}},
+			},
+		}, usage)
+	})
+
+	t.Run("returns no usage for templates that are not invoked", func(t *testing.T) {
+		sut := createSut()
+
+		usage, err := sut.GetTemplateUsage(context.Background(), 1, "unused")
+
+		require.NoError(t, err)
+		require.Empty(t, usage.Templates)
+		require.Empty(t, usage.ContactPoints)
+	})
+
+	t.Run("returns ErrNotFound for unknown template", func(t *testing.T) {
+		sut := createSut()
+
+		_, err := sut.GetTemplateUsage(context.Background(), 1, "does not exist")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+var configWithTemplateUsage = `
+{
+	"template_files": {
+		"email": "{{ define \"email.subject\" }}subject{{ end }}{{ define \"email.message\" }}message{{ end }}",
+		"slack": "{{ define \"slack.title\" }}{{ len .Alerts.Firing }} firing{{ end }}",
+		"wrapper": "{{ define \"wrapper.text\" }}{{ if .Alerts }}{{ template \"slack.title\" . }}{{ end }}{{ end }}",
+		"unused": "{{ define \"unused\" }}unused{{ end }}",
+		"broken": "{{ template \"slack.title\" . "
+	},
+	"alertmanager_config": {
+		"route": {
+			"receiver": "grafana-default-email"
+		},
+		"receivers": [{
+			"name": "grafana-default-email",
+			"grafana_managed_receiver_configs": [{
+				"uid": "email-1",
+				"name": "email receiver",
+				"type": "email",
+				"settings": {
+					"addresses": "<example@email.com>",
+					"subject": "{{ template \"email.subject\" . }}",
+					"message": "{{ template \"email.message\" . }}"
+				}
+			}, {
+				"uid": "slack-1",
+				"name": "slack receiver",
+				"type": "slack",
+				"settings": {
+					"text": "{{ template \"wrapper.text\" . }}",
+					"title": "{{ template \"unknown\" . }}"
+				}
+			}, {
+				"uid": "webhook-1",
+				"name": "webhook receiver",
+				"type": "webhook",
+				"settings": {
+					"title": "{{ template \"global.title\" . }}",
+					"fields": {
+						"summary": "{{- template \"slack.title\" . -}}"
+					}
+				}
+			}]
+		}]
+	}
+}
+`
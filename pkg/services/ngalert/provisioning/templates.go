@@ -3,10 +3,15 @@ package provisioning
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
 )
 
 type TemplateService struct {
@@ -14,31 +19,235 @@ type TemplateService struct {
 	prov   ProvisioningStore
 	xact   TransactionManager
 	log    log.Logger
+	// global are the templates shared by all organizations
+	global map[string]string
 }
 
-func NewTemplateService(config AMConfigStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *TemplateService {
+func NewTemplateService(config AMConfigStore, prov ProvisioningStore, xact TransactionManager, global map[string]string, log log.Logger) *TemplateService {
 	return &TemplateService{
 		config: config,
 		prov:   prov,
 		xact:   xact,
 		log:    log,
+		global: global,
 	}
 }
 
-func (t *TemplateService) GetTemplates(ctx context.Context, orgID int64) (map[string]string, error) {
+// GetDefaultTemplates returns the templates Grafana defines for all notifications, and the defaults of the templated
+// settings of every type of contact point, sorted by type.
+func (t *TemplateService) GetDefaultTemplates() definitions.DefaultTemplates {
+	defaults := definitions.DefaultTemplates{
+		Templates: make([]definitions.DefaultTemplate, 0),
+		Notifiers: make([]definitions.NotifierDefaultTemplates, 0, len(channels.DefaultTemplatedSettings)),
+	}
+	for _, def := range channels.DefaultTemplateDefinitions() {
+		defaults.Templates = append(defaults.Templates, definitions.DefaultTemplate{Name: def.Name, Template: def.Template})
+	}
+	for notifierType, settings := range channels.DefaultTemplatedSettings {
+		defaults.Notifiers = append(defaults.Notifiers, definitions.NotifierDefaultTemplates{Type: notifierType, Settings: settings})
+	}
+	sort.Slice(defaults.Notifiers, func(i, j int) bool {
+		return defaults.Notifiers[i].Type < defaults.Notifiers[j].Type
+	})
+	return defaults
+}
+
+// GetTemplates returns the message templates of the given org, sorted by name, together with their provenance.
+// The global templates that the org does not override are included.
+func (t *TemplateService) GetTemplates(ctx context.Context, orgID int64) ([]definitions.MessageTemplate, error) {
 	revision, err := getLastConfiguration(ctx, orgID, t.config)
 	if err != nil {
 		return nil, err
 	}
 
-	if revision.cfg.TemplateFiles == nil {
-		return map[string]string{}, nil
+	provenances, err := t.prov.GetProvenances(ctx, orgID, (&definitions.MessageTemplate{}).ResourceType())
+	if err != nil {
+		return nil, err
 	}
 
-	return revision.cfg.TemplateFiles, nil
+	templates := make([]definitions.MessageTemplate, 0, len(revision.cfg.TemplateFiles)+len(t.global))
+	for name, tmpl := range revision.cfg.TemplateFiles {
+		templates = append(templates, definitions.MessageTemplate{
+			Name:       name,
+			Template:   tmpl,
+			Provenance: provenances[name],
+		})
+	}
+	for name, tmpl := range t.global {
+		if _, ok := revision.cfg.TemplateFiles[name]; ok {
+			continue
+		}
+		templates = append(templates, definitions.MessageTemplate{
+			Name:     name,
+			Template: tmpl,
+			Global:   true,
+		})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// GetTemplate returns the message template with the given name, or ErrNotFound if there is none. The global
+// template with the name is returned if the org does not have one.
+func (t *TemplateService) GetTemplate(ctx context.Context, orgID int64, name string) (definitions.MessageTemplate, error) {
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+
+	content, ok := revision.cfg.TemplateFiles[name]
+	if !ok {
+		if global, ok := t.global[name]; ok {
+			return definitions.MessageTemplate{Name: name, Template: global, Global: true}, nil
+		}
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: template '%s'", ErrNotFound, name)
+	}
+
+	tmpl := definitions.MessageTemplate{Name: name, Template: content}
+	tmpl.Provenance, err = t.prov.GetProvenance(ctx, &tmpl, orgID)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+	return tmpl, nil
+}
+
+// ExportTemplates returns the message templates of the given org as a file provisioning document. Global templates
+// are not exported, as they are not templates of the org.
+func (t *TemplateService) ExportTemplates(ctx context.Context, orgID int64) (definitions.TemplatesExport, error) {
+	templates, err := t.GetTemplates(ctx, orgID)
+	if err != nil {
+		return definitions.TemplatesExport{}, err
+	}
+
+	export := definitions.TemplatesExport{
+		APIVersion: fileProvisioningAPIVersion,
+		Templates:  make([]definitions.TemplateExportEntry, 0, len(templates)),
+	}
+	for _, tmpl := range templates {
+		if tmpl.Global {
+			continue
+		}
+		export.Templates = append(export.Templates, definitions.TemplateExportEntry{
+			OrgID:    orgID,
+			Name:     tmpl.Name,
+			Template: tmpl.Template,
+		})
+	}
+	return export, nil
+}
+
+// ImportTemplates saves the message templates of a file provisioning document to the given org with the API
+// provenance, and reports what is done with each template. Templates of the document must be of the org, or have no
+// org. A template conflicts with an existing template of the same name with a different content that was not saved
+// with the API. If there are conflicts, ErrImportConflict is returned together with the result and nothing is saved.
+// With overwrite, the conflicting templates without provenance, such as templates created in the UI, are replaced.
+// With dryRun, the result is returned without saving the templates.
+func (t *TemplateService) ImportTemplates(ctx context.Context, orgID int64, doc definitions.TemplatesExport, dryRun, overwrite bool) (definitions.TemplatesImportResult, error) {
+	templates := make([]definitions.MessageTemplate, 0, len(doc.Templates))
+	names := make(map[string]struct{}, len(doc.Templates))
+	for _, entry := range doc.Templates {
+		if entry.OrgID != 0 && entry.OrgID != orgID {
+			return definitions.TemplatesImportResult{}, fmt.Errorf("%w: template '%s' is of organization %d", ErrValidation, entry.Name, entry.OrgID)
+		}
+		if _, ok := names[entry.Name]; ok {
+			return definitions.TemplatesImportResult{}, fmt.Errorf("%w: template '%s' is defined more than once", ErrValidation, entry.Name)
+		}
+		names[entry.Name] = struct{}{}
+		tmpl := definitions.MessageTemplate{Name: entry.Name, Template: entry.Template, Provenance: models.ProvenanceAPI}
+		if err := tmpl.Validate(); err != nil {
+			return definitions.TemplatesImportResult{}, fmt.Errorf("%w: template '%s': %s", ErrValidation, entry.Name, err.Error())
+		}
+		templates = append(templates, tmpl)
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return definitions.TemplatesImportResult{}, err
+	}
+	provenances, err := t.prov.GetProvenances(ctx, orgID, (&definitions.MessageTemplate{}).ResourceType())
+	if err != nil {
+		return definitions.TemplatesImportResult{}, err
+	}
+
+	result := definitions.TemplatesImportResult{
+		DryRun:    dryRun,
+		Templates: make([]definitions.TemplateImportResult, 0, len(templates)),
+	}
+	changed := make([]definitions.MessageTemplate, 0, len(templates))
+	conflict := false
+	for _, tmpl := range templates {
+		res := definitions.TemplateImportResult{Name: tmpl.Name, ExistingProvenance: provenances[tmpl.Name]}
+		existing, exists := revision.cfg.TemplateFiles[tmpl.Name]
+		switch {
+		case !exists:
+			res.Action = definitions.TemplateImportCreated
+		case existing == tmpl.Template:
+			res.Action = definitions.TemplateImportUnchanged
+		case res.ExistingProvenance == models.ProvenanceAPI,
+			res.ExistingProvenance == models.ProvenanceNone && overwrite:
+			res.Action = definitions.TemplateImportUpdated
+		default:
+			res.Action = definitions.TemplateImportConflict
+			conflict = true
+		}
+		if res.Action == definitions.TemplateImportCreated || res.Action == definitions.TemplateImportUpdated {
+			changed = append(changed, tmpl)
+		}
+		result.Templates = append(result.Templates, res)
+	}
+	sort.Slice(result.Templates, func(i, j int) bool {
+		return result.Templates[i].Name < result.Templates[j].Name
+	})
+
+	if conflict {
+		return result, ErrImportConflict
+	}
+	if dryRun || len(changed) == 0 {
+		return result, nil
+	}
+
+	if revision.cfg.TemplateFiles == nil {
+		revision.cfg.TemplateFiles = map[string]string{}
+	}
+	for _, tmpl := range changed {
+		revision.cfg.TemplateFiles[tmpl.Name] = tmpl.Template
+	}
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return definitions.TemplatesImportResult{}, err
+	}
+	cmd := models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		ConfigurationVersion:      revision.version,
+		FetchedConfigurationHash:  revision.concurrencyToken,
+		Default:                   false,
+		OrgID:                     orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
+	}
+	err = t.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := t.config.UpdateAlertmanagerConfiguration(ctx, &cmd); err != nil {
+			return err
+		}
+		for i := range changed {
+			if err := t.prov.SetProvenance(ctx, &changed[i], orgID, models.ProvenanceAPI); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return definitions.TemplatesImportResult{}, err
+	}
+	return result, nil
 }
 
+// SetTemplate creates or replaces the message template with the given name. The template is validated and
+// normalized before it is saved. Templates cannot be saved with a provenance different from the stored one.
+// Saving a template with the name of a global template overrides the global template for the org.
 func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl definitions.MessageTemplate) (definitions.MessageTemplate, error) {
+	tmpl.Global = false
 	err := tmpl.Validate()
 	if err != nil {
 		return definitions.MessageTemplate{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
@@ -49,6 +258,15 @@ func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl def
 		return definitions.MessageTemplate{}, err
 	}
 
+	// check that provenance is not changed in a invalid way
+	storedProvenance, err := t.prov.GetProvenance(ctx, &tmpl, orgID)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+	if storedProvenance != tmpl.Provenance && storedProvenance != models.ProvenanceNone {
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: cannot changed provenance from '%s' to '%s'", ErrValidation, storedProvenance, tmpl.Provenance)
+	}
+
 	if revision.cfg.TemplateFiles == nil {
 		revision.cfg.TemplateFiles = map[string]string{}
 	}
@@ -64,6 +282,7 @@ func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl def
 		FetchedConfigurationHash:  revision.concurrencyToken,
 		Default:                   false,
 		OrgID:                     orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
 	}
 	err = t.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err = t.config.UpdateAlertmanagerConfiguration(ctx, &cmd)
@@ -83,12 +302,28 @@ func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl def
 	return tmpl, nil
 }
 
-func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name string) error {
+// DeleteTemplate deletes the message template with the given name. If the template does not exist, no error is returned.
+// Templates cannot be deleted with a provenance different from the stored one, so that provisioned templates are only
+// deleted the way they were provisioned. Global templates cannot be deleted, but deleting the template of the org
+// that overrides one restores it.
+func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name string, provenance models.Provenance) error {
 	revision, err := getLastConfiguration(ctx, orgID, t.config)
 	if err != nil {
 		return err
 	}
 
+	if _, ok := revision.cfg.TemplateFiles[name]; ok {
+		storedProvenance, err := t.prov.GetProvenance(ctx, &definitions.MessageTemplate{Name: name}, orgID)
+		if err != nil {
+			return err
+		}
+		if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+			return fmt.Errorf("%w: cannot delete template '%s' with provenance '%s' as '%s'", ErrValidation, name, storedProvenance, provenance)
+		}
+	} else if _, ok := t.global[name]; ok {
+		return fmt.Errorf("%w: template '%s' is shared by all organizations and cannot be deleted", ErrValidation, name)
+	}
+
 	delete(revision.cfg.TemplateFiles, name)
 
 	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
@@ -102,6 +337,7 @@ func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name
 		FetchedConfigurationHash:  revision.concurrencyToken,
 		Default:                   false,
 		OrgID:                     orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
 	}
 	err = t.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err = t.config.UpdateAlertmanagerConfiguration(ctx, &cmd)
@@ -123,3 +359,134 @@ func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name
 
 	return nil
 }
+
+// RenameTemplate renames the message template with the given name. The template it defines with its name is renamed
+// too, and the invocations of that template in the other templates and in the settings of the contact points are
+// changed to the new name, in the same configuration save, so that they keep working. Templates cannot be renamed
+// with a provenance different from the stored one, and global templates cannot be renamed.
+func (t *TemplateService) RenameTemplate(ctx context.Context, orgID int64, name, newName string, provenance models.Provenance) (definitions.MessageTemplate, error) {
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+
+	content, ok := revision.cfg.TemplateFiles[name]
+	if !ok {
+		if _, ok := t.global[name]; ok {
+			return definitions.MessageTemplate{}, fmt.Errorf("%w: template '%s' is shared by all organizations and cannot be renamed", ErrValidation, name)
+		}
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: template '%s'", ErrNotFound, name)
+	}
+	if _, ok := revision.cfg.TemplateFiles[newName]; ok {
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: a template with the name '%s' already exists", ErrValidation, newName)
+	}
+	if _, ok := t.global[newName]; ok {
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: the name '%s' is used by a template shared by all organizations", ErrValidation, newName)
+	}
+
+	old := definitions.MessageTemplate{Name: name}
+	storedProvenance, err := t.prov.GetProvenance(ctx, &old, orgID)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: cannot rename template '%s' with provenance '%s' as '%s'", ErrValidation, name, storedProvenance, provenance)
+	}
+
+	renamed := definitions.MessageTemplate{
+		Name:       newName,
+		Template:   renameTemplateActions(content, "define", name, newName),
+		Provenance: provenance,
+	}
+	if err := renamed.Validate(); err != nil {
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	delete(revision.cfg.TemplateFiles, name)
+	for other, otherContent := range revision.cfg.TemplateFiles {
+		revision.cfg.TemplateFiles[other] = renameTemplateActions(otherContent, "template", name, newName)
+	}
+	revision.cfg.TemplateFiles[newName] = renameTemplateActions(renamed.Template, "template", name, newName)
+	renamed.Template = revision.cfg.TemplateFiles[newName]
+
+	contactPoints := 0
+	for _, receiver := range revision.cfg.AlertmanagerConfig.Receivers {
+		for _, cp := range receiver.PostableGrafanaReceivers.GrafanaManagedReceivers {
+			if cp.Settings == nil {
+				continue
+			}
+			if renameInvocationsInSettings(cp.Settings.Interface(), name, newName) {
+				contactPoints++
+			}
+		}
+	}
+
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+	cmd := models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		ConfigurationVersion:      revision.version,
+		FetchedConfigurationHash:  revision.concurrencyToken,
+		Default:                   false,
+		OrgID:                     orgID,
+		Origin:                    models.AlertConfigurationOriginProvisioning,
+	}
+	err = t.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := t.config.UpdateAlertmanagerConfiguration(ctx, &cmd); err != nil {
+			return err
+		}
+		if err := t.prov.DeleteProvenance(ctx, &old, orgID); err != nil {
+			return err
+		}
+		return t.prov.SetProvenance(ctx, &renamed, orgID, renamed.Provenance)
+	})
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+	t.log.Info("renamed template", "name", name, "new_name", newName, "contact_points", contactPoints, "orgID", orgID)
+
+	return renamed, nil
+}
+
+// renameTemplateActions changes the name of the template in the actions with the given keyword, such as
+// {{ template "name" . }}, to the new name.
+func renameTemplateActions(content, keyword, name, newName string) string {
+	if !strings.Contains(content, name) {
+		return content
+	}
+	action := regexp.MustCompile(`(\{\{-?\s*` + keyword + `\s+)(` + regexp.QuoteMeta(strconv.Quote(name)) + "|`" + regexp.QuoteMeta(name) + "`)")
+	return action.ReplaceAllString(content, "${1}"+strings.ReplaceAll(strconv.Quote(newName), "$", "$$"))
+}
+
+// renameInvocationsInSettings changes the invocations of the template in the strings of the settings to the new
+// name. It returns true if a setting is changed.
+func renameInvocationsInSettings(value interface{}, name, newName string) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if s, ok := nested.(string); ok {
+				if renamed := renameTemplateActions(s, "template", name, newName); renamed != s {
+					v[key] = renamed
+					changed = true
+				}
+				continue
+			}
+			changed = renameInvocationsInSettings(nested, name, newName) || changed
+		}
+	case []interface{}:
+		for i, nested := range v {
+			if s, ok := nested.(string); ok {
+				if renamed := renameTemplateActions(s, "template", name, newName); renamed != s {
+					v[i] = renamed
+					changed = true
+				}
+				continue
+			}
+			changed = renameInvocationsInSettings(nested, name, newName) || changed
+		}
+	}
+	return changed
+}
@@ -3,11 +3,14 @@ package provisioning
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
 	"github.com/grafana/grafana/pkg/setting"
 	mock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -20,19 +23,50 @@ func TestTemplateService(t *testing.T) {
 			GetsConfig(models.AlertConfiguration{
 				AlertmanagerConfiguration: configWithTemplates,
 			})
+		sut.prov.(*MockProvisioningStore).EXPECT().
+			GetAllReturns(map[string]models.Provenance{"a": models.ProvenanceFile})
 
 		result, err := sut.GetTemplates(context.Background(), 1)
 
 		require.NoError(t, err)
-		require.Len(t, result, 1)
+		require.Equal(t, []definitions.MessageTemplate{
+			{Name: "a", Template: "template", Provenance: models.ProvenanceFile},
+		}, result)
 	})
 
-	t.Run("service returns empty map when config file contains no templates", func(t *testing.T) {
+	t.Run("service returns a single template with its provenance", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithTemplates,
+			})
+		sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceAPI)
+
+		result, err := sut.GetTemplate(context.Background(), 1, "a")
+
+		require.NoError(t, err)
+		require.Equal(t, definitions.MessageTemplate{Name: "a", Template: "template", Provenance: models.ProvenanceAPI}, result)
+	})
+
+	t.Run("service returns ErrNotFound for unknown template", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithTemplates,
+			})
+
+		_, err := sut.GetTemplate(context.Background(), 1, "does not exist")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("service returns empty list when config file contains no templates", func(t *testing.T) {
 		sut := createTemplateServiceSut()
 		sut.config.(*MockAMConfigStore).EXPECT().
 			GetsConfig(models.AlertConfiguration{
 				AlertmanagerConfiguration: defaultConfig,
 			})
+		sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{})
 
 		result, err := sut.GetTemplates(context.Background(), 1)
 
@@ -76,6 +110,31 @@ func TestTemplateService(t *testing.T) {
 		})
 	})
 
+	t.Run("service returns the default templates and notifier settings", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+
+		defaults := sut.GetDefaultTemplates()
+
+		names := make([]string, 0, len(defaults.Templates))
+		for _, tmpl := range defaults.Templates {
+			names = append(names, tmpl.Name)
+		}
+		require.Contains(t, names, "default.title")
+		require.Contains(t, names, "default.message")
+		require.Len(t, defaults.Notifiers, len(channels.DefaultTemplatedSettings))
+		require.True(t, sort.SliceIsSorted(defaults.Notifiers, func(i, j int) bool {
+			return defaults.Notifiers[i].Type < defaults.Notifiers[j].Type
+		}))
+		for _, notifier := range defaults.Notifiers {
+			if notifier.Type == "slack" {
+				require.Equal(t, map[string]string{
+					"title": `{{ template "default.title" . }}`,
+					"text":  `{{ template "default.message" . }}`,
+				}, notifier.Settings)
+			}
+		}
+	})
+
 	t.Run("setting templates", func(t *testing.T) {
 		t.Run("rejects templates that fail validation", func(t *testing.T) {
 			sut := createTemplateServiceSut()
@@ -135,6 +194,7 @@ func TestTemplateService(t *testing.T) {
 						AlertmanagerConfiguration: configWithTemplates,
 					})
 				sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+				sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone)
 				sut.prov.(*MockProvisioningStore).EXPECT().
 					SetProvenance(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(fmt.Errorf("failed to save provenance"))
@@ -154,7 +214,7 @@ func TestTemplateService(t *testing.T) {
 				sut.config.(*MockAMConfigStore).EXPECT().
 					UpdateAlertmanagerConfiguration(mock.Anything, mock.Anything).
 					Return(fmt.Errorf("failed to save config"))
-				sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+				sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
 
 				_, err := sut.SetTemplate(context.Background(), 1, tmpl)
 
@@ -170,7 +230,7 @@ func TestTemplateService(t *testing.T) {
 					AlertmanagerConfiguration: configWithTemplates,
 				})
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
-			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
 
 			_, err := sut.SetTemplate(context.Background(), 1, tmpl)
 
@@ -185,7 +245,7 @@ func TestTemplateService(t *testing.T) {
 					AlertmanagerConfiguration: defaultConfig,
 				})
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
-			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
 
 			_, err := sut.SetTemplate(context.Background(), 1, tmpl)
 
@@ -203,7 +263,7 @@ func TestTemplateService(t *testing.T) {
 					AlertmanagerConfiguration: defaultConfig,
 				})
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
-			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
 
 			result, _ := sut.SetTemplate(context.Background(), 1, tmpl)
 
@@ -222,7 +282,7 @@ func TestTemplateService(t *testing.T) {
 					AlertmanagerConfiguration: defaultConfig,
 				})
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
-			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
 
 			result, _ := sut.SetTemplate(context.Background(), 1, tmpl)
 
@@ -240,7 +300,7 @@ func TestTemplateService(t *testing.T) {
 					AlertmanagerConfiguration: defaultConfig,
 				})
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
-			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
 
 			_, err := sut.SetTemplate(context.Background(), 1, tmpl)
 
@@ -258,12 +318,68 @@ func TestTemplateService(t *testing.T) {
 					AlertmanagerConfiguration: defaultConfig,
 				})
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
-			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
 
 			_, err := sut.SetTemplate(context.Background(), 1, tmpl)
 
 			require.NoError(t, err)
 		})
+
+		t.Run("rejects changing the provenance of provisioned templates", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			tmpl := createMessageTemplate()
+			tmpl.Provenance = models.ProvenanceAPI
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceFile)
+
+			_, err := sut.SetTemplate(context.Background(), 1, tmpl)
+
+			require.ErrorIs(t, err, ErrValidation)
+			require.ErrorContains(t, err, "cannot changed provenance from 'file' to 'api'")
+		})
+
+		t.Run("accepts templates using template functions", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			tmpl := definitions.MessageTemplate{
+				Name:     "name",
+				Template: "{{ .CommonLabels.alertname | toUpper }} {{ .Alerts.Firing | len }}",
+			}
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: defaultConfig,
+				})
+			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
+
+			_, err := sut.SetTemplate(context.Background(), 1, tmpl)
+
+			require.NoError(t, err)
+		})
+
+		t.Run("rejects templates using unknown functions", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			tmpl := definitions.MessageTemplate{
+				Name:     "name",
+				Template: "{{ .CommonLabels.alertname | doesNotExist }}",
+			}
+
+			_, err := sut.SetTemplate(context.Background(), 1, tmpl)
+
+			require.ErrorIs(t, err, ErrValidation)
+		})
+
+		t.Run("rejects templates with names that are not file names", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			tmpl := createMessageTemplate()
+			tmpl.Name = "../name"
+
+			_, err := sut.SetTemplate(context.Background(), 1, tmpl)
+
+			require.ErrorIs(t, err, ErrValidation)
+		})
 	})
 
 	t.Run("deleting templates", func(t *testing.T) {
@@ -274,7 +390,7 @@ func TestTemplateService(t *testing.T) {
 					GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).
 					Return(fmt.Errorf("failed"))
 
-				err := sut.DeleteTemplate(context.Background(), 1, "template")
+				err := sut.DeleteTemplate(context.Background(), 1, "template", models.ProvenanceNone)
 
 				require.Error(t, err)
 			})
@@ -286,7 +402,7 @@ func TestTemplateService(t *testing.T) {
 						AlertmanagerConfiguration: brokenConfig,
 					})
 
-				err := sut.DeleteTemplate(context.Background(), 1, "template")
+				err := sut.DeleteTemplate(context.Background(), 1, "template", models.ProvenanceNone)
 
 				require.ErrorContains(t, err, "failed to deserialize")
 			})
@@ -297,7 +413,7 @@ func TestTemplateService(t *testing.T) {
 					GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).
 					Return(nil)
 
-				err := sut.DeleteTemplate(context.Background(), 1, "template")
+				err := sut.DeleteTemplate(context.Background(), 1, "template", models.ProvenanceNone)
 
 				require.ErrorContains(t, err, "no alertmanager configuration")
 			})
@@ -313,7 +429,7 @@ func TestTemplateService(t *testing.T) {
 					DeleteProvenance(mock.Anything, mock.Anything, mock.Anything).
 					Return(fmt.Errorf("failed to save provenance"))
 
-				err := sut.DeleteTemplate(context.Background(), 1, "template")
+				err := sut.DeleteTemplate(context.Background(), 1, "template", models.ProvenanceNone)
 
 				require.ErrorContains(t, err, "failed to save provenance")
 			})
@@ -329,7 +445,7 @@ func TestTemplateService(t *testing.T) {
 					Return(fmt.Errorf("failed to save config"))
 				sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
 
-				err := sut.DeleteTemplate(context.Background(), 1, "template")
+				err := sut.DeleteTemplate(context.Background(), 1, "template", models.ProvenanceNone)
 
 				require.ErrorContains(t, err, "failed to save config")
 			})
@@ -342,13 +458,41 @@ func TestTemplateService(t *testing.T) {
 					AlertmanagerConfiguration: configWithTemplates,
 				})
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
-			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
+
+			err := sut.DeleteTemplate(context.Background(), 1, "a", models.ProvenanceNone)
+
+			require.NoError(t, err)
+		})
 
-			err := sut.DeleteTemplate(context.Background(), 1, "a")
+		t.Run("deletes templates with the provenance they were provisioned with", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceAPI).SaveSucceeds()
+
+			err := sut.DeleteTemplate(context.Background(), 1, "a", models.ProvenanceAPI)
 
 			require.NoError(t, err)
 		})
 
+		t.Run("rejects deleting templates provisioned with another provenance", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceFile)
+
+			err := sut.DeleteTemplate(context.Background(), 1, "a", models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, ErrValidation)
+			require.ErrorContains(t, err, "cannot delete template 'a' with provenance 'file' as 'api'")
+		})
+
 		t.Run("does not error when deleting templates that do not exist", func(t *testing.T) {
 			sut := createTemplateServiceSut()
 			sut.config.(*MockAMConfigStore).EXPECT().
@@ -358,7 +502,7 @@ func TestTemplateService(t *testing.T) {
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
 			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
 
-			err := sut.DeleteTemplate(context.Background(), 1, "does not exist")
+			err := sut.DeleteTemplate(context.Background(), 1, "does not exist", models.ProvenanceNone)
 
 			require.NoError(t, err)
 		})
@@ -372,9 +516,338 @@ func TestTemplateService(t *testing.T) {
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
 			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
 
-			err := sut.DeleteTemplate(context.Background(), 1, "a")
+			err := sut.DeleteTemplate(context.Background(), 1, "a", models.ProvenanceNone)
+
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("exporting templates", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		sut.global = map[string]string{"b": "global b"}
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithTemplates,
+			})
+		sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{})
+
+		result, err := sut.ExportTemplates(context.Background(), 2)
+
+		require.NoError(t, err)
+		require.Equal(t, definitions.TemplatesExport{
+			APIVersion: 1,
+			Templates:  []definitions.TemplateExportEntry{{OrgID: 2, Name: "a", Template: "template"}},
+		}, result)
+	})
+
+	t.Run("importing templates", func(t *testing.T) {
+		doc := func(entries ...definitions.TemplateExportEntry) definitions.TemplatesExport {
+			return definitions.TemplatesExport{APIVersion: 1, Templates: entries}
+		}
+
+		t.Run("reports the changes without saving in a dry run", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{"a": models.ProvenanceAPI})
+
+			result, err := sut.ImportTemplates(context.Background(), 1, doc(
+				definitions.TemplateExportEntry{Name: "b", Template: "b"},
+				definitions.TemplateExportEntry{Name: "a", Template: "changed"},
+			), true, false)
+
+			require.NoError(t, err)
+			require.Equal(t, definitions.TemplatesImportResult{
+				DryRun: true,
+				Templates: []definitions.TemplateImportResult{
+					{Name: "a", Action: definitions.TemplateImportUpdated, ExistingProvenance: models.ProvenanceAPI},
+					{Name: "b", Action: definitions.TemplateImportCreated},
+				},
+			}, result)
+			sut.config.(*MockAMConfigStore).AssertNotCalled(t, "UpdateAlertmanagerConfiguration", mock.Anything, mock.Anything)
+		})
+
+		t.Run("saves the changed templates with the API provenance", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{})
+			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+
+			result, err := sut.ImportTemplates(context.Background(), 1, doc(
+				definitions.TemplateExportEntry{OrgID: 1, Name: "b", Template: "b"},
+			), false, false)
+
+			require.NoError(t, err)
+			require.Equal(t, definitions.TemplatesImportResult{
+				Templates: []definitions.TemplateImportResult{{Name: "b", Action: definitions.TemplateImportCreated}},
+			}, result)
+			sut.prov.(*MockProvisioningStore).AssertCalled(t, "SetProvenance", mock.Anything,
+				&definitions.MessageTemplate{Name: "b", Template: "{{ define \"b\" }}\n  b\n{{ end }}", Provenance: models.ProvenanceAPI},
+				int64(1), models.ProvenanceAPI)
+		})
+
+		t.Run("does not change templates with the same content", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: strings.Replace(configWithTemplates, `"a": "template"`, `"a": "{{ define \"a\" }}a{{ end }}"`, 1),
+				})
+			sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{})
+
+			result, err := sut.ImportTemplates(context.Background(), 1, doc(
+				definitions.TemplateExportEntry{Name: "a", Template: `{{ define "a" }}a{{ end }}`},
+			), false, false)
+
+			require.NoError(t, err)
+			require.Equal(t, []definitions.TemplateImportResult{
+				{Name: "a", Action: definitions.TemplateImportUnchanged},
+			}, result.Templates)
+			sut.config.(*MockAMConfigStore).AssertNotCalled(t, "UpdateAlertmanagerConfiguration", mock.Anything, mock.Anything)
+		})
+
+		t.Run("returns conflicts with templates created in the UI", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{})
+
+			result, err := sut.ImportTemplates(context.Background(), 1, doc(
+				definitions.TemplateExportEntry{Name: "a", Template: "changed"},
+				definitions.TemplateExportEntry{Name: "b", Template: "b"},
+			), false, false)
+
+			require.ErrorIs(t, err, ErrImportConflict)
+			require.Equal(t, []definitions.TemplateImportResult{
+				{Name: "a", Action: definitions.TemplateImportConflict},
+				{Name: "b", Action: definitions.TemplateImportCreated},
+			}, result.Templates)
+			sut.config.(*MockAMConfigStore).AssertNotCalled(t, "UpdateAlertmanagerConfiguration", mock.Anything, mock.Anything)
+		})
+
+		t.Run("overwrites templates created in the UI", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{})
+			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+
+			result, err := sut.ImportTemplates(context.Background(), 1, doc(
+				definitions.TemplateExportEntry{Name: "a", Template: "changed"},
+			), false, true)
 
 			require.NoError(t, err)
+			require.Equal(t, []definitions.TemplateImportResult{
+				{Name: "a", Action: definitions.TemplateImportUpdated},
+			}, result.Templates)
+		})
+
+		t.Run("does not overwrite provisioned templates", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{"a": models.ProvenanceFile})
+
+			_, err := sut.ImportTemplates(context.Background(), 1, doc(
+				definitions.TemplateExportEntry{Name: "a", Template: "changed"},
+			), false, true)
+
+			require.ErrorIs(t, err, ErrImportConflict)
+		})
+
+		t.Run("rejects invalid documents", func(t *testing.T) {
+			for name, d := range map[string]definitions.TemplatesExport{
+				"template of another org": doc(definitions.TemplateExportEntry{OrgID: 2, Name: "a", Template: "a"}),
+				"duplicate names": doc(
+					definitions.TemplateExportEntry{Name: "a", Template: "a"},
+					definitions.TemplateExportEntry{Name: "a", Template: "b"},
+				),
+				"invalid template": doc(definitions.TemplateExportEntry{Name: "a", Template: "{{ .Foo"}),
+			} {
+				t.Run(name, func(t *testing.T) {
+					sut := createTemplateServiceSut()
+
+					_, err := sut.ImportTemplates(context.Background(), 1, d, true, false)
+
+					require.ErrorIs(t, err, ErrValidation)
+				})
+			}
+		})
+	})
+
+	t.Run("global templates", func(t *testing.T) {
+		t.Run("are returned unless the org overrides them", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.global = map[string]string{"a": "global a", "b": "global b"}
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.prov.(*MockProvisioningStore).EXPECT().GetAllReturns(map[string]models.Provenance{})
+
+			result, err := sut.GetTemplates(context.Background(), 1)
+
+			require.NoError(t, err)
+			require.Equal(t, []definitions.MessageTemplate{
+				{Name: "a", Template: "template"},
+				{Name: "b", Template: "global b", Global: true},
+			}, result)
+		})
+
+		t.Run("are returned by name", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.global = map[string]string{"b": "global b"}
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+
+			result, err := sut.GetTemplate(context.Background(), 1, "b")
+
+			require.NoError(t, err)
+			require.Equal(t, definitions.MessageTemplate{Name: "b", Template: "global b", Global: true}, result)
+		})
+
+		t.Run("can be overridden by the org", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.global = map[string]string{"b": "global b"}
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone)
+			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+
+			result, err := sut.SetTemplate(context.Background(), 1, definitions.MessageTemplate{Name: "b", Template: "{{ define \"b\" }}org b{{ end }}", Global: true})
+
+			require.NoError(t, err)
+			require.False(t, result.Global)
+		})
+
+		t.Run("cannot be deleted", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.global = map[string]string{"b": "global b"}
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+
+			err := sut.DeleteTemplate(context.Background(), 1, "b", models.ProvenanceNone)
+
+			require.ErrorIs(t, err, ErrValidation)
+		})
+
+		t.Run("are restored by deleting the override of the org", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.global = map[string]string{"a": "global a"}
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplates,
+				})
+			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone).SaveSucceeds()
+
+			err := sut.DeleteTemplate(context.Background(), 1, "a", models.ProvenanceNone)
+
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("renaming templates", func(t *testing.T) {
+		createSut := func() (*TemplateService, *models.SaveAlertmanagerConfigurationCmd) {
+			sut := createTemplateServiceSut()
+			sut.global = map[string]string{"global": `{{ define "global" }}global{{ end }}`}
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithTemplatesToRename,
+				})
+			saved := &models.SaveAlertmanagerConfigurationCmd{}
+			sut.config.(*MockAMConfigStore).EXPECT().
+				UpdateAlertmanagerConfiguration(mock.Anything, mock.Anything).
+				Run(func(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) {
+					*saved = *cmd
+				}).
+				Return(nil).Maybe()
+			return sut, saved
+		}
+
+		t.Run("renames the invocations in templates and contact points", func(t *testing.T) {
+			sut, saved := createSut()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceAPI).SaveSucceeds()
+
+			renamed, err := sut.RenameTemplate(context.Background(), 1, "title", "subject", models.ProvenanceAPI)
+
+			require.NoError(t, err)
+			require.Equal(t, "subject", renamed.Name)
+			require.Equal(t, `{{ define "subject" }}{{ .Status }}{{ end }}{{ define "title.short" }}{{ template "subject" . }}{{ end }}`, renamed.Template)
+			cfg, err := deserializeAlertmanagerConfig([]byte(saved.AlertmanagerConfiguration))
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{
+				"subject": renamed.Template,
+				"wrapper": `{{ define "wrapper" }}{{- template "subject" . -}} {{ template "title.short" . }} {{ template "titles" . }}{{ end }}`,
+			}, cfg.TemplateFiles)
+			settings := cfg.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].Settings
+			require.Equal(t, `{{ template "subject" . }}`, settings.Get("title").MustString())
+			require.Equal(t, "{{ template \"subject\" . }} and {{template \"subject\"}}", settings.Get("message").MustString())
+			require.Equal(t, `{{ template "subject" . }}`, settings.GetPath("fields", "summary").MustString())
+			require.Equal(t, `title`, settings.Get("text").MustString())
+		})
+
+		t.Run("rejects names of existing templates", func(t *testing.T) {
+			for _, name := range []string{"wrapper", "global"} {
+				sut, _ := createSut()
+
+				_, err := sut.RenameTemplate(context.Background(), 1, "title", name, models.ProvenanceAPI)
+
+				require.ErrorIs(t, err, ErrValidation, name)
+			}
+		})
+
+		t.Run("rejects invalid names", func(t *testing.T) {
+			sut, _ := createSut()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceNone)
+
+			_, err := sut.RenameTemplate(context.Background(), 1, "title", "../title", models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, ErrValidation)
+		})
+
+		t.Run("rejects renaming templates provisioned with another provenance", func(t *testing.T) {
+			sut, _ := createSut()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetReturns(models.ProvenanceFile)
+
+			_, err := sut.RenameTemplate(context.Background(), 1, "title", "subject", models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, ErrValidation)
+		})
+
+		t.Run("rejects renaming global templates", func(t *testing.T) {
+			sut, _ := createSut()
+
+			_, err := sut.RenameTemplate(context.Background(), 1, "global", "subject", models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, ErrValidation)
+		})
+
+		t.Run("returns ErrNotFound for unknown template", func(t *testing.T) {
+			sut, _ := createSut()
+
+			_, err := sut.RenameTemplate(context.Background(), 1, "does not exist", "subject", models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, ErrNotFound)
 		})
 	})
 }
@@ -439,3 +912,33 @@ var brokenConfig = `
 		}]
 	}
 }`
+
+var configWithTemplatesToRename = `
+{
+	"template_files": {
+		"title": "{{ define \"title\" }}{{ .Status }}{{ end }}{{ define \"title.short\" }}{{ template \"title\" . }}{{ end }}",
+		"wrapper": "{{ define \"wrapper\" }}{{- template \"title\" . -}} {{ template \"title.short\" . }} {{ template \"titles\" . }}{{ end }}"
+	},
+	"alertmanager_config": {
+		"route": {
+			"receiver": "grafana-default-email"
+		},
+		"receivers": [{
+			"name": "grafana-default-email",
+			"grafana_managed_receiver_configs": [{
+				"uid": "webhook-1",
+				"name": "webhook receiver",
+				"type": "webhook",
+				"settings": {
+					"title": "{{ template \"title\" . }}",
+					"message": "{{ template \"title\" . }} and {{template \"title\"}}",
+					"text": "title",
+					"fields": {
+						"summary": "{{ template \"title\" . }}"
+					}
+				}
+			}]
+		}]
+	}
+}
+`
@@ -0,0 +1,236 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var (
+	hclIdentifier        = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+	hclInvalidIdentifier = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+)
+
+// RuleGroupsHCL renders the rule groups of an export as grafana_rule_group resources of the Terraform provider for
+// Grafana, each with an import block so that Terraform adopts the existing group rather than creating a new one.
+// If tree is not nil, every rule is preceded by a comment listing the contact points its labels are routed to.
+// Webhooks and instance limits of rules are not supported by the provider and are left out.
+func RuleGroupsHCL(export definitions.AlertRulesExport, tree *definitions.Route) string {
+	var routes *dispatch.Route
+	if tree != nil {
+		routes = dispatch.NewRoute(tree.AsAMRoute(), nil)
+	}
+
+	var b strings.Builder
+	names := make(map[string]int, len(export.Groups))
+	for i, g := range export.Groups {
+		name := hclResourceName(g.Name)
+		names[name]++
+		if n := names[name]; n > 1 {
+			name = fmt.Sprintf("%s_%d", name, n)
+		}
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "import {\n  to = grafana_rule_group.%s\n  id = %s\n}\n\n", name, hclString(g.FolderUID+";"+g.Name))
+		fmt.Fprintf(&b, "resource \"grafana_rule_group\" %s {\n", hclString(name))
+		writeHCLAttributes(&b, 1, []hclAttribute{
+			{"org_id", strconv.FormatInt(g.OrgID, 10)},
+			{"name", hclString(g.Name)},
+			{"folder_uid", hclString(g.FolderUID)},
+			{"interval_seconds", strconv.FormatInt(int64(time.Duration(g.Interval)/time.Second), 10)},
+		})
+		for _, r := range g.Rules {
+			// the provider has no labels for groups
+			r.Labels = mergeGroupLabels(r.Labels, g.Labels)
+			b.WriteString("\n")
+			if routes != nil {
+				fmt.Fprintf(&b, "  # contact points: %s\n", strings.Join(ruleContactPoints(routes, r), ", "))
+			}
+			writeHCLRule(&b, r)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func writeHCLRule(b *strings.Builder, r definitions.AlertRuleExport) {
+	b.WriteString("  rule {\n")
+	attributes := []hclAttribute{
+		{"name", hclString(r.Title)},
+		{"for", hclString(time.Duration(r.For).String())},
+		{"condition", hclString(r.Condition)},
+		{"no_data_state", hclString(string(r.NoDataState))},
+		{"exec_err_state", hclString(string(r.ExecErrState))},
+		{"is_paused", strconv.FormatBool(r.IsPaused)},
+	}
+	if len(r.Annotations) > 0 {
+		attributes = append(attributes, hclAttribute{"annotations", hclStringMap(r.Annotations, 2)})
+	}
+	if len(r.Labels) > 0 {
+		attributes = append(attributes, hclAttribute{"labels", hclStringMap(r.Labels, 2)})
+	}
+	writeHCLAttributes(b, 2, attributes)
+
+	for _, q := range r.Data {
+		b.WriteString("\n    data {\n")
+		attributes := []hclAttribute{{"ref_id", hclString(q.RefID)}}
+		if q.QueryType != "" {
+			attributes = append(attributes, hclAttribute{"query_type", hclString(q.QueryType)})
+		}
+		attributes = append(attributes,
+			hclAttribute{"datasource_uid", hclString(q.DatasourceUID)},
+			hclAttribute{"model", "jsonencode(" + hclValue(q.Model, 3) + ")"},
+		)
+		writeHCLAttributes(b, 3, attributes)
+		b.WriteString("\n      relative_time_range {\n")
+		writeHCLAttributes(b, 4, []hclAttribute{
+			{"from", strconv.FormatInt(int64(time.Duration(q.RelativeTimeRange.From)/time.Second), 10)},
+			{"to", strconv.FormatInt(int64(time.Duration(q.RelativeTimeRange.To)/time.Second), 10)},
+		})
+		b.WriteString("      }\n    }\n")
+	}
+	b.WriteString("  }\n")
+}
+
+// ruleContactPoints returns the contact points that the alerts of the rule are routed to, based on the labels of
+// the rule. Labels that are only known once the rule is evaluated, such as templated labels, are not taken into
+// account.
+func ruleContactPoints(routes *dispatch.Route, r definitions.AlertRuleExport) []string {
+	lbls := model.LabelSet{
+		model.AlertNameLabel:                 model.LabelValue(r.Title),
+		model.LabelName(models.RuleUIDLabel): model.LabelValue(r.UID),
+	}
+	for k, v := range r.Labels {
+		lbls[model.LabelName(k)] = model.LabelValue(v)
+	}
+	var result []string
+	seen := make(map[string]struct{})
+	for _, route := range routes.Match(lbls) {
+		if _, ok := seen[route.RouteOpts.Receiver]; ok {
+			continue
+		}
+		seen[route.RouteOpts.Receiver] = struct{}{}
+		result = append(result, route.RouteOpts.Receiver)
+	}
+	return result
+}
+
+type hclAttribute struct {
+	name  string
+	value string
+}
+
+// writeHCLAttributes writes the attributes at the indentation level, with their equal signs aligned like
+// terraform fmt does.
+func writeHCLAttributes(b *strings.Builder, level int, attributes []hclAttribute) {
+	width := 0
+	for _, a := range attributes {
+		if len(a.name) > width {
+			width = len(a.name)
+		}
+	}
+	indent := strings.Repeat("  ", level)
+	for _, a := range attributes {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, a.name, a.value)
+	}
+}
+
+// hclValue returns a value decoded from JSON as an HCL expression. Nested lines are indented from the level.
+func hclValue(v interface{}, level int) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case string:
+		return hclString(v)
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]"
+		}
+		indent := strings.Repeat("  ", level+1)
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, item := range v {
+			fmt.Fprintf(&b, "%s%s,\n", indent, hclValue(item, level+1))
+		}
+		b.WriteString(strings.Repeat("  ", level) + "]")
+		return b.String()
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}"
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("{\n")
+		attributes := make([]hclAttribute, 0, len(keys))
+		for _, k := range keys {
+			attributes = append(attributes, hclAttribute{hclKey(k), hclValue(v[k], level+1)})
+		}
+		writeHCLAttributes(&b, level+1, attributes)
+		b.WriteString(strings.Repeat("  ", level) + "}")
+		return b.String()
+	default:
+		return hclString(fmt.Sprint(v))
+	}
+}
+
+// hclStringMap returns the map as an HCL object with its keys sorted.
+func hclStringMap(m map[string]string, level int) string {
+	values := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		values[k] = v
+	}
+	return hclValue(values, level)
+}
+
+// hclKey returns the key of an object, quoted if it is not a valid identifier.
+func hclKey(k string) string {
+	if hclIdentifier.MatchString(k) {
+		return k
+	}
+	return hclString(k)
+}
+
+// hclString returns s as a quoted HCL string. Template sequences are escaped so that Terraform does not
+// interpolate them, which keeps the Go templates of annotations and labels intact.
+func hclString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	s = strings.ReplaceAll(s, "\r", `\r`)
+	s = strings.ReplaceAll(s, "\t", `\t`)
+	s = strings.ReplaceAll(s, "${", "$${")
+	s = strings.ReplaceAll(s, "%{", "%%{")
+	return `"` + s + `"`
+}
+
+// hclResourceName returns the name of the resource of a rule group, with the characters that are not allowed in
+// Terraform identifiers replaced by underscores.
+func hclResourceName(group string) string {
+	name := strings.Trim(hclInvalidIdentifier.ReplaceAllString(strings.ToLower(group), "_"), "_")
+	if name == "" {
+		return "rule_group"
+	}
+	return "rule_group_" + name
+}
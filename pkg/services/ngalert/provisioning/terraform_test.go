@@ -0,0 +1,126 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRuleGroupsHCL(t *testing.T) {
+	export := definitions.AlertRulesExport{
+		APIVersion: 1,
+		Groups: []definitions.AlertRuleGroupExport{
+			{
+				OrgID:     1,
+				Name:      "CPU usage",
+				FolderUID: "folder",
+				Interval:  model.Duration(time.Minute),
+				Rules: []definitions.AlertRuleExport{
+					{
+						UID:       "rule",
+						Title:     "High CPU",
+						Condition: "B",
+						Data: []definitions.AlertQueryExport{
+							{
+								RefID:             "A",
+								RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(10 * time.Minute)},
+								DatasourceUID:     "prometheus",
+								Model: map[string]interface{}{
+									"expr":         `rate(cpu{job="node"}[5m])`,
+									"intervalMs":   float64(1000),
+									"legendFormat": "${instance}",
+									"refId":        "A",
+									"tags":         []interface{}{"a", true},
+								},
+							},
+						},
+						NoDataState:  models.NoData,
+						ExecErrState: models.AlertingErrState,
+						For:          model.Duration(5 * time.Minute),
+						Annotations:  map[string]string{"summary": "{{ $labels.instance }} is busy"},
+						Labels:       map[string]string{"team": "a"},
+					},
+				},
+			},
+			{OrgID: 1, Name: "cpu_usage", FolderUID: "folder", Interval: model.Duration(time.Minute)},
+		},
+	}
+	tree := &definitions.Route{
+		Receiver: "default",
+		Routes: []*definitions.Route{
+			{Receiver: "team-a", Match: map[string]string{"team": "a"}, Continue: true},
+			{Receiver: "team-a-pager", Match: map[string]string{"alertname": "High CPU"}},
+		},
+	}
+
+	expected := `import {
+  to = grafana_rule_group.rule_group_cpu_usage
+  id = "folder;CPU usage"
+}
+
+resource "grafana_rule_group" "rule_group_cpu_usage" {
+  org_id           = 1
+  name             = "CPU usage"
+  folder_uid       = "folder"
+  interval_seconds = 60
+
+  # contact points: team-a, team-a-pager
+  rule {
+    name           = "High CPU"
+    for            = "5m0s"
+    condition      = "B"
+    no_data_state  = "NoData"
+    exec_err_state = "Alerting"
+    is_paused      = false
+    annotations    = {
+      summary = "{{ $labels.instance }} is busy"
+    }
+    labels         = {
+      team = "a"
+    }
+
+    data {
+      ref_id         = "A"
+      datasource_uid = "prometheus"
+      model          = jsonencode({
+        expr         = "rate(cpu{job=\"node\"}[5m])"
+        intervalMs   = 1000
+        legendFormat = "$${instance}"
+        refId        = "A"
+        tags         = [
+          "a",
+          true,
+        ]
+      })
+
+      relative_time_range {
+        from = 600
+        to   = 0
+      }
+    }
+  }
+}
+
+import {
+  to = grafana_rule_group.rule_group_cpu_usage_2
+  id = "folder;cpu_usage"
+}
+
+resource "grafana_rule_group" "rule_group_cpu_usage_2" {
+  org_id           = 1
+  name             = "cpu_usage"
+  folder_uid       = "folder"
+  interval_seconds = 60
+}
+`
+	require.Equal(t, expected, RuleGroupsHCL(export, tree))
+
+	t.Run("contact points are left out without a policy tree", func(t *testing.T) {
+		require.NotContains(t, RuleGroupsHCL(export, nil), "# contact points")
+	})
+}
@@ -6,7 +6,13 @@ import (
 	"fmt"
 	"strings"
 
+	gfcore "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/quota"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -136,6 +142,129 @@ func (f *fakeProvisioningStore) DeleteProvenance(ctx context.Context, o models.P
 	return nil
 }
 
+type fakePolicyHistoryStore struct {
+	versions map[int64][]models.NotificationPolicyVersion
+}
+
+func newFakePolicyHistoryStore() *fakePolicyHistoryStore {
+	return &fakePolicyHistoryStore{
+		versions: map[int64][]models.NotificationPolicyVersion{},
+	}
+}
+
+func (f *fakePolicyHistoryStore) SaveNotificationPolicyVersion(ctx context.Context, v *models.NotificationPolicyVersion) error {
+	v.Version = int64(len(f.versions[v.OrgID]) + 1)
+	f.versions[v.OrgID] = append(f.versions[v.OrgID], *v)
+	return nil
+}
+
+func (f *fakePolicyHistoryStore) GetNotificationPolicyVersions(ctx context.Context, orgID int64) ([]models.NotificationPolicyVersion, error) {
+	versions := f.versions[orgID]
+	result := make([]models.NotificationPolicyVersion, 0, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		v.PolicyTree = ""
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+func (f *fakePolicyHistoryStore) GetNotificationPolicyVersion(ctx context.Context, orgID int64, version int64) (*models.NotificationPolicyVersion, error) {
+	versions := f.versions[orgID]
+	if version < 1 || version > int64(len(versions)) {
+		return nil, models.ErrNotificationPolicyVersionNotFound
+	}
+	v := versions[version-1]
+	return &v, nil
+}
+
+type fakeRuleReader struct {
+	rules []*models.AlertRule
+}
+
+func (f *fakeRuleReader) ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) error {
+	for _, r := range f.rules {
+		if r.OrgID == query.OrgID {
+			query.Result = append(query.Result, r)
+		}
+	}
+	return nil
+}
+
+type fakeQuotaChecker struct {
+	reached bool
+	calls   int
+}
+
+func (f *fakeQuotaChecker) CheckQuotaReached(ctx context.Context, target string, scopeParams *quota.ScopeParameters) (bool, error) {
+	f.calls++
+	return f.reached, nil
+}
+
+// fakeFolderPolicyChecker has the rule policies of folders by folder UID. Rules are only checked for the labels
+// required by the policy of their folder.
+type fakeFolderPolicyChecker map[string]definitions.FolderRulePolicy
+
+func (f fakeFolderPolicyChecker) GetFolderPolicy(ctx context.Context, orgID int64, folderUID string) (definitions.FolderRulePolicy, error) {
+	return f[folderUID], nil
+}
+
+func (f fakeFolderPolicyChecker) CheckFolderPolicy(ctx context.Context, orgID int64, rule *models.AlertRule) ([]definitions.RuleLintViolation, error) {
+	violations := make([]definitions.RuleLintViolation, 0)
+	for _, label := range f[rule.NamespaceUID].RequiredLabels {
+		if rule.Labels[label] == "" {
+			violations = append(violations, definitions.RuleLintViolation{
+				RuleTitle: rule.Title,
+				Policy:    definitions.RuleLintPolicyFolderRequiredLabels,
+				Severity:  definitions.RuleLintSeverityError,
+				Message:   fmt.Sprintf("the folder requires the label %s", label),
+			})
+		}
+	}
+	return violations, nil
+}
+
+// fakeSilenceReader has the silences of every organization.
+type fakeSilenceReader map[int64]definitions.GettableSilences
+
+func (f fakeSilenceReader) ListSilences(ctx context.Context, orgID int64) (definitions.GettableSilences, error) {
+	return f[orgID], nil
+}
+
+// fakeDatasourceReader has the datasources of every organization.
+type fakeDatasourceReader []*datasources.DataSource
+
+func (f fakeDatasourceReader) GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) error {
+	for _, ds := range f {
+		if ds.OrgId == query.OrgId && ds.Uid == query.Uid {
+			query.Result = ds
+			return nil
+		}
+	}
+	return datasources.ErrDataSourceNotFound
+}
+
+// fakeDashboardReader has the dashboards and folders of every organization.
+type fakeDashboardReader []*gfcore.Dashboard
+
+func (f fakeDashboardReader) GetDashboard(ctx context.Context, query *gfcore.GetDashboardQuery) error {
+	for _, d := range f {
+		if d.OrgId == query.OrgId && d.Uid == query.Uid {
+			query.Result = d
+			return nil
+		}
+	}
+	return dashboards.ErrDashboardNotFound
+}
+
+// fakePluginReader has the installed plugins by ID.
+type fakePluginReader map[string]plugins.PluginDTO
+
+func (f fakePluginReader) Plugin(ctx context.Context, pluginID string) (plugins.PluginDTO, bool) {
+	p, ok := f[pluginID]
+	return p, ok
+}
+
 type NopTransactionManager struct{}
 
 func newNopTransactionManager() *NopTransactionManager {
@@ -165,6 +294,11 @@ func (m *MockProvisioningStore_Expecter) GetReturns(p models.Provenance) *MockPr
 	return m
 }
 
+func (m *MockProvisioningStore_Expecter) GetAllReturns(p map[string]models.Provenance) *MockProvisioningStore_Expecter {
+	m.GetProvenances(mock.Anything, mock.Anything, mock.Anything).Return(p, nil)
+	return m
+}
+
 func (m *MockProvisioningStore_Expecter) SaveSucceeds() *MockProvisioningStore_Expecter {
 	m.SetProvenance(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	m.DeleteProvenance(mock.Anything, mock.Anything, mock.Anything).Return(nil)
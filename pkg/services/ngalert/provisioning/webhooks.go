@@ -0,0 +1,340 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const ProvisioningWebhooksKVNamespace = "ngalert.provisioning_webhooks"
+
+// ProvisioningWebhookSignatureHeader is the header of the HMAC-SHA256 signature of the changes posted to webhooks
+// that have a secret.
+const ProvisioningWebhookSignatureHeader = "X-Grafana-Signature"
+
+// ProvisioningWebhookService stores the webhooks of every organization and notifies them of the changes of
+// provisioned resources.
+type ProvisioningWebhookService struct {
+	kvStore kvstore.KVStore
+	client  *http.Client
+	log     log.Logger
+}
+
+func NewProvisioningWebhookService(kvStore kvstore.KVStore, log log.Logger) *ProvisioningWebhookService {
+	return &ProvisioningWebhookService{
+		kvStore: kvStore,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		log:     log,
+	}
+}
+
+// GetWebhooks returns the webhooks of the organization, sorted by URL, without their secrets.
+func (s *ProvisioningWebhookService) GetWebhooks(ctx context.Context, orgID int64) ([]definitions.ProvisioningWebhook, error) {
+	webhooks, err := s.getWebhooks(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns a webhook of the organization without its secret, or ErrNotFound if it does not exist.
+func (s *ProvisioningWebhookService) GetWebhook(ctx context.Context, orgID int64, uid string) (definitions.ProvisioningWebhook, error) {
+	webhook, err := s.getWebhook(ctx, orgID, uid)
+	webhook.Secret = ""
+	return webhook, err
+}
+
+// CreateWebhook validates and stores a new webhook with a generated UID.
+func (s *ProvisioningWebhookService) CreateWebhook(ctx context.Context, orgID int64, webhook definitions.ProvisioningWebhook) (definitions.ProvisioningWebhook, error) {
+	webhook.UID = util.GenerateShortUID()
+	return s.setWebhook(ctx, orgID, webhook)
+}
+
+// UpdateWebhook validates and stores a webhook, replacing the webhook with the same UID. The secret of the stored
+// webhook is kept if the webhook has none.
+func (s *ProvisioningWebhookService) UpdateWebhook(ctx context.Context, orgID int64, webhook definitions.ProvisioningWebhook) (definitions.ProvisioningWebhook, error) {
+	stored, err := s.getWebhook(ctx, orgID, webhook.UID)
+	if err != nil {
+		return definitions.ProvisioningWebhook{}, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = stored.Secret
+	}
+	return s.setWebhook(ctx, orgID, webhook)
+}
+
+// DeleteWebhook deletes a webhook of the organization.
+func (s *ProvisioningWebhookService) DeleteWebhook(ctx context.Context, orgID int64, uid string) error {
+	if _, err := s.getWebhook(ctx, orgID, uid); err != nil {
+		return err
+	}
+	return kvstore.WithNamespace(s.kvStore, orgID, ProvisioningWebhooksKVNamespace).Del(ctx, uid)
+}
+
+// Subscribed returns true if a webhook of the organization is notified of the changes of the resource, so that the
+// state of the resource before a change is only read when it is needed.
+func (s *ProvisioningWebhookService) Subscribed(ctx context.Context, orgID int64, resource string) bool {
+	webhooks, err := s.getWebhooks(ctx, orgID)
+	if err != nil {
+		s.log.Error("failed to get the provisioning webhooks", "org", orgID, "err", err)
+		return false
+	}
+	for _, webhook := range webhooks {
+		if webhookSubscribed(webhook, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify posts a change to the webhooks of the organization that subscribed to its resource. The diff of the change
+// is computed from its Before and After fields. The changes are posted in the background and the failures are
+// logged, so that they never fail the change itself.
+func (s *ProvisioningWebhookService) Notify(ctx context.Context, orgID int64, change definitions.ProvisioningChange) {
+	webhooks, err := s.getWebhooks(ctx, orgID)
+	if err != nil {
+		s.log.Error("failed to get the provisioning webhooks", "org", orgID, "err", err)
+		return
+	}
+	var targets []definitions.ProvisioningWebhook
+	for _, webhook := range webhooks {
+		if webhookSubscribed(webhook, change.Resource) {
+			targets = append(targets, webhook)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	change.OrgID = orgID
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now().UTC()
+	}
+	diff, err := ProvisioningChangeDiff(change.Before, change.After)
+	if err != nil {
+		s.log.Error("failed to compute the diff of a provisioning change", "org", orgID, "resource", change.Resource, "uid", change.UID, "err", err)
+		return
+	}
+	change.Diff = diff
+	body, err := json.Marshal(change)
+	if err != nil {
+		s.log.Error("failed to marshal a provisioning change", "org", orgID, "resource", change.Resource, "uid", change.UID, "err", err)
+		return
+	}
+	for _, webhook := range targets {
+		go s.post(webhook, body)
+	}
+}
+
+func (s *ProvisioningWebhookService) post(webhook definitions.ProvisioningWebhook, body []byte) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		s.log.Error("failed to create the request to a provisioning webhook", "webhook", webhook.UID, "err", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		req.Header.Set(ProvisioningWebhookSignatureHeader, signProvisioningChange(webhook.Secret, body))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.log.Error("failed to notify a provisioning webhook", "webhook", webhook.UID, "err", err)
+		return
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.log.Warn("failed to close the response body of a provisioning webhook", "webhook", webhook.UID, "err", err)
+		}
+	}()
+	if resp.StatusCode/100 != 2 {
+		s.log.Error("provisioning webhook returned an unexpected status", "webhook", webhook.UID, "status", resp.StatusCode)
+	}
+}
+
+func (s *ProvisioningWebhookService) getWebhooks(ctx context.Context, orgID int64) ([]definitions.ProvisioningWebhook, error) {
+	keys, err := kvstore.WithNamespace(s.kvStore, orgID, ProvisioningWebhooksKVNamespace).Keys(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.ProvisioningWebhook, 0, len(keys))
+	for _, key := range keys {
+		webhook, err := s.getWebhook(ctx, orgID, key.Key)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, webhook)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].URL != result[j].URL {
+			return result[i].URL < result[j].URL
+		}
+		return result[i].UID < result[j].UID
+	})
+	return result, nil
+}
+
+func (s *ProvisioningWebhookService) getWebhook(ctx context.Context, orgID int64, uid string) (definitions.ProvisioningWebhook, error) {
+	raw, ok, err := kvstore.WithNamespace(s.kvStore, orgID, ProvisioningWebhooksKVNamespace).Get(ctx, uid)
+	if err != nil {
+		return definitions.ProvisioningWebhook{}, err
+	}
+	if !ok {
+		return definitions.ProvisioningWebhook{}, fmt.Errorf("%w: webhook '%s' does not exist", ErrNotFound, uid)
+	}
+	var webhook definitions.ProvisioningWebhook
+	if err := json.Unmarshal([]byte(raw), &webhook); err != nil {
+		return definitions.ProvisioningWebhook{}, fmt.Errorf("failed to unmarshal webhook '%s': %w", uid, err)
+	}
+	return webhook, nil
+}
+
+func (s *ProvisioningWebhookService) setWebhook(ctx context.Context, orgID int64, webhook definitions.ProvisioningWebhook) (definitions.ProvisioningWebhook, error) {
+	if err := validateProvisioningWebhook(webhook); err != nil {
+		return definitions.ProvisioningWebhook{}, err
+	}
+	raw, err := json.Marshal(webhook)
+	if err != nil {
+		return definitions.ProvisioningWebhook{}, err
+	}
+	if err := kvstore.WithNamespace(s.kvStore, orgID, ProvisioningWebhooksKVNamespace).Set(ctx, webhook.UID, string(raw)); err != nil {
+		return definitions.ProvisioningWebhook{}, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+func validateProvisioningWebhook(webhook definitions.ProvisioningWebhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: the URL of a webhook must be an absolute http or https URL", ErrValidation)
+	}
+	for _, resource := range webhook.Resources {
+		switch resource {
+		case definitions.ProvisioningResourceAlertRule, definitions.ProvisioningResourceContactPoint,
+			definitions.ProvisioningResourceNotificationPolicy:
+		default:
+			return fmt.Errorf("%w: unknown resource '%s'", ErrValidation, resource)
+		}
+	}
+	return nil
+}
+
+func webhookSubscribed(webhook definitions.ProvisioningWebhook, resource string) bool {
+	if len(webhook.Resources) == 0 {
+		return true
+	}
+	for _, r := range webhook.Resources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func signProvisioningChange(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ProvisioningChangeDiff returns the fields that differ between the JSON representations of two versions of a
+// resource, sorted by path. Objects are compared field by field and arrays item by item.
+func ProvisioningChangeDiff(before, after interface{}) ([]definitions.ProvisioningChangeField, error) {
+	b, err := toJSONValue(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toJSONValue(after)
+	if err != nil {
+		return nil, err
+	}
+	// created and deleted resources are compared with an empty resource, so that each of their fields is listed
+	if _, ok := a.(map[string]interface{}); ok && b == nil {
+		b = map[string]interface{}{}
+	}
+	if _, ok := b.(map[string]interface{}); ok && a == nil {
+		a = map[string]interface{}{}
+	}
+	diff := make([]definitions.ProvisioningChangeField, 0)
+	diffJSONValues("", b, a, &diff)
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Path < diff[j].Path
+	})
+	return diff, nil
+}
+
+// toJSONValue returns the value decoded from the JSON representation of v, so that values of any type can be
+// compared.
+func toJSONValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func diffJSONValues(path string, before, after interface{}, diff *[]definitions.ProvisioningChangeField) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			for k, bv := range b {
+				diffJSONValues(joinDiffPath(path, k), bv, a[k], diff)
+			}
+			for k, av := range a {
+				if _, ok := b[k]; !ok {
+					diffJSONValues(joinDiffPath(path, k), nil, av, diff)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			for i := 0; i < len(b) || i < len(a); i++ {
+				var bv, av interface{}
+				if i < len(b) {
+					bv = b[i]
+				}
+				if i < len(a) {
+					av = a[i]
+				}
+				diffJSONValues(path+"["+strconv.Itoa(i)+"]", bv, av, diff)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(before, after) {
+		*diff = append(*diff, definitions.ProvisioningChangeField{Path: path, Before: before, After: after})
+	}
+}
+
+func joinDiffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,176 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func TestProvisioningWebhookService(t *testing.T) {
+	ctx := context.Background()
+	sut := NewProvisioningWebhookService(kvstore.ProvideService(sqlstore.InitTestDB(t)), log.NewNopLogger())
+	var orgID int64 = 1
+
+	t.Run("should store, list and delete webhooks without returning their secrets", func(t *testing.T) {
+		created, err := sut.CreateWebhook(ctx, orgID, definitions.ProvisioningWebhook{URL: "https://b.example.com", Secret: "s3cr3t"})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+		require.Empty(t, created.Secret)
+		_, err = sut.CreateWebhook(ctx, orgID, definitions.ProvisioningWebhook{URL: "https://a.example.com"})
+		require.NoError(t, err)
+
+		webhooks, err := sut.GetWebhooks(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+		require.Equal(t, "https://a.example.com", webhooks[0].URL)
+		require.Empty(t, webhooks[1].Secret)
+
+		other, err := sut.GetWebhooks(ctx, orgID+1)
+		require.NoError(t, err)
+		require.Empty(t, other)
+
+		for _, w := range webhooks {
+			require.NoError(t, sut.DeleteWebhook(ctx, orgID, w.UID))
+		}
+		webhooks, err = sut.GetWebhooks(ctx, orgID)
+		require.NoError(t, err)
+		require.Empty(t, webhooks)
+	})
+
+	t.Run("should keep the secret of updated webhooks that have none", func(t *testing.T) {
+		created, err := sut.CreateWebhook(ctx, orgID, definitions.ProvisioningWebhook{URL: "https://example.com", Secret: "s3cr3t"})
+		require.NoError(t, err)
+
+		created.URL = "https://other.example.com"
+		_, err = sut.UpdateWebhook(ctx, orgID, created)
+		require.NoError(t, err)
+
+		stored, err := sut.getWebhook(ctx, orgID, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, "https://other.example.com", stored.URL)
+		require.Equal(t, "s3cr3t", stored.Secret)
+		require.NoError(t, sut.DeleteWebhook(ctx, orgID, created.UID))
+	})
+
+	t.Run("should reject invalid webhooks", func(t *testing.T) {
+		for _, webhook := range []definitions.ProvisioningWebhook{
+			{URL: "ftp://example.com"},
+			{URL: "example.com/hook"},
+			{URL: "https://example.com", Resources: []string{"dashboard"}},
+		} {
+			_, err := sut.CreateWebhook(ctx, orgID, webhook)
+			require.ErrorIs(t, err, ErrValidation)
+		}
+	})
+
+	t.Run("should return ErrNotFound for unknown webhooks", func(t *testing.T) {
+		_, err := sut.GetWebhook(ctx, orgID, "unknown")
+		require.ErrorIs(t, err, ErrNotFound)
+		_, err = sut.UpdateWebhook(ctx, orgID, definitions.ProvisioningWebhook{UID: "unknown", URL: "https://example.com"})
+		require.ErrorIs(t, err, ErrNotFound)
+		require.ErrorIs(t, sut.DeleteWebhook(ctx, orgID, "unknown"), ErrNotFound)
+	})
+
+	t.Run("should post signed changes to the subscribed webhooks", func(t *testing.T) {
+		type request struct {
+			signature string
+			change    definitions.ProvisioningChange
+			body      []byte
+		}
+		requests := make(chan request, 2)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			var change definitions.ProvisioningChange
+			_ = json.Unmarshal(body, &change)
+			requests <- request{signature: r.Header.Get(ProvisioningWebhookSignatureHeader), change: change, body: body}
+		}))
+		t.Cleanup(server.Close)
+
+		subscribed, err := sut.CreateWebhook(ctx, orgID, definitions.ProvisioningWebhook{URL: server.URL, Secret: "s3cr3t", Resources: []string{definitions.ProvisioningResourceAlertRule}})
+		require.NoError(t, err)
+		other, err := sut.CreateWebhook(ctx, orgID, definitions.ProvisioningWebhook{URL: server.URL, Resources: []string{definitions.ProvisioningResourceContactPoint}})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, sut.DeleteWebhook(ctx, orgID, subscribed.UID))
+			require.NoError(t, sut.DeleteWebhook(ctx, orgID, other.UID))
+		})
+		require.True(t, sut.Subscribed(ctx, orgID, definitions.ProvisioningResourceAlertRule))
+		require.False(t, sut.Subscribed(ctx, orgID, definitions.ProvisioningResourceNotificationPolicy))
+
+		sut.Notify(ctx, orgID, definitions.ProvisioningChange{
+			Action:   definitions.ProvisioningChangeUpdated,
+			Resource: definitions.ProvisioningResourceAlertRule,
+			UID:      "rule",
+			Before:   map[string]interface{}{"title": "a", "labels": map[string]string{"team": "sre"}},
+			After:    map[string]interface{}{"title": "b", "labels": map[string]string{"team": "sre"}},
+		})
+
+		select {
+		case r := <-requests:
+			require.Equal(t, signProvisioningChange("s3cr3t", r.body), r.signature)
+			require.Equal(t, orgID, r.change.OrgID)
+			require.Equal(t, "rule", r.change.UID)
+			require.False(t, r.change.Timestamp.IsZero())
+			require.Equal(t, []definitions.ProvisioningChangeField{{Path: "title", Before: "a", After: "b"}}, r.change.Diff)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the webhook was not notified")
+		}
+		select {
+		case r := <-requests:
+			t.Fatalf("a webhook that did not subscribe to the resource was notified: %v", r.change)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
+func TestProvisioningChangeDiff(t *testing.T) {
+	t.Run("should list the changed fields of nested objects and arrays", func(t *testing.T) {
+		before := map[string]interface{}{
+			"title":  "rule",
+			"labels": map[string]interface{}{"team": "sre", "severity": "warning"},
+			"data":   []interface{}{map[string]interface{}{"refId": "A", "expr": "up"}},
+		}
+		after := map[string]interface{}{
+			"title":  "rule",
+			"labels": map[string]interface{}{"team": "sre", "env": "prod"},
+			"data": []interface{}{
+				map[string]interface{}{"refId": "A", "expr": "up == 0"},
+				map[string]interface{}{"refId": "B"},
+			},
+		}
+
+		diff, err := ProvisioningChangeDiff(before, after)
+
+		require.NoError(t, err)
+		require.Equal(t, []definitions.ProvisioningChangeField{
+			{Path: "data[0].expr", Before: "up", After: "up == 0"},
+			{Path: "data[1]", After: map[string]interface{}{"refId": "B"}},
+			{Path: "labels.env", After: "prod"},
+			{Path: "labels.severity", Before: "warning"},
+		}, diff)
+	})
+
+	t.Run("should list every field of created resources", func(t *testing.T) {
+		diff, err := ProvisioningChangeDiff(nil, definitions.EmbeddedContactPoint{UID: "cp", Name: "email", Type: "email"})
+
+		require.NoError(t, err)
+		paths := make([]string, 0, len(diff))
+		for _, f := range diff {
+			require.Nil(t, f.Before)
+			paths = append(paths, f.Path)
+		}
+		require.Contains(t, paths, "name")
+		require.Contains(t, paths, "uid")
+	})
+}
@@ -122,6 +122,11 @@ func FromAlertStateToPostableAlerts(firingStates []*state.State, stateManager *s
 		if !alertState.NeedsSending(stateManager.ResendDelay) {
 			continue
 		}
+		// Resolved alerts are held back during the warm-up period, as the alerts that were firing before the
+		// restart might not have been evaluated again yet.
+		if alertState.Resolved && stateManager.IsWarmingUp() {
+			continue
+		}
 		alert := stateToPostableAlert(alertState, appURL)
 		alerts.PostableAlerts = append(alerts.PostableAlerts, *alert)
 		alertState.LastSentAt = ts
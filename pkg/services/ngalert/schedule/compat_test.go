@@ -1,6 +1,7 @@
 package schedule
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"net/url"
@@ -10,12 +11,18 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/go-openapi/strfmt"
 	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -237,6 +244,37 @@ func Test_FromAlertsStateToStoppedAlert(t *testing.T) {
 	require.Equal(t, expected, result.PostableAlerts)
 }
 
+func Test_FromAlertStateToPostableAlertsWarmup(t *testing.T) {
+	appURL := &url.URL{Scheme: "http", Host: "localhost"}
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+	m := metrics.NewNGAlert(prometheus.NewPedanticRegistry())
+	st := state.NewManager(log.NewNopLogger(), m.GetStateMetrics(), appURL, store.NewFakeRuleStore(t), &store.FakeInstanceStore{}, &dashboards.FakeDashboardService{}, &image.NoopImageService{}, clk)
+	st.WarmupPeriod = time.Minute
+	st.Warm(context.Background())
+
+	newStates := func() []*state.State {
+		resolved := randomState(eval.Normal)
+		resolved.Resolved = true
+		resolved.LastSentAt = resolved.LastEvaluationTime.Add(-time.Hour)
+		firing := randomState(eval.Alerting)
+		firing.LastSentAt = firing.LastEvaluationTime.Add(-time.Hour)
+		return []*state.State{resolved, firing}
+	}
+
+	t.Run("resolved alerts are held back during the warm-up period", func(t *testing.T) {
+		states := newStates()
+		alerts := FromAlertStateToPostableAlerts(states, st, appURL)
+		require.Equal(t, []models.PostableAlert{*stateToPostableAlert(states[1], appURL)}, alerts.PostableAlerts)
+	})
+
+	t.Run("resolved alerts are sent after the warm-up period", func(t *testing.T) {
+		clk.Add(time.Minute)
+		alerts := FromAlertStateToPostableAlerts(newStates(), st, appURL)
+		require.Len(t, alerts.PostableAlerts, 2)
+	})
+}
+
 func randomMapOfStrings() map[string]string {
 	max := 5
 	result := make(map[string]string, max)
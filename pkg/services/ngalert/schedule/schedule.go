@@ -210,7 +210,7 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 	for _, cfg := range cfgs {
 		_, isDisabledOrg := sch.disabledOrgs[cfg.OrgID]
 		if isDisabledOrg {
-			sch.log.Debug("skipping starting sender for disabled org", "org", cfg.OrgID)
+			sch.log.Debug("skipping starting sender for disabled org", "orgID", cfg.OrgID)
 			continue
 		}
 
@@ -223,18 +223,18 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 
 		// We have no running sender and no Alertmanager(s) configured, no-op.
 		if !ok && len(cfg.Alertmanagers) == 0 {
-			sch.log.Debug("no external alertmanagers configured", "org", cfg.OrgID)
+			sch.log.Debug("no external alertmanagers configured", "orgID", cfg.OrgID)
 			continue
 		}
 		//  We have no running sender and alerts are handled internally, no-op.
 		if !ok && cfg.SendAlertsTo == ngmodels.InternalAlertmanager {
-			sch.log.Debug("alerts are handled internally", "org", cfg.OrgID)
+			sch.log.Debug("alerts are handled internally", "orgID", cfg.OrgID)
 			continue
 		}
 
 		// We have a running sender but no Alertmanager(s) configured, shut it down.
 		if ok && len(cfg.Alertmanagers) == 0 {
-			sch.log.Debug("no external alertmanager(s) configured, sender will be stopped", "org", cfg.OrgID)
+			sch.log.Debug("no external alertmanager(s) configured, sender will be stopped", "orgID", cfg.OrgID)
 			delete(orgsFound, cfg.OrgID)
 			continue
 		}
@@ -242,14 +242,14 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 		// We have a running sender, check if we need to apply a new config.
 		if ok {
 			if sch.sendersCfgHash[cfg.OrgID] == cfg.AsSHA256() {
-				sch.log.Debug("sender configuration is the same as the one running, no-op", "org", cfg.OrgID, "alertmanagers", cfg.Alertmanagers)
+				sch.log.Debug("sender configuration is the same as the one running, no-op", "orgID", cfg.OrgID, "alertmanagers", cfg.Alertmanagers)
 				continue
 			}
 
-			sch.log.Debug("applying new configuration to sender", "org", cfg.OrgID, "alertmanagers", cfg.Alertmanagers)
+			sch.log.Debug("applying new configuration to sender", "orgID", cfg.OrgID, "alertmanagers", cfg.Alertmanagers)
 			err := existing.ApplyConfig(cfg)
 			if err != nil {
-				sch.log.Error("failed to apply configuration", "err", err, "org", cfg.OrgID)
+				sch.log.Error("failed to apply configuration", "err", err, "orgID", cfg.OrgID)
 				continue
 			}
 			sch.sendersCfgHash[cfg.OrgID] = cfg.AsSHA256()
@@ -257,10 +257,10 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 		}
 
 		// No sender and have Alertmanager(s) to send to - start a new one.
-		sch.log.Info("creating new sender for the external alertmanagers", "org", cfg.OrgID, "alertmanagers", cfg.Alertmanagers)
+		sch.log.Info("creating new sender for the external alertmanagers", "orgID", cfg.OrgID, "alertmanagers", cfg.Alertmanagers)
 		s, err := sender.New(sch.metrics)
 		if err != nil {
-			sch.log.Error("unable to start the sender", "err", err, "org", cfg.OrgID)
+			sch.log.Error("unable to start the sender", "err", err, "orgID", cfg.OrgID)
 			continue
 		}
 
@@ -269,7 +269,7 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 
 		err = s.ApplyConfig(cfg)
 		if err != nil {
-			sch.log.Error("failed to apply configuration", "err", err, "org", cfg.OrgID)
+			sch.log.Error("failed to apply configuration", "err", err, "orgID", cfg.OrgID)
 			continue
 		}
 
@@ -289,9 +289,9 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 
 	// We can now stop these senders w/o having to hold a lock.
 	for orgID, s := range sendersToStop {
-		sch.log.Info("stopping sender", "org", orgID)
+		sch.log.Info("stopping sender", "orgID", orgID)
 		s.Stop()
-		sch.log.Info("stopped sender", "org", orgID)
+		sch.log.Info("stopped sender", "orgID", orgID)
 	}
 
 	sch.log.Debug("finish of admin configuration sync")
@@ -358,13 +358,13 @@ func (sch *schedule) DeleteAlertRule(key ngmodels.AlertRuleKey) {
 	// Ruler API has called DeleteAlertRule. This can happen as requests to
 	// the Ruler API do not hold an exclusive lock over all scheduler operations.
 	if _, ok := sch.schedulableAlertRules.del(key); !ok {
-		sch.log.Info("alert rule cannot be removed from the scheduler as it is not scheduled", "uid", key.UID, "org_id", key.OrgID)
+		sch.log.Info("alert rule cannot be removed from the scheduler as it is not scheduled", "ruleUID", key.UID, "orgID", key.OrgID)
 	}
 
 	// Delete the rule routine
 	ruleInfo, ok := sch.registry.del(key)
 	if !ok {
-		sch.log.Info("alert rule cannot be stopped as it is not running", "uid", key.UID, "org_id", key.OrgID)
+		sch.log.Info("alert rule cannot be stopped as it is not running", "ruleUID", key.UID, "orgID", key.OrgID)
 		return
 	}
 	// stop rule evaluation
@@ -487,11 +487,11 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 				time.AfterFunc(time.Duration(int64(i)*step), func() {
 					success, dropped := item.ruleInfo.eval(tick, item.version)
 					if !success {
-						sch.log.Debug("scheduled evaluation was canceled because evaluation routine was stopped", "uid", item.key.UID, "org", item.key.OrgID, "time", tick)
+						sch.log.Debug("scheduled evaluation was canceled because evaluation routine was stopped", "ruleUID", item.key.UID, "orgID", item.key.OrgID, "time", tick)
 						return
 					}
 					if dropped != nil {
-						sch.log.Warn("Alert rule evaluation is too slow - dropped tick", "uid", item.key.UID, "org", item.key.OrgID, "time", tick)
+						sch.log.Warn("Alert rule evaluation is too slow - dropped tick", "ruleUID", item.key.UID, "orgID", item.key.OrgID, "time", tick)
 						orgID := fmt.Sprint(item.key.OrgID)
 						sch.metrics.EvaluationMissed.WithLabelValues(orgID, item.ruleName).Inc()
 					}
@@ -524,7 +524,7 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 
 //nolint: gocyclo
 func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertRuleKey, evalCh <-chan *evaluation, updateCh <-chan struct{}) error {
-	logger := sch.log.New("uid", key.UID, "org", key.OrgID)
+	logger := sch.log.New("ruleUID", key.UID, "orgID", key.OrgID)
 	logger.Debug("alert rule routine started")
 
 	orgID := fmt.Sprint(key.OrgID)
@@ -711,7 +711,11 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				}
 			}()
 		case <-grafanaCtx.Done():
-			clearState()
+			// The state is cleared only if the rule is deleted. On shutdown, it is kept so that it is saved and
+			// restored on startup, rather than resolving all the alerts on every restart.
+			if !sch.registry.exists(key) {
+				clearState()
+			}
 			logger.Debug("stopping alert rule routine")
 			return nil
 		}
@@ -733,7 +737,7 @@ func (sch *schedule) saveAlertStates(ctx context.Context, states []*state.State)
 		}
 		err := sch.instanceStore.SaveAlertInstance(ctx, &cmd)
 		if err != nil {
-			sch.log.Error("failed to save alert state", "uid", s.AlertRuleUID, "orgId", s.OrgID, "labels", s.Labels.String(), "state", s.State.String(), "msg", err.Error())
+			sch.log.Error("failed to save alert state", "ruleUID", s.AlertRuleUID, "orgID", s.OrgID, "labels", s.Labels.String(), "state", s.State.String(), "msg", err.Error())
 		}
 	}
 }
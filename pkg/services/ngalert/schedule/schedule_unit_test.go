@@ -503,6 +503,30 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 			err := waitForErrChannel(t, stoppedChan)
 			require.NoError(t, err)
 		})
+
+		t.Run("and keep the state on shutdown", func(t *testing.T) {
+			stoppedChan := make(chan error)
+			sch, ruleStore, _, _, _ := createSchedule(make(chan time.Time))
+			rule := CreateTestAlertRule(t, ruleStore, 10, rand.Int63(), eval.Alerting)
+			sch.stateManager.Put([]*state.State{{
+				AlertRuleUID: rule.UID,
+				OrgID:        rule.OrgID,
+				CacheId:      util.GenerateShortUID(),
+				State:        eval.Alerting,
+			}})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			info, _ := sch.registry.getOrCreateInfo(ctx, rule.GetKey())
+			go func() {
+				err := sch.ruleRoutine(info.ctx, rule.GetKey(), info.evalCh, info.updateCh)
+				stoppedChan <- err
+			}()
+
+			cancel()
+			err := waitForErrChannel(t, stoppedChan)
+			require.NoError(t, err)
+			require.Len(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID), 1)
+		})
 	})
 
 	t.Run("should fetch rule from database only if new version is greater than current", func(t *testing.T) {
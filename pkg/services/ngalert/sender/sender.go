@@ -39,7 +39,7 @@ type Sender struct {
 }
 
 func New(_ *metrics.Scheduler) (*Sender, error) {
-	l := log.New("sender")
+	l := log.New("ngalert.sender")
 	sdCtx, sdCancel := context.WithCancel(context.Background())
 	s := &Sender{
 		logger:   l,
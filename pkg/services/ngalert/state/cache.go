@@ -33,17 +33,19 @@ func newCache(logger log.Logger, metrics *metrics.State, externalURL *url.URL) *
 	}
 }
 
-func (c *cache) getOrCreate(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
+func (c *cache) getOrCreate(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, defaultLabels map[string]string) *State {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
 
-	// clone the labels so we don't change eval.Result
-	labels := result.Instance.Copy()
+	// clone the labels so we don't change eval.Result, the default labels of the organization can be used in templates
+	labels := mergeLabels(result.Instance, defaultLabels)
 	attachRuleLabels(labels, alertRule)
 	ruleLabels, annotations := c.expandRuleLabelsAndAnnotations(ctx, alertRule, labels, result)
 
-	// if duplicate labels exist, alertRule label will take precedence
+	// if duplicate labels exist, alertRule label will take precedence, and default labels are only used if neither
+	// the rule nor the instance has the label
 	lbs := mergeLabels(ruleLabels, result.Instance)
+	lbs = mergeLabels(lbs, defaultLabels)
 	attachRuleLabels(lbs, alertRule)
 
 	il := ngModels.InstanceLabels(lbs)
@@ -0,0 +1,63 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// InstanceLimitExceededReason is the reason of the state history entry of a rule that starts to produce more alert
+// instances than its limit.
+const InstanceLimitExceededReason = "InstanceLimitExceeded"
+
+// instanceLimit returns the maximum number of alert instances of an evaluation of the rule, zero or less if there is
+// no limit.
+func (st *Manager) instanceLimit(alertRule *ngModels.AlertRule) int64 {
+	if alertRule.MaxInstances > 0 {
+		return alertRule.MaxInstances
+	}
+	return st.MaxInstancesPerRule
+}
+
+// isInstanceLimitExceeded returns true if the previous evaluations of the rule exceeded its limit of alert instances.
+func (st *Manager) isInstanceLimitExceeded(alertRule *ngModels.AlertRule) bool {
+	for _, s := range st.GetStatesForRuleUID(alertRule.OrgID, alertRule.UID) {
+		if _, ok := s.Labels[ngModels.InstanceLimitExceededLabel]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// instanceLimitExceededResult returns the result that replaces the results of an evaluation that produced more alert
+// instances than the limit of the rule. It is always alerting, so that the rule does not silently stop to alert.
+func instanceLimitExceededResult(results eval.Results, limit int64) eval.Result {
+	first := results[0]
+	return eval.Result{
+		Instance:           data.Labels{ngModels.InstanceLimitExceededLabel: "true"},
+		State:              eval.Alerting,
+		EvaluatedAt:        first.EvaluatedAt,
+		EvaluationDuration: first.EvaluationDuration,
+		EvaluationString:   fmt.Sprintf("%d alert instances exceed the limit of %d", len(results), limit),
+		QueryStats:         first.QueryStats,
+	}
+}
+
+// annotateInstanceLimitExceeded records in the state history of the rule that it produced more alert instances than
+// its limit.
+func (st *Manager) annotateInstanceLimitExceeded(ctx context.Context, alertRule *ngModels.AlertRule, evaluatedAt time.Time, count int, limit int64) {
+	newState := InstanceStateAndReason{State: eval.Alerting, Reason: InstanceLimitExceededReason}
+	st.saveAnnotation(ctx, alertRule, &annotations.Item{
+		AlertId:  alertRule.ID,
+		OrgId:    alertRule.OrgID,
+		NewState: newState.String(),
+		Text:     fmt.Sprintf("%s - %d alert instances exceed the limit of %d", alertRule.Title, count, limit),
+		Epoch:    evaluatedAt.UnixNano() / int64(time.Millisecond),
+	})
+}
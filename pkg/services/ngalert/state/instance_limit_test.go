@@ -0,0 +1,101 @@
+package state_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestInstanceLimit(t *testing.T) {
+	repo := store.NewFakeAnnotationsRepo()
+	annotations.SetRepository(repo)
+	evaluationTime := time.Now()
+	results := func(count int) eval.Results {
+		res := make(eval.Results, 0, count)
+		for i := 0; i < count; i++ {
+			res = append(res, eval.Result{
+				Instance:    data.Labels{"instance": fmt.Sprint(i)},
+				State:       eval.Normal,
+				EvaluatedAt: evaluationTime,
+			})
+		}
+		return res
+	}
+	setup := func(t *testing.T, serverLimit, ruleLimit int64) (*state.Manager, *models.AlertRule) {
+		st := state.NewManager(log.New("test_instance_limit"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, &dashboards.FakeDashboardService{}, &image.NotAvailableImageService{}, clock.New())
+		st.MaxInstancesPerRule = serverLimit
+		rule := &models.AlertRule{
+			OrgID:           1,
+			Title:           "test_title",
+			UID:             fmt.Sprintf("test_alert_rule_uid_%d_%d", serverLimit, ruleLimit),
+			NamespaceUID:    "test_namespace_uid",
+			IntervalSeconds: 10,
+			MaxInstances:    ruleLimit,
+		}
+		return st, rule
+	}
+
+	t.Run("alert instances within the limit are kept", func(t *testing.T) {
+		st, rule := setup(t, 3, 0)
+
+		states := st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(3))
+		require.Len(t, states, 3)
+	})
+
+	t.Run("alert instances over the limit are replaced with a single alert", func(t *testing.T) {
+		st, rule := setup(t, 3, 0)
+		annotationCount := repo.Len()
+		exceeded := testMetrics.GetStateMetrics().InstanceLimitExceeded.WithLabelValues("1")
+		before := testutil.ToFloat64(exceeded)
+
+		states := st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(5))
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Alerting, states[0].State)
+		require.Equal(t, "true", states[0].Labels[models.InstanceLimitExceededLabel])
+		require.Equal(t, "5", states[0].Annotations[models.InstanceCountAnnotation])
+		require.Equal(t, "5 alert instances exceed the limit of 3", states[0].LastEvaluationString)
+		require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), 1)
+		require.Equal(t, before+1, testutil.ToFloat64(exceeded))
+
+		// the state transition of the alert and the exceeded limit are in the state history
+		require.Eventually(t, func() bool { return repo.Len() == annotationCount+2 }, time.Second, 10*time.Millisecond)
+
+		// the exceeded limit is recorded once while it is exceeded
+		st.ProcessEvalResults(context.Background(), evaluationTime.Add(10*time.Second), rule, results(6))
+		require.Equal(t, before+2, testutil.ToFloat64(exceeded))
+		require.Never(t, func() bool { return repo.Len() > annotationCount+2 }, 100*time.Millisecond, 10*time.Millisecond)
+	})
+
+	t.Run("the limit of the rule takes precedence", func(t *testing.T) {
+		st, rule := setup(t, 3, 10)
+
+		states := st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(5))
+		require.Len(t, states, 5)
+
+		st, rule = setup(t, 0, 2)
+		states = st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(5))
+		require.Len(t, states, 1)
+	})
+
+	t.Run("alert instances are not limited without a limit", func(t *testing.T) {
+		st, rule := setup(t, 0, 0)
+
+		states := st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(50))
+		require.Len(t, states, 50)
+	})
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/annotations"
@@ -32,6 +34,11 @@ type AlertInstanceManager interface {
 	GetStatesForRuleUID(orgID int64, alertRuleUID string) []*State
 }
 
+// DefaultLabelsProvider provides the default labels of organizations.
+type DefaultLabelsProvider interface {
+	DefaultLabels(ctx context.Context, orgID int64) (map[string]string, error)
+}
+
 type Manager struct {
 	log     log.Logger
 	metrics *metrics.State
@@ -40,11 +47,23 @@ type Manager struct {
 	cache       *cache
 	quit        chan struct{}
 	ResendDelay time.Duration
+	// MaxInstancesPerRule is the maximum number of alert instances of an evaluation of the rules without a limit of
+	// their own. Alert instances are not limited if it is zero or less.
+	MaxInstancesPerRule int64
+	// DefaultLabels provides the labels that are added to every alert instance of an organization that does not have
+	// a label with the same name. There are no default labels if it is nil.
+	DefaultLabels DefaultLabelsProvider
+	// WarmupPeriod is the time after the state is restored on startup during which resolved alerts are not sent to the
+	// Alertmanager. They are sent once it is over, unless the alert fired again in the meantime.
+	WarmupPeriod time.Duration
+	// warmupEnd is the end of the warm-up period. It is zero until the state is restored.
+	warmupEnd time.Time
 
 	ruleStore        store.RuleStore
 	instanceStore    store.InstanceStore
 	dashboardService dashboards.DashboardService
 	imageService     image.ImageService
+	webhooks         *webhookSender
 }
 
 func NewManager(logger log.Logger, metrics *metrics.State, externalURL *url.URL,
@@ -60,6 +79,7 @@ func NewManager(logger log.Logger, metrics *metrics.State, externalURL *url.URL,
 		instanceStore:    instanceStore,
 		dashboardService: dashboardService,
 		imageService:     imageService,
+		webhooks:         newWebhookSender(logger),
 		clock:            clock,
 	}
 	go manager.recordMetrics()
@@ -73,6 +93,7 @@ func (st *Manager) Close() {
 func (st *Manager) Warm(ctx context.Context) {
 	st.log.Info("warming cache for startup")
 	st.ResetCache()
+	st.warmupEnd = st.clock.Now().Add(st.WarmupPeriod)
 
 	orgIds, err := st.instanceStore.FetchOrgIds(ctx)
 	if err != nil {
@@ -105,7 +126,7 @@ func (st *Manager) Warm(ctx context.Context) {
 		for _, entry := range cmd.Result {
 			ruleForEntry, ok := ruleByUID[entry.RuleUID]
 			if !ok {
-				st.log.Error("rule not found for instance, ignoring", "rule", entry.RuleUID)
+				st.log.Error("rule not found for instance, ignoring", "ruleUID", entry.RuleUID)
 				continue
 			}
 
@@ -136,8 +157,8 @@ func (st *Manager) Warm(ctx context.Context) {
 	}
 }
 
-func (st *Manager) getOrCreate(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
-	return st.cache.getOrCreate(ctx, alertRule, result)
+func (st *Manager) getOrCreate(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, defaultLabels map[string]string) *State {
+	return st.cache.getOrCreate(ctx, alertRule, result, defaultLabels)
 }
 
 func (st *Manager) set(entry *State) {
@@ -148,6 +169,11 @@ func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) (*State, error
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
 
+// IsWarmingUp returns true during the warm-up period after the state is restored on startup.
+func (st *Manager) IsWarmingUp() bool {
+	return st.clock.Now().Before(st.warmupEnd)
+}
+
 // ResetCache is used to ensure a clean cache on startup.
 func (st *Manager) ResetCache() {
 	st.cache.reset()
@@ -159,11 +185,26 @@ func (st *Manager) RemoveByRuleUID(orgID int64, ruleUID string) {
 }
 
 func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time, alertRule *ngModels.AlertRule, results eval.Results) []*State {
-	st.log.Debug("state manager processing evaluation results", "uid", alertRule.UID, "resultCount", len(results))
+	st.log.Debug("state manager processing evaluation results", "ruleUID", alertRule.UID, "resultCount", len(results))
+	instanceCount, limit := len(results), st.instanceLimit(alertRule)
+	limitExceeded := limit > 0 && int64(instanceCount) > limit
+	if limitExceeded {
+		st.log.Warn("alert rule produced more alert instances than its limit", "ruleUID", alertRule.UID, "instances", instanceCount, "limit", limit)
+		st.metrics.InstanceLimitExceeded.WithLabelValues(fmt.Sprint(alertRule.OrgID)).Inc()
+		if !st.isInstanceLimitExceeded(alertRule) {
+			go st.annotateInstanceLimitExceeded(ctx, alertRule, evaluatedAt, instanceCount, limit)
+		}
+		results = eval.Results{instanceLimitExceededResult(results, limit)}
+	}
+
+	defaultLabels := st.defaultLabels(ctx, alertRule)
 	var states []*State
 	processedResults := make(map[string]*State, len(results))
 	for _, result := range results {
-		s := st.setNextState(ctx, alertRule, result)
+		s := st.setNextState(ctx, alertRule, result, defaultLabels)
+		if limitExceeded {
+			s.Annotations[ngModels.InstanceCountAnnotation] = strconv.Itoa(instanceCount)
+		}
 		states = append(states, s)
 		processedResults[s.CacheId] = s
 	}
@@ -203,8 +244,8 @@ func (st *Manager) maybeTakeScreenshot(
 }
 
 // Set the current state based on evaluation results
-func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
-	currentState := st.getOrCreate(ctx, alertRule, result)
+func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, defaultLabels map[string]string) *State {
+	currentState := st.getOrCreate(ctx, alertRule, result, defaultLabels)
 
 	currentState.LastEvaluationTime = result.EvaluatedAt
 	currentState.EvaluationDuration = result.EvaluationDuration
@@ -213,13 +254,14 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		EvaluationState: result.State,
 		Values:          NewEvaluationValues(result.Values),
 		Condition:       alertRule.Condition,
+		QueryStats:      result.QueryStats,
 	})
 	currentState.LastEvaluationString = result.EvaluationString
 	currentState.TrimResults(alertRule)
 	oldState := currentState.State
 	oldReason := currentState.StateReason
 
-	st.log.Debug("setting alert state", "uid", alertRule.UID)
+	st.log.Debug("setting alert state", "ruleUID", alertRule.UID)
 	switch result.State {
 	case eval.Normal:
 		currentState.resultNormal(alertRule, result)
@@ -242,8 +284,11 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	}
 
 	// Set Resolved property so the scheduler knows to send a postable alert
-	// to Alertmanager.
-	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
+	// to Alertmanager. An alert resolved during the warm-up period stays resolved
+	// until it is sent.
+	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal ||
+		currentState.Resolved && currentState.State == eval.Normal &&
+			currentState.StartsAt.Before(st.warmupEnd) && currentState.LastSentAt.Before(currentState.StartsAt)
 
 	err := st.maybeTakeScreenshot(ctx, alertRule, currentState, oldState)
 	if err != nil {
@@ -258,11 +303,28 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 
 	shouldUpdateAnnotation := oldState != currentState.State || oldReason != currentState.StateReason
 	if shouldUpdateAnnotation {
-		go st.annotateState(ctx, alertRule, currentState.Labels, result.EvaluatedAt, InstanceStateAndReason{State: currentState.State, Reason: currentState.StateReason}, InstanceStateAndReason{State: oldState, Reason: oldReason})
+		go st.annotateState(ctx, alertRule, currentState.Labels, result.EvaluatedAt, result.QueryStats, InstanceStateAndReason{State: currentState.State, Reason: currentState.StateReason}, InstanceStateAndReason{State: oldState, Reason: oldReason})
+	}
+	if event, ok := webhookEvent(oldState, currentState); ok {
+		st.webhooks.notify(ctx, alertRule, currentState, event, result.EvaluatedAt)
 	}
 	return currentState
 }
 
+// defaultLabels returns the default labels of the organization of the rule. The rule is evaluated without them if
+// they cannot be fetched, rather than not at all.
+func (st *Manager) defaultLabels(ctx context.Context, alertRule *ngModels.AlertRule) map[string]string {
+	if st.DefaultLabels == nil {
+		return nil
+	}
+	labels, err := st.DefaultLabels.DefaultLabels(ctx, alertRule.OrgID)
+	if err != nil {
+		st.log.Error("failed to get the default labels of the organization", "orgID", alertRule.OrgID, "ruleUID", alertRule.UID, "err", err)
+		return nil
+	}
+	return labels
+}
+
 func (st *Manager) GetAll(orgID int64) []*State {
 	return st.cache.getAll(orgID)
 }
@@ -295,13 +357,16 @@ func (st *Manager) Put(states []*State) {
 	}
 }
 
-// TODO: why wouldn't you allow other types like NoData or Error?
 func translateInstanceState(state ngModels.InstanceStateType) eval.State {
-	switch {
-	case state == ngModels.InstanceStateFiring:
+	switch state {
+	case ngModels.InstanceStateFiring:
 		return eval.Alerting
-	case state == ngModels.InstanceStateNormal:
+	case ngModels.InstanceStateNormal:
 		return eval.Normal
+	case ngModels.InstanceStatePending:
+		return eval.Pending
+	case ngModels.InstanceStateNoData:
+		return eval.NoData
 	default:
 		return eval.Error
 	}
@@ -321,8 +386,8 @@ func (i InstanceStateAndReason) String() string {
 	return s
 }
 
-func (st *Manager) annotateState(ctx context.Context, alertRule *ngModels.AlertRule, labels data.Labels, evaluatedAt time.Time, currentData, previousData InstanceStateAndReason) {
-	st.log.Debug("alert state changed creating annotation", "alertRuleUID", alertRule.UID, "newState", currentData.String(), "oldState", previousData.String())
+func (st *Manager) annotateState(ctx context.Context, alertRule *ngModels.AlertRule, labels data.Labels, evaluatedAt time.Time, queryStats map[string]expr.QueryStats, currentData, previousData InstanceStateAndReason) {
+	st.log.Debug("alert state changed creating annotation", "ruleUID", alertRule.UID, "newState", currentData.String(), "oldState", previousData.String())
 
 	labels = removePrivateLabels(labels)
 	annotationText := fmt.Sprintf("%s {%s} - %s", alertRule.Title, labels.String(), currentData.String())
@@ -335,14 +400,21 @@ func (st *Manager) annotateState(ctx context.Context, alertRule *ngModels.AlertR
 		Text:      annotationText,
 		Epoch:     evaluatedAt.UnixNano() / int64(time.Millisecond),
 	}
+	if len(queryStats) > 0 {
+		item.Data = simplejson.NewFromAny(map[string]interface{}{"queryStats": queryStats})
+	}
+	st.saveAnnotation(ctx, alertRule, item)
+}
 
+// saveAnnotation saves an annotation of the state history of an alert rule, on the panel of the rule if it has one.
+func (st *Manager) saveAnnotation(ctx context.Context, alertRule *ngModels.AlertRule, item *annotations.Item) {
 	dashUid, ok := alertRule.Annotations[ngModels.DashboardUIDAnnotation]
 	if ok {
 		panelUid := alertRule.Annotations[ngModels.PanelIDAnnotation]
 
 		panelId, err := strconv.ParseInt(panelUid, 10, 64)
 		if err != nil {
-			st.log.Error("error parsing panelUID for alert annotation", "panelUID", panelUid, "alertRuleUID", alertRule.UID, "error", err.Error())
+			st.log.Error("error parsing panelUID for alert annotation", "panelUID", panelUid, "ruleUID", alertRule.UID, "error", err.Error())
 			return
 		}
 
@@ -353,7 +425,7 @@ func (st *Manager) annotateState(ctx context.Context, alertRule *ngModels.AlertR
 
 		err = st.dashboardService.GetDashboard(ctx, query)
 		if err != nil {
-			st.log.Error("error getting dashboard for alert annotation", "dashboardUID", dashUid, "alertRuleUID", alertRule.UID, "err", err.Error())
+			st.log.Error("error getting dashboard for alert annotation", "dashboardUID", dashUid, "ruleUID", alertRule.UID, "err", err.Error())
 			return
 		}
 
@@ -363,7 +435,7 @@ func (st *Manager) annotateState(ctx context.Context, alertRule *ngModels.AlertR
 
 	annotationRepo := annotations.GetRepository()
 	if err := annotationRepo.Save(item); err != nil {
-		st.log.Error("error saving alert annotation", "alertRuleUID", alertRule.UID, "err", err.Error())
+		st.log.Error("error saving alert annotation", "ruleUID", alertRule.UID, "err", err.Error())
 		return
 	}
 }
@@ -373,22 +445,25 @@ func (st *Manager) staleResultsHandler(ctx context.Context, evaluatedAt time.Tim
 	for _, s := range allStates {
 		_, ok := states[s.CacheId]
 		if !ok && isItStale(evaluatedAt, s.LastEvaluationTime, alertRule.IntervalSeconds) {
-			st.log.Debug("removing stale state entry", "orgID", s.OrgID, "alertRuleUID", s.AlertRuleUID, "cacheID", s.CacheId)
+			st.log.Debug("removing stale state entry", "orgID", s.OrgID, "ruleUID", s.AlertRuleUID, "cacheID", s.CacheId)
 			st.cache.deleteEntry(s.OrgID, s.AlertRuleUID, s.CacheId)
 			ilbs := ngModels.InstanceLabels(s.Labels)
 			_, labelsHash, err := ilbs.StringAndHash()
 			if err != nil {
-				st.log.Error("unable to get labelsHash", "err", err.Error(), "orgID", s.OrgID, "alertRuleUID", s.AlertRuleUID)
+				st.log.Error("unable to get labelsHash", "err", err.Error(), "orgID", s.OrgID, "ruleUID", s.AlertRuleUID)
 			}
 
 			if err = st.instanceStore.DeleteAlertInstance(ctx, s.OrgID, s.AlertRuleUID, labelsHash); err != nil {
-				st.log.Error("unable to delete stale instance from database", "err", err.Error(), "orgID", s.OrgID, "alertRuleUID", s.AlertRuleUID, "cacheID", s.CacheId)
+				st.log.Error("unable to delete stale instance from database", "err", err.Error(), "orgID", s.OrgID, "ruleUID", s.AlertRuleUID, "cacheID", s.CacheId)
 			}
 
 			if s.State == eval.Alerting {
-				st.annotateState(ctx, alertRule, s.Labels, evaluatedAt,
+				st.annotateState(ctx, alertRule, s.Labels, evaluatedAt, nil,
 					InstanceStateAndReason{State: eval.Normal, Reason: ""},
 					InstanceStateAndReason{State: s.State, Reason: s.StateReason})
+				resolved := *s
+				resolved.State, resolved.StateReason = eval.Normal, ""
+				st.webhooks.notify(ctx, alertRule, &resolved, ngModels.AlertRuleWebhookResolved, evaluatedAt)
 			}
 		}
 	}
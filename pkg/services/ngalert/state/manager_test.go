@@ -1906,6 +1906,100 @@ func TestProcessEvalResults(t *testing.T) {
 	}
 }
 
+func TestProcessEvalResultsQueryStats(t *testing.T) {
+	evaluationTime := time.Now()
+	st := state.NewManager(log.New("test_state_manager"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, &dashboards.FakeDashboardService{}, &image.NotAvailableImageService{}, clock.New())
+	fakeAnnoRepo := store.NewFakeAnnotationsRepo()
+	annotations.SetRepository(fakeAnnoRepo)
+
+	rule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test_title",
+		UID:             "test_alert_rule_uid",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+	}
+	queryStats := map[string]expr.QueryStats{
+		"A": {SeriesCount: 1, SampleCount: 60, Bytes: 1024, Latency: 2 * time.Second},
+	}
+
+	states := st.ProcessEvalResults(context.Background(), evaluationTime, rule, eval.Results{{
+		Instance:    data.Labels{"instance_label": "test"},
+		State:       eval.Alerting,
+		EvaluatedAt: evaluationTime,
+		QueryStats:  queryStats,
+	}})
+	require.Len(t, states, 1)
+	require.Len(t, states[0].Results, 1)
+	require.Equal(t, queryStats, states[0].Results[0].QueryStats)
+
+	require.Eventually(t, func() bool {
+		return fakeAnnoRepo.Len() == 1
+	}, time.Second, 100*time.Millisecond)
+	require.Equal(t, queryStats, fakeAnnoRepo.Items[0].Data.Get("queryStats").Interface())
+}
+
+type fakeDefaultLabelsProvider struct {
+	labels map[int64]map[string]string
+	err    error
+}
+
+func (f *fakeDefaultLabelsProvider) DefaultLabels(_ context.Context, orgID int64) (map[string]string, error) {
+	return f.labels[orgID], f.err
+}
+
+func TestProcessEvalResultsDefaultLabels(t *testing.T) {
+	evaluationTime := time.Now()
+	annotations.SetRepository(store.NewFakeAnnotationsRepo())
+	rule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test_title",
+		UID:             "test_alert_rule_uid",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+		Labels:          map[string]string{"team": "rule", "summary": "{{ $labels.env }}"},
+	}
+	results := eval.Results{{
+		Instance:    data.Labels{"org": "instance"},
+		State:       eval.Normal,
+		EvaluatedAt: evaluationTime,
+	}}
+	setup := func(provider state.DefaultLabelsProvider) *state.Manager {
+		st := state.NewManager(log.New("test_state_manager"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, &dashboards.FakeDashboardService{}, &image.NotAvailableImageService{}, clock.New())
+		st.DefaultLabels = provider
+		return st
+	}
+
+	t.Run("default labels are added unless the rule or the instance has them", func(t *testing.T) {
+		st := setup(&fakeDefaultLabelsProvider{labels: map[int64]map[string]string{
+			1: {"env": "prod", "org": "acme", "team": "default"},
+		}})
+
+		states := st.ProcessEvalResults(context.Background(), evaluationTime, rule, results)
+
+		require.Len(t, states, 1)
+		require.Equal(t, data.Labels{
+			"__alert_rule_namespace_uid__": "test_namespace_uid",
+			"__alert_rule_uid__":           "test_alert_rule_uid",
+			"alertname":                    "test_title",
+			"env":                          "prod",
+			"org":                          "instance",
+			"team":                         "rule",
+			"summary":                      "prod",
+		}, states[0].Labels)
+	})
+
+	t.Run("rules are evaluated without default labels if they cannot be fetched", func(t *testing.T) {
+		st := setup(&fakeDefaultLabelsProvider{err: errors.New("unavailable")})
+
+		states := st.ProcessEvalResults(context.Background(), evaluationTime, rule, results)
+
+		require.Len(t, states, 1)
+		require.NotContains(t, states[0].Labels, "env")
+		require.Equal(t, "rule", states[0].Labels["team"])
+	})
+}
+
 func printAllAnnotations(annos []*annotations.Item) string {
 	str := "["
 	for _, anno := range annos {
@@ -2025,3 +2119,87 @@ func TestStaleResultsHandler(t *testing.T) {
 		assert.Equal(t, tc.finalStateCount, len(existingStatesForRule))
 	}
 }
+
+func TestWarmRestoresPendingState(t *testing.T) {
+	evaluationTime := time.Now().Truncate(time.Second)
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, 1)
+	rule.For = 5 * time.Minute
+	err := dbstore.SaveAlertInstance(ctx, &models.SaveAlertInstanceCommand{
+		RuleOrgID:         rule.OrgID,
+		RuleUID:           rule.UID,
+		Labels:            models.InstanceLabels{"__alert_rule_namespace_uid__": "namespace", "__alert_rule_uid__": rule.UID, "alertname": rule.Title, "test1": "testValue1"},
+		State:             models.InstanceStatePending,
+		LastEvalTime:      evaluationTime.Add(-time.Minute),
+		CurrentStateSince: evaluationTime.Add(-5 * time.Minute),
+		CurrentStateEnd:   evaluationTime.Add(2 * time.Minute),
+	})
+	require.NoError(t, err)
+
+	st := state.NewManager(log.New("test_warm"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, &dashboards.FakeDashboardService{}, &image.NoopImageService{}, clock.New())
+	st.Warm(ctx)
+	states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	require.Equal(t, eval.Pending, states[0].State)
+	require.Equal(t, evaluationTime.Add(-5*time.Minute).UTC(), states[0].StartsAt.UTC())
+
+	states = st.ProcessEvalResults(ctx, evaluationTime, rule, eval.Results{{
+		Instance:    data.Labels{"test1": "testValue1"},
+		State:       eval.Alerting,
+		EvaluatedAt: evaluationTime,
+	}})
+	require.Len(t, states, 1)
+	require.Equal(t, eval.Alerting, states[0].State, "the pending period before the restart should count towards the for duration")
+}
+
+func TestWarmupPeriod(t *testing.T) {
+	evaluationTime := time.Now().Truncate(time.Second)
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, 1)
+	err := dbstore.SaveAlertInstance(ctx, &models.SaveAlertInstanceCommand{
+		RuleOrgID:         rule.OrgID,
+		RuleUID:           rule.UID,
+		Labels:            models.InstanceLabels{"__alert_rule_namespace_uid__": "namespace", "__alert_rule_uid__": rule.UID, "alertname": rule.Title, "test1": "testValue1"},
+		State:             models.InstanceStateFiring,
+		LastEvalTime:      evaluationTime.Add(-time.Minute),
+		CurrentStateSince: evaluationTime.Add(-10 * time.Minute),
+		CurrentStateEnd:   evaluationTime.Add(2 * time.Minute),
+	})
+	require.NoError(t, err)
+
+	clk := clock.NewMock()
+	clk.Set(evaluationTime)
+	st := state.NewManager(log.New("test_warmup"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, &dashboards.FakeDashboardService{}, &image.NoopImageService{}, clk)
+	st.WarmupPeriod = 2 * time.Minute
+	require.False(t, st.IsWarmingUp())
+	st.Warm(ctx)
+	require.True(t, st.IsWarmingUp())
+
+	normal := func(at time.Time) *state.State {
+		states := st.ProcessEvalResults(ctx, at, rule, eval.Results{{
+			Instance:    data.Labels{"test1": "testValue1"},
+			State:       eval.Normal,
+			EvaluatedAt: at,
+		}})
+		require.Len(t, states, 1)
+		return states[0]
+	}
+
+	s := normal(evaluationTime)
+	require.True(t, s.Resolved)
+
+	// The alert stays resolved until it is sent, even after the warm-up period.
+	clk.Add(3 * time.Minute)
+	require.False(t, st.IsWarmingUp())
+	s = normal(evaluationTime.Add(3 * time.Minute))
+	require.True(t, s.Resolved)
+
+	s.LastSentAt = clk.Now()
+	st.Put([]*state.State{s})
+	s = normal(evaluationTime.Add(4 * time.Minute))
+	require.False(t, s.Resolved)
+}
@@ -45,6 +45,8 @@ type Evaluation struct {
 	Values map[string]*float64
 	// Condition is the refID specified as the condition in the alerting rule at the time of the evaluation.
 	Condition string
+	// QueryStats contains the statistics of the data source queries of the evaluation by RefID.
+	QueryStats map[string]expr.QueryStats
 }
 
 // NewEvaluationValues returns the labels and values for each RefID in the capture.
@@ -0,0 +1,143 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var (
+	// WebhookMaxAttempts is how many times a rule webhook is called before the event is dropped.
+	WebhookMaxAttempts = 3
+	// WebhookRetryDelay is the delay before the first retry of a rule webhook, it doubles with each attempt.
+	WebhookRetryDelay = time.Second
+	webhookTimeout    = 10 * time.Second
+)
+
+// webhookPayload is the body of the requests sent to the webhooks of an alert rule.
+type webhookPayload struct {
+	Event       ngModels.AlertRuleWebhookEvent `json:"event"`
+	OrgID       int64                          `json:"orgId"`
+	RuleUID     string                         `json:"ruleUID"`
+	RuleTitle   string                         `json:"ruleTitle"`
+	State       string                         `json:"state"`
+	StateReason string                         `json:"stateReason,omitempty"`
+	Labels      map[string]string              `json:"labels"`
+	Annotations map[string]string              `json:"annotations"`
+	Value       string                         `json:"value,omitempty"`
+	Error       string                         `json:"error,omitempty"`
+	EvaluatedAt time.Time                      `json:"evaluatedAt"`
+}
+
+// webhookSender delivers the state transitions of alert instances to the webhooks of their rule.
+type webhookSender struct {
+	log    log.Logger
+	client *http.Client
+}
+
+func newWebhookSender(logger log.Logger) *webhookSender {
+	return &webhookSender{
+		log:    logger,
+		client: httpclient.NewOutboundClient(httpclient.OutboundOptions{Name: "alerting_rule_webhooks", Timeout: webhookTimeout}),
+	}
+}
+
+// webhookEvent returns the event to send to the webhooks of a rule for a state transition, if any.
+func webhookEvent(oldState eval.State, s *State) (ngModels.AlertRuleWebhookEvent, bool) {
+	switch {
+	case s.State == eval.Alerting && oldState != eval.Alerting:
+		return ngModels.AlertRuleWebhookFiring, true
+	case s.State == eval.Normal && oldState == eval.Alerting:
+		return ngModels.AlertRuleWebhookResolved, true
+	case s.State == eval.Error && oldState != eval.Error:
+		return ngModels.AlertRuleWebhookError, true
+	}
+	return "", false
+}
+
+// notify sends the event to the webhooks of the rule that are subscribed to it. The requests are sent in the
+// background, so it does not delay the evaluation of the rule.
+func (w *webhookSender) notify(ctx context.Context, alertRule *ngModels.AlertRule, s *State, event ngModels.AlertRuleWebhookEvent, evaluatedAt time.Time) {
+	if len(alertRule.Webhooks) == 0 {
+		return
+	}
+	payload := webhookPayload{
+		Event:       event,
+		OrgID:       alertRule.OrgID,
+		RuleUID:     alertRule.UID,
+		RuleTitle:   alertRule.Title,
+		State:       s.State.String(),
+		StateReason: s.StateReason,
+		Labels:      removePrivateLabels(s.Labels),
+		Annotations: s.Annotations,
+		Value:       s.LastEvaluationString,
+		EvaluatedAt: evaluatedAt,
+	}
+	if s.Error != nil {
+		payload.Error = s.Error.Error()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		w.log.Error("failed to encode rule webhook payload", "ruleUID", alertRule.UID, "err", err)
+		return
+	}
+	for _, webhook := range alertRule.Webhooks {
+		if webhook.Subscribed(event) {
+			go w.send(ctx, alertRule.UID, webhook.URL, body)
+		}
+	}
+}
+
+// send posts the body to the URL, retrying with an exponential backoff on network errors and server errors.
+func (w *webhookSender) send(ctx context.Context, ruleUID, url string, body []byte) {
+	delay := WebhookRetryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(ctx, url, body)
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= WebhookMaxAttempts {
+			w.log.Error("failed to call rule webhook", "ruleUID", ruleUID, "url", url, "attempts", attempt, "err", err)
+			return
+		}
+		w.log.Debug("failed to call rule webhook, retrying", "ruleUID", ruleUID, "url", url, "attempt", attempt, "err", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post returns whether the request should be retried if it fails.
+func (w *webhookSender) post(ctx context.Context, url string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Grafana")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			w.log.Warn("failed to close response body", "err", err)
+		}
+	}()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+}
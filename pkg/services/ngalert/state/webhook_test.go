@@ -0,0 +1,121 @@
+package state_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+type webhookRecorder struct {
+	mtx      sync.Mutex
+	failures int
+	calls    int
+	events   []map[string]interface{}
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.calls++
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var payload map[string]interface{}
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.events = append(r.events, payload)
+}
+
+func (r *webhookRecorder) received() []map[string]interface{} {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]map[string]interface{}{}, r.events...)
+}
+
+func TestRuleWebhooks(t *testing.T) {
+	oldDelay := state.WebhookRetryDelay
+	state.WebhookRetryDelay = time.Millisecond
+	t.Cleanup(func() { state.WebhookRetryDelay = oldDelay })
+	annotations.SetRepository(store.NewFakeAnnotationsRepo())
+
+	evaluationTime := time.Now()
+	results := func(s eval.State, offset time.Duration) eval.Results {
+		return eval.Results{{Instance: data.Labels{"instance": "a"}, State: s, EvaluatedAt: evaluationTime.Add(offset)}}
+	}
+	setup := func(t *testing.T, recorder *webhookRecorder, events ...models.AlertRuleWebhookEvent) (*state.Manager, *models.AlertRule) {
+		server := httptest.NewServer(recorder)
+		t.Cleanup(server.Close)
+		st := state.NewManager(log.New("test_rule_webhooks"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, &dashboards.FakeDashboardService{}, &image.NotAvailableImageService{}, clock.New())
+		rule := &models.AlertRule{
+			OrgID:           1,
+			Title:           "test_title",
+			UID:             "test_alert_rule_uid",
+			NamespaceUID:    "test_namespace_uid",
+			IntervalSeconds: 10,
+			Webhooks:        []models.AlertRuleWebhook{{URL: server.URL, Events: events}},
+		}
+		return st, rule
+	}
+
+	t.Run("state transitions are sent to the webhook", func(t *testing.T) {
+		recorder := &webhookRecorder{}
+		st, rule := setup(t, recorder)
+
+		st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(eval.Normal, 0))
+		st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(eval.Alerting, 10*time.Second))
+		require.Eventually(t, func() bool { return len(recorder.received()) == 1 }, time.Second, 10*time.Millisecond)
+		// a firing alert is only sent once
+		st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(eval.Alerting, 20*time.Second))
+		st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(eval.Normal, 30*time.Second))
+		require.Eventually(t, func() bool { return len(recorder.received()) == 2 }, time.Second, 10*time.Millisecond)
+
+		events := recorder.received()
+		require.Equal(t, "firing", events[0]["event"])
+		require.Equal(t, "Alerting", events[0]["state"])
+		require.Equal(t, "test_alert_rule_uid", events[0]["ruleUID"])
+		require.Equal(t, map[string]interface{}{"alertname": "test_title", "instance": "a"}, events[0]["labels"])
+		require.Equal(t, "resolved", events[1]["event"])
+	})
+
+	t.Run("only subscribed events are sent", func(t *testing.T) {
+		recorder := &webhookRecorder{}
+		st, rule := setup(t, recorder, models.AlertRuleWebhookResolved)
+
+		st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(eval.Alerting, 0))
+		st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(eval.Normal, 10*time.Second))
+		require.Eventually(t, func() bool { return len(recorder.received()) == 1 }, time.Second, 10*time.Millisecond)
+		require.Equal(t, "resolved", recorder.received()[0]["event"])
+	})
+
+	t.Run("failed calls are retried", func(t *testing.T) {
+		recorder := &webhookRecorder{failures: 2}
+		st, rule := setup(t, recorder)
+
+		st.ProcessEvalResults(context.Background(), evaluationTime, rule, results(eval.Alerting, 0))
+		require.Eventually(t, func() bool { return len(recorder.received()) == 1 }, time.Second, 10*time.Millisecond)
+		recorder.mtx.Lock()
+		defer recorder.mtx.Unlock()
+		require.Equal(t, 3, recorder.calls)
+	})
+}
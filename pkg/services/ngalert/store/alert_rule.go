@@ -72,7 +72,7 @@ func getAlertRuleByUID(sess *sqlstore.DBSession, alertRuleUID string, orgID int6
 
 // DeleteAlertRulesByUID is a handler for deleting an alert rule.
 func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error {
-	logger := st.Logger.New("org_id", orgID, "rule_uids", ruleUID)
+	logger := st.Logger.New("orgID", orgID, "ruleUIDs", ruleUID)
 	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		rows, err := sess.Table("alert_rule").Where("org_id = ?", orgID).In("uid", ruleUID).Delete(ngmodels.AlertRule{})
 		if err != nil {
@@ -175,6 +175,10 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				For:              r.For,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				Webhooks:         r.Webhooks,
+				IsPaused:         r.IsPaused,
+				MaxInstances:     r.MaxInstances,
+				GroupLabels:      r.GroupLabels,
 			})
 		}
 		if len(newRules) > 0 {
@@ -243,6 +247,10 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []UpdateRule) erro
 				For:              r.New.For,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				Webhooks:         r.New.Webhooks,
+				IsPaused:         r.New.IsPaused,
+				MaxInstances:     r.New.MaxInstances,
+				GroupLabels:      r.New.GroupLabels,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -379,7 +387,7 @@ func (st DBstore) GetNamespaceByTitle(ctx context.Context, namespace string, org
 		g := guardian.New(ctx, folder.Id, orgID, user)
 		if canSave, err := g.CanSave(); err != nil || !canSave {
 			if err != nil {
-				st.Logger.Error("checking can save permission has failed", "userId", user.UserId, "username", user.Login, "namespace", namespace, "orgId", orgID, "err", err)
+				st.Logger.Error("checking can save permission has failed", "userId", user.UserId, "username", user.Login, "namespace", namespace, "orgID", orgID, "err", err)
 			}
 			return nil, ngmodels.ErrCannotEditNamespace
 		}
@@ -410,6 +418,8 @@ func (st DBstore) GetAlertRulesForScheduling(ctx context.Context, query *ngmodel
 			}
 			q = q.NotIn("org_id", excludeOrgs...)
 		}
+		// paused rules are not scheduled, they are stopped like deleted rules
+		q = q.Where("is_paused = ?", false)
 		q = q.Asc("namespace_uid", "rule_group", "rule_group_idx", "id")
 		if err := q.Find(&alerts); err != nil {
 			return err
@@ -441,6 +451,12 @@ var GenerateNewAlertRuleUID = func(sess *sqlstore.DBSession, orgID int64, ruleTi
 
 // validateAlertRule validates the alert rule interval and organisation.
 func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
+	return ValidateAlertRule(alertRule, int64(st.BaseInterval.Seconds()))
+}
+
+// ValidateAlertRule validates an alert rule the way it is validated before it is stored, the interval of the rule
+// must be a multiple of the base interval of the scheduler.
+func ValidateAlertRule(alertRule ngmodels.AlertRule, baseIntervalSeconds int64) error {
 	if len(alertRule.Data) == 0 {
 		return fmt.Errorf("%w: no queries or expressions are found", ngmodels.ErrAlertRuleFailedValidation)
 	}
@@ -449,7 +465,7 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 		return fmt.Errorf("%w: title is empty", ngmodels.ErrAlertRuleFailedValidation)
 	}
 
-	if err := ngmodels.ValidateRuleGroupInterval(alertRule.IntervalSeconds, int64(st.BaseInterval.Seconds())); err != nil {
+	if err := ngmodels.ValidateRuleGroupInterval(alertRule.IntervalSeconds, baseIntervalSeconds); err != nil {
 		return err
 	}
 
@@ -482,5 +498,15 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if alertRule.MaxInstances < 0 {
+		return fmt.Errorf("%w: field `max_instances` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	for _, w := range alertRule.Webhooks {
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -89,3 +89,21 @@ func withIntervalMatching(baseInterval time.Duration) func(*models.AlertRule) {
 		rule.For = time.Duration(rule.IntervalSeconds*rand.Int63n(9)+1) * time.Second
 	}
 }
+
+func TestGetAlertRulesForScheduling(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	store := DBstore{
+		SQLStore:     sqlStore,
+		BaseInterval: time.Duration(rand.Int63n(100)) * time.Second,
+	}
+	active := models.AlertRuleGen(withIntervalMatching(store.BaseInterval))()
+	paused := models.AlertRuleGen(withIntervalMatching(store.BaseInterval))()
+	paused.IsPaused = true
+	_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*active, *paused})
+	require.NoError(t, err)
+
+	query := &models.GetAlertRulesForSchedulingQuery{}
+	require.NoError(t, store.GetAlertRulesForScheduling(context.Background(), query))
+	require.Len(t, query.Result, 1)
+	require.Equal(t, active.UID, query.Result[0].UID)
+}
@@ -57,6 +57,91 @@ func (st *DBstore) GetAllLatestAlertmanagerConfiguration(ctx context.Context) ([
 	return result, nil
 }
 
+// GetAlertmanagerConfigurationVersions returns the stored versions of the alertmanager configuration of an
+// organization, newest first. The configurations themselves are not loaded. At most limit versions are returned,
+// after skipping offset versions, or all versions if limit is 0.
+func (st *DBstore) GetAlertmanagerConfigurationVersions(ctx context.Context, orgID int64, limit, offset int) ([]*models.AlertConfiguration, error) {
+	var result []*models.AlertConfiguration
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		q := sess.Table("alert_configuration").
+			Cols("id", "configuration_hash", "configuration_version", "created_at", "default", "org_id", "origin", "created_by").
+			Where("org_id = ?", orgID).
+			Desc("id")
+		if limit > 0 {
+			q = q.Limit(limit, offset)
+		}
+		return q.Find(&result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteOldAlertmanagerConfigurations removes the stored versions of the alertmanager configuration of an organization
+// that are older than the newest maxVersions versions, or that were created before olderThan. A limit is not enforced
+// if it is zero. The latest version is never removed. It returns the number of removed versions.
+func (st *DBstore) DeleteOldAlertmanagerConfigurations(ctx context.Context, orgID int64, maxVersions int, olderThan time.Time) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		// deleteBefore removes the versions older than the one at the offset from the newest version.
+		deleteBefore := func(offset int, condition string, args ...interface{}) error {
+			var ids []int64
+			if err := sess.Table("alert_configuration").Cols("id").Where("org_id = ?", orgID).Desc("id").Limit(1, offset).Find(&ids); err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			query := "DELETE FROM alert_configuration WHERE org_id = ? AND id < ?" + condition
+			res, err := sess.Exec(append([]interface{}{query, orgID, ids[0]}, args...)...)
+			if err != nil {
+				return err
+			}
+			rows, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			deleted += rows
+			return nil
+		}
+
+		if maxVersions > 0 {
+			if err := deleteBefore(maxVersions-1, ""); err != nil {
+				return err
+			}
+		}
+		if !olderThan.IsZero() {
+			return deleteBefore(0, " AND created_at < ?", olderThan.Unix())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// GetAlertmanagerConfigurationVersion returns a stored version of the alertmanager configuration of an organization.
+// It returns ErrNoAlertmanagerConfiguration if the version is not found.
+func (st *DBstore) GetAlertmanagerConfigurationVersion(ctx context.Context, orgID int64, id int64) (*models.AlertConfiguration, error) {
+	c := &models.AlertConfiguration{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		ok, err := sess.Where("org_id = ? AND id = ?", orgID, id).Get(c)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNoAlertmanagerConfiguration
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 // SaveAlertmanagerConfiguration creates an alertmanager configuration.
 func (st DBstore) SaveAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
 	return st.SaveAlertmanagerConfigurationWithCallback(ctx, cmd, func() error { return nil })
@@ -74,6 +159,8 @@ func (st DBstore) SaveAlertmanagerConfigurationWithCallback(ctx context.Context,
 			ConfigurationVersion:      cmd.ConfigurationVersion,
 			Default:                   cmd.Default,
 			OrgID:                     cmd.OrgID,
+			Origin:                    cmd.Origin,
+			CreatedBy:                 cmd.CreatedBy,
 		}
 		if _, err := sess.Insert(config); err != nil {
 			return err
@@ -95,6 +182,8 @@ func (st *DBstore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *mod
 			ConfigurationVersion:      cmd.ConfigurationVersion,
 			Default:                   cmd.Default,
 			OrgID:                     cmd.OrgID,
+			Origin:                    cmd.Origin,
+			CreatedBy:                 cmd.CreatedBy,
 			CreatedAt:                 time.Now().Unix(),
 		}
 		res, err := sess.Exec(fmt.Sprintf(getInsertQuery(st.SQLStore.Dialect.DriverName()), st.SQLStore.Dialect.Quote("default")),
@@ -104,6 +193,8 @@ func (st *DBstore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *mod
 			config.OrgID,
 			config.CreatedAt,
 			st.SQLStore.Dialect.BooleanStr(config.Default),
+			config.Origin,
+			config.CreatedBy,
 			cmd.OrgID,
 			cmd.OrgID,
 			cmd.FetchedConfigurationHash,
@@ -132,8 +223,8 @@ func getInsertQuery(driver string) string {
 	case core.MYSQL:
 		return `
 		INSERT INTO alert_configuration
-		(alertmanager_configuration, configuration_hash, configuration_version, org_id, created_at, %s) 
-		SELECT T.* FROM (SELECT ? AS alertmanager_configuration,? AS configuration_hash,? AS configuration_version,? AS org_id,? AS created_at,? AS 'default') AS T
+		(alertmanager_configuration, configuration_hash, configuration_version, org_id, created_at, %s, origin, created_by) 
+		SELECT T.* FROM (SELECT ? AS alertmanager_configuration,? AS configuration_hash,? AS configuration_version,? AS org_id,? AS created_at,? AS 'default',? AS origin,? AS created_by) AS T
 		WHERE
 		EXISTS (
 			SELECT 1 
@@ -148,24 +239,24 @@ func getInsertQuery(driver string) string {
 	case core.POSTGRES:
 		return `
 		INSERT INTO alert_configuration
-		(alertmanager_configuration, configuration_hash, configuration_version, org_id, created_at, %s) 
-		SELECT T.* FROM (VALUES($1,$2,$3,$4::bigint,$5::integer,$6::boolean)) AS T
+		(alertmanager_configuration, configuration_hash, configuration_version, org_id, created_at, %s, origin, created_by) 
+		SELECT T.* FROM (VALUES($1,$2,$3,$4::bigint,$5::integer,$6::boolean,$7,$8)) AS T
 		WHERE
 		EXISTS (
 			SELECT 1 
 			FROM alert_configuration 
 			WHERE 
-				org_id = $7 
+				org_id = $9 
 			AND 
-				id = (SELECT MAX(id) FROM alert_configuration WHERE org_id = $8::bigint) 
+				id = (SELECT MAX(id) FROM alert_configuration WHERE org_id = $10::bigint) 
 			AND 
-				configuration_hash = $9
+				configuration_hash = $11
 		)`
 	case core.SQLITE:
 		return `
 		INSERT INTO alert_configuration
-		(alertmanager_configuration, configuration_hash, configuration_version, org_id, created_at, %s) 
-		SELECT T.* FROM (VALUES(?,?,?,?,?,?)) AS T
+		(alertmanager_configuration, configuration_hash, configuration_version, org_id, created_at, %s, origin, created_by) 
+		SELECT T.* FROM (VALUES(?,?,?,?,?,?,?,?)) AS T
 		WHERE
 		EXISTS (
 			SELECT 1 
@@ -181,8 +272,8 @@ func getInsertQuery(driver string) string {
 		// SQLite version
 		return `
 		INSERT INTO alert_configuration
-		(alertmanager_configuration, configuration_hash, configuration_version, org_id, created_at, %s) 
-		SELECT T.* FROM (VALUES(?,?,?,?,?,?)) AS T
+		(alertmanager_configuration, configuration_hash, configuration_version, org_id, created_at, %s, origin, created_by) 
+		SELECT T.* FROM (VALUES(?,?,?,?,?,?,?,?)) AS T
 		WHERE
 		EXISTS (
 			SELECT 1 
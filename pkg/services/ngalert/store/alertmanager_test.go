@@ -5,6 +5,7 @@ import (
 	"crypto/md5"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -55,12 +56,14 @@ func TestIntegrationAlertManagerHash(t *testing.T) {
 			ConfigurationVersion:      "v1",
 			Default:                   false,
 			OrgID:                     1,
+			Origin:                    models.AlertConfigurationOriginProvisioning,
 		})
 		require.NoError(t, err)
 		err = store.GetLatestAlertmanagerConfiguration(context.Background(), req)
 		require.NoError(t, err)
 		require.Equal(t, newConfig, req.Result.AlertmanagerConfiguration)
 		require.Equal(t, newConfigMD5, req.Result.ConfigurationHash)
+		require.Equal(t, models.AlertConfigurationOriginProvisioning, req.Result.Origin)
 	})
 
 	t.Run("When passing the wrong hash the update should error", func(t *testing.T) {
@@ -82,3 +85,122 @@ func TestIntegrationAlertManagerHash(t *testing.T) {
 		require.EqualError(t, ErrVersionLockedObjectNotFound, err.Error())
 	})
 }
+
+func TestIntegrationAlertmanagerConfigurationVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := sqlstore.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+	}
+	for _, c := range []struct {
+		config string
+		orgID  int64
+	}{{"config-1", 1}, {"config-2", 1}, {"other-org", 2}} {
+		err := store.SaveAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: c.config,
+			ConfigurationVersion:      "v1",
+			OrgID:                     c.orgID,
+			Origin:                    models.AlertConfigurationOriginAPI,
+			CreatedBy:                 "admin",
+		})
+		require.NoError(t, err)
+	}
+
+	versions, err := store.GetAlertmanagerConfigurationVersions(context.Background(), 1, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Greater(t, versions[0].ID, versions[1].ID)
+	require.Empty(t, versions[0].AlertmanagerConfiguration)
+	require.Equal(t, fmt.Sprintf("%x", md5.Sum([]byte("config-2"))), versions[0].ConfigurationHash)
+	require.Equal(t, models.AlertConfigurationOriginAPI, versions[0].Origin)
+	require.Equal(t, "admin", versions[0].CreatedBy)
+
+	page, err := store.GetAlertmanagerConfigurationVersions(context.Background(), 1, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, versions[1].ID, page[0].ID)
+
+	version, err := store.GetAlertmanagerConfigurationVersion(context.Background(), 1, versions[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, "config-1", version.AlertmanagerConfiguration)
+
+	other, err := store.GetAlertmanagerConfigurationVersions(context.Background(), 2, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	_, err = store.GetAlertmanagerConfigurationVersion(context.Background(), 1, other[0].ID)
+	require.ErrorIs(t, err, ErrNoAlertmanagerConfiguration)
+}
+
+func TestIntegrationDeleteOldAlertmanagerConfigurations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	setup := func(t *testing.T) *DBstore {
+		store := &DBstore{SQLStore: sqlstore.InitTestDB(t)}
+		for i := 1; i <= 5; i++ {
+			err := store.SaveAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{
+				AlertmanagerConfiguration: fmt.Sprintf("config-%d", i),
+				ConfigurationVersion:      "v1",
+				OrgID:                     1,
+			})
+			require.NoError(t, err)
+		}
+		err := store.SaveAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: "other-org",
+			ConfigurationVersion:      "v1",
+			OrgID:                     2,
+		})
+		require.NoError(t, err)
+		// all versions of the organization are a day old
+		err = store.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+			_, err := sess.Exec("UPDATE alert_configuration SET created_at = ? WHERE org_id = 1", time.Now().Add(-24*time.Hour).Unix())
+			return err
+		})
+		require.NoError(t, err)
+		return store
+	}
+	configs := func(t *testing.T, store *DBstore, orgID int64) []string {
+		versions, err := store.GetAlertmanagerConfigurationVersions(context.Background(), orgID, 0, 0)
+		require.NoError(t, err)
+		result := make([]string, 0, len(versions))
+		for _, v := range versions {
+			version, err := store.GetAlertmanagerConfigurationVersion(context.Background(), orgID, v.ID)
+			require.NoError(t, err)
+			result = append(result, version.AlertmanagerConfiguration)
+		}
+		return result
+	}
+
+	t.Run("should keep the newest versions", func(t *testing.T) {
+		store := setup(t)
+
+		deleted, err := store.DeleteOldAlertmanagerConfigurations(context.Background(), 1, 2, time.Time{})
+
+		require.NoError(t, err)
+		require.Equal(t, int64(3), deleted)
+		require.Equal(t, []string{"config-5", "config-4"}, configs(t, store, 1))
+		require.Equal(t, []string{"other-org"}, configs(t, store, 2))
+	})
+
+	t.Run("should keep the latest version regardless of its age", func(t *testing.T) {
+		store := setup(t)
+
+		deleted, err := store.DeleteOldAlertmanagerConfigurations(context.Background(), 1, 0, time.Now().Add(-time.Hour))
+
+		require.NoError(t, err)
+		require.Equal(t, int64(4), deleted)
+		require.Equal(t, []string{"config-5"}, configs(t, store, 1))
+	})
+
+	t.Run("should keep all versions without limits", func(t *testing.T) {
+		store := setup(t)
+
+		deleted, err := store.DeleteOldAlertmanagerConfigurations(context.Background(), 1, 0, time.Time{})
+
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+		require.Len(t, configs(t, store, 1), 5)
+	})
+}
@@ -21,6 +21,9 @@ const AlertDefinitionMaxTitleLength = 190
 type AlertingStore interface {
 	GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error
 	GetAllLatestAlertmanagerConfiguration(ctx context.Context) ([]*models.AlertConfiguration, error)
+	GetAlertmanagerConfigurationVersions(ctx context.Context, orgID int64, limit, offset int) ([]*models.AlertConfiguration, error)
+	GetAlertmanagerConfigurationVersion(ctx context.Context, orgID int64, id int64) (*models.AlertConfiguration, error)
+	DeleteOldAlertmanagerConfigurations(ctx context.Context, orgID int64, maxVersions int, olderThan time.Time) (int64, error)
 	SaveAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error
 	SaveAlertmanagerConfigurationWithCallback(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd, callback SaveCallback) error
 	UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// DeferredNotificationStore is the queue of the notifications held back during the quiet hours of receivers.
+type DeferredNotificationStore interface {
+	// DeferNotifications adds the notifications to the queue of a receiver. A notification that is
+	// already queued for the same alert is replaced, the time it was first queued is kept.
+	DeferNotifications(ctx context.Context, orgID int64, receiver string, notifications []models.DeferredNotification) error
+
+	// GetDeferredNotifications returns the queued notifications of all receivers of an organization, oldest first.
+	GetDeferredNotifications(ctx context.Context, orgID int64) ([]models.DeferredNotification, error)
+
+	// DeleteDeferredNotifications removes the notifications from the queue.
+	DeleteDeferredNotifications(ctx context.Context, orgID int64, ids []int64) error
+}
+
+func (st DBstore) DeferNotifications(ctx context.Context, orgID int64, receiver string, notifications []models.DeferredNotification) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		now := TimeNow().Unix()
+		for _, n := range notifications {
+			existing := models.DeferredNotification{}
+			found, err := sess.Where("org_id = ? AND receiver = ? AND fingerprint = ?", orgID, receiver, n.Fingerprint).Get(&existing)
+			if err != nil {
+				return fmt.Errorf("failed to get deferred notification: %w", err)
+			}
+			if found {
+				existing.Alert = n.Alert
+				existing.UpdatedAt = now
+				if _, err := sess.ID(existing.ID).Cols("alert", "updated_at").Update(&existing); err != nil {
+					return fmt.Errorf("failed to update deferred notification: %w", err)
+				}
+				continue
+			}
+
+			n.ID = 0
+			n.OrgID = orgID
+			n.Receiver = receiver
+			n.CreatedAt = now
+			n.UpdatedAt = now
+			if _, err := sess.Insert(&n); err != nil {
+				return fmt.Errorf("failed to save deferred notification: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetDeferredNotifications(ctx context.Context, orgID int64) ([]models.DeferredNotification, error) {
+	var notifications []models.DeferredNotification
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ?", orgID).Asc("created_at", "id").Find(&notifications)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (st DBstore) DeleteDeferredNotifications(ctx context.Context, orgID int64, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Where("org_id = ?", orgID).In("id", ids).Delete(&models.DeferredNotification{})
+		return err
+	})
+}
@@ -0,0 +1,56 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationDeferredNotifications(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	t.Run("notifications are queued per receiver and replaced per alert", func(t *testing.T) {
+		err := dbstore.DeferNotifications(ctx, 1, "team-a", []models.DeferredNotification{
+			{Fingerprint: "a", Alert: `{"v":1}`},
+			{Fingerprint: "b", Alert: `{"v":1}`},
+		})
+		require.NoError(t, err)
+		err = dbstore.DeferNotifications(ctx, 1, "team-b", []models.DeferredNotification{{Fingerprint: "a", Alert: `{"v":1}`}})
+		require.NoError(t, err)
+		err = dbstore.DeferNotifications(ctx, 1, "team-a", []models.DeferredNotification{{Fingerprint: "a", Alert: `{"v":2}`}})
+		require.NoError(t, err)
+
+		queued, err := dbstore.GetDeferredNotifications(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, queued, 3)
+		require.Equal(t, "team-a", queued[0].Receiver)
+		require.Equal(t, "a", queued[0].Fingerprint)
+		require.Equal(t, `{"v":2}`, queued[0].Alert)
+		require.Equal(t, "team-a", queued[1].Receiver)
+		require.Equal(t, "b", queued[1].Fingerprint)
+		require.Equal(t, "team-b", queued[2].Receiver)
+
+		other, err := dbstore.GetDeferredNotifications(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, other)
+	})
+
+	t.Run("deleted notifications are removed from the queue", func(t *testing.T) {
+		queued, err := dbstore.GetDeferredNotifications(ctx, 1)
+		require.NoError(t, err)
+		require.NoError(t, dbstore.DeleteDeferredNotifications(ctx, 1, []int64{queued[0].ID, queued[2].ID}))
+
+		queued, err = dbstore.GetDeferredNotifications(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, queued, 1)
+		require.Equal(t, "b", queued[0].Fingerprint)
+	})
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// NotificationPolicyHistoryLimit is the number of previous versions of the notification policy tree kept per organization.
+const NotificationPolicyHistoryLimit = 50
+
+// SaveNotificationPolicyVersion stores a previous version of the notification policy tree. The version number is
+// assigned by the store. Versions that exceed NotificationPolicyHistoryLimit are removed, oldest first.
+func (st DBstore) SaveNotificationPolicyVersion(ctx context.Context, v *models.NotificationPolicyVersion) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		latest := models.NotificationPolicyVersion{}
+		if _, err := sess.Where("org_id = ?", v.OrgID).Desc("version").Cols("version").Get(&latest); err != nil {
+			return fmt.Errorf("failed to get latest notification policy version: %w", err)
+		}
+
+		v.ID = 0
+		v.Version = latest.Version + 1
+		v.CreatedAt = TimeNow().Unix()
+		if _, err := sess.Insert(v); err != nil {
+			return fmt.Errorf("failed to save notification policy version: %w", err)
+		}
+
+		_, err := sess.Where("org_id = ? AND version <= ?", v.OrgID, v.Version-NotificationPolicyHistoryLimit).Delete(&models.NotificationPolicyVersion{})
+		if err != nil {
+			return fmt.Errorf("failed to delete old notification policy versions: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetNotificationPolicyVersions returns the previous versions of the notification policy tree of an organization,
+// newest first. The policy trees themselves are not loaded.
+func (st DBstore) GetNotificationPolicyVersions(ctx context.Context, orgID int64) ([]models.NotificationPolicyVersion, error) {
+	var versions []models.NotificationPolicyVersion
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ?", orgID).Desc("version").Cols("id", "org_id", "version", "created_at").Find(&versions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetNotificationPolicyVersion returns a single previous version of the notification policy tree,
+// or ErrNotificationPolicyVersionNotFound.
+func (st DBstore) GetNotificationPolicyVersion(ctx context.Context, orgID int64, version int64) (*models.NotificationPolicyVersion, error) {
+	var v models.NotificationPolicyVersion
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		exists, err := sess.Where("org_id = ? AND version = ?", orgID, version).Get(&v)
+		if err != nil {
+			return fmt.Errorf("failed to get notification policy version: %w", err)
+		}
+		if !exists {
+			return models.ErrNotificationPolicyVersionNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
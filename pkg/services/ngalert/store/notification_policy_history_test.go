@@ -0,0 +1,61 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationPolicyHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	t.Run("versions are numbered per organization", func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			v := models.NotificationPolicyVersion{OrgID: 1, PolicyTree: fmt.Sprintf(`{"receiver":"r%d"}`, i)}
+			require.NoError(t, dbstore.SaveNotificationPolicyVersion(ctx, &v))
+			require.Equal(t, int64(i), v.Version)
+		}
+		other := models.NotificationPolicyVersion{OrgID: 2, PolicyTree: `{"receiver":"other"}`}
+		require.NoError(t, dbstore.SaveNotificationPolicyVersion(ctx, &other))
+		require.Equal(t, int64(1), other.Version)
+
+		versions, err := dbstore.GetNotificationPolicyVersions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, versions, 3)
+		require.Equal(t, int64(3), versions[0].Version)
+		require.Empty(t, versions[0].PolicyTree)
+
+		v, err := dbstore.GetNotificationPolicyVersion(ctx, 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, `{"receiver":"r2"}`, v.PolicyTree)
+	})
+
+	t.Run("unknown version returns ErrNotificationPolicyVersionNotFound", func(t *testing.T) {
+		_, err := dbstore.GetNotificationPolicyVersion(ctx, 1, 100)
+		require.ErrorIs(t, err, models.ErrNotificationPolicyVersionNotFound)
+	})
+
+	t.Run("old versions are removed", func(t *testing.T) {
+		for i := 0; i < store.NotificationPolicyHistoryLimit; i++ {
+			v := models.NotificationPolicyVersion{OrgID: 3, PolicyTree: `{}`}
+			require.NoError(t, dbstore.SaveNotificationPolicyVersion(ctx, &v))
+		}
+		v := models.NotificationPolicyVersion{OrgID: 3, PolicyTree: `{}`}
+		require.NoError(t, dbstore.SaveNotificationPolicyVersion(ctx, &v))
+
+		versions, err := dbstore.GetNotificationPolicyVersions(ctx, 3)
+		require.NoError(t, err)
+		require.Len(t, versions, store.NotificationPolicyHistoryLimit)
+		require.Equal(t, int64(2), versions[len(versions)-1].Version)
+	})
+}
@@ -44,7 +44,8 @@ func (st DBstore) GetProvenance(ctx context.Context, o models.Provisionable, org
 	return provenance, nil
 }
 
-// GetProvenance gets the provenance status for a provisionable object.
+// GetProvenances gets the provenance status of all the provisionable objects of a type in an organization, keyed
+// by their resource ID, with a single query.
 func (st DBstore) GetProvenances(ctx context.Context, org int64, resourceType string) (map[string]models.Provenance, error) {
 	resultMap := make(map[string]models.Provenance)
 	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/grafana/grafana/pkg/services/ngalert"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -104,6 +105,29 @@ func TestProvisioningStore(t *testing.T) {
 		require.Equal(t, models.ProvenanceAPI, p[rule2.UID])
 	})
 
+	t.Run("Store should only return provenances of the given type and org ID", func(t *testing.T) {
+		const orgID = 125
+		rule := models.AlertRule{
+			UID:   "791",
+			OrgID: orgID,
+		}
+		otherOrgRule := models.AlertRule{
+			UID:   "792",
+			OrgID: orgID + 1,
+		}
+		tmpl := definitions.MessageTemplate{Name: "791"}
+		err := store.SetProvenance(context.Background(), &rule, orgID, models.ProvenanceFile)
+		require.NoError(t, err)
+		err = store.SetProvenance(context.Background(), &otherOrgRule, otherOrgRule.OrgID, models.ProvenanceAPI)
+		require.NoError(t, err)
+		err = store.SetProvenance(context.Background(), &tmpl, orgID, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		p, err := store.GetProvenances(context.Background(), orgID, rule.ResourceType())
+		require.NoError(t, err)
+		require.Equal(t, map[string]models.Provenance{rule.UID: models.ProvenanceFile}, p)
+	})
+
 	t.Run("Store should delete provenance correctly", func(t *testing.T) {
 		const orgID = 1234
 		ruleOrg := models.AlertRule{
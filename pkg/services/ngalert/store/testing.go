@@ -193,6 +193,9 @@ func (f *FakeRuleStore) GetAlertRulesForScheduling(_ context.Context, q *models.
 	}
 	for _, rules := range f.Rules {
 		for _, rule := range rules {
+			if rule.IsPaused {
+				continue
+			}
 			q.Result = append(q.Result, &models.SchedulableAlertRule{
 				UID:             rule.UID,
 				OrgID:           rule.OrgID,
@@ -64,7 +64,7 @@ func SetupTestEnv(t *testing.T, baseInterval time.Duration) (*ngalert.AlertNG, *
 
 	ng, err := ngalert.ProvideService(
 		cfg, nil, routing.NewRouteRegister(), sqlStore, nil, nil, nil, nil,
-		secretsService, nil, m, folderService, ac, &dashboards.FakeDashboardService{}, nil, bus,
+		secretsService, nil, m, folderService, ac, &dashboards.FakeDashboardService{}, nil, bus, nil, folderPermissions,
 	)
 	require.NoError(t, err)
 	return ng, &store.DBstore{
@@ -58,7 +58,12 @@ func (ns *NotificationService) buildEmailMessage(cmd *models.SendEmailCommand) (
 	if !ns.Cfg.Smtp.Enabled {
 		return nil, models.ErrSmtpNotEnabled
 	}
+	return ns.RenderEmailCommand(cmd)
+}
 
+// RenderEmailCommand renders the email of the command without sending it. Unlike sending, rendering does not
+// require SMTP to be enabled.
+func (ns *NotificationService) RenderEmailCommand(cmd *models.SendEmailCommand) (*Message, error) {
 	data := cmd.Data
 	if data == nil {
 		data = make(map[string]interface{}, 10)
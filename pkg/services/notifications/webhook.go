@@ -3,14 +3,13 @@ package notifications
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net"
 	"net/http"
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -24,20 +23,10 @@ type Webhook struct {
 	ContentType string
 }
 
-var netTransport = &http.Transport{
-	TLSClientConfig: &tls.Config{
-		Renegotiation: tls.RenegotiateFreelyAsClient,
-	},
-	Proxy: http.ProxyFromEnvironment,
-	Dial: (&net.Dialer{
-		Timeout: 30 * time.Second,
-	}).Dial,
-	TLSHandshakeTimeout: 5 * time.Second,
-}
-var netClient = &http.Client{
-	Timeout:   time.Second * 30,
-	Transport: netTransport,
-}
+var netClient = httpclient.NewOutboundClient(httpclient.OutboundOptions{
+	Name:    "notifications",
+	Timeout: 30 * time.Second,
+})
 
 func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *Webhook) error {
 	if webhook.HttpMethod == "" {
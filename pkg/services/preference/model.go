@@ -7,7 +7,10 @@ import (
 	"time"
 )
 
-var ErrPrefNotFound = errors.New("preference not found")
+var (
+	ErrPrefNotFound                     = errors.New("preference not found")
+	ErrInvalidHomeDashboardTeamPriority = errors.New("team priority must not contain a team more than once")
+)
 
 type Preference struct {
 	ID              int64   `xorm:"pk autoincr 'id'"`
@@ -103,3 +106,45 @@ func (j *PreferenceJSONData) ToDB() ([]byte, error) {
 }
 
 func (p Preference) TableName() string { return "preferences" }
+
+// HomeDashboardTeamPriority is the position of a team in the order in which the home dashboards
+// of the teams of a user are considered. The team with the lowest priority is considered first.
+type HomeDashboardTeamPriority struct {
+	ID       int64 `xorm:"pk autoincr 'id'"`
+	OrgID    int64 `xorm:"org_id"`
+	TeamID   int64 `xorm:"team_id"`
+	Priority int
+}
+
+func (p HomeDashboardTeamPriority) TableName() string { return "home_dashboard_team_priority" }
+
+type SetHomeDashboardTeamPriorityCommand struct {
+	OrgID int64 `json:"-"`
+
+	// TeamIDs are the teams whose home dashboards are considered first, in order.
+	TeamIDs []int64 `json:"teamIds"`
+}
+
+type ResolveHomeDashboardQuery struct {
+	OrgID  int64
+	UserID int64
+	Teams  []int64
+}
+
+type HomeDashboardSource string
+
+const (
+	HomeDashboardSourceUser    HomeDashboardSource = "user"
+	HomeDashboardSourceTeam    HomeDashboardSource = "team"
+	HomeDashboardSourceOrg     HomeDashboardSource = "org"
+	HomeDashboardSourceDefault HomeDashboardSource = "default"
+)
+
+// HomeDashboard is the home dashboard picked for a user and where it comes from. The dashboard ID
+// is 0 if the default of the instance is used.
+type HomeDashboard struct {
+	DashboardID int64               `json:"dashboardId"`
+	Source      HomeDashboardSource `json:"source"`
+	// TeamID is the team whose home dashboard is used, if the source is a team.
+	TeamID int64 `json:"teamId,omitempty"`
+}
@@ -10,4 +10,10 @@ type Service interface {
 	Save(context.Context, *SavePreferenceCommand) error
 	Patch(context.Context, *PatchPreferenceCommand) error
 	GetDefaults() *Preference
+	// ResolveHomeDashboard picks the home dashboard of a user. The home dashboard of the user comes first,
+	// then the ones of their teams, in the order set with SetHomeDashboardTeamPriority, then the one of
+	// the organization.
+	ResolveHomeDashboard(context.Context, *ResolveHomeDashboardQuery) (*HomeDashboard, error)
+	GetHomeDashboardTeamPriority(ctx context.Context, orgID int64) ([]int64, error)
+	SetHomeDashboardTeamPriority(context.Context, *SetHomeDashboardTeamPriorityCommand) error
 }
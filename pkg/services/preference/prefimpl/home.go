@@ -0,0 +1,82 @@
+package prefimpl
+
+import (
+	"context"
+	"sort"
+
+	pref "github.com/grafana/grafana/pkg/services/preference"
+)
+
+func (s *Service) ResolveHomeDashboard(ctx context.Context, query *pref.ResolveHomeDashboardQuery) (*pref.HomeDashboard, error) {
+	prefs, err := s.store.List(ctx, &pref.Preference{
+		Teams:  query.Teams,
+		OrgID:  query.OrgID,
+		UserID: query.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var orgHome, userHome int64
+	teamHomes := make(map[int64]int64)
+	for _, p := range prefs {
+		if p.HomeDashboardID == 0 {
+			continue
+		}
+		switch {
+		case p.UserID != 0:
+			userHome = p.HomeDashboardID
+		case p.TeamID != 0:
+			teamHomes[p.TeamID] = p.HomeDashboardID
+		default:
+			orgHome = p.HomeDashboardID
+		}
+	}
+
+	if userHome != 0 {
+		return &pref.HomeDashboard{DashboardID: userHome, Source: pref.HomeDashboardSourceUser}, nil
+	}
+	if len(teamHomes) > 0 {
+		priority, err := s.store.GetHomeDashboardTeamPriority(ctx, query.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		teamID := pickTeam(teamHomes, priority)
+		return &pref.HomeDashboard{DashboardID: teamHomes[teamID], Source: pref.HomeDashboardSourceTeam, TeamID: teamID}, nil
+	}
+	if orgHome != 0 {
+		return &pref.HomeDashboard{DashboardID: orgHome, Source: pref.HomeDashboardSourceOrg}, nil
+	}
+	return &pref.HomeDashboard{Source: pref.HomeDashboardSourceDefault}, nil
+}
+
+// pickTeam returns the first team of the priority order that has a home dashboard. If none of them
+// has one, the team with the lowest ID is returned.
+func pickTeam(teamHomes map[int64]int64, priority []int64) int64 {
+	for _, teamID := range priority {
+		if _, ok := teamHomes[teamID]; ok {
+			return teamID
+		}
+	}
+	teamIDs := make([]int64, 0, len(teamHomes))
+	for teamID := range teamHomes {
+		teamIDs = append(teamIDs, teamID)
+	}
+	sort.Slice(teamIDs, func(i, j int) bool { return teamIDs[i] < teamIDs[j] })
+	return teamIDs[0]
+}
+
+func (s *Service) GetHomeDashboardTeamPriority(ctx context.Context, orgID int64) ([]int64, error) {
+	return s.store.GetHomeDashboardTeamPriority(ctx, orgID)
+}
+
+func (s *Service) SetHomeDashboardTeamPriority(ctx context.Context, cmd *pref.SetHomeDashboardTeamPriorityCommand) error {
+	seen := make(map[int64]struct{}, len(cmd.TeamIDs))
+	for _, teamID := range cmd.TeamIDs {
+		if _, ok := seen[teamID]; ok {
+			return pref.ErrInvalidHomeDashboardTeamPriority
+		}
+		seen[teamID] = struct{}{}
+	}
+	return s.store.SetHomeDashboardTeamPriority(ctx, cmd.OrgID, cmd.TeamIDs)
+}
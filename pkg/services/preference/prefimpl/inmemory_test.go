@@ -24,9 +24,10 @@ type preferenceKey struct {
 // inmemStore implements an implementation of store appropriate for
 // testing without needing a full SQL database.
 type inmemStore struct {
-	preference map[preferenceKey]pref.Preference
-	idMap      map[int64]preferenceKey
-	nextID     int64
+	preference   map[preferenceKey]pref.Preference
+	idMap        map[int64]preferenceKey
+	nextID       int64
+	teamPriority map[int64][]int64
 }
 
 func (s *inmemStore) Get(ctx context.Context, preference *pref.Preference) (*pref.Preference, error) {
@@ -120,3 +121,12 @@ func (s *inmemStore) Update(ctx context.Context, preference *pref.Preference) er
 	s.preference[key] = *preference
 	return nil
 }
+
+func (s *inmemStore) GetHomeDashboardTeamPriority(ctx context.Context, orgID int64) ([]int64, error) {
+	return append([]int64{}, s.teamPriority[orgID]...), nil
+}
+
+func (s *inmemStore) SetHomeDashboardTeamPriority(ctx context.Context, orgID int64, teamIDs []int64) error {
+	s.teamPriority[orgID] = append([]int64{}, teamIDs...)
+	return nil
+}
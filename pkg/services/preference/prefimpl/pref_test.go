@@ -456,8 +456,64 @@ func insertPrefs(t testing.TB, store store, preferences ...pref.Preference) {
 
 func newFake() store {
 	return &inmemStore{
-		preference: map[preferenceKey]pref.Preference{},
-		idMap:      map[int64]preferenceKey{},
-		nextID:     1,
+		preference:   map[preferenceKey]pref.Preference{},
+		idMap:        map[int64]preferenceKey{},
+		nextID:       1,
+		teamPriority: map[int64][]int64{},
 	}
 }
+
+func TestResolveHomeDashboard(t *testing.T) {
+	prefService := &Service{
+		store:    newFake(),
+		cfg:      setting.NewCfg(),
+		features: featuremgmt.WithFeatures(),
+	}
+	insertPrefs(t, prefService.store,
+		pref.Preference{OrgID: 1, HomeDashboardID: 1},
+		pref.Preference{OrgID: 1, TeamID: 2, HomeDashboardID: 2},
+		pref.Preference{OrgID: 1, TeamID: 3, HomeDashboardID: 3},
+		pref.Preference{OrgID: 1, TeamID: 4, Theme: "dark"},
+		pref.Preference{OrgID: 1, UserID: 5, HomeDashboardID: 5},
+		pref.Preference{OrgID: 2, Theme: "dark"},
+	)
+	resolve := func(t *testing.T, query *pref.ResolveHomeDashboardQuery) *pref.HomeDashboard {
+		t.Helper()
+		home, err := prefService.ResolveHomeDashboard(context.Background(), query)
+		require.NoError(t, err)
+		return home
+	}
+
+	t.Run("the home dashboard of the user comes first", func(t *testing.T) {
+		home := resolve(t, &pref.ResolveHomeDashboardQuery{OrgID: 1, UserID: 5, Teams: []int64{2, 3}})
+		require.Equal(t, &pref.HomeDashboard{DashboardID: 5, Source: pref.HomeDashboardSourceUser}, home)
+	})
+
+	t.Run("without priority the team with the lowest ID is used", func(t *testing.T) {
+		home := resolve(t, &pref.ResolveHomeDashboardQuery{OrgID: 1, UserID: 6, Teams: []int64{3, 2}})
+		require.Equal(t, &pref.HomeDashboard{DashboardID: 2, Source: pref.HomeDashboardSourceTeam, TeamID: 2}, home)
+	})
+
+	t.Run("the team priority is used", func(t *testing.T) {
+		err := prefService.SetHomeDashboardTeamPriority(context.Background(), &pref.SetHomeDashboardTeamPriorityCommand{OrgID: 1, TeamIDs: []int64{4, 3, 2}})
+		require.NoError(t, err)
+
+		home := resolve(t, &pref.ResolveHomeDashboardQuery{OrgID: 1, UserID: 6, Teams: []int64{2, 3, 4}})
+		require.Equal(t, &pref.HomeDashboard{DashboardID: 3, Source: pref.HomeDashboardSourceTeam, TeamID: 3}, home)
+	})
+
+	t.Run("teams without a home dashboard fall back to the organization", func(t *testing.T) {
+		home := resolve(t, &pref.ResolveHomeDashboardQuery{OrgID: 1, UserID: 6, Teams: []int64{4}})
+		require.Equal(t, &pref.HomeDashboard{DashboardID: 1, Source: pref.HomeDashboardSourceOrg}, home)
+	})
+
+	t.Run("without any home dashboard the default is used", func(t *testing.T) {
+		home := resolve(t, &pref.ResolveHomeDashboardQuery{OrgID: 2, UserID: 6})
+		require.Equal(t, &pref.HomeDashboard{Source: pref.HomeDashboardSourceDefault}, home)
+	})
+
+	t.Run("a team cannot be in the priority more than once", func(t *testing.T) {
+		err := prefService.SetHomeDashboardTeamPriority(context.Background(), &pref.SetHomeDashboardTeamPriorityCommand{OrgID: 1, TeamIDs: []int64{2, 2}})
+		require.ErrorIs(t, err, pref.ErrInvalidHomeDashboardTeamPriority)
+	})
+}
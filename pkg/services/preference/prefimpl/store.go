@@ -14,6 +14,8 @@ type store interface {
 	List(context.Context, *pref.Preference) ([]*pref.Preference, error)
 	Insert(context.Context, *pref.Preference) (int64, error)
 	Update(context.Context, *pref.Preference) error
+	GetHomeDashboardTeamPriority(ctx context.Context, orgID int64) ([]int64, error)
+	SetHomeDashboardTeamPriority(ctx context.Context, orgID int64, teamIDs []int64) error
 }
 
 type sqlStore struct {
@@ -86,3 +88,32 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *pref.Preference) (int64, err
 	})
 	return ID, err
 }
+
+func (s *sqlStore) GetHomeDashboardTeamPriority(ctx context.Context, orgID int64) ([]int64, error) {
+	priorities := make([]*pref.HomeDashboardTeamPriority, 0)
+	err := s.db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id=?", orgID).OrderBy("priority ASC").Find(&priorities)
+	})
+	if err != nil {
+		return nil, err
+	}
+	teamIDs := make([]int64, 0, len(priorities))
+	for _, p := range priorities {
+		teamIDs = append(teamIDs, p.TeamID)
+	}
+	return teamIDs, nil
+}
+
+func (s *sqlStore) SetHomeDashboardTeamPriority(ctx context.Context, orgID int64, teamIDs []int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Exec("DELETE FROM home_dashboard_team_priority WHERE org_id=?", orgID); err != nil {
+			return err
+		}
+		for i, teamID := range teamIDs {
+			if _, err := sess.Insert(&pref.HomeDashboardTeamPriority{OrgID: orgID, TeamID: teamID, Priority: i}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -167,4 +167,17 @@ func TestIntegrationPreferencesDataAccess(t *testing.T) {
 			})
 		require.NoError(t, err)
 	})
+	t.Run("home dashboard team priority replaces the previous one", func(t *testing.T) {
+		teamIDs, err := prefStore.GetHomeDashboardTeamPriority(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, teamIDs)
+
+		require.NoError(t, prefStore.SetHomeDashboardTeamPriority(context.Background(), 1, []int64{3, 1, 2}))
+		require.NoError(t, prefStore.SetHomeDashboardTeamPriority(context.Background(), 2, []int64{1}))
+		require.NoError(t, prefStore.SetHomeDashboardTeamPriority(context.Background(), 1, []int64{2, 3}))
+
+		teamIDs, err = prefStore.GetHomeDashboardTeamPriority(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, []int64{2, 3}, teamIDs)
+	})
 }
@@ -7,8 +7,10 @@ import (
 )
 
 type FakePreferenceService struct {
-	ExpectedPreference *pref.Preference
-	ExpectedError      error
+	ExpectedPreference    *pref.Preference
+	ExpectedHomeDashboard *pref.HomeDashboard
+	ExpectedTeamPriority  []int64
+	ExpectedError         error
 }
 
 func NewPreferenceServiceFake() *FakePreferenceService {
@@ -34,3 +36,18 @@ func (f *FakePreferenceService) GetDefaults() *pref.Preference {
 func (f *FakePreferenceService) Patch(ctx context.Context, cmd *pref.PatchPreferenceCommand) error {
 	return f.ExpectedError
 }
+
+func (f *FakePreferenceService) ResolveHomeDashboard(ctx context.Context, query *pref.ResolveHomeDashboardQuery) (*pref.HomeDashboard, error) {
+	if f.ExpectedHomeDashboard == nil {
+		return &pref.HomeDashboard{Source: pref.HomeDashboardSourceDefault}, f.ExpectedError
+	}
+	return f.ExpectedHomeDashboard, f.ExpectedError
+}
+
+func (f *FakePreferenceService) GetHomeDashboardTeamPriority(ctx context.Context, orgID int64) ([]int64, error) {
+	return f.ExpectedTeamPriority, f.ExpectedError
+}
+
+func (f *FakePreferenceService) SetHomeDashboardTeamPriority(ctx context.Context, cmd *pref.SetHomeDashboardTeamPriorityCommand) error {
+	return f.ExpectedError
+}
@@ -84,11 +84,13 @@ func (provider *Provisioner) Provision(ctx context.Context) error {
 
 // CleanUpOrphanedDashboards deletes provisioned dashboards missing a linked reader.
 func (provider *Provisioner) CleanUpOrphanedDashboards(ctx context.Context) {
-	currentReaders := make([]string, len(provider.fileReaders))
+	currentReaders := make([]string, len(provider.fileReaders), len(provider.fileReaders)+1)
 
 	for index, reader := range provider.fileReaders {
 		currentReaders[index] = reader.Cfg.Name
 	}
+	// the dashboards imported from jsonnet bundles are not provisioned from files
+	currentReaders = append(currentReaders, dashboards.JsonnetProvisionerName)
 
 	if err := provider.provisioner.DeleteOrphanedProvisionedDashboards(ctx, &models.DeleteOrphanedProvisionedDashboardsCommand{ReaderNames: currentReaders}); err != nil {
 		provider.log.Warn("Failed to delete orphaned provisioned dashboards", "err", err)
@@ -132,6 +134,9 @@ func getFileReaders(
 	var readers []*FileReader
 
 	for _, config := range configs {
+		if config.Name == dashboards.JsonnetProvisionerName {
+			return nil, fmt.Errorf("the name %q is reserved for the dashboards imported from jsonnet bundles", config.Name)
+		}
 		switch config.Type {
 		case "file":
 			fileReader, err := NewDashboardFileReader(config, logger.New("type", config.Type, "name", config.Name), service, store)
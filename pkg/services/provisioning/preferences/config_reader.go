@@ -0,0 +1,136 @@
+package preferences
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// weekStarts are the week starts that can be set in preferences, the empty week start is the default one.
+var weekStarts = map[string]bool{"": true, "saturday": true, "sunday": true, "monday": true}
+
+type configReader interface {
+	readConfig(path string) ([]*preferencesAsConfig, error)
+}
+
+type configReaderImpl struct {
+	log log.Logger
+}
+
+func newConfigReader(logger log.Logger) configReader {
+	return &configReaderImpl{log: logger}
+}
+
+func (cr *configReaderImpl) readConfig(path string) ([]*preferencesAsConfig, error) {
+	var configs []*preferencesAsConfig
+	cr.log.Debug("Looking for preferences provisioning files", "path", path)
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read preferences provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing preferences provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parsePreferencesConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	cr.log.Debug("Validating preferences")
+	checkOrgIDAndOrgName(configs)
+	if err := validatePreferences(configs); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+func (cr *configReaderImpl) parsePreferencesConfig(path string, file os.FileInfo) (*preferencesAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *preferencesAsConfigV1
+	err = yaml.Unmarshal(yamlFile, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToPreferencesFromConfig(), nil
+}
+
+// validatePreferences checks the time zones and week starts of the preferences, and that the preferences of
+// an organization are not configured more than once.
+func validatePreferences(configs []*preferencesAsConfig) error {
+	orgs := map[string]bool{}
+	for i := range configs {
+		for index, p := range configs[i].Preferences {
+			org := p.OrgName
+			if p.OrgID > 0 {
+				org = fmt.Sprintf("%d", p.OrgID)
+			}
+			if orgs[org] {
+				return fmt.Errorf("preferences of organization %q are configured more than once", org)
+			}
+			orgs[org] = true
+
+			if err := validateTimezone(p.Timezone); err != nil {
+				return fmt.Errorf("preferences item %d in configuration has an invalid timezone: %w", index+1, err)
+			}
+			if !weekStarts[p.WeekStart] {
+				return fmt.Errorf("preferences item %d in configuration has an invalid week start %q", index+1, p.WeekStart)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTimezone accepts the time zones of the time zone picker: the empty default, "browser", "utc"
+// and the names of time zone locations.
+func validateTimezone(timezone string) error {
+	switch timezone {
+	case "", "browser", "utc":
+		return nil
+	}
+	_, err := time.LoadLocation(timezone)
+	return err
+}
+
+func checkOrgIDAndOrgName(configs []*preferencesAsConfig) {
+	for i := range configs {
+		for _, p := range configs[i].Preferences {
+			if p.OrgID < 1 {
+				if p.OrgName == "" {
+					p.OrgID = 1
+				} else {
+					p.OrgID = 0
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package preferences
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	brokenYaml        = "./testdata/broken-yaml"
+	emptyFolder       = "./testdata/empty_folder"
+	invalidTimezone   = "./testdata/invalid-timezone"
+	invalidWeekStart  = "./testdata/invalid-week-start"
+	duplicateOrg      = "./testdata/duplicate-org"
+	correctProperties = "./testdata/correct-properties"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Invalid timezone should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(invalidTimezone)
+		require.ErrorContains(t, err, "preferences item 1 in configuration has an invalid timezone")
+	})
+
+	t.Run("Invalid week start should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(invalidWeekStart)
+		require.EqualError(t, err, `preferences item 1 in configuration has an invalid week start "tuesday"`)
+	})
+
+	t.Run("Preferences of an organization configured twice should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(duplicateOrg)
+		require.EqualError(t, err, `preferences of organization "1" are configured more than once`)
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		err := os.Setenv("HOME_DASHBOARD_UID", "home")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.Unsetenv("HOME_DASHBOARD_UID")
+		})
+
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+
+		require.Equal(t, []*orgPreferencesFromConfig{
+			{
+				OrgID:                2,
+				HomeDashboardUID:     "home",
+				Timezone:             "Europe/Berlin",
+				WeekStart:            "monday",
+				DefaultDatasourceUID: "prometheus",
+			},
+			{OrgName: "Org 3", Timezone: "utc"},
+			{OrgID: 1, WeekStart: "sunday"},
+		}, cfg[0].Preferences)
+	})
+}
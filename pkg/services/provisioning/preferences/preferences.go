@@ -0,0 +1,163 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type Store interface {
+	GetOrgById(ctx context.Context, query *models.GetOrgByIdQuery) error
+	GetOrgByNameHandler(ctx context.Context, query *models.GetOrgByNameQuery) error
+}
+
+type DataSourceStore interface {
+	GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) error
+	UpdateDataSource(ctx context.Context, cmd *datasources.UpdateDataSourceCommand) error
+}
+
+// Provision scans a directory for provisioning config files
+// and provisions the organization preferences in those files.
+func Provision(ctx context.Context, configDirectory string, store Store, prefs pref.Service,
+	dashboards utils.DashboardStore, dataSources DataSourceStore) error {
+	logger := log.New("provisioning.preferences")
+	pp := PreferencesProvisioner{
+		log:         logger,
+		cfgProvider: newConfigReader(logger),
+		store:       store,
+		prefs:       prefs,
+		dashboards:  dashboards,
+		dataSources: dataSources,
+	}
+	return pp.applyChanges(ctx, configDirectory)
+}
+
+// PreferencesProvisioner is responsible for provisioning the preferences of organizations based on
+// configuration read by the `configReader`. Preferences that are not configured are left as they are,
+// and nothing is saved if the configured preferences are already set.
+type PreferencesProvisioner struct {
+	log         log.Logger
+	cfgProvider configReader
+	store       Store
+	prefs       pref.Service
+	dashboards  utils.DashboardStore
+	dataSources DataSourceStore
+}
+
+func (pp *PreferencesProvisioner) apply(ctx context.Context, cfg *preferencesAsConfig) error {
+	for _, p := range cfg.Preferences {
+		if p.OrgID == 0 && p.OrgName != "" {
+			getOrgQuery := &models.GetOrgByNameQuery{Name: p.OrgName}
+			if err := pp.store.GetOrgByNameHandler(ctx, getOrgQuery); err != nil {
+				return err
+			}
+			p.OrgID = getOrgQuery.Result.Id
+		} else if err := utils.CheckOrgExists(ctx, pp.store, p.OrgID); err != nil {
+			return err
+		}
+
+		if err := pp.applyPreferences(ctx, p); err != nil {
+			return fmt.Errorf("failed to provision preferences of organization %d: %w", p.OrgID, err)
+		}
+
+		if p.DefaultDatasourceUID != "" {
+			if err := pp.applyDefaultDatasource(ctx, p.OrgID, p.DefaultDatasourceUID); err != nil {
+				return fmt.Errorf("failed to provision default data source of organization %d: %w", p.OrgID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyPreferences patches the configured preferences of the organization if they differ from the stored ones.
+func (pp *PreferencesProvisioner) applyPreferences(ctx context.Context, p *orgPreferencesFromConfig) error {
+	current, err := pp.prefs.Get(ctx, &pref.GetPreferenceQuery{OrgID: p.OrgID})
+	if err != nil {
+		if !errors.Is(err, pref.ErrPrefNotFound) {
+			return err
+		}
+		current = &pref.Preference{}
+	}
+
+	cmd := pref.PatchPreferenceCommand{OrgID: p.OrgID}
+	changed := false
+	if p.HomeDashboardUID != "" {
+		query := models.GetDashboardQuery{Uid: p.HomeDashboardUID, OrgId: p.OrgID}
+		if err := pp.dashboards.GetDashboard(ctx, &query); err != nil {
+			return fmt.Errorf("home dashboard %q: %w", p.HomeDashboardUID, err)
+		}
+		if query.Result.Id != current.HomeDashboardID {
+			cmd.HomeDashboardID = &query.Result.Id
+			changed = true
+		}
+	}
+	if p.Timezone != "" && p.Timezone != current.Timezone {
+		cmd.Timezone = &p.Timezone
+		changed = true
+	}
+	if p.WeekStart != "" && p.WeekStart != current.WeekStart {
+		cmd.WeekStart = &p.WeekStart
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	pp.log.Info("Updating organization preferences from configuration", "orgId", p.OrgID)
+	return pp.prefs.Patch(ctx, &cmd)
+}
+
+// applyDefaultDatasource makes the data source with the given UID the default one of the organization,
+// unless it already is.
+func (pp *PreferencesProvisioner) applyDefaultDatasource(ctx context.Context, orgID int64, uid string) error {
+	query := &datasources.GetDataSourceQuery{OrgId: orgID, Uid: uid}
+	if err := pp.dataSources.GetDataSource(ctx, query); err != nil {
+		return fmt.Errorf("data source %q: %w", uid, err)
+	}
+	ds := query.Result
+	if ds.IsDefault {
+		return nil
+	}
+
+	pp.log.Info("Updating default data source from configuration", "orgId", orgID, "uid", uid)
+	return pp.dataSources.UpdateDataSource(ctx, &datasources.UpdateDataSourceCommand{
+		Id:              ds.Id,
+		Uid:             ds.Uid,
+		OrgId:           ds.OrgId,
+		Name:            ds.Name,
+		Type:            ds.Type,
+		Access:          ds.Access,
+		Url:             ds.Url,
+		User:            ds.User,
+		Database:        ds.Database,
+		BasicAuth:       ds.BasicAuth,
+		BasicAuthUser:   ds.BasicAuthUser,
+		WithCredentials: ds.WithCredentials,
+		IsDefault:       true,
+		JsonData:        ds.JsonData,
+		ReadOnly:        ds.ReadOnly,
+		Version:         ds.Version,
+	})
+}
+
+func (pp *PreferencesProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := pp.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := pp.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/preference/preftest"
+)
+
+func TestPreferencesProvisioner(t *testing.T) {
+	t.Run("Should return error when config reader returns error", func(t *testing.T) {
+		expectedErr := errors.New("test")
+		reader := &testConfigReader{err: expectedErr}
+		pp := PreferencesProvisioner{log: log.New("test"), cfgProvider: reader}
+		err := pp.applyChanges(context.Background(), "")
+		require.Equal(t, expectedErr, err)
+	})
+
+	t.Run("Should apply configurations", func(t *testing.T) {
+		cfg := []*preferencesAsConfig{
+			{
+				Preferences: []*orgPreferencesFromConfig{
+					{OrgID: 2, HomeDashboardUID: "home", Timezone: "utc", WeekStart: "monday", DefaultDatasourceUID: "prometheus"},
+					{OrgName: "Org 3", Timezone: "browser"},
+				},
+			},
+		}
+		prefs := &mockPreferenceService{FakePreferenceService: preftest.NewPreferenceServiceFake()}
+		prefs.ExpectedError = pref.ErrPrefNotFound
+		dataSources := &mockDataSourceStore{dataSources: []*datasources.DataSource{
+			{Id: 5, Uid: "prometheus", OrgId: 2, Name: "Prometheus", Version: 3},
+		}}
+		pp := newTestProvisioner(cfg, prefs, dataSources)
+
+		err := pp.applyChanges(context.Background(), "")
+		require.NoError(t, err)
+
+		require.Len(t, prefs.patches, 2)
+		require.Equal(t, int64(2), prefs.patches[0].OrgID)
+		require.Equal(t, int64(10), *prefs.patches[0].HomeDashboardID)
+		require.Equal(t, "utc", *prefs.patches[0].Timezone)
+		require.Equal(t, "monday", *prefs.patches[0].WeekStart)
+		require.Equal(t, int64(3), prefs.patches[1].OrgID)
+		require.Nil(t, prefs.patches[1].HomeDashboardID)
+		require.Equal(t, "browser", *prefs.patches[1].Timezone)
+		require.Nil(t, prefs.patches[1].WeekStart)
+
+		require.Len(t, dataSources.updates, 1)
+		require.Equal(t, int64(5), dataSources.updates[0].Id)
+		require.Equal(t, "Prometheus", dataSources.updates[0].Name)
+		require.Equal(t, 3, dataSources.updates[0].Version)
+		require.True(t, dataSources.updates[0].IsDefault)
+	})
+
+	t.Run("Should not save preferences that are already set", func(t *testing.T) {
+		cfg := []*preferencesAsConfig{
+			{
+				Preferences: []*orgPreferencesFromConfig{
+					{OrgID: 2, HomeDashboardUID: "home", Timezone: "utc", WeekStart: "monday", DefaultDatasourceUID: "prometheus"},
+				},
+			},
+		}
+		prefs := &mockPreferenceService{FakePreferenceService: preftest.NewPreferenceServiceFake()}
+		prefs.ExpectedPreference = &pref.Preference{OrgID: 2, HomeDashboardID: 10, Timezone: "utc", WeekStart: "monday", Theme: "dark"}
+		dataSources := &mockDataSourceStore{dataSources: []*datasources.DataSource{
+			{Id: 5, Uid: "prometheus", OrgId: 2, IsDefault: true},
+		}}
+		pp := newTestProvisioner(cfg, prefs, dataSources)
+
+		err := pp.applyChanges(context.Background(), "")
+		require.NoError(t, err)
+		require.Empty(t, prefs.patches)
+		require.Empty(t, dataSources.updates)
+	})
+
+	t.Run("Should return error when home dashboard does not exist", func(t *testing.T) {
+		cfg := []*preferencesAsConfig{
+			{
+				Preferences: []*orgPreferencesFromConfig{
+					{OrgID: 2, HomeDashboardUID: "does-not-exist"},
+				},
+			},
+		}
+		prefs := &mockPreferenceService{FakePreferenceService: preftest.NewPreferenceServiceFake()}
+		pp := newTestProvisioner(cfg, prefs, &mockDataSourceStore{})
+
+		err := pp.applyChanges(context.Background(), "")
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+		require.Empty(t, prefs.patches)
+	})
+
+	t.Run("Should return error when default data source does not exist", func(t *testing.T) {
+		cfg := []*preferencesAsConfig{
+			{
+				Preferences: []*orgPreferencesFromConfig{
+					{OrgID: 2, DefaultDatasourceUID: "does-not-exist"},
+				},
+			},
+		}
+		prefs := &mockPreferenceService{FakePreferenceService: preftest.NewPreferenceServiceFake()}
+		pp := newTestProvisioner(cfg, prefs, &mockDataSourceStore{})
+
+		err := pp.applyChanges(context.Background(), "")
+		require.ErrorIs(t, err, datasources.ErrDataSourceNotFound)
+	})
+
+	t.Run("Should return error when organization does not exist", func(t *testing.T) {
+		cfg := []*preferencesAsConfig{
+			{
+				Preferences: []*orgPreferencesFromConfig{
+					{OrgID: 42, Timezone: "utc"},
+				},
+			},
+		}
+		prefs := &mockPreferenceService{FakePreferenceService: preftest.NewPreferenceServiceFake()}
+		pp := newTestProvisioner(cfg, prefs, &mockDataSourceStore{})
+
+		err := pp.applyChanges(context.Background(), "")
+		require.ErrorIs(t, err, models.ErrOrgNotFound)
+		require.Empty(t, prefs.patches)
+	})
+}
+
+func newTestProvisioner(cfg []*preferencesAsConfig, prefs pref.Service, dataSources DataSourceStore) PreferencesProvisioner {
+	return PreferencesProvisioner{
+		log:         log.New("test"),
+		cfgProvider: &testConfigReader{result: cfg},
+		store:       &mockStore{},
+		prefs:       prefs,
+		dashboards:  &mockStore{},
+		dataSources: dataSources,
+	}
+}
+
+type testConfigReader struct {
+	result []*preferencesAsConfig
+	err    error
+}
+
+func (tcr *testConfigReader) readConfig(_ string) ([]*preferencesAsConfig, error) {
+	return tcr.result, tcr.err
+}
+
+type mockStore struct{}
+
+func (m *mockStore) GetOrgById(_ context.Context, query *models.GetOrgByIdQuery) error {
+	if query.Id > 3 {
+		return models.ErrOrgNotFound
+	}
+	query.Result = &models.Org{Id: query.Id}
+	return nil
+}
+
+func (m *mockStore) GetOrgByNameHandler(_ context.Context, query *models.GetOrgByNameQuery) error {
+	if query.Name == "Org 3" {
+		query.Result = &models.Org{Id: 3}
+		return nil
+	}
+	return models.ErrOrgNotFound
+}
+
+func (m *mockStore) GetDashboard(_ context.Context, query *models.GetDashboardQuery) error {
+	if query.Uid == "home" && query.OrgId == 2 {
+		query.Result = &models.Dashboard{Id: 10, Uid: "home", OrgId: 2}
+		return nil
+	}
+	return dashboards.ErrDashboardNotFound
+}
+
+type mockPreferenceService struct {
+	*preftest.FakePreferenceService
+	patches []*pref.PatchPreferenceCommand
+}
+
+func (m *mockPreferenceService) Patch(_ context.Context, cmd *pref.PatchPreferenceCommand) error {
+	m.patches = append(m.patches, cmd)
+	return nil
+}
+
+type mockDataSourceStore struct {
+	dataSources []*datasources.DataSource
+	updates     []*datasources.UpdateDataSourceCommand
+}
+
+func (m *mockDataSourceStore) GetDataSource(_ context.Context, query *datasources.GetDataSourceQuery) error {
+	for _, ds := range m.dataSources {
+		if ds.Uid == query.Uid && ds.OrgId == query.OrgId {
+			query.Result = ds
+			return nil
+		}
+	}
+	return datasources.ErrDataSourceNotFound
+}
+
+func (m *mockDataSourceStore) UpdateDataSource(_ context.Context, cmd *datasources.UpdateDataSourceCommand) error {
+	m.updates = append(m.updates, cmd)
+	return nil
+}
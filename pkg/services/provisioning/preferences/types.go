@@ -0,0 +1,64 @@
+package preferences
+
+import "github.com/grafana/grafana/pkg/services/provisioning/values"
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// preferencesAsConfig is a normalized data object for preferences config data. Any config version should be mappable
+// to this type.
+type preferencesAsConfig struct {
+	Preferences []*orgPreferencesFromConfig
+}
+
+type orgPreferencesFromConfig struct {
+	OrgID                int64
+	OrgName              string
+	HomeDashboardUID     string
+	Timezone             string
+	WeekStart            string
+	DefaultDatasourceUID string
+}
+
+type orgPreferencesFromConfigV1 struct {
+	OrgID                values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName              values.StringValue `json:"orgName" yaml:"orgName"`
+	HomeDashboardUID     values.StringValue `json:"homeDashboardUid" yaml:"homeDashboardUid"`
+	Timezone             values.StringValue `json:"timezone" yaml:"timezone"`
+	WeekStart            values.StringValue `json:"weekStart" yaml:"weekStart"`
+	DefaultDatasourceUID values.StringValue `json:"defaultDatasourceUid" yaml:"defaultDatasourceUid"`
+}
+
+// preferencesAsConfigV1 is a mapping for version 1 configs. This is mapped to its normalised version.
+type preferencesAsConfigV1 struct {
+	configVersion
+
+	Preferences []*orgPreferencesFromConfigV1 `json:"preferences" yaml:"preferences"`
+}
+
+// mapToPreferencesFromConfig maps config syntax to a normalized preferencesAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *preferencesAsConfigV1) mapToPreferencesFromConfig() *preferencesAsConfig {
+	r := &preferencesAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, p := range cfg.Preferences {
+		if p == nil {
+			continue
+		}
+		r.Preferences = append(r.Preferences, &orgPreferencesFromConfig{
+			OrgID:                p.OrgID.Value(),
+			OrgName:              p.OrgName.Value(),
+			HomeDashboardUID:     p.HomeDashboardUID.Value(),
+			Timezone:             p.Timezone.Value(),
+			WeekStart:            p.WeekStart.Value(),
+			DefaultDatasourceUID: p.DefaultDatasourceUID.Value(),
+		})
+	}
+
+	return r
+}
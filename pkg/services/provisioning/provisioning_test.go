@@ -7,7 +7,9 @@ import (
 	"time"
 
 	dashboardstore "github.com/grafana/grafana/pkg/services/dashboards"
+	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/preferences"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/assert"
@@ -99,6 +101,9 @@ func setup() *serviceTestStruct {
 		nil,
 		nil,
 		nil,
+		func(context.Context, string, preferences.Store, pref.Service, utils.DashboardStore, preferences.DataSourceStore) error {
+			return nil
+		},
 	)
 	serviceTest.service.Cfg = setting.NewCfg()
 
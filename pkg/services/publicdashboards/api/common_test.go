@@ -133,6 +133,7 @@ func buildQueryDataService(t *testing.T, cs datasources.CacheService, fpc *fakeP
 		&fakePluginRequestValidator{},
 		&fakeDatasources.FakeDataSourceService{},
 		fpc,
+		nil,
 		&fakeOAuthTokenService{},
 	)
 }
@@ -38,6 +38,7 @@ func ProvideService(
 	pluginRequestValidator models.PluginRequestValidator,
 	dataSourceService datasources.DataSourceService,
 	pluginClient plugins.Client,
+	pluginStore plugins.Store,
 	oAuthTokenService oauthtoken.OAuthTokenService,
 ) *Service {
 	g := &Service{
@@ -47,6 +48,7 @@ func ProvideService(
 		pluginRequestValidator: pluginRequestValidator,
 		dataSourceService:      dataSourceService,
 		pluginClient:           pluginClient,
+		pluginStore:            pluginStore,
 		oAuthTokenService:      oAuthTokenService,
 		log:                    log.New("query_data"),
 	}
@@ -61,6 +63,7 @@ type Service struct {
 	pluginRequestValidator models.PluginRequestValidator
 	dataSourceService      datasources.DataSourceService
 	pluginClient           plugins.Client
+	pluginStore            plugins.Store
 	oAuthTokenService      oauthtoken.OAuthTokenService
 	log                    log.Logger
 }
@@ -201,6 +204,9 @@ func (s *Service) handleQueryData(ctx context.Context, user *models.SignedInUser
 
 	ctx = httpclient.WithContextualMiddleware(ctx, middlewares...)
 
+	if interval, concurrency := s.querySplitting(ctx, ds); interval > 0 {
+		return s.splitQueryData(ctx, req, interval, concurrency)
+	}
 	return s.pluginClient.QueryData(ctx, req)
 }
 
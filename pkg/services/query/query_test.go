@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
 
@@ -98,6 +102,56 @@ func TestQueryData(t *testing.T) {
 
 		require.Equal(t, map[string]string{"Cookie": "bar=rab; foo=oof"}, tc.pluginContext.req.Headers)
 	})
+
+	t.Run("it splits queries by time range when the data source supports it", func(t *testing.T) {
+		tc := setup(t)
+		tc.pluginStore.plugins = map[string]plugins.PluginDTO{
+			"loki": {JSONData: plugins.JSONData{ID: "loki", QuerySplitting: true}},
+		}
+		tc.dataSourceCache.ds = &datasources.DataSource{
+			Type:     "loki",
+			JsonData: simplejson.NewFromAny(map[string]interface{}{"querySplitInterval": "1d"}),
+		}
+		tc.pluginContext.queryDataFunc = func(req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			tr := req.Queries[0].TimeRange
+			frame := data.NewFrame("A",
+				data.NewField("time", nil, []time.Time{tr.From, tr.To}),
+				data.NewField("value", nil, []float64{1, 1}),
+			)
+			return &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{frame}}}}, nil
+		}
+
+		metricReq := metricRequest()
+		metricReq.From = "0"
+		metricReq.To = strconv.FormatInt((3 * 24 * time.Hour).Milliseconds(), 10)
+		resp, err := tc.queryService.QueryData(context.Background(), nil, true, metricReq, false)
+		require.NoError(t, err)
+
+		require.Len(t, tc.pluginContext.reqs, 3)
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		// the point at the boundary of two parts is only kept once
+		require.Equal(t, 4, frames[0].Rows())
+		for i := 0; i < 4; i++ {
+			require.Equal(t, time.Unix(0, 0).UTC().Add(time.Duration(i)*24*time.Hour), frames[0].Fields[0].At(i))
+		}
+	})
+
+	t.Run("it does not split queries when the plugin does not support it", func(t *testing.T) {
+		tc := setup(t)
+		tc.dataSourceCache.ds = &datasources.DataSource{
+			Type:     "loki",
+			JsonData: simplejson.NewFromAny(map[string]interface{}{"querySplitInterval": "1d"}),
+		}
+
+		metricReq := metricRequest()
+		metricReq.From = "0"
+		metricReq.To = strconv.FormatInt((3 * 24 * time.Hour).Milliseconds(), 10)
+		_, err := tc.queryService.QueryData(context.Background(), nil, true, metricReq, false)
+		require.NoError(t, err)
+
+		require.Len(t, tc.pluginContext.reqs, 1)
+	})
 }
 
 func setup(t *testing.T) *testContext {
@@ -105,6 +159,7 @@ func setup(t *testing.T) *testContext {
 	dc := &fakeDataSourceCache{ds: &datasources.DataSource{}}
 	tc := &fakeOAuthTokenService{}
 	rv := &fakePluginRequestValidator{}
+	ps := &fakePluginStore{}
 
 	ss := kvstore.SetupTestService(t)
 	ssvc := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
@@ -116,7 +171,8 @@ func setup(t *testing.T) *testContext {
 		dataSourceCache:        dc,
 		oauthTokenService:      tc,
 		pluginRequestValidator: rv,
-		queryService:           query.ProvideService(nil, dc, nil, rv, ds, pc, tc),
+		pluginStore:            ps,
+		queryService:           query.ProvideService(nil, dc, nil, rv, ds, pc, ps, tc),
 	}
 }
 
@@ -126,6 +182,7 @@ type testContext struct {
 	dataSourceCache        *fakeDataSourceCache
 	oauthTokenService      *fakeOAuthTokenService
 	pluginRequestValidator *fakePluginRequestValidator
+	pluginStore            *fakePluginStore
 	queryService           *query.Service
 }
 
@@ -175,10 +232,30 @@ func (c *fakeDataSourceCache) GetDatasourceByUID(ctx context.Context, datasource
 type fakePluginClient struct {
 	plugins.Client
 
-	req *backend.QueryDataRequest
+	mtx           sync.Mutex
+	req           *backend.QueryDataRequest
+	reqs          []*backend.QueryDataRequest
+	queryDataFunc func(req *backend.QueryDataRequest) (*backend.QueryDataResponse, error)
 }
 
 func (c *fakePluginClient) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	c.mtx.Lock()
 	c.req = req
+	c.reqs = append(c.reqs, req)
+	c.mtx.Unlock()
+	if c.queryDataFunc != nil {
+		return c.queryDataFunc(req)
+	}
 	return nil, nil
 }
+
+type fakePluginStore struct {
+	plugins.Store
+
+	plugins map[string]plugins.PluginDTO
+}
+
+func (s *fakePluginStore) Plugin(ctx context.Context, pluginID string) (plugins.PluginDTO, bool) {
+	p, exists := s.plugins[pluginID]
+	return p, exists
+}
@@ -0,0 +1,182 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// defaultQuerySplitConcurrency is how many parts of a split query run at the same time when the
+	// data source does not set querySplitConcurrency.
+	defaultQuerySplitConcurrency = 4
+	// maxQuerySplits is the maximum number of parts a query is split into. The split interval is
+	// widened for longer time ranges.
+	maxQuerySplits = 100
+)
+
+// querySplitting returns the interval the queries of a data source are split by and how many of the
+// parts run at the same time. The interval is 0 if the queries are not split, which is the case unless
+// the plugin declares support for it and the data source sets querySplitInterval.
+func (s *Service) querySplitting(ctx context.Context, ds *datasources.DataSource) (time.Duration, int) {
+	if s.pluginStore == nil || ds.JsonData == nil {
+		return 0, 0
+	}
+	p, exists := s.pluginStore.Plugin(ctx, ds.Type)
+	if !exists || !p.QuerySplitting {
+		return 0, 0
+	}
+
+	value := ds.JsonData.Get("querySplitInterval").MustString()
+	if value == "" {
+		return 0, 0
+	}
+	interval, err := gtime.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		s.log.Warn("Ignoring invalid query split interval", "datasource", ds.Uid, "interval", value, "error", err)
+		return 0, 0
+	}
+
+	concurrency := ds.JsonData.Get("querySplitConcurrency").MustInt(defaultQuerySplitConcurrency)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return interval, concurrency
+}
+
+// splitTimeRange splits a time range into consecutive parts of the given interval. The last part is
+// shorter if the time range is not a multiple of the interval.
+func splitTimeRange(tr backend.TimeRange, interval time.Duration) []backend.TimeRange {
+	total := tr.To.Sub(tr.From)
+	if total <= interval {
+		return []backend.TimeRange{tr}
+	}
+	if n := (total + interval - 1) / interval; n > maxQuerySplits {
+		interval = (total + maxQuerySplits - 1) / maxQuerySplits
+	}
+
+	var parts []backend.TimeRange
+	for from := tr.From; from.Before(tr.To); from = from.Add(interval) {
+		to := from.Add(interval)
+		if to.After(tr.To) {
+			to = tr.To
+		}
+		parts = append(parts, backend.TimeRange{From: from, To: to})
+	}
+	return parts
+}
+
+// splitQueryData runs the queries of a request as one request for each part of their time range,
+// with at most concurrency requests at the same time, and merges the responses.
+func (s *Service) splitQueryData(ctx context.Context, req *backend.QueryDataRequest, interval time.Duration, concurrency int) (*backend.QueryDataResponse, error) {
+	var requests []*backend.QueryDataRequest
+	for _, q := range req.Queries {
+		for i, tr := range splitTimeRange(q.TimeRange, interval) {
+			if i == len(requests) {
+				requests = append(requests, &backend.QueryDataRequest{
+					PluginContext: req.PluginContext,
+					Headers:       req.Headers,
+				})
+			}
+			part := q
+			part.TimeRange = tr
+			requests[i].Queries = append(requests[i].Queries, part)
+		}
+	}
+	if len(requests) <= 1 {
+		return s.pluginClient.QueryData(ctx, req)
+	}
+
+	s.log.Debug("Splitting query by time range", "datasource", req.PluginContext.PluginID, "parts", len(requests), "interval", interval)
+
+	responses := make([]*backend.QueryDataResponse, len(requests))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for i := range requests {
+		i := i
+		g.Go(func() error {
+			resp, err := s.pluginClient.QueryData(gctx, requests[i])
+			responses[i] = resp
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return mergeSplitResponses(responses), nil
+}
+
+// mergeSplitResponses merges the responses of the parts of a split query, which are in the order of
+// their time ranges. Frames with the same name and fields are joined into one frame, the first error of
+// a query is kept.
+func mergeSplitResponses(responses []*backend.QueryDataResponse) *backend.QueryDataResponse {
+	merged := backend.NewQueryDataResponse()
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		for refID, r := range resp.Responses {
+			m := merged.Responses[refID]
+			if m.Error == nil {
+				m.Error = r.Error
+			}
+			m.Frames = mergeFrames(m.Frames, r.Frames)
+			merged.Responses[refID] = m
+		}
+	}
+	return merged
+}
+
+func mergeFrames(dst data.Frames, src data.Frames) data.Frames {
+	for _, f := range src {
+		key := frameKey(f)
+		found := false
+		for _, d := range dst {
+			if frameKey(d) == key {
+				appendFrameRows(d, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, f)
+		}
+	}
+	return dst
+}
+
+// frameKey identifies the frames of the parts of a split query that hold the same series.
+func frameKey(f *data.Frame) string {
+	key := f.Name
+	for _, field := range f.Fields {
+		key += "\x00" + field.Name + "\x01" + field.Type().ItemTypeString() + "\x01" + field.Labels.String()
+	}
+	return key
+}
+
+// appendFrameRows appends the rows of src to dst, which have the same fields. If the first field is a
+// time, rows that are not after the last row of dst are skipped, as a point at the boundary of two
+// parts can be returned by both.
+func appendFrameRows(dst *data.Frame, src *data.Frame) {
+	var last *time.Time
+	if len(dst.Fields) > 0 && dst.Fields[0].Type() == data.FieldTypeTime && dst.Fields[0].Len() > 0 {
+		t := dst.Fields[0].At(dst.Fields[0].Len() - 1).(time.Time)
+		last = &t
+	}
+
+	for row := 0; row < src.Rows(); row++ {
+		if last != nil && !src.Fields[0].At(row).(time.Time).After(*last) {
+			continue
+		}
+		for i, field := range src.Fields {
+			dst.Fields[i].Append(field.At(row))
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package readonly
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	kvNamespace = "readonly"
+	kvKey       = "status"
+)
+
+// refreshInterval is how often an instance reads the read-only mode shared by all instances.
+const refreshInterval = 10 * time.Second
+
+// Status is the read-only mode of the instance.
+type Status struct {
+	Enabled bool `json:"enabled"`
+	// Configured is whether the configuration of the instance enables the read-only mode, in which case it
+	// cannot be turned off at runtime.
+	Configured        bool       `json:"configured"`
+	Reason            string     `json:"reason,omitempty"`
+	Since             *time.Time `json:"since,omitempty"`
+	RetryAfterSeconds int        `json:"retryAfterSeconds"`
+}
+
+// mode is the read-only mode stored in the key-value store.
+type mode struct {
+	Enabled bool      `json:"enabled"`
+	Reason  string    `json:"reason,omitempty"`
+	Since   time.Time `json:"since,omitempty"`
+}
+
+// Service holds the read-only mode. While it is enabled, the HTTP API rejects the requests that change
+// data, see middleware.ReadOnly.
+//
+// The mode has two sources. The mode set at runtime by server admins is stored in the database, so it is
+// shared by all the instances of Grafana: each instance reads it every refreshInterval. The mode of the
+// configuration only applies to the instance that has it and is never stored. An instance is in read-only
+// mode if either source enables it, so the configuration wins over a runtime change that turns the mode off.
+type Service struct {
+	log        log.Logger
+	retryAfter time.Duration
+	store      *kvstore.NamespacedKVStore
+	configured bool
+	started    time.Time
+
+	mu   sync.RWMutex
+	mode mode
+}
+
+func ProvideService(cfg *setting.Cfg, kv kvstore.KVStore) (*Service, error) {
+	s := &Service{
+		log:        log.New("readonly"),
+		retryAfter: cfg.ReadOnlyModeRetryAfter,
+		store:      kvstore.WithNamespace(kv, 0, kvNamespace),
+		configured: cfg.ReadOnlyMode,
+		started:    time.Now().UTC(),
+	}
+	if s.retryAfter <= 0 {
+		s.retryAfter = time.Minute
+	}
+
+	if err := s.refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	if s.configured {
+		s.log.Info("Read-only mode enabled in the configuration")
+	}
+	return s, nil
+}
+
+// Run reads the read-only mode shared by all instances every refreshInterval until the context is done.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.refresh(ctx); err != nil {
+				s.log.Error("Failed to read the read-only mode", "error", err)
+			}
+		}
+	}
+}
+
+// IsEnabled returns whether the instance is in read-only mode.
+func (s *Service) IsEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.configured || s.mode.Enabled
+}
+
+// RetryAfter is the time clients should wait before retrying a request rejected in read-only mode.
+func (s *Service) RetryAfter() time.Duration {
+	return s.retryAfter
+}
+
+// Status returns the current read-only mode.
+func (s *Service) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := Status{
+		Enabled:           s.configured || s.mode.Enabled,
+		Configured:        s.configured,
+		RetryAfterSeconds: int(s.retryAfter.Seconds()),
+	}
+	switch {
+	case s.mode.Enabled:
+		since := s.mode.Since
+		status.Reason, status.Since = s.mode.Reason, &since
+	case s.configured:
+		since := s.started
+		status.Reason, status.Since = "enabled in the configuration", &since
+	}
+	return status
+}
+
+// SetEnabled turns the read-only mode of all instances on or off, reason is shown to the admins checking
+// the status. The other instances apply the change the next time they read the mode. Turning the mode off
+// does not affect the instances that enable it in their configuration.
+func (s *Service) SetEnabled(ctx context.Context, enabled bool, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := mode{}
+	if enabled {
+		next = mode{Enabled: true, Reason: reason, Since: time.Now().UTC()}
+		if s.mode.Enabled {
+			next.Since = s.mode.Since
+		}
+	}
+	value, err := json.Marshal(next)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Set(ctx, kvKey, string(value)); err != nil {
+		return err
+	}
+
+	if enabled != s.mode.Enabled {
+		s.log.Info("Changed read-only mode", "enabled", enabled, "reason", reason)
+	}
+	s.mode = next
+	return nil
+}
+
+// refresh reads the read-only mode from the key-value store.
+func (s *Service) refresh(ctx context.Context) error {
+	value, ok, err := s.store.Get(ctx, kvKey)
+	if err != nil {
+		return err
+	}
+	next := mode{}
+	if ok {
+		if err := json.Unmarshal([]byte(value), &next); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if next.Enabled != s.mode.Enabled {
+		s.log.Info("Changed read-only mode on another instance", "enabled", next.Enabled, "reason", next.Reason)
+	}
+	s.mode = next
+	return nil
+}
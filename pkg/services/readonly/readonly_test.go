@@ -0,0 +1,87 @@
+package readonly
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("starts with the mode of the configuration", func(t *testing.T) {
+		kv := kvstore.ProvideService(sqlstore.InitTestDB(t))
+		s, err := ProvideService(&setting.Cfg{ReadOnlyMode: true, ReadOnlyModeRetryAfter: 30 * time.Second}, kv)
+		require.NoError(t, err)
+
+		status := s.Status()
+		require.True(t, status.Enabled)
+		require.True(t, status.Configured)
+		require.NotNil(t, status.Since)
+		require.Equal(t, 30, status.RetryAfterSeconds)
+	})
+
+	t.Run("of the configuration is local to the instance and cannot be turned off at runtime", func(t *testing.T) {
+		kv := kvstore.ProvideService(sqlstore.InitTestDB(t))
+		configured, err := ProvideService(&setting.Cfg{ReadOnlyMode: true}, kv)
+		require.NoError(t, err)
+		other, err := ProvideService(&setting.Cfg{}, kv)
+		require.NoError(t, err)
+		require.False(t, other.IsEnabled())
+
+		require.NoError(t, configured.SetEnabled(ctx, true, "database maintenance"))
+		require.NoError(t, other.refresh(ctx))
+		require.True(t, other.IsEnabled())
+		require.Equal(t, "database maintenance", configured.Status().Reason)
+
+		require.NoError(t, other.SetEnabled(ctx, false, ""))
+		require.NoError(t, configured.refresh(ctx))
+		require.False(t, other.IsEnabled())
+		status := configured.Status()
+		require.True(t, status.Enabled)
+		require.Equal(t, "enabled in the configuration", status.Reason)
+	})
+
+	t.Run("can be enabled and disabled", func(t *testing.T) {
+		s, err := ProvideService(&setting.Cfg{}, kvstore.ProvideService(sqlstore.InitTestDB(t)))
+		require.NoError(t, err)
+		require.False(t, s.IsEnabled())
+		require.Equal(t, time.Minute, s.RetryAfter())
+
+		require.NoError(t, s.SetEnabled(ctx, true, "database maintenance"))
+		status := s.Status()
+		require.True(t, status.Enabled)
+		require.Equal(t, "database maintenance", status.Reason)
+
+		require.NoError(t, s.SetEnabled(ctx, false, ""))
+		require.Equal(t, Status{RetryAfterSeconds: 60}, s.Status())
+	})
+
+	t.Run("is shared by the instances using the same database", func(t *testing.T) {
+		kv := kvstore.ProvideService(sqlstore.InitTestDB(t))
+		s, err := ProvideService(&setting.Cfg{}, kv)
+		require.NoError(t, err)
+		other, err := ProvideService(&setting.Cfg{}, kv)
+		require.NoError(t, err)
+
+		require.NoError(t, s.SetEnabled(ctx, true, "database maintenance"))
+		require.False(t, other.IsEnabled())
+		require.NoError(t, other.refresh(ctx))
+		require.Equal(t, s.Status(), other.Status())
+
+		// an instance started later gets the mode of the others
+		started, err := ProvideService(&setting.Cfg{}, kv)
+		require.NoError(t, err)
+		require.True(t, started.IsEnabled())
+
+		require.NoError(t, other.SetEnabled(ctx, false, ""))
+		require.NoError(t, s.refresh(ctx))
+		require.False(t, s.IsEnabled())
+	})
+}
@@ -8,25 +8,16 @@ import (
 	"io"
 	"io/fs"
 	"mime"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"time"
-)
 
-var netTransport = &http.Transport{
-	Proxy: http.ProxyFromEnvironment,
-	Dial: (&net.Dialer{
-		Timeout: 30 * time.Second,
-	}).Dial,
-	TLSHandshakeTimeout: 5 * time.Second,
-}
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+)
 
-var netClient = &http.Client{
-	Transport: netTransport,
-}
+var netClient = httpclient.NewOutboundClient(httpclient.OutboundOptions{Name: "rendering"})
 
 var (
 	remoteVersionFetchInterval   time.Duration = time.Second * 15
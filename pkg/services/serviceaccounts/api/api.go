@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -25,6 +26,8 @@ type ServiceAccountsAPI struct {
 	accesscontrol  accesscontrol.AccessControl
 	RouterRegister routing.RouteRegister
 	store          serviceaccounts.Store
+	roles          accesscontrol.RoleGetter
+	managedRoles   accesscontrol.ManagedRoleStore
 	log            log.Logger
 }
 
@@ -34,6 +37,8 @@ func NewServiceAccountsAPI(
 	accesscontrol accesscontrol.AccessControl,
 	routerRegister routing.RouteRegister,
 	store serviceaccounts.Store,
+	roles accesscontrol.RoleGetter,
+	managedRoles accesscontrol.ManagedRoleStore,
 ) *ServiceAccountsAPI {
 	return &ServiceAccountsAPI{
 		cfg:            cfg,
@@ -41,6 +46,8 @@ func NewServiceAccountsAPI(
 		accesscontrol:  accesscontrol,
 		RouterRegister: routerRegister,
 		store:          store,
+		roles:          roles,
+		managedRoles:   managedRoles,
 		log:            log.New("serviceaccounts.api"),
 	}
 }
@@ -58,6 +65,10 @@ func (api *ServiceAccountsAPI) RegisterAPIEndpoints() {
 			accesscontrol.EvalPermission(serviceaccounts.ActionWrite, serviceaccounts.ScopeID)), routing.Wrap(api.UpdateServiceAccount))
 		serviceAccountsRoute.Delete("/:serviceAccountId", auth(middleware.ReqOrgAdmin,
 			accesscontrol.EvalPermission(serviceaccounts.ActionDelete, serviceaccounts.ScopeID)), routing.Wrap(api.DeleteServiceAccount))
+		serviceAccountsRoute.Get("/:serviceAccountId/permissions/diff", auth(middleware.ReqOrgAdmin,
+			accesscontrol.EvalPermission(serviceaccounts.ActionRead, serviceaccounts.ScopeID)), routing.Wrap(api.DiffServiceAccountPermissions))
+		serviceAccountsRoute.Get("/:serviceAccountId/permissions/history", auth(middleware.ReqOrgAdmin,
+			accesscontrol.EvalPermission(serviceaccounts.ActionRead, serviceaccounts.ScopeID)), routing.Wrap(api.GetServiceAccountPermissionHistory))
 		serviceAccountsRoute.Get("/:serviceAccountId/tokens", auth(middleware.ReqOrgAdmin,
 			accesscontrol.EvalPermission(serviceaccounts.ActionRead, serviceaccounts.ScopeID)), routing.Wrap(api.ListTokens))
 		serviceAccountsRoute.Post("/:serviceAccountId/tokens", auth(middleware.ReqOrgAdmin,
@@ -92,9 +103,121 @@ func (api *ServiceAccountsAPI) CreateServiceAccount(c *models.ReqContext) respon
 		return response.Error(http.StatusInternalServerError, "Failed to create service account", err)
 	}
 
+	if !api.accesscontrol.IsDisabled() {
+		role, err := api.managedRoles.GetOrCreateManagedUserRole(c.Req.Context(), serviceAccount.OrgId, serviceAccount.Id)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to create managed role of service account", err)
+		}
+		serviceAccount.ManagedRoleUID = role.UID
+
+		permissions, err := api.getServiceAccountPermissions(c.Req.Context(), serviceAccount.OrgId, serviceAccount.Id, serviceAccount.Login, serviceAccount.Role)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to get permissions of service account", err)
+		}
+		serviceAccount.Permissions = permissions
+	}
+
 	return response.JSON(http.StatusCreated, serviceAccount)
 }
 
+// GET /api/serviceaccounts/:serviceAccountId/permissions/diff?role=basic:viewer
+func (api *ServiceAccountsAPI) DiffServiceAccountPermissions(c *models.ReqContext) response.Response {
+	if api.accesscontrol.IsDisabled() {
+		return response.Error(http.StatusNotFound, "Role based access control is disabled", nil)
+	}
+
+	scopeID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+	roleName := c.Query("role")
+	if roleName == "" {
+		return response.Error(http.StatusBadRequest, "Role name is required", nil)
+	}
+
+	serviceAccount, err := api.store.RetrieveServiceAccount(c.Req.Context(), c.OrgId, scopeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
+			return response.Error(http.StatusNotFound, "Failed to retrieve service account", err)
+		default:
+			return response.Error(http.StatusInternalServerError, "Failed to retrieve service account", err)
+		}
+	}
+
+	role, err := api.roles.GetRoleByName(c.Req.Context(), roleName)
+	if err != nil {
+		switch {
+		case errors.Is(err, accesscontrol.ErrRoleNotFound):
+			return response.Error(http.StatusNotFound, "Failed to retrieve role", err)
+		default:
+			return response.Error(http.StatusInternalServerError, "Failed to retrieve role", err)
+		}
+	}
+
+	permissions, err := api.getServiceAccountPermissions(c.Req.Context(), serviceAccount.OrgId, serviceAccount.Id, serviceAccount.Login, serviceAccount.Role)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get permissions of service account", err)
+	}
+
+	return response.JSON(http.StatusOK, serviceaccounts.PermissionsDiff{
+		Role:    role.Name,
+		Missing: uncoveredPermissions(accesscontrol.GroupScopesByAction(role.Permissions), permissions),
+		Extra:   uncoveredPermissions(permissions, accesscontrol.GroupScopesByAction(role.Permissions)),
+	})
+}
+
+// GET /api/serviceaccounts/:serviceAccountId/permissions/history
+func (api *ServiceAccountsAPI) GetServiceAccountPermissionHistory(c *models.ReqContext) response.Response {
+	scopeID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+
+	history, err := api.store.GetPermissionHistory(c.Req.Context(), c.OrgId, scopeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
+			return response.Error(http.StatusNotFound, "Failed to retrieve service account", err)
+		default:
+			return response.Error(http.StatusInternalServerError, "Failed to get permission history of service account", err)
+		}
+	}
+
+	return response.JSON(http.StatusOK, history)
+}
+
+// getServiceAccountPermissions returns the effective permissions of the service account, scopes grouped by action
+func (api *ServiceAccountsAPI) getServiceAccountPermissions(ctx context.Context, orgID, serviceAccountID int64, login, role string) (map[string][]string, error) {
+	permissions, err := api.accesscontrol.GetUserPermissions(ctx, &models.SignedInUser{
+		UserId:  serviceAccountID,
+		OrgId:   orgID,
+		OrgRole: models.RoleType(role),
+		Login:   login,
+	}, accesscontrol.Options{ReloadCache: true})
+	if err != nil {
+		return nil, err
+	}
+	return accesscontrol.GroupScopesByAction(permissions), nil
+}
+
+// uncoveredPermissions returns the permissions that are not granted by the other permissions
+func uncoveredPermissions(permissions, other map[string][]string) map[string][]string {
+	uncovered := map[string][]string{}
+	for action, scopes := range permissions {
+		for _, scope := range scopes {
+			evaluator := accesscontrol.EvalPermission(action)
+			if scope != "" {
+				evaluator = accesscontrol.EvalPermission(action, scope)
+			}
+			if !evaluator.Evaluate(other) {
+				uncovered[action] = append(uncovered[action], scope)
+			}
+		}
+	}
+	return uncovered
+}
+
 // GET /api/serviceaccounts/:serviceAccountId
 func (api *ServiceAccountsAPI) RetrieveServiceAccount(ctx *models.ReqContext) response.Response {
 	scopeID, err := strconv.ParseInt(web.Params(ctx.Req)[":serviceAccountId"], 10, 64)
@@ -144,6 +267,7 @@ func (api *ServiceAccountsAPI) UpdateServiceAccount(c *models.ReqContext) respon
 	if cmd.Role != nil && !c.OrgRole.Includes(*cmd.Role) {
 		return response.Error(http.StatusForbidden, "Cannot assign a role higher than user's role", nil)
 	}
+	cmd.UpdatedBy = c.UserId
 
 	resp, err := api.store.UpdateServiceAccount(c.Req.Context(), c.OrgId, scopeID, &cmd)
 	if err != nil {
@@ -224,7 +348,7 @@ func (api *ServiceAccountsAPI) SearchOrgServiceAccountsWithPaging(c *models.ReqC
 		sa.Tokens = int64(len(tokens))
 	}
 
-	return response.JSON(http.StatusOK, serviceAccountSearch)
+	return response.JSONFields(http.StatusOK, serviceAccountSearch, response.Fields(c), "serviceAccounts")
 }
 
 // GET /api/serviceaccounts/migrationstatus
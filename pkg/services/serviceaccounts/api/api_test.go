@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acdatabase "github.com/grafana/grafana/pkg/services/accesscontrol/database"
 	accesscontrolmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
@@ -142,6 +143,9 @@ func TestServiceAccountsAPI_CreateServiceAccount(t *testing.T) {
 					assert.NotEmpty(t, actualBody["id"])
 					assert.Equal(t, tc.body["name"], actualBody["name"].(string))
 					assert.Equal(t, tc.wantID, actualBody["login"].(string))
+					assert.Equal(t, string(models.ROLE_VIEWER), actualBody["role"])
+					assert.NotEmpty(t, actualBody["managedRoleUid"])
+					assert.Equal(t, map[string]interface{}{serviceaccounts.ActionCreate: []interface{}{""}}, actualBody["permissions"])
 				} else if actualCode == http.StatusBadRequest {
 					assert.Contains(t, tc.wantError, actualBody["error"].(string))
 				}
@@ -224,7 +228,7 @@ func setupTestServer(t *testing.T, svc *tests.ServiceAccountMock,
 	routerRegister routing.RouteRegister,
 	acmock *accesscontrolmock.Mock,
 	sqlStore *sqlstore.SQLStore, saStore serviceaccounts.Store) (*web.Mux, *ServiceAccountsAPI) {
-	a := NewServiceAccountsAPI(setting.NewCfg(), svc, acmock, routerRegister, saStore)
+	a := NewServiceAccountsAPI(setting.NewCfg(), svc, acmock, routerRegister, saStore, acmock, acdatabase.ProvideService(sqlStore))
 	a.RegisterAPIEndpoints()
 
 	a.cfg.ApiKeyMaxSecondsToLive = -1 // disable api key expiration
@@ -474,3 +478,148 @@ func TestServiceAccountsAPI_UpdateServiceAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceAccountsAPI_DiffServiceAccountPermissions(t *testing.T) {
+	store := sqlstore.InitTestDB(t)
+	kvStore := kvstore.ProvideService(store)
+	saStore := database.NewServiceAccountsStore(store, kvStore)
+	svcmock := tests.ServiceAccountMock{}
+
+	var requestResponse = func(server *web.Mux, requestpath string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, requestpath, nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	sa := tests.SetupUserServiceAccount(t, store, tests.TestUser{Login: "servicetest1@admin", IsServiceAccount: true})
+	setupMock := func(t *testing.T) *accesscontrolmock.Mock {
+		acmock := tests.SetupMockAccesscontrol(
+			t,
+			func(c context.Context, siu *models.SignedInUser, _ accesscontrol.Options) ([]accesscontrol.Permission, error) {
+				if siu.UserId == sa.ID {
+					return []accesscontrol.Permission{
+						{Action: "dashboards:read", Scope: "dashboards:*"},
+						{Action: "folders:read", Scope: "folders:uid:general"},
+					}, nil
+				}
+				return []accesscontrol.Permission{{Action: serviceaccounts.ActionRead, Scope: serviceaccounts.ScopeAll}}, nil
+			},
+			false,
+		)
+		acmock.GetRoleByNameFunc = func(_ context.Context, name string) (*accesscontrol.RoleDTO, error) {
+			if name != "fixed:test:reader" {
+				return nil, accesscontrol.ErrRoleNotFound
+			}
+			return &accesscontrol.RoleDTO{Name: name, Permissions: []accesscontrol.Permission{
+				{Action: "dashboards:read", Scope: "dashboards:uid:1"},
+				{Action: "folders:read", Scope: "folders:*"},
+				{Action: "annotations:read"},
+			}}, nil
+		}
+		return acmock
+	}
+
+	t.Run("should return permissions missing and extra compared to the role", func(t *testing.T) {
+		server, _ := setupTestServer(t, &svcmock, routing.NewRouteRegister(), setupMock(t), store, saStore)
+		actual := requestResponse(server, fmt.Sprintf(serviceAccountIDPath+"/permissions/diff?role=fixed:test:reader", sa.ID))
+		require.Equal(t, http.StatusOK, actual.Code, actual.Body.String())
+
+		var diff serviceaccounts.PermissionsDiff
+		require.NoError(t, json.Unmarshal(actual.Body.Bytes(), &diff))
+		assert.Equal(t, serviceaccounts.PermissionsDiff{
+			Role: "fixed:test:reader",
+			Missing: map[string][]string{
+				"folders:read":     {"folders:*"},
+				"annotations:read": {""},
+			},
+			Extra: map[string][]string{
+				"dashboards:read": {"dashboards:*"},
+			},
+		}, diff)
+	})
+
+	t.Run("should return bad request without role", func(t *testing.T) {
+		server, _ := setupTestServer(t, &svcmock, routing.NewRouteRegister(), setupMock(t), store, saStore)
+		actual := requestResponse(server, fmt.Sprintf(serviceAccountIDPath+"/permissions/diff", sa.ID))
+		require.Equal(t, http.StatusBadRequest, actual.Code)
+	})
+
+	t.Run("should return not found for unknown role", func(t *testing.T) {
+		server, _ := setupTestServer(t, &svcmock, routing.NewRouteRegister(), setupMock(t), store, saStore)
+		actual := requestResponse(server, fmt.Sprintf(serviceAccountIDPath+"/permissions/diff?role=fixed:unknown", sa.ID))
+		require.Equal(t, http.StatusNotFound, actual.Code)
+	})
+
+	t.Run("should return not found for unknown service account", func(t *testing.T) {
+		server, _ := setupTestServer(t, &svcmock, routing.NewRouteRegister(), setupMock(t), store, saStore)
+		actual := requestResponse(server, fmt.Sprintf(serviceAccountIDPath+"/permissions/diff?role=fixed:test:reader", 12345))
+		require.Equal(t, http.StatusNotFound, actual.Code)
+	})
+}
+
+func TestServiceAccountsAPI_GetServiceAccountPermissionHistory(t *testing.T) {
+	store := sqlstore.InitTestDB(t)
+	kvStore := kvstore.ProvideService(store)
+	saStore := database.NewServiceAccountsStore(store, kvStore)
+	svcmock := tests.ServiceAccountMock{}
+
+	var requestResponse = func(server *web.Mux, httpMethod, requestpath string, body io.Reader) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(httpMethod, requestpath, body)
+		req.Header.Add("Content-Type", "application/json")
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	sa := tests.SetupUserServiceAccount(t, store, tests.TestUser{Login: "servicetest1@admin", IsServiceAccount: true})
+	setupMock := func(t *testing.T, permissions []accesscontrol.Permission) *accesscontrolmock.Mock {
+		return tests.SetupMockAccesscontrol(
+			t,
+			func(c context.Context, siu *models.SignedInUser, _ accesscontrol.Options) ([]accesscontrol.Permission, error) {
+				return permissions, nil
+			},
+			false,
+		)
+	}
+	writer := []accesscontrol.Permission{
+		{Action: serviceaccounts.ActionRead, Scope: serviceaccounts.ScopeAll},
+		{Action: serviceaccounts.ActionWrite, Scope: serviceaccounts.ScopeAll},
+	}
+
+	t.Run("should return the role changes of the service account", func(t *testing.T) {
+		server, _ := setupTestServer(t, &svcmock, routing.NewRouteRegister(), setupMock(t, writer), store, saStore)
+		initial, err := saStore.RetrieveServiceAccount(context.Background(), sa.OrgID, sa.ID)
+		require.NoError(t, err)
+		require.NotEqual(t, "Viewer", initial.Role)
+		viewerRole := models.ROLE_VIEWER
+		body, err := json.Marshal(serviceaccounts.UpdateServiceAccountForm{Role: &viewerRole})
+		require.NoError(t, err)
+		actual := requestResponse(server, http.MethodPatch, fmt.Sprintf(serviceAccountIDPath, sa.ID), bytes.NewReader(body))
+		require.Equal(t, http.StatusOK, actual.Code, actual.Body.String())
+
+		actual = requestResponse(server, http.MethodGet, fmt.Sprintf(serviceAccountIDPath+"/permissions/history", sa.ID), http.NoBody)
+		require.Equal(t, http.StatusOK, actual.Code, actual.Body.String())
+
+		var history []serviceaccounts.PermissionChange
+		require.NoError(t, json.Unmarshal(actual.Body.Bytes(), &history))
+		require.Len(t, history, 1)
+		assert.Equal(t, sa.ID, history[0].ServiceAccountId)
+		assert.Equal(t, initial.Role, history[0].PreviousRole)
+		assert.Equal(t, "Viewer", history[0].Role)
+	})
+
+	t.Run("should return not found for unknown service account", func(t *testing.T) {
+		server, _ := setupTestServer(t, &svcmock, routing.NewRouteRegister(), setupMock(t, writer), store, saStore)
+		actual := requestResponse(server, http.MethodGet, fmt.Sprintf(serviceAccountIDPath+"/permissions/history", 12345), http.NoBody)
+		require.Equal(t, http.StatusNotFound, actual.Code)
+	})
+
+	t.Run("should be forbidden without permissions", func(t *testing.T) {
+		server, _ := setupTestServer(t, &svcmock, routing.NewRouteRegister(), setupMock(t, nil), store, saStore)
+		actual := requestResponse(server, http.MethodGet, fmt.Sprintf(serviceAccountIDPath+"/permissions/history", sa.ID), http.NoBody)
+		require.Equal(t, http.StatusForbidden, actual.Code)
+	})
+}
@@ -76,6 +76,7 @@ func (s *ServiceAccountsStoreImpl) CreateServiceAccount(ctx context.Context, org
 		Name:   newSA.Name,
 		Login:  newSA.Login,
 		OrgId:  newSA.OrgID,
+		Role:   string(models.ROLE_VIEWER),
 		Tokens: 0,
 	}, nil
 }
@@ -98,6 +99,18 @@ func (s *ServiceAccountsStoreImpl) UpdateServiceAccount(ctx context.Context,
 		}
 
 		updateTime := time.Now()
+		if saForm.Role != nil && updatedUser.Role != string(*saForm.Role) {
+			if err := addPermissionChange(sess, &permissionChange{
+				OrgId:            orgId,
+				ServiceAccountId: serviceAccountId,
+				ChangedBy:        saForm.UpdatedBy,
+				Created:          updateTime,
+				PreviousRole:     updatedUser.Role,
+				Role:             string(*saForm.Role),
+			}); err != nil {
+				return err
+			}
+		}
 		if saForm.Role != nil {
 			var orgUser models.OrgUser
 			orgUser.Role = *saForm.Role
@@ -140,6 +153,7 @@ func (s *ServiceAccountsStoreImpl) UpdateServiceAccount(ctx context.Context,
 func ServiceAccountDeletions() []string {
 	deletes := []string{
 		"DELETE FROM api_key WHERE service_account_id = ?",
+		"DELETE FROM " + permissionChangeTable + " WHERE service_account_id = ?",
 	}
 	deletes = append(deletes, sqlstore.UserDeletions()...)
 	return deletes
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const permissionChangeTable = "service_account_permission_change"
+
+// permissionChange is a row of the permission history of service accounts, rows are never updated
+type permissionChange struct {
+	Id               int64     `xorm:"pk autoincr 'id'"`
+	OrgId            int64     `xorm:"org_id"`
+	ServiceAccountId int64     `xorm:"service_account_id"`
+	ChangedBy        int64     `xorm:"changed_by"`
+	Created          time.Time `xorm:"created"`
+	PreviousRole     string    `xorm:"previous_role"`
+	Role             string    `xorm:"role"`
+}
+
+func addPermissionChange(sess *sqlstore.DBSession, change *permissionChange) error {
+	_, err := sess.Table(permissionChangeTable).Insert(change)
+	return err
+}
+
+// GetPermissionHistory returns the changes of the role of a service account, most recent first
+func (s *ServiceAccountsStoreImpl) GetPermissionHistory(ctx context.Context, orgId, serviceAccountId int64) ([]*serviceaccounts.PermissionChange, error) {
+	result := make([]*serviceaccounts.PermissionChange, 0)
+	err := s.sqlStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		if _, err := s.RetrieveServiceAccount(ctx, orgId, serviceAccountId); err != nil {
+			return err
+		}
+
+		quotedUser := s.sqlStore.Dialect.Quote("user")
+		return dbSession.Table(permissionChangeTable).
+			Select(permissionChangeTable+".id, "+
+				permissionChangeTable+".service_account_id, "+
+				permissionChangeTable+".changed_by, "+
+				quotedUser+".login AS changed_by_login, "+
+				permissionChangeTable+".created, "+
+				permissionChangeTable+".previous_role, "+
+				permissionChangeTable+".role").
+			Join("LEFT", quotedUser, quotedUser+".id = "+permissionChangeTable+".changed_by").
+			Where(permissionChangeTable+".org_id = ? AND "+permissionChangeTable+".service_account_id = ?", orgId, serviceAccountId).
+			Desc(permissionChangeTable + ".id").
+			Find(&result)
+	})
+	return result, err
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_GetPermissionHistory(t *testing.T) {
+	ctx := context.Background()
+	db, store := setupTestDatabase(t)
+	admin := tests.SetupUserServiceAccount(t, db, tests.TestUser{Login: "admin1", Role: string(models.ROLE_ADMIN)})
+	sa := tests.SetupUserServiceAccount(t, db, tests.TestUser{Login: "servicetest1@admin", IsServiceAccount: true})
+
+	initial, err := store.RetrieveServiceAccount(ctx, sa.OrgID, sa.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, "Editor", initial.Role)
+
+	editor, viewer := models.ROLE_EDITOR, models.ROLE_VIEWER
+	name := "renamed"
+	for _, form := range []serviceaccounts.UpdateServiceAccountForm{
+		{Role: &editor, UpdatedBy: admin.ID},
+		// neither a change of name nor an update to the same role changes permissions
+		{Name: &name, UpdatedBy: admin.ID},
+		{Role: &editor, UpdatedBy: admin.ID},
+		{Role: &viewer, UpdatedBy: admin.ID},
+	} {
+		form := form
+		_, err := store.UpdateServiceAccount(ctx, sa.OrgID, sa.ID, &form)
+		require.NoError(t, err)
+	}
+
+	history, err := store.GetPermissionHistory(ctx, sa.OrgID, sa.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "Editor", history[0].PreviousRole)
+	require.Equal(t, "Viewer", history[0].Role)
+	require.Equal(t, initial.Role, history[1].PreviousRole)
+	require.Equal(t, "Editor", history[1].Role)
+	for _, change := range history {
+		require.Equal(t, sa.ID, change.ServiceAccountId)
+		require.Equal(t, admin.ID, change.ChangedBy)
+		require.Equal(t, "admin1", change.ChangedByLogin)
+		require.False(t, change.Created.IsZero())
+	}
+
+	t.Run("history of unknown service accounts is not found", func(t *testing.T) {
+		_, err := store.GetPermissionHistory(ctx, sa.OrgID, admin.ID)
+		require.ErrorIs(t, err, serviceaccounts.ErrServiceAccountNotFound)
+	})
+
+	t.Run("history is deleted with the service account", func(t *testing.T) {
+		require.NoError(t, store.DeleteServiceAccount(ctx, sa.OrgID, sa.ID))
+		var count int64
+		err := db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+			var err error
+			count, err = sess.Table(permissionChangeTable).Where("service_account_id = ?", sa.ID).Count()
+			return err
+		})
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+}
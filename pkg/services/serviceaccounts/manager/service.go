@@ -29,6 +29,8 @@ func ProvideServiceAccountsService(
 	store *sqlstore.SQLStore,
 	kvStore kvstore.KVStore,
 	ac accesscontrol.AccessControl,
+	roles accesscontrol.RoleGetter,
+	managedRoles accesscontrol.ManagedRoleStore,
 	routeRegister routing.RouteRegister,
 	usageStats usagestats.Service,
 ) (*ServiceAccountsService, error) {
@@ -44,7 +46,7 @@ func ProvideServiceAccountsService(
 
 	usageStats.RegisterMetricsFunc(s.store.GetUsageMetrics)
 
-	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, routeRegister, s.store)
+	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, routeRegister, s.store, roles, managedRoles)
 	serviceaccountsAPI.RegisterAPIEndpoints()
 
 	return s, nil
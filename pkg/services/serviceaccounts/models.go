@@ -31,6 +31,8 @@ type UpdateServiceAccountForm struct {
 	Name       *string          `json:"name"`
 	Role       *models.RoleType `json:"role"`
 	IsDisabled *bool            `json:"isDisabled"`
+	// UpdatedBy is the ID of the user updating the service account, recorded in its permission history
+	UpdatedBy int64 `json:"-"`
 }
 
 type ServiceAccountDTO struct {
@@ -43,6 +45,32 @@ type ServiceAccountDTO struct {
 	Tokens        int64           `json:"tokens"`
 	AvatarUrl     string          `json:"avatarUrl"`
 	AccessControl map[string]bool `json:"accessControl,omitempty"`
+	// ManagedRoleUID is the UID of the managed role holding the permissions granted to the service account
+	ManagedRoleUID string `json:"managedRoleUid,omitempty"`
+	// Permissions are the effective permissions of the service account, scopes grouped by action
+	Permissions map[string][]string `json:"permissions,omitempty"`
+}
+
+// PermissionsDiff are the differences between the permissions of a service account and a role
+type PermissionsDiff struct {
+	Role string `json:"role"`
+	// Missing are the permissions of the role the service account does not have
+	Missing map[string][]string `json:"missing"`
+	// Extra are the permissions of the service account that are not in the role
+	Extra map[string][]string `json:"extra"`
+}
+
+// PermissionChange is a change of the role of a service account, recorded in its append-only permission history
+type PermissionChange struct {
+	Id               int64 `json:"id" xorm:"id"`
+	ServiceAccountId int64 `json:"serviceAccountId" xorm:"service_account_id"`
+	// ChangedBy is the ID of the user who made the change
+	ChangedBy int64 `json:"changedBy" xorm:"changed_by"`
+	// ChangedByLogin is the login of the user who made the change, empty if the user has been deleted
+	ChangedByLogin string    `json:"changedByLogin" xorm:"changed_by_login"`
+	Created        time.Time `json:"created" xorm:"created"`
+	PreviousRole   string    `json:"previousRole" xorm:"previous_role"`
+	Role           string    `json:"role" xorm:"role"`
 }
 
 type AddServiceAccountTokenCommand struct {
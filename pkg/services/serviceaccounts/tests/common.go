@@ -129,6 +129,7 @@ type Calls struct {
 	UpdateServiceAccount            []interface{}
 	AddServiceAccountToken          []interface{}
 	SearchOrgServiceAccounts        []interface{}
+	GetPermissionHistory            []interface{}
 	RetrieveServiceAccountIdByName  []interface{}
 }
 
@@ -197,6 +198,11 @@ func (s *ServiceAccountsStoreMock) UpdateServiceAccount(ctx context.Context,
 	return nil, nil
 }
 
+func (s *ServiceAccountsStoreMock) GetPermissionHistory(ctx context.Context, orgID, serviceAccountID int64) ([]*serviceaccounts.PermissionChange, error) {
+	s.Calls.GetPermissionHistory = append(s.Calls.GetPermissionHistory, []interface{}{ctx, orgID, serviceAccountID})
+	return nil, nil
+}
+
 func (s *ServiceAccountsStoreMock) SearchOrgServiceAccounts(
 	ctx context.Context,
 	orgID int64,
@@ -92,6 +92,7 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 	accesscontrol.AddManagedFolderAlertActionsMigration(mg)
 	accesscontrol.AddActionNameMigrator(mg)
 	addPlaylistUIDMigration(mg)
+	addServiceAccountPermissionChangeMigrations(mg)
 
 	ualert.UpdateRuleGroupIndexMigration(mg)
 }
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
@@ -237,6 +238,71 @@ func TestDatabaseLocking(t *testing.T) {
 	assert.Equal(t, int64(1), errorNum)
 }
 
+func TestDatabaseLockingWait(t *testing.T) {
+	dbType := getDBType()
+	// skip for SQLite since there is no database locking (only migrator locking)
+	if dbType == SQLite {
+		t.Skip()
+	}
+
+	testDB := getTestDB(t, dbType)
+
+	x, err := xorm.NewEngine(testDB.DriverName, testDB.ConnStr)
+	require.NoError(t, err)
+
+	err = NewDialect(x).CleanDB()
+	require.NoError(t, err)
+
+	reg := registry{
+		migrators: make(map[int]*Migrator, 2),
+	}
+	migrations := &OSSMigrations{}
+	for i := 0; i < 2; i++ {
+		mg := NewMigrator(x, &setting.Cfg{})
+		mg.LockWaitTimeout = time.Minute
+		migrations.AddMigration(mg)
+		reg.set(i, mg)
+	}
+
+	t.Run("when concurrent migrations occur with a wait timeout, the second one should wait for the first one", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			i := i // capture i variable
+			t.Run(fmt.Sprintf("run migration %d", i), func(t *testing.T) {
+				mg, err := reg.get(i)
+				require.NoError(t, err)
+				t.Parallel()
+				require.NoError(t, mg.Start(true, 0))
+			})
+		}
+	})
+}
+
+func TestMigrationStatus(t *testing.T) {
+	testDB := sqlutil.SQLite3TestDB()
+
+	x, err := xorm.NewEngine(testDB.DriverName, testDB.ConnStr)
+	require.NoError(t, err)
+
+	err = NewDialect(x).CleanDB()
+	require.NoError(t, err)
+
+	statuses, err := GetMigrationStatus(x)
+	require.NoError(t, err)
+	require.Empty(t, statuses)
+
+	mg := NewMigrator(x, &setting.Cfg{})
+	migrations := &OSSMigrations{}
+	migrations.AddMigration(mg)
+	require.NoError(t, mg.Start(true, 0))
+
+	statuses, err = GetMigrationStatus(x)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, setting.InstanceName, statuses[0].Instance)
+	require.Equal(t, MigrationStateDone, statuses[0].State)
+	require.False(t, statuses[0].Updated.Before(statuses[0].Started))
+}
+
 func checkStepsAndDatabaseMatch(t *testing.T, mg *Migrator, expected []string) {
 	t.Helper()
 	log, err := mg.GetMigrationLog()
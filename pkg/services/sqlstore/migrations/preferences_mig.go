@@ -55,4 +55,20 @@ func addPreferencesMigrations(mg *Migrator) {
 	// change column type of preferences.json_data
 	mg.AddMigration("alter preferences.json_data to mediumtext v1", NewRawSQLMigration("").
 		Mysql("ALTER TABLE preferences MODIFY json_data MEDIUMTEXT;"))
+
+	homeDashboardTeamPriorityV1 := Table{
+		Name: "home_dashboard_team_priority",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false},
+			{Name: "priority", Type: DB_Int, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "team_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create home_dashboard_team_priority table v1", NewAddTableMigration(homeDashboardTeamPriorityV1))
+	mg.AddMigration("add unique index home_dashboard_team_priority.org_id_team_id", NewAddIndexMigration(homeDashboardTeamPriorityV1, homeDashboardTeamPriorityV1.Indices[0]))
 }
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addServiceAccountPermissionChangeMigrations(mg *Migrator) {
+	permissionChangeV1 := Table{
+		Name: "service_account_permission_change",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "service_account_id", Type: DB_BigInt, Nullable: false},
+			{Name: "changed_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "previous_role", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "role", Type: DB_NVarchar, Length: 20, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "service_account_id"}},
+		},
+	}
+
+	mg.AddMigration("create service_account_permission_change table v1", NewAddTableMigration(permissionChangeV1))
+
+	mg.AddMigration("add index service_account_permission_change.org_id-service_account_id", NewAddIndexMigration(permissionChangeV1, permissionChangeV1.Indices[0]))
+}
@@ -27,6 +27,10 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	AddProvisioningMigrations(mg)
 
 	AddAlertImageMigrations(mg)
+
+	AddNotificationPolicyHistoryMigrations(mg)
+
+	AddDeferredNotificationMigrations(mg)
 }
 
 // AddAlertDefinitionMigrations should not be modified.
@@ -251,6 +255,26 @@ func AddAlertRuleMigrations(mg *migrator.Migrator, defaultIntervalSeconds int64)
 			Default:  "1",
 		},
 	))
+
+	mg.AddMigration("add webhooks column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{Name: "webhooks", Type: migrator.DB_Text, Nullable: true},
+	))
+
+	mg.AddMigration("add is_paused column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{Name: "is_paused", Type: migrator.DB_Bool, Nullable: false, Default: "0"},
+	))
+
+	mg.AddMigration("add max_instances column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{Name: "max_instances", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+	))
+
+	mg.AddMigration("add group_labels column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{Name: "group_labels", Type: migrator.DB_Text, Nullable: true},
+	))
 }
 
 func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
@@ -304,6 +328,26 @@ func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
 			Default:  "1",
 		},
 	))
+
+	mg.AddMigration("add webhooks column to alert_rule_version", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{Name: "webhooks", Type: migrator.DB_Text, Nullable: true},
+	))
+
+	mg.AddMigration("add is_paused column to alert_rule_version", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{Name: "is_paused", Type: migrator.DB_Bool, Nullable: false, Default: "0"},
+	))
+
+	mg.AddMigration("add max_instances column to alert_rule_version", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{Name: "max_instances", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+	))
+
+	mg.AddMigration("add group_labels column to alert_rule_version", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{Name: "group_labels", Type: migrator.DB_Text, Nullable: true},
+	))
 }
 
 func AddAlertmanagerConfigMigrations(mg *migrator.Migrator) {
@@ -337,6 +381,14 @@ func AddAlertmanagerConfigMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("add configuration_hash column to alert_configuration", migrator.NewAddColumnMigration(alertConfiguration, &migrator.Column{
 		Name: "configuration_hash", Type: migrator.DB_Varchar, Nullable: false, Default: "'not-yet-calculated'", Length: 32,
 	}))
+
+	mg.AddMigration("add origin column to alert_configuration", migrator.NewAddColumnMigration(alertConfiguration, &migrator.Column{
+		Name: "origin", Type: migrator.DB_NVarchar, Nullable: false, Default: "''", Length: 40,
+	}))
+
+	mg.AddMigration("add created_by column to alert_configuration", migrator.NewAddColumnMigration(alertConfiguration, &migrator.Column{
+		Name: "created_by", Type: migrator.DB_NVarchar, Nullable: false, Default: "''", Length: 190,
+	}))
 }
 
 func AddAlertAdminConfigMigrations(mg *migrator.Migrator) {
@@ -399,3 +451,43 @@ func AddAlertImageMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("create alert_image table", migrator.NewAddTableMigration(imageTable))
 	mg.AddMigration("add unique index on token to alert_image table", migrator.NewAddIndexMigration(imageTable, imageTable.Indices[0]))
 }
+
+func AddNotificationPolicyHistoryMigrations(mg *migrator.Migrator) {
+	historyTable := migrator.Table{
+		Name: "alert_notification_policy_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "policy_tree", Type: migrator.DB_Text, Nullable: false},
+			{Name: "created_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "version"}, Type: migrator.UniqueIndex},
+		},
+	}
+	mg.AddMigration("create alert_notification_policy_history table", migrator.NewAddTableMigration(historyTable))
+	mg.AddMigration("add unique index on org_id and version to alert_notification_policy_history table", migrator.NewAddIndexMigration(historyTable, historyTable.Indices[0]))
+	mg.AddMigration("alter alert_notification_policy_history table policy_tree column to mediumtext in mysql", migrator.NewRawSQLMigration("").
+		Mysql("ALTER TABLE alert_notification_policy_history MODIFY policy_tree MEDIUMTEXT;"))
+}
+
+func AddDeferredNotificationMigrations(mg *migrator.Migrator) {
+	deferredTable := migrator.Table{
+		Name: "alert_deferred_notification",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "fingerprint", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "alert", Type: migrator.DB_Text, Nullable: false},
+			{Name: "created_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "receiver", "fingerprint"}, Type: migrator.UniqueIndex},
+		},
+	}
+	mg.AddMigration("create alert_deferred_notification table", migrator.NewAddTableMigration(deferredTable))
+	mg.AddMigration("add unique index on org_id, receiver and fingerprint to alert_deferred_notification table", migrator.NewAddIndexMigration(deferredTable, deferredTable.Indices[0]))
+}
@@ -27,6 +27,9 @@ type Migrator struct {
 	Logger       log.Logger
 	Cfg          *setting.Cfg
 	isLocked     atomic.Bool
+	// LockWaitTimeout is how long to wait for another instance to finish the migrations when migration locking is
+	// enabled, the migrations fail right away if it is zero.
+	LockWaitTimeout time.Duration
 }
 
 type MigrationLog struct {
@@ -105,9 +108,22 @@ func (mg *Migrator) Start(isDatabaseLockingEnabled bool, lockAttemptTimeout int)
 		return mg.run()
 	}
 
+	if err := mg.ensureStatusTable(); err != nil {
+		return fmt.Errorf("failed to create migration status table: %w", err)
+	}
+	started := time.Now()
+	mg.setStatus(MigrationStateWaiting, started)
+	defer func() {
+		if err != nil {
+			mg.setStatus(MigrationStateFailed, started)
+			return
+		}
+		mg.setStatus(MigrationStateDone, started)
+	}()
+
 	return mg.InTransaction(func(sess *xorm.Session) error {
 		mg.Logger.Info("Locking database")
-		if err := casRestoreOnErr(&mg.isLocked, false, true, ErrMigratorIsLocked, mg.Dialect.Lock, LockCfg{Session: sess, Timeout: lockAttemptTimeout}); err != nil {
+		if err := mg.lock(LockCfg{Session: sess, Timeout: lockAttemptTimeout}); err != nil {
 			mg.Logger.Error("Failed to lock database", "error", err)
 			return err
 		}
@@ -120,6 +136,7 @@ func (mg *Migrator) Start(isDatabaseLockingEnabled bool, lockAttemptTimeout int)
 			}
 		}()
 
+		mg.setStatus(MigrationStateMigrating, started)
 		// migration will run inside a nested transaction
 		return mg.run()
 	})
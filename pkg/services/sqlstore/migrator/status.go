@@ -0,0 +1,113 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	MigrationStateWaiting   = "waiting"
+	MigrationStateMigrating = "migrating"
+	MigrationStateDone      = "done"
+	MigrationStateFailed    = "failed"
+)
+
+// lockRetryInterval is the time between two attempts to lock the database when waiting for another instance to
+// finish the migrations.
+var lockRetryInterval = time.Second
+
+// MigrationStatus is the state of the migrations of a Grafana instance. It is recorded when migration locking is
+// enabled, so that the instances of a rollout can tell which one is migrating.
+type MigrationStatus struct {
+	Instance string    `json:"instance" xorm:"instance"`
+	State    string    `json:"state" xorm:"state"`
+	Started  time.Time `json:"started" xorm:"started"`
+	Updated  time.Time `json:"updated" xorm:"updated"`
+}
+
+// migrationStatusTable is created outside of the migrations, as the status is recorded while waiting for the lock of
+// the migrations.
+var migrationStatusTable = Table{
+	Name: "migration_status",
+	Columns: []*Column{
+		{Name: "instance", Type: DB_NVarchar, Length: 190, Nullable: false, IsPrimaryKey: true},
+		{Name: "state", Type: DB_NVarchar, Length: 20, Nullable: false},
+		{Name: "started", Type: DB_DateTime, Nullable: false},
+		{Name: "updated", Type: DB_DateTime, Nullable: false},
+	},
+}
+
+func (mg *Migrator) ensureStatusTable() error {
+	_, err := mg.DBEngine.Exec(mg.Dialect.CreateTableSQL(&migrationStatusTable))
+	return err
+}
+
+// setStatus records the state of the migrations of this instance. The status is only informational, failing to
+// record it does not stop the migrations.
+func (mg *Migrator) setStatus(state string, started time.Time) {
+	status := MigrationStatus{
+		Instance: setting.InstanceName,
+		State:    state,
+		Started:  started,
+		Updated:  time.Now(),
+	}
+	sess := mg.DBEngine.NewSession()
+	defer sess.Close()
+	if _, err := sess.Exec("DELETE FROM "+mg.Dialect.Quote(migrationStatusTable.Name)+" WHERE instance = ?", status.Instance); err != nil {
+		mg.Logger.Warn("Failed to record migration status", "state", state, "error", err)
+		return
+	}
+	if _, err := sess.Table(migrationStatusTable.Name).Insert(&status); err != nil {
+		mg.Logger.Warn("Failed to record migration status", "state", state, "error", err)
+	}
+}
+
+// lock locks the database for the migrations. If LockWaitTimeout is set, it waits for the instance holding the lock
+// to release it.
+func (mg *Migrator) lock(cfg LockCfg) error {
+	deadline := time.Now().Add(mg.LockWaitTimeout)
+	for {
+		err := casRestoreOnErr(&mg.isLocked, false, true, ErrMigratorIsLocked, mg.Dialect.Lock, cfg)
+		if !errors.Is(err, ErrLockDB) || !time.Now().Before(deadline) {
+			return err
+		}
+		mg.Logger.Info("Waiting for another instance to finish the migrations", "migrating", mg.migratingInstances())
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+func (mg *Migrator) migratingInstances() []string {
+	statuses, err := GetMigrationStatus(mg.DBEngine)
+	if err != nil {
+		return nil
+	}
+	var instances []string
+	for _, status := range statuses {
+		if status.State == MigrationStateMigrating {
+			instances = append(instances, status.Instance)
+		}
+	}
+	return instances
+}
+
+// GetMigrationStatus returns the state of the migrations of every instance that ran them with migration locking
+// enabled, most recently updated first.
+func GetMigrationStatus(engine *xorm.Engine) ([]MigrationStatus, error) {
+	statuses := make([]MigrationStatus, 0)
+	exists, err := engine.IsTableExist(migrationStatusTable.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", "failed to check table existence", err)
+	}
+	if !exists {
+		return statuses, nil
+	}
+	if err := engine.Table(migrationStatusTable.Name).Desc("updated").Find(&statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
@@ -42,6 +42,7 @@ type SQLStoreMock struct {
 	ExpectedAPIKey                 *models.ApiKey
 	ExpectedUserStars              map[int64]bool
 	ExpectedLoginAttempts          int64
+	ExpectedMigrationStatus        []migrator.MigrationStatus
 
 	ExpectedError            error
 	ExpectedSetUsingOrgError error
@@ -79,6 +80,10 @@ func (m *SQLStoreMock) GetDialect() migrator.Dialect {
 	return nil
 }
 
+func (m *SQLStoreMock) GetMigrationStatus(ctx context.Context) ([]migrator.MigrationStatus, error) {
+	return m.ExpectedMigrationStatus, m.ExpectedError
+}
+
 func (m *SQLStoreMock) HasEditPermissionInFolders(ctx context.Context, query *models.HasEditPermissionInFoldersQuery) error {
 	return m.ExpectedError
 }
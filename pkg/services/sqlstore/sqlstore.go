@@ -132,11 +132,18 @@ func (ss *SQLStore) Migrate(isDatabaseLockingEnabled bool) error {
 	}
 
 	migrator := migrator.NewMigrator(ss.engine, ss.Cfg)
+	migrator.LockWaitTimeout = time.Duration(ss.dbCfg.MigrationLockWaitTimeout) * time.Second
 	ss.migrations.AddMigration(migrator)
 
 	return migrator.Start(isDatabaseLockingEnabled, ss.dbCfg.MigrationLockAttemptTimeout)
 }
 
+// GetMigrationStatus returns the state of the migrations of the instances that ran them with migration locking
+// enabled.
+func (ss *SQLStore) GetMigrationStatus(ctx context.Context) ([]migrator.MigrationStatus, error) {
+	return migrator.GetMigrationStatus(ss.engine)
+}
+
 // Sync syncs changes to the database.
 func (ss *SQLStore) Sync() error {
 	return ss.engine.Sync2()
@@ -422,6 +429,7 @@ func (ss *SQLStore) readConfig() error {
 	ss.dbCfg.CacheMode = sec.Key("cache_mode").MustString("private")
 	ss.dbCfg.SkipMigrations = sec.Key("skip_migrations").MustBool()
 	ss.dbCfg.MigrationLockAttemptTimeout = sec.Key("locking_attempt_timeout_sec").MustInt()
+	ss.dbCfg.MigrationLockWaitTimeout = sec.Key("locking_wait_timeout_sec").MustInt()
 	return nil
 }
 
@@ -639,4 +647,5 @@ type DatabaseConfig struct {
 	UrlQueryParams              map[string][]string
 	SkipMigrations              bool
 	MigrationLockAttemptTimeout int
+	MigrationLockWaitTimeout    int
 }
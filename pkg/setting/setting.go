@@ -49,6 +49,19 @@ const (
 	ApplicationName  = "Grafana"
 )
 
+// DashboardSanitizationPolicy is what is done with the dangerous content, such as scripts in text panels,
+// found in dashboards when they are saved.
+type DashboardSanitizationPolicy string
+
+const (
+	// DashboardSanitizationOff saves dashboards as they are.
+	DashboardSanitizationOff DashboardSanitizationPolicy = "off"
+	// DashboardSanitizationFlag saves dashboards as they are and logs the dangerous content.
+	DashboardSanitizationFlag DashboardSanitizationPolicy = "flag"
+	// DashboardSanitizationStrip removes the dangerous content before dashboards are saved.
+	DashboardSanitizationStrip DashboardSanitizationPolicy = "strip"
+)
+
 // zoneInfo names environment variable for setting the path to look for the timezone database in go
 const zoneInfo = "ZONEINFO"
 
@@ -207,6 +220,10 @@ type Cfg struct {
 	EnableGzip       bool
 	EnforceDomain    bool
 
+	// Read-only mode
+	ReadOnlyMode           bool
+	ReadOnlyModeRetryAfter time.Duration
+
 	// Security settings
 	SecretKey             string
 	EmailCodeValidMinutes int
@@ -257,6 +274,8 @@ type Cfg struct {
 	// CSPTemplate contains the Content Security Policy template.
 	CSPTemplate           string
 	AngularSupportEnabled bool
+	// Egress restricts the destinations of outbound requests.
+	Egress EgressSettings
 
 	TempDataLifetime                 time.Duration
 	PluginsEnableAlpha               bool
@@ -278,7 +297,16 @@ type Cfg struct {
 	MetricsGrafanaEnvironmentInfo    map[string]string
 
 	// Dashboards
-	DefaultHomeDashboardPath string
+	DefaultHomeDashboardPath     string
+	DashboardContentSanitization DashboardSanitizationPolicy
+	// JsonnetAllowedLibraries are the libraries that the imported jsonnet bundles may contain.
+	JsonnetAllowedLibraries []string
+	// JsonnetEvaluationTimeout is the maximum duration of the evaluation of an imported jsonnet bundle.
+	JsonnetEvaluationTimeout time.Duration
+	// JsonnetEvaluationMaxMemory is the maximum size in bytes of the heap of the evaluation of an imported jsonnet bundle.
+	JsonnetEvaluationMaxMemory int64
+	// JsonnetMaxConcurrentEvaluations is the maximum number of evaluations of imported jsonnet bundles in progress.
+	JsonnetMaxConcurrentEvaluations int
 
 	// Auth
 	LoginCookieName              string
@@ -290,6 +318,9 @@ type Cfg struct {
 	BasicAuthEnabled             bool
 	AdminUser                    string
 	AdminPassword                string
+	AuthEventsEndpointEnabled    bool
+	AuthEventsEndpointMaxEvents  int
+	AuthzSnapshot                AuthzSnapshotSettings
 
 	// AWS Plugin Auth
 	AWSAllowedAuthProviders []string
@@ -338,6 +369,7 @@ type Cfg struct {
 	DataProxyIdleConnTimeout       int
 	ResponseLimit                  int64
 	DataProxyRowLimit              int64
+	DataProxyQueryRowLimit         int64
 
 	// DistributedCache
 	RemoteCacheOptions *RemoteCacheOptions
@@ -414,6 +446,9 @@ type Cfg struct {
 	ExpressionsEnabled bool
 
 	ImageUploadProvider string
+	// ImageUploadRetention is how long uploaded images are kept in the external image storage,
+	// 0 keeps them forever.
+	ImageUploadRetention time.Duration
 
 	// LiveMaxConnections is a maximum number of WebSocket connections to
 	// Grafana Live ws endpoint (per Grafana server instance). 0 disables
@@ -623,6 +658,18 @@ type AnnotationCleanupSettings struct {
 	MaxCount int64
 }
 
+// AuthzSnapshotSettings configure the snapshots of the roles, teams and permissions of users that external
+// systems fetch to mirror the authorization decisions of Grafana.
+type AuthzSnapshotSettings struct {
+	Enabled bool
+	// SigningKey signs the snapshots and the webhook requests, it is required when the snapshots are enabled.
+	SigningKey string
+	// WebhookURL is notified when the snapshot of a user changes, no webhook is called if it is empty.
+	WebhookURL string
+	// PollInterval is how often the snapshots that were fetched are checked for changes.
+	PollInterval time.Duration
+}
+
 func EnvKey(sectionName string, keyName string) string {
 	sN := strings.ToUpper(strings.ReplaceAll(sectionName, ".", "_"))
 	sN = strings.ReplaceAll(sN, "-", "_")
@@ -914,6 +961,10 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 		return err
 	}
 
+	if err := readEgressSettings(iniFile, cfg); err != nil {
+		return err
+	}
+
 	if err := readSnapshotsSettings(cfg, iniFile); err != nil {
 		return err
 	}
@@ -924,6 +975,16 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	cfg.DashboardContentSanitization = DashboardSanitizationPolicy(valueAsString(dashboards, "content_sanitization", string(DashboardSanitizationOff)))
+	switch cfg.DashboardContentSanitization {
+	case DashboardSanitizationOff, DashboardSanitizationFlag, DashboardSanitizationStrip:
+	default:
+		return fmt.Errorf("invalid [dashboards] content_sanitization %q, must be one of off, flag or strip", cfg.DashboardContentSanitization)
+	}
+	cfg.JsonnetAllowedLibraries = util.SplitString(valueAsString(dashboards, "jsonnet_allowed_libraries", ""))
+	cfg.JsonnetEvaluationTimeout = dashboards.Key("jsonnet_evaluation_timeout").MustDuration(30 * time.Second)
+	cfg.JsonnetEvaluationMaxMemory = dashboards.Key("jsonnet_evaluation_max_memory").MustInt64(268435456)
+	cfg.JsonnetMaxConcurrentEvaluations = dashboards.Key("jsonnet_max_concurrent_evaluations").MustInt(2)
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1029,6 +1090,11 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	imageUploadingSection := iniFile.Section("external_image_storage")
 	cfg.ImageUploadProvider = valueAsString(imageUploadingSection, "provider", "")
 	ImageUploadProvider = cfg.ImageUploadProvider
+	imageUploadRetention, err := gtime.ParseDuration(valueAsString(imageUploadingSection, "retention", "0"))
+	if err != nil {
+		return fmt.Errorf("invalid retention of the external image storage: %w", err)
+	}
+	cfg.ImageUploadRetention = imageUploadRetention
 
 	enterprise := iniFile.Section("enterprise")
 	cfg.EnterpriseLicensePath = valueAsString(enterprise, "license_path", filepath.Join(cfg.DataPath, "license.jwt"))
@@ -1280,6 +1346,23 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	cfg.OAuthCookieMaxAge = auth.Key("oauth_state_cookie_max_age").MustInt(600)
 	SignoutRedirectUrl = valueAsString(auth, "signout_redirect_url", "")
 	cfg.OAuthSkipOrgRoleUpdateSync = auth.Key("oauth_skip_org_role_update_sync").MustBool(false)
+	cfg.AuthEventsEndpointEnabled = auth.Key("events_endpoint_enabled").MustBool(false)
+	cfg.AuthEventsEndpointMaxEvents = auth.Key("events_endpoint_max_events").MustInt(100)
+
+	authzSnapshot := iniFile.Section("auth.authz_snapshot")
+	cfg.AuthzSnapshot.Enabled = authzSnapshot.Key("enabled").MustBool(false)
+	cfg.AuthzSnapshot.SigningKey = valueAsString(authzSnapshot, "signing_key", "")
+	if cfg.AuthzSnapshot.Enabled && cfg.AuthzSnapshot.SigningKey == "" {
+		return errors.New("signing_key is required when [auth.authz_snapshot] is enabled")
+	}
+	cfg.AuthzSnapshot.WebhookURL = valueAsString(authzSnapshot, "webhook_url", "")
+	cfg.AuthzSnapshot.PollInterval, err = gtime.ParseDuration(valueAsString(authzSnapshot, "poll_interval", "5m"))
+	if err != nil {
+		return fmt.Errorf("invalid poll_interval in [auth.authz_snapshot]: %w", err)
+	}
+	if cfg.AuthzSnapshot.PollInterval < time.Minute {
+		cfg.AuthzSnapshot.PollInterval = time.Minute
+	}
 
 	// SigV4
 	SigV4AuthEnabled = auth.Key("sigv4_auth_enabled").MustBool(false)
@@ -1513,6 +1596,8 @@ func (cfg *Cfg) readServerSettings(iniFile *ini.File) error {
 	}
 
 	cfg.ReadTimeout = server.Key("read_timeout").MustDuration(0)
+	cfg.ReadOnlyMode = server.Key("read_only_mode").MustBool(false)
+	cfg.ReadOnlyModeRetryAfter = server.Key("read_only_mode_retry_after").MustDuration(time.Minute)
 
 	return nil
 }
@@ -0,0 +1,56 @@
+package setting
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var tlsVersions = map[string]uint16{
+	"TLS1.0": tls.VersionTLS10,
+	"TLS1.1": tls.VersionTLS11,
+	"TLS1.2": tls.VersionTLS12,
+	"TLS1.3": tls.VersionTLS13,
+}
+
+// EgressSettings restricts the destinations of the outbound requests that Grafana makes on its own,
+// such as notifications, webhooks, plugin installs and image rendering.
+type EgressSettings struct {
+	// AllowedHosts are host names, wildcards such as *.example.com and CIDR ranges.
+	// If it is not empty, only the destinations that match one of them can be reached.
+	AllowedHosts []string
+	// DeniedHosts are host names, wildcards and CIDR ranges that cannot be reached.
+	DeniedHosts []string
+	// MinTLSVersion is the minimum TLS version of the connections to HTTPS destinations, 0 if not set.
+	MinTLSVersion uint16
+}
+
+func readEgressSettings(iniFile *ini.File, cfg *Cfg) error {
+	egress := iniFile.Section("security.egress")
+	cfg.Egress.AllowedHosts = util.SplitString(valueAsString(egress, "allowed_hosts", ""))
+	cfg.Egress.DeniedHosts = util.SplitString(valueAsString(egress, "denied_hosts", ""))
+	for _, host := range append(cfg.Egress.AllowedHosts, cfg.Egress.DeniedHosts...) {
+		if !strings.Contains(host, "/") {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(host); err != nil {
+			return fmt.Errorf("invalid CIDR range %q in [security.egress]: %w", host, err)
+		}
+	}
+
+	cfg.Egress.MinTLSVersion = 0
+	minTLSVersion := valueAsString(egress, "min_tls_version", "")
+	if minTLSVersion != "" {
+		v, ok := tlsVersions[strings.ToUpper(minTLSVersion)]
+		if !ok {
+			return fmt.Errorf("unknown TLS version %q in [security.egress], should be one of TLS1.0, TLS1.1, TLS1.2 or TLS1.3", minTLSVersion)
+		}
+		cfg.Egress.MinTLSVersion = v
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package setting
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadEgressSettings(t *testing.T) {
+	load := func(t *testing.T, settings map[string]string) (*Cfg, error) {
+		iniFile := ini.Empty()
+		s, err := iniFile.NewSection("security.egress")
+		require.NoError(t, err)
+		for k, v := range settings {
+			_, err := s.NewKey(k, v)
+			require.NoError(t, err)
+		}
+		cfg := NewCfg()
+		return cfg, readEgressSettings(iniFile, cfg)
+	}
+
+	t.Run("no restrictions by default", func(t *testing.T) {
+		cfg, err := load(t, nil)
+		require.NoError(t, err)
+		require.Empty(t, cfg.Egress.AllowedHosts)
+		require.Empty(t, cfg.Egress.DeniedHosts)
+		require.Zero(t, cfg.Egress.MinTLSVersion)
+	})
+
+	t.Run("reads the rules and the TLS version", func(t *testing.T) {
+		cfg, err := load(t, map[string]string{
+			"allowed_hosts":   "*.example.com hooks.slack.com",
+			"denied_hosts":    "169.254.169.254 10.0.0.0/8",
+			"min_tls_version": "TLS1.2",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"*.example.com", "hooks.slack.com"}, cfg.Egress.AllowedHosts)
+		require.Equal(t, []string{"169.254.169.254", "10.0.0.0/8"}, cfg.Egress.DeniedHosts)
+		require.Equal(t, uint16(tls.VersionTLS12), cfg.Egress.MinTLSVersion)
+	})
+
+	t.Run("fails on invalid values", func(t *testing.T) {
+		_, err := load(t, map[string]string{"denied_hosts": "10.0.0.0/33"})
+		require.Error(t, err)
+		_, err = load(t, map[string]string{"min_tls_version": "SSL3"})
+		require.Error(t, err)
+	})
+}
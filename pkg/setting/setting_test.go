@@ -352,6 +352,24 @@ func TestAuthDurationSettings(t *testing.T) {
 	require.Equal(t, maxLifetimeDurationTest, cfg.LoginMaxLifetime)
 }
 
+func TestAuthzSnapshotSettings(t *testing.T) {
+	f := ini.Empty()
+	cfg := NewCfg()
+	sec, err := f.NewSection("auth.authz_snapshot")
+	require.NoError(t, err)
+	_, err = sec.NewKey("enabled", "true")
+	require.NoError(t, err)
+	err = readAuthSettings(f, cfg)
+	require.Error(t, err)
+
+	_, err = sec.NewKey("signing_key", "signing key")
+	require.NoError(t, err)
+	err = readAuthSettings(f, cfg)
+	require.NoError(t, err)
+	require.True(t, cfg.AuthzSnapshot.Enabled)
+	require.Equal(t, "signing key", cfg.AuthzSnapshot.SigningKey)
+}
+
 func TestGetCDNPath(t *testing.T) {
 	var err error
 	cfg := NewCfg()
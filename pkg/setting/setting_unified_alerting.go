@@ -51,6 +51,9 @@ const (
 	screenshotsDefaultCapture               = false
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	policyLimitsDefaultMaxRoutes            = 5000
+	policyLimitsDefaultMaxDepth             = 20
+	policyLimitsDefaultMaxMatchersPerRoute  = 50
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -81,6 +84,32 @@ type UnifiedAlertingSettings struct {
 	// DefaultRuleEvaluationInterval default interval between evaluations of a rule.
 	DefaultRuleEvaluationInterval time.Duration
 	Screenshots                   UnifiedAlertingScreenshotSettings
+	// PolicyTreeLimits are the limits on the notification policy trees of the organizations without limits of their own.
+	PolicyTreeLimits UnifiedAlertingPolicyTreeLimits
+	// PolicyTreeLimitsPerOrg are the limits on the notification policy trees of organizations by their ID.
+	PolicyTreeLimitsPerOrg map[int64]UnifiedAlertingPolicyTreeLimits
+	// GlobalTemplatesPath is the directory of the message templates that are shared by all organizations.
+	// Templates are not shared if it is empty.
+	GlobalTemplatesPath string
+	// MaxAlertInstancesPerRule is the maximum number of alert instances of an evaluation of the rules that have no limit
+	// of their own. Alert instances are not limited if it is zero or less.
+	MaxAlertInstancesPerRule int64
+	// WarmupPeriod is the time after the alert state is restored on startup during which resolved alerts are not sent.
+	WarmupPeriod time.Duration
+	// ConfigHistoryMaxVersions is the number of stored versions of the Alertmanager configuration kept per organization.
+	// All versions are kept if it is zero.
+	ConfigHistoryMaxVersions int64
+	// ConfigHistoryMaxAge is the time the stored versions of the Alertmanager configuration are kept. Versions are kept
+	// regardless of their age if it is zero. The latest version of an organization is never removed.
+	ConfigHistoryMaxAge time.Duration
+	// ConfigLimits are the limits on the Alertmanager configurations of the organizations without limits of their own.
+	ConfigLimits UnifiedAlertingConfigLimits
+	// ConfigLimitsPerOrg are the limits on the Alertmanager configurations of organizations by their ID.
+	ConfigLimitsPerOrg map[int64]UnifiedAlertingConfigLimits
+	// RuleLimits are the limits on the alert rules of the organizations without limits of their own.
+	RuleLimits UnifiedAlertingRuleLimits
+	// RuleLimitsPerOrg are the limits on the alert rules of organizations by their ID.
+	RuleLimitsPerOrg map[int64]UnifiedAlertingRuleLimits
 }
 
 type UnifiedAlertingScreenshotSettings struct {
@@ -89,6 +118,59 @@ type UnifiedAlertingScreenshotSettings struct {
 	UploadExternalImageStorage bool
 }
 
+// UnifiedAlertingPolicyTreeLimits are the limits on the size of a notification policy tree that are enforced when
+// the tree is saved. Limits of zero or less are not enforced.
+type UnifiedAlertingPolicyTreeLimits struct {
+	// MaxRoutes is the maximum number of routes of a tree, including the root route.
+	MaxRoutes int64
+	// MaxDepth is the maximum number of levels of routes below the root route.
+	MaxDepth int64
+	// MaxMatchersPerRoute is the maximum number of matchers of a route.
+	MaxMatchersPerRoute int64
+}
+
+// UnifiedAlertingConfigLimits are the limits on the Alertmanager configuration of an organization that are enforced
+// when the configuration is saved. Limits of zero or less are not enforced.
+type UnifiedAlertingConfigLimits struct {
+	// MaxSize is the maximum size in bytes of the serialized configuration.
+	MaxSize int64
+}
+
+// UnifiedAlertingRuleLimits are the limits on the number of alert rules of an organization that are enforced when
+// rules are provisioned. Limits of zero or less are not enforced.
+type UnifiedAlertingRuleLimits struct {
+	// MaxRules is the maximum number of alert rules of the organization.
+	MaxRules int64
+	// MaxRulesPerGroup is the maximum number of alert rules of a rule group.
+	MaxRulesPerGroup int64
+	// MaxGroupsPerFolder is the maximum number of rule groups of a folder.
+	MaxGroupsPerFolder int64
+}
+
+// RuleLimitsForOrg returns the limits on the alert rules of an organization.
+func (u *UnifiedAlertingSettings) RuleLimitsForOrg(orgID int64) UnifiedAlertingRuleLimits {
+	if limits, ok := u.RuleLimitsPerOrg[orgID]; ok {
+		return limits
+	}
+	return u.RuleLimits
+}
+
+// ConfigLimitsForOrg returns the limits on the Alertmanager configuration of an organization.
+func (u *UnifiedAlertingSettings) ConfigLimitsForOrg(orgID int64) UnifiedAlertingConfigLimits {
+	if limits, ok := u.ConfigLimitsPerOrg[orgID]; ok {
+		return limits
+	}
+	return u.ConfigLimits
+}
+
+// PolicyTreeLimitsForOrg returns the limits on the notification policy tree of an organization.
+func (u *UnifiedAlertingSettings) PolicyTreeLimitsForOrg(orgID int64) UnifiedAlertingPolicyTreeLimits {
+	if limits, ok := u.PolicyTreeLimitsPerOrg[orgID]; ok {
+		return limits
+	}
+	return u.PolicyTreeLimits
+}
+
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
 // It hides the implementation details of the Enabled and simplifies its usage.
 func (u *UnifiedAlertingSettings) IsEnabled() bool {
@@ -265,6 +347,32 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		uaCfg.DefaultRuleEvaluationInterval = uaMinInterval
 	}
 
+	if globalTemplatesPath := valueAsString(ua, "global_templates_path", ""); globalTemplatesPath != "" {
+		uaCfg.GlobalTemplatesPath = makeAbsolute(globalTemplatesPath, cfg.HomePath)
+	}
+
+	uaCfg.MaxAlertInstancesPerRule = ua.Key("max_alert_instances_per_rule").MustInt64(0)
+
+	uaCfg.WarmupPeriod, err = gtime.ParseDuration(valueAsString(ua, "warmup_period", "0s"))
+	if err != nil {
+		return fmt.Errorf("value of setting 'warmup_period' is not a valid duration: %w", err)
+	}
+	if uaCfg.WarmupPeriod < 0 {
+		return fmt.Errorf("value of setting 'warmup_period' should not be negative")
+	}
+
+	uaCfg.ConfigHistoryMaxVersions = ua.Key("config_history_max_versions").MustInt64(0)
+	if uaCfg.ConfigHistoryMaxVersions < 0 {
+		return fmt.Errorf("value of setting 'config_history_max_versions' should not be negative")
+	}
+	uaCfg.ConfigHistoryMaxAge, err = gtime.ParseDuration(valueAsString(ua, "config_history_max_age", "0s"))
+	if err != nil {
+		return fmt.Errorf("value of setting 'config_history_max_age' is not a valid duration: %w", err)
+	}
+	if uaCfg.ConfigHistoryMaxAge < 0 {
+		return fmt.Errorf("value of setting 'config_history_max_age' should not be negative")
+	}
+
 	screenshots := iniFile.Section("unified_alerting.screenshots")
 	uaCfgScreenshots := uaCfg.Screenshots
 
@@ -273,10 +381,75 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfgScreenshots.UploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(screenshotsDefaultUploadImageStorage)
 	uaCfg.Screenshots = uaCfgScreenshots
 
+	policyLimits := iniFile.Section("unified_alerting.policy_limits")
+	uaCfg.PolicyTreeLimits = readPolicyTreeLimits(policyLimits, UnifiedAlertingPolicyTreeLimits{
+		MaxRoutes:           policyLimitsDefaultMaxRoutes,
+		MaxDepth:            policyLimitsDefaultMaxDepth,
+		MaxMatchersPerRoute: policyLimitsDefaultMaxMatchersPerRoute,
+	})
+	uaCfg.PolicyTreeLimitsPerOrg = map[int64]UnifiedAlertingPolicyTreeLimits{}
+	for _, section := range policyLimits.ChildSections() {
+		name := strings.TrimPrefix(section.Name(), policyLimits.Name()+".")
+		orgID, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid section [%s]: %q is not an organization ID", section.Name(), name)
+		}
+		// the limits of an organization that are not set are the limits of all organizations
+		uaCfg.PolicyTreeLimitsPerOrg[orgID] = readPolicyTreeLimits(section, uaCfg.PolicyTreeLimits)
+	}
+
+	configLimits := iniFile.Section("unified_alerting.config_limits")
+	uaCfg.ConfigLimits = readConfigLimits(configLimits, UnifiedAlertingConfigLimits{})
+	uaCfg.ConfigLimitsPerOrg = map[int64]UnifiedAlertingConfigLimits{}
+	for _, section := range configLimits.ChildSections() {
+		name := strings.TrimPrefix(section.Name(), configLimits.Name()+".")
+		orgID, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid section [%s]: %q is not an organization ID", section.Name(), name)
+		}
+		// the limits of an organization that are not set are the limits of all organizations
+		uaCfg.ConfigLimitsPerOrg[orgID] = readConfigLimits(section, uaCfg.ConfigLimits)
+	}
+
+	ruleLimits := iniFile.Section("unified_alerting.rule_limits")
+	uaCfg.RuleLimits = readRuleLimits(ruleLimits, UnifiedAlertingRuleLimits{})
+	uaCfg.RuleLimitsPerOrg = map[int64]UnifiedAlertingRuleLimits{}
+	for _, section := range ruleLimits.ChildSections() {
+		name := strings.TrimPrefix(section.Name(), ruleLimits.Name()+".")
+		orgID, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid section [%s]: %q is not an organization ID", section.Name(), name)
+		}
+		// the limits of an organization that are not set are the limits of all organizations
+		uaCfg.RuleLimitsPerOrg[orgID] = readRuleLimits(section, uaCfg.RuleLimits)
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
 
+func readPolicyTreeLimits(section *ini.Section, defaults UnifiedAlertingPolicyTreeLimits) UnifiedAlertingPolicyTreeLimits {
+	return UnifiedAlertingPolicyTreeLimits{
+		MaxRoutes:           section.Key("max_routes").MustInt64(defaults.MaxRoutes),
+		MaxDepth:            section.Key("max_depth").MustInt64(defaults.MaxDepth),
+		MaxMatchersPerRoute: section.Key("max_matchers_per_route").MustInt64(defaults.MaxMatchersPerRoute),
+	}
+}
+
+func readConfigLimits(section *ini.Section, defaults UnifiedAlertingConfigLimits) UnifiedAlertingConfigLimits {
+	return UnifiedAlertingConfigLimits{
+		MaxSize: section.Key("max_size").MustInt64(defaults.MaxSize),
+	}
+}
+
+func readRuleLimits(section *ini.Section, defaults UnifiedAlertingRuleLimits) UnifiedAlertingRuleLimits {
+	return UnifiedAlertingRuleLimits{
+		MaxRules:           section.Key("max_rules").MustInt64(defaults.MaxRules),
+		MaxRulesPerGroup:   section.Key("max_rules_per_group").MustInt64(defaults.MaxRulesPerGroup),
+		MaxGroupsPerFolder: section.Key("max_groups_per_folder").MustInt64(defaults.MaxGroupsPerFolder),
+	}
+}
+
 func GetAlertmanagerDefaultConfiguration() string {
 	return alertmanagerDefaultConfiguration
 }
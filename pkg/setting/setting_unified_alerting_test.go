@@ -39,6 +39,84 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 		require.Len(t, cfg.UnifiedAlerting.HAPeers, 3)
 		require.ElementsMatch(t, []string{"hostname1:9090", "hostname2:9090", "hostname3:9090"}, cfg.UnifiedAlerting.HAPeers)
 	}
+
+	// It reads the alert rule limits and the limits of single organizations.
+	{
+		require.Equal(t, UnifiedAlertingRuleLimits{}, cfg.UnifiedAlerting.RuleLimits)
+		require.Empty(t, cfg.UnifiedAlerting.RuleLimitsPerOrg)
+
+		s, err := cfg.Raw.NewSection("unified_alerting.rule_limits")
+		require.NoError(t, err)
+		_, err = s.NewKey("max_rules", "1000")
+		require.NoError(t, err)
+		s, err = cfg.Raw.NewSection("unified_alerting.rule_limits.2")
+		require.NoError(t, err)
+		_, err = s.NewKey("max_rules_per_group", "10")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, UnifiedAlertingRuleLimits{MaxRules: 1000}, cfg.UnifiedAlerting.RuleLimitsForOrg(1))
+		require.Equal(t, UnifiedAlertingRuleLimits{MaxRules: 1000, MaxRulesPerGroup: 10}, cfg.UnifiedAlerting.RuleLimitsForOrg(2))
+	}
+
+	// It reads the Alertmanager configuration limits and the limits of single organizations.
+	{
+		require.Equal(t, UnifiedAlertingConfigLimits{}, cfg.UnifiedAlerting.ConfigLimits)
+		require.Empty(t, cfg.UnifiedAlerting.ConfigLimitsPerOrg)
+
+		s, err := cfg.Raw.NewSection("unified_alerting.config_limits")
+		require.NoError(t, err)
+		_, err = s.NewKey("max_size", "1048576")
+		require.NoError(t, err)
+		s, err = cfg.Raw.NewSection("unified_alerting.config_limits.2")
+		require.NoError(t, err)
+		_, err = s.NewKey("max_size", "0")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, UnifiedAlertingConfigLimits{MaxSize: 1048576}, cfg.UnifiedAlerting.ConfigLimitsForOrg(1))
+		require.Equal(t, UnifiedAlertingConfigLimits{}, cfg.UnifiedAlerting.ConfigLimitsForOrg(2))
+	}
+
+	// It reads the retention of the configuration history.
+	{
+		require.Zero(t, cfg.UnifiedAlerting.ConfigHistoryMaxVersions)
+		require.Zero(t, cfg.UnifiedAlerting.ConfigHistoryMaxAge)
+
+		s := cfg.Raw.Section("unified_alerting")
+		_, err := s.NewKey("config_history_max_versions", "100")
+		require.NoError(t, err)
+		_, err = s.NewKey("config_history_max_age", "30d")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, int64(100), cfg.UnifiedAlerting.ConfigHistoryMaxVersions)
+		require.Equal(t, 30*24*time.Hour, cfg.UnifiedAlerting.ConfigHistoryMaxAge)
+
+		s.Key("config_history_max_age").SetValue("-1h")
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		s.Key("config_history_max_age").SetValue("30d")
+	}
+
+	// It reads the policy tree limits and the limits of single organizations.
+	{
+		defaults := UnifiedAlertingPolicyTreeLimits{MaxRoutes: 5000, MaxDepth: 20, MaxMatchersPerRoute: 50}
+		require.Equal(t, defaults, cfg.UnifiedAlerting.PolicyTreeLimits)
+		require.Empty(t, cfg.UnifiedAlerting.PolicyTreeLimitsPerOrg)
+
+		s, err := cfg.Raw.NewSection("unified_alerting.policy_limits.2")
+		require.NoError(t, err)
+		_, err = s.NewKey("max_routes", "20000")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, defaults, cfg.UnifiedAlerting.PolicyTreeLimitsForOrg(1))
+		require.Equal(t, UnifiedAlertingPolicyTreeLimits{MaxRoutes: 20000, MaxDepth: 20, MaxMatchersPerRoute: 50}, cfg.UnifiedAlerting.PolicyTreeLimitsForOrg(2))
+
+		_, err = cfg.Raw.NewSection("unified_alerting.policy_limits.main")
+		require.NoError(t, err)
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+	}
 }
 
 func TestUnifiedAlertingSettings(t *testing.T) {
@@ -2,6 +2,7 @@ package proxyutil
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 	glog "github.com/grafana/grafana/pkg/infra/log"
 )
 
@@ -93,6 +95,7 @@ type timeoutError interface {
 // certain HTTP status based on the kind of error.
 // If client cancel/close the request we return 499 StatusClientClosedRequest.
 // If timeout happens while communicating with upstream server we return http.StatusGatewayTimeout.
+// If the upstream response exceeds the response limit we return http.StatusBadGateway with an error message.
 // If any other error we return http.StatusBadGateway.
 func errorHandler(logger glog.Logger) func(http.ResponseWriter, *http.Request, error) {
 	return func(w http.ResponseWriter, r *http.Request, err error) {
@@ -109,6 +112,19 @@ func errorHandler(logger glog.Logger) func(http.ResponseWriter, *http.Request, e
 			return
 		}
 
+		var limitErr *httpclient.ResponseLimitError
+		if errors.As(err, &limitErr) {
+			logger.Warn("Proxy response exceeded the response limit", "limit", limitErr.Limit)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadGateway)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"message": "Data source response exceeded the response limit",
+				"error":   limitErr.Error(),
+				"limit":   limitErr.Limit,
+			})
+			return
+		}
+
 		logger.Error("Proxy request failed", "err", err)
 		w.WriteHeader(http.StatusBadGateway)
 	}
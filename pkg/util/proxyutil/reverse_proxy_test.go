@@ -2,12 +2,14 @@ package proxyutil
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/stretchr/testify/require"
 )
@@ -141,6 +143,27 @@ func TestReverseProxy(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Error handling should return the response limit when the response is too large", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+
+		rp := NewReverseProxy(
+			log.New("test"),
+			func(req *http.Request) {},
+			WithTransport(&responseLimitRoundTripper{}),
+		)
+		rp.ServeHTTP(rec, req)
+
+		resp := rec.Result()
+		require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.Equal(t, "Data source response exceeded the response limit", body["message"])
+		require.EqualValues(t, 10, body["limit"])
+		require.NoError(t, resp.Body.Close())
+	})
 }
 
 func newUpstreamServer(t *testing.T, handler http.Handler) *httptest.Server {
@@ -161,3 +184,9 @@ type failingRoundTripper struct{}
 func (failingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
 	return nil, errors.New("some error")
 }
+
+type responseLimitRoundTripper struct{}
+
+func (responseLimitRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, &httpclient.ResponseLimitError{Limit: 10}
+}
@@ -110,6 +110,22 @@ export const Math: FC<Props> = ({ labelWidth, onChange, query, onRunQuery }) =>
             name="floor"
             description="rounds the number down to the nearest integer value. It's able to operate on series or escalar values."
           />
+          <DocumentedFunction
+            name="day_of_week"
+            description="returns the day of the week of the evaluation, from 0 for Sunday to 6 for Saturday. It takes an optional timezone, e.g. day_of_week(&quot;Europe/Berlin&quot;)."
+          />
+          <DocumentedFunction
+            name="hour_of_day"
+            description="returns the hour of the day of the evaluation, from 0 to 23. It takes an optional timezone."
+          />
+          <DocumentedFunction
+            name="is_business_hours"
+            description="returns 1 from 9:00 to 17:00 Monday to Friday and 0 at other times. It takes an optional timezone."
+          />
+          <DocumentedFunction
+            name="time_since"
+            description="returns the seconds since the latest value of each series."
+          />
         </div>
         <div>
           See our additional documentation on{' '}
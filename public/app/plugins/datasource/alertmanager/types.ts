@@ -95,6 +95,8 @@ export type Receiver = {
 type ObjectMatcher = [name: string, operator: MatcherOperator, value: string];
 
 export type Route = {
+  /** identifies nested routes of Grafana managed policies, the root policy has no id */
+  id?: string;
   receiver?: string;
   group_by?: string[];
   continue?: boolean;
@@ -109,7 +111,7 @@ export type Route = {
   repeat_interval?: string;
   routes?: Route[];
   mute_time_intervals?: string[];
-  /** only the root policy might have a provenance field defined */
+  active_time_intervals?: string[];
   provenance?: string;
 };
 
@@ -302,6 +304,8 @@ export interface TimeInterval {
   days_of_month?: string[];
   months?: string[];
   years?: string[];
+  /** Name of a location in the IANA Time Zone database, the times and days are in UTC without it */
+  location?: string;
 }
 
 export type MuteTimeInterval = {